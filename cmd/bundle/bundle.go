@@ -0,0 +1,484 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v2/cmd"
+	"github.com/traefik/traefik/v2/pkg/anonymize"
+	"github.com/traefik/traefik/v2/pkg/api"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/tls"
+)
+
+// Configuration holds the configuration of the export-bundle command.
+type Configuration struct {
+	cmd.TraefikCmdConfiguration `export:"true"`
+
+	Output    string `description:"Path of the bundle archive." json:"output,omitempty" toml:"output,omitempty" yaml:"output,omitempty"`
+	Anonymize bool   `description:"Anonymizes the bundle: removes the secrets, rules and addresses, and masks the URLs, domains and email addresses." json:"anonymize,omitempty" toml:"anonymize,omitempty" yaml:"anonymize,omitempty"`
+	APIURL    string `description:"URL of the API of the running Traefik instance. Defaults to the traefik entry point when the API is insecure." json:"apiURL,omitempty" toml:"apiURL,omitempty" yaml:"apiURL,omitempty"`
+}
+
+// NewConfiguration creates a Configuration with the default values.
+func NewConfiguration() *Configuration {
+	return &Configuration{
+		TraefikCmdConfiguration: *cmd.NewTraefikConfiguration(),
+		Output:                  "traefik-bundle.tar.gz",
+	}
+}
+
+// NewCmd builds a new ExportBundle command.
+func NewCmd(loaders []cli.ResourceLoader) *cli.Command {
+	bundleConfiguration := NewConfiguration()
+
+	return &cli.Command{
+		Name: "export-bundle",
+		Description: `Exports, in a single archive, the static configuration, and the dynamic configuration, certificates, versions and errors
+of the running Traefik instance, as reported by its API, to make bug reports reproducible.`,
+		Configuration: bundleConfiguration,
+		Run:           runCmd(bundleConfiguration),
+		Resources:     loaders,
+	}
+}
+
+func runCmd(bundleConfiguration *Configuration) func(_ []string) error {
+	return func(_ []string) error {
+		bundleConfiguration.SetEffectiveConfiguration()
+
+		apiURL, err := getAPIURL(bundleConfiguration)
+		if err != nil {
+			return err
+		}
+
+		client := &http.Client{Timeout: 10 * time.Second}
+
+		files, err := Build(client, apiURL, bundleConfiguration.Configuration, bundleConfiguration.Anonymize)
+		if err != nil {
+			return err
+		}
+
+		if err := writeArchive(bundleConfiguration.Output, files); err != nil {
+			return err
+		}
+
+		log.WithoutContext().Infof("Bundle written to %s", bundleConfiguration.Output)
+
+		return nil
+	}
+}
+
+func getAPIURL(bundleConfiguration *Configuration) (string, error) {
+	if bundleConfiguration.APIURL != "" {
+		return strings.TrimSuffix(bundleConfiguration.APIURL, "/"), nil
+	}
+
+	if bundleConfiguration.API == nil || !bundleConfiguration.API.Insecure {
+		return "", errors.New("the API URL is required when the API is not insecure")
+	}
+
+	entryPoint, ok := bundleConfiguration.EntryPoints["traefik"]
+	if !ok {
+		return "", errors.New("api: missing traefik entry point")
+	}
+
+	return "http://" + entryPoint.GetAddress(), nil
+}
+
+// File is a file of the bundle.
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// ErrorInfo holds the errors of an element of the dynamic configuration.
+type ErrorInfo struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	Status string   `json:"status,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// CertificateInfo holds the certificate served for a domain of a router.
+type CertificateInfo struct {
+	Router string `json:"router"`
+	Domain string `json:"domain"`
+	*tls.CertificateInfo
+}
+
+// Build collects the bundle files, from the given static configuration,
+// and from the API of the running Traefik instance.
+func Build(client *http.Client, apiURL string, staticConfiguration static.Configuration, anonymized bool) ([]File, error) {
+	var rawData api.RunTimeRepresentation
+	if err := getJSON(client, apiURL+"/api/rawdata", &rawData); err != nil {
+		return nil, err
+	}
+
+	var overview map[string]interface{}
+	if err := getJSON(client, apiURL+"/api/overview", &overview); err != nil {
+		return nil, err
+	}
+
+	var version map[string]interface{}
+	if err := getJSON(client, apiURL+"/api/version", &version); err != nil {
+		return nil, err
+	}
+
+	certificates := getCertificates(rawData)
+	errs := getErrors(rawData)
+
+	var staticContent []byte
+	if anonymized {
+		content, err := anonymize.Do(staticConfiguration, true)
+		if err != nil {
+			return nil, fmt.Errorf("unable to anonymize the static configuration: %w", err)
+		}
+
+		staticContent = []byte(content)
+
+		if err := anonymizeRawData(&rawData); err != nil {
+			return nil, fmt.Errorf("unable to anonymize the dynamic configuration: %w", err)
+		}
+
+		anonymizeCertificates(certificates)
+		anonymizeErrors(errs)
+	} else {
+		content, err := json.MarshalIndent(staticConfiguration, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		staticContent = content
+	}
+
+	files := []File{{Name: "static.json", Content: staticContent}}
+
+	for name, element := range map[string]interface{}{
+		"rawdata.json":      rawData,
+		"overview.json":     overview,
+		"version.json":      version,
+		"certificates.json": certificates,
+		"errors.json":       errs,
+	} {
+		content, err := json.MarshalIndent(element, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, File{Name: name, Content: content})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	return files, nil
+}
+
+func getJSON(client *http.Client, url string, element interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("unable to call %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to call %s: %s", url, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(element); err != nil {
+		return fmt.Errorf("unable to decode the response of %s: %w", url, err)
+	}
+
+	return nil
+}
+
+// getCertificates returns the certificates served for the domains of the routers.
+func getCertificates(rawData api.RunTimeRepresentation) []CertificateInfo {
+	certificates := []CertificateInfo{}
+	for name, router := range rawData.Routers {
+		for domain, certificate := range router.Certificates {
+			certificates = append(certificates, CertificateInfo{Router: name, Domain: domain, CertificateInfo: certificate})
+		}
+	}
+
+	sort.Slice(certificates, func(i, j int) bool {
+		if certificates[i].Router != certificates[j].Router {
+			return certificates[i].Router < certificates[j].Router
+		}
+		return certificates[i].Domain < certificates[j].Domain
+	})
+
+	return certificates
+}
+
+// getErrors returns the errors of the elements of the dynamic configuration, the conflicts between providers,
+// and the labels the providers could not decode.
+func getErrors(rawData api.RunTimeRepresentation) []ErrorInfo {
+	errs := []ErrorInfo{}
+	add := func(kind, name, status string, elementErrors []string) {
+		if len(elementErrors) > 0 {
+			errs = append(errs, ErrorInfo{Kind: kind, Name: name, Status: status, Errors: elementErrors})
+		}
+	}
+
+	for name, info := range rawData.Routers {
+		add("router", name, info.Status, info.Err)
+	}
+	for name, info := range rawData.Middlewares {
+		add("middleware", name, info.Status, info.Err)
+	}
+	for name, info := range rawData.Services {
+		if info.ServiceInfo != nil {
+			add("service", name, info.Status, info.Err)
+		}
+	}
+	for name, info := range rawData.TCPRouters {
+		add("tcpRouter", name, info.Status, info.Err)
+	}
+	for name, info := range rawData.TCPMiddlewares {
+		add("tcpMiddleware", name, info.Status, info.Err)
+	}
+	for name, info := range rawData.TCPServices {
+		add("tcpService", name, info.Status, info.Err)
+	}
+	for name, info := range rawData.UDPRouters {
+		add("udpRouter", name, info.Status, info.Err)
+	}
+	for name, info := range rawData.UDPServices {
+		add("udpService", name, info.Status, info.Err)
+	}
+
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Kind != errs[j].Kind {
+			return errs[i].Kind < errs[j].Kind
+		}
+		return errs[i].Name < errs[j].Name
+	})
+
+	for _, conflict := range rawData.Conflicts {
+		errs = append(errs, ErrorInfo{
+			Kind:   "conflict",
+			Name:   conflict.Name,
+			Status: conflict.Status,
+			Errors: []string{fmt.Sprintf("%s defined with different configurations by %s", conflict.Kind, strings.Join(conflict.Sources, ", "))},
+		})
+	}
+
+	for _, labelErr := range rawData.LabelErrors {
+		errs = append(errs, ErrorInfo{
+			Kind:   "labelError",
+			Name:   labelErr.Source + "@" + labelErr.Provider,
+			Status: labelErr.Status,
+			Errors: []string{fmt.Sprintf("label %q: %s", labelErr.Label, labelErr.Reason)},
+		})
+	}
+
+	return errs
+}
+
+// anonymizeRawData anonymizes the dynamic configuration of the elements,
+// and masks the URLs of the errors.
+func anonymizeRawData(rawData *api.RunTimeRepresentation) error {
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:     make(map[string]*dynamic.Router),
+			Middlewares: make(map[string]*dynamic.Middleware),
+			Services:    make(map[string]*dynamic.Service),
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers:     make(map[string]*dynamic.TCPRouter),
+			Middlewares: make(map[string]*dynamic.TCPMiddleware),
+			Services:    make(map[string]*dynamic.TCPService),
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  make(map[string]*dynamic.UDPRouter),
+			Services: make(map[string]*dynamic.UDPService),
+		},
+	}
+
+	for name, info := range rawData.Routers {
+		conf.HTTP.Routers[name] = info.Router
+	}
+	for name, info := range rawData.Middlewares {
+		conf.HTTP.Middlewares[name] = info.Middleware
+	}
+	for name, info := range rawData.Services {
+		if info.ServiceInfo != nil {
+			conf.HTTP.Services[name] = info.Service
+		}
+	}
+	for name, info := range rawData.TCPRouters {
+		conf.TCP.Routers[name] = info.TCPRouter
+	}
+	for name, info := range rawData.TCPMiddlewares {
+		conf.TCP.Middlewares[name] = info.TCPMiddleware
+	}
+	for name, info := range rawData.TCPServices {
+		conf.TCP.Services[name] = info.TCPService
+	}
+	for name, info := range rawData.UDPRouters {
+		conf.UDP.Routers[name] = info.UDPRouter
+	}
+	for name, info := range rawData.UDPServices {
+		conf.UDP.Services[name] = info.UDPService
+	}
+
+	content, err := anonymize.Do(&conf, false)
+	if err != nil {
+		return err
+	}
+
+	var anonymized dynamic.Configuration
+	if err := json.Unmarshal([]byte(content), &anonymized); err != nil {
+		return err
+	}
+
+	for name, info := range rawData.Routers {
+		info.Router = anonymized.HTTP.Routers[name]
+		info.Err = anonymizeTexts(info.Err)
+		// The served certificates are keyed by domain.
+		info.Certificates = nil
+	}
+	for name, info := range rawData.Middlewares {
+		info.Middleware = anonymized.HTTP.Middlewares[name]
+		info.Err = anonymizeTexts(info.Err)
+	}
+	for name, info := range rawData.Services {
+		if info.ServiceInfo != nil {
+			info.Service = anonymized.HTTP.Services[name]
+			info.Err = anonymizeTexts(info.Err)
+		}
+		info.ServerStatus = anonymizeServerStatus(info.ServerStatus)
+	}
+	for name, info := range rawData.TCPRouters {
+		info.TCPRouter = anonymized.TCP.Routers[name]
+		info.Err = anonymizeTexts(info.Err)
+	}
+	for name, info := range rawData.TCPMiddlewares {
+		info.TCPMiddleware = anonymized.TCP.Middlewares[name]
+		info.Err = anonymizeTexts(info.Err)
+	}
+	for name, info := range rawData.TCPServices {
+		info.TCPService = anonymized.TCP.Services[name]
+		info.Err = anonymizeTexts(info.Err)
+	}
+	for name, info := range rawData.UDPRouters {
+		info.UDPRouter = anonymized.UDP.Routers[name]
+		info.Err = anonymizeTexts(info.Err)
+	}
+	for name, info := range rawData.UDPServices {
+		info.UDPService = anonymized.UDP.Services[name]
+		info.Err = anonymizeTexts(info.Err)
+	}
+	for _, info := range rawData.LabelErrors {
+		// The reason may quote the value of the label.
+		info.Reason = anonymize.DoOnText(info.Reason)
+	}
+
+	return nil
+}
+
+// anonymizeServerStatus replaces the URLs of the servers by their rank, to keep the statuses without the addresses.
+func anonymizeServerStatus(serverStatus map[string]string) map[string]string {
+	if len(serverStatus) == 0 {
+		return serverStatus
+	}
+
+	urls := make([]string, 0, len(serverStatus))
+	for u := range serverStatus {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+
+	anonymized := make(map[string]string, len(serverStatus))
+	for i, u := range urls {
+		anonymized[fmt.Sprintf("server%d", i)] = serverStatus[u]
+	}
+
+	return anonymized
+}
+
+func anonymizeCertificates(certificates []CertificateInfo) {
+	for i, certificate := range certificates {
+		certificates[i].Domain = anonymize.DoOnText(certificate.Domain)
+
+		if certificate.CertificateInfo != nil {
+			info := *certificate.CertificateInfo
+			info.SANs = anonymizeTexts(info.SANs)
+			certificates[i].CertificateInfo = &info
+		}
+	}
+}
+
+func anonymizeErrors(errs []ErrorInfo) {
+	for i := range errs {
+		errs[i].Errors = anonymizeTexts(errs[i].Errors)
+	}
+}
+
+func anonymizeTexts(texts []string) []string {
+	if len(texts) == 0 {
+		return texts
+	}
+
+	anonymized := make([]string, len(texts))
+	for i, text := range texts {
+		anonymized[i] = anonymize.DoOnText(text)
+	}
+
+	return anonymized
+}
+
+func writeArchive(path string, files []File) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to create the bundle: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := writeFiles(file, files); err != nil {
+		return fmt.Errorf("unable to write the bundle: %w", err)
+	}
+
+	return file.Close()
+}
+
+func writeFiles(w io.Writer, files []File) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	now := time.Now()
+	for _, file := range files {
+		header := &tar.Header{
+			Name:    file.Name,
+			Mode:    0o600,
+			Size:    int64(len(file.Content)),
+			ModTime: now,
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := tarWriter.Write(file.Content); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
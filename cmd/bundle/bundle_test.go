@@ -0,0 +1,172 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+const rawData = `{
+  "routers": {
+    "foo@file": {
+      "entryPoints": ["websecure"],
+      "service": "foo",
+      "rule": "Host(` + "`foo.example.com`" + `)",
+      "tls": {},
+      "status": "enabled",
+      "certificates": {
+        "foo.example.com": {"source": "resolver", "resolver": "myresolver", "sans": ["foo.example.com"], "notAfter": "2030-01-01T00:00:00Z"}
+      }
+    },
+    "bar@file": {
+      "service": "missing",
+      "rule": "Path(` + "`/bar`" + `)",
+      "status": "disabled",
+      "error": ["the service \"missing@file\" does not exist at http://10.0.0.1"]
+    }
+  },
+  "services": {
+    "foo@file": {
+      "loadBalancer": {"servers": [{"url": "http://10.0.0.1:80"}, {"url": "http://10.0.0.2:80"}]},
+      "status": "enabled",
+      "serverStatus": {"http://10.0.0.1:80": "UP", "http://10.0.0.2:80": "DOWN"}
+    }
+  }
+}`
+
+func TestBuild(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rawdata", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(rawData))
+	})
+	mux.HandleFunc("/api/overview", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{"providers": ["File"]}`))
+	})
+	mux.HandleFunc("/api/version", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(`{"Version": "dev"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	staticConfiguration := static.Configuration{
+		Providers: &static.Providers{},
+		API:       &static.API{Insecure: true},
+	}
+
+	testCases := []struct {
+		desc               string
+		anonymize          bool
+		expectedRule       string
+		expectedServers    map[string]string
+		expectedDomain     string
+		expectedError      string
+		expectedRawCertKey bool
+	}{
+		{
+			desc:               "raw",
+			expectedRule:       "Host(`foo.example.com`)",
+			expectedServers:    map[string]string{"http://10.0.0.1:80": "UP", "http://10.0.0.2:80": "DOWN"},
+			expectedDomain:     "foo.example.com",
+			expectedError:      "the service \"missing@file\" does not exist at http://10.0.0.1",
+			expectedRawCertKey: true,
+		},
+		{
+			desc:            "anonymized",
+			anonymize:       true,
+			expectedRule:    "xxxx",
+			expectedServers: map[string]string{"server0": "UP", "server1": "DOWN"},
+			expectedDomain:  "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+			expectedError:   "the service \"missing@file\" does not exist at xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			files, err := Build(server.Client(), server.URL, staticConfiguration, test.anonymize)
+			require.NoError(t, err)
+
+			contents := make(map[string][]byte)
+			for _, file := range files {
+				contents[file.Name] = file.Content
+			}
+
+			require.Len(t, contents, 6)
+			assert.Contains(t, string(contents["version.json"]), `"Version": "dev"`)
+			assert.Contains(t, string(contents["overview.json"]), `"File"`)
+
+			var raw struct {
+				Routers map[string]struct {
+					Rule         string                     `json:"rule"`
+					Certificates map[string]json.RawMessage `json:"certificates"`
+				} `json:"routers"`
+				Services map[string]struct {
+					ServerStatus map[string]string `json:"serverStatus"`
+				} `json:"services"`
+			}
+			require.NoError(t, json.Unmarshal(contents["rawdata.json"], &raw))
+
+			assert.Equal(t, test.expectedRule, raw.Routers["foo@file"].Rule)
+			assert.Equal(t, test.expectedServers, raw.Services["foo@file"].ServerStatus)
+			_, ok := raw.Routers["foo@file"].Certificates["foo.example.com"]
+			assert.Equal(t, test.expectedRawCertKey, ok)
+
+			var certificates []CertificateInfo
+			require.NoError(t, json.Unmarshal(contents["certificates.json"], &certificates))
+			require.Len(t, certificates, 1)
+			assert.Equal(t, "foo@file", certificates[0].Router)
+			assert.Equal(t, test.expectedDomain, certificates[0].Domain)
+			assert.Equal(t, "myresolver", certificates[0].Resolver)
+
+			var errs []ErrorInfo
+			require.NoError(t, json.Unmarshal(contents["errors.json"], &errs))
+			require.Len(t, errs, 1)
+			assert.Equal(t, "router", errs[0].Kind)
+			assert.Equal(t, "bar@file", errs[0].Name)
+			assert.Equal(t, []string{test.expectedError}, errs[0].Errors)
+		})
+	}
+}
+
+func TestWriteFiles(t *testing.T) {
+	files := []File{
+		{Name: "a.json", Content: []byte(`{"a": 1}`)},
+		{Name: "b.json", Content: []byte(`{"b": 2}`)},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeFiles(&buf, files))
+
+	gzipReader, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+
+	tarReader := tar.NewReader(gzipReader)
+
+	var read []File
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+
+		read = append(read, File{Name: header.Name, Content: content})
+	}
+
+	assert.Equal(t, files, read)
+}
@@ -0,0 +1,309 @@
+package testroutes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/middlewares/addprefix"
+	"github.com/traefik/traefik/v2/pkg/middlewares/redirect"
+	"github.com/traefik/traefik/v2/pkg/middlewares/replacepath"
+	"github.com/traefik/traefik/v2/pkg/middlewares/replacepathregex"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefix"
+	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefixregex"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+)
+
+// Result is the routing of a sample request.
+type Result struct {
+	Name string
+	// Router is the router matching the request, if any.
+	Router string
+	// Middlewares are the middlewares the request goes through, the chains being flattened.
+	Middlewares []string
+	// NotEvaluated are the middlewares which do not rewrite nor redirect the requests, and are therefore not evaluated.
+	NotEvaluated []string
+	// Status is the status of the response sent instead of forwarding the request, e.g. by a redirection.
+	Status int
+	// URL is the URL the request is forwarded, or redirected, to.
+	URL      string
+	Failures []string
+}
+
+// Evaluate evaluates the sample requests against the routing configuration.
+func Evaluate(routing *Routing, staticConfiguration static.Configuration, tests []Test) []Result {
+	var results []Result
+	for _, test := range tests {
+		result, err := evaluate(routing, staticConfiguration, test)
+		if err != nil {
+			result.Failures = append(result.Failures, err.Error())
+		} else if test.Expected != nil {
+			result.Failures = check(result, *test.Expected)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func evaluate(routing *Routing, staticConfiguration static.Configuration, test Test) (Result, error) {
+	result := Result{Name: test.Name}
+	if result.Name == "" {
+		result.Name = strings.TrimSpace(test.Method + " " + test.URL)
+	}
+
+	req, err := buildRequest(test)
+	if err != nil {
+		return result, err
+	}
+
+	var priorityStrategy string
+	var entryPointMiddlewares []string
+	if test.EntryPoint != "" {
+		entryPoint, ok := staticConfiguration.EntryPoints[test.EntryPoint]
+		if !ok {
+			return result, fmt.Errorf("unknown entry point %s", test.EntryPoint)
+		}
+
+		priorityStrategy = entryPoint.HTTP.PriorityStrategy
+		entryPointMiddlewares = entryPoint.HTTP.Middlewares
+	}
+
+	routerName, req, err := matchRouter(routing, test.EntryPoint, priorityStrategy, req)
+	if err != nil {
+		return result, err
+	}
+
+	if routerName == "" {
+		result.Status = http.StatusNotFound
+		return result, nil
+	}
+
+	result.Router = routerName
+
+	ctx := context.Background()
+
+	middlewares, err := flattenMiddlewares(ctx, routing, entryPointMiddlewares, make(map[string]struct{}))
+	if err != nil {
+		return result, err
+	}
+
+	routerMiddlewares, err := flattenMiddlewares(provider.AddInContext(ctx, routerName), routing, routing.Routers[routerName].Middlewares, make(map[string]struct{}))
+	if err != nil {
+		return result, err
+	}
+
+	result.Middlewares = append(middlewares, routerMiddlewares...)
+
+	var forwarded *http.Request
+	var handler http.Handler = http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		forwarded = req
+	})
+
+	for i := len(result.Middlewares) - 1; i >= 0; i-- {
+		name := result.Middlewares[i]
+
+		next, err := buildMiddleware(ctx, name, routing.Middlewares[name], handler)
+		if err != nil {
+			return result, fmt.Errorf("middleware %s: %w", name, err)
+		}
+
+		if next == nil {
+			result.NotEvaluated = append([]string{name}, result.NotEvaluated...)
+			continue
+		}
+
+		handler = next
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	if forwarded == nil {
+		result.Status = recorder.Code
+		result.URL = recorder.Header().Get("Location")
+		return result, nil
+	}
+
+	scheme := "http"
+	if forwarded.TLS != nil {
+		scheme = "https"
+	}
+
+	result.URL = scheme + "://" + forwarded.Host + forwarded.URL.RequestURI()
+
+	return result, nil
+}
+
+func buildRequest(test Test) (*http.Request, error) {
+	u, err := url.Parse(test.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL %q: an absolute http or https URL is expected", test.URL)
+	}
+
+	method := test.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	// The request is created with a TLS connection state for the https scheme.
+	req := httptest.NewRequest(method, u.String(), nil)
+
+	for name, value := range test.Headers {
+		req.Header.Set(name, value)
+	}
+
+	return req, nil
+}
+
+// matchRouter returns the name of the router matching the request, evaluating the routers in the same order as Traefik.
+// It returns the request as seen by the router, i.e. decorated by the request decorator.
+func matchRouter(routing *Routing, entryPoint, priorityStrategy string, req *http.Request) (string, *http.Request, error) {
+	router, err := rules.NewRouter()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var routes []rules.Route
+	for name, config := range routing.Routers {
+		if (config.TLS != nil) != (req.TLS != nil) {
+			continue
+		}
+
+		if entryPoint != "" && len(config.EntryPoints) > 0 && !contains(config.EntryPoints, entryPoint) {
+			continue
+		}
+
+		priority, err := rules.GetPriority(config.Rule, config.Priority, priorityStrategy)
+		if err != nil {
+			return "", nil, fmt.Errorf("router %s: %w", name, err)
+		}
+
+		routes = append(routes, rules.Route{Name: name, Rule: config.Rule, Priority: priority})
+	}
+
+	rules.SortRoutes(routes)
+
+	var matched string
+	matchedReq := req
+	for _, route := range routes {
+		name := route.Name
+
+		err = router.AddRoute(route.Rule, route.Priority, http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			matched = name
+			matchedReq = req
+		}))
+		if err != nil {
+			return "", nil, fmt.Errorf("router %s: %w", name, err)
+		}
+	}
+
+	// The Host matcher relies on the canonized host set by the request decorator.
+	requestdecorator.New(nil).ServeHTTP(httptest.NewRecorder(), req, router.ServeHTTP)
+
+	return matched, matchedReq, nil
+}
+
+// flattenMiddlewares returns the qualified names of the middlewares, the chains being replaced by their middlewares.
+func flattenMiddlewares(ctx context.Context, routing *Routing, names []string, visited map[string]struct{}) ([]string, error) {
+	var flattened []string
+	for _, name := range names {
+		qualifiedName := provider.GetQualifiedName(ctx, name)
+
+		config, ok := routing.Middlewares[qualifiedName]
+		if !ok {
+			return nil, fmt.Errorf("middleware %s does not exist", qualifiedName)
+		}
+
+		if config.Chain == nil {
+			flattened = append(flattened, qualifiedName)
+			continue
+		}
+
+		if _, ok := visited[qualifiedName]; ok {
+			return nil, fmt.Errorf("middleware %s is part of a recursion", qualifiedName)
+		}
+		visited[qualifiedName] = struct{}{}
+
+		chained, err := flattenMiddlewares(provider.AddInContext(ctx, qualifiedName), routing, config.Chain.Middlewares, visited)
+		if err != nil {
+			return nil, err
+		}
+
+		delete(visited, qualifiedName)
+
+		flattened = append(flattened, chained...)
+	}
+
+	return flattened, nil
+}
+
+// buildMiddleware builds the middlewares rewriting or redirecting the requests,
+// and returns a nil handler for the other middlewares.
+func buildMiddleware(ctx context.Context, name string, config *dynamic.Middleware, next http.Handler) (http.Handler, error) {
+	switch {
+	case config.AddPrefix != nil:
+		return addprefix.New(ctx, next, *config.AddPrefix, name)
+	case config.StripPrefix != nil:
+		return stripprefix.New(ctx, next, *config.StripPrefix, name)
+	case config.StripPrefixRegex != nil:
+		return stripprefixregex.New(ctx, next, *config.StripPrefixRegex, name)
+	case config.ReplacePath != nil:
+		return replacepath.New(ctx, next, *config.ReplacePath, name)
+	case config.ReplacePathRegex != nil:
+		return replacepathregex.New(ctx, next, *config.ReplacePathRegex, name)
+	case config.RedirectScheme != nil:
+		return redirect.NewRedirectScheme(ctx, next, *config.RedirectScheme, name)
+	case config.RedirectRegex != nil:
+		return redirect.NewRedirectRegex(ctx, next, *config.RedirectRegex, name)
+	default:
+		return nil, nil
+	}
+}
+
+// check returns the differences between the result and the expectations.
+func check(result Result, expected Expected) []string {
+	var failures []string
+
+	if expected.Router != "" && expected.Router != result.Router {
+		failures = append(failures, fmt.Sprintf("expected router %q, got %q", expected.Router, result.Router))
+	}
+
+	// An empty list of middlewares is expected to match a request going through no middleware.
+	if expected.Middlewares != nil && (len(expected.Middlewares) > 0 || len(result.Middlewares) > 0) && !reflect.DeepEqual(expected.Middlewares, result.Middlewares) {
+		failures = append(failures, fmt.Sprintf("expected middlewares %q, got %q", expected.Middlewares, result.Middlewares))
+	}
+
+	if expected.URL != "" && expected.URL != result.URL {
+		failures = append(failures, fmt.Sprintf("expected URL %q, got %q", expected.URL, result.URL))
+	}
+
+	if expected.Status != 0 && expected.Status != result.Status {
+		failures = append(failures, fmt.Sprintf("expected status %d, got %d", expected.Status, result.Status))
+	}
+
+	return failures
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,276 @@
+package testroutes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v2/cmd"
+	"github.com/traefik/traefik/v2/pkg/api"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/provider/file"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration holds the configuration of the test-routes command.
+type Configuration struct {
+	cmd.TraefikCmdConfiguration `export:"true"`
+
+	Tests         string `description:"Path of the YAML file describing the sample requests to evaluate." json:"tests,omitempty" toml:"tests,omitempty" yaml:"tests,omitempty"`
+	DynamicConfig string `description:"Path of a dynamic configuration file, or directory, to evaluate the requests against, instead of the configuration of the running Traefik instance." json:"dynamicConfig,omitempty" toml:"dynamicConfig,omitempty" yaml:"dynamicConfig,omitempty"`
+	APIURL        string `description:"URL of the API of the running Traefik instance. Defaults to the traefik entry point when the API is insecure." json:"apiURL,omitempty" toml:"apiURL,omitempty" yaml:"apiURL,omitempty"`
+}
+
+// NewConfiguration creates a Configuration with the default values.
+func NewConfiguration() *Configuration {
+	return &Configuration{
+		TraefikCmdConfiguration: *cmd.NewTraefikConfiguration(),
+	}
+}
+
+// NewCmd builds a new TestRoutes command.
+func NewCmd(loaders []cli.ResourceLoader) *cli.Command {
+	testConfiguration := NewConfiguration()
+
+	return &cli.Command{
+		Name: "test-routes",
+		Description: `Evaluates sample requests against the routing configuration, and reports, for each request,
+the router it matches, the middlewares it goes through, and the URL it is forwarded or redirected to.`,
+		Configuration: testConfiguration,
+		Run:           runCmd(testConfiguration),
+		Resources:     loaders,
+	}
+}
+
+func runCmd(testConfiguration *Configuration) func(_ []string) error {
+	return func(_ []string) error {
+		testConfiguration.SetEffectiveConfiguration()
+
+		if testConfiguration.Tests == "" {
+			return errors.New("the path of the tests file is required")
+		}
+
+		tests, err := loadTests(testConfiguration.Tests)
+		if err != nil {
+			return err
+		}
+
+		conf, err := loadRouting(testConfiguration)
+		if err != nil {
+			return err
+		}
+
+		return report(os.Stdout, Evaluate(conf, testConfiguration.Configuration, tests))
+	}
+}
+
+// Tests is the content of a tests file.
+type Tests struct {
+	Tests []Test `yaml:"tests"`
+}
+
+// Test describes a sample request, and optionally how it is expected to be routed.
+type Test struct {
+	Name       string            `yaml:"name,omitempty"`
+	EntryPoint string            `yaml:"entryPoint,omitempty"`
+	Method     string            `yaml:"method,omitempty"`
+	URL        string            `yaml:"url"`
+	Headers    map[string]string `yaml:"headers,omitempty"`
+	Expected   *Expected         `yaml:"expected,omitempty"`
+}
+
+// Expected holds the expected routing of a sample request.
+// The empty fields are not checked.
+type Expected struct {
+	Router      string   `yaml:"router,omitempty"`
+	Middlewares []string `yaml:"middlewares,omitempty"`
+	URL         string   `yaml:"url,omitempty"`
+	Status      int      `yaml:"status,omitempty"`
+}
+
+func loadTests(path string) ([]Test, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the tests file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+
+	var tests Tests
+	if err := decoder.Decode(&tests); err != nil {
+		return nil, fmt.Errorf("unable to decode the tests file %s: %w", path, err)
+	}
+
+	return tests.Tests, nil
+}
+
+// Routing holds the HTTP routers and middlewares, indexed by their qualified names.
+type Routing struct {
+	Routers     map[string]*dynamic.Router
+	Middlewares map[string]*dynamic.Middleware
+}
+
+func loadRouting(testConfiguration *Configuration) (*Routing, error) {
+	if testConfiguration.DynamicConfig != "" {
+		return loadRoutingFromFile(testConfiguration.DynamicConfig)
+	}
+
+	apiURL, err := getAPIURL(testConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	return loadRoutingFromAPI(client, apiURL)
+}
+
+// loadRoutingFromFile loads a dynamic configuration, as the file provider would.
+func loadRoutingFromFile(path string) (*Routing, error) {
+	fileProvider := &file.Provider{Filename: path}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the dynamic configuration: %w", err)
+	}
+
+	if info.IsDir() {
+		fileProvider = &file.Provider{Directory: path}
+	}
+
+	conf, err := fileProvider.BuildConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the dynamic configuration: %w", err)
+	}
+
+	routing := &Routing{
+		Routers:     make(map[string]*dynamic.Router),
+		Middlewares: make(map[string]*dynamic.Middleware),
+	}
+
+	if conf.HTTP == nil {
+		return routing, nil
+	}
+
+	for name, router := range conf.HTTP.Routers {
+		routing.Routers[provider.MakeQualifiedName("file", name)] = router
+	}
+
+	for name, middleware := range conf.HTTP.Middlewares {
+		routing.Middlewares[provider.MakeQualifiedName("file", name)] = middleware
+	}
+
+	return routing, nil
+}
+
+// loadRoutingFromAPI loads the dynamic configuration of the running Traefik instance.
+// The routers disabled because of errors are ignored, as Traefik does not serve them.
+func loadRoutingFromAPI(client *http.Client, apiURL string) (*Routing, error) {
+	resp, err := client.Get(apiURL + "/api/rawdata")
+	if err != nil {
+		return nil, fmt.Errorf("unable to call %s: %w", apiURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to call %s: %s", apiURL, resp.Status)
+	}
+
+	var rawData api.RunTimeRepresentation
+	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
+		return nil, fmt.Errorf("unable to decode the response of %s: %w", apiURL, err)
+	}
+
+	routing := &Routing{
+		Routers:     make(map[string]*dynamic.Router),
+		Middlewares: make(map[string]*dynamic.Middleware),
+	}
+
+	for name, router := range rawData.Routers {
+		if router.Router == nil || router.Status == runtime.StatusDisabled {
+			continue
+		}
+
+		routing.Routers[name] = router.Router
+	}
+
+	for name, middleware := range rawData.Middlewares {
+		if middleware.Middleware == nil {
+			continue
+		}
+
+		routing.Middlewares[name] = middleware.Middleware
+	}
+
+	return routing, nil
+}
+
+func getAPIURL(testConfiguration *Configuration) (string, error) {
+	if testConfiguration.APIURL != "" {
+		return strings.TrimSuffix(testConfiguration.APIURL, "/"), nil
+	}
+
+	if testConfiguration.API == nil || !testConfiguration.API.Insecure {
+		return "", errors.New("the API URL, or a dynamic configuration, is required when the API is not insecure")
+	}
+
+	entryPoint, ok := testConfiguration.EntryPoints["traefik"]
+	if !ok {
+		return "", errors.New("api: missing traefik entry point")
+	}
+
+	return "http://" + entryPoint.GetAddress(), nil
+}
+
+// report writes the results, and returns an error when some expectations are not met.
+func report(w io.Writer, results []Result) error {
+	var failed int
+	for _, result := range results {
+		status := "PASS"
+		if len(result.Failures) > 0 {
+			status = "FAIL"
+			failed++
+		}
+
+		_, _ = fmt.Fprintf(w, "%s %s\n", status, result.Name)
+
+		router := result.Router
+		if router == "" {
+			router = "none"
+		}
+		_, _ = fmt.Fprintf(w, "    router:      %s\n", router)
+
+		if len(result.Middlewares) > 0 {
+			_, _ = fmt.Fprintf(w, "    middlewares: %s\n", strings.Join(result.Middlewares, ", "))
+		}
+		if len(result.NotEvaluated) > 0 {
+			_, _ = fmt.Fprintf(w, "    not evaluated: %s\n", strings.Join(result.NotEvaluated, ", "))
+		}
+		if result.Status != 0 {
+			_, _ = fmt.Fprintf(w, "    status:      %d\n", result.Status)
+		}
+		if result.URL != "" {
+			_, _ = fmt.Fprintf(w, "    url:         %s\n", result.URL)
+		}
+
+		for _, failure := range result.Failures {
+			_, _ = fmt.Fprintf(w, "    %s\n", failure)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d route tests failed", failed, len(results))
+	}
+
+	return nil
+}
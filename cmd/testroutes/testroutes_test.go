@@ -0,0 +1,258 @@
+package testroutes
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+const dynamicConfig = `
+http:
+  routers:
+    web:
+      entryPoints: [web]
+      rule: PathPrefix(` + "`/`" + `)
+      middlewares: [to-https]
+      service: api
+    api:
+      rule: Host(` + "`example.com`" + `) && PathPrefix(` + "`/api`" + `)
+      middlewares: [api]
+      service: api
+      tls: {}
+    app:
+      rule: Host(` + "`example.com`" + `)
+      service: api
+      tls: {}
+  middlewares:
+    to-https:
+      redirectScheme:
+        scheme: https
+        permanent: true
+    api:
+      chain:
+        middlewares: [strip-api, auth, add-v1]
+    strip-api:
+      stripPrefix:
+        prefixes: [/api]
+    auth:
+      basicAuth:
+        users: ["test:$apr1$H6uskkkW$IgXLP6ewTrSuBkTrqE8wj/"]
+    add-v1:
+      addPrefix:
+        prefix: /v1
+  services:
+    api:
+      loadBalancer:
+        servers:
+          - url: http://10.0.0.1
+`
+
+func TestEvaluate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynamic.yml")
+	require.NoError(t, os.WriteFile(path, []byte(dynamicConfig), 0o600))
+
+	routing, err := loadRoutingFromFile(path)
+	require.NoError(t, err)
+
+	staticConfiguration := static.Configuration{
+		EntryPoints: static.EntryPoints{
+			"web":       {Address: ":80"},
+			"websecure": {Address: ":443", HTTP: static.HTTPConfig{PriorityStrategy: "specificity"}},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		test     Test
+		expected Result
+	}{
+		{
+			desc: "redirection",
+			test: Test{EntryPoint: "web", URL: "http://example.com/api/users?page=2"},
+			expected: Result{
+				Name:        "http://example.com/api/users?page=2",
+				Router:      "web@file",
+				Middlewares: []string{"to-https@file"},
+				Status:      http.StatusMovedPermanently,
+				URL:         "https://example.com/api/users?page=2",
+			},
+		},
+		{
+			desc: "rewrites",
+			test: Test{Name: "api", EntryPoint: "websecure", URL: "https://example.com/api/users"},
+			expected: Result{
+				Name:         "api",
+				Router:       "api@file",
+				Middlewares:  []string{"strip-api@file", "auth@file", "add-v1@file"},
+				NotEvaluated: []string{"auth@file"},
+				URL:          "https://example.com/v1/users",
+			},
+		},
+		{
+			desc: "no middleware",
+			test: Test{URL: "https://example.com/users"},
+			expected: Result{
+				Name:   "https://example.com/users",
+				Router: "app@file",
+				URL:    "https://example.com/users",
+			},
+		},
+		{
+			desc: "no router",
+			test: Test{URL: "https://example.org/"},
+			expected: Result{
+				Name:   "https://example.org/",
+				Status: http.StatusNotFound,
+			},
+		},
+		{
+			desc: "expectations not met",
+			test: Test{
+				URL: "https://example.com/api",
+				Expected: &Expected{
+					Router:      "app@file",
+					Middlewares: []string{},
+					Status:      http.StatusOK,
+				},
+			},
+			expected: Result{
+				Name:         "https://example.com/api",
+				Router:       "api@file",
+				Middlewares:  []string{"strip-api@file", "auth@file", "add-v1@file"},
+				NotEvaluated: []string{"auth@file"},
+				URL:          "https://example.com/v1/",
+				Failures: []string{
+					`expected router "app@file", got "api@file"`,
+					`expected middlewares [], got ["strip-api@file" "auth@file" "add-v1@file"]`,
+					"expected status 200, got 0",
+				},
+			},
+		},
+		{
+			desc: "unknown entry point",
+			test: Test{EntryPoint: "foo", URL: "https://example.com/"},
+			expected: Result{
+				Name:     "https://example.com/",
+				Failures: []string{"unknown entry point foo"},
+			},
+		},
+		{
+			desc: "relative URL",
+			test: Test{URL: "/foo"},
+			expected: Result{
+				Name:     "/foo",
+				Failures: []string{`invalid URL "/foo": an absolute http or https URL is expected`},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			results := Evaluate(routing, staticConfiguration, []Test{test.test})
+			require.Len(t, results, 1)
+
+			assert.Equal(t, test.expected, results[0])
+		})
+	}
+}
+
+func TestLoadRoutingFromAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/rawdata" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{
+  "routers": {
+    "foo@docker": {"rule": "Host(` + "`foo.example.com`" + `)", "middlewares": ["strip"], "service": "foo", "status": "enabled"},
+    "bar@file": {"rule": "Host(` + "`bar.example.com`" + `)", "service": "missing", "status": "disabled", "error": ["the service \"missing@file\" does not exist"]}
+  },
+  "middlewares": {
+    "strip@docker": {"stripPrefix": {"prefixes": ["/foo"]}, "status": "enabled"}
+  }
+}`))
+	}))
+	t.Cleanup(server.Close)
+
+	routing, err := loadRoutingFromAPI(server.Client(), server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]*dynamic.Router{
+		"foo@docker": {Rule: "Host(`foo.example.com`)", Middlewares: []string{"strip"}, Service: "foo"},
+	}, routing.Routers)
+	assert.Equal(t, map[string]*dynamic.Middleware{
+		"strip@docker": {StripPrefix: &dynamic.StripPrefix{Prefixes: []string{"/foo"}}},
+	}, routing.Middlewares)
+
+	results := Evaluate(routing, static.Configuration{}, []Test{{URL: "http://foo.example.com/foo/bar"}})
+	require.Len(t, results, 1)
+	assert.Equal(t, "foo@docker", results[0].Router)
+	assert.Equal(t, []string{"strip@docker"}, results[0].Middlewares)
+	assert.Equal(t, "http://foo.example.com/bar", results[0].URL)
+}
+
+func TestLoadTests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tests.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+tests:
+  - name: redirection
+    entryPoint: web
+    url: http://example.com/
+    headers:
+      X-Foo: bar
+    expected:
+      router: web@file
+      status: 301
+`), 0o600))
+
+	tests, err := loadTests(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Test{{
+		Name:       "redirection",
+		EntryPoint: "web",
+		URL:        "http://example.com/",
+		Headers:    map[string]string{"X-Foo": "bar"},
+		Expected:   &Expected{Router: "web@file", Status: http.StatusMovedPermanently},
+	}}, tests)
+
+	require.NoError(t, os.WriteFile(path, []byte("tests:\n  - path: /foo\n"), 0o600))
+
+	_, err = loadTests(path)
+	assert.Error(t, err)
+}
+
+func TestReport(t *testing.T) {
+	results := []Result{
+		{Name: "redirection", Router: "web@file", Middlewares: []string{"to-https@file"}, Status: http.StatusMovedPermanently, URL: "https://example.com/"},
+		{Name: "not found", Status: http.StatusNotFound, Failures: []string{`expected router "app@file", got ""`}},
+	}
+
+	var buf bytes.Buffer
+	err := report(&buf, results)
+	require.EqualError(t, err, "1 of 2 route tests failed")
+
+	expected := `PASS redirection
+    router:      web@file
+    middlewares: to-https@file
+    status:      301
+    url:         https://example.com/
+FAIL not found
+    router:      none
+    status:      404
+    expected router "app@file", got ""
+`
+	assert.Equal(t, expected, buf.String())
+}
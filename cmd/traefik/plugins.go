@@ -31,6 +31,7 @@ func initPlugins(staticCfg *static.Configuration) (*plugins.Client, map[string]p
 		opts := plugins.ClientOptions{
 			Output: outputDir,
 			Token:  staticCfg.Pilot.Token,
+			Proxy:  staticCfg.OutboundProxy.PluginsProxy(),
 		}
 
 		var err error
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io"
 	stdlog "log"
 	"net/http"
 	"os"
@@ -23,20 +24,31 @@ import (
 	"github.com/traefik/paerser/cli"
 	"github.com/traefik/traefik/v2/autogen/genstatic"
 	"github.com/traefik/traefik/v2/cmd"
+	"github.com/traefik/traefik/v2/cmd/bundle"
 	"github.com/traefik/traefik/v2/cmd/healthcheck"
+	"github.com/traefik/traefik/v2/cmd/testroutes"
 	cmdVersion "github.com/traefik/traefik/v2/cmd/version"
+	"github.com/traefik/traefik/v2/cmd/webhook"
+	"github.com/traefik/traefik/v2/pkg/accounting"
+	"github.com/traefik/traefik/v2/pkg/api"
 	tcli "github.com/traefik/traefik/v2/pkg/cli"
+	"github.com/traefik/traefik/v2/pkg/cluster"
 	"github.com/traefik/traefik/v2/pkg/collector"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	traefikhealthcheck "github.com/traefik/traefik/v2/pkg/healthcheck"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/log/sink"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/middlewares/connections"
 	"github.com/traefik/traefik/v2/pkg/pilot"
 	"github.com/traefik/traefik/v2/pkg/provider/acme"
 	"github.com/traefik/traefik/v2/pkg/provider/aggregator"
+	"github.com/traefik/traefik/v2/pkg/provider/kv"
 	"github.com/traefik/traefik/v2/pkg/provider/traefik"
+	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
@@ -59,8 +71,8 @@ func main() {
 Complete documentation is available at https://traefik.io`,
 		Configuration: tConfig,
 		Resources:     loaders,
-		Run: func(_ []string) error {
-			return runCmd(&tConfig.Configuration)
+		Run: func(args []string) error {
+			return runCmd(&tConfig.Configuration, args)
 		},
 	}
 
@@ -76,6 +88,24 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	err = cmdTraefik.AddCommand(bundle.NewCmd(loaders))
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(testroutes.NewCmd(loaders))
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(webhook.NewCmd([]cli.ResourceLoader{&tcli.FlagLoader{}}))
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cli.Execute(cmdTraefik)
 	if err != nil {
 		stdlog.Println(err)
@@ -85,7 +115,7 @@ Complete documentation is available at https://traefik.io`,
 	logrus.Exit(0)
 }
 
-func runCmd(staticConfiguration *static.Configuration) error {
+func runCmd(staticConfiguration *static.Configuration, args []string) error {
 	configureLogging(staticConfiguration)
 
 	http.DefaultTransport.(*http.Transport).Proxy = http.ProxyFromEnvironment
@@ -94,6 +124,12 @@ func runCmd(staticConfiguration *static.Configuration) error {
 		log.WithoutContext().Errorf("Could not set round robin default weight: %v", err)
 	}
 
+	if staticConfiguration.Global != nil && staticConfiguration.Global.StrictConfig {
+		if err := checkStrictConfig(staticConfiguration); err != nil {
+			return err
+		}
+	}
+
 	staticConfiguration.SetEffectiveConfiguration()
 	if err := staticConfiguration.ValidateConfiguration(); err != nil {
 		return err
@@ -119,7 +155,9 @@ func runCmd(staticConfiguration *static.Configuration) error {
 
 	stats(staticConfiguration)
 
-	svr, err := setupServer(staticConfiguration)
+	svr, err := setupServer(staticConfiguration, func() (*static.Configuration, error) {
+		return loadStaticConfiguration(args)
+	})
 	if err != nil {
 		return err
 	}
@@ -169,7 +207,9 @@ func runCmd(staticConfiguration *static.Configuration) error {
 	return nil
 }
 
-func setupServer(staticConfiguration *static.Configuration) (*server.Server, error) {
+func setupServer(staticConfiguration *static.Configuration, loadStaticConfiguration func() (*static.Configuration, error)) (*server.Server, error) {
+	configureProvidersProxy(staticConfiguration)
+
 	providerAggregator := aggregator.NewProviderAggregator(*staticConfiguration.Providers)
 
 	ctx := context.Background()
@@ -193,20 +233,22 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		return nil, err
 	}
 
-	acmeProviders := initACMEProvider(staticConfiguration, &providerAggregator, tlsManager, httpChallengeProvider, tlsChallengeProvider)
+	// Cluster
 
-	// Entrypoints
+	var clusterNode *cluster.Node
+	if staticConfiguration.Cluster != nil {
+		clusterNode, err = cluster.New(ctx, staticConfiguration.Cluster)
+		if err != nil {
+			return nil, err
+		}
 
-	serverEntryPointsTCP, err := server.NewTCPEntryPoints(staticConfiguration.EntryPoints)
-	if err != nil {
-		return nil, err
-	}
+		httpChallengeProvider.SetStore(clusterNode.ACMEStore())
 
-	serverEntryPointsUDP, err := server.NewUDPEntryPoints(staticConfiguration.EntryPoints)
-	if err != nil {
-		return nil, err
+		routinesPool.GoCtx(clusterNode.Run)
 	}
 
+	acmeProviders := initACMEProvider(staticConfiguration, &providerAggregator, tlsManager, httpChallengeProvider, tlsChallengeProvider, clusterNode)
+
 	// Pilot
 
 	var aviator *pilot.Pilot
@@ -252,30 +294,108 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	if pilotRegistry != nil {
 		metricRegistries = append(metricRegistries, pilotRegistry)
 	}
+
+	// The live traffic of the API is computed from the entry points and routers metrics.
+	var trafficRegistry *metrics.TrafficRegistry
+	if staticConfiguration.API != nil {
+		trafficRegistry = metrics.RegisterTraffic()
+		metricRegistries = append(metricRegistries, trafficRegistry)
+	}
+
 	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
+	if staticConfiguration.Metrics != nil {
+		metricsRegistry = metrics.RewriteLabels(metricsRegistry, staticConfiguration.Metrics.Labels)
+	}
 
-	// Service manager factory
+	rules.SetCacheRequestsCounter(metricsRegistry.RulesCacheRequestsCounter())
+	kv.SetSuppressedReloadsCounter(metricsRegistry.ProviderSuppressedReloadsCounter())
 
-	roundTripperManager := service.NewRoundTripperManager()
-	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
-	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler)
+	// Accounting
 
-	// Router factory
+	accountant, err := setupAccountant(staticConfiguration.Accounting, routinesPool)
+	if err != nil {
+		return nil, err
+	}
 
-	accessLog := setupAccessLog(staticConfiguration.AccessLog)
-	chainBuilder := middleware.NewChainBuilder(*staticConfiguration, metricsRegistry, accessLog)
-	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry)
+	// Entrypoints
+
+	serverEntryPointsTCP, err := server.NewTCPEntryPoints(staticConfiguration.EntryPoints, metricsRegistry)
+	if err != nil {
+		return nil, err
+	}
+
+	serverEntryPointsUDP, err := server.NewUDPEntryPoints(staticConfiguration.EntryPoints, metricsRegistry)
+	if err != nil {
+		return nil, err
+	}
 
 	// Watcher
 
+	var historySize int
+	if staticConfiguration.API != nil {
+		historySize = staticConfiguration.API.HistorySize
+	}
+
 	watcher := server.NewConfigurationWatcher(
 		routinesPool,
 		providerAggregator,
 		time.Duration(staticConfiguration.Providers.ProvidersThrottleDuration),
-		getDefaultsEntrypoints(staticConfiguration),
+		server.DefaultEntryPoints(*staticConfiguration),
 		"internal",
+		staticConfiguration.Providers.Expiry,
+		historySize,
 	)
 
+	var configurationHistory api.ConfigurationHistory
+	if historySize > 0 {
+		configurationHistory = watcher
+	}
+
+	// Static configuration reload
+
+	// The reload of the static configuration changes the listening entry points, and must be explicitly enabled.
+	var staticReloader *server.StaticReloader
+	var staticConfigurationReloader api.StaticConfigurationReloader
+	if staticConfiguration.Global != nil && staticConfiguration.Global.StaticReload {
+		staticReloader = server.NewStaticReloader(*staticConfiguration, loadStaticConfiguration, watcher, serverEntryPointsTCP, serverEntryPointsUDP, metricsRegistry)
+		staticConfigurationReloader = staticReloader
+	}
+
+	// Service manager factory
+
+	roundTripperManager := service.NewRoundTripperManager()
+	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
+
+	// The rotation of the ACME account keys changes the state of the resolvers, and must be explicitly enabled.
+	var accountKeyRotators map[string]api.AccountKeyRotator
+	if staticConfiguration.API != nil && staticConfiguration.API.AccountKeyRotation {
+		accountKeyRotators = getAccountKeyRotators(acmeProviders)
+	}
+
+	var clusterMembership api.ClusterMembership
+	if clusterNode != nil {
+		clusterMembership = clusterNode
+	}
+	var trafficStatistics api.TrafficStatistics
+	if trafficRegistry != nil {
+		trafficStatistics = trafficRegistry
+	}
+
+	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler, accountKeyRotators, configurationHistory, staticConfigurationReloader, clusterMembership, trafficStatistics)
+
+	// Router factory
+
+	accessLog := setupAccessLog(staticConfiguration.AccessLog)
+	chainBuilder, err := middleware.NewChainBuilder(*staticConfiguration, metricsRegistry, accessLog)
+	if err != nil {
+		return nil, err
+	}
+	connObserver := connections.NewObserver(accessLog, metricsRegistry)
+	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, chainBuilder, pluginBuilder, metricsRegistry, accountant, connObserver)
+	if staticReloader != nil {
+		staticReloader.AddEntryPointListener(routerFactory.AddEntryPoint)
+	}
+
 	// TLS
 	watcher.AddListener(func(conf dynamic.Configuration) {
 		ctx := context.Background()
@@ -288,8 +408,15 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	})
 
 	// Metrics
-	watcher.AddListener(func(_ dynamic.Configuration) {
+	watcher.AddReloadListener(func(err error) {
 		metricsRegistry.ConfigReloadsCounter().Add(1)
+
+		if err != nil {
+			metricsRegistry.ConfigReloadsFailureCounter().Add(1)
+			metricsRegistry.LastConfigReloadFailureGauge().Set(float64(time.Now().Unix()))
+			return
+		}
+
 		metricsRegistry.LastConfigReloadSuccessGauge().Set(float64(time.Now().Unix()))
 	})
 
@@ -298,16 +425,29 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		roundTripperManager.Update(conf.HTTP.ServersTransports)
 	})
 
+	// Kubernetes Events
+	var runtimeListeners []func(*runtime.Configuration)
+	if crdProvider := staticConfiguration.Providers.KubernetesCRD; crdProvider != nil && crdProvider.EmitEvents {
+		traefikhealthcheck.GetHealthCheck(metricsRegistry).AddServerStatusListener(crdProvider.ServerStatusChanged)
+		runtimeListeners = append(runtimeListeners, crdProvider.UpdateRuntimeStatus)
+	}
+
+	// Cluster runtime state
+	if clusterNode != nil {
+		runtimeListeners = append(runtimeListeners, clusterNode.SetRuntimeConfiguration)
+	}
+
 	// Switch router
-	watcher.AddListener(switchRouter(routerFactory, serverEntryPointsTCP, serverEntryPointsUDP, aviator))
+	watcher.AddListener(switchRouter(routerFactory, serverEntryPointsTCP, serverEntryPointsUDP, aviator, watcher, runtimeListeners, staticConfiguration.Providers.RollbackOnError))
 
 	// Metrics
 	if metricsRegistry.IsEpEnabled() || metricsRegistry.IsSvcEnabled() {
-		var eps []string
-		for key := range serverEntryPointsTCP {
-			eps = append(eps, key)
-		}
 		watcher.AddListener(func(conf dynamic.Configuration) {
+			// The entry points are listed on each update, as entry points can be added by a reload of the static configuration.
+			var eps []string
+			for key := range serverEntryPointsTCP {
+				eps = append(eps, key)
+			}
 			metrics.OnConfigurationUpdate(conf, eps)
 		})
 	}
@@ -335,7 +475,7 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	})
 
-	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog), nil
+	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog, staticReloader), nil
 }
 
 func getHTTPChallengeHandler(acmeProviders []*acme.Provider, httpChallengeProvider http.Handler) http.Handler {
@@ -349,59 +489,151 @@ func getHTTPChallengeHandler(acmeProviders []*acme.Provider, httpChallengeProvid
 	return acmeHTTPHandler
 }
 
-func getDefaultsEntrypoints(staticConfiguration *static.Configuration) []string {
-	var defaultEntryPoints []string
-	for name, cfg := range staticConfiguration.EntryPoints {
-		protocol, err := cfg.GetProtocol()
+func getAccountKeyRotators(acmeProviders []*acme.Provider) map[string]api.AccountKeyRotator {
+	rotators := make(map[string]api.AccountKeyRotator)
+	for _, p := range acmeProviders {
+		if p != nil {
+			rotators[p.ResolverName] = p
+		}
+	}
+	return rotators
+}
+
+// loadStaticConfiguration loads the static configuration again, from the same sources as on startup.
+func loadStaticConfiguration(args []string) (*static.Configuration, error) {
+	tConfig := cmd.NewTraefikConfiguration()
+
+	cmdTraefik := &cli.Command{
+		Name:          "traefik",
+		Configuration: tConfig,
+		Resources:     []cli.ResourceLoader{&tcli.FileLoader{}, &tcli.FlagLoader{}, &tcli.EnvLoader{}},
+	}
+
+	for _, resource := range cmdTraefik.Resources {
+		done, err := resource.Load(args, cmdTraefik)
 		if err != nil {
-			// Should never happen because Traefik should not start if protocol is invalid.
-			log.WithoutContext().Errorf("Invalid protocol: %v", err)
+			return nil, err
+		}
+		if done {
+			break
 		}
+	}
+
+	staticConfiguration := &tConfig.Configuration
 
-		if protocol != "udp" && name != static.DefaultInternalEntryPointName {
-			defaultEntryPoints = append(defaultEntryPoints, name)
+	if staticConfiguration.Global != nil && staticConfiguration.Global.StrictConfig {
+		if err := checkStrictConfig(staticConfiguration); err != nil {
+			return nil, err
 		}
 	}
 
-	sort.Strings(defaultEntryPoints)
-	return defaultEntryPoints
+	staticConfiguration.SetEffectiveConfiguration()
+	if err := staticConfiguration.ValidateConfiguration(); err != nil {
+		return nil, err
+	}
+
+	configureProvidersProxy(staticConfiguration)
+
+	return staticConfiguration, nil
+}
+
+// checkStrictConfig fails if the static configuration, or the configuration files of the file provider, have warnings.
+func checkStrictConfig(staticConfiguration *static.Configuration) error {
+	problems := staticConfiguration.Warnings()
+
+	if staticConfiguration.Providers != nil && staticConfiguration.Providers.File != nil {
+		fileProblems, err := staticConfiguration.Providers.File.Check()
+		if err != nil {
+			return fmt.Errorf("strict configuration: unable to check the file provider configuration: %w", err)
+		}
+
+		problems = append(problems, fileProblems...)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("strict configuration: %d warning(s):\n  - %s", len(problems), strings.Join(problems, "\n  - "))
 }
 
-func switchRouter(routerFactory *server.RouterFactory, serverEntryPointsTCP server.TCPEntryPoints, serverEntryPointsUDP server.UDPEntryPoints, aviator *pilot.Pilot) func(conf dynamic.Configuration) {
+func switchRouter(routerFactory *server.RouterFactory, serverEntryPointsTCP server.TCPEntryPoints, serverEntryPointsUDP server.UDPEntryPoints, aviator *pilot.Pilot, watcher *server.ConfigurationWatcher, runtimeListeners []func(*runtime.Configuration), rollbackOnError bool) func(conf dynamic.Configuration) {
+	// failingRouters are the routers failing with the last switched configuration, if any.
+	var failingRouters map[string]struct{}
+
 	return func(conf dynamic.Configuration) {
 		rtConf := runtime.NewConfig(conf)
+		rtConf.MarkStale(watcher.StaleProviders())
+		rtConf.RejectedReload = watcher.LastRejection()
 
 		routers, udpRouters := routerFactory.CreateRouters(rtConf)
 
+		if rollbackOnError {
+			// The routers which were already failing do not prevent the configuration from being applied,
+			// nor do the failing routers of the first configuration, as there is no previous one to keep.
+			var newlyFailing []string
+			for _, name := range rtConf.FailingRouters() {
+				if _, ok := failingRouters[name]; failingRouters != nil && !ok {
+					newlyFailing = append(newlyFailing, name)
+				}
+			}
+
+			if len(newlyFailing) > 0 {
+				watcher.Reject(fmt.Errorf("routers failing to build: %s", strings.Join(newlyFailing, ", ")))
+				return
+			}
+
+			failingRouters = make(map[string]struct{})
+			for _, name := range rtConf.FailingRouters() {
+				failingRouters[name] = struct{}{}
+			}
+		}
+
 		if aviator != nil {
 			aviator.SetDynamicConfiguration(conf)
 		}
 
+		for _, listener := range runtimeListeners {
+			listener(rtConf)
+		}
+
 		serverEntryPointsTCP.Switch(routers)
 		serverEntryPointsUDP.Switch(udpRouters)
 	}
 }
 
 // initACMEProvider creates an acme provider from the ACME part of globalConfiguration.
-func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator, tlsManager *traefiktls.Manager, httpChallengeProvider, tlsChallengeProvider challenge.Provider) []*acme.Provider {
+// In cluster mode, the resolvers share their data through the KV store of the cluster, and only the leader obtains the certificates.
+func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.ProviderAggregator, tlsManager *traefiktls.Manager, httpChallengeProvider, tlsChallengeProvider challenge.Provider, clusterNode *cluster.Node) []*acme.Provider {
 	localStores := map[string]*acme.LocalStore{}
 
+	var clusterStore *cluster.ACMEStore
+	if clusterNode != nil {
+		clusterStore = clusterNode.ACMEStore()
+	}
+
 	var resolvers []*acme.Provider
 	for name, resolver := range c.CertificatesResolvers {
 		if resolver.ACME == nil {
 			continue
 		}
 
-		if localStores[resolver.ACME.Storage] == nil {
-			localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage)
-		}
-
 		p := &acme.Provider{
 			Configuration:         resolver.ACME,
-			Store:                 localStores[resolver.ACME.Storage],
 			ResolverName:          name,
 			HTTPChallengeProvider: httpChallengeProvider,
 			TLSChallengeProvider:  tlsChallengeProvider,
+			Proxy:                 c.OutboundProxy.ACMEProxy(),
+		}
+
+		if clusterNode != nil {
+			p.Store = clusterStore
+			p.IsLeader = clusterNode.IsLeader
+		} else {
+			if localStores[resolver.ACME.Storage] == nil {
+				localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage)
+			}
+			p.Store = localStores[resolver.ACME.Storage]
 		}
 
 		if err := providerAggregator.AddProvider(p); err != nil {
@@ -428,7 +660,7 @@ func registerMetricClients(metricsConfig *types.Metrics) []metrics.Registry {
 
 	if metricsConfig.Prometheus != nil {
 		ctx := log.With(context.Background(), log.Str(log.MetricsProviderName, "prometheus"))
-		prometheusRegister := metrics.RegisterPrometheus(ctx, metricsConfig.Prometheus)
+		prometheusRegister := metrics.RegisterPrometheus(ctx, metricsConfig.Prometheus, len(metricsConfig.Operations) > 0)
 		if prometheusRegister != nil {
 			registries = append(registries, prometheusRegister)
 			log.FromContext(ctx).Debug("Configured Prometheus metrics")
@@ -456,6 +688,23 @@ func registerMetricClients(metricsConfig *types.Metrics) []metrics.Registry {
 			metricsConfig.InfluxDB.Address, metricsConfig.InfluxDB.PushInterval)
 	}
 
+	if metricsConfig.InfluxDB2 != nil {
+		ctx := log.With(context.Background(), log.Str(log.MetricsProviderName, "influxdb2"))
+		registries = append(registries, metrics.RegisterInfluxDB2(ctx, metricsConfig.InfluxDB2))
+		log.FromContext(ctx).Debugf("Configured InfluxDB v2 metrics: pushing to %s (%s bucket) once every %s",
+			metricsConfig.InfluxDB2.Address, metricsConfig.InfluxDB2.Bucket, metricsConfig.InfluxDB2.PushInterval)
+	}
+
+	if metricsConfig.OpenTelemetry != nil {
+		ctx := log.With(context.Background(), log.Str(log.MetricsProviderName, "openTelemetry"))
+		openTelemetryRegistry := metrics.RegisterOpenTelemetry(ctx, metricsConfig.OpenTelemetry)
+		if openTelemetryRegistry != nil {
+			registries = append(registries, openTelemetryRegistry)
+			log.FromContext(ctx).Debugf("Configured OpenTelemetry metrics: pushing to %s once every %s",
+				metricsConfig.OpenTelemetry.Address, metricsConfig.OpenTelemetry.PushInterval)
+		}
+	}
+
 	return registries
 }
 
@@ -487,6 +736,21 @@ func setupAccessLog(conf *types.AccessLog) *accesslog.Handler {
 	return accessLoggerMiddleware
 }
 
+func setupAccountant(conf *types.Accounting, routinesPool *safe.Pool) (*accounting.Accountant, error) {
+	if conf == nil {
+		return nil, nil
+	}
+
+	accountant, err := accounting.New(conf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create accountant: %w", err)
+	}
+
+	routinesPool.GoCtx(accountant.Run)
+
+	return accountant, nil
+}
+
 func configureLogging(staticConfiguration *static.Configuration) {
 	// configure default log flags
 	stdlog.SetFlags(stdlog.Lshortfile | stdlog.LstdFlags)
@@ -538,6 +802,78 @@ func configureLogging(staticConfiguration *static.Configuration) {
 			log.WithoutContext().Errorf("Error while opening log file %s: %v", logFile, err)
 		}
 	}
+
+	if staticConfiguration.Log != nil {
+		configureLogSinks(staticConfiguration.Log, len(logFile) > 0)
+	}
+}
+
+// configureProvidersProxy sets the upstream proxy of the providers reaching their APIs with their own HTTP clients.
+func configureProvidersProxy(staticConfiguration *static.Configuration) {
+	proxy := staticConfiguration.OutboundProxy.ProvidersProxy()
+	if proxy == nil {
+		return
+	}
+
+	if staticConfiguration.Providers.HTTP != nil {
+		staticConfiguration.Providers.HTTP.SetProxy(proxy)
+	}
+
+	if staticConfiguration.Providers.Marathon != nil {
+		staticConfiguration.Providers.Marathon.SetProxy(proxy)
+	}
+
+	if staticConfiguration.Providers.Rancher != nil {
+		staticConfiguration.Providers.Rancher.SetProxy(proxy)
+	}
+}
+
+// configureLogSinks sends the logs to the configured syslog and fluentd outputs.
+// The logs are not written to stdout anymore, unless they are written to a file.
+func configureLogSinks(config *types.TraefikLog, hasFile bool) {
+	// The formatter of the standard logger would enable the colors on a terminal.
+	var formatter logrus.Formatter = &logrus.TextFormatter{DisableColors: true, FullTimestamp: true, DisableSorting: true}
+	if config.Format == "json" {
+		formatter = &logrus.JSONFormatter{}
+	}
+
+	var writers []*sink.Writer
+
+	if config.Syslog != nil {
+		writer, err := sink.NewSyslog(config.Syslog)
+		if err != nil {
+			log.WithoutContext().Errorf("Error while creating the syslog log output: %v", err)
+		} else {
+			writers = append(writers, writer)
+		}
+	}
+
+	if config.Fluentd != nil {
+		writer, err := sink.NewFluentd(config.Fluentd)
+		if err != nil {
+			log.WithoutContext().Errorf("Error while creating the fluentd log output: %v", err)
+		} else {
+			writers = append(writers, writer)
+		}
+	}
+
+	if len(writers) == 0 {
+		return
+	}
+
+	for _, writer := range writers {
+		log.AddHook(sink.NewHook(writer, formatter))
+	}
+
+	if !hasFile {
+		log.SetOutput(io.Discard)
+	}
+
+	logrus.RegisterExitHandler(func() {
+		for _, writer := range writers {
+			_ = writer.Close()
+		}
+	})
 }
 
 func checkNewVersion() {
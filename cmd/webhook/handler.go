@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/plugins"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/traefik/v2/pkg/server/middleware"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type validator interface {
+	ValidateIngressRoute(ctx context.Context, ingressRoute *v1alpha1.IngressRoute) (*crd.Validation, error)
+	ValidateMiddleware(ctx context.Context, middleware *v1alpha1.Middleware) (*crd.Validation, error)
+	ValidateTLSOption(ctx context.Context, tlsOption *v1alpha1.TLSOption) (*crd.Validation, error)
+}
+
+// handler handles the admission reviews sent by the Kubernetes API server.
+type handler struct {
+	validator          validator
+	sourceRangeSources *types.SourceRangeSources
+}
+
+func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(req.Body).Decode(&review); err != nil {
+		http.Error(rw, fmt.Sprintf("invalid admission review: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if review.Request == nil {
+		http.Error(rw, "invalid admission review: missing request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.review(req.Context(), review.Request)
+	review.Request = nil
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(review); err != nil {
+		log.FromContext(req.Context()).Errorf("Unable to write the admission review: %v", err)
+	}
+}
+
+func (h *handler) review(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	response := &admissionv1.AdmissionResponse{UID: request.UID, Allowed: true}
+
+	if request.Operation == admissionv1.Delete {
+		return response
+	}
+
+	logger := log.FromContext(ctx).WithField("kind", request.Kind.Kind).WithField("namespace", request.Namespace).WithField("name", request.Name)
+
+	if err := h.validate(ctx, request); err != nil {
+		logger.Debugf("Rejecting resource: %v", err)
+
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		}
+	}
+
+	return response
+}
+
+func (h *handler) validate(ctx context.Context, request *admissionv1.AdmissionRequest) error {
+	var validation *crd.Validation
+	var err error
+
+	switch request.Kind.Kind {
+	case "IngressRoute":
+		ingressRoute := &v1alpha1.IngressRoute{}
+		if err = decodeObject(request, ingressRoute, &ingressRoute.ObjectMeta); err != nil {
+			return err
+		}
+
+		validation, err = h.validator.ValidateIngressRoute(ctx, ingressRoute)
+
+	case "Middleware":
+		middleware := &v1alpha1.Middleware{}
+		if err = decodeObject(request, middleware, &middleware.ObjectMeta); err != nil {
+			return err
+		}
+
+		validation, err = h.validator.ValidateMiddleware(ctx, middleware)
+
+	case "TLSOption":
+		tlsOption := &v1alpha1.TLSOption{}
+		if err = decodeObject(request, tlsOption, &tlsOption.ObjectMeta); err != nil {
+			return err
+		}
+
+		validation, err = h.validator.ValidateTLSOption(ctx, tlsOption)
+
+	default:
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if validation == nil {
+		return nil
+	}
+
+	return checkConfiguration(ctx, validation, h.sourceRangeSources)
+}
+
+func decodeObject(request *admissionv1.AdmissionRequest, object interface{}, meta *metav1.ObjectMeta) error {
+	if err := json.Unmarshal(request.Object.Raw, object); err != nil {
+		return fmt.Errorf("invalid %s: %w", request.Kind.Kind, err)
+	}
+
+	// The namespace is not part of the object when it comes from the request context (e.g. kubectl apply -n).
+	if meta.Namespace == "" {
+		meta.Namespace = request.Namespace
+	}
+
+	return nil
+}
+
+// checkConfiguration builds the configuration elements of the validation as Traefik does at runtime,
+// where building errors silently disable them.
+// The services and the plugins are not built.
+func checkConfiguration(ctx context.Context, validation *crd.Validation, sourceRangeSources *types.SourceRangeSources) error {
+	conf := validation.Configuration
+	providerName := validation.ProviderName
+
+	middlewares := make(map[string]*runtime.MiddlewareInfo)
+	for name, m := range conf.HTTP.Middlewares {
+		if m.Chain != nil {
+			m = m.DeepCopy()
+			m.Chain.Middlewares = existingMiddlewares(validation, m.Chain.Middlewares)
+		}
+
+		middlewares[provider.MakeQualifiedName(providerName, name)] = &runtime.MiddlewareInfo{Middleware: m}
+	}
+
+	builder := middleware.NewBuilder(middlewares, serviceBuilder{}, pluginBuilder{}, nil, sourceRangeSources, nil)
+
+	for _, name := range validation.Routers {
+		router, ok := conf.HTTP.Routers[name]
+		if !ok {
+			continue
+		}
+
+		ctxRouter := provider.AddInContext(ctx, provider.MakeQualifiedName(providerName, name))
+
+		rulesRouter, err := rules.NewRouter()
+		if err != nil {
+			return err
+		}
+
+		err = rulesRouter.AddRoute(router.Rule, router.Priority, http.NotFoundHandler())
+		if err != nil {
+			return fmt.Errorf("router %s: %w", name, err)
+		}
+
+		_, err = builder.BuildChain(ctxRouter, existingMiddlewares(validation, router.Middlewares)).Then(http.NotFoundHandler())
+		if err != nil {
+			return fmt.Errorf("router %s: %w", name, err)
+		}
+	}
+
+	for _, name := range validation.Middlewares {
+		ctxMiddleware := provider.AddInContext(ctx, provider.MakeQualifiedName(providerName, name))
+
+		_, err := builder.BuildChain(ctxMiddleware, []string{name}).Then(http.NotFoundHandler())
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range validation.TLSOptions {
+		manager := tls.NewManager()
+		manager.UpdateConfigs(ctx, nil, conf.TLS.Options, nil)
+
+		if _, err := manager.Get(tls.DefaultTLSStoreName, name); err != nil {
+			return fmt.Errorf("TLS options %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// existingMiddlewares returns the referenced middlewares which are defined in the configuration of the validation.
+// The other ones are not checked, as they may belong to another provider or be applied later on.
+func existingMiddlewares(validation *crd.Validation, names []string) []string {
+	var result []string
+	for _, name := range names {
+		if _, ok := validation.Configuration.HTTP.Middlewares[strings.TrimSuffix(name, "@"+validation.ProviderName)]; ok {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// serviceBuilder does not build the services, which are not validated.
+type serviceBuilder struct{}
+
+func (serviceBuilder) BuildHTTP(_ context.Context, _ string) (http.Handler, error) {
+	return http.NotFoundHandler(), nil
+}
+
+// pluginBuilder does not build the plugins, which are not loaded by the webhook.
+type pluginBuilder struct{}
+
+func (pluginBuilder) Build(_ string, _ map[string]interface{}, _ string) (plugins.Constructor, error) {
+	return func(_ context.Context, next http.Handler) (http.Handler, error) {
+		return next, nil
+	}, nil
+}
@@ -0,0 +1,213 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/traefik/traefik/v2/pkg/tls"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+type validatorMock struct {
+	validation *crd.Validation
+	err        error
+	namespace  string
+}
+
+func (v *validatorMock) ValidateIngressRoute(_ context.Context, ingressRoute *v1alpha1.IngressRoute) (*crd.Validation, error) {
+	v.namespace = ingressRoute.Namespace
+	return v.validation, v.err
+}
+
+func (v *validatorMock) ValidateMiddleware(_ context.Context, middleware *v1alpha1.Middleware) (*crd.Validation, error) {
+	v.namespace = middleware.Namespace
+	return v.validation, v.err
+}
+
+func (v *validatorMock) ValidateTLSOption(_ context.Context, tlsOption *v1alpha1.TLSOption) (*crd.Validation, error) {
+	v.namespace = tlsOption.Namespace
+	return v.validation, v.err
+}
+
+func TestHandler(t *testing.T) {
+	newValidation := func(routers, middlewares, tlsOptions []string) *crd.Validation {
+		return &crd.Validation{
+			ProviderName: "kubernetescrd",
+			Configuration: &dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"valid":   {Rule: "Host(`foo.com`)", Middlewares: []string{"valid", "missing", "foo@file"}},
+						"invalid": {Rule: "Host(`foo.com`"},
+						"chained": {Rule: "Host(`foo.com`)", Middlewares: []string{"invalid-chain"}},
+					},
+					Middlewares: map[string]*dynamic.Middleware{
+						"valid":         {Chain: &dynamic.Chain{Middlewares: []string{"regex@kubernetescrd", "missing"}}},
+						"regex":         {StripPrefixRegex: &dynamic.StripPrefixRegex{Regex: []string{"^/foo/(.*)"}}},
+						"invalid":       {ReplacePathRegex: &dynamic.ReplacePathRegex{Regex: "(", Replacement: "/"}},
+						"invalid-chain": {Chain: &dynamic.Chain{Middlewares: []string{"invalid"}}},
+						"plugin":        {Plugin: map[string]dynamic.PluginConf{"foo": {"bar": "baz"}}},
+					},
+				},
+				TLS: &dynamic.TLSConfiguration{
+					Options: map[string]tls.Options{
+						"valid":   {MinVersion: "VersionTLS12"},
+						"invalid": {CipherSuites: []string{"foo"}},
+					},
+				},
+			},
+			Routers:     routers,
+			Middlewares: middlewares,
+			TLSOptions:  tlsOptions,
+		}
+	}
+
+	testCases := []struct {
+		desc              string
+		kind              string
+		operation         admissionv1.Operation
+		validation        *crd.Validation
+		err               error
+		expectedAllowed   bool
+		expectedMessage   string
+		expectedNamespace string
+	}{
+		{
+			desc:              "valid router",
+			kind:              "IngressRoute",
+			validation:        newValidation([]string{"valid"}, nil, nil),
+			expectedAllowed:   true,
+			expectedNamespace: "default",
+		},
+		{
+			desc:            "invalid router rule",
+			kind:            "IngressRoute",
+			validation:      newValidation([]string{"valid", "invalid"}, nil, nil),
+			expectedMessage: "router invalid: ",
+		},
+		{
+			desc:            "router with an invalid middleware",
+			kind:            "IngressRoute",
+			validation:      newValidation([]string{"chained"}, nil, nil),
+			expectedMessage: "router chained: ",
+		},
+		{
+			desc:            "ignored IngressRoute",
+			kind:            "IngressRoute",
+			expectedAllowed: true,
+		},
+		{
+			desc:            "valid middleware",
+			kind:            "Middleware",
+			validation:      newValidation(nil, []string{"valid", "regex", "plugin"}, nil),
+			expectedAllowed: true,
+		},
+		{
+			desc:            "invalid middleware",
+			kind:            "Middleware",
+			validation:      newValidation(nil, []string{"invalid"}, nil),
+			expectedMessage: "error parsing regexp",
+		},
+		{
+			desc:            "valid TLS options",
+			kind:            "TLSOption",
+			validation:      newValidation(nil, nil, []string{"valid"}),
+			expectedAllowed: true,
+		},
+		{
+			desc:            "invalid TLS options",
+			kind:            "TLSOption",
+			validation:      newValidation(nil, nil, []string{"invalid"}),
+			expectedMessage: "TLS options invalid: ",
+		},
+		{
+			desc:            "provider error",
+			kind:            "Middleware",
+			err:             errors.New("foo"),
+			expectedMessage: "foo",
+		},
+		{
+			desc:            "deletion",
+			kind:            "Middleware",
+			operation:       admissionv1.Delete,
+			err:             errors.New("foo"),
+			expectedAllowed: true,
+		},
+		{
+			desc:            "unknown kind",
+			kind:            "TraefikService",
+			err:             errors.New("foo"),
+			expectedAllowed: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			operation := test.operation
+			if operation == "" {
+				operation = admissionv1.Create
+			}
+
+			review := admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					UID:       "uid",
+					Namespace: "default",
+					Operation: operation,
+				},
+			}
+			review.Request.Kind.Kind = test.kind
+			review.Request.Object.Raw = []byte(`{"metadata": {"name": "foo"}}`)
+
+			body, err := json.Marshal(review)
+			require.NoError(t, err)
+
+			validator := &validatorMock{validation: test.validation, err: test.err}
+			h := &handler{validator: validator}
+
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body))))
+			require.Equal(t, http.StatusOK, rw.Code)
+
+			var result admissionv1.AdmissionReview
+			err = json.NewDecoder(rw.Body).Decode(&result)
+			require.NoError(t, err)
+
+			require.NotNil(t, result.Response)
+			assert.Equal(t, "uid", string(result.Response.UID))
+			assert.Equal(t, test.expectedAllowed, result.Response.Allowed)
+
+			if test.expectedNamespace != "" {
+				assert.Equal(t, test.expectedNamespace, validator.namespace)
+			}
+
+			if test.expectedAllowed {
+				assert.Nil(t, result.Response.Result)
+				return
+			}
+
+			require.NotNil(t, result.Response.Result)
+			assert.Contains(t, result.Response.Result.Message, test.expectedMessage)
+		})
+	}
+}
+
+func TestHandler_invalidReview(t *testing.T) {
+	h := &handler{validator: &validatorMock{}}
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)))
+
+	assert.Equal(t, http.StatusBadRequest, rw.Code)
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// Configuration holds the configuration of the validation webhook.
+type Configuration struct {
+	Address       string        `description:"Address the webhook listens on." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	CertFile      string        `description:"TLS certificate file." json:"certFile,omitempty" toml:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile       string        `description:"TLS key file." json:"keyFile,omitempty" toml:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+	KubernetesCRD *crd.Provider `description:"Kubernetes IngressRoute provider, whose resources are validated." json:"kubernetesCRD,omitempty" toml:"kubernetesCRD,omitempty" yaml:"kubernetesCRD,omitempty"`
+
+	SourceRangeSources *types.SourceRangeSources `description:"Files and URLs the IPWhiteList middlewares can read their source ranges from, as in the Traefik static configuration." json:"sourceRangeSources,omitempty" toml:"sourceRangeSources,omitempty" yaml:"sourceRangeSources,omitempty"`
+}
+
+// NewConfiguration creates a Configuration with the default values.
+func NewConfiguration() *Configuration {
+	provider := &crd.Provider{}
+	provider.SetDefaults()
+
+	return &Configuration{
+		Address:       ":9443",
+		KubernetesCRD: provider,
+	}
+}
+
+// NewCmd builds a new Webhook command.
+func NewCmd(loaders []cli.ResourceLoader) *cli.Command {
+	webhookConfiguration := NewConfiguration()
+
+	return &cli.Command{
+		Name: "webhook",
+		Description: `Runs a Kubernetes admission webhook validating the IngressRoute, Middleware and TLSOption resources,
+with the logic used by Traefik to build its configuration from them.`,
+		Configuration: webhookConfiguration,
+		Run:           runCmd(webhookConfiguration),
+		Resources:     loaders,
+	}
+}
+
+func runCmd(webhookConfiguration *Configuration) func(_ []string) error {
+	return func(_ []string) error {
+		if webhookConfiguration.CertFile == "" || webhookConfiguration.KeyFile == "" {
+			return errors.New("a TLS certificate and key are required by the Kubernetes API server to call the webhook")
+		}
+
+		stop := make(chan struct{})
+		defer close(stop)
+
+		validator, err := webhookConfiguration.KubernetesCRD.NewValidator(context.Background(), stop)
+		if err != nil {
+			return err
+		}
+
+		server := &http.Server{
+			Addr:    webhookConfiguration.Address,
+			Handler: &handler{validator: validator, sourceRangeSources: webhookConfiguration.SourceRangeSources},
+		}
+
+		log.WithoutContext().Infof("Starting validation webhook on %s", webhookConfiguration.Address)
+
+		return server.ListenAndServeTLS(webhookConfiguration.CertFile, webhookConfiguration.KeyFile)
+	}
+}
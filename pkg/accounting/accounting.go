@@ -0,0 +1,183 @@
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// Record is the usage of a router/service pair over a flush period.
+// Its JSON representation is the stable schema of the accounting file.
+type Record struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	Router        string    `json:"router"`
+	Service       string    `json:"service"`
+	Requests      int64     `json:"requests"`
+	RequestBytes  int64     `json:"requestBytes"`
+	ResponseBytes int64     `json:"responseBytes"`
+}
+
+type usageKey struct {
+	router  string
+	service string
+}
+
+type usage struct {
+	requests      int64
+	requestBytes  int64
+	responseBytes int64
+}
+
+// Accountant accumulates the request and response sizes per router and service,
+// and periodically flushes them to its writer.
+// Contrary to the metrics, every request is accounted exactly once.
+type Accountant struct {
+	interval time.Duration
+
+	mu     sync.Mutex
+	start  time.Time
+	usages map[usageKey]*usage
+
+	writer io.WriteCloser
+}
+
+// New creates a new Accountant writing its records to the configured file.
+func New(config *types.Accounting) (*Accountant, error) {
+	if config == nil {
+		return nil, errors.New("accounting configuration is missing")
+	}
+
+	if config.FilePath == "" {
+		return nil, errors.New("accounting file path is missing")
+	}
+
+	dir := filepath.Dir(config.FilePath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create accounting path %s: %w", dir, err)
+	}
+
+	file, err := os.OpenFile(config.FilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o664)
+	if err != nil {
+		return nil, fmt.Errorf("error opening accounting file %s: %w", config.FilePath, err)
+	}
+
+	return newAccountant(file, time.Duration(config.FlushInterval)), nil
+}
+
+func newAccountant(writer io.WriteCloser, interval time.Duration) *Accountant {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &Accountant{
+		interval: interval,
+		start:    time.Now(),
+		usages:   make(map[usageKey]*usage),
+		writer:   writer,
+	}
+}
+
+// Add accounts one request with the given request and response sizes for a router/service pair.
+func (a *Accountant) Add(routerName, serviceName string, requestBytes, responseBytes int64) {
+	key := usageKey{router: routerName, service: serviceName}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	u, ok := a.usages[key]
+	if !ok {
+		u = &usage{}
+		a.usages[key] = u
+	}
+
+	u.requests++
+	u.requestBytes += requestBytes
+	u.responseBytes += responseBytes
+}
+
+// Run flushes the accumulated records at every interval, until the context is done.
+// A last flush is done before closing the writer.
+func (a *Accountant) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	logger := log.FromContext(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := a.Flush(); err != nil {
+				logger.Errorf("Unable to flush accounting records: %v", err)
+			}
+
+		case <-ctx.Done():
+			if err := a.Flush(); err != nil {
+				logger.Errorf("Unable to flush accounting records: %v", err)
+			}
+
+			if err := a.writer.Close(); err != nil {
+				logger.Errorf("Unable to close accounting file: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// Flush writes the records accumulated since the last flush, and resets them.
+func (a *Accountant) Flush() error {
+	records := a.snapshot(time.Now())
+	if len(records) == 0 {
+		return nil
+	}
+
+	encoder := json.NewEncoder(a.writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Accountant) snapshot(end time.Time) []Record {
+	a.mu.Lock()
+	usages := a.usages
+	start := a.start
+	a.usages = make(map[usageKey]*usage)
+	a.start = end
+	a.mu.Unlock()
+
+	records := make([]Record, 0, len(usages))
+	for key, u := range usages {
+		records = append(records, Record{
+			Start:         start,
+			End:           end,
+			Router:        key.router,
+			Service:       key.service,
+			Requests:      u.requests,
+			RequestBytes:  u.requestBytes,
+			ResponseBytes: u.responseBytes,
+		})
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Router == records[j].Router {
+			return records[i].Service < records[j].Service
+		}
+		return records[i].Router < records[j].Router
+	})
+
+	return records
+}
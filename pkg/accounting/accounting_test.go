@@ -0,0 +1,59 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (n *nopCloser) Close() error {
+	return nil
+}
+
+func TestAccountant_Flush(t *testing.T) {
+	buf := &nopCloser{}
+	accountant := newAccountant(buf, time.Minute)
+
+	accountant.Add("router2", "service", 10, 100)
+	accountant.Add("router1", "service", 5, 50)
+	accountant.Add("router2", "service", 20, 200)
+
+	err := accountant.Flush()
+	require.NoError(t, err)
+
+	decoder := json.NewDecoder(buf)
+
+	var records []Record
+	for decoder.More() {
+		var record Record
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "router1", records[0].Router)
+	assert.Equal(t, "service", records[0].Service)
+	assert.Equal(t, int64(1), records[0].Requests)
+	assert.Equal(t, int64(5), records[0].RequestBytes)
+	assert.Equal(t, int64(50), records[0].ResponseBytes)
+
+	assert.Equal(t, "router2", records[1].Router)
+	assert.Equal(t, int64(2), records[1].Requests)
+	assert.Equal(t, int64(30), records[1].RequestBytes)
+	assert.Equal(t, int64(300), records[1].ResponseBytes)
+	assert.False(t, records[1].End.Before(records[1].Start))
+
+	// Records are reset after a flush.
+	err = accountant.Flush()
+	require.NoError(t, err)
+	assert.Zero(t, buf.Len())
+}
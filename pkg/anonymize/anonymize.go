@@ -39,6 +39,12 @@ func Do(baseConfig interface{}, indent bool) (string, error) {
 	return doOnJSON(string(configJSON)), nil
 }
 
+// DoOnText anonymizes the URLs, the domain names, the IP addresses, and the email addresses of a text, such as an error message.
+func DoOnText(input string) string {
+	mailExp := regexp.MustCompile(`\w[-.\w]*\w@\w[-.\w]*\w\.\w{2,3}`)
+	return xurls.Relaxed().ReplaceAllString(mailExp.ReplaceAllString(input, maskLarge), maskLarge)
+}
+
 func doOnJSON(input string) string {
 	mailExp := regexp.MustCompile(`\w[-.\w]*\w@\w[-.\w]*\w\.\w{2,3}"`)
 	return xurls.Relaxed().ReplaceAllString(mailExp.ReplaceAllString(input, maskLarge+"\""), maskLarge)
@@ -16,6 +16,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/ping"
 	"github.com/traefik/traefik/v2/pkg/plugins"
 	"github.com/traefik/traefik/v2/pkg/provider/acme"
+	"github.com/traefik/traefik/v2/pkg/provider/azure"
 	"github.com/traefik/traefik/v2/pkg/provider/consulcatalog"
 	"github.com/traefik/traefik/v2/pkg/provider/docker"
 	"github.com/traefik/traefik/v2/pkg/provider/ecs"
@@ -33,6 +34,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/provider/rancher"
 	"github.com/traefik/traefik/v2/pkg/provider/rest"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/traefik/traefik/v2/pkg/tracing/datadog"
 	"github.com/traefik/traefik/v2/pkg/tracing/elastic"
 	"github.com/traefik/traefik/v2/pkg/tracing/haystack"
@@ -148,6 +150,7 @@ func TestDo_dynamicConfiguration(t *testing.T) {
 					DialTimeout:           42,
 					ResponseHeaderTimeout: 42,
 					IdleConnTimeout:       42,
+					TLSHandshakeTimeout:   42,
 				},
 			},
 		},
@@ -491,6 +494,7 @@ func TestDo_staticConfiguration(t *testing.T) {
 			DialTimeout:           42,
 			ResponseHeaderTimeout: 42,
 			IdleConnTimeout:       42,
+			TLSHandshakeTimeout:   42,
 		},
 	}
 
@@ -511,6 +515,7 @@ func TestDo_staticConfiguration(t *testing.T) {
 			ProxyProtocol: &static.ProxyProtocol{
 				Insecure:   true,
 				TrustedIPs: []string{"127.0.0.1/32", "192.168.0.1"},
+				Required:   true,
 			},
 			ForwardedHeaders: &static.ForwardedHeaders{
 				Insecure:   true,
@@ -549,6 +554,7 @@ func TestDo_staticConfiguration(t *testing.T) {
 			DialTimeout:           ptypes.Duration(111 * time.Second),
 			ResponseHeaderTimeout: ptypes.Duration(111 * time.Second),
 			IdleConnTimeout:       ptypes.Duration(111 * time.Second),
+			TLSHandshakeTimeout:   ptypes.Duration(111 * time.Second),
 		},
 	}
 
@@ -577,6 +583,18 @@ func TestDo_staticConfiguration(t *testing.T) {
 		Network:                 "MyNetwork",
 		SwarmModeRefreshSeconds: 42,
 		HTTPClientTimeout:       42,
+		Endpoints: []docker.Endpoint{
+			{
+				Name:     "MyEndpointName",
+				Endpoint: "MyEndPoint2",
+				TLS: &types.ClientTLS{
+					CA:   "myCa2",
+					Cert: "mycert2.pem",
+					Key:  "mycert2.key",
+				},
+				Network: "MyNetwork2",
+			},
+		},
 	}
 
 	config.Providers.Marathon = &marathon.Provider{
@@ -587,6 +605,12 @@ func TestDo_staticConfiguration(t *testing.T) {
 		DefaultRule:      "PathPrefix(`/`)",
 		ExposedByDefault: true,
 		DCOSToken:        "foobar",
+		DCOSTokenFile:    "/token",
+		DCOSServiceAccount: &marathon.DCOSServiceAccount{
+			UID:           "traefik",
+			PrivateKey:    "/key.pem",
+			LoginEndpoint: "https://leader.mesos/acs/api/v1/auth/login",
+		},
 		TLS: &types.ClientTLS{
 			CA:                 "myCa",
 			CAOptional:         true,
@@ -600,8 +624,9 @@ func TestDo_staticConfiguration(t *testing.T) {
 		KeepAlive:             42,
 		ForceTaskHostname:     true,
 		Basic: &marathon.Basic{
-			HTTPBasicAuthUser: "user",
-			HTTPBasicPassword: "password",
+			HTTPBasicAuthUser:     "user",
+			HTTPBasicPassword:     "password",
+			HTTPBasicPasswordFile: "/password",
 		},
 		RespectReadinessChecks: true,
 	}
@@ -661,6 +686,7 @@ func TestDo_staticConfiguration(t *testing.T) {
 			Address:    "MyAddress",
 			Scheme:     "MyScheme",
 			DataCenter: "MyDatacenter",
+			Partition:  "MyPartition",
 			Token:      "MyToken",
 			TLS: &types.ClientTLS{
 				CA:                 "myCa",
@@ -682,6 +708,8 @@ func TestDo_staticConfiguration(t *testing.T) {
 		Cache:             true,
 		ExposedByDefault:  true,
 		DefaultRule:       "PathPrefix(`/`)",
+		Datacenters:       []string{"MyDatacenter", "MyDatacenter2"},
+		PreparedQueries:   true,
 	}
 
 	config.Providers.Ecs = &ecs.Provider{
@@ -694,6 +722,34 @@ func TestDo_staticConfiguration(t *testing.T) {
 		Region:               "Awsregion",
 		AccessKeyID:          "AwsAccessKeyID",
 		SecretAccessKey:      "AwsSecretAccessKey",
+		RoleARN:              "AwsRoleARN",
+		ExternalID:           "AwsExternalID",
+		Accounts: []ecs.Account{
+			{
+				Region:               "Awsregion2",
+				RoleARN:              "AwsRoleARN2",
+				ExternalID:           "AwsExternalID2",
+				Clusters:             []string{"Cluster3"},
+				AutoDiscoverClusters: true,
+			},
+		},
+	}
+
+	config.Providers.Azure = &azure.Provider{
+		Constraints:      `Label("foo", "bar")`,
+		ExposedByDefault: true,
+		RefreshSeconds:   42,
+		DefaultRule:      "PathPrefix(`/`)",
+		StrictLabels:     true,
+		SubscriptionID:   "AzureSubscriptionID",
+		ResourceGroups:   []string{"ResourceGroup1", "ResourceGroup2"},
+		ContainerApps:    true,
+		ContainerGroups:  true,
+		TenantID:         "AzureTenantID",
+		ClientID:         "AzureClientID",
+		ClientSecret:     "AzureClientSecret",
+		Endpoint:         "AzureEndpoint",
+		AuthorityHost:    "AzureAuthorityHost",
 	}
 
 	config.Providers.Consul = &consul.Provider{
@@ -709,6 +765,8 @@ func TestDo_staticConfiguration(t *testing.T) {
 				Key:                "mycert.key",
 				InsecureSkipVerify: true,
 			},
+			WatchQuietPeriod: ptypes.Duration(111 * time.Second),
+			WatchJitter:      ptypes.Duration(111 * time.Second),
 		},
 	}
 
@@ -725,6 +783,8 @@ func TestDo_staticConfiguration(t *testing.T) {
 				Key:                "mycert.key",
 				InsecureSkipVerify: true,
 			},
+			WatchQuietPeriod: ptypes.Duration(111 * time.Second),
+			WatchJitter:      ptypes.Duration(111 * time.Second),
 		},
 	}
 
@@ -741,6 +801,8 @@ func TestDo_staticConfiguration(t *testing.T) {
 				Key:                "mycert.key",
 				InsecureSkipVerify: true,
 			},
+			WatchQuietPeriod: ptypes.Duration(111 * time.Second),
+			WatchJitter:      ptypes.Duration(111 * time.Second),
 		},
 	}
 
@@ -757,6 +819,8 @@ func TestDo_staticConfiguration(t *testing.T) {
 				Key:                "mycert.key",
 				InsecureSkipVerify: true,
 			},
+			WatchQuietPeriod: ptypes.Duration(111 * time.Second),
+			WatchJitter:      ptypes.Duration(111 * time.Second),
 		},
 	}
 
@@ -823,6 +887,15 @@ func TestDo_staticConfiguration(t *testing.T) {
 			AddEntryPointsLabels: true,
 			AddServicesLabels:    true,
 		},
+		InfluxDB2: &types.InfluxDB2{
+			Address:              "http://localhost:8086",
+			Token:                "secret",
+			PushInterval:         42,
+			Org:                  "myOrg",
+			Bucket:               "myBucket",
+			AddEntryPointsLabels: true,
+			AddServicesLabels:    true,
+		},
 	}
 
 	config.Ping = &ping.Handler{
@@ -858,6 +931,7 @@ func TestDo_staticConfiguration(t *testing.T) {
 			},
 		},
 		BufferingSize: 42,
+		Connections:   true,
 	}
 
 	config.Tracing = &static.Tracing{
@@ -913,6 +987,11 @@ func TestDo_staticConfiguration(t *testing.T) {
 			SecretToken:        "foobar",
 			ServiceEnvironment: "foobar",
 		},
+		SpanAttributes: &tracing.SpanAttributes{
+			Static:          map[string]string{"foobar": "foobar"},
+			CaptureHeaders:  true,
+			RedactedHeaders: []string{"foobar"},
+		},
 	}
 
 	config.HostResolver = &types.HostResolverConfig{
@@ -924,11 +1003,14 @@ func TestDo_staticConfiguration(t *testing.T) {
 	config.CertificatesResolvers = map[string]static.CertificateResolver{
 		"CertificateResolver0": {
 			ACME: &acme.Configuration{
-				Email:          "acme Email",
-				CAServer:       "CAServer",
-				PreferredChain: "foobar",
-				Storage:        "Storage",
-				KeyType:        "MyKeyType",
+				Email:            "acme Email",
+				CAServer:         "CAServer",
+				PreferredChain:   "foobar",
+				Storage:          "Storage",
+				KeyType:          "MyKeyType",
+				CACertificates:   []string{"ca1.pem", "ca2.pem"},
+				CASystemCertPool: true,
+				CAServerName:     "CAServerName",
 				DNSChallenge: &acme.DNSChallenge{
 					Provider:                "DNSProvider",
 					DelayBeforeCheck:        42,
@@ -63,3 +63,34 @@ func Test_doOnJSON_simple(t *testing.T) {
 		})
 	}
 }
+
+func TestDoOnText(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		expectedOutput string
+	}{
+		{
+			name:           "email",
+			input:          `unable to register account for foo.bar@example.com`,
+			expectedOutput: `unable to register account for xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx`,
+		},
+		{
+			name:           "url",
+			input:          `unable to reach http://10.0.0.1:80/health: connection refused`,
+			expectedOutput: `unable to reach xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx: connection refused`,
+		},
+		{
+			name:           "domain",
+			input:          `no certificate found for sub.domain.com`,
+			expectedOutput: `no certificate found for xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			output := DoOnText(test.input)
+			assert.Equal(t, test.expectedOutput, output)
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/version"
 )
 
@@ -31,7 +32,8 @@ func writeError(rw http.ResponseWriter, msg string, code int) {
 
 type serviceInfoRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
+	ServerStatus map[string]string   `json:"serverStatus,omitempty"`
+	Ramp         *runtime.RampStatus `json:"ramp,omitempty"`
 }
 
 // RunTimeRepresentation is the configuration information exposed by the API handler.
@@ -44,6 +46,9 @@ type RunTimeRepresentation struct {
 	TCPServices    map[string]*runtime.TCPServiceInfo    `json:"tcpServices,omitempty"`
 	UDPRouters     map[string]*runtime.UDPRouterInfo     `json:"udpRouters,omitempty"`
 	UDPServices    map[string]*runtime.UDPServiceInfo    `json:"udpServices,omitempty"`
+	Conflicts      []*runtime.ConflictInfo               `json:"conflicts,omitempty"`
+	LabelErrors    []*runtime.LabelErrorInfo             `json:"labelErrors,omitempty"`
+	RejectedReload *runtime.RejectedReload               `json:"rejectedReload,omitempty"`
 }
 
 // Handler serves the configuration and status of Traefik on API endpoints.
@@ -53,14 +58,45 @@ type Handler struct {
 	staticConfig    static.Configuration
 	dashboardAssets *assetfs.AssetFS
 
+	// accountKeyRotators are the certificate resolvers whose ACME account keys can be rotated through the API, by name.
+	accountKeyRotators map[string]AccountKeyRotator
+
+	// configurationHistory gives access to the last applied dynamic configurations, if any.
+	configurationHistory ConfigurationHistory
+
+	// staticReloader reloads the static configuration, if it can be reloaded through the API.
+	staticReloader StaticConfigurationReloader
+
+	// cluster gives access to the state of the instances of the cluster, if the cluster mode is enabled.
+	cluster ClusterMembership
+
+	// trafficStatistics gives access to the live traffic of the entry points and of the routers.
+	trafficStatistics TrafficStatistics
+
+	// providersHealth returns the liveness of the providers.
+	providersHealth func() map[string]provider.Health
+
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+func NewBuilder(staticConfig static.Configuration, accountKeyRotators map[string]AccountKeyRotator, configurationHistory ConfigurationHistory, staticReloader StaticConfigurationReloader, cluster ClusterMembership, trafficStatistics TrafficStatistics) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		conf := staticConfig
+		if staticReloader != nil {
+			// The static configuration may have been reloaded since the builder was created.
+			conf = staticReloader.StaticConfiguration()
+		}
+
+		handler := New(conf, configuration)
+		handler.accountKeyRotators = accountKeyRotators
+		handler.configurationHistory = configurationHistory
+		handler.staticReloader = staticReloader
+		handler.cluster = cluster
+		handler.trafficStatistics = trafficStatistics
+
+		return handler.createRouter()
 	}
 }
 
@@ -78,6 +114,7 @@ func New(staticConfig static.Configuration, runtimeConfig *runtime.Configuration
 		runtimeConfiguration: rConfig,
 		staticConfig:         staticConfig,
 		debug:                staticConfig.API.Debug,
+		providersHealth:      provider.ProvidersHealth,
 	}
 }
 
@@ -87,20 +124,35 @@ func (h Handler) createRouter() *mux.Router {
 
 	if h.debug {
 		DebugHandler{}.Append(router)
+
+		// The decisions evaluate synthetic requests against the routers, and are only exposed for debugging.
+		router.Methods(http.MethodPost).Path("/api/http/routers/{routerID}/decision").HandlerFunc(h.getDecision)
 	}
 
 	router.Methods(http.MethodGet).Path("/api/rawdata").HandlerFunc(h.getRuntimeConfiguration)
 
+	if h.configurationHistory != nil {
+		router.Methods(http.MethodGet).Path("/api/rawdata/history").HandlerFunc(h.getConfigurationHistory)
+		router.Methods(http.MethodGet).Path("/api/rawdata/diff").HandlerFunc(h.getConfigurationDiff)
+	}
+
 	// Experimental endpoint
 	router.Methods(http.MethodGet).Path("/api/overview").HandlerFunc(h.getOverview)
+	router.Methods(http.MethodGet).Path("/api/overview/health").HandlerFunc(h.getHealthOverview)
 
 	router.Methods(http.MethodGet).Path("/api/entrypoints").HandlerFunc(h.getEntryPoints)
 	router.Methods(http.MethodGet).Path("/api/entrypoints/{entryPointID}").HandlerFunc(h.getEntryPoint)
+	router.Methods(http.MethodGet).Path("/api/entrypoints/{entryPointID}/routers").HandlerFunc(h.getEntryPointRouters)
+
+	if h.trafficStatistics != nil {
+		router.Methods(http.MethodGet).Path("/api/entrypoints/{entryPointID}/stats").HandlerFunc(h.getEntryPointStats)
+	}
 
 	router.Methods(http.MethodGet).Path("/api/http/routers").HandlerFunc(h.getRouters)
 	router.Methods(http.MethodGet).Path("/api/http/routers/{routerID}").HandlerFunc(h.getRouter)
 	router.Methods(http.MethodGet).Path("/api/http/services").HandlerFunc(h.getServices)
 	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}").HandlerFunc(h.getService)
+	router.Methods(http.MethodGet).Path("/api/http/services/{serviceID}/healthchecks").HandlerFunc(h.getServiceHealthChecks)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares").HandlerFunc(h.getMiddlewares)
 	router.Methods(http.MethodGet).Path("/api/http/middlewares/{middlewareID}").HandlerFunc(h.getMiddleware)
 
@@ -116,6 +168,24 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	router.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
 
+	router.Methods(http.MethodGet).Path("/api/conflicts").HandlerFunc(h.getConflicts)
+	router.Methods(http.MethodGet).Path("/api/labelerrors").HandlerFunc(h.getLabelErrors)
+
+	router.Methods(http.MethodGet).Path("/api/providers").HandlerFunc(h.getProviders)
+	router.Methods(http.MethodGet).Path("/api/providers/{providerID}/labelerrors").HandlerFunc(h.getLabelErrors)
+
+	if len(h.accountKeyRotators) > 0 {
+		router.Methods(http.MethodPost).Path("/api/acme/{resolverID}/rotatekeys").HandlerFunc(h.rotateAccountKeys)
+	}
+
+	if h.staticReloader != nil {
+		router.Methods(http.MethodPost).Path("/api/reload").HandlerFunc(h.reloadStaticConfiguration)
+	}
+
+	if h.cluster != nil {
+		router.Methods(http.MethodGet).Path("/api/cluster").HandlerFunc(h.getCluster)
+	}
+
 	version.Handler{}.Append(router)
 
 	if h.dashboard {
@@ -126,11 +196,23 @@ func (h Handler) createRouter() *mux.Router {
 }
 
 func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.Request) {
+	format, err := rawDataFormat(request)
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if format != formatJSON {
+		h.exportRuntimeConfiguration(rw, request, format)
+		return
+	}
+
 	siRepr := make(map[string]*serviceInfoRepresentation, len(h.runtimeConfiguration.Services))
 	for k, v := range h.runtimeConfiguration.Services {
 		siRepr[k] = &serviceInfoRepresentation{
 			ServiceInfo:  v,
 			ServerStatus: v.GetAllStatus(),
+			Ramp:         v.GetRampStatus(),
 		}
 	}
 
@@ -143,11 +225,14 @@ func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.R
 		TCPServices:    h.runtimeConfiguration.TCPServices,
 		UDPRouters:     h.runtimeConfiguration.UDPRouters,
 		UDPServices:    h.runtimeConfiguration.UDPServices,
+		Conflicts:      h.runtimeConfiguration.Conflicts,
+		LabelErrors:    h.runtimeConfiguration.LabelErrors,
+		RejectedReload: h.runtimeConfiguration.RejectedReload,
 	}
 
 	rw.Header().Set("Content-Type", "application/json")
 
-	err := json.NewEncoder(rw).Encode(result)
+	err = json.NewEncoder(rw).Encode(result)
 	if err != nil {
 		log.FromContext(request.Context()).Error(err)
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
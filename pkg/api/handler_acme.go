@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// AccountKeyRotator is implemented by the certificate resolvers able to rotate the keys of their ACME accounts.
+type AccountKeyRotator interface {
+	RotateAccountKeys(ctx context.Context) error
+}
+
+func (h Handler) rotateAccountKeys(rw http.ResponseWriter, request *http.Request) {
+	resolverID := mux.Vars(request)["resolverID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	rotator, ok := h.accountKeyRotators[resolverID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("certificate resolver not found: %s", resolverID), http.StatusNotFound)
+		return
+	}
+
+	err := rotator.RotateAccountKeys(request.Context())
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
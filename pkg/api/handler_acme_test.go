@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+type accountKeyRotatorFunc func(ctx context.Context) error
+
+func (f accountKeyRotatorFunc) RotateAccountKeys(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestHandler_RotateAccountKeys(t *testing.T) {
+	var rotated []string
+	rotators := map[string]AccountKeyRotator{
+		"foo": accountKeyRotatorFunc(func(_ context.Context) error {
+			rotated = append(rotated, "foo")
+			return nil
+		}),
+		"bar": accountKeyRotatorFunc(func(_ context.Context) error {
+			return errors.New("rejected")
+		}),
+	}
+
+	testCases := []struct {
+		desc       string
+		method     string
+		path       string
+		rotators   map[string]AccountKeyRotator
+		statusCode int
+	}{
+		{
+			desc:       "rotated",
+			method:     http.MethodPost,
+			path:       "/api/acme/foo/rotatekeys",
+			rotators:   rotators,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			desc:       "rotation error",
+			method:     http.MethodPost,
+			path:       "/api/acme/bar/rotatekeys",
+			rotators:   rotators,
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			desc:       "unknown resolver",
+			method:     http.MethodPost,
+			path:       "/api/acme/baz/rotatekeys",
+			rotators:   rotators,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "wrong method",
+			method:     http.MethodGet,
+			path:       "/api/acme/foo/rotatekeys",
+			rotators:   rotators,
+			statusCode: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:       "no resolvers",
+			method:     http.MethodPost,
+			path:       "/api/acme/foo/rotatekeys",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			staticConfig := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+			handler := NewBuilder(staticConfig, test.rotators, nil, nil, nil, nil)(&runtime.Configuration{})
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+
+			assert.Equal(t, test.statusCode, rec.Code)
+		})
+	}
+
+	assert.Equal(t, []string{"foo"}, rotated)
+}
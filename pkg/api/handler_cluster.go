@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/cluster"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// ClusterMembership gives access to the state of the instances of the cluster.
+type ClusterMembership interface {
+	// Name returns the name of this instance in the cluster.
+	Name() string
+	// Nodes returns the state published by the live instances of the cluster.
+	Nodes() ([]cluster.NodeInfo, error)
+}
+
+type clusterRepresentation struct {
+	Name   string             `json:"name"`
+	Leader string             `json:"leader,omitempty"`
+	Nodes  []cluster.NodeInfo `json:"nodes"`
+}
+
+func (h Handler) getCluster(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	nodes, err := h.cluster.Nodes()
+	if err != nil {
+		log.FromContext(request.Context()).Errorf("Unable to read the state of the cluster: %v", err)
+		writeError(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	result := clusterRepresentation{
+		Name:  h.cluster.Name(),
+		Nodes: nodes,
+	}
+
+	for _, node := range nodes {
+		if node.Leader {
+			result.Leader = node.Name
+			break
+		}
+	}
+
+	err = json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
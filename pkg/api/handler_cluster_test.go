@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/cluster"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+type clusterMembershipMock struct {
+	nodes []cluster.NodeInfo
+	err   error
+}
+
+func (c clusterMembershipMock) Name() string {
+	return "node2"
+}
+
+func (c clusterMembershipMock) Nodes() ([]cluster.NodeInfo, error) {
+	return c.nodes, c.err
+}
+
+func TestHandler_Cluster(t *testing.T) {
+	date := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	nodes := []cluster.NodeInfo{
+		{
+			Name:      "node1",
+			Version:   "2.4.0",
+			Leader:    true,
+			StartedAt: date,
+			LastSeen:  date.Add(time.Hour),
+			Runtime: &cluster.RuntimeInfo{
+				HTTP: cluster.SchemeInfo{Routers: cluster.Counters{Total: 2, Errors: 1}},
+			},
+		},
+		{
+			Name:      "node2",
+			Version:   "2.4.0",
+			StartedAt: date,
+			LastSeen:  date.Add(time.Hour),
+		},
+	}
+
+	testCases := []struct {
+		desc       string
+		cluster    ClusterMembership
+		statusCode int
+		expected   string
+	}{
+		{
+			desc:       "cluster mode disabled",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "nodes",
+			cluster:    clusterMembershipMock{nodes: nodes},
+			statusCode: http.StatusOK,
+			expected: `{
+				"name": "node2",
+				"leader": "node1",
+				"nodes": [
+					{
+						"name": "node1",
+						"version": "2.4.0",
+						"leader": true,
+						"startedAt": "2021-03-01T10:00:00Z",
+						"lastSeen": "2021-03-01T11:00:00Z",
+						"runtime": {
+							"http": {"routers": {"total": 2, "warnings": 0, "errors": 1}, "services": {"total": 0, "warnings": 0, "errors": 0}, "middlewares": {"total": 0, "warnings": 0, "errors": 0}},
+							"tcp": {"routers": {"total": 0, "warnings": 0, "errors": 0}, "services": {"total": 0, "warnings": 0, "errors": 0}, "middlewares": {"total": 0, "warnings": 0, "errors": 0}},
+							"udp": {"routers": {"total": 0, "warnings": 0, "errors": 0}, "services": {"total": 0, "warnings": 0, "errors": 0}, "middlewares": {"total": 0, "warnings": 0, "errors": 0}}
+						}
+					},
+					{
+						"name": "node2",
+						"version": "2.4.0",
+						"leader": false,
+						"startedAt": "2021-03-01T10:00:00Z",
+						"lastSeen": "2021-03-01T11:00:00Z"
+					}
+				]
+			}`,
+		},
+		{
+			desc:       "no leader",
+			cluster:    clusterMembershipMock{nodes: nodes[1:]},
+			statusCode: http.StatusOK,
+			expected: `{
+				"name": "node2",
+				"nodes": [
+					{
+						"name": "node2",
+						"version": "2.4.0",
+						"leader": false,
+						"startedAt": "2021-03-01T10:00:00Z",
+						"lastSeen": "2021-03-01T11:00:00Z"
+					}
+				]
+			}`,
+		},
+		{
+			desc:       "KV store unavailable",
+			cluster:    clusterMembershipMock{err: errors.New("connection refused")},
+			statusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			staticConfig := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+			handler := NewBuilder(staticConfig, nil, nil, nil, test.cluster, nil)(&runtime.Configuration{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/cluster", nil)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			require.Equal(t, test.statusCode, rw.Code)
+
+			if test.expected == "" {
+				return
+			}
+
+			body, err := io.ReadAll(rw.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(body))
+		})
+	}
+}
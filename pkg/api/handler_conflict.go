@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+type conflictRepresentation struct {
+	*runtime.ConflictInfo
+	Provider string `json:"provider,omitempty"`
+}
+
+func newConflictRepresentation(ci *runtime.ConflictInfo) conflictRepresentation {
+	return conflictRepresentation{
+		ConflictInfo: ci,
+		Provider:     getProviderName(ci.Name),
+	}
+}
+
+func (h Handler) getConflicts(rw http.ResponseWriter, request *http.Request) {
+	results := make([]conflictRepresentation, 0, len(h.runtimeConfiguration.Conflicts))
+
+	criterion := newSearchCriterion(request.URL.Query())
+
+	for _, ci := range h.runtimeConfiguration.Conflicts {
+		if keepConflict(ci, criterion) {
+			results = append(results, newConflictRepresentation(ci))
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Name == results[j].Name {
+			return results[i].Kind < results[j].Kind
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func keepConflict(item *runtime.ConflictInfo, criterion *searchCriterion) bool {
+	if criterion == nil {
+		return true
+	}
+
+	return criterion.withStatus(item.Status) && criterion.searchIn(append([]string{item.Name, item.Kind}, item.Sources...)...)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestHandler_Conflicts(t *testing.T) {
+	type expected struct {
+		statusCode int
+		nextPage   string
+		jsonFile   string
+	}
+
+	conflicts := []*runtime.ConflictInfo{
+		{
+			Conflict: dynamic.Conflict{Kind: "service", Name: "foo@docker", Sources: []string{"container-1", "container-2"}},
+			Status:   runtime.StatusWarning,
+		},
+		{
+			Conflict: dynamic.Conflict{Kind: "router", Name: "foo@docker", Sources: []string{"container-1", "container-2"}},
+			Status:   runtime.StatusWarning,
+		},
+		{
+			Conflict: dynamic.Conflict{Kind: "tcpRouter", Name: "bar@marathon", Sources: []string{"/app", "/app2"}},
+			Status:   runtime.StatusWarning,
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		conf     runtime.Configuration
+		expected expected
+	}{
+		{
+			desc: "all conflicts, but no config",
+			path: "/api/conflicts",
+			conf: runtime.Configuration{},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/conflicts-empty.json",
+			},
+		},
+		{
+			desc: "all conflicts",
+			path: "/api/conflicts",
+			conf: runtime.Configuration{Conflicts: conflicts},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/conflicts.json",
+			},
+		},
+		{
+			desc: "conflicts filtered by search",
+			path: "/api/conflicts?search=container-1",
+			conf: runtime.Configuration{Conflicts: conflicts},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/conflicts-filtered-search.json",
+			},
+		},
+		{
+			desc: "all conflicts, pagination, 1 res per page, want page 2",
+			path: "/api/conflicts?page=2&per_page=1",
+			conf: runtime.Configuration{Conflicts: conflicts},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "3",
+				jsonFile:   "testdata/conflicts-page2.json",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &test.conf)
+			server := httptest.NewServer(handler.createRouter())
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected.nextPage, resp.Header.Get(nextPageHeader))
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = os.WriteFile(test.expected.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
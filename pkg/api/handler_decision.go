@@ -0,0 +1,444 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/traefik/v2/pkg/server/cookie"
+	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/vulcand/oxy/roundrobin/stickycookie"
+)
+
+const (
+	serverStatusUp      = "UP"
+	serverStatusDown    = "DOWN"
+	serverStatusUnknown = "UNKNOWN"
+)
+
+// decisionRequest is the description of the synthetic request to explain the load-balancing decision for.
+type decisionRequest struct {
+	Method   string            `json:"method,omitempty"`
+	Host     string            `json:"host,omitempty"`
+	Path     string            `json:"path,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Cookies  map[string]string `json:"cookies,omitempty"`
+	ClientIP string            `json:"clientIP,omitempty"`
+}
+
+func (d decisionRequest) build() (*http.Request, error) {
+	method := d.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	host := d.Host
+	if host == "" {
+		host = "localhost"
+	}
+
+	path := d.Path
+	if path == "" {
+		path = "/"
+	}
+
+	u, err := url.Parse("http://" + host + path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	req := httptest.NewRequest(method, u.String(), nil)
+
+	for name, value := range d.Headers {
+		req.Header.Set(name, value)
+	}
+
+	for name, value := range d.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	if d.ClientIP != "" {
+		if net.ParseIP(d.ClientIP) == nil {
+			return nil, fmt.Errorf("invalid client IP: %s", d.ClientIP)
+		}
+		req.RemoteAddr = net.JoinHostPort(d.ClientIP, "1234")
+	}
+
+	return req, nil
+}
+
+// decisionRepresentation explains which service and which server a router would forward a request to.
+// Service and Server are the last service and server which could be determined.
+type decisionRepresentation struct {
+	Router      string         `json:"router"`
+	RuleMatched bool           `json:"ruleMatched"`
+	Service     string         `json:"service,omitempty"`
+	Server      string         `json:"server,omitempty"`
+	Steps       []decisionStep `json:"steps,omitempty"`
+}
+
+// decisionStep explains the choice made by a service between its children services or servers.
+type decisionStep struct {
+	Service    string              `json:"service"`
+	Type       string              `json:"type,omitempty"`
+	Selected   string              `json:"selected,omitempty"`
+	Reason     string              `json:"reason"`
+	Candidates []decisionCandidate `json:"candidates,omitempty"`
+}
+
+type decisionCandidate struct {
+	Name     string `json:"name"`
+	Weight   int    `json:"weight,omitempty"`
+	Status   string `json:"status"`
+	Excluded bool   `json:"excluded,omitempty"`
+}
+
+func (h Handler) getDecision(rw http.ResponseWriter, request *http.Request) {
+	routerID := mux.Vars(request)["routerID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	router, ok := h.runtimeConfiguration.Routers[routerID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("router not found: %s", routerID), http.StatusNotFound)
+		return
+	}
+
+	var description decisionRequest
+	err := json.NewDecoder(request.Body).Decode(&description)
+	if err != nil && !errors.Is(err, io.EOF) {
+		writeError(rw, fmt.Sprintf("invalid request description: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	req, err := description.build()
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ruleMatched, err := matchRule(router.Rule, req)
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := decisionRepresentation{
+		Router:      routerID,
+		RuleMatched: ruleMatched,
+	}
+
+	ctx := provider.AddInContext(request.Context(), routerID)
+	h.explainService(ctx, provider.GetQualifiedName(ctx, router.Service), req, &result, make(map[string]struct{}))
+
+	err = json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// matchRule returns whether the request matches the rule.
+func matchRule(rule string, req *http.Request) (bool, error) {
+	router, err := rules.NewRouter()
+	if err != nil {
+		return false, err
+	}
+
+	var matched bool
+	err = router.AddRoute(rule, 0, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		matched = true
+	}))
+	if err != nil {
+		return false, err
+	}
+
+	// The Host matcher relies on the canonized host set by the request decorator.
+	requestdecorator.New(nil).ServeHTTP(httptest.NewRecorder(), req, router.ServeHTTP)
+
+	return matched, nil
+}
+
+func (h Handler) explainService(ctx context.Context, serviceName string, req *http.Request, result *decisionRepresentation, visited map[string]struct{}) {
+	result.Service = serviceName
+
+	if _, ok := visited[serviceName]; ok {
+		result.Steps = append(result.Steps, decisionStep{Service: serviceName, Reason: "service already visited, the services form a loop"})
+		return
+	}
+	visited[serviceName] = struct{}{}
+
+	si, ok := h.runtimeConfiguration.Services[serviceName]
+	if !ok || si.Service == nil {
+		result.Steps = append(result.Steps, decisionStep{Service: serviceName, Reason: "service not found"})
+		return
+	}
+
+	ctx = provider.AddInContext(ctx, serviceName)
+
+	switch {
+	case si.LoadBalancer != nil:
+		step := explainLoadBalancer(serviceName, si, req)
+		result.Steps = append(result.Steps, step)
+		result.Server = step.Selected
+
+	case si.Weighted != nil:
+		step := h.explainWeighted(ctx, serviceName, si.Weighted, req)
+		result.Steps = append(result.Steps, step)
+		if step.Selected != "" {
+			h.explainService(ctx, provider.GetQualifiedName(ctx, step.Selected), req, result, visited)
+		}
+
+	case si.Mirroring != nil:
+		step := decisionStep{
+			Service:  serviceName,
+			Type:     "mirroring",
+			Selected: si.Mirroring.Service,
+			Reason:   "the request is forwarded to the main service, and mirrored to the mirrors",
+		}
+		for _, mirror := range si.Mirroring.Mirrors {
+			step.Candidates = append(step.Candidates, decisionCandidate{
+				Name:   mirror.Name,
+				Weight: mirror.Percent,
+				Status: h.serviceStatus(ctx, provider.GetQualifiedName(ctx, mirror.Name), make(map[string]struct{})),
+			})
+		}
+		result.Steps = append(result.Steps, step)
+		h.explainService(ctx, provider.GetQualifiedName(ctx, si.Mirroring.Service), req, result, visited)
+
+	case si.Failover != nil:
+		step := h.explainFailover(ctx, serviceName, si.Failover)
+		result.Steps = append(result.Steps, step)
+		if step.Selected != "" {
+			h.explainService(ctx, provider.GetQualifiedName(ctx, step.Selected), req, result, visited)
+		}
+
+	default:
+		result.Steps = append(result.Steps, decisionStep{Service: serviceName, Reason: "service without any type defined"})
+	}
+}
+
+// explainFailover explains the choice of a failover between its main service and its fallback service,
+// which is only selected when the main service is down, and, if its status is required, when it is not down.
+func (h Handler) explainFailover(ctx context.Context, serviceName string, config *dynamic.Failover) decisionStep {
+	step := decisionStep{Service: serviceName, Type: "failover"}
+
+	ctx = provider.AddInContext(ctx, serviceName)
+
+	main := decisionCandidate{
+		Name:   config.Service,
+		Status: h.serviceStatus(ctx, provider.GetQualifiedName(ctx, config.Service), make(map[string]struct{})),
+	}
+	fallback := decisionCandidate{
+		Name:   config.Fallback,
+		Status: h.serviceStatus(ctx, provider.GetQualifiedName(ctx, config.Fallback), make(map[string]struct{})),
+	}
+
+	switch {
+	case main.Status != serverStatusDown:
+		fallback.Excluded = true
+		step.Selected = main.Name
+		step.Reason = "the main service is not down"
+	case fallback.Status == serverStatusDown && (config.RequireHealthyFallback || config.HealthCheck != nil):
+		main.Excluded = true
+		fallback.Excluded = true
+		step.Reason = "the main and the fallback services are down"
+	default:
+		main.Excluded = true
+		step.Selected = fallback.Name
+		step.Reason = "the main service is down"
+	}
+
+	step.Candidates = []decisionCandidate{main, fallback}
+
+	return step
+}
+
+// explainLoadBalancer explains the choice of a server by a load-balancer, which excludes the servers down,
+// and honors the sticky cookie when it designates an available server.
+func explainLoadBalancer(serviceName string, si *runtime.ServiceInfo, req *http.Request) decisionStep {
+	step := decisionStep{Service: serviceName, Type: "loadbalancer"}
+
+	statuses := si.GetAllStatus()
+
+	var available []*url.URL
+	for _, server := range si.LoadBalancer.Servers {
+		candidate := decisionCandidate{Name: server.URL, Status: serverStatusUnknown}
+
+		if status, ok := statuses[server.URL]; ok {
+			candidate.Status = status
+		}
+
+		u, err := url.Parse(server.URL)
+		if err != nil || candidate.Status == serverStatusDown {
+			candidate.Excluded = true
+		} else {
+			available = append(available, u)
+		}
+
+		step.Candidates = append(step.Candidates, candidate)
+	}
+
+	if len(available) == 0 {
+		step.Reason = "no available server"
+		return step
+	}
+
+	if si.LoadBalancer.Sticky != nil && si.LoadBalancer.Sticky.Cookie != nil {
+		cookieName := cookie.GetName(si.LoadBalancer.Sticky.Cookie.Name, serviceName)
+
+		if c, err := req.Cookie(cookieName); err == nil {
+			// The values the load-balancer accepts for its sticky cookie.
+			cv, err := stickycookie.NewFallbackValue(&stickycookie.RawValue{}, &stickycookie.HashValue{})
+			if err == nil {
+				if u, _ := cv.FindURL(c.Value, available); u != nil {
+					step.Selected = u.String()
+					step.Reason = fmt.Sprintf("sticky cookie %q honored", cookieName)
+					return step
+				}
+			}
+
+			step.Reason = fmt.Sprintf("sticky cookie %q ignored, as it does not designate an available server; ", cookieName)
+		}
+	}
+
+	if len(available) == 1 {
+		step.Selected = available[0].String()
+		step.Reason += "only available server"
+		return step
+	}
+
+	step.Reason += fmt.Sprintf("round robin between %d available servers", len(available))
+
+	return step
+}
+
+// explainWeighted explains the choice of a service by a weighted round robin, which excludes the services with a non-positive weight,
+// and, with the health check enabled, the services down. It honors the sticky cookie when it designates an available service.
+func (h Handler) explainWeighted(ctx context.Context, serviceName string, config *dynamic.WeightedRoundRobin, req *http.Request) decisionStep {
+	step := decisionStep{Service: serviceName, Type: "weighted"}
+
+	var available []string
+	for _, service := range config.Services {
+		candidate := decisionCandidate{
+			Name:   service.Name,
+			Weight: 1,
+			Status: h.serviceStatus(ctx, provider.GetQualifiedName(ctx, service.Name), make(map[string]struct{})),
+		}
+		if service.Weight != nil {
+			candidate.Weight = *service.Weight
+		}
+
+		// The status of the children is only taken into account with the health check enabled.
+		candidate.Excluded = candidate.Weight <= 0 || config.HealthCheck != nil && candidate.Status == serverStatusDown
+		if !candidate.Excluded {
+			available = append(available, service.Name)
+		}
+
+		step.Candidates = append(step.Candidates, candidate)
+	}
+
+	if len(available) == 0 {
+		step.Reason = "no available service"
+		return step
+	}
+
+	if config.Sticky != nil && config.Sticky.Cookie != nil {
+		cookieName := cookie.GetName(config.Sticky.Cookie.Name, serviceName)
+
+		if c, err := req.Cookie(cookieName); err == nil {
+			for _, name := range available {
+				if name == c.Value {
+					step.Selected = name
+					step.Reason = fmt.Sprintf("sticky cookie %q honored", cookieName)
+					return step
+				}
+			}
+
+			step.Reason = fmt.Sprintf("sticky cookie %q ignored, as it does not designate an available service; ", cookieName)
+		}
+	}
+
+	if len(available) == 1 {
+		step.Selected = available[0]
+		step.Reason += "only available service"
+		return step
+	}
+
+	step.Reason += fmt.Sprintf("weighted round robin between %d available services", len(available))
+
+	return step
+}
+
+// serviceStatus returns the status of a service, as propagated to a parent weighted round robin with the health check enabled.
+func (h Handler) serviceStatus(ctx context.Context, serviceName string, visited map[string]struct{}) string {
+	if _, ok := visited[serviceName]; ok {
+		return serverStatusUnknown
+	}
+	visited[serviceName] = struct{}{}
+	defer delete(visited, serviceName)
+
+	si, ok := h.runtimeConfiguration.Services[serviceName]
+	if !ok || si.Service == nil {
+		return serverStatusDown
+	}
+
+	ctx = provider.AddInContext(ctx, serviceName)
+
+	switch {
+	case si.LoadBalancer != nil:
+		statuses := si.GetAllStatus()
+		if len(statuses) == 0 {
+			return serverStatusUnknown
+		}
+
+		for _, status := range statuses {
+			if status == serverStatusUp {
+				return serverStatusUp
+			}
+		}
+		return serverStatusDown
+
+	case si.Weighted != nil:
+		status := serverStatusDown
+		for _, service := range si.Weighted.Services {
+			switch h.serviceStatus(ctx, provider.GetQualifiedName(ctx, service.Name), visited) {
+			case serverStatusUp:
+				return serverStatusUp
+			case serverStatusUnknown:
+				status = serverStatusUnknown
+			}
+		}
+		return status
+
+	case si.Mirroring != nil:
+		return h.serviceStatus(ctx, provider.GetQualifiedName(ctx, si.Mirroring.Service), visited)
+
+	case si.Failover != nil:
+		status := serverStatusDown
+		for _, name := range []string{si.Failover.Service, si.Failover.Fallback} {
+			switch h.serviceStatus(ctx, provider.GetQualifiedName(ctx, name), visited) {
+			case serverStatusUp:
+				return serverStatusUp
+			case serverStatusUnknown:
+				status = serverStatusUnknown
+			}
+		}
+		return status
+
+	default:
+		return serverStatusUnknown
+	}
+}
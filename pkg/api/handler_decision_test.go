@@ -0,0 +1,260 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestHandler_Decision(t *testing.T) {
+	type expected struct {
+		statusCode int
+		jsonFile   string
+	}
+
+	newLoadBalancer := func(sticky *dynamic.Sticky, statuses map[string]string) *runtime.ServiceInfo {
+		si := &runtime.ServiceInfo{
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					Sticky: sticky,
+					Servers: []dynamic.Server{
+						{URL: "http://10.0.0.1:80"},
+						{URL: "http://10.0.0.2:80"},
+					},
+				},
+			},
+		}
+		for server, status := range statuses {
+			si.UpdateServerStatus(server, status)
+		}
+		return si
+	}
+
+	allUp := map[string]string{"http://10.0.0.1:80": "UP", "http://10.0.0.2:80": "UP"}
+
+	router := &runtime.RouterInfo{
+		Router: &dynamic.Router{
+			EntryPoints: []string{"web"},
+			Service:     "wrr",
+			Rule:        "Host(`foo.bar`)",
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		body     string
+		conf     runtime.Configuration
+		expected expected
+	}{
+		{
+			desc: "router not found",
+			path: "/api/http/routers/foo@myprovider/decision",
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			desc: "invalid client IP",
+			path: "/api/http/routers/foo@myprovider/decision",
+			body: `{"clientIP": "foo"}`,
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{"foo@myprovider": router},
+			},
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			desc: "load-balancer sticky cookie honored",
+			path: "/api/http/routers/foo@myprovider/decision",
+			body: `{"host": "foo.bar", "cookies": {"lb": "http://10.0.0.2:80"}}`,
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"foo@myprovider": {
+						Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "lb", Rule: "Host(`foo.bar`)"},
+					},
+				},
+				Services: map[string]*runtime.ServiceInfo{
+					"lb@myprovider": newLoadBalancer(&dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "lb"}}, allUp),
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/decision-lb-sticky.json",
+			},
+		},
+		{
+			desc: "load-balancer sticky cookie of a server down",
+			path: "/api/http/routers/foo@myprovider/decision",
+			body: `{"host": "foo.bar", "cookies": {"lb": "http://10.0.0.2:80"}}`,
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"foo@myprovider": {
+						Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "lb", Rule: "Host(`foo.bar`)"},
+					},
+				},
+				Services: map[string]*runtime.ServiceInfo{
+					"lb@myprovider": newLoadBalancer(&dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "lb"}}, map[string]string{
+						"http://10.0.0.1:80": "UP",
+						"http://10.0.0.2:80": "DOWN",
+					}),
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/decision-lb-down.json",
+			},
+		},
+		{
+			desc: "weighted sticky cookie honored, rule not matched",
+			path: "/api/http/routers/foo@myprovider/decision",
+			body: `{"host": "bar.foo", "cookies": {"wrr": "lb2"}}`,
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{"foo@myprovider": router},
+				Services: map[string]*runtime.ServiceInfo{
+					"wrr@myprovider": {
+						Service: &dynamic.Service{
+							Weighted: &dynamic.WeightedRoundRobin{
+								Sticky:   &dynamic.Sticky{Cookie: &dynamic.Cookie{Name: "wrr"}},
+								Services: []dynamic.WRRService{{Name: "lb1"}, {Name: "lb2"}},
+							},
+						},
+					},
+					"lb1@myprovider": newLoadBalancer(nil, allUp),
+					"lb2@myprovider": newLoadBalancer(nil, allUp),
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/decision-wrr-sticky.json",
+			},
+		},
+		{
+			desc: "weighted health check exclusion",
+			path: "/api/http/routers/foo@myprovider/decision",
+			body: `{"host": "foo.bar"}`,
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{"foo@myprovider": router},
+				Services: map[string]*runtime.ServiceInfo{
+					"wrr@myprovider": {
+						Service: &dynamic.Service{
+							Weighted: &dynamic.WeightedRoundRobin{
+								HealthCheck: &dynamic.HealthCheck{},
+								Services:    []dynamic.WRRService{{Name: "lb1"}, {Name: "lb2"}},
+							},
+						},
+					},
+					"lb1@myprovider": newLoadBalancer(nil, map[string]string{
+						"http://10.0.0.1:80": "DOWN",
+						"http://10.0.0.2:80": "DOWN",
+					}),
+					"lb2@myprovider": newLoadBalancer(nil, allUp),
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/decision-wrr-healthcheck.json",
+			},
+		},
+		{
+			desc: "failover to the fallback service",
+			path: "/api/http/routers/foo@myprovider/decision",
+			body: `{"host": "foo.bar"}`,
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{"foo@myprovider": router},
+				Services: map[string]*runtime.ServiceInfo{
+					"wrr@myprovider": {
+						Service: &dynamic.Service{
+							Failover: &dynamic.Failover{Service: "lb1", Fallback: "lb2"},
+						},
+					},
+					"lb1@myprovider": newLoadBalancer(nil, map[string]string{
+						"http://10.0.0.1:80": "DOWN",
+						"http://10.0.0.2:80": "DOWN",
+					}),
+					"lb2@myprovider": newLoadBalancer(nil, map[string]string{
+						"http://10.0.0.1:80": "UP",
+						"http://10.0.0.2:80": "DOWN",
+					}),
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/decision-failover.json",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{Debug: true}, Global: &static.Global{}}, &test.conf)
+			server := httptest.NewServer(handler.createRouter())
+
+			resp, err := http.DefaultClient.Post(server.URL+test.path, "application/json", strings.NewReader(test.body))
+			require.NoError(t, err)
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			if test.expected.jsonFile == "" {
+				return
+			}
+
+			assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = os.WriteFile(test.expected.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
+
+func TestHandler_Decision_debugDisabled(t *testing.T) {
+	conf := runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"foo@myprovider": {
+				Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "foo", Rule: "Host(`foo.bar`)"},
+			},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &conf)
+	server := httptest.NewServer(handler.createRouter())
+	t.Cleanup(server.Close)
+
+	resp, err := http.DefaultClient.Post(server.URL+"/api/http/routers/foo@myprovider/decision", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
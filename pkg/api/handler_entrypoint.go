@@ -8,8 +8,10 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/rules"
 )
 
 type entryPointRepresentation struct {
@@ -17,6 +19,23 @@ type entryPointRepresentation struct {
 	Name string `json:"name,omitempty"`
 }
 
+// routerOrderRepresentation is the evaluation order of the rules of the HTTP routers of an entry point.
+type routerOrderRepresentation struct {
+	EntryPoint       string                 `json:"entryPoint"`
+	PriorityStrategy string                 `json:"priorityStrategy"`
+	Routers          []routePriorityElement `json:"routers"`
+	TLSRouters       []routePriorityElement `json:"tlsRouters"`
+}
+
+// routePriorityElement is the rule of a router, with its effective priority.
+type routePriorityElement struct {
+	Name     string `json:"name"`
+	Rule     string `json:"rule"`
+	Priority int    `json:"priority"`
+	// DefaultPriority is true when the priority is computed by the priority strategy.
+	DefaultPriority bool `json:"defaultPriority,omitempty"`
+}
+
 func (h Handler) getEntryPoints(rw http.ResponseWriter, request *http.Request) {
 	results := make([]entryPointRepresentation, 0, len(h.staticConfig.EntryPoints))
 
@@ -70,3 +89,66 @@ func (h Handler) getEntryPoint(rw http.ResponseWriter, request *http.Request) {
 		writeError(rw, err.Error(), http.StatusInternalServerError)
 	}
 }
+
+// getEntryPointRouters returns the HTTP routers of an entry point, in the order in which their rules are evaluated,
+// for the non-TLS and the TLS requests.
+func (h Handler) getEntryPointRouters(rw http.ResponseWriter, request *http.Request) {
+	entryPointID := mux.Vars(request)["entryPointID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	ep, ok := h.staticConfig.EntryPoints[entryPointID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("entry point not found: %s", entryPointID), http.StatusNotFound)
+		return
+	}
+
+	strategy := ep.HTTP.PriorityStrategy
+	if strategy == "" {
+		strategy = rules.PriorityStrategyLength
+	}
+
+	result := routerOrderRepresentation{
+		EntryPoint:       entryPointID,
+		PriorityStrategy: strategy,
+		Routers:          h.getRoutersOrder(entryPointID, strategy, false),
+		TLSRouters:       h.getRoutersOrder(entryPointID, strategy, true),
+	}
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getRoutersOrder returns the enabled HTTP routers of an entry point in evaluation order, as sorted by the router manager.
+func (h Handler) getRoutersOrder(entryPointName, strategy string, tls bool) []routePriorityElement {
+	var routes []rules.Route
+	for name, rt := range h.runtimeConfiguration.Routers {
+		if rt.Status == runtime.StatusDisabled || (rt.TLS != nil) != tls || !contains(rt.EntryPoints, entryPointName) {
+			continue
+		}
+
+		priority, err := rules.GetPriority(rt.Rule, rt.Priority, strategy)
+		if err != nil {
+			continue
+		}
+
+		routes = append(routes, rules.Route{Name: name, Rule: rt.Rule, Priority: priority})
+	}
+
+	rules.SortRoutes(routes)
+
+	results := make([]routePriorityElement, 0, len(routes))
+	for _, route := range routes {
+		results = append(results, routePriorityElement{
+			Name:            route.Name,
+			Rule:            route.Rule,
+			Priority:        route.Priority,
+			DefaultPriority: h.runtimeConfiguration.Routers[route.Name].Priority == 0,
+		})
+	}
+
+	return results
+}
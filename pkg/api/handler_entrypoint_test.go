@@ -12,6 +12,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 )
@@ -248,3 +249,156 @@ func generateEntryPoints(nb int) map[string]*static.EntryPoint {
 
 	return eps
 }
+
+func TestHandler_EntryPointRouters(t *testing.T) {
+	type expected struct {
+		statusCode int
+		jsonFile   string
+	}
+
+	routers := map[string]*runtime.RouterInfo{
+		"api@file": {
+			Router: &dynamic.Router{
+				EntryPoints: []string{"web"},
+				Service:     "api",
+				Rule:        "Host(`foo.bar`) && PathPrefix(`/api`)",
+			},
+			Status: runtime.StatusEnabled,
+		},
+		"host@file": {
+			Router: &dynamic.Router{
+				EntryPoints: []string{"web"},
+				Service:     "host",
+				Rule:        "Host(`foo.bar`)",
+			},
+			Status: runtime.StatusEnabled,
+		},
+		"catchall@file": {
+			Router: &dynamic.Router{
+				EntryPoints: []string{"web"},
+				Service:     "catchall",
+				Rule:        "PathPrefix(`/some/long/path/prefix`)",
+			},
+			Status: runtime.StatusEnabled,
+		},
+		"explicit@file": {
+			Router: &dynamic.Router{
+				EntryPoints: []string{"web"},
+				Service:     "explicit",
+				Rule:        "Path(`/`)",
+				Priority:    1,
+			},
+			Status: runtime.StatusEnabled,
+		},
+		"secure@file": {
+			Router: &dynamic.Router{
+				EntryPoints: []string{"web"},
+				Service:     "secure",
+				Rule:        "Host(`foo.bar`)",
+				TLS:         &dynamic.RouterTLSConfig{},
+			},
+			Status: runtime.StatusEnabled,
+		},
+		"disabled@file": {
+			Router: &dynamic.Router{
+				EntryPoints: []string{"web"},
+				Service:     "missing",
+				Rule:        "Host(`foo.bar`)",
+			},
+			Status: runtime.StatusDisabled,
+		},
+		"other@file": {
+			Router: &dynamic.Router{
+				EntryPoints: []string{"other"},
+				Service:     "other",
+				Rule:        "Host(`foo.bar`)",
+			},
+			Status: runtime.StatusEnabled,
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		strategy string
+		expected expected
+	}{
+		{
+			desc: "length strategy",
+			path: "/api/entrypoints/web/routers",
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/entrypoint-routers-length.json",
+			},
+		},
+		{
+			desc:     "specificity strategy",
+			path:     "/api/entrypoints/web/routers",
+			strategy: "specificity",
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/entrypoint-routers-specificity.json",
+			},
+		},
+		{
+			desc: "entry point not found",
+			path: "/api/entrypoints/foo/routers",
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := static.Configuration{
+				Global: &static.Global{},
+				API:    &static.API{},
+				EntryPoints: map[string]*static.EntryPoint{
+					"web": {
+						Address: ":80",
+						HTTP:    static.HTTPConfig{PriorityStrategy: test.strategy},
+					},
+				},
+			}
+
+			handler := New(conf, &runtime.Configuration{Routers: routers})
+			server := httptest.NewServer(handler.createRouter())
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			if test.expected.jsonFile == "" {
+				return
+			}
+
+			assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = os.WriteFile(test.expected.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
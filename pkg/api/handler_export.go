@@ -0,0 +1,303 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+)
+
+const internalProvider = "internal"
+
+// rawDataFormat returns the output format of the raw data requested by the format query parameter,
+// or by the Accept header.
+func rawDataFormat(request *http.Request) (string, error) {
+	if format := request.URL.Query().Get("format"); format != "" {
+		switch strings.ToLower(format) {
+		case formatJSON:
+			return formatJSON, nil
+		case formatYAML, "yml":
+			return formatYAML, nil
+		case formatTOML:
+			return formatTOML, nil
+		default:
+			return "", fmt.Errorf("unsupported format: %s", format)
+		}
+	}
+
+	for _, accept := range strings.Split(request.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+			return formatYAML, nil
+		case "application/toml", "text/toml":
+			return formatTOML, nil
+		case "application/json":
+			return formatJSON, nil
+		}
+	}
+
+	return formatJSON, nil
+}
+
+// exportRuntimeConfiguration writes the dynamic configuration of the runtime configuration,
+// in a format compatible with the file provider.
+func (h Handler) exportRuntimeConfiguration(rw http.ResponseWriter, request *http.Request, format string) {
+	conf := h.exportConfiguration()
+
+	var (
+		buf         bytes.Buffer
+		contentType string
+		err         error
+	)
+
+	switch format {
+	case formatYAML:
+		contentType = "application/yaml"
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		err = encoder.Encode(conf)
+	case formatTOML:
+		contentType = "application/toml"
+		err = toml.NewEncoder(&buf).Encode(conf)
+	}
+
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", contentType)
+
+	if _, err = buf.WriteTo(rw); err != nil {
+		log.FromContext(request.Context()).Error(err)
+	}
+}
+
+// exportConfiguration returns the dynamic configuration of the elements of the runtime configuration,
+// the elements of the internal provider excepted.
+// As the file provider does not accept qualified names, an element named "name@provider" is exported as "name-provider",
+// and the references to the elements are renamed accordingly.
+// The references to the TLS options and the servers transports, which are not exported, are qualified with their provider.
+func (h Handler) exportConfiguration() *dynamic.Configuration {
+	conf := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:     make(map[string]*dynamic.Router),
+			Middlewares: make(map[string]*dynamic.Middleware),
+			Services:    make(map[string]*dynamic.Service),
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers:     make(map[string]*dynamic.TCPRouter),
+			Middlewares: make(map[string]*dynamic.TCPMiddleware),
+			Services:    make(map[string]*dynamic.TCPService),
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  make(map[string]*dynamic.UDPRouter),
+			Services: make(map[string]*dynamic.UDPService),
+		},
+	}
+
+	for name, rt := range h.runtimeConfiguration.Routers {
+		pvd := getProviderName(name)
+		if pvd == internalProvider || rt.Router == nil {
+			continue
+		}
+
+		router := rt.Router.DeepCopy()
+		router.Service = exportReference(router.Service, pvd)
+		router.Middlewares = exportReferences(router.Middlewares, pvd)
+		if router.TLS != nil {
+			router.TLS.Options = tlsOptionsReference(router.TLS.Options, pvd)
+		}
+
+		conf.HTTP.Routers[exportName(name)] = router
+	}
+
+	for name, mi := range h.runtimeConfiguration.Middlewares {
+		pvd := getProviderName(name)
+		if pvd == internalProvider || mi.Middleware == nil {
+			continue
+		}
+
+		middleware := mi.Middleware.DeepCopy()
+		if middleware.Chain != nil {
+			middleware.Chain.Middlewares = exportReferences(middleware.Chain.Middlewares, pvd)
+		}
+		if middleware.Errors != nil {
+			middleware.Errors.Service = exportReference(middleware.Errors.Service, pvd)
+		}
+		if middleware.Fallback != nil {
+			middleware.Fallback.Service = exportReference(middleware.Fallback.Service, pvd)
+		}
+
+		conf.HTTP.Middlewares[exportName(name)] = middleware
+	}
+
+	for name, si := range h.runtimeConfiguration.Services {
+		pvd := getProviderName(name)
+		if pvd == internalProvider || si.Service == nil {
+			continue
+		}
+
+		service := si.Service.DeepCopy()
+		if service.LoadBalancer != nil {
+			service.LoadBalancer.ServersTransport = qualifyReference(service.LoadBalancer.ServersTransport, pvd)
+		}
+		if service.Weighted != nil {
+			for i := range service.Weighted.Services {
+				service.Weighted.Services[i].Name = exportReference(service.Weighted.Services[i].Name, pvd)
+			}
+			if service.Weighted.Ramp != nil {
+				service.Weighted.Ramp.Service = exportReference(service.Weighted.Ramp.Service, pvd)
+			}
+		}
+		if service.Mirroring != nil {
+			service.Mirroring.Service = exportReference(service.Mirroring.Service, pvd)
+			for i := range service.Mirroring.Mirrors {
+				service.Mirroring.Mirrors[i].Name = exportReference(service.Mirroring.Mirrors[i].Name, pvd)
+			}
+		}
+		if service.Failover != nil {
+			service.Failover.Service = exportReference(service.Failover.Service, pvd)
+			service.Failover.Fallback = exportReference(service.Failover.Fallback, pvd)
+		}
+
+		conf.HTTP.Services[exportName(name)] = service
+	}
+
+	for name, rt := range h.runtimeConfiguration.TCPRouters {
+		pvd := getProviderName(name)
+		if pvd == internalProvider || rt.TCPRouter == nil {
+			continue
+		}
+
+		router := rt.TCPRouter.DeepCopy()
+		router.Service = exportReference(router.Service, pvd)
+		router.Middlewares = exportReferences(router.Middlewares, pvd)
+		if router.TLS != nil {
+			router.TLS.Options = tlsOptionsReference(router.TLS.Options, pvd)
+		}
+
+		conf.TCP.Routers[exportName(name)] = router
+	}
+
+	for name, mi := range h.runtimeConfiguration.TCPMiddlewares {
+		pvd := getProviderName(name)
+		if pvd == internalProvider || mi.TCPMiddleware == nil {
+			continue
+		}
+
+		conf.TCP.Middlewares[exportName(name)] = mi.TCPMiddleware.DeepCopy()
+	}
+
+	for name, si := range h.runtimeConfiguration.TCPServices {
+		pvd := getProviderName(name)
+		if pvd == internalProvider || si.TCPService == nil {
+			continue
+		}
+
+		service := si.TCPService.DeepCopy()
+		if service.Weighted != nil {
+			for i := range service.Weighted.Services {
+				service.Weighted.Services[i].Name = exportReference(service.Weighted.Services[i].Name, pvd)
+			}
+		}
+
+		conf.TCP.Services[exportName(name)] = service
+	}
+
+	for name, rt := range h.runtimeConfiguration.UDPRouters {
+		pvd := getProviderName(name)
+		if pvd == internalProvider || rt.UDPRouter == nil {
+			continue
+		}
+
+		router := rt.UDPRouter.DeepCopy()
+		router.Service = exportReference(router.Service, pvd)
+
+		conf.UDP.Routers[exportName(name)] = router
+	}
+
+	for name, si := range h.runtimeConfiguration.UDPServices {
+		pvd := getProviderName(name)
+		if pvd == internalProvider || si.UDPService == nil {
+			continue
+		}
+
+		service := si.UDPService.DeepCopy()
+		if service.Weighted != nil {
+			for i := range service.Weighted.Services {
+				service.Weighted.Services[i].Name = exportReference(service.Weighted.Services[i].Name, pvd)
+			}
+		}
+
+		conf.UDP.Services[exportName(name)] = service
+	}
+
+	return conf
+}
+
+// exportName returns the name of an element in the exported configuration.
+func exportName(qualifiedName string) string {
+	return strings.Replace(qualifiedName, "@", "-", 1)
+}
+
+// exportReference returns a reference, relative to the given provider, to an element of the exported configuration.
+// The references to the elements of the internal provider are kept as is.
+func exportReference(reference, providerName string) string {
+	if reference == "" {
+		return reference
+	}
+
+	qualified := qualifyReference(reference, providerName)
+	if getProviderName(qualified) == internalProvider {
+		return qualified
+	}
+
+	return exportName(qualified)
+}
+
+func exportReferences(references []string, providerName string) []string {
+	for i, reference := range references {
+		references[i] = exportReference(reference, providerName)
+	}
+
+	return references
+}
+
+// qualifyReference returns a reference, relative to the given provider, qualified with its provider.
+func qualifyReference(reference, providerName string) string {
+	if reference == "" || strings.Contains(reference, "@") {
+		return reference
+	}
+
+	return reference + "@" + providerName
+}
+
+// tlsOptionsReference returns a reference, relative to the given provider, to TLS options.
+// The default TLS options are shared by all the providers, and their reference is kept as is.
+func tlsOptionsReference(reference, providerName string) string {
+	if reference == traefiktls.DefaultTLSConfigName {
+		return reference
+	}
+
+	return qualifyReference(reference, providerName)
+}
@@ -0,0 +1,291 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"gopkg.in/yaml.v3"
+)
+
+func TestHandler_RawDataExport(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		path                string
+		accept              string
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{
+			desc:                "YAML format query parameter",
+			path:                "/api/rawdata?format=yaml",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/yaml",
+		},
+		{
+			desc:                "TOML format query parameter",
+			path:                "/api/rawdata?format=toml",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/toml",
+		},
+		{
+			desc:                "YAML Accept header",
+			path:                "/api/rawdata",
+			accept:              "text/html, application/yaml;q=0.9",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/yaml",
+		},
+		{
+			desc:                "TOML Accept header",
+			path:                "/api/rawdata",
+			accept:              "application/toml",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/toml",
+		},
+		{
+			desc:                "format query parameter takes precedence over the Accept header",
+			path:                "/api/rawdata?format=json",
+			accept:              "application/yaml",
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		{
+			desc:               "unsupported format",
+			path:               "/api/rawdata?format=xml",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	expected := &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"bar-myprovider": {
+					EntryPoints: []string{"web"},
+					Middlewares: []string{"auth-myprovider", "addPrefixTest-anotherprovider"},
+					Service:     "foo-service-myprovider",
+					Rule:        "Host(`foo.bar`)",
+					TLS:         &dynamic.RouterTLSConfig{Options: "foo@myprovider"},
+				},
+				"dashboard-myprovider": {
+					EntryPoints: []string{"web"},
+					Service:     "api@internal",
+					Rule:        "Host(`traefik.bar`)",
+				},
+			},
+			Middlewares: map[string]*dynamic.Middleware{
+				"auth-myprovider": {
+					BasicAuth: &dynamic.BasicAuth{Users: []string{"admin:admin"}},
+				},
+				"addPrefixTest-anotherprovider": {
+					AddPrefix: &dynamic.AddPrefix{Prefix: "/toto"},
+				},
+				"chain-myprovider": {
+					Chain: &dynamic.Chain{Middlewares: []string{"auth-myprovider", "addPrefixTest-anotherprovider"}},
+				},
+			},
+			Services: map[string]*dynamic.Service{
+				"foo-service-myprovider": {
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						PassHostHeader: Bool(true),
+						Servers:        []dynamic.Server{{URL: "http://127.0.0.1"}},
+					},
+				},
+				"weighted-myprovider": {
+					Weighted: &dynamic.WeightedRoundRobin{
+						Services: []dynamic.WRRService{{Name: "foo-service-myprovider"}},
+					},
+				},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers: map[string]*dynamic.TCPRouter{
+				"tcpbar-myprovider": {
+					EntryPoints: []string{"web"},
+					Service:     "tcpfoo-service-myprovider",
+					Rule:        "HostSNI(`foo.bar`)",
+				},
+			},
+			Services: map[string]*dynamic.TCPService{
+				"tcpfoo-service-myprovider": {
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "127.0.0.1:80"}},
+					},
+				},
+			},
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers: map[string]*dynamic.UDPRouter{
+				"udpbar-myprovider": {
+					EntryPoints: []string{"udp"},
+					Service:     "udpfoo-service-myprovider",
+				},
+			},
+			Services: map[string]*dynamic.UDPService{
+				"udpfoo-service-myprovider": {
+					LoadBalancer: &dynamic.UDPServersLoadBalancer{
+						Servers: []dynamic.UDPServer{{Address: "127.0.0.1:53"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := exportRuntimeConfigurationFixture()
+			rtConf.PopulateUsedBy()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+			server := httptest.NewServer(handler.createRouter())
+			t.Cleanup(server.Close)
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+test.path, nil)
+			require.NoError(t, err)
+
+			if test.accept != "" {
+				req.Header.Set("Accept", test.accept)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			require.Equal(t, test.expectedStatusCode, resp.StatusCode)
+			if test.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, test.expectedContentType, resp.Header.Get("Content-Type"))
+
+			conf := &dynamic.Configuration{}
+			switch test.expectedContentType {
+			case "application/yaml":
+				require.NoError(t, yaml.Unmarshal(contents, conf))
+			case "application/toml":
+				_, err = toml.Decode(string(contents), conf)
+				require.NoError(t, err)
+			default:
+				return
+			}
+
+			assert.Equal(t, expected, conf)
+		})
+	}
+}
+
+func exportRuntimeConfigurationFixture() *runtime.Configuration {
+	return &runtime.Configuration{
+		Services: map[string]*runtime.ServiceInfo{
+			"foo-service@myprovider": {
+				Service: &dynamic.Service{
+					LoadBalancer: &dynamic.ServersLoadBalancer{
+						PassHostHeader: Bool(true),
+						Servers:        []dynamic.Server{{URL: "http://127.0.0.1"}},
+					},
+				},
+			},
+			"weighted@myprovider": {
+				Service: &dynamic.Service{
+					Weighted: &dynamic.WeightedRoundRobin{
+						Services: []dynamic.WRRService{{Name: "foo-service"}},
+					},
+				},
+			},
+			"api@internal": {
+				Service: &dynamic.Service{},
+			},
+		},
+		Middlewares: map[string]*runtime.MiddlewareInfo{
+			"auth@myprovider": {
+				Middleware: &dynamic.Middleware{
+					BasicAuth: &dynamic.BasicAuth{Users: []string{"admin:admin"}},
+				},
+			},
+			"addPrefixTest@anotherprovider": {
+				Middleware: &dynamic.Middleware{
+					AddPrefix: &dynamic.AddPrefix{Prefix: "/toto"},
+				},
+			},
+			"chain@myprovider": {
+				Middleware: &dynamic.Middleware{
+					Chain: &dynamic.Chain{Middlewares: []string{"auth", "addPrefixTest@anotherprovider"}},
+				},
+			},
+		},
+		Routers: map[string]*runtime.RouterInfo{
+			"bar@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "Host(`foo.bar`)",
+					Middlewares: []string{"auth", "addPrefixTest@anotherprovider"},
+					TLS:         &dynamic.RouterTLSConfig{Options: "foo"},
+				},
+			},
+			"dashboard@myprovider": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"web"},
+					Service:     "api@internal",
+					Rule:        "Host(`traefik.bar`)",
+				},
+			},
+			"ping@internal": {
+				Router: &dynamic.Router{
+					EntryPoints: []string{"traefik"},
+					Service:     "ping@internal",
+					Rule:        "PathPrefix(`/ping`)",
+				},
+			},
+		},
+		TCPServices: map[string]*runtime.TCPServiceInfo{
+			"tcpfoo-service@myprovider": {
+				TCPService: &dynamic.TCPService{
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: "127.0.0.1:80"}},
+					},
+				},
+			},
+		},
+		TCPRouters: map[string]*runtime.TCPRouterInfo{
+			"tcpbar@myprovider": {
+				TCPRouter: &dynamic.TCPRouter{
+					EntryPoints: []string{"web"},
+					Service:     "tcpfoo-service",
+					Rule:        "HostSNI(`foo.bar`)",
+				},
+			},
+		},
+		UDPServices: map[string]*runtime.UDPServiceInfo{
+			"udpfoo-service@myprovider": {
+				UDPService: &dynamic.UDPService{
+					LoadBalancer: &dynamic.UDPServersLoadBalancer{
+						Servers: []dynamic.UDPServer{{Address: "127.0.0.1:53"}},
+					},
+				},
+			},
+		},
+		UDPRouters: map[string]*runtime.UDPRouterInfo{
+			"udpbar@myprovider": {
+				UDPRouter: &dynamic.UDPRouter{
+					EntryPoints: []string{"udp"},
+					Service:     "udpfoo-service@myprovider",
+				},
+			},
+		},
+	}
+}
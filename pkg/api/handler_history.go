@@ -0,0 +1,284 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// ConfigurationSnapshot is a dynamic configuration applied by Traefik.
+type ConfigurationSnapshot struct {
+	ID            int                    `json:"id"`
+	Date          time.Time              `json:"date"`
+	Configuration *dynamic.Configuration `json:"configuration,omitempty"`
+}
+
+// ConfigurationHistory gives access to the last applied dynamic configurations.
+type ConfigurationHistory interface {
+	// History returns the last applied configurations, the oldest first.
+	History() []ConfigurationSnapshot
+}
+
+// Kinds of changes of a configuration element.
+const (
+	changeAdded    = "added"
+	changeRemoved  = "removed"
+	changeModified = "modified"
+)
+
+type configurationDiff struct {
+	From    int             `json:"from"`
+	To      int             `json:"to"`
+	Changes []elementChange `json:"changes"`
+}
+
+type elementChange struct {
+	// Kind is the kind of the element, e.g. http.routers.
+	Kind   string      `json:"kind"`
+	Name   string      `json:"name"`
+	Change string      `json:"change"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+func (h Handler) getConfigurationHistory(rw http.ResponseWriter, request *http.Request) {
+	history := h.configurationHistory.History()
+
+	results := make([]ConfigurationSnapshot, 0, len(history))
+	for _, snapshot := range history {
+		results = append(results, ConfigurationSnapshot{
+			ID:            snapshot.ID,
+			Date:          snapshot.Date,
+			Configuration: sanitizeConfiguration(snapshot.Configuration),
+		})
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(results)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getConfigurationDiff returns the changes between two configurations of the history.
+// By default, the last applied configuration is compared with the previous one.
+func (h Handler) getConfigurationDiff(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	history := h.configurationHistory.History()
+	if len(history) == 0 {
+		writeError(rw, "no configuration in the history", http.StatusNotFound)
+		return
+	}
+
+	toIndex := len(history) - 1
+	if value := request.URL.Query().Get("to"); value != "" {
+		var err error
+		toIndex, err = findSnapshot(history, value)
+		if err != nil {
+			writeError(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if toIndex < 0 {
+			writeError(rw, fmt.Sprintf("configuration not found in the history: %s", value), http.StatusNotFound)
+			return
+		}
+	}
+
+	// Without a previous configuration, the changes are computed from an empty configuration.
+	from := ConfigurationSnapshot{Configuration: &dynamic.Configuration{}}
+	if toIndex > 0 {
+		from = history[toIndex-1]
+	}
+
+	if value := request.URL.Query().Get("from"); value != "" {
+		fromIndex, err := findSnapshot(history, value)
+		if err != nil {
+			writeError(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if fromIndex < 0 {
+			writeError(rw, fmt.Sprintf("configuration not found in the history: %s", value), http.StatusNotFound)
+			return
+		}
+
+		from = history[fromIndex]
+	}
+
+	to := history[toIndex]
+
+	result := configurationDiff{
+		From:    from.ID,
+		To:      to.ID,
+		Changes: diffConfigurations(sanitizeConfiguration(from.Configuration), sanitizeConfiguration(to.Configuration)),
+	}
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// findSnapshot returns the index in the history of the snapshot with the given ID, or -1 if it is not in the history.
+func findSnapshot(history []ConfigurationSnapshot, value string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid configuration ID: %s", value)
+	}
+
+	for i, snapshot := range history {
+		if snapshot.ID == id {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// diffConfigurations returns the elements added, removed, or modified between the two configurations,
+// sorted by kind and name.
+func diffConfigurations(from, to *dynamic.Configuration) []elementChange {
+	fromElements := configurationElements(from)
+	toElements := configurationElements(to)
+
+	changes := make([]elementChange, 0)
+	for _, kind := range elementKinds {
+		before := fromElements[kind]
+		after := toElements[kind]
+
+		for name, element := range before {
+			toElement, ok := after[name]
+			if !ok {
+				changes = append(changes, elementChange{Kind: kind, Name: name, Change: changeRemoved, Before: element})
+				continue
+			}
+
+			if !reflect.DeepEqual(element, toElement) {
+				changes = append(changes, elementChange{Kind: kind, Name: name, Change: changeModified, Before: element, After: toElement})
+			}
+		}
+
+		for name, element := range after {
+			if _, ok := before[name]; !ok {
+				changes = append(changes, elementChange{Kind: kind, Name: name, Change: changeAdded, After: element})
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind == changes[j].Kind {
+			return changes[i].Name < changes[j].Name
+		}
+		return kindIndex(changes[i].Kind) < kindIndex(changes[j].Kind)
+	})
+
+	return changes
+}
+
+// elementKinds are the kinds of configuration elements compared by the diffs, in the order of the changes.
+var elementKinds = []string{
+	"http.routers",
+	"http.middlewares",
+	"http.services",
+	"http.serversTransports",
+	"tcp.routers",
+	"tcp.middlewares",
+	"tcp.services",
+	"udp.routers",
+	"udp.services",
+	"tls.options",
+	"tls.stores",
+}
+
+func kindIndex(kind string) int {
+	for i, k := range elementKinds {
+		if k == kind {
+			return i
+		}
+	}
+
+	return len(elementKinds)
+}
+
+// configurationElements returns the elements of the configuration, by kind and name.
+func configurationElements(conf *dynamic.Configuration) map[string]map[string]interface{} {
+	elements := make(map[string]map[string]interface{})
+	if conf == nil {
+		return elements
+	}
+
+	add := func(kind string, values interface{}) {
+		v := reflect.ValueOf(values)
+
+		elements[kind] = make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			elements[kind][key.String()] = v.MapIndex(key).Interface()
+		}
+	}
+
+	if conf.HTTP != nil {
+		add("http.routers", conf.HTTP.Routers)
+		add("http.middlewares", conf.HTTP.Middlewares)
+		add("http.services", conf.HTTP.Services)
+		add("http.serversTransports", conf.HTTP.ServersTransports)
+	}
+
+	if conf.TCP != nil {
+		add("tcp.routers", conf.TCP.Routers)
+		add("tcp.middlewares", conf.TCP.Middlewares)
+		add("tcp.services", conf.TCP.Services)
+	}
+
+	if conf.UDP != nil {
+		add("udp.routers", conf.UDP.Routers)
+		add("udp.services", conf.UDP.Services)
+	}
+
+	if conf.TLS != nil {
+		add("tls.options", conf.TLS.Options)
+		add("tls.stores", conf.TLS.Stores)
+	}
+
+	return elements
+}
+
+// sanitizeConfiguration returns a copy of the configuration without the certificates,
+// so that their private keys are not exposed.
+func sanitizeConfiguration(conf *dynamic.Configuration) *dynamic.Configuration {
+	if conf == nil {
+		return nil
+	}
+
+	sanitized := conf.DeepCopy()
+
+	if sanitized.HTTP != nil {
+		for _, transport := range sanitized.HTTP.ServersTransports {
+			if transport != nil {
+				transport.Certificates = nil
+			}
+		}
+	}
+
+	if sanitized.TLS != nil {
+		sanitized.TLS.Certificates = nil
+
+		for name, store := range sanitized.TLS.Stores {
+			store.DefaultCertificate = nil
+			sanitized.TLS.Stores[name] = store
+		}
+	}
+
+	return sanitized
+}
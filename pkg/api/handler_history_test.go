@@ -0,0 +1,232 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/tls"
+)
+
+type configurationHistoryFunc func() []ConfigurationSnapshot
+
+func (f configurationHistoryFunc) History() []ConfigurationSnapshot {
+	return f()
+}
+
+func TestHandler_ConfigurationHistory(t *testing.T) {
+	date := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	history := configurationHistoryFunc(func() []ConfigurationSnapshot {
+		return []ConfigurationSnapshot{
+			{
+				ID:   1,
+				Date: date,
+				Configuration: &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"foo@file": {Rule: "Host(`foo.localhost`)", Service: "foo@file"},
+						},
+					},
+					TLS: &dynamic.TLSConfiguration{
+						Certificates: []*tls.CertAndStores{{Certificate: tls.Certificate{CertFile: "cert", KeyFile: "key"}}},
+					},
+				},
+			},
+		}
+	})
+
+	staticConfig := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+	handler := NewBuilder(staticConfig, nil, history, nil, nil, nil)(&runtime.Configuration{})
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/rawdata/history")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var snapshots []ConfigurationSnapshot
+	err = json.NewDecoder(resp.Body).Decode(&snapshots)
+	require.NoError(t, err)
+
+	// The certificates are not exposed.
+	expected := []ConfigurationSnapshot{
+		{
+			ID:   1,
+			Date: date,
+			Configuration: &dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo@file": {Rule: "Host(`foo.localhost`)", Service: "foo@file"},
+					},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
+	}
+	assert.Equal(t, expected, snapshots)
+}
+
+func TestHandler_ConfigurationDiff(t *testing.T) {
+	history := configurationHistoryFunc(func() []ConfigurationSnapshot {
+		return []ConfigurationSnapshot{
+			{
+				ID: 3,
+				Configuration: &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"foo@file": {Rule: "Host(`foo.localhost`)", Service: "foo@file"},
+							"bar@file": {Rule: "Host(`bar.localhost`)", Service: "bar@file"},
+						},
+					},
+				},
+			},
+			{
+				ID: 4,
+				Configuration: &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"foo@file": {Rule: "Host(`foo.localhost`) && PathPrefix(`/api`)", Service: "foo@file"},
+						},
+					},
+					TCP: &dynamic.TCPConfiguration{
+						Routers: map[string]*dynamic.TCPRouter{
+							"baz@file": {Rule: "HostSNI(`*`)", Service: "baz@file"},
+						},
+					},
+				},
+			},
+			{
+				ID: 5,
+				Configuration: &dynamic.Configuration{
+					HTTP: &dynamic.HTTPConfiguration{
+						Routers: map[string]*dynamic.Router{
+							"foo@file": {Rule: "Host(`foo.localhost`) && PathPrefix(`/api`)", Service: "foo@file"},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	testCases := []struct {
+		desc       string
+		query      string
+		history    ConfigurationHistory
+		statusCode int
+		expected   *configurationDiff
+	}{
+		{
+			desc:       "last change",
+			history:    history,
+			statusCode: http.StatusOK,
+			expected: &configurationDiff{
+				From: 4,
+				To:   5,
+				Changes: []elementChange{
+					{Kind: "tcp.routers", Name: "baz@file", Change: changeRemoved, Before: map[string]interface{}{"rule": "HostSNI(`*`)", "service": "baz@file"}},
+				},
+			},
+		},
+		{
+			desc:       "from and to",
+			query:      "?from=3&to=4",
+			history:    history,
+			statusCode: http.StatusOK,
+			expected: &configurationDiff{
+				From: 3,
+				To:   4,
+				Changes: []elementChange{
+					{Kind: "http.routers", Name: "bar@file", Change: changeRemoved, Before: map[string]interface{}{"rule": "Host(`bar.localhost`)", "service": "bar@file"}},
+					{
+						Kind:   "http.routers",
+						Name:   "foo@file",
+						Change: changeModified,
+						Before: map[string]interface{}{"rule": "Host(`foo.localhost`)", "service": "foo@file"},
+						After:  map[string]interface{}{"rule": "Host(`foo.localhost`) && PathPrefix(`/api`)", "service": "foo@file"},
+					},
+					{Kind: "tcp.routers", Name: "baz@file", Change: changeAdded, After: map[string]interface{}{"rule": "HostSNI(`*`)", "service": "baz@file"}},
+				},
+			},
+		},
+		{
+			desc:       "oldest configuration",
+			query:      "?to=3",
+			history:    history,
+			statusCode: http.StatusOK,
+			expected: &configurationDiff{
+				To: 3,
+				Changes: []elementChange{
+					{Kind: "http.routers", Name: "bar@file", Change: changeAdded, After: map[string]interface{}{"rule": "Host(`bar.localhost`)", "service": "bar@file"}},
+					{Kind: "http.routers", Name: "foo@file", Change: changeAdded, After: map[string]interface{}{"rule": "Host(`foo.localhost`)", "service": "foo@file"}},
+				},
+			},
+		},
+		{
+			desc:       "no change",
+			query:      "?from=4&to=4",
+			history:    history,
+			statusCode: http.StatusOK,
+			expected:   &configurationDiff{From: 4, To: 4, Changes: []elementChange{}},
+		},
+		{
+			desc:       "unknown configuration",
+			query:      "?from=1",
+			history:    history,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "invalid configuration ID",
+			query:      "?to=foo",
+			history:    history,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			desc:       "empty history",
+			history:    configurationHistoryFunc(func() []ConfigurationSnapshot { return nil }),
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "no history",
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			staticConfig := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+			handler := NewBuilder(staticConfig, nil, test.history, nil, nil, nil)(&runtime.Configuration{})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/rawdata/diff"+test.query, nil)
+			rw := httptest.NewRecorder()
+
+			handler.ServeHTTP(rw, req)
+
+			require.Equal(t, test.statusCode, rw.Code)
+
+			if test.expected == nil {
+				return
+			}
+
+			var diff configurationDiff
+			err := json.NewDecoder(rw.Body).Decode(&diff)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, &diff)
+		})
+	}
+}
@@ -29,10 +29,11 @@ func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresen
 
 type serviceRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
-	Name         string            `json:"name,omitempty"`
-	Provider     string            `json:"provider,omitempty"`
-	Type         string            `json:"type,omitempty"`
+	ServerStatus map[string]string   `json:"serverStatus,omitempty"`
+	Ramp         *runtime.RampStatus `json:"ramp,omitempty"`
+	Name         string              `json:"name,omitempty"`
+	Provider     string              `json:"provider,omitempty"`
+	Type         string              `json:"type,omitempty"`
 }
 
 func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepresentation {
@@ -41,6 +42,7 @@ func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepre
 		Name:         name,
 		Provider:     getProviderName(name),
 		ServerStatus: si.GetAllStatus(),
+		Ramp:         si.GetRampStatus(),
 		Type:         strings.ToLower(extractType(si.Service)),
 	}
 }
@@ -165,6 +167,29 @@ func (h Handler) getService(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func (h Handler) getServiceHealthChecks(rw http.ResponseWriter, request *http.Request) {
+	serviceID := mux.Vars(request)["serviceID"]
+
+	rw.Header().Add("Content-Type", "application/json")
+
+	service, ok := h.runtimeConfiguration.Services[serviceID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("service not found: %s", serviceID), http.StatusNotFound)
+		return
+	}
+
+	healthChecks := service.GetServerHealthChecks()
+	if healthChecks == nil {
+		healthChecks = make(map[string]runtime.ServerHealthCheck)
+	}
+
+	err := json.NewEncoder(rw).Encode(healthChecks)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (h Handler) getMiddlewares(rw http.ResponseWriter, request *http.Request) {
 	results := make([]middlewareRepresentation, 0, len(h.runtimeConfiguration.Middlewares))
 
@@ -10,16 +10,21 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/tls"
 )
 
 func Bool(v bool) *bool { return &v }
 
+func Int(v int) *int { return &v }
+
 func TestHandler_HTTP(t *testing.T) {
 	type expected struct {
 		statusCode int
@@ -223,6 +228,42 @@ func TestHandler_HTTP(t *testing.T) {
 				jsonFile:   "testdata/router-bar.json",
 			},
 		},
+		{
+			desc: "one TLS router by id, with the served certificates",
+			path: "/api/http/routers/secure@myprovider",
+			conf: runtime.Configuration{
+				Routers: map[string]*runtime.RouterInfo{
+					"secure@myprovider": {
+						Router: &dynamic.Router{
+							EntryPoints: []string{"websecure"},
+							Service:     "foo-service@myprovider",
+							Rule:        "Host(`foo.bar`) || Host(`other.bar`)",
+							TLS:         &dynamic.RouterTLSConfig{CertResolver: "myresolver"},
+						},
+						Status: "enabled",
+						Certificates: map[string]*tls.CertificateInfo{
+							"foo.bar": {
+								Source:       tls.CertificateSourceResolver,
+								Resolver:     "myresolver",
+								SerialNumber: "3a2b1c",
+								SANs:         []string{"foo.bar"},
+								NotAfter:     time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC),
+							},
+							"other.bar": {
+								Source:       tls.CertificateSourceDefault,
+								SerialNumber: "1f",
+								SANs:         []string{"traefik.default"},
+								NotAfter:     time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC),
+							},
+						},
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/router-secure-certificates.json",
+			},
+		},
 		{
 			desc: "one router by id, that does not exist",
 			path: "/api/http/routers/foo@myprovider",
@@ -537,6 +578,47 @@ func TestHandler_HTTP(t *testing.T) {
 				jsonFile:   "testdata/service-bar.json",
 			},
 		},
+		{
+			desc: "one weighted service by id, with a weight ramp",
+			path: "/api/http/services/canary@myprovider",
+			conf: runtime.Configuration{
+				Services: map[string]*runtime.ServiceInfo{
+					"canary@myprovider": func() *runtime.ServiceInfo {
+						si := &runtime.ServiceInfo{
+							Service: &dynamic.Service{
+								Weighted: &dynamic.WeightedRoundRobin{
+									Services: []dynamic.WRRService{
+										{Name: "stable", Weight: Int(99)},
+										{Name: "canary", Weight: Int(1)},
+									},
+									Ramp: &dynamic.WeightRamp{
+										Service:       "canary",
+										TargetWeight:  99,
+										Duration:      ptypes.Duration(30 * time.Minute),
+										Interval:      ptypes.Duration(30 * time.Second),
+										MaxErrorRatio: 0.1,
+									},
+								},
+							},
+							UsedBy: []string{"foo@myprovider"},
+						}
+						si.UpdateRampStatus(runtime.RampStatus{
+							Service:    "canary",
+							State:      runtime.RampRunning,
+							Weight:     50,
+							Progress:   0.5,
+							ErrorRatio: 0.01,
+							StartedAt:  time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC),
+						})
+						return si
+					}(),
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/service-canary.json",
+			},
+		},
 		{
 			desc: "one service by id, that does not exist",
 			path: "/api/http/services/nono@myprovider",
@@ -573,6 +655,79 @@ func TestHandler_HTTP(t *testing.T) {
 				statusCode: http.StatusNotFound,
 			},
 		},
+		{
+			desc: "health checks of one service by id",
+			path: "/api/http/services/bar@myprovider/healthchecks",
+			conf: runtime.Configuration{
+				Services: map[string]*runtime.ServiceInfo{
+					"bar@myprovider": func() *runtime.ServiceInfo {
+						si := &runtime.ServiceInfo{
+							Service: &dynamic.Service{
+								LoadBalancer: &dynamic.ServersLoadBalancer{
+									PassHostHeader: Bool(true),
+									Servers: []dynamic.Server{
+										{
+											URL: "http://127.0.0.1",
+										},
+										{
+											URL: "http://127.0.0.2",
+										},
+									},
+								},
+							},
+							UsedBy: []string{"foo@myprovider"},
+						}
+						si.UpdateServerHealthCheck("http://127.0.0.1", runtime.ServerHealthCheck{
+							Status:      "UP",
+							Passed:      10,
+							LastLatency: 0.002,
+							LastCheck:   time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC),
+						})
+						si.UpdateServerHealthCheck("http://127.0.0.2", runtime.ServerHealthCheck{
+							Status:              "DOWN",
+							Passed:              7,
+							Failed:              3,
+							ConsecutiveFailures: 3,
+							LastLatency:         5,
+							LastError:           "HTTP request failed: context deadline exceeded",
+							LastCheck:           time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC),
+						})
+						return si
+					}(),
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/service-bar-healthchecks.json",
+			},
+		},
+		{
+			desc: "health checks of one service by id, without health checks",
+			path: "/api/http/services/bar@myprovider/healthchecks",
+			conf: runtime.Configuration{
+				Services: map[string]*runtime.ServiceInfo{
+					"bar@myprovider": {
+						Service: &dynamic.Service{
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/service-bar-healthchecks-empty.json",
+			},
+		},
+		{
+			desc: "health checks of one service by id, that does not exist",
+			path: "/api/http/services/nono@myprovider/healthchecks",
+			conf: runtime.Configuration{},
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
 		{
 			desc: "all middlewares, but no config",
 			path: "/api/http/middlewares",
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// getLabelErrors lists the label errors, of all the providers,
+// or of the provider given in the path of the request.
+func (h Handler) getLabelErrors(rw http.ResponseWriter, request *http.Request) {
+	providerID := mux.Vars(request)["providerID"]
+
+	results := make([]*runtime.LabelErrorInfo, 0, len(h.runtimeConfiguration.LabelErrors))
+
+	criterion := newSearchCriterion(request.URL.Query())
+
+	for _, lei := range h.runtimeConfiguration.LabelErrors {
+		if providerID != "" && lei.Provider != providerID {
+			continue
+		}
+
+		if keepLabelError(lei, criterion) {
+			results = append(results, lei)
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func keepLabelError(item *runtime.LabelErrorInfo, criterion *searchCriterion) bool {
+	if criterion == nil {
+		return true
+	}
+
+	return criterion.withStatus(item.Status) && criterion.searchIn(item.Provider, item.Source, item.Label, item.Reason)
+}
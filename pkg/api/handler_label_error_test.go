@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestHandler_LabelErrors(t *testing.T) {
+	type expected struct {
+		statusCode int
+		nextPage   string
+		jsonFile   string
+	}
+
+	labelErrors := []*runtime.LabelErrorInfo{
+		{
+			LabelError: dynamic.LabelError{Provider: "docker", Source: "container-1", Label: "traefik.http.routers.foo.prority", Reason: "field not found, node: prority"},
+			Status:     runtime.StatusDisabled,
+		},
+		{
+			LabelError: dynamic.LabelError{Provider: "docker", Source: "container-2", Label: "traefik.docker.netwrok", Reason: "unknown label"},
+			Status:     runtime.StatusDisabled,
+		},
+		{
+			LabelError: dynamic.LabelError{Provider: "marathon", Source: "/app", Label: "traefik.tcp.routers.bar.rule", Reason: "unknown label"},
+			Status:     runtime.StatusDisabled,
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		conf     runtime.Configuration
+		expected expected
+	}{
+		{
+			desc: "all label errors, but no config",
+			path: "/api/labelerrors",
+			conf: runtime.Configuration{},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/labelerrors-empty.json",
+			},
+		},
+		{
+			desc: "all label errors",
+			path: "/api/labelerrors",
+			conf: runtime.Configuration{LabelErrors: labelErrors},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/labelerrors.json",
+			},
+		},
+		{
+			desc: "label errors filtered by search",
+			path: "/api/labelerrors?search=unknown",
+			conf: runtime.Configuration{LabelErrors: labelErrors},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/labelerrors-filtered-search.json",
+			},
+		},
+		{
+			desc: "label errors of a provider",
+			path: "/api/providers/docker/labelerrors",
+			conf: runtime.Configuration{LabelErrors: labelErrors},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/labelerrors-provider.json",
+			},
+		},
+		{
+			desc: "all label errors, pagination, 1 res per page, want page 2",
+			path: "/api/labelerrors?page=2&per_page=1",
+			conf: runtime.Configuration{LabelErrors: labelErrors},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "3",
+				jsonFile:   "testdata/labelerrors-page2.json",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &test.conf)
+			server := httptest.NewServer(handler.createRouter())
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected.nextPage, resp.Header.Get(nextPageHeader))
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = os.WriteFile(test.expected.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
@@ -30,11 +30,12 @@ type features struct {
 }
 
 type overview struct {
-	HTTP      schemeOverview `json:"http"`
-	TCP       schemeOverview `json:"tcp"`
-	UDP       schemeOverview `json:"udp"`
-	Features  features       `json:"features,omitempty"`
-	Providers []string       `json:"providers,omitempty"`
+	HTTP           schemeOverview          `json:"http"`
+	TCP            schemeOverview          `json:"tcp"`
+	UDP            schemeOverview          `json:"udp"`
+	Features       features                `json:"features,omitempty"`
+	Providers      []string                `json:"providers,omitempty"`
+	RejectedReload *runtime.RejectedReload `json:"rejectedReload,omitempty"`
 }
 
 func (h Handler) getOverview(rw http.ResponseWriter, request *http.Request) {
@@ -53,8 +54,9 @@ func (h Handler) getOverview(rw http.ResponseWriter, request *http.Request) {
 			Routers:  getUDPRouterSection(h.runtimeConfiguration.UDPRouters),
 			Services: getUDPServiceSection(h.runtimeConfiguration.UDPServices),
 		},
-		Features:  getFeatures(h.staticConfig),
-		Providers: getProviders(h.staticConfig),
+		Features:       getFeatures(h.staticConfig),
+		Providers:      getProviders(h.staticConfig),
+		RejectedReload: h.runtimeConfiguration.RejectedReload,
 	}
 
 	rw.Header().Set("Content-Type", "application/json")
@@ -66,6 +68,93 @@ func (h Handler) getOverview(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+// healthRollup counts the services by health.
+// A service is healthy when all its servers are up, degraded when only some of them are up,
+// and down when none of them are up, or when the service could not be built.
+type healthRollup struct {
+	Healthy  int `json:"healthy"`
+	Degraded int `json:"degraded"`
+	Down     int `json:"down"`
+}
+
+type healthOverview struct {
+	healthRollup
+	Providers map[string]*healthRollup `json:"providers,omitempty"`
+}
+
+func (h Handler) getHealthOverview(rw http.ResponseWriter, request *http.Request) {
+	result := healthOverview{Providers: make(map[string]*healthRollup)}
+
+	for name, svc := range h.runtimeConfiguration.Services {
+		health := getServiceHealth(svc)
+		if health == "" {
+			continue
+		}
+
+		providerName := getProviderName(name)
+		if result.Providers[providerName] == nil {
+			result.Providers[providerName] = &healthRollup{}
+		}
+
+		result.add(health)
+		result.Providers[providerName].add(health)
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+const (
+	healthHealthy  = "healthy"
+	healthDegraded = "degraded"
+	healthDown     = "down"
+)
+
+func (r *healthRollup) add(health string) {
+	switch health {
+	case healthHealthy:
+		r.Healthy++
+	case healthDegraded:
+		r.Degraded++
+	case healthDown:
+		r.Down++
+	}
+}
+
+// getServiceHealth returns the health of the service from the statuses of its servers,
+// or an empty string when the health of its servers is not known, i.e. when the service has no health check.
+func getServiceHealth(svc *runtime.ServiceInfo) string {
+	if svc.Status == runtime.StatusDisabled {
+		return healthDown
+	}
+
+	statuses := svc.GetAllStatus()
+	if len(statuses) == 0 {
+		return ""
+	}
+
+	var up int
+	for _, status := range statuses {
+		if status == "UP" {
+			up++
+		}
+	}
+
+	switch up {
+	case len(statuses):
+		return healthHealthy
+	case 0:
+		return healthDown
+	default:
+		return healthDegraded
+	}
+}
+
 func getHTTPRouterSection(routers map[string]*runtime.RouterInfo) *section {
 	var countErrors int
 	var countWarnings int
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -222,6 +223,67 @@ func TestHandler_Overview(t *testing.T) {
 						Status: runtime.StatusDisabled,
 					},
 				},
+				UDPServices: map[string]*runtime.UDPServiceInfo{
+					"udpfoo-service@myprovider": {
+						UDPService: &dynamic.UDPService{
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								Servers: []dynamic.UDPServer{
+									{
+										Address: "127.0.0.1",
+									},
+								},
+							},
+						},
+						Status: runtime.StatusEnabled,
+					},
+					"udpbar-service@myprovider": {
+						UDPService: &dynamic.UDPService{
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								Servers: []dynamic.UDPServer{
+									{
+										Address: "127.0.0.2",
+									},
+								},
+							},
+						},
+						Status: runtime.StatusWarning,
+					},
+					"udpfii-service@myprovider": {
+						UDPService: &dynamic.UDPService{
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								Servers: []dynamic.UDPServer{
+									{
+										Address: "127.0.0.2",
+									},
+								},
+							},
+						},
+						Status: runtime.StatusDisabled,
+					},
+				},
+				UDPRouters: map[string]*runtime.UDPRouterInfo{
+					"udpbar@myprovider": {
+						UDPRouter: &dynamic.UDPRouter{
+							EntryPoints: []string{"udp"},
+							Service:     "udpfoo-service@myprovider",
+						},
+						Status: runtime.StatusEnabled,
+					},
+					"udptest@myprovider": {
+						UDPRouter: &dynamic.UDPRouter{
+							EntryPoints: []string{"udp"},
+							Service:     "udpfoo-service@myprovider",
+						},
+						Status: runtime.StatusWarning,
+					},
+					"udpfoo@myprovider": {
+						UDPRouter: &dynamic.UDPRouter{
+							EntryPoints: []string{"udp"},
+							Service:     "udpfoo-service@myprovider",
+						},
+						Status: runtime.StatusDisabled,
+					},
+				},
 			},
 			expected: expected{
 				statusCode: http.StatusOK,
@@ -316,3 +378,61 @@ func TestHandler_Overview(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_HealthOverview(t *testing.T) {
+	newServiceInfo := func(status string, serverStatuses ...string) *runtime.ServiceInfo {
+		si := &runtime.ServiceInfo{
+			Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{}},
+			Status:  status,
+		}
+		for i, serverStatus := range serverStatuses {
+			si.UpdateServerStatus(fmt.Sprintf("http://127.0.0.%d", i+1), serverStatus)
+		}
+		return si
+	}
+
+	rtConf := &runtime.Configuration{
+		Services: map[string]*runtime.ServiceInfo{
+			"healthy@docker":   newServiceInfo(runtime.StatusEnabled, "UP", "UP"),
+			"degraded@docker":  newServiceInfo(runtime.StatusEnabled, "UP", "DOWN"),
+			"down@docker":      newServiceInfo(runtime.StatusEnabled, "DOWN"),
+			"healthy@file":     newServiceInfo(runtime.StatusEnabled, "UP"),
+			"disabled@file":    newServiceInfo(runtime.StatusDisabled),
+			"unchecked@file":   newServiceInfo(runtime.StatusEnabled),
+			"unchecked@docker": newServiceInfo(runtime.StatusWarning),
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	server := httptest.NewServer(handler.createRouter())
+	t.Cleanup(server.Close)
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/overview/health")
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+
+	contents, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	err = resp.Body.Close()
+	require.NoError(t, err)
+
+	jsonFile := "testdata/overview-health.json"
+	if *updateExpected {
+		var results interface{}
+		err := json.Unmarshal(contents, &results)
+		require.NoError(t, err)
+
+		newJSON, err := json.MarshalIndent(results, "", "\t")
+		require.NoError(t, err)
+
+		err = os.WriteFile(jsonFile, newJSON, 0o644)
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(jsonFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(contents))
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+)
+
+// Statuses of the providers.
+const (
+	providerStatusHealthy = "healthy"
+	providerStatusFailing = "failing"
+)
+
+type providerRepresentation struct {
+	provider.Health
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func (h Handler) getProviders(rw http.ResponseWriter, request *http.Request) {
+	health := h.providersHealth()
+
+	results := make([]providerRepresentation, 0, len(health))
+	for name, providerHealth := range health {
+		status := providerStatusHealthy
+		if providerHealth.ConsecutiveFailures > 0 {
+			status = providerStatusFailing
+		}
+
+		results = append(results, providerRepresentation{
+			Health: providerHealth,
+			Name:   name,
+			Status: status,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// filterProvidersHealth returns the liveness of the providers matching the scope.
+func filterProvidersHealth(health map[string]provider.Health, scope static.APIScope) map[string]provider.Health {
+	if len(scope.Providers) == 0 {
+		return health
+	}
+
+	filtered := make(map[string]provider.Health)
+	for name, providerHealth := range health {
+		if contains(scope.Providers, name) {
+			filtered[name] = providerHealth
+		}
+	}
+
+	return filtered
+}
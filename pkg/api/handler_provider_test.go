@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/provider"
+)
+
+func TestHandler_Providers(t *testing.T) {
+	type expected struct {
+		statusCode int
+		nextPage   string
+		jsonFile   string
+	}
+
+	refresh := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+	failure := time.Date(2021, time.March, 1, 10, 5, 0, 0, time.UTC)
+
+	providers := map[string]provider.Health{
+		"file": {
+			LastRefresh: &refresh,
+		},
+		"docker": {
+			LastRefresh:         &refresh,
+			LastError:           "connection refused",
+			LastErrorDate:       &failure,
+			ConsecutiveFailures: 2,
+			FailingSince:        &failure,
+		},
+		"kubernetescrd": {
+			LastRefresh: &refresh,
+		},
+	}
+
+	testCases := []struct {
+		desc      string
+		path      string
+		providers map[string]provider.Health
+		scope     *static.APIScope
+		expected  expected
+	}{
+		{
+			desc:      "all providers, but no health reported",
+			path:      "/api/providers",
+			providers: map[string]provider.Health{},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/providers-empty.json",
+			},
+		},
+		{
+			desc:      "all providers",
+			path:      "/api/providers",
+			providers: providers,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/providers.json",
+			},
+		},
+		{
+			desc:      "all providers, pagination, 1 res per page, want page 2",
+			path:      "/api/providers?page=2&per_page=1",
+			providers: providers,
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "3",
+				jsonFile:   "testdata/providers-page2.json",
+			},
+		},
+		{
+			desc:      "providers filtered by scope",
+			path:      "/api/providers",
+			providers: providers,
+			scope:     &static.APIScope{Providers: []string{"docker", "file"}},
+			expected: expected{
+				statusCode: http.StatusOK,
+				nextPage:   "1",
+				jsonFile:   "testdata/providers-scoped.json",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, &runtime.Configuration{})
+			handler.providersHealth = func() map[string]provider.Health {
+				if test.scope != nil {
+					return filterProvidersHealth(test.providers, *test.scope)
+				}
+				return test.providers
+			}
+			server := httptest.NewServer(handler.createRouter())
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected.nextPage, resp.Header.Get(nextPageHeader))
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = os.WriteFile(test.expected.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
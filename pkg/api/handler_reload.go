@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// StaticReload is the outcome of a reload of the static configuration.
+type StaticReload struct {
+	// EntryPoints are the names of the added entry points.
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	// Providers are the names of the added providers.
+	Providers []string `json:"providers,omitempty"`
+	// RestartRequired are the options whose change is only applied by a restart.
+	RestartRequired []string `json:"restartRequired,omitempty"`
+}
+
+// StaticConfigurationReloader reloads the static configuration, applying the changes which do not require a restart.
+type StaticConfigurationReloader interface {
+	ReloadStaticConfiguration() (*StaticReload, error)
+	// StaticConfiguration returns the static configuration in use.
+	StaticConfiguration() static.Configuration
+}
+
+func (h Handler) reloadStaticConfiguration(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	reload, err := h.staticReloader.ReloadStaticConfiguration()
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(rw).Encode(reload)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
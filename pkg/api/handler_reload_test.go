@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+type staticReloaderMock struct {
+	reload *StaticReload
+	err    error
+	conf   static.Configuration
+}
+
+func (s staticReloaderMock) ReloadStaticConfiguration() (*StaticReload, error) {
+	return s.reload, s.err
+}
+
+func (s staticReloaderMock) StaticConfiguration() static.Configuration {
+	return s.conf
+}
+
+func TestHandler_ReloadStaticConfiguration(t *testing.T) {
+	staticConfig := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+
+	reloaded := &StaticReload{
+		EntryPoints:     []string{"websecure"},
+		Providers:       []string{"docker"},
+		RestartRequired: []string{"metrics"},
+	}
+
+	testCases := []struct {
+		desc           string
+		method         string
+		reloader       StaticConfigurationReloader
+		statusCode     int
+		expectedReload *StaticReload
+	}{
+		{
+			desc:           "reloaded",
+			method:         http.MethodPost,
+			reloader:       staticReloaderMock{reload: reloaded, conf: staticConfig},
+			statusCode:     http.StatusOK,
+			expectedReload: reloaded,
+		},
+		{
+			desc:       "reload error",
+			method:     http.MethodPost,
+			reloader:   staticReloaderMock{err: errors.New("invalid configuration"), conf: staticConfig},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			desc:       "wrong method",
+			method:     http.MethodGet,
+			reloader:   staticReloaderMock{reload: reloaded, conf: staticConfig},
+			statusCode: http.StatusMethodNotAllowed,
+		},
+		{
+			desc:       "no reloader",
+			method:     http.MethodPost,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewBuilder(staticConfig, nil, nil, test.reloader, nil, nil)(&runtime.Configuration{})
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(test.method, "/api/reload", nil))
+
+			require.Equal(t, test.statusCode, rec.Code)
+
+			if test.expectedReload == nil {
+				return
+			}
+
+			reload := &StaticReload{}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(reload))
+			assert.Equal(t, test.expectedReload, reload)
+		})
+	}
+}
+
+func TestNewBuilder_reloadedStaticConfiguration(t *testing.T) {
+	reloader := staticReloaderMock{
+		conf: static.Configuration{
+			API:    &static.API{},
+			Global: &static.Global{},
+			EntryPoints: static.EntryPoints{
+				"web":       {Address: ":80"},
+				"websecure": {Address: ":443"},
+			},
+		},
+	}
+
+	startup := static.Configuration{
+		API:         &static.API{},
+		Global:      &static.Global{},
+		EntryPoints: static.EntryPoints{"web": {Address: ":80"}},
+	}
+
+	handler := NewBuilder(startup, nil, nil, reloader, nil, nil)(&runtime.Configuration{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/entrypoints/websecure", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -118,6 +118,27 @@ func TestHandler_RawData(t *testing.T) {
 						},
 					},
 				},
+				UDPServices: map[string]*runtime.UDPServiceInfo{
+					"udpfoo-service@myprovider": {
+						UDPService: &dynamic.UDPService{
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								Servers: []dynamic.UDPServer{
+									{
+										Address: "127.0.0.1",
+									},
+								},
+							},
+						},
+					},
+				},
+				UDPRouters: map[string]*runtime.UDPRouterInfo{
+					"udpbar@myprovider": {
+						UDPRouter: &dynamic.UDPRouter{
+							EntryPoints: []string{"udp"},
+							Service:     "udpfoo-service@myprovider",
+						},
+					},
+				},
 			},
 			expected: expected{
 				statusCode: http.StatusOK,
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+// maxTopRouters is the maximum number of routers listed in the traffic statistics of an entry point.
+const maxTopRouters = 10
+
+// TrafficStatistics gives access to the live traffic recorded by the metrics instrumentation.
+type TrafficStatistics interface {
+	// Snapshot returns the live traffic of the entry points and of the routers.
+	Snapshot() metrics.TrafficSnapshot
+}
+
+// entryPointStatsRepresentation is the live traffic of an entry point.
+type entryPointStatsRepresentation struct {
+	EntryPoint        string                      `json:"entryPoint"`
+	Window            string                      `json:"window"`
+	OpenConnections   float64                     `json:"openConnections"`
+	Requests          float64                     `json:"requests"`
+	RequestsPerSecond float64                     `json:"requestsPerSecond"`
+	TopRouters        []routerStatsRepresentation `json:"topRouters"`
+}
+
+// routerStatsRepresentation is the live traffic of a router.
+type routerStatsRepresentation struct {
+	Name              string  `json:"name"`
+	Requests          float64 `json:"requests"`
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+}
+
+// getEntryPointStats returns the open connections and the requests over the rolling window of an entry point,
+// with the routers of the entry point which received the most requests over the window.
+func (h Handler) getEntryPointStats(rw http.ResponseWriter, request *http.Request) {
+	entryPointID := mux.Vars(request)["entryPointID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	if _, ok := h.staticConfig.EntryPoints[entryPointID]; !ok {
+		writeError(rw, fmt.Sprintf("entry point not found: %s", entryPointID), http.StatusNotFound)
+		return
+	}
+
+	snapshot := h.trafficStatistics.Snapshot()
+	seconds := snapshot.Window.Seconds()
+
+	result := entryPointStatsRepresentation{
+		EntryPoint:        entryPointID,
+		Window:            snapshot.Window.String(),
+		OpenConnections:   snapshot.EntryPointOpenConns[entryPointID],
+		Requests:          snapshot.EntryPointRequests[entryPointID],
+		RequestsPerSecond: snapshot.EntryPointRequests[entryPointID] / seconds,
+		TopRouters:        []routerStatsRepresentation{},
+	}
+
+	for name, requests := range snapshot.RouterRequests {
+		rt, ok := h.runtimeConfiguration.Routers[name]
+		if !ok || !contains(rt.EntryPoints, entryPointID) {
+			continue
+		}
+
+		result.TopRouters = append(result.TopRouters, routerStatsRepresentation{
+			Name:              name,
+			Requests:          requests,
+			RequestsPerSecond: requests / seconds,
+		})
+	}
+
+	sort.Slice(result.TopRouters, func(i, j int) bool {
+		if result.TopRouters[i].Requests == result.TopRouters[j].Requests {
+			return result.TopRouters[i].Name < result.TopRouters[j].Name
+		}
+		return result.TopRouters[i].Requests > result.TopRouters[j].Requests
+	})
+
+	if len(result.TopRouters) > maxTopRouters {
+		result.TopRouters = result.TopRouters[:maxTopRouters]
+	}
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.FromContext(request.Context()).Error(err)
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
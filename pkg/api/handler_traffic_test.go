@@ -0,0 +1,189 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+)
+
+type trafficStatisticsMock metrics.TrafficSnapshot
+
+func (t trafficStatisticsMock) Snapshot() metrics.TrafficSnapshot {
+	return metrics.TrafficSnapshot(t)
+}
+
+func TestHandler_EntryPointStats(t *testing.T) {
+	snapshot := trafficStatisticsMock{
+		Window:              10 * time.Second,
+		EntryPointOpenConns: map[string]float64{"web": 3},
+		EntryPointRequests:  map[string]float64{"web": 150, "websecure": 20},
+		RouterRequests: map[string]float64{
+			"foo@file":     100,
+			"bar@file":     30,
+			"baz@file":     30,
+			"secure@file":  20,
+			"removed@file": 5,
+		},
+	}
+
+	routers := map[string]*runtime.RouterInfo{
+		"foo@file": {
+			Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "foo", Rule: "Host(`foo.bar`)"},
+			Status: runtime.StatusEnabled,
+		},
+		"bar@file": {
+			Router: &dynamic.Router{EntryPoints: []string{"web", "websecure"}, Service: "bar", Rule: "Host(`bar.foo`)"},
+			Status: runtime.StatusEnabled,
+		},
+		"baz@file": {
+			Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "baz", Rule: "Host(`baz.foo`)"},
+			Status: runtime.StatusEnabled,
+		},
+		"secure@file": {
+			Router: &dynamic.Router{EntryPoints: []string{"websecure"}, Service: "secure", Rule: "Host(`secure.foo`)"},
+			Status: runtime.StatusEnabled,
+		},
+		"idle@file": {
+			Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "idle", Rule: "Host(`idle.foo`)"},
+			Status: runtime.StatusEnabled,
+		},
+	}
+
+	manyRouters := make(map[string]*runtime.RouterInfo)
+	manySnapshot := trafficStatisticsMock{Window: time.Minute, RouterRequests: make(map[string]float64)}
+	for i := 1; i <= 12; i++ {
+		name := fmt.Sprintf("router%02d@file", i)
+		manyRouters[name] = &runtime.RouterInfo{
+			Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "foo", Rule: "Host(`foo.bar`)"},
+			Status: runtime.StatusEnabled,
+		}
+		manySnapshot.RouterRequests[name] = float64(60 * i)
+	}
+
+	testCases := []struct {
+		desc       string
+		path       string
+		traffic    TrafficStatistics
+		routers    map[string]*runtime.RouterInfo
+		statusCode int
+		expected   string
+	}{
+		{
+			desc:       "traffic statistics disabled",
+			path:       "/api/entrypoints/web/stats",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "unknown entry point",
+			path:       "/api/entrypoints/unknown/stats",
+			traffic:    snapshot,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			desc:       "entry point",
+			path:       "/api/entrypoints/web/stats",
+			traffic:    snapshot,
+			routers:    routers,
+			statusCode: http.StatusOK,
+			expected: `{
+				"entryPoint": "web",
+				"window": "10s",
+				"openConnections": 3,
+				"requests": 150,
+				"requestsPerSecond": 15,
+				"topRouters": [
+					{"name": "foo@file", "requests": 100, "requestsPerSecond": 10},
+					{"name": "bar@file", "requests": 30, "requestsPerSecond": 3},
+					{"name": "baz@file", "requests": 30, "requestsPerSecond": 3}
+				]
+			}`,
+		},
+		{
+			desc:       "entry point without traffic",
+			path:       "/api/entrypoints/traefik/stats",
+			traffic:    snapshot,
+			routers:    routers,
+			statusCode: http.StatusOK,
+			expected: `{
+				"entryPoint": "traefik",
+				"window": "10s",
+				"openConnections": 0,
+				"requests": 0,
+				"requestsPerSecond": 0,
+				"topRouters": []
+			}`,
+		},
+		{
+			desc:       "top routers",
+			path:       "/api/entrypoints/web/stats",
+			traffic:    manySnapshot,
+			routers:    manyRouters,
+			statusCode: http.StatusOK,
+			expected: `{
+				"entryPoint": "web",
+				"window": "1m0s",
+				"openConnections": 0,
+				"requests": 0,
+				"requestsPerSecond": 0,
+				"topRouters": [
+					{"name": "router12@file", "requests": 720, "requestsPerSecond": 12},
+					{"name": "router11@file", "requests": 660, "requestsPerSecond": 11},
+					{"name": "router10@file", "requests": 600, "requestsPerSecond": 10},
+					{"name": "router09@file", "requests": 540, "requestsPerSecond": 9},
+					{"name": "router08@file", "requests": 480, "requestsPerSecond": 8},
+					{"name": "router07@file", "requests": 420, "requestsPerSecond": 7},
+					{"name": "router06@file", "requests": 360, "requestsPerSecond": 6},
+					{"name": "router05@file", "requests": 300, "requestsPerSecond": 5},
+					{"name": "router04@file", "requests": 240, "requestsPerSecond": 4},
+					{"name": "router03@file", "requests": 180, "requestsPerSecond": 3}
+				]
+			}`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			staticConfig := static.Configuration{
+				API:    &static.API{},
+				Global: &static.Global{},
+				EntryPoints: map[string]*static.EntryPoint{
+					"web":       {Address: ":80"},
+					"websecure": {Address: ":443"},
+					"traefik":   {Address: ":8080"},
+				},
+			}
+
+			rtConf := &runtime.Configuration{Routers: test.routers}
+			handler := NewBuilder(staticConfig, nil, nil, nil, nil, test.traffic)(rtConf)
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			require.Equal(t, test.statusCode, rw.Code)
+
+			if test.expected == "" {
+				return
+			}
+
+			assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+
+			body, err := io.ReadAll(rw.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.expected, string(body))
+		})
+	}
+}
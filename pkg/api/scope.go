@@ -0,0 +1,147 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/provider"
+)
+
+// NewScopedBuilder returns a http.Handler builder based on runtime.Configuration,
+// which only exposes the objects matching the given scope.
+// The debug endpoints are never exposed by a scoped handler.
+func NewScopedBuilder(staticConfig static.Configuration, scope static.APIScope) func(*runtime.Configuration) http.Handler {
+	return func(configuration *runtime.Configuration) http.Handler {
+		handler := New(staticConfig, filterConfiguration(configuration, scope))
+		handler.debug = false
+		handler.providersHealth = func() map[string]provider.Health {
+			return filterProvidersHealth(provider.ProvidersHealth(), scope)
+		}
+
+		return handler.createRouter()
+	}
+}
+
+// filterConfiguration returns a runtime configuration only holding the objects matching the scope.
+// The objects themselves are shared with the given configuration.
+func filterConfiguration(conf *runtime.Configuration, scope static.APIScope) *runtime.Configuration {
+	filtered := &runtime.Configuration{
+		Routers:        make(map[string]*runtime.RouterInfo),
+		Middlewares:    make(map[string]*runtime.MiddlewareInfo),
+		TCPMiddlewares: make(map[string]*runtime.TCPMiddlewareInfo),
+		Services:       make(map[string]*runtime.ServiceInfo),
+		TCPRouters:     make(map[string]*runtime.TCPRouterInfo),
+		TCPServices:    make(map[string]*runtime.TCPServiceInfo),
+		UDPRouters:     make(map[string]*runtime.UDPRouterInfo),
+		UDPServices:    make(map[string]*runtime.UDPServiceInfo),
+	}
+
+	if conf == nil {
+		return filtered
+	}
+
+	namespaces := make(map[string]map[string][]string)
+	for _, element := range conf.Namespaces {
+		if namespaces[element.Kind] == nil {
+			namespaces[element.Kind] = make(map[string][]string)
+		}
+		namespaces[element.Kind][element.Name] = append(namespaces[element.Kind][element.Name], element.Namespace)
+	}
+
+	for name, rt := range conf.Routers {
+		if inScope("router", name, scope, namespaces) {
+			filtered.Routers[name] = rt
+		}
+	}
+
+	for name, mi := range conf.Middlewares {
+		if inScope("middleware", name, scope, namespaces) {
+			filtered.Middlewares[name] = mi
+		}
+	}
+
+	for name, mi := range conf.TCPMiddlewares {
+		if inScope("tcpMiddleware", name, scope, namespaces) {
+			filtered.TCPMiddlewares[name] = mi
+		}
+	}
+
+	for name, si := range conf.Services {
+		if inScope("service", name, scope, namespaces) {
+			filtered.Services[name] = si
+		}
+	}
+
+	for name, rt := range conf.TCPRouters {
+		if inScope("tcpRouter", name, scope, namespaces) {
+			filtered.TCPRouters[name] = rt
+		}
+	}
+
+	for name, si := range conf.TCPServices {
+		if inScope("tcpService", name, scope, namespaces) {
+			filtered.TCPServices[name] = si
+		}
+	}
+
+	for name, rt := range conf.UDPRouters {
+		if inScope("udpRouter", name, scope, namespaces) {
+			filtered.UDPRouters[name] = rt
+		}
+	}
+
+	for name, si := range conf.UDPServices {
+		if inScope("udpService", name, scope, namespaces) {
+			filtered.UDPServices[name] = si
+		}
+	}
+
+	// The label providers do not have namespaces.
+	if len(scope.Namespaces) == 0 {
+		for _, lei := range conf.LabelErrors {
+			if len(scope.Providers) == 0 || contains(scope.Providers, lei.Provider) {
+				filtered.LabelErrors = append(filtered.LabelErrors, lei)
+			}
+		}
+	}
+
+	return filtered
+}
+
+// inScope reports whether the element of the given kind and qualified name (name@provider) matches the scope.
+// The namespaces of the scope are matched against the namespaces of the resources defining the element,
+// as recorded by the providers, so the elements of the providers without namespaces never match them.
+func inScope(kind, qualifiedName string, scope static.APIScope, namespaces map[string]map[string][]string) bool {
+	parts := strings.SplitN(qualifiedName, "@", 2)
+	if len(parts) != 2 {
+		return false
+	}
+
+	if len(scope.Providers) > 0 && !contains(scope.Providers, parts[1]) {
+		return false
+	}
+
+	if len(scope.Namespaces) == 0 {
+		return true
+	}
+
+	for _, namespace := range namespaces[kind][qualifiedName] {
+		if contains(scope.Namespaces, namespace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
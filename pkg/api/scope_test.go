@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestInScope(t *testing.T) {
+	namespaces := map[string]map[string][]string{
+		"router": {
+			"team-a-whoami-6f97c4b3a2ad4ba7c9b5@kubernetescrd": {"team-a"},
+			"team-b-whoami-6f97c4b3a2ad4ba7c9b5@kubernetescrd": {"team-b"},
+			"team-whoami@kubernetescrd":                        {"team"},
+			"shared@kubernetescrd":                             {"team-a", "team-b"},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		kind     string
+		name     string
+		scope    static.APIScope
+		expected bool
+	}{
+		{
+			desc:     "empty scope",
+			kind:     "router",
+			name:     "foo@file",
+			expected: true,
+		},
+		{
+			desc:     "unqualified name",
+			kind:     "router",
+			name:     "foo",
+			expected: false,
+		},
+		{
+			desc:     "matching provider",
+			kind:     "router",
+			name:     "foo@file",
+			scope:    static.APIScope{Providers: []string{"docker", "file"}},
+			expected: true,
+		},
+		{
+			desc:     "other provider",
+			kind:     "router",
+			name:     "foo@docker",
+			scope:    static.APIScope{Providers: []string{"file"}},
+			expected: false,
+		},
+		{
+			desc:     "matching namespace",
+			kind:     "router",
+			name:     "team-a-whoami-6f97c4b3a2ad4ba7c9b5@kubernetescrd",
+			scope:    static.APIScope{Namespaces: []string{"team-a"}},
+			expected: true,
+		},
+		{
+			desc:     "other namespace",
+			kind:     "router",
+			name:     "team-b-whoami-6f97c4b3a2ad4ba7c9b5@kubernetescrd",
+			scope:    static.APIScope{Namespaces: []string{"team-a"}},
+			expected: false,
+		},
+		{
+			desc:     "namespace is a prefix of the namespace of the element",
+			kind:     "router",
+			name:     "team-b-whoami-6f97c4b3a2ad4ba7c9b5@kubernetescrd",
+			scope:    static.APIScope{Namespaces: []string{"team"}},
+			expected: false,
+		},
+		{
+			desc:     "namespace is the exact namespace of the element",
+			kind:     "router",
+			name:     "team-whoami@kubernetescrd",
+			scope:    static.APIScope{Namespaces: []string{"team"}},
+			expected: true,
+		},
+		{
+			desc:     "element defined in several namespaces",
+			kind:     "router",
+			name:     "shared@kubernetescrd",
+			scope:    static.APIScope{Namespaces: []string{"team-b"}},
+			expected: true,
+		},
+		{
+			desc:     "namespace of an element of another kind",
+			kind:     "service",
+			name:     "team-a-whoami-6f97c4b3a2ad4ba7c9b5@kubernetescrd",
+			scope:    static.APIScope{Namespaces: []string{"team-a"}},
+			expected: false,
+		},
+		{
+			desc:     "element without namespace",
+			kind:     "router",
+			name:     "team-a-whoami@file",
+			scope:    static.APIScope{Namespaces: []string{"team-a"}},
+			expected: false,
+		},
+		{
+			desc:     "matching namespace and other provider",
+			kind:     "router",
+			name:     "team-a-whoami-6f97c4b3a2ad4ba7c9b5@kubernetescrd",
+			scope:    static.APIScope{Providers: []string{"kubernetes"}, Namespaces: []string{"team-a"}},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, inScope(test.kind, test.name, test.scope, namespaces))
+		})
+	}
+}
+
+func TestScopedHandler(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"team-a-foo@kubernetescrd": {
+				Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "team-a-foo@kubernetescrd", Rule: "Host(`foo.bar`)"},
+				Status: runtime.StatusEnabled,
+			},
+			"team-b-bar@kubernetescrd": {
+				Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "team-b-bar@kubernetescrd", Rule: "Host(`bar.bar`)"},
+				Status: runtime.StatusEnabled,
+			},
+			"baz@file": {
+				Router: &dynamic.Router{EntryPoints: []string{"web"}, Service: "baz@file", Rule: "Host(`baz.bar`)"},
+				Status: runtime.StatusEnabled,
+			},
+		},
+		Namespaces: []dynamic.ElementNamespace{
+			{Kind: "router", Name: "team-a-foo@kubernetescrd", Namespace: "team-a"},
+			{Kind: "router", Name: "team-b-bar@kubernetescrd", Namespace: "team-b"},
+		},
+	}
+
+	staticConfig := static.Configuration{API: &static.API{Debug: true}}
+	scope := static.APIScope{Namespaces: []string{"team-a"}}
+
+	handler := NewScopedBuilder(staticConfig, scope)(rtConf)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/http/routers", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var routers []struct {
+		Name string `json:"name"`
+	}
+	err := json.Unmarshal(rec.Body.Bytes(), &routers)
+	require.NoError(t, err)
+
+	require.Len(t, routers, 1)
+	assert.Equal(t, "team-a-foo@kubernetescrd", routers[0].Name)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/http/routers/baz@file", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// The unscoped configuration is left untouched.
+	assert.Len(t, rtConf.Routers, 3)
+}
@@ -0,0 +1,184 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
+)
+
+const (
+	acmeKey       = "acme"
+	resolversKey  = "resolvers"
+	challengesKey = "challenges"
+)
+
+const (
+	// httpChallengeTTL is the duration after which the stored HTTP challenges expire,
+	// longer than their validation, so that the challenges left over by a stopped node are removed.
+	httpChallengeTTL = 10 * time.Minute
+	// updateAttempts is the maximum number of attempts to update the data of a resolver modified concurrently.
+	updateAttempts = 10
+)
+
+var _ acme.Store = (*ACMEStore)(nil)
+
+// ACMEStore stores the ACME data of the certificate resolvers in the KV store of the cluster,
+// along with the pending HTTP challenges, so that any node can serve them.
+type ACMEStore struct {
+	kvClient store.Store
+	rootKey  string
+
+	lock sync.Mutex
+}
+
+// NewACMEStore creates a new ACMEStore, storing the data under the given root key.
+func NewACMEStore(kvClient store.Store, rootKey string) *ACMEStore {
+	return &ACMEStore{
+		kvClient: kvClient,
+		rootKey:  rootKey,
+	}
+}
+
+// GetAccount returns the account of the given resolver.
+func (s *ACMEStore) GetAccount(resolverName string) (*acme.Account, error) {
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedData.Account, nil
+}
+
+// SaveAccount stores the account of the given resolver.
+func (s *ACMEStore) SaveAccount(resolverName string, account *acme.Account) error {
+	return s.update(resolverName, func(storedData *acme.StoredData) {
+		storedData.Account = account
+	})
+}
+
+// GetBackupAccounts returns the backup accounts of the given resolver.
+func (s *ACMEStore) GetBackupAccounts(resolverName string) ([]*acme.Account, error) {
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedData.BackupAccounts, nil
+}
+
+// SaveBackupAccounts stores the backup accounts of the given resolver.
+func (s *ACMEStore) SaveBackupAccounts(resolverName string, accounts []*acme.Account) error {
+	return s.update(resolverName, func(storedData *acme.StoredData) {
+		storedData.BackupAccounts = accounts
+	})
+}
+
+// GetCertificates returns the certificates of the given resolver.
+func (s *ACMEStore) GetCertificates(resolverName string) ([]*acme.CertAndStore, error) {
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedData.Certificates, nil
+}
+
+// SaveCertificates stores the certificates of the given resolver.
+func (s *ACMEStore) SaveCertificates(resolverName string, certificates []*acme.CertAndStore) error {
+	return s.update(resolverName, func(storedData *acme.StoredData) {
+		storedData.Certificates = certificates
+	})
+}
+
+// SetHTTPChallenge stores the key authorization of an HTTP challenge.
+func (s *ACMEStore) SetHTTPChallenge(token, domain string, keyAuth []byte) error {
+	return s.kvClient.Put(s.challengeKey(token, domain), keyAuth, &store.WriteOptions{TTL: httpChallengeTTL})
+}
+
+// GetHTTPChallenge returns the key authorization of an HTTP challenge.
+func (s *ACMEStore) GetHTTPChallenge(token, domain string) ([]byte, error) {
+	pair, err := s.kvClient.Get(s.challengeKey(token, domain), nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot find challenge for token %s and domain %s: %w", token, domain, err)
+	}
+
+	return pair.Value, nil
+}
+
+// DeleteHTTPChallenge deletes an HTTP challenge.
+func (s *ACMEStore) DeleteHTTPChallenge(token, domain string) error {
+	err := s.kvClient.Delete(s.challengeKey(token, domain))
+	if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ACMEStore) get(resolverName string) (*acme.StoredData, error) {
+	storedData, _, err := s.getPair(resolverName)
+	return storedData, err
+}
+
+// getPair returns the stored data of the resolver, and the KV pair holding them, which is nil if there is none.
+func (s *ACMEStore) getPair(resolverName string) (*acme.StoredData, *store.KVPair, error) {
+	storedData := &acme.StoredData{}
+
+	pair, err := s.kvClient.Get(s.resolverKey(resolverName), nil)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return storedData, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := json.Unmarshal(pair.Value, storedData); err != nil {
+		return nil, nil, fmt.Errorf("unable to decode the ACME data of the resolver %s: %w", resolverName, err)
+	}
+
+	return storedData, pair, nil
+}
+
+// update applies the given change to the stored data of the resolver.
+// The data are updated atomically, and the change is applied again to the data modified concurrently by another node.
+func (s *ACMEStore) update(resolverName string, apply func(storedData *acme.StoredData)) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for attempt := 0; attempt < updateAttempts; attempt++ {
+		storedData, previous, err := s.getPair(resolverName)
+		if err != nil {
+			return err
+		}
+
+		apply(storedData)
+
+		value, err := json.Marshal(storedData)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = s.kvClient.AtomicPut(s.resolverKey(resolverName), value, previous, nil)
+		if errors.Is(err, store.ErrKeyModified) || errors.Is(err, store.ErrKeyExists) {
+			continue
+		}
+
+		return err
+	}
+
+	return fmt.Errorf("unable to update the ACME data of the resolver %s: modified concurrently %d times", resolverName, updateAttempts)
+}
+
+func (s *ACMEStore) resolverKey(resolverName string) string {
+	return path.Join(s.rootKey, resolversKey, resolverName)
+}
+
+func (s *ACMEStore) challengeKey(token, domain string) string {
+	return path.Join(s.rootKey, challengesKey, token, domain)
+}
@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/http01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/provider/acme"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestACMEStore(t *testing.T) {
+	acmeStore := NewACMEStore(newMemoryStore(), "traefik-cluster/acme")
+
+	account, err := acmeStore.GetAccount("le")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	certificates, err := acmeStore.GetCertificates("le")
+	require.NoError(t, err)
+	assert.Empty(t, certificates)
+
+	err = acmeStore.SaveAccount("le", &acme.Account{Email: "foo@example.com"})
+	require.NoError(t, err)
+
+	err = acmeStore.SaveBackupAccounts("le", []*acme.Account{{Email: "bar@example.com"}})
+	require.NoError(t, err)
+
+	err = acmeStore.SaveCertificates("le", []*acme.CertAndStore{{
+		Certificate: acme.Certificate{Domain: types.Domain{Main: "example.com"}, Certificate: []byte("cert"), Key: []byte("key")},
+		Store:       "default",
+	}})
+	require.NoError(t, err)
+
+	err = acmeStore.SaveAccount("other", &acme.Account{Email: "other@example.com"})
+	require.NoError(t, err)
+
+	account, err = acmeStore.GetAccount("le")
+	require.NoError(t, err)
+	assert.Equal(t, "foo@example.com", account.Email)
+
+	backupAccounts, err := acmeStore.GetBackupAccounts("le")
+	require.NoError(t, err)
+	require.Len(t, backupAccounts, 1)
+	assert.Equal(t, "bar@example.com", backupAccounts[0].Email)
+
+	certificates, err = acmeStore.GetCertificates("le")
+	require.NoError(t, err)
+	require.Len(t, certificates, 1)
+	assert.Equal(t, "example.com", certificates[0].Certificate.Domain.Main)
+	assert.Equal(t, []byte("cert"), certificates[0].Certificate.Certificate)
+
+	account, err = acmeStore.GetAccount("other")
+	require.NoError(t, err)
+	assert.Equal(t, "other@example.com", account.Email)
+}
+
+func TestACMEStore_concurrentUpdate(t *testing.T) {
+	kvStore := newMemoryStore()
+	acmeStore := NewACMEStore(kvStore, "traefik-cluster/acme")
+	otherStore := NewACMEStore(kvStore, "traefik-cluster/acme")
+
+	err := acmeStore.SaveAccount("le", &acme.Account{Email: "foo@example.com"})
+	require.NoError(t, err)
+
+	// The certificates are saved by another node while the account is being saved.
+	var attempts int
+	err = acmeStore.update("le", func(storedData *acme.StoredData) {
+		attempts++
+		if attempts == 1 {
+			require.NoError(t, otherStore.SaveCertificates("le", []*acme.CertAndStore{{
+				Certificate: acme.Certificate{Domain: types.Domain{Main: "example.com"}},
+				Store:       "default",
+			}}))
+		}
+
+		storedData.Account = &acme.Account{Email: "bar@example.com"}
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, attempts)
+
+	account, err := acmeStore.GetAccount("le")
+	require.NoError(t, err)
+	assert.Equal(t, "bar@example.com", account.Email)
+
+	certificates, err := acmeStore.GetCertificates("le")
+	require.NoError(t, err)
+	require.Len(t, certificates, 1)
+	assert.Equal(t, "example.com", certificates[0].Certificate.Domain.Main)
+}
+
+func TestACMEStore_httpChallenges(t *testing.T) {
+	kvStore := newMemoryStore()
+
+	leader := acme.NewChallengeHTTP()
+	leader.SetStore(NewACMEStore(kvStore, "traefik-cluster/acme"))
+
+	follower := acme.NewChallengeHTTP()
+	follower.SetStore(NewACMEStore(kvStore, "traefik-cluster/acme"))
+
+	require.NoError(t, leader.Present("example.com", "token", "keyAuth"))
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com"+http01.ChallengePath("token"), nil)
+	rw := httptest.NewRecorder()
+	follower.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "keyAuth", rw.Body.String())
+	assert.Equal(t, httpChallengeTTL, kvStore.ttls["traefik-cluster/acme/challenges/token/example.com"])
+
+	require.NoError(t, leader.CleanUp("example.com", "token", "keyAuth"))
+
+	_, err := kvStore.Get("traefik-cluster/acme/challenges/token/example.com", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,383 @@
+// Package cluster coordinates the Traefik instances sharing a KV store:
+// the instances register themselves with their runtime state, and elect a leader performing the ACME operations.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/abronan/valkeyrie"
+	"github.com/abronan/valkeyrie/store"
+	"github.com/abronan/valkeyrie/store/consul"
+	etcdv3 "github.com/abronan/valkeyrie/store/etcd/v3"
+	"github.com/abronan/valkeyrie/store/redis"
+	"github.com/abronan/valkeyrie/store/zookeeper"
+	"github.com/cenkalti/backoff/v4"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/job"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/version"
+)
+
+const (
+	nodesKey  = "nodes"
+	leaderKey = "leader"
+)
+
+// Configuration holds the cluster mode configuration.
+type Configuration struct {
+	Store     string           `description:"KV store holding the state of the cluster: consul, etcd, zookeeper or redis." json:"store,omitempty" toml:"store,omitempty" yaml:"store,omitempty" export:"true"`
+	Endpoints []string         `description:"KV store endpoints." json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	Username  string           `description:"KV store username." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password  string           `description:"KV store password." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	TLS       *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	RootKey   string           `description:"Root key of the state of the cluster in the KV store." json:"rootKey,omitempty" toml:"rootKey,omitempty" yaml:"rootKey,omitempty" export:"true"`
+	NodeName  string           `description:"Name of the instance in the cluster. Defaults to the hostname." json:"nodeName,omitempty" toml:"nodeName,omitempty" yaml:"nodeName,omitempty" export:"true"`
+	TTL       ptypes.Duration  `description:"Duration after which an instance, or the leader, which stopped refreshing its state is considered gone." json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *Configuration) SetDefaults() {
+	c.Store = "consul"
+	c.RootKey = "traefik-cluster"
+	c.TTL = ptypes.Duration(30 * time.Second)
+}
+
+// NodeInfo is the state published by an instance of the cluster.
+type NodeInfo struct {
+	Name      string       `json:"name"`
+	Version   string       `json:"version,omitempty"`
+	Leader    bool         `json:"leader"`
+	StartedAt time.Time    `json:"startedAt"`
+	LastSeen  time.Time    `json:"lastSeen"`
+	Runtime   *RuntimeInfo `json:"runtime,omitempty"`
+}
+
+// RuntimeInfo counts the elements of the runtime configuration of an instance, by status.
+type RuntimeInfo struct {
+	HTTP SchemeInfo `json:"http"`
+	TCP  SchemeInfo `json:"tcp"`
+	UDP  SchemeInfo `json:"udp"`
+}
+
+// SchemeInfo counts the routers, services and middlewares of a protocol.
+type SchemeInfo struct {
+	Routers     Counters `json:"routers"`
+	Services    Counters `json:"services"`
+	Middlewares Counters `json:"middlewares"`
+}
+
+// Counters are the numbers of elements, of elements with warnings, and of disabled elements.
+type Counters struct {
+	Total    int `json:"total"`
+	Warnings int `json:"warnings"`
+	Errors   int `json:"errors"`
+}
+
+func (c *Counters) add(status string) {
+	c.Total++
+
+	switch status {
+	case runtime.StatusWarning:
+		c.Warnings++
+	case runtime.StatusDisabled:
+		c.Errors++
+	}
+}
+
+// Node is the instance of Traefik registered in the cluster.
+type Node struct {
+	name      string
+	rootKey   string
+	ttl       time.Duration
+	startedAt time.Time
+	kvClient  store.Store
+
+	mu      sync.RWMutex
+	leader  bool
+	runtime *RuntimeInfo
+}
+
+// New connects to the KV store of the cluster, and creates the node of this instance.
+func New(ctx context.Context, config *Configuration) (*Node, error) {
+	kvClient, err := createKVClient(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the KV store of the cluster: %w", err)
+	}
+
+	name := config.NodeName
+	if name == "" {
+		name, err = os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the node name from the hostname: %w", err)
+		}
+	}
+
+	return newNode(kvClient, config.RootKey, name, time.Duration(config.TTL)), nil
+}
+
+func newNode(kvClient store.Store, rootKey, name string, ttl time.Duration) *Node {
+	return &Node{
+		name:      name,
+		rootKey:   rootKey,
+		ttl:       ttl,
+		startedAt: time.Now(),
+		kvClient:  kvClient,
+	}
+}
+
+// Name returns the name of the node.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// IsLeader returns whether the node is the leader of the cluster.
+func (n *Node) IsLeader() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.leader
+}
+
+// ACMEStore returns the store sharing the ACME accounts, certificates and HTTP challenges between the nodes.
+func (n *Node) ACMEStore() *ACMEStore {
+	return NewACMEStore(n.kvClient, path.Join(n.rootKey, acmeKey))
+}
+
+// SetRuntimeConfiguration updates the runtime state published by the node.
+func (n *Node) SetRuntimeConfiguration(conf *runtime.Configuration) {
+	info := newRuntimeInfo(conf)
+
+	n.mu.Lock()
+	n.runtime = info
+	n.mu.Unlock()
+}
+
+// Run campaigns for the leadership, and publishes the state of the node until the context is done.
+// The node is then unregistered, and resigns if it is the leader.
+func (n *Node) Run(ctx context.Context) {
+	ctx = log.With(ctx, log.Str("node", n.name))
+
+	safe.Go(func() {
+		n.campaign(ctx)
+	})
+
+	ticker := time.NewTicker(n.ttl / 3)
+	defer ticker.Stop()
+
+	n.publish(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			n.publish(ctx)
+		case <-ctx.Done():
+			if err := n.kvClient.Delete(n.nodeKey(n.name)); err != nil {
+				log.FromContext(ctx).Errorf("Unable to unregister the node from the cluster: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// Nodes returns the state of the nodes which refreshed it within the TTL, sorted by name.
+func (n *Node) Nodes() ([]NodeInfo, error) {
+	pairs, err := n.kvClient.List(path.Join(n.rootKey, nodesKey), nil)
+	if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+		return nil, err
+	}
+
+	nodes := make([]NodeInfo, 0, len(pairs))
+	for _, pair := range pairs {
+		var node NodeInfo
+		if err := json.Unmarshal(pair.Value, &node); err != nil {
+			log.WithoutContext().Debugf("Skipping the invalid cluster node %s: %v", pair.Key, err)
+			continue
+		}
+
+		// Some stores, such as ZooKeeper, do not expire the keys.
+		if time.Since(node.LastSeen) > n.ttl {
+			continue
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes, nil
+}
+
+func (n *Node) publish(ctx context.Context) {
+	n.mu.RLock()
+	node := NodeInfo{
+		Name:      n.name,
+		Version:   version.Version,
+		Leader:    n.leader,
+		StartedAt: n.startedAt,
+		LastSeen:  time.Now(),
+		Runtime:   n.runtime,
+	}
+	n.mu.RUnlock()
+
+	value, err := json.Marshal(node)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Unable to marshal the state of the node: %v", err)
+		return
+	}
+
+	if err := n.kvClient.Put(n.nodeKey(n.name), value, &store.WriteOptions{TTL: n.ttl}); err != nil {
+		log.FromContext(ctx).Errorf("Unable to publish the state of the node: %v", err)
+	}
+}
+
+// campaign acquires the leader lock, holds it until it is lost, and then campaigns again, until the context is done.
+func (n *Node) campaign(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	operation := func() error {
+		locker, err := n.kvClient.NewLock(path.Join(n.rootKey, leaderKey), &store.LockOptions{Value: []byte(n.name), TTL: n.ttl})
+		if err != nil {
+			return fmt.Errorf("unable to create the leader lock: %w", err)
+		}
+
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		defer close(done)
+
+		go func() {
+			select {
+			case <-ctx.Done():
+				close(stop)
+			case <-done:
+			}
+		}()
+
+		lost, err := locker.Lock(stop)
+		if err != nil {
+			return fmt.Errorf("unable to acquire the leader lock: %w", err)
+		}
+
+		if ctx.Err() != nil {
+			if lost != nil {
+				_ = locker.Unlock()
+			}
+			return nil
+		}
+
+		n.setLeader(true)
+		logger.Info("Elected leader of the cluster")
+
+		select {
+		case <-ctx.Done():
+			n.setLeader(false)
+			if err := locker.Unlock(); err != nil {
+				logger.Errorf("Unable to release the leader lock: %v", err)
+			}
+			return nil
+		case <-lost:
+			n.setLeader(false)
+			return errors.New("leadership lost")
+		}
+	}
+
+	notify := func(err error, time time.Duration) {
+		logger.Errorf("Cluster leader election error: %v, retrying in %s", err, time)
+	}
+
+	err := backoff.RetryNotify(safe.OperationWithRecover(operation),
+		backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctx), notify)
+	if err != nil && ctx.Err() == nil {
+		logger.Errorf("Cannot campaign for the leadership of the cluster: %v", err)
+	}
+}
+
+func (n *Node) setLeader(leader bool) {
+	n.mu.Lock()
+	n.leader = leader
+	n.mu.Unlock()
+}
+
+func (n *Node) nodeKey(name string) string {
+	return path.Join(n.rootKey, nodesKey, name)
+}
+
+func newRuntimeInfo(conf *runtime.Configuration) *RuntimeInfo {
+	info := &RuntimeInfo{}
+
+	for _, rt := range conf.Routers {
+		info.HTTP.Routers.add(rt.Status)
+	}
+	for _, svc := range conf.Services {
+		info.HTTP.Services.add(svc.Status)
+	}
+	for _, mi := range conf.Middlewares {
+		info.HTTP.Middlewares.add(mi.Status)
+	}
+	for _, rt := range conf.TCPRouters {
+		info.TCP.Routers.add(rt.Status)
+	}
+	for _, svc := range conf.TCPServices {
+		info.TCP.Services.add(svc.Status)
+	}
+	for _, mi := range conf.TCPMiddlewares {
+		info.TCP.Middlewares.add(mi.Status)
+	}
+	for _, rt := range conf.UDPRouters {
+		info.UDP.Routers.add(rt.Status)
+	}
+	for _, svc := range conf.UDPServices {
+		info.UDP.Services.add(svc.Status)
+	}
+
+	return info
+}
+
+func createKVClient(ctx context.Context, config *Configuration) (store.Store, error) {
+	storeConfig := &store.Config{
+		ConnectionTimeout: 3 * time.Second,
+		Bucket:            "traefik",
+		Username:          config.Username,
+		Password:          config.Password,
+	}
+
+	if config.TLS != nil {
+		var err error
+		storeConfig.TLS, err = config.TLS.CreateTLSConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var storeType store.Backend
+	switch config.Store {
+	case "consul":
+		storeType = store.CONSUL
+		consul.Register()
+	case "etcd":
+		storeType = store.ETCDV3
+		etcdv3.Register()
+	case "zookeeper":
+		storeType = store.ZK
+		zookeeper.Register()
+	case "redis":
+		storeType = store.REDIS
+		redis.Register()
+	default:
+		return nil, fmt.Errorf("unsupported KV store: %q", config.Store)
+	}
+
+	return valkeyrie.NewStore(storeType, config.Endpoints, storeConfig)
+}
@@ -0,0 +1,161 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+)
+
+func TestNode_election(t *testing.T) {
+	kvStore := newMemoryStore()
+
+	node1 := newNode(kvStore, "traefik-cluster", "node1", 300*time.Millisecond)
+	node2 := newNode(kvStore, "traefik-cluster", "node2", 300*time.Millisecond)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	t.Cleanup(cancel1)
+	go node1.Run(ctx1)
+
+	require.Eventually(t, node1.IsLeader, 5*time.Second, 10*time.Millisecond)
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	t.Cleanup(cancel2)
+	go node2.Run(ctx2)
+
+	require.Eventually(t, func() bool {
+		nodes, err := node1.Nodes()
+		return err == nil && len(nodes) == 2 && nodes[0].Leader && !nodes[1].Leader
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, node2.IsLeader())
+
+	// The leader steps down, and the other node takes over.
+	cancel1()
+
+	require.Eventually(t, node2.IsLeader, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, node1.IsLeader())
+
+	require.Eventually(t, func() bool {
+		nodes, err := node2.Nodes()
+		return err == nil && len(nodes) == 1 && nodes[0].Name == "node2" && nodes[0].Leader
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNode_leadershipLost(t *testing.T) {
+	kvStore := newMemoryStore()
+
+	node := newNode(kvStore, "traefik-cluster", "node1", 300*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go node.Run(ctx)
+
+	require.Eventually(t, node.IsLeader, 5*time.Second, 10*time.Millisecond)
+
+	kvStore.loseLock("traefik-cluster/leader")
+
+	require.Eventually(t, func() bool { return !node.IsLeader() }, 5*time.Second, 10*time.Millisecond)
+
+	// The node campaigns again.
+	require.Eventually(t, node.IsLeader, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	select {
+	case <-kvStore.released:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the leader lock to be released")
+	}
+	assert.False(t, kvStore.lockHeld("traefik-cluster/leader"))
+}
+
+func TestNode_Nodes(t *testing.T) {
+	kvStore := newMemoryStore()
+
+	node := newNode(kvStore, "traefik-cluster", "node1", time.Minute)
+
+	nodes, err := node.Nodes()
+	require.NoError(t, err)
+	assert.Empty(t, nodes)
+
+	put := func(info NodeInfo) {
+		value, err := json.Marshal(info)
+		require.NoError(t, err)
+		require.NoError(t, kvStore.Put("traefik-cluster/nodes/"+info.Name, value, nil))
+	}
+
+	put(NodeInfo{Name: "node2", LastSeen: time.Now()})
+	put(NodeInfo{Name: "node1", LastSeen: time.Now(), Leader: true})
+	put(NodeInfo{Name: "gone", LastSeen: time.Now().Add(-2 * time.Minute)})
+	require.NoError(t, kvStore.Put("traefik-cluster/nodes/invalid", []byte("{"), nil))
+
+	nodes, err = node.Nodes()
+	require.NoError(t, err)
+
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.Name)
+	}
+	assert.Equal(t, []string{"node1", "node2"}, names)
+	assert.True(t, nodes[0].Leader)
+}
+
+func TestNode_publish(t *testing.T) {
+	kvStore := newMemoryStore()
+
+	node := newNode(kvStore, "traefik-cluster", "node1", time.Minute)
+
+	node.SetRuntimeConfiguration(&runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"foo@file": {Router: &dynamic.Router{}, Status: runtime.StatusEnabled},
+			"bar@file": {Router: &dynamic.Router{}, Status: runtime.StatusDisabled},
+		},
+		Services: map[string]*runtime.ServiceInfo{
+			"foo@file": {Service: &dynamic.Service{}, Status: runtime.StatusWarning},
+		},
+		TCPRouters: map[string]*runtime.TCPRouterInfo{
+			"foo@file": {TCPRouter: &dynamic.TCPRouter{}, Status: runtime.StatusEnabled},
+		},
+		UDPServices: map[string]*runtime.UDPServiceInfo{
+			"foo@file": {UDPService: &dynamic.UDPService{}, Status: runtime.StatusDisabled},
+		},
+	})
+
+	node.publish(context.Background())
+
+	assert.Equal(t, time.Minute, kvStore.ttls["traefik-cluster/nodes/node1"])
+
+	nodes, err := node.Nodes()
+	require.NoError(t, err)
+	require.Len(t, nodes, 1)
+
+	assert.Equal(t, "node1", nodes[0].Name)
+	assert.False(t, nodes[0].Leader)
+
+	expected := &RuntimeInfo{
+		HTTP: SchemeInfo{
+			Routers:  Counters{Total: 2, Errors: 1},
+			Services: Counters{Total: 1, Warnings: 1},
+		},
+		TCP: SchemeInfo{
+			Routers: Counters{Total: 1},
+		},
+		UDP: SchemeInfo{
+			Services: Counters{Total: 1, Errors: 1},
+		},
+	}
+	assert.Equal(t, expected, nodes[0].Runtime)
+}
+
+func TestNew_unsupportedStore(t *testing.T) {
+	_, err := New(context.Background(), &Configuration{Store: "foo", Endpoints: []string{"localhost:1234"}})
+	assert.Error(t, err)
+}
+
+var _ store.Store = (*memoryStore)(nil)
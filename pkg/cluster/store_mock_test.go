@@ -0,0 +1,205 @@
+package cluster
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+)
+
+// memoryStore is an in-memory KV store, whose locks can be lost on demand.
+type memoryStore struct {
+	mu    sync.Mutex
+	pairs map[string][]byte
+	ttls  map[string]time.Duration
+	// indexes are the indexes of the last modification of the keys.
+	indexes map[string]uint64
+	index   uint64
+
+	// locks holds, by key, the channel closed when the lock is lost, while the lock is held.
+	locks map[string]chan struct{}
+	// released is signaled when a lock is released.
+	released chan struct{}
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		pairs:    make(map[string][]byte),
+		ttls:     make(map[string]time.Duration),
+		indexes:  make(map[string]uint64),
+		locks:    make(map[string]chan struct{}),
+		released: make(chan struct{}, 1),
+	}
+}
+
+func (s *memoryStore) Put(key string, value []byte, options *store.WriteOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(key, value, options)
+
+	return nil
+}
+
+func (s *memoryStore) put(key string, value []byte, options *store.WriteOptions) {
+	s.index++
+
+	s.pairs[key] = value
+	s.indexes[key] = s.index
+	if options != nil {
+		s.ttls[key] = options.TTL
+	}
+}
+
+func (s *memoryStore) Get(key string, _ *store.ReadOptions) (*store.KVPair, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return &store.KVPair{Key: key, Value: value, LastIndex: s.indexes[key]}, nil
+}
+
+func (s *memoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pairs[key]; !ok {
+		return store.ErrKeyNotFound
+	}
+
+	delete(s.pairs, key)
+	delete(s.indexes, key)
+
+	return nil
+}
+
+func (s *memoryStore) Exists(key string, _ *store.ReadOptions) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.pairs[key]
+
+	return ok, nil
+}
+
+func (s *memoryStore) Watch(string, <-chan struct{}, *store.ReadOptions) (<-chan *store.KVPair, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+func (s *memoryStore) WatchTree(string, <-chan struct{}, *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	return nil, store.ErrCallNotSupported
+}
+
+func (s *memoryStore) NewLock(key string, _ *store.LockOptions) (store.Locker, error) {
+	return &memoryLocker{store: s, key: key}, nil
+}
+
+func (s *memoryStore) List(directory string, _ *store.ReadOptions) ([]*store.KVPair, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pairs []*store.KVPair
+	for key, value := range s.pairs {
+		if strings.HasPrefix(key, directory+"/") {
+			pairs = append(pairs, &store.KVPair{Key: key, Value: value})
+		}
+	}
+
+	if len(pairs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return pairs, nil
+}
+
+func (s *memoryStore) DeleteTree(string) error {
+	return store.ErrCallNotSupported
+}
+
+func (s *memoryStore) AtomicPut(key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, exists := s.indexes[key]
+	if previous == nil && exists {
+		return false, nil, store.ErrKeyExists
+	}
+	if previous != nil && (!exists || previous.LastIndex != index) {
+		return false, nil, store.ErrKeyModified
+	}
+
+	s.put(key, value, options)
+
+	return true, &store.KVPair{Key: key, Value: value, LastIndex: s.index}, nil
+}
+
+func (s *memoryStore) AtomicDelete(string, *store.KVPair) (bool, error) {
+	return false, store.ErrCallNotSupported
+}
+
+func (s *memoryStore) Close() {}
+
+// loseLock makes the holder of the lock lose it.
+func (s *memoryStore) loseLock(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lost, ok := s.locks[key]; ok {
+		close(lost)
+		delete(s.locks, key)
+	}
+}
+
+func (s *memoryStore) lockHeld(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.locks[key]
+
+	return ok
+}
+
+type memoryLocker struct {
+	store *memoryStore
+	key   string
+}
+
+func (l *memoryLocker) Lock(stopChan chan struct{}) (<-chan struct{}, error) {
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		l.store.mu.Lock()
+		if _, held := l.store.locks[l.key]; !held {
+			lost := make(chan struct{})
+			l.store.locks[l.key] = lost
+			l.store.mu.Unlock()
+			return lost, nil
+		}
+		l.store.mu.Unlock()
+
+		select {
+		case <-stopChan:
+			return nil, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *memoryLocker) Unlock() error {
+	l.store.mu.Lock()
+	delete(l.store.locks, l.key)
+	l.store.mu.Unlock()
+
+	select {
+	case l.store.released <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
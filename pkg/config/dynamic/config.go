@@ -25,6 +25,59 @@ type Configuration struct {
 	TCP  *TCPConfiguration  `json:"tcp,omitempty" toml:"tcp,omitempty" yaml:"tcp,omitempty" export:"true"`
 	UDP  *UDPConfiguration  `json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty" export:"true"`
 	TLS  *TLSConfiguration  `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+
+	// Conflicts are the elements dropped while merging the configurations of the provider.
+	// They are computed by Traefik, and cannot be configured.
+	Conflicts []Conflict `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
+
+	// LabelErrors are the errors of the labels which cannot be decoded by the provider.
+	// They are computed by Traefik, and cannot be configured.
+	LabelErrors []LabelError `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
+
+	// Namespaces are the namespaces of the resources defining the elements, for the providers having namespaces.
+	// They are computed by Traefik, and cannot be configured.
+	Namespaces []ElementNamespace `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Conflict describes an element defined multiple times with different configurations,
+// which is dropped from the configuration.
+type Conflict struct {
+	// Kind is the kind of the element (e.g. router, tcpService).
+	Kind string `json:"kind,omitempty"`
+	// Name is the name of the element.
+	Name string `json:"name,omitempty"`
+	// Sources are the sources of the provider defining the element (e.g. container names).
+	Sources []string `json:"sources,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// LabelError describes a label which cannot be decoded,
+// and makes the provider drop the source defining it.
+type LabelError struct {
+	// Provider is the name of the provider.
+	Provider string `json:"provider,omitempty"`
+	// Source is the source of the provider defining the label (e.g. a container name).
+	Source string `json:"source,omitempty"`
+	// Label is the name of the label.
+	Label string `json:"label,omitempty"`
+	// Reason is the reason why the label cannot be decoded.
+	Reason string `json:"reason,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ElementNamespace describes the namespace of a resource defining an element.
+// An element defined by several resources has one ElementNamespace per namespace.
+type ElementNamespace struct {
+	// Kind is the kind of the element (e.g. router, tcpService).
+	Kind string `json:"kind,omitempty"`
+	// Name is the name of the element.
+	Name string `json:"name,omitempty"`
+	// Namespace is the namespace of the resource defining the element.
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
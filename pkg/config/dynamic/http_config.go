@@ -1,6 +1,7 @@
 package dynamic
 
 import (
+	"net/http"
 	"reflect"
 	"time"
 
@@ -35,18 +36,21 @@ type Service struct {
 	LoadBalancer *ServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *WeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
 	Mirroring    *Mirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
+	Failover     *Failover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // Router holds the router configuration.
 type Router struct {
-	EntryPoints []string         `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
-	Middlewares []string         `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
-	Service     string           `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
-	Rule        string           `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
-	Priority    int              `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
-	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	EntryPoints      []string         `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
+	Middlewares      []string         `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty" export:"true"`
+	Service          string           `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Rule             string           `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
+	Priority         int              `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
+	TLS              *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	SkipRedirections bool             `json:"skipRedirections,omitempty" toml:"skipRedirections,omitempty" yaml:"skipRedirections,omitempty" export:"true"`
+	IPStrategy       *IPStrategy      `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -56,6 +60,27 @@ type RouterTLSConfig struct {
 	Options      string         `json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
 	CertResolver string         `json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
 	Domains      []types.Domain `json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty" export:"true"`
+	// ClientAuth requires the client certificates for this router only, instead of the whole entry point.
+	ClientAuth *RouterTLSClientAuth `json:"clientAuth,omitempty" toml:"clientAuth,omitempty" yaml:"clientAuth,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RouterTLSClientAuth holds the client authentication policy of a router.
+// The TLS handshake only requests the client certificates, which are verified once the request is routed.
+type RouterTLSClientAuth struct {
+	CAFiles []tls.FileOrContent `json:"caFiles,omitempty" toml:"caFiles,omitempty" yaml:"caFiles,omitempty"`
+	// ClientAuthType defines the client authentication type to apply.
+	// The available values are: "VerifyClientCertIfGiven" and "RequireAndVerifyClientCert" (default).
+	ClientAuthType string `json:"clientAuthType,omitempty" toml:"clientAuthType,omitempty" yaml:"clientAuthType,omitempty" export:"true"`
+	// StatusCode is the status code of the response to the rejected requests.
+	StatusCode int `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *RouterTLSClientAuth) SetDefaults() {
+	c.ClientAuthType = "RequireAndVerifyClientCert"
+	c.StatusCode = http.StatusForbidden
 }
 
 // +k8s:deepcopy-gen=true
@@ -76,10 +101,69 @@ func (m *Mirroring) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// Failover holds the Failover configuration.
+type Failover struct {
+	Service  string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Fallback string `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty" export:"true"`
+	// RequireHealthyFallback only switches the traffic to the fallback service while it is reported as up,
+	// which requires the fallback service to report its status, i.e. to have a health check.
+	RequireHealthyFallback bool `json:"requireHealthyFallback,omitempty" toml:"requireHealthyFallback,omitempty" yaml:"requireHealthyFallback,omitempty" export:"true"`
+	// HealthCheck enables automatic self-healthcheck for this service,
+	// i.e. this service reports to its parent whether the service or the fallback is up.
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // MirrorService holds the MirrorService configuration.
 type MirrorService struct {
 	Name    string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
 	Percent int    `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	// Match selects the requests which are always mirrored, and counted in the percentage of mirrored requests.
+	Match *RequestMatch `json:"match,omitempty" toml:"match,omitempty" yaml:"match,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RequestMatch selects requests by the value of a header, or of a cookie.
+type RequestMatch struct {
+	Header string `json:"header,omitempty" toml:"header,omitempty" yaml:"header,omitempty" export:"true"`
+	Cookie string `json:"cookie,omitempty" toml:"cookie,omitempty" yaml:"cookie,omitempty" export:"true"`
+	// Values are the accepted values of the header or of the cookie. Any non-empty value is accepted when there are none.
+	Values []string `json:"values,omitempty" toml:"values,omitempty" yaml:"values,omitempty" export:"true"`
+}
+
+// Match returns true if the value of the header, or of the cookie, of the request is accepted.
+func (m *RequestMatch) Match(req *http.Request) bool {
+	if m.Header != "" && m.accepts(req.Header.Get(m.Header)) {
+		return true
+	}
+
+	if m.Cookie != "" {
+		if cookie, err := req.Cookie(m.Cookie); err == nil && m.accepts(cookie.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *RequestMatch) accepts(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	if len(m.Values) == 0 {
+		return true
+	}
+
+	for _, v := range m.Values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
 }
 
 // +k8s:deepcopy-gen=true
@@ -94,6 +178,28 @@ type WeightedRoundRobin struct {
 	// load-balancing algorithm. In addition, if the parent of this service also has
 	// HealthCheck enabled, this service reports to its parent any status change.
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// Ramp progressively changes the weight of one of the services, e.g. to shift the traffic to a canary release.
+	Ramp *WeightRamp `json:"ramp,omitempty" toml:"ramp,omitempty" yaml:"ramp,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// WeightRamp holds the configuration of the progressive change of the weight of a service load-balanced with weighted round robin.
+// The weight changes linearly from the configured one to the target one, over the duration of the ramp.
+type WeightRamp struct {
+	Service      string          `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	TargetWeight int             `json:"targetWeight,omitempty" toml:"targetWeight,omitempty" yaml:"targetWeight,omitempty" export:"true"`
+	Duration     ptypes.Duration `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty" export:"true"`
+	Interval     ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	// MaxErrorRatio is the ratio of 5XX responses of the service, over an interval, above which the ramp is aborted,
+	// and the configured weight restored. Zero disables the check.
+	MaxErrorRatio float64 `json:"maxErrorRatio,omitempty" toml:"maxErrorRatio,omitempty" yaml:"maxErrorRatio,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a WeightRamp.
+func (w *WeightRamp) SetDefaults() {
+	w.Duration = ptypes.Duration(30 * time.Minute)
+	w.Interval = ptypes.Duration(30 * time.Second)
 }
 
 // +k8s:deepcopy-gen=true
@@ -102,6 +208,8 @@ type WeightedRoundRobin struct {
 type WRRService struct {
 	Name   string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
 	Weight *int   `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty" export:"true"`
+	// Match selects the requests which are always forwarded to the service, whatever its weight.
+	Match *RequestMatch `json:"match,omitempty" toml:"match,omitempty" yaml:"match,omitempty" export:"true"`
 }
 
 // SetDefaults Default values for a WRRService.
@@ -121,10 +229,32 @@ type Sticky struct {
 
 // Cookie holds the sticky configuration based on cookie.
 type Cookie struct {
-	Name     string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
-	Secure   bool   `json:"secure,omitempty" toml:"secure,omitempty" yaml:"secure,omitempty" export:"true"`
-	HTTPOnly bool   `json:"httpOnly,omitempty" toml:"httpOnly,omitempty" yaml:"httpOnly,omitempty" export:"true"`
-	SameSite string `json:"sameSite,omitempty" toml:"sameSite,omitempty" yaml:"sameSite,omitempty" export:"true"`
+	Name     string       `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	Secure   bool         `json:"secure,omitempty" toml:"secure,omitempty" yaml:"secure,omitempty" export:"true"`
+	HTTPOnly bool         `json:"httpOnly,omitempty" toml:"httpOnly,omitempty" yaml:"httpOnly,omitempty" export:"true"`
+	SameSite string       `json:"sameSite,omitempty" toml:"sameSite,omitempty" yaml:"sameSite,omitempty" export:"true"`
+	Store    *CookieStore `json:"store,omitempty" toml:"store,omitempty" yaml:"store,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CookieStore holds the configuration of the external store sharing the sticky sessions between the Traefik instances.
+type CookieStore struct {
+	Redis *RedisCookieStore `json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RedisCookieStore holds the configuration of the Redis sticky sessions store.
+type RedisCookieStore struct {
+	Endpoints []string        `json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty" export:"true"`
+	Password  string          `json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	TTL       ptypes.Duration `json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a RedisCookieStore.
+func (r *RedisCookieStore) SetDefaults() {
+	r.TTL = ptypes.Duration(24 * time.Hour)
 }
 
 // +k8s:deepcopy-gen=true
@@ -141,6 +271,15 @@ type ServersLoadBalancer struct {
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// HTTP2ConnectionPool spreads the requests sent to each server over a pool of HTTP/2 connections,
+	// so that multiplexed calls (e.g. gRPC) are balanced per request rather than per connection.
+	HTTP2ConnectionPool *HTTP2ConnectionPool `json:"http2ConnectionPool,omitempty" toml:"http2ConnectionPool,omitempty" yaml:"http2ConnectionPool,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// WarmUp sends requests to the servers added to the load-balancer, or back from a failed health check,
+	// before they enter the rotation.
+	WarmUp *WarmUp `json:"warmUp,omitempty" toml:"warmUp,omitempty" yaml:"warmUp,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// SlowStart ramps up linearly the share of the traffic sent to the servers added to the load-balancer,
+	// or back from a failed health check.
+	SlowStart *SlowStart `json:"slowStart,omitempty" toml:"slowStart,omitempty" yaml:"slowStart,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -168,6 +307,53 @@ func (l *ServersLoadBalancer) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// HTTP2ConnectionPool holds the configuration of the pool of HTTP/2 connections opened to each server.
+type HTTP2ConnectionPool struct {
+	Size int `json:"size,omitempty" toml:"size,omitempty" yaml:"size,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a HTTP2ConnectionPool.
+func (p *HTTP2ConnectionPool) SetDefaults() {
+	p.Size = 4
+}
+
+// +k8s:deepcopy-gen=true
+
+// WarmUp holds the configuration of the requests sent to a server before it enters the rotation.
+type WarmUp struct {
+	Paths       []string        `json:"paths,omitempty" toml:"paths,omitempty" yaml:"paths,omitempty" export:"true"`
+	Requests    int             `json:"requests,omitempty" toml:"requests,omitempty" yaml:"requests,omitempty" export:"true"`
+	Concurrency int             `json:"concurrency,omitempty" toml:"concurrency,omitempty" yaml:"concurrency,omitempty" export:"true"`
+	Timeout     ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// Status is the list of the status codes of a successful warm-up request. Defaults to 200-399.
+	Status []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	// MinSuccessRatio is the ratio of successful warm-up requests required for the server to enter the rotation.
+	MinSuccessRatio float64 `json:"minSuccessRatio,omitempty" toml:"minSuccessRatio,omitempty" yaml:"minSuccessRatio,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a WarmUp.
+func (w *WarmUp) SetDefaults() {
+	w.Requests = 10
+	w.Concurrency = 1
+	w.Timeout = ptypes.Duration(5 * time.Second)
+	w.MinSuccessRatio = 1
+}
+
+// +k8s:deepcopy-gen=true
+
+// SlowStart holds the configuration of the progressive increase of the traffic sent to a new server.
+// The weight of the server increases linearly from almost zero to the one of the other servers, over the duration.
+type SlowStart struct {
+	Duration ptypes.Duration `json:"duration,omitempty" toml:"duration,omitempty" yaml:"duration,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a SlowStart.
+func (s *SlowStart) SetDefaults() {
+	s.Duration = ptypes.Duration(30 * time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
 // ResponseForwarding holds configuration for the forward of the response.
 type ResponseForwarding struct {
 	FlushInterval string `json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty" export:"true"`
@@ -218,13 +404,26 @@ type HealthCheck struct{}
 
 // ServersTransport options to configure communication between Traefik and the servers.
 type ServersTransport struct {
-	ServerName          string              `description:"ServerName used to contact the server" json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
-	InsecureSkipVerify  bool                `description:"Disable SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
-	RootCAs             []tls.FileOrContent `description:"Add cert file for self-signed certificate." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
-	Certificates        tls.Certificates    `description:"Certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
-	MaxIdleConnsPerHost int                 `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
-	ForwardingTimeouts  *ForwardingTimeouts `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
-	DisableHTTP2        bool                `description:"Disable HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
+	ServerName          string                 `description:"ServerName used to contact the server" json:"serverName,omitempty" toml:"serverName,omitempty" yaml:"serverName,omitempty"`
+	InsecureSkipVerify  bool                   `description:"Disable SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
+	RootCAs             []tls.FileOrContent    `description:"Add cert file for self-signed certificate." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
+	Certificates        tls.Certificates       `description:"Certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
+	MaxIdleConnsPerHost int                    `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
+	ForwardingTimeouts  *ForwardingTimeouts    `description:"Timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
+	DisableHTTP2        bool                   `description:"Disable HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
+	Proxy               *ServersTransportProxy `description:"Upstream proxy tunneling the connections to the backend servers." json:"proxy,omitempty" toml:"proxy,omitempty" yaml:"proxy,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ServersTransportProxy holds the configuration of an upstream proxy,
+// through which the connections to the backend servers are tunneled with CONNECT requests.
+type ServersTransportProxy struct {
+	URL                string              `description:"URL of the proxy, with the http or https scheme." json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty"`
+	Username           string              `description:"Username used to authenticate to the proxy." json:"username,omitempty" toml:"username,omitempty" yaml:"username,omitempty"`
+	Password           string              `description:"Password used to authenticate to the proxy." json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
+	InsecureSkipVerify bool                `description:"Disable the verification of the certificate of the proxy." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
+	RootCAs            []tls.FileOrContent `description:"Add cert file for the self-signed certificate of the proxy." json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -234,10 +433,12 @@ type ForwardingTimeouts struct {
 	DialTimeout           ptypes.Duration `description:"The amount of time to wait until a connection to a backend server can be established. If zero, no timeout exists." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
 	ResponseHeaderTimeout ptypes.Duration `description:"The amount of time to wait for a server's response headers after fully writing the request (including its body, if any). If zero, no timeout exists." json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty" export:"true"`
 	IdleConnTimeout       ptypes.Duration `description:"The maximum period for which an idle HTTP keep-alive connection will remain open before closing itself" json:"idleConnTimeout,omitempty" toml:"idleConnTimeout,omitempty" yaml:"idleConnTimeout,omitempty" export:"true"`
+	TLSHandshakeTimeout   ptypes.Duration `description:"The amount of time to wait for the TLS handshake with a backend server. If zero, no timeout exists." json:"tlsHandshakeTimeout,omitempty" toml:"tlsHandshakeTimeout,omitempty" yaml:"tlsHandshakeTimeout,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
 func (f *ForwardingTimeouts) SetDefaults() {
 	f.DialTimeout = ptypes.Duration(30 * time.Second)
 	f.IdleConnTimeout = ptypes.Duration(90 * time.Second)
+	f.TLSHandshakeTimeout = ptypes.Duration(10 * time.Second)
 }
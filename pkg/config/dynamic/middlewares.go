@@ -24,6 +24,7 @@ type Middleware struct {
 	IPWhiteList       *IPWhiteList       `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
 	Headers           *Headers           `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
 	Errors            *ErrorPage         `json:"errors,omitempty" toml:"errors,omitempty" yaml:"errors,omitempty" export:"true"`
+	Fallback          *Fallback          `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty" export:"true"`
 	RateLimit         *RateLimit         `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
 	RedirectRegex     *RedirectRegex     `json:"redirectRegex,omitempty" toml:"redirectRegex,omitempty" yaml:"redirectRegex,omitempty" export:"true"`
 	RedirectScheme    *RedirectScheme    `json:"redirectScheme,omitempty" toml:"redirectScheme,omitempty" yaml:"redirectScheme,omitempty" export:"true"`
@@ -37,6 +38,14 @@ type Middleware struct {
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+	AcceptLanguage    *AcceptLanguage    `json:"acceptLanguage,omitempty" toml:"acceptLanguage,omitempty" yaml:"acceptLanguage,omitempty" export:"true"`
+	SignedURL         *SignedURL         `json:"signedURL,omitempty" toml:"signedURL,omitempty" yaml:"signedURL,omitempty" export:"true"`
+	HeadersLimit      *HeadersLimit      `json:"headersLimit,omitempty" toml:"headersLimit,omitempty" yaml:"headersLimit,omitempty" export:"true"`
+	StaticResponse    *StaticResponse    `json:"staticResponse,omitempty" toml:"staticResponse,omitempty" yaml:"staticResponse,omitempty" export:"true"`
+	JWT               *JWT               `json:"jwt,omitempty" toml:"jwt,omitempty" yaml:"jwt,omitempty" export:"true"`
+	RequestID         *RequestID         `json:"requestId,omitempty" toml:"requestId,omitempty" yaml:"requestId,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	RewriteBody       *RewriteBody       `json:"rewriteBody,omitempty" toml:"rewriteBody,omitempty" yaml:"rewriteBody,omitempty" export:"true"`
+	GeoIP             *GeoIP             `json:"geoIP,omitempty" toml:"geoIP,omitempty" yaml:"geoIP,omitempty" export:"true"`
 
 	Plugin map[string]PluginConf `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty" export:"true"`
 }
@@ -58,6 +67,19 @@ type ContentType struct {
 
 // +k8s:deepcopy-gen=true
 
+// AcceptLanguage holds the AcceptLanguage configuration.
+type AcceptLanguage struct {
+	Languages  []string `json:"languages,omitempty" toml:"languages,omitempty" yaml:"languages,omitempty" export:"true"`
+	HeaderName string   `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on an AcceptLanguage.
+func (a *AcceptLanguage) SetDefaults() {
+	a.HeaderName = "X-Language"
+}
+
+// +k8s:deepcopy-gen=true
+
 // AddPrefix holds the AddPrefix configuration.
 type AddPrefix struct {
 	Prefix string `json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty" export:"true"`
@@ -78,11 +100,14 @@ type BasicAuth struct {
 
 // Buffering holds the request/response buffering configuration.
 type Buffering struct {
-	MaxRequestBodyBytes  int64  `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
-	MemRequestBodyBytes  int64  `json:"memRequestBodyBytes,omitempty" toml:"memRequestBodyBytes,omitempty" yaml:"memRequestBodyBytes,omitempty" export:"true"`
-	MaxResponseBodyBytes int64  `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
-	MemResponseBodyBytes int64  `json:"memResponseBodyBytes,omitempty" toml:"memResponseBodyBytes,omitempty" yaml:"memResponseBodyBytes,omitempty" export:"true"`
-	RetryExpression      string `json:"retryExpression,omitempty" toml:"retryExpression,omitempty" yaml:"retryExpression,omitempty" export:"true"`
+	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
+	MemRequestBodyBytes int64 `json:"memRequestBodyBytes,omitempty" toml:"memRequestBodyBytes,omitempty" yaml:"memRequestBodyBytes,omitempty" export:"true"`
+	// MaxDiskRequestBodyBytes is the maximum number of bytes of the request bodies spilled to disk at once,
+	// across the requests handled by the middleware. Zero means no limit.
+	MaxDiskRequestBodyBytes int64  `json:"maxDiskRequestBodyBytes,omitempty" toml:"maxDiskRequestBodyBytes,omitempty" yaml:"maxDiskRequestBodyBytes,omitempty" export:"true"`
+	MaxResponseBodyBytes    int64  `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+	MemResponseBodyBytes    int64  `json:"memResponseBodyBytes,omitempty" toml:"memResponseBodyBytes,omitempty" yaml:"memResponseBodyBytes,omitempty" export:"true"`
+	RetryExpression         string `json:"retryExpression,omitempty" toml:"retryExpression,omitempty" yaml:"retryExpression,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -104,6 +129,13 @@ type CircuitBreaker struct {
 // Compress holds the compress configuration.
 type Compress struct {
 	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty" toml:"excludedContentTypes,omitempty" yaml:"excludedContentTypes,omitempty" export:"true"`
+	IncludedContentTypes []string `json:"includedContentTypes,omitempty" toml:"includedContentTypes,omitempty" yaml:"includedContentTypes,omitempty" export:"true"`
+	MinResponseBodyBytes int      `json:"minResponseBodyBytes,omitempty" toml:"minResponseBodyBytes,omitempty" yaml:"minResponseBodyBytes,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a Compress.
+func (c *Compress) SetDefaults() {
+	c.MinResponseBodyBytes = 1400
 }
 
 // +k8s:deepcopy-gen=true
@@ -121,21 +153,64 @@ type DigestAuth struct {
 
 // ErrorPage holds the custom error page configuration.
 type ErrorPage struct {
-	Status  []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
-	Service string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
-	Query   string   `json:"query,omitempty" toml:"query,omitempty" yaml:"query,omitempty" export:"true"`
+	Status      []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	Service     string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Query       string   `json:"query,omitempty" toml:"query,omitempty" yaml:"query,omitempty" export:"true"`
+	Retry       *Retry   `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
+	Template    string   `json:"template,omitempty" toml:"template,omitempty" yaml:"template,omitempty" export:"true"`
+	ContentType string   `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Fallback holds the fallback configuration.
+type Fallback struct {
+	Status              []string `json:"status,omitempty" toml:"status,omitempty" yaml:"status,omitempty" export:"true"`
+	Service             string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Path                string   `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	MaxRequestBodyBytes int64    `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a Fallback.
+func (f *Fallback) SetDefaults() {
+	f.MaxRequestBodyBytes = 1024 * 1024
 }
 
 // +k8s:deepcopy-gen=true
 
 // ForwardAuth holds the http forward authentication configuration.
 type ForwardAuth struct {
-	Address                  string     `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
-	TLS                      *ClientTLS `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
-	TrustForwardHeader       bool       `json:"trustForwardHeader,omitempty" toml:"trustForwardHeader,omitempty" yaml:"trustForwardHeader,omitempty" export:"true"`
-	AuthResponseHeaders      []string   `json:"authResponseHeaders,omitempty" toml:"authResponseHeaders,omitempty" yaml:"authResponseHeaders,omitempty" export:"true"`
-	AuthResponseHeadersRegex string     `json:"authResponseHeadersRegex,omitempty" toml:"authResponseHeadersRegex,omitempty" yaml:"authResponseHeadersRegex,omitempty" export:"true"`
-	AuthRequestHeaders       []string   `json:"authRequestHeaders,omitempty" toml:"authRequestHeaders,omitempty" yaml:"authRequestHeaders,omitempty" export:"true"`
+	Address                  string            `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	TLS                      *ClientTLS        `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	TrustForwardHeader       bool              `json:"trustForwardHeader,omitempty" toml:"trustForwardHeader,omitempty" yaml:"trustForwardHeader,omitempty" export:"true"`
+	AuthResponseHeaders      []string          `json:"authResponseHeaders,omitempty" toml:"authResponseHeaders,omitempty" yaml:"authResponseHeaders,omitempty" export:"true"`
+	AuthResponseHeadersRegex string            `json:"authResponseHeadersRegex,omitempty" toml:"authResponseHeadersRegex,omitempty" yaml:"authResponseHeadersRegex,omitempty" export:"true"`
+	AuthRequestHeaders       []string          `json:"authRequestHeaders,omitempty" toml:"authRequestHeaders,omitempty" yaml:"authRequestHeaders,omitempty" export:"true"`
+	Cache                    *ForwardAuthCache `json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" export:"true"`
+	ForwardBody              bool              `json:"forwardBody,omitempty" toml:"forwardBody,omitempty" yaml:"forwardBody,omitempty" export:"true"`
+	MaxBodySize              int64             `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ForwardAuthCache holds the configuration of the cache of the successful authentication responses.
+type ForwardAuthCache struct {
+	// TTL is how long an authentication response is cached. It defaults to 10 seconds.
+	TTL ptypes.Duration `json:"ttl,omitempty" toml:"ttl,omitempty" yaml:"ttl,omitempty" export:"true"`
+	// MaxEntries is the maximum number of cached authentication responses. It defaults to 1000.
+	MaxEntries int `json:"maxEntries,omitempty" toml:"maxEntries,omitempty" yaml:"maxEntries,omitempty" export:"true"`
+	// KeyHeaders are the request headers identifying the credentials, e.g. Authorization.
+	// Their values are part of the cache key, which is hashed.
+	KeyHeaders []string `json:"keyHeaders,omitempty" toml:"keyHeaders,omitempty" yaml:"keyHeaders,omitempty" export:"true"`
+	// KeyPathSegments is the number of leading segments of the request path which are part of the cache key,
+	// instead of the whole request URI. It defaults to 0, which means the whole request URI is part of the cache key.
+	KeyPathSegments int `json:"keyPathSegments,omitempty" toml:"keyPathSegments,omitempty" yaml:"keyPathSegments,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a ForwardAuthCache.
+func (f *ForwardAuthCache) SetDefaults() {
+	f.TTL = ptypes.Duration(10 * time.Second)
+	f.MaxEntries = 1000
 }
 
 // +k8s:deepcopy-gen=true
@@ -235,6 +310,34 @@ func (h *Headers) HasSecureHeadersDefined() bool {
 		h.IsDevelopment)
 }
 
+// Deprecation is a deprecated option which is set, with what should be used instead.
+type Deprecation struct {
+	Option      string
+	Replacement string
+}
+
+// Deprecations returns the deprecated options which are set, by their name in the configuration files.
+func (h *Headers) Deprecations() []Deprecation {
+	var deprecations []Deprecation
+	if h.SSLRedirect {
+		deprecations = append(deprecations, Deprecation{Option: "sslRedirect", Replacement: "entrypoint redirection"})
+	}
+	if h.SSLTemporaryRedirect {
+		deprecations = append(deprecations, Deprecation{Option: "sslTemporaryRedirect", Replacement: "entrypoint redirection"})
+	}
+	if h.SSLHost != "" {
+		deprecations = append(deprecations, Deprecation{Option: "sslHost", Replacement: "RedirectRegex middleware"})
+	}
+	if h.SSLForceHost {
+		deprecations = append(deprecations, Deprecation{Option: "sslForceHost", Replacement: "RedirectScheme middleware"})
+	}
+	if h.FeaturePolicy != "" {
+		deprecations = append(deprecations, Deprecation{Option: "featurePolicy", Replacement: "PermissionsPolicy header"})
+	}
+
+	return deprecations
+}
+
 // +k8s:deepcopy-gen=true
 
 // IPStrategy holds the ip strategy configuration.
@@ -276,8 +379,11 @@ func (s *IPStrategy) Get() (ip.Strategy, error) {
 
 // IPWhiteList holds the ip white list configuration.
 type IPWhiteList struct {
+	// SourceRange holds the allowed IPs and CIDRs, and the absolute paths of the files or the HTTP(S) URLs listing them.
 	SourceRange []string    `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
 	IPStrategy  *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty"  label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// RefreshInterval defines how often the files and the URLs of the source range are read again.
+	RefreshInterval ptypes.Duration `json:"refreshInterval,omitempty" toml:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -290,6 +396,131 @@ type InFlightReq struct {
 
 // +k8s:deepcopy-gen=true
 
+// JWT holds the JWT middleware configuration.
+// This middleware validates the Bearer tokens of the requests, and rejects the requests without a valid token.
+type JWT struct {
+	// Keys are the PEM encoded public keys or certificates, or the HMAC secrets, verifying the signature of the tokens.
+	Keys []string `json:"keys,omitempty" toml:"keys,omitempty" yaml:"keys,omitempty"`
+	// JWKSURL is the URL of the JSON Web Key Set verifying the signature of the tokens.
+	JWKSURL string `json:"jwksURL,omitempty" toml:"jwksURL,omitempty" yaml:"jwksURL,omitempty" export:"true"`
+	// JWKSRefreshInterval defines how often the JSON Web Key Set is fetched again.
+	JWKSRefreshInterval ptypes.Duration `json:"jwksRefreshInterval,omitempty" toml:"jwksRefreshInterval,omitempty" yaml:"jwksRefreshInterval,omitempty" export:"true"`
+	// Issuer is the expected "iss" claim of the tokens.
+	Issuer string `json:"issuer,omitempty" toml:"issuer,omitempty" yaml:"issuer,omitempty" export:"true"`
+	// Audiences are the accepted "aud" claims of the tokens, the tokens having to be issued for one of them.
+	Audiences []string `json:"audiences,omitempty" toml:"audiences,omitempty" yaml:"audiences,omitempty" export:"true"`
+	// ForwardClaims maps the names of the request headers to the names of the claims they are set to.
+	ForwardClaims map[string]string `json:"forwardClaims,omitempty" toml:"forwardClaims,omitempty" yaml:"forwardClaims,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a JWT.
+func (j *JWT) SetDefaults() {
+	j.JWKSRefreshInterval = ptypes.Duration(15 * time.Minute)
+}
+
+// +k8s:deepcopy-gen=true
+
+// HeadersLimit holds the headers limit configuration.
+// This middleware rejects the requests whose headers exceed the limits. A zero limit means no limit.
+type HeadersLimit struct {
+	// MaxCount is the maximum number of header fields, each value of a multi-valued header counting as a field.
+	MaxCount int `json:"maxCount,omitempty" toml:"maxCount,omitempty" yaml:"maxCount,omitempty" export:"true"`
+	// MaxHeaderBytes is the maximum size of a header field, its name and its value.
+	MaxHeaderBytes int64 `json:"maxHeaderBytes,omitempty" toml:"maxHeaderBytes,omitempty" yaml:"maxHeaderBytes,omitempty" export:"true"`
+	// MaxTotalBytes is the maximum size of all the header fields.
+	MaxTotalBytes int64 `json:"maxTotalBytes,omitempty" toml:"maxTotalBytes,omitempty" yaml:"maxTotalBytes,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// StaticResponse holds the static response configuration.
+// This middleware answers the requests with the configured response, without forwarding them to the service.
+type StaticResponse struct {
+	// StatusCode is the status code of the response (default: 503).
+	StatusCode int `json:"statusCode,omitempty" toml:"statusCode,omitempty" yaml:"statusCode,omitempty" export:"true"`
+	// Headers are the headers added to the response.
+	Headers map[string]string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	// Body is the body of the response.
+	Body string `json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty"`
+	// BodyFile is the path of a file holding the body of the response, read when the middleware is created.
+	BodyFile string `json:"bodyFile,omitempty" toml:"bodyFile,omitempty" yaml:"bodyFile,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RequestID holds the request ID configuration.
+// This middleware sets a unique ID on each request, or reuses the one of the incoming request,
+// and forwards it in a header of the request and of the response.
+type RequestID struct {
+	// HeaderName is the name of the header holding the request ID (default: X-Request-Id).
+	HeaderName string `json:"headerName,omitempty" toml:"headerName,omitempty" yaml:"headerName,omitempty" export:"true"`
+	// Format is the format of the generated request IDs, uuid (default) or ulid.
+	Format string `json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a RequestID.
+func (r *RequestID) SetDefaults() {
+	r.HeaderName = "X-Request-Id"
+	r.Format = "uuid"
+}
+
+// +k8s:deepcopy-gen=true
+
+// RewriteBody holds the response body rewrite configuration.
+// This middleware applies regular expression replacements to the bodies of the responses of the selected content types,
+// e.g. to rewrite the absolute URLs returned by a backend.
+type RewriteBody struct {
+	// Rewrites are the replacements, applied in order.
+	Rewrites []BodyRewrite `json:"rewrites,omitempty" toml:"rewrites,omitempty" yaml:"rewrites,omitempty" export:"true"`
+	// ContentTypes are the media types of the rewritten responses, such as text/* (default: text/html).
+	ContentTypes []string `json:"contentTypes,omitempty" toml:"contentTypes,omitempty" yaml:"contentTypes,omitempty" export:"true"`
+	// MaxBodySize is the maximum size, in bytes, of a rewritten body (default: 1048576).
+	// The larger bodies are forwarded unchanged.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// BodyRewrite holds a replacement of the RewriteBody middleware.
+type BodyRewrite struct {
+	// Regex is the regular expression matched in the body.
+	Regex string `json:"regex,omitempty" toml:"regex,omitempty" yaml:"regex,omitempty" export:"true"`
+	// Replacement is the replacement of the matches, which can refer to the groups of the regular expression ($1).
+	Replacement string `json:"replacement,omitempty" toml:"replacement,omitempty" yaml:"replacement,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GeoIP holds the GeoIP middleware configuration.
+// This middleware allows or denies the requests by the country and the autonomous system of the client IP,
+// looked up in local MaxMind DB files, and forwards the location of the client to the backends in headers.
+// The database files are read again when they change.
+type GeoIP struct {
+	// Database is the path of the MaxMind DB file of the countries or of the cities, e.g. GeoLite2-City.mmdb.
+	Database string `json:"database,omitempty" toml:"database,omitempty" yaml:"database,omitempty"`
+	// ASNDatabase is the path of the MaxMind DB file of the autonomous systems, e.g. GeoLite2-ASN.mmdb.
+	ASNDatabase string `json:"asnDatabase,omitempty" toml:"asnDatabase,omitempty" yaml:"asnDatabase,omitempty"`
+	// AllowedCountries are the ISO 3166-1 codes of the only allowed countries.
+	AllowedCountries []string `json:"allowedCountries,omitempty" toml:"allowedCountries,omitempty" yaml:"allowedCountries,omitempty" export:"true"`
+	// DeniedCountries are the ISO 3166-1 codes of the denied countries.
+	DeniedCountries []string `json:"deniedCountries,omitempty" toml:"deniedCountries,omitempty" yaml:"deniedCountries,omitempty" export:"true"`
+	// AllowedASNs are the numbers of the only allowed autonomous systems.
+	AllowedASNs []uint `json:"allowedASNs,omitempty" toml:"allowedASNs,omitempty" yaml:"allowedASNs,omitempty" export:"true"`
+	// DeniedASNs are the numbers of the denied autonomous systems.
+	DeniedASNs []uint `json:"deniedASNs,omitempty" toml:"deniedASNs,omitempty" yaml:"deniedASNs,omitempty" export:"true"`
+	// AllowUnknown allows the client IPs without country or autonomous system in the databases, despite the allowed lists.
+	AllowUnknown bool `json:"allowUnknown,omitempty" toml:"allowUnknown,omitempty" yaml:"allowUnknown,omitempty" export:"true"`
+	// CountryHeader is the name of the request header set to the ISO 3166-1 code of the country of the client.
+	CountryHeader string `json:"countryHeader,omitempty" toml:"countryHeader,omitempty" yaml:"countryHeader,omitempty" export:"true"`
+	// CityHeader is the name of the request header set to the English name of the city of the client.
+	CityHeader string `json:"cityHeader,omitempty" toml:"cityHeader,omitempty" yaml:"cityHeader,omitempty" export:"true"`
+	// ASNHeader is the name of the request header set to the number of the autonomous system of the client.
+	ASNHeader  string      `json:"asnHeader,omitempty" toml:"asnHeader,omitempty" yaml:"asnHeader,omitempty" export:"true"`
+	IPStrategy *IPStrategy `json:"ipStrategy,omitempty" toml:"ipStrategy,omitempty" yaml:"ipStrategy,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // PassTLSClientCert holds the TLS client cert headers configuration.
 type PassTLSClientCert struct {
 	PEM  bool                      `json:"pem,omitempty" toml:"pem,omitempty" yaml:"pem,omitempty" export:"true"`
@@ -377,6 +608,25 @@ type Retry struct {
 
 // +k8s:deepcopy-gen=true
 
+// SignedURL holds the signed URL configuration.
+type SignedURL struct {
+	Secret         string `json:"secret,omitempty" toml:"secret,omitempty" yaml:"secret,omitempty"`
+	Algorithm      string `json:"algorithm,omitempty" toml:"algorithm,omitempty" yaml:"algorithm,omitempty" export:"true"`
+	Encoding       string `json:"encoding,omitempty" toml:"encoding,omitempty" yaml:"encoding,omitempty" export:"true"`
+	ExpiresParam   string `json:"expiresParam,omitempty" toml:"expiresParam,omitempty" yaml:"expiresParam,omitempty" export:"true"`
+	SignatureParam string `json:"signatureParam,omitempty" toml:"signatureParam,omitempty" yaml:"signatureParam,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a SignedURL.
+func (s *SignedURL) SetDefaults() {
+	s.Algorithm = "sha256"
+	s.Encoding = "hex"
+	s.ExpiresParam = "expires"
+	s.SignatureParam = "signature"
+}
+
+// +k8s:deepcopy-gen=true
+
 // StripPrefix holds the StripPrefix configuration.
 type StripPrefix struct {
 	Prefixes   []string `json:"prefixes,omitempty" toml:"prefixes,omitempty" yaml:"prefixes,omitempty" export:"true"`
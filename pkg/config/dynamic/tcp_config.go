@@ -3,6 +3,7 @@ package dynamic
 import (
 	"reflect"
 
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
 
@@ -76,6 +77,7 @@ type TCPServersLoadBalancer struct {
 	// means an infinite deadline (i.e. the reading capability is never closed).
 	TerminationDelay *int           `json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty" export:"true"`
 	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Retry            *TCPRetry      `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 }
 
@@ -121,3 +123,12 @@ type ProxyProtocol struct {
 func (p *ProxyProtocol) SetDefaults() {
 	p.Version = 2
 }
+
+// +k8s:deepcopy-gen=true
+
+// TCPRetry holds the retry configuration of the connections to the servers of a TCP load balancer.
+// Each attempt connects to the next server of the load balancer.
+type TCPRetry struct {
+	Attempts        int             `json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
+	InitialInterval ptypes.Duration `json:"initialInterval,omitempty" toml:"initialInterval,omitempty" yaml:"initialInterval,omitempty" export:"true"`
+}
@@ -4,7 +4,10 @@ package dynamic
 
 // TCPMiddleware holds the TCPMiddleware configuration.
 type TCPMiddleware struct {
-	IPWhiteList *TCPIPWhiteList `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	IPWhiteList  *TCPIPWhiteList  `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
+	SNILimit     *TCPSNILimit     `json:"sniLimit,omitempty" toml:"sniLimit,omitempty" yaml:"sniLimit,omitempty" export:"true"`
+	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty" export:"true"`
+	RateLimit    *TCPRateLimit    `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -13,3 +16,40 @@ type TCPMiddleware struct {
 type TCPIPWhiteList struct {
 	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// TCPSNILimit holds the TCP SNI limit configuration.
+// The limits apply to the connections of each server name (SNI) independently.
+type TCPSNILimit struct {
+	MaxConnections int64 `json:"maxConnections,omitempty" toml:"maxConnections,omitempty" yaml:"maxConnections,omitempty" export:"true"`
+	Average        int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	Burst          int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *TCPSNILimit) SetDefaults() {
+	s.Burst = 1
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPInFlightConn holds the TCP in flight connection configuration.
+// The limit applies to the connections of each source IP independently.
+type TCPInFlightConn struct {
+	Amount int64 `json:"amount,omitempty" toml:"amount,omitempty" yaml:"amount,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPRateLimit holds the TCP connection rate limit configuration.
+// The limit applies to the connections of each source IP independently.
+type TCPRateLimit struct {
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	Burst   int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *TCPRateLimit) SetDefaults() {
+	r.Burst = 1
+}
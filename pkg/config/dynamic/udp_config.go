@@ -2,6 +2,8 @@ package dynamic
 
 import (
 	"reflect"
+
+	ptypes "github.com/traefik/paerser/types"
 )
 
 // +k8s:deepcopy-gen=true
@@ -47,13 +49,19 @@ func (w *UDPWRRService) SetDefaults() {
 type UDPRouter struct {
 	EntryPoints []string `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
 	Service     string   `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	// Timeout overrides the session timeout of the entry points, when set.
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // UDPServersLoadBalancer defines the configuration for a load-balancer of UDP servers.
 type UDPServersLoadBalancer struct {
-	Servers []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	// ProxyProtocol prepends a PROXY protocol version 2 header, with the client address, to each datagram sent to the servers.
+	ProxyProtocol *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// Transparent sends the datagrams to the servers from the client address, instead of a Traefik address (Linux only).
+	Transparent bool        `json:"transparent,omitempty" toml:"transparent,omitempty" yaml:"transparent,omitempty" export:"true"`
+	Servers     []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 }
 
 // Mergeable reports whether the given load-balancer can be merged with the receiver.
@@ -2,21 +2,85 @@
 package label
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/traefik/paerser/parser"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 )
 
+// configurationFilters are the prefixes of the labels holding the dynamic configuration.
+var configurationFilters = []string{"traefik.http", "traefik.tcp", "traefik.udp"}
+
+// Error describes a label which cannot be decoded.
+type Error struct {
+	Label  string
+	Reason string
+}
+
+func (e Error) Error() string {
+	if e.Label == "" {
+		return e.Reason
+	}
+
+	return fmt.Sprintf("label %q: %s", e.Label, e.Reason)
+}
+
+// DecodeError is returned when labels cannot be decoded, and holds the error of each offending label.
+type DecodeError struct {
+	Errors []Error
+}
+
+func (e *DecodeError) Error() string {
+	var msgs []string
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, ", ")
+}
+
 // DecodeConfiguration converts the labels to a configuration.
+// The returned error, if any, is a *DecodeError.
 func DecodeConfiguration(labels map[string]string) (*dynamic.Configuration, error) {
-	conf := &dynamic.Configuration{
-		HTTP: &dynamic.HTTPConfiguration{},
-		TCP:  &dynamic.TCPConfiguration{},
-		UDP:  &dynamic.UDPConfiguration{},
+	conf := newConfiguration()
+
+	err := parser.Decode(labels, conf, parser.DefaultRootName, configurationFilters...)
+	if err != nil {
+		return nil, newDecodeError(labels, err)
 	}
 
-	err := parser.Decode(labels, conf, parser.DefaultRootName, "traefik.http", "traefik.tcp", "traefik.udp")
+	return conf, nil
+}
+
+// DecodeConfigurationStrict converts the labels to a configuration,
+// and additionally rejects the labels of the traefik namespace that are neither configuration labels,
+// nor known labels (e.g. the labels specific to a provider).
+// A known label ending with a dot stands for all the labels starting with it.
+// The returned error, if any, is a *DecodeError holding all the offending labels.
+func DecodeConfigurationStrict(labels map[string]string, knownLabels ...string) (*dynamic.Configuration, error) {
+	var errs []Error
+	for key := range labels {
+		if hasPrefix(key, parser.DefaultRootName+".") && !hasPrefix(key, configurationFilters...) && !isKnown(key, knownLabels) {
+			errs = append(errs, Error{Label: key, Reason: "unknown label"})
+		}
+	}
+
+	conf, err := DecodeConfiguration(labels)
 	if err != nil {
-		return nil, err
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			return nil, err
+		}
+
+		errs = append(errs, decodeErr.Errors...)
+	}
+
+	if len(errs) > 0 {
+		sortErrors(errs)
+		return nil, &DecodeError{Errors: errs}
 	}
 
 	return conf, nil
@@ -32,3 +96,64 @@ func EncodeConfiguration(conf *dynamic.Configuration) (map[string]string, error)
 func Decode(labels map[string]string, element interface{}, filters ...string) error {
 	return parser.Decode(labels, element, parser.DefaultRootName, filters...)
 }
+
+func newConfiguration() *dynamic.Configuration {
+	return &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{},
+		TCP:  &dynamic.TCPConfiguration{},
+		UDP:  &dynamic.UDPConfiguration{},
+	}
+}
+
+// newDecodeError finds out the labels responsible for the given decoding error,
+// by decoding each configuration label on its own.
+func newDecodeError(labels map[string]string, err error) *DecodeError {
+	var errs []Error
+	for key, value := range labels {
+		if !hasPrefix(key, configurationFilters...) {
+			continue
+		}
+
+		labelErr := parser.Decode(map[string]string{key: value}, newConfiguration(), parser.DefaultRootName, configurationFilters...)
+		if labelErr != nil {
+			errs = append(errs, Error{Label: key, Reason: labelErr.Error()})
+		}
+	}
+
+	// The labels are only invalid when combined.
+	if len(errs) == 0 {
+		return &DecodeError{Errors: []Error{{Reason: err.Error()}}}
+	}
+
+	sortErrors(errs)
+
+	return &DecodeError{Errors: errs}
+}
+
+func sortErrors(errs []Error) {
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Label < errs[j].Label
+	})
+}
+
+// isKnown reports whether the label is one of the known labels, or starts with a known label ending with a dot.
+func isKnown(key string, knownLabels []string) bool {
+	for _, known := range knownLabels {
+		if strings.HasSuffix(known, ".") && hasPrefix(key, known) || strings.EqualFold(key, known) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasPrefix reports whether the label starts with one of the given prefixes, regardless of the case.
+func hasPrefix(key string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if len(key) >= len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+			return true
+		}
+	}
+
+	return false
+}
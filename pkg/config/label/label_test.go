@@ -19,6 +19,7 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware1.basicauth.removeheader":                              "true",
 		"traefik.http.middlewares.Middleware1.basicauth.users":                                     "foobar, fiibar",
 		"traefik.http.middlewares.Middleware1.basicauth.usersfile":                                 "foobar",
+		"traefik.http.middlewares.Middleware2.buffering.maxdiskrequestbodybytes":                   "42",
 		"traefik.http.middlewares.Middleware2.buffering.maxrequestbodybytes":                       "42",
 		"traefik.http.middlewares.Middleware2.buffering.maxresponsebodybytes":                      "42",
 		"traefik.http.middlewares.Middleware2.buffering.memrequestbodybytes":                       "42",
@@ -31,10 +32,18 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware5.digestauth.removeheader":                             "true",
 		"traefik.http.middlewares.Middleware5.digestauth.users":                                    "foobar, fiibar",
 		"traefik.http.middlewares.Middleware5.digestauth.usersfile":                                "foobar",
+		"traefik.http.middlewares.Middleware6.errors.contenttype":                                  "foobar",
 		"traefik.http.middlewares.Middleware6.errors.query":                                        "foobar",
+		"traefik.http.middlewares.Middleware6.errors.retry.attempts":                               "42",
+		"traefik.http.middlewares.Middleware6.errors.retry.initialinterval":                        "1s",
 		"traefik.http.middlewares.Middleware6.errors.service":                                      "foobar",
 		"traefik.http.middlewares.Middleware6.errors.status":                                       "foobar, fiibar",
+		"traefik.http.middlewares.Middleware6.errors.template":                                     "foobar",
 		"traefik.http.middlewares.Middleware7.forwardauth.address":                                 "foobar",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.keyheaders":                        "foobar, fiibar",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.keypathsegments":                   "42",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.maxentries":                        "42",
+		"traefik.http.middlewares.Middleware7.forwardauth.cache.ttl":                               "42s",
 		"traefik.http.middlewares.Middleware7.forwardauth.authresponseheaders":                     "foobar, fiibar",
 		"traefik.http.middlewares.Middleware7.forwardauth.authrequestheaders":                      "foobar, fiibar",
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.ca":                                  "foobar",
@@ -43,6 +52,8 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.insecureskipverify":                  "true",
 		"traefik.http.middlewares.Middleware7.forwardauth.tls.key":                                 "foobar",
 		"traefik.http.middlewares.Middleware7.forwardauth.trustforwardheader":                      "true",
+		"traefik.http.middlewares.Middleware7.forwardauth.forwardbody":                             "true",
+		"traefik.http.middlewares.Middleware7.forwardauth.maxbodysize":                             "42",
 		"traefik.http.middlewares.Middleware8.headers.accesscontrolallowcredentials":               "true",
 		"traefik.http.middlewares.Middleware8.headers.allowedhosts":                                "foobar, fiibar",
 		"traefik.http.middlewares.Middleware8.headers.accesscontrolallowheaders":                   "X-foobar, X-fiibar",
@@ -80,6 +91,7 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware8.headers.stsseconds":                                  "42",
 		"traefik.http.middlewares.Middleware9.ipwhitelist.ipstrategy.depth":                        "42",
 		"traefik.http.middlewares.Middleware9.ipwhitelist.ipstrategy.excludedips":                  "foobar, fiibar",
+		"traefik.http.middlewares.Middleware9.ipwhitelist.refreshinterval":                         "10s",
 		"traefik.http.middlewares.Middleware9.ipwhitelist.sourcerange":                             "foobar, fiibar",
 		"traefik.http.middlewares.Middleware10.inflightreq.amount":                                 "42",
 		"traefik.http.middlewares.Middleware10.inflightreq.sourcecriterion.ipstrategy.depth":       "42",
@@ -128,6 +140,49 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.middlewares.Middleware19.compress":                                           "true",
 		"traefik.http.middlewares.Middleware20.plugin.tomato.aaa":                                  "foo1",
 		"traefik.http.middlewares.Middleware20.plugin.tomato.bbb":                                  "foo2",
+		"traefik.http.middlewares.Middleware21.fallback.maxrequestbodybytes":                       "42",
+		"traefik.http.middlewares.Middleware21.fallback.path":                                      "foobar",
+		"traefik.http.middlewares.Middleware21.fallback.service":                                   "foobar",
+		"traefik.http.middlewares.Middleware21.fallback.status":                                    "foobar, fiibar",
+		"traefik.http.middlewares.Middleware22.acceptlanguage.headername":                          "foobar",
+		"traefik.http.middlewares.Middleware22.acceptlanguage.languages":                           "foobar, fiibar",
+		"traefik.http.middlewares.Middleware23.signedurl.algorithm":                                "foobar",
+		"traefik.http.middlewares.Middleware23.signedurl.encoding":                                 "foobar",
+		"traefik.http.middlewares.Middleware23.signedurl.expiresparam":                             "foobar",
+		"traefik.http.middlewares.Middleware23.signedurl.secret":                                   "foobar",
+		"traefik.http.middlewares.Middleware23.signedurl.signatureparam":                           "foobar",
+		"traefik.http.middlewares.Middleware24.headerslimit.maxcount":                              "42",
+		"traefik.http.middlewares.Middleware24.headerslimit.maxheaderbytes":                        "42",
+		"traefik.http.middlewares.Middleware24.headerslimit.maxtotalbytes":                         "42",
+		"traefik.http.middlewares.Middleware25.staticresponse.statuscode":                          "503",
+		"traefik.http.middlewares.Middleware25.staticresponse.headers.name0":                       "foobar",
+		"traefik.http.middlewares.Middleware25.staticresponse.headers.name1":                       "foobar",
+		"traefik.http.middlewares.Middleware25.staticresponse.body":                                "foobar",
+		"traefik.http.middlewares.Middleware25.staticresponse.bodyfile":                            "foobar",
+		"traefik.http.middlewares.Middleware26.jwt.keys":                                           "foobar, fiibar",
+		"traefik.http.middlewares.Middleware26.jwt.jwksurl":                                        "foobar",
+		"traefik.http.middlewares.Middleware26.jwt.jwksrefreshinterval":                            "1s",
+		"traefik.http.middlewares.Middleware26.jwt.issuer":                                         "foobar",
+		"traefik.http.middlewares.Middleware26.jwt.audiences":                                      "foobar, fiibar",
+		"traefik.http.middlewares.Middleware26.jwt.forwardclaims.X-User":                           "sub",
+		"traefik.http.middlewares.Middleware27.requestid.headername":                               "foobar",
+		"traefik.http.middlewares.Middleware27.requestid.format":                                   "foobar",
+		"traefik.http.middlewares.Middleware28.rewritebody.rewrites[0].regex":                      "foobar",
+		"traefik.http.middlewares.Middleware28.rewritebody.rewrites[0].replacement":                "foobar",
+		"traefik.http.middlewares.Middleware28.rewritebody.contenttypes":                           "foobar, fiibar",
+		"traefik.http.middlewares.Middleware28.rewritebody.maxbodysize":                            "42",
+		"traefik.http.middlewares.Middleware29.geoip.database":                                     "foobar",
+		"traefik.http.middlewares.Middleware29.geoip.asndatabase":                                  "foobar",
+		"traefik.http.middlewares.Middleware29.geoip.allowedcountries":                             "foobar, fiibar",
+		"traefik.http.middlewares.Middleware29.geoip.deniedcountries":                              "foobar, fiibar",
+		"traefik.http.middlewares.Middleware29.geoip.allowedasns":                                  "42, 43",
+		"traefik.http.middlewares.Middleware29.geoip.deniedasns":                                   "42, 43",
+		"traefik.http.middlewares.Middleware29.geoip.allowunknown":                                 "true",
+		"traefik.http.middlewares.Middleware29.geoip.countryheader":                                "foobar",
+		"traefik.http.middlewares.Middleware29.geoip.cityheader":                                   "foobar",
+		"traefik.http.middlewares.Middleware29.geoip.asnheader":                                    "foobar",
+		"traefik.http.middlewares.Middleware29.geoip.ipstrategy.depth":                             "42",
+		"traefik.http.middlewares.Middleware29.geoip.ipstrategy.excludedips":                       "foobar, fiibar",
 		"traefik.http.routers.Router0.entrypoints":                                                 "foobar, fiibar",
 		"traefik.http.routers.Router0.middlewares":                                                 "foobar, fiibar",
 		"traefik.http.routers.Router0.priority":                                                    "42",
@@ -139,39 +194,54 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.http.routers.Router1.priority":                                                    "42",
 		"traefik.http.routers.Router1.rule":                                                        "foobar",
 		"traefik.http.routers.Router1.service":                                                     "foobar",
+		"traefik.http.routers.Router1.skipredirections":                                            "true",
+		"traefik.http.routers.Router1.ipstrategy.depth":                                            "42",
+		"traefik.http.routers.Router1.ipstrategy.excludedips":                                      "foobar, fiibar",
 
-		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name0":        "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name1":        "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.hostname":             "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.interval":             "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.path":                 "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.port":                 "42",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.scheme":               "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.timeout":              "foobar",
-		"traefik.http.services.Service0.loadbalancer.healthcheck.followredirects":      "true",
-		"traefik.http.services.Service0.loadbalancer.passhostheader":                   "true",
-		"traefik.http.services.Service0.loadbalancer.responseforwarding.flushinterval": "foobar",
-		"traefik.http.services.Service0.loadbalancer.server.scheme":                    "foobar",
-		"traefik.http.services.Service0.loadbalancer.server.port":                      "8080",
-		"traefik.http.services.Service0.loadbalancer.sticky.cookie.name":               "foobar",
-		"traefik.http.services.Service0.loadbalancer.sticky.cookie.secure":             "true",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.headers.name0":        "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.headers.name1":        "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.hostname":             "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.interval":             "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.path":                 "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.port":                 "42",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.scheme":               "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.timeout":              "foobar",
-		"traefik.http.services.Service1.loadbalancer.healthcheck.followredirects":      "true",
-		"traefik.http.services.Service1.loadbalancer.passhostheader":                   "true",
-		"traefik.http.services.Service1.loadbalancer.responseforwarding.flushinterval": "foobar",
-		"traefik.http.services.Service1.loadbalancer.server.scheme":                    "foobar",
-		"traefik.http.services.Service1.loadbalancer.server.port":                      "8080",
-		"traefik.http.services.Service1.loadbalancer.sticky":                           "false",
-		"traefik.http.services.Service1.loadbalancer.sticky.cookie.name":               "fui",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name0":           "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.headers.name1":           "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.hostname":                "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.interval":                "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.path":                    "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.port":                    "42",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.scheme":                  "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.timeout":                 "foobar",
+		"traefik.http.services.Service0.loadbalancer.healthcheck.followredirects":         "true",
+		"traefik.http.services.Service0.loadbalancer.http2connectionpool.size":            "42",
+		"traefik.http.services.Service0.loadbalancer.warmup.paths":                        "foobar, fiibar",
+		"traefik.http.services.Service0.loadbalancer.warmup.requests":                     "42",
+		"traefik.http.services.Service0.loadbalancer.slowstart.duration":                  "42s",
+		"traefik.http.services.Service0.loadbalancer.passhostheader":                      "true",
+		"traefik.http.services.Service0.loadbalancer.responseforwarding.flushinterval":    "foobar",
+		"traefik.http.services.Service0.loadbalancer.server.scheme":                       "foobar",
+		"traefik.http.services.Service0.loadbalancer.server.port":                         "8080",
+		"traefik.http.services.Service0.loadbalancer.sticky.cookie.name":                  "foobar",
+		"traefik.http.services.Service0.loadbalancer.sticky.cookie.secure":                "true",
+		"traefik.http.services.Service0.loadbalancer.sticky.cookie.store.redis.endpoints": "foobar",
+		"traefik.http.services.Service0.loadbalancer.sticky.cookie.store.redis.password":  "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.headers.name0":           "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.headers.name1":           "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.hostname":                "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.interval":                "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.path":                    "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.port":                    "42",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.scheme":                  "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.timeout":                 "foobar",
+		"traefik.http.services.Service1.loadbalancer.healthcheck.followredirects":         "true",
+		"traefik.http.services.Service1.loadbalancer.passhostheader":                      "true",
+		"traefik.http.services.Service1.loadbalancer.responseforwarding.flushinterval":    "foobar",
+		"traefik.http.services.Service1.loadbalancer.server.scheme":                       "foobar",
+		"traefik.http.services.Service1.loadbalancer.server.port":                         "8080",
+		"traefik.http.services.Service1.loadbalancer.sticky":                              "false",
+		"traefik.http.services.Service1.loadbalancer.sticky.cookie.name":                  "fui",
 
 		"traefik.tcp.middlewares.Middleware0.ipwhitelist.sourcerange":      "foobar, fiibar",
+		"traefik.tcp.middlewares.Middleware1.snilimit.average":             "42",
+		"traefik.tcp.middlewares.Middleware1.snilimit.burst":               "42",
+		"traefik.tcp.middlewares.Middleware1.snilimit.maxconnections":      "42",
+		"traefik.tcp.middlewares.Middleware2.inflightconn.amount":          "42",
+		"traefik.tcp.middlewares.Middleware3.ratelimit.average":            "42",
+		"traefik.tcp.middlewares.Middleware3.ratelimit.burst":              "42",
 		"traefik.tcp.routers.Router0.rule":                                 "foobar",
 		"traefik.tcp.routers.Router0.entrypoints":                          "foobar, fiibar",
 		"traefik.tcp.routers.Router0.service":                              "foobar",
@@ -185,16 +255,23 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.tcp.services.Service0.loadbalancer.server.Port":           "42",
 		"traefik.tcp.services.Service0.loadbalancer.TerminationDelay":      "42",
 		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.version": "42",
+		"traefik.tcp.services.Service0.loadbalancer.retry.attempts":        "42",
+		"traefik.tcp.services.Service0.loadbalancer.retry.initialinterval": "1s",
 		"traefik.tcp.services.Service1.loadbalancer.server.Port":           "42",
 		"traefik.tcp.services.Service1.loadbalancer.TerminationDelay":      "42",
 		"traefik.tcp.services.Service1.loadbalancer.proxyProtocol":         "true",
 
-		"traefik.udp.routers.Router0.entrypoints":                "foobar, fiibar",
-		"traefik.udp.routers.Router0.service":                    "foobar",
-		"traefik.udp.routers.Router1.entrypoints":                "foobar, fiibar",
-		"traefik.udp.routers.Router1.service":                    "foobar",
-		"traefik.udp.services.Service0.loadbalancer.server.Port": "42",
-		"traefik.udp.services.Service1.loadbalancer.server.Port": "42",
+		"traefik.udp.routers.Router0.entrypoints":                          "foobar, fiibar",
+		"traefik.udp.routers.Router0.service":                              "foobar",
+		"traefik.udp.routers.Router0.timeout":                              "1s",
+		"traefik.udp.routers.Router1.entrypoints":                          "foobar, fiibar",
+		"traefik.udp.routers.Router1.service":                              "foobar",
+		"traefik.udp.routers.Router1.timeout":                              "1s",
+		"traefik.udp.services.Service0.loadbalancer.server.Port":           "42",
+		"traefik.udp.services.Service0.loadbalancer.proxyProtocol.version": "42",
+		"traefik.udp.services.Service0.loadbalancer.transparent":           "true",
+		"traefik.udp.services.Service1.loadbalancer.server.Port":           "42",
+		"traefik.udp.services.Service1.loadbalancer.proxyProtocol":         "true",
 	}
 
 	configuration, err := DecodeConfiguration(labels)
@@ -234,6 +311,24 @@ func TestDecodeConfiguration(t *testing.T) {
 						SourceRange: []string{"foobar", "fiibar"},
 					},
 				},
+				"Middleware1": {
+					SNILimit: &dynamic.TCPSNILimit{
+						MaxConnections: 42,
+						Average:        42,
+						Burst:          42,
+					},
+				},
+				"Middleware2": {
+					InFlightConn: &dynamic.TCPInFlightConn{
+						Amount: 42,
+					},
+				},
+				"Middleware3": {
+					RateLimit: &dynamic.TCPRateLimit{
+						Average: 42,
+						Burst:   42,
+					},
+				},
 			},
 			Services: map[string]*dynamic.TCPService{
 				"Service0": {
@@ -245,6 +340,10 @@ func TestDecodeConfiguration(t *testing.T) {
 						},
 						TerminationDelay: func(i int) *int { return &i }(42),
 						ProxyProtocol:    &dynamic.ProxyProtocol{Version: 42},
+						Retry: &dynamic.TCPRetry{
+							Attempts:        42,
+							InitialInterval: ptypes.Duration(time.Second),
+						},
 					},
 				},
 				"Service1": {
@@ -268,6 +367,7 @@ func TestDecodeConfiguration(t *testing.T) {
 						"fiibar",
 					},
 					Service: "foobar",
+					Timeout: ptypes.Duration(time.Second),
 				},
 				"Router1": {
 					EntryPoints: []string{
@@ -275,6 +375,7 @@ func TestDecodeConfiguration(t *testing.T) {
 						"fiibar",
 					},
 					Service: "foobar",
+					Timeout: ptypes.Duration(time.Second),
 				},
 			},
 			Services: map[string]*dynamic.UDPService{
@@ -285,6 +386,8 @@ func TestDecodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						ProxyProtocol: &dynamic.ProxyProtocol{Version: 42},
+						Transparent:   true,
 					},
 				},
 				"Service1": {
@@ -294,6 +397,7 @@ func TestDecodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						ProxyProtocol: &dynamic.ProxyProtocol{Version: 2},
 					},
 				},
 			},
@@ -323,9 +427,14 @@ func TestDecodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service:  "foobar",
-					Rule:     "foobar",
-					Priority: 42,
+					Service:          "foobar",
+					Rule:             "foobar",
+					Priority:         42,
+					SkipRedirections: true,
+					IPStrategy: &dynamic.IPStrategy{
+						Depth:       42,
+						ExcludedIPs: []string{"foobar", "fiibar"},
+					},
 				},
 			},
 			Middlewares: map[string]*dynamic.Middleware{
@@ -452,15 +561,18 @@ func TestDecodeConfiguration(t *testing.T) {
 					},
 				},
 				"Middleware19": {
-					Compress: &dynamic.Compress{},
+					Compress: &dynamic.Compress{
+						MinResponseBodyBytes: 1400,
+					},
 				},
 				"Middleware2": {
 					Buffering: &dynamic.Buffering{
-						MaxRequestBodyBytes:  42,
-						MemRequestBodyBytes:  42,
-						MaxResponseBodyBytes: 42,
-						MemResponseBodyBytes: 42,
-						RetryExpression:      "foobar",
+						MaxRequestBodyBytes:     42,
+						MemRequestBodyBytes:     42,
+						MaxDiskRequestBodyBytes: 42,
+						MaxResponseBodyBytes:    42,
+						MemResponseBodyBytes:    42,
+						RetryExpression:         "foobar",
 					},
 				},
 				"Middleware3": {
@@ -496,6 +608,12 @@ func TestDecodeConfiguration(t *testing.T) {
 						},
 						Service: "foobar",
 						Query:   "foobar",
+						Retry: &dynamic.Retry{
+							Attempts:        42,
+							InitialInterval: ptypes.Duration(time.Second),
+						},
+						Template:    "foobar",
+						ContentType: "foobar",
 					},
 				},
 				"Middleware7": {
@@ -517,6 +635,14 @@ func TestDecodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						Cache: &dynamic.ForwardAuthCache{
+							TTL:             ptypes.Duration(42 * time.Second),
+							MaxEntries:      42,
+							KeyHeaders:      []string{"foobar", "fiibar"},
+							KeyPathSegments: 42,
+						},
+						ForwardBody: true,
+						MaxBodySize: 42,
 					},
 				},
 				"Middleware8": {
@@ -598,6 +724,7 @@ func TestDecodeConfiguration(t *testing.T) {
 								"fiibar",
 							},
 						},
+						RefreshInterval: ptypes.Duration(10 * time.Second),
 					},
 				},
 				"Middleware20": {
@@ -608,6 +735,104 @@ func TestDecodeConfiguration(t *testing.T) {
 						},
 					},
 				},
+				"Middleware21": {
+					Fallback: &dynamic.Fallback{
+						Status: []string{
+							"foobar",
+							"fiibar",
+						},
+						Service:             "foobar",
+						Path:                "foobar",
+						MaxRequestBodyBytes: 42,
+					},
+				},
+				"Middleware22": {
+					AcceptLanguage: &dynamic.AcceptLanguage{
+						Languages: []string{
+							"foobar",
+							"fiibar",
+						},
+						HeaderName: "foobar",
+					},
+				},
+				"Middleware23": {
+					SignedURL: &dynamic.SignedURL{
+						Secret:         "foobar",
+						Algorithm:      "foobar",
+						Encoding:       "foobar",
+						ExpiresParam:   "foobar",
+						SignatureParam: "foobar",
+					},
+				},
+				"Middleware24": {
+					HeadersLimit: &dynamic.HeadersLimit{
+						MaxCount:       42,
+						MaxHeaderBytes: 42,
+						MaxTotalBytes:  42,
+					},
+				},
+				"Middleware25": {
+					StaticResponse: &dynamic.StaticResponse{
+						StatusCode: 503,
+						Headers: map[string]string{
+							"name0": "foobar",
+							"name1": "foobar",
+						},
+						Body:     "foobar",
+						BodyFile: "foobar",
+					},
+				},
+				"Middleware26": {
+					JWT: &dynamic.JWT{
+						Keys:                []string{"foobar", "fiibar"},
+						JWKSURL:             "foobar",
+						JWKSRefreshInterval: ptypes.Duration(time.Second),
+						Issuer:              "foobar",
+						Audiences:           []string{"foobar", "fiibar"},
+						ForwardClaims: map[string]string{
+							"X-User": "sub",
+						},
+					},
+				},
+				"Middleware27": {
+					RequestID: &dynamic.RequestID{
+						HeaderName: "foobar",
+						Format:     "foobar",
+					},
+				},
+				"Middleware28": {
+					RewriteBody: &dynamic.RewriteBody{
+						Rewrites: []dynamic.BodyRewrite{
+							{
+								Regex:       "foobar",
+								Replacement: "foobar",
+							},
+						},
+						ContentTypes: []string{
+							"foobar",
+							"fiibar",
+						},
+						MaxBodySize: 42,
+					},
+				},
+				"Middleware29": {
+					GeoIP: &dynamic.GeoIP{
+						Database:         "foobar",
+						ASNDatabase:      "foobar",
+						AllowedCountries: []string{"foobar", "fiibar"},
+						DeniedCountries:  []string{"foobar", "fiibar"},
+						AllowedASNs:      []uint{42, 43},
+						DeniedASNs:       []uint{42, 43},
+						AllowUnknown:     true,
+						CountryHeader:    "foobar",
+						CityHeader:       "foobar",
+						ASNHeader:        "foobar",
+						IPStrategy: &dynamic.IPStrategy{
+							Depth:       42,
+							ExcludedIPs: []string{"foobar", "fiibar"},
+						},
+					},
+				},
 			},
 			Services: map[string]*dynamic.Service{
 				"Service0": {
@@ -617,6 +842,13 @@ func TestDecodeConfiguration(t *testing.T) {
 								Name:     "foobar",
 								Secure:   true,
 								HTTPOnly: false,
+								Store: &dynamic.CookieStore{
+									Redis: &dynamic.RedisCookieStore{
+										Endpoints: []string{"foobar"},
+										Password:  "foobar",
+										TTL:       ptypes.Duration(24 * time.Hour),
+									},
+								},
 							},
 						},
 						Servers: []dynamic.Server{
@@ -642,6 +874,19 @@ func TestDecodeConfiguration(t *testing.T) {
 						ResponseForwarding: &dynamic.ResponseForwarding{
 							FlushInterval: "foobar",
 						},
+						HTTP2ConnectionPool: &dynamic.HTTP2ConnectionPool{
+							Size: 42,
+						},
+						WarmUp: &dynamic.WarmUp{
+							Paths:           []string{"foobar", "fiibar"},
+							Requests:        42,
+							Concurrency:     1,
+							Timeout:         ptypes.Duration(5 * time.Second),
+							MinSuccessRatio: 1,
+						},
+						SlowStart: &dynamic.SlowStart{
+							Duration: ptypes.Duration(42 * time.Second),
+						},
 					},
 				},
 				"Service1": {
@@ -678,6 +923,113 @@ func TestDecodeConfiguration(t *testing.T) {
 	assert.Equal(t, expected, configuration)
 }
 
+func TestDecodeConfiguration_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected []Error
+	}{
+		{
+			desc: "unknown field",
+			labels: map[string]string{
+				"traefik.http.routers.foo.rule":  "Host(`foo.localhost`)",
+				"traefik.http.routers.foo.rulee": "Host(`foo.localhost`)",
+			},
+			expected: []Error{
+				{Label: "traefik.http.routers.foo.rulee", Reason: "field not found, node: rulee"},
+			},
+		},
+		{
+			desc: "invalid values",
+			labels: map[string]string{
+				"traefik.http.routers.foo.priority":                 "high",
+				"traefik.udp.services.bar.loadbalancer.transparent": "yes",
+				"traefik.http.routers.foo.entrypoints":              "web",
+			},
+			expected: []Error{
+				{Label: "traefik.http.routers.foo.priority", Reason: `strconv.ParseInt: parsing "high": invalid syntax`},
+				{Label: "traefik.udp.services.bar.loadbalancer.transparent", Reason: `strconv.ParseBool: parsing "yes": invalid syntax`},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := DecodeConfiguration(test.labels)
+			require.Error(t, err)
+
+			var decodeErr *DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, test.expected, decodeErr.Errors)
+		})
+	}
+}
+
+func TestDecodeConfigurationStrict(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		labels   map[string]string
+		expected []Error
+	}{
+		{
+			desc: "valid labels",
+			labels: map[string]string{
+				"traefik.enable":                "true",
+				"traefik.docker.network":        "foo",
+				"traefik.http.routers.foo.rule": "Host(`foo.localhost`)",
+				"com.docker.compose.service":    "foo",
+			},
+		},
+		{
+			desc: "unknown labels",
+			labels: map[string]string{
+				"traefik.enabled":               "true",
+				"traefik.htp.routers.foo.rule":  "Host(`foo.localhost`)",
+				"traefik.http.routers.foo.rule": "Host(`foo.localhost`)",
+				"com.docker.compose.service":    "foo",
+			},
+			expected: []Error{
+				{Label: "traefik.enabled", Reason: "unknown label"},
+				{Label: "traefik.htp.routers.foo.rule", Reason: "unknown label"},
+			},
+		},
+		{
+			desc: "unknown labels and invalid values",
+			labels: map[string]string{
+				"Traefik.Docker.Network":            "foo",
+				"traefik.dockr.network":             "foo",
+				"traefik.http.routers.foo.priority": "high",
+			},
+			expected: []Error{
+				{Label: "traefik.dockr.network", Reason: "unknown label"},
+				{Label: "traefik.http.routers.foo.priority", Reason: `strconv.ParseInt: parsing "high": invalid syntax`},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf, err := DecodeConfigurationStrict(test.labels, "traefik.docker.", "traefik.enable")
+
+			if test.expected == nil {
+				require.NoError(t, err)
+				assert.NotNil(t, conf)
+				return
+			}
+
+			var decodeErr *DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, test.expected, decodeErr.Errors)
+		})
+	}
+}
+
 func TestEncodeConfiguration(t *testing.T) {
 	configuration := &dynamic.Configuration{
 		TCP: &dynamic.TCPConfiguration{
@@ -713,6 +1065,24 @@ func TestEncodeConfiguration(t *testing.T) {
 						SourceRange: []string{"foobar", "fiibar"},
 					},
 				},
+				"Middleware1": {
+					SNILimit: &dynamic.TCPSNILimit{
+						MaxConnections: 42,
+						Average:        42,
+						Burst:          42,
+					},
+				},
+				"Middleware2": {
+					InFlightConn: &dynamic.TCPInFlightConn{
+						Amount: 42,
+					},
+				},
+				"Middleware3": {
+					RateLimit: &dynamic.TCPRateLimit{
+						Average: 42,
+						Burst:   42,
+					},
+				},
 			},
 			Services: map[string]*dynamic.TCPService{
 				"Service0": {
@@ -723,6 +1093,10 @@ func TestEncodeConfiguration(t *testing.T) {
 							},
 						},
 						TerminationDelay: func(i int) *int { return &i }(42),
+						Retry: &dynamic.TCPRetry{
+							Attempts:        42,
+							InitialInterval: ptypes.Duration(time.Second),
+						},
 					},
 				},
 				"Service1": {
@@ -745,6 +1119,7 @@ func TestEncodeConfiguration(t *testing.T) {
 						"fiibar",
 					},
 					Service: "foobar",
+					Timeout: ptypes.Duration(time.Second),
 				},
 				"Router1": {
 					EntryPoints: []string{
@@ -752,6 +1127,7 @@ func TestEncodeConfiguration(t *testing.T) {
 						"fiibar",
 					},
 					Service: "foobar",
+					Timeout: ptypes.Duration(time.Second),
 				},
 			},
 			Services: map[string]*dynamic.UDPService{
@@ -762,6 +1138,7 @@ func TestEncodeConfiguration(t *testing.T) {
 								Port: "42",
 							},
 						},
+						Transparent: true,
 					},
 				},
 				"Service1": {
@@ -800,9 +1177,14 @@ func TestEncodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service:  "foobar",
-					Rule:     "foobar",
-					Priority: 42,
+					Service:          "foobar",
+					Rule:             "foobar",
+					Priority:         42,
+					SkipRedirections: true,
+					IPStrategy: &dynamic.IPStrategy{
+						Depth:       42,
+						ExcludedIPs: []string{"foobar", "fiibar"},
+					},
 				},
 			},
 			Middlewares: map[string]*dynamic.Middleware{
@@ -928,15 +1310,18 @@ func TestEncodeConfiguration(t *testing.T) {
 					},
 				},
 				"Middleware19": {
-					Compress: &dynamic.Compress{},
+					Compress: &dynamic.Compress{
+						MinResponseBodyBytes: 1400,
+					},
 				},
 				"Middleware2": {
 					Buffering: &dynamic.Buffering{
-						MaxRequestBodyBytes:  42,
-						MemRequestBodyBytes:  42,
-						MaxResponseBodyBytes: 42,
-						MemResponseBodyBytes: 42,
-						RetryExpression:      "foobar",
+						MaxRequestBodyBytes:     42,
+						MemRequestBodyBytes:     42,
+						MaxDiskRequestBodyBytes: 42,
+						MaxResponseBodyBytes:    42,
+						MemResponseBodyBytes:    42,
+						RetryExpression:         "foobar",
 					},
 				},
 				"Middleware20": {
@@ -947,6 +1332,104 @@ func TestEncodeConfiguration(t *testing.T) {
 						},
 					},
 				},
+				"Middleware21": {
+					Fallback: &dynamic.Fallback{
+						Status: []string{
+							"foobar",
+							"fiibar",
+						},
+						Service:             "foobar",
+						Path:                "foobar",
+						MaxRequestBodyBytes: 42,
+					},
+				},
+				"Middleware22": {
+					AcceptLanguage: &dynamic.AcceptLanguage{
+						Languages: []string{
+							"foobar",
+							"fiibar",
+						},
+						HeaderName: "foobar",
+					},
+				},
+				"Middleware23": {
+					SignedURL: &dynamic.SignedURL{
+						Secret:         "foobar",
+						Algorithm:      "foobar",
+						Encoding:       "foobar",
+						ExpiresParam:   "foobar",
+						SignatureParam: "foobar",
+					},
+				},
+				"Middleware24": {
+					HeadersLimit: &dynamic.HeadersLimit{
+						MaxCount:       42,
+						MaxHeaderBytes: 42,
+						MaxTotalBytes:  42,
+					},
+				},
+				"Middleware25": {
+					StaticResponse: &dynamic.StaticResponse{
+						StatusCode: 503,
+						Headers: map[string]string{
+							"name0": "foobar",
+							"name1": "foobar",
+						},
+						Body:     "foobar",
+						BodyFile: "foobar",
+					},
+				},
+				"Middleware26": {
+					JWT: &dynamic.JWT{
+						Keys:                []string{"foobar", "fiibar"},
+						JWKSURL:             "foobar",
+						JWKSRefreshInterval: ptypes.Duration(time.Second),
+						Issuer:              "foobar",
+						Audiences:           []string{"foobar", "fiibar"},
+						ForwardClaims: map[string]string{
+							"X-User": "sub",
+						},
+					},
+				},
+				"Middleware27": {
+					RequestID: &dynamic.RequestID{
+						HeaderName: "foobar",
+						Format:     "foobar",
+					},
+				},
+				"Middleware28": {
+					RewriteBody: &dynamic.RewriteBody{
+						Rewrites: []dynamic.BodyRewrite{
+							{
+								Regex:       "foobar",
+								Replacement: "foobar",
+							},
+						},
+						ContentTypes: []string{
+							"foobar",
+							"fiibar",
+						},
+						MaxBodySize: 42,
+					},
+				},
+				"Middleware29": {
+					GeoIP: &dynamic.GeoIP{
+						Database:         "foobar",
+						ASNDatabase:      "foobar",
+						AllowedCountries: []string{"foobar", "fiibar"},
+						DeniedCountries:  []string{"foobar", "fiibar"},
+						AllowedASNs:      []uint{42, 43},
+						DeniedASNs:       []uint{42, 43},
+						AllowUnknown:     true,
+						CountryHeader:    "foobar",
+						CityHeader:       "foobar",
+						ASNHeader:        "foobar",
+						IPStrategy: &dynamic.IPStrategy{
+							Depth:       42,
+							ExcludedIPs: []string{"foobar", "fiibar"},
+						},
+					},
+				},
 				"Middleware3": {
 					Chain: &dynamic.Chain{
 						Middlewares: []string{
@@ -980,6 +1463,12 @@ func TestEncodeConfiguration(t *testing.T) {
 						},
 						Service: "foobar",
 						Query:   "foobar",
+						Retry: &dynamic.Retry{
+							Attempts:        42,
+							InitialInterval: ptypes.Duration(time.Second),
+						},
+						Template:    "foobar",
+						ContentType: "foobar",
 					},
 				},
 				"Middleware7": {
@@ -1001,6 +1490,14 @@ func TestEncodeConfiguration(t *testing.T) {
 							"foobar",
 							"fiibar",
 						},
+						Cache: &dynamic.ForwardAuthCache{
+							TTL:             ptypes.Duration(42 * time.Second),
+							MaxEntries:      42,
+							KeyHeaders:      []string{"foobar", "fiibar"},
+							KeyPathSegments: 42,
+						},
+						ForwardBody: true,
+						MaxBodySize: 42,
 					},
 				},
 				"Middleware8": {
@@ -1082,6 +1579,7 @@ func TestEncodeConfiguration(t *testing.T) {
 								"fiibar",
 							},
 						},
+						RefreshInterval: ptypes.Duration(10 * time.Second),
 					},
 				},
 			},
@@ -1092,6 +1590,13 @@ func TestEncodeConfiguration(t *testing.T) {
 							Cookie: &dynamic.Cookie{
 								Name:     "foobar",
 								HTTPOnly: true,
+								Store: &dynamic.CookieStore{
+									Redis: &dynamic.RedisCookieStore{
+										Endpoints: []string{"foobar"},
+										Password:  "foobar",
+										TTL:       ptypes.Duration(42 * time.Second),
+									},
+								},
 							},
 						},
 						Servers: []dynamic.Server{
@@ -1116,6 +1621,20 @@ func TestEncodeConfiguration(t *testing.T) {
 						ResponseForwarding: &dynamic.ResponseForwarding{
 							FlushInterval: "foobar",
 						},
+						HTTP2ConnectionPool: &dynamic.HTTP2ConnectionPool{
+							Size: 42,
+						},
+						WarmUp: &dynamic.WarmUp{
+							Paths:           []string{"foobar", "fiibar"},
+							Requests:        42,
+							Concurrency:     42,
+							Timeout:         ptypes.Duration(42 * time.Second),
+							Status:          []string{"foobar", "fiibar"},
+							MinSuccessRatio: 0.5,
+						},
+						SlowStart: &dynamic.SlowStart{
+							Duration: ptypes.Duration(42 * time.Second),
+						},
 					},
 				},
 				"Service1": {
@@ -1158,6 +1677,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware1.BasicAuth.RemoveHeader":                              "true",
 		"traefik.HTTP.Middlewares.Middleware1.BasicAuth.Users":                                     "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware1.BasicAuth.UsersFile":                                 "foobar",
+		"traefik.HTTP.Middlewares.Middleware2.Buffering.MaxDiskRequestBodyBytes":                   "42",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.MaxRequestBodyBytes":                       "42",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.MaxResponseBodyBytes":                      "42",
 		"traefik.HTTP.Middlewares.Middleware2.Buffering.MemRequestBodyBytes":                       "42",
@@ -1170,10 +1690,18 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.RemoveHeader":                             "true",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.Users":                                    "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware5.DigestAuth.UsersFile":                                "foobar",
+		"traefik.HTTP.Middlewares.Middleware6.Errors.ContentType":                                  "foobar",
 		"traefik.HTTP.Middlewares.Middleware6.Errors.Query":                                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware6.Errors.Retry.Attempts":                               "42",
+		"traefik.HTTP.Middlewares.Middleware6.Errors.Retry.InitialInterval":                        "1000000000",
 		"traefik.HTTP.Middlewares.Middleware6.Errors.Service":                                      "foobar",
 		"traefik.HTTP.Middlewares.Middleware6.Errors.Status":                                       "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware6.Errors.Template":                                     "foobar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Address":                                 "foobar",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.KeyHeaders":                        "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.KeyPathSegments":                   "42",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.MaxEntries":                        "42",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.Cache.TTL":                               "42000000000",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.AuthResponseHeaders":                     "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.AuthRequestHeaders":                      "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.CA":                                  "foobar",
@@ -1182,6 +1710,8 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.InsecureSkipVerify":                  "true",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TLS.Key":                                 "foobar",
 		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.TrustForwardHeader":                      "true",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.ForwardBody":                             "true",
+		"traefik.HTTP.Middlewares.Middleware7.ForwardAuth.MaxBodySize":                             "42",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.AccessControlAllowCredentials":               "true",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.AccessControlAllowHeaders":                   "X-foobar, X-fiibar",
 		"traefik.HTTP.Middlewares.Middleware8.Headers.AccessControlAllowMethods":                   "GET, PUT",
@@ -1219,6 +1749,7 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware8.Headers.STSSeconds":                                  "42",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.IPStrategy.Depth":                        "42",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.IPStrategy.ExcludedIPs":                  "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.RefreshInterval":                         "10000000000",
 		"traefik.HTTP.Middlewares.Middleware9.IPWhiteList.SourceRange":                             "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.Amount":                                 "42",
 		"traefik.HTTP.Middlewares.Middleware10.InFlightReq.SourceCriterion.IPStrategy.Depth":       "42",
@@ -1265,72 +1796,142 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.Prefixes":                               "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.ForceSlash":                             "true",
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                             "foobar, fiibar",
-		"traefik.HTTP.Middlewares.Middleware19.Compress":                                           "true",
+		"traefik.HTTP.Middlewares.Middleware19.Compress.MinResponseBodyBytes":                      "1400",
 		"traefik.HTTP.Middlewares.Middleware20.Plugin.tomato.aaa":                                  "foo1",
 		"traefik.HTTP.Middlewares.Middleware20.Plugin.tomato.bbb":                                  "foo2",
+		"traefik.HTTP.Middlewares.Middleware21.Fallback.MaxRequestBodyBytes":                       "42",
+		"traefik.HTTP.Middlewares.Middleware21.Fallback.Path":                                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.Fallback.Service":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware21.Fallback.Status":                                    "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware22.AcceptLanguage.HeaderName":                          "foobar",
+		"traefik.HTTP.Middlewares.Middleware22.AcceptLanguage.Languages":                           "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware23.SignedURL.Algorithm":                                "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.SignedURL.Encoding":                                 "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.SignedURL.ExpiresParam":                             "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.SignedURL.Secret":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware23.SignedURL.SignatureParam":                           "foobar",
+		"traefik.HTTP.Middlewares.Middleware24.HeadersLimit.MaxCount":                              "42",
+		"traefik.HTTP.Middlewares.Middleware24.HeadersLimit.MaxHeaderBytes":                        "42",
+		"traefik.HTTP.Middlewares.Middleware24.HeadersLimit.MaxTotalBytes":                         "42",
+		"traefik.HTTP.Middlewares.Middleware25.StaticResponse.StatusCode":                          "503",
+		"traefik.HTTP.Middlewares.Middleware25.StaticResponse.Headers.name0":                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware25.StaticResponse.Headers.name1":                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware25.StaticResponse.Body":                                "foobar",
+		"traefik.HTTP.Middlewares.Middleware25.StaticResponse.BodyFile":                            "foobar",
+		"traefik.HTTP.Middlewares.Middleware26.JWT.Keys":                                           "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware26.JWT.JWKSURL":                                        "foobar",
+		"traefik.HTTP.Middlewares.Middleware26.JWT.JWKSRefreshInterval":                            "1000000000",
+		"traefik.HTTP.Middlewares.Middleware26.JWT.Issuer":                                         "foobar",
+		"traefik.HTTP.Middlewares.Middleware26.JWT.Audiences":                                      "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware26.JWT.ForwardClaims.X-User":                           "sub",
+		"traefik.HTTP.Middlewares.Middleware27.RequestID.HeaderName":                               "foobar",
+		"traefik.HTTP.Middlewares.Middleware27.RequestID.Format":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware28.RewriteBody.Rewrites[0].Regex":                      "foobar",
+		"traefik.HTTP.Middlewares.Middleware28.RewriteBody.Rewrites[0].Replacement":                "foobar",
+		"traefik.HTTP.Middlewares.Middleware28.RewriteBody.ContentTypes":                           "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware28.RewriteBody.MaxBodySize":                            "42",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.Database":                                     "foobar",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.ASNDatabase":                                  "foobar",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.AllowedCountries":                             "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.DeniedCountries":                              "foobar, fiibar",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.AllowedASNs":                                  "42, 43",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.DeniedASNs":                                   "42, 43",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.AllowUnknown":                                 "true",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.CountryHeader":                                "foobar",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.CityHeader":                                   "foobar",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.ASNHeader":                                    "foobar",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.IPStrategy.Depth":                             "42",
+		"traefik.HTTP.Middlewares.Middleware29.GeoIP.IPStrategy.ExcludedIPs":                       "foobar, fiibar",
 
-		"traefik.HTTP.Routers.Router0.EntryPoints": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router0.Middlewares": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router0.Priority":    "42",
-		"traefik.HTTP.Routers.Router0.Rule":        "foobar",
-		"traefik.HTTP.Routers.Router0.Service":     "foobar",
-		"traefik.HTTP.Routers.Router0.TLS":         "true",
-		"traefik.HTTP.Routers.Router1.EntryPoints": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router1.Middlewares": "foobar, fiibar",
-		"traefik.HTTP.Routers.Router1.Priority":    "42",
-		"traefik.HTTP.Routers.Router1.Rule":        "foobar",
-		"traefik.HTTP.Routers.Router1.Service":     "foobar",
+		"traefik.HTTP.Routers.Router0.EntryPoints":            "foobar, fiibar",
+		"traefik.HTTP.Routers.Router0.Middlewares":            "foobar, fiibar",
+		"traefik.HTTP.Routers.Router0.Priority":               "42",
+		"traefik.HTTP.Routers.Router0.Rule":                   "foobar",
+		"traefik.HTTP.Routers.Router0.Service":                "foobar",
+		"traefik.HTTP.Routers.Router0.SkipRedirections":       "false",
+		"traefik.HTTP.Routers.Router0.TLS":                    "true",
+		"traefik.HTTP.Routers.Router1.EntryPoints":            "foobar, fiibar",
+		"traefik.HTTP.Routers.Router1.Middlewares":            "foobar, fiibar",
+		"traefik.HTTP.Routers.Router1.Priority":               "42",
+		"traefik.HTTP.Routers.Router1.Rule":                   "foobar",
+		"traefik.HTTP.Routers.Router1.Service":                "foobar",
+		"traefik.HTTP.Routers.Router1.SkipRedirections":       "true",
+		"traefik.HTTP.Routers.Router1.IPStrategy.Depth":       "42",
+		"traefik.HTTP.Routers.Router1.IPStrategy.ExcludedIPs": "foobar, fiibar",
 
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name1":        "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Hostname":             "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Interval":             "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Path":                 "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Port":                 "42",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Scheme":               "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Timeout":              "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.PassHostHeader":                   "true",
-		"traefik.HTTP.Services.Service0.LoadBalancer.ResponseForwarding.FlushInterval": "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.server.Port":                      "8080",
-		"traefik.HTTP.Services.Service0.LoadBalancer.server.Scheme":                    "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Name":               "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.HTTPOnly":           "true",
-		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Secure":             "false",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Headers.name1":        "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Hostname":             "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Interval":             "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Path":                 "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Port":                 "42",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Scheme":               "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Timeout":              "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.PassHostHeader":                   "true",
-		"traefik.HTTP.Services.Service1.LoadBalancer.ResponseForwarding.FlushInterval": "foobar",
-		"traefik.HTTP.Services.Service1.LoadBalancer.server.Port":                      "8080",
-		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
-		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":        "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name1":           "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Hostname":                "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Interval":                "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Path":                    "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Port":                    "42",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Scheme":                  "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Timeout":                 "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HTTP2ConnectionPool.Size":            "42",
+		"traefik.HTTP.Services.Service0.LoadBalancer.WarmUp.Paths":                        "foobar, fiibar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.WarmUp.Requests":                     "42",
+		"traefik.HTTP.Services.Service0.LoadBalancer.WarmUp.Concurrency":                  "42",
+		"traefik.HTTP.Services.Service0.LoadBalancer.WarmUp.Timeout":                      "42000000000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.WarmUp.Status":                       "foobar, fiibar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.WarmUp.MinSuccessRatio":              "0.500000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.SlowStart.Duration":                  "42000000000",
+		"traefik.HTTP.Services.Service0.LoadBalancer.PassHostHeader":                      "true",
+		"traefik.HTTP.Services.Service0.LoadBalancer.ResponseForwarding.FlushInterval":    "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.server.Port":                         "8080",
+		"traefik.HTTP.Services.Service0.LoadBalancer.server.Scheme":                       "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Name":                  "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.HTTPOnly":              "true",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Secure":                "false",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Store.Redis.Endpoints": "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Store.Redis.Password":  "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.Sticky.Cookie.Store.Redis.TTL":       "42000000000",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Headers.name0":           "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Headers.name1":           "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Hostname":                "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Interval":                "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Path":                    "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Port":                    "42",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Scheme":                  "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.HealthCheck.Timeout":                 "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.PassHostHeader":                      "true",
+		"traefik.HTTP.Services.Service1.LoadBalancer.ResponseForwarding.FlushInterval":    "foobar",
+		"traefik.HTTP.Services.Service1.LoadBalancer.server.Port":                         "8080",
+		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                       "foobar",
+		"traefik.HTTP.Services.Service0.LoadBalancer.HealthCheck.Headers.name0":           "foobar",
 
-		"traefik.TCP.Middlewares.Middleware0.IPWhiteList.SourceRange": "foobar, fiibar",
-		"traefik.TCP.Routers.Router0.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router0.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router0.Service":                         "foobar",
-		"traefik.TCP.Routers.Router0.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router0.TLS.Options":                     "foo",
-		"traefik.TCP.Routers.Router1.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router1.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router1.Service":                         "foobar",
-		"traefik.TCP.Routers.Router1.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router1.TLS.Options":                     "foo",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay": "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay": "42",
+		"traefik.TCP.Middlewares.Middleware0.IPWhiteList.SourceRange":      "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware1.SNILimit.Average":             "42",
+		"traefik.TCP.Middlewares.Middleware1.SNILimit.Burst":               "42",
+		"traefik.TCP.Middlewares.Middleware1.SNILimit.MaxConnections":      "42",
+		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":          "42",
+		"traefik.TCP.Middlewares.Middleware3.RateLimit.Average":            "42",
+		"traefik.TCP.Middlewares.Middleware3.RateLimit.Burst":              "42",
+		"traefik.TCP.Routers.Router0.Rule":                                 "foobar",
+		"traefik.TCP.Routers.Router0.EntryPoints":                          "foobar, fiibar",
+		"traefik.TCP.Routers.Router0.Service":                              "foobar",
+		"traefik.TCP.Routers.Router0.TLS.Passthrough":                      "false",
+		"traefik.TCP.Routers.Router0.TLS.Options":                          "foo",
+		"traefik.TCP.Routers.Router1.Rule":                                 "foobar",
+		"traefik.TCP.Routers.Router1.EntryPoints":                          "foobar, fiibar",
+		"traefik.TCP.Routers.Router1.Service":                              "foobar",
+		"traefik.TCP.Routers.Router1.TLS.Passthrough":                      "false",
+		"traefik.TCP.Routers.Router1.TLS.Options":                          "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":           "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay":      "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.Retry.Attempts":        "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.Retry.InitialInterval": "1000000000",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":           "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay":      "42",
 
 		"traefik.UDP.Routers.Router0.EntryPoints":                "foobar, fiibar",
 		"traefik.UDP.Routers.Router0.Service":                    "foobar",
+		"traefik.UDP.Routers.Router0.Timeout":                    "1000000000",
 		"traefik.UDP.Routers.Router1.EntryPoints":                "foobar, fiibar",
 		"traefik.UDP.Routers.Router1.Service":                    "foobar",
+		"traefik.UDP.Routers.Router1.Timeout":                    "1000000000",
 		"traefik.UDP.Services.Service0.LoadBalancer.server.Port": "42",
+		"traefik.UDP.Services.Service0.LoadBalancer.Transparent": "true",
 		"traefik.UDP.Services.Service1.LoadBalancer.server.Port": "42",
+		"traefik.UDP.Services.Service1.LoadBalancer.Transparent": "false",
 	}
 
 	for key, val := range expected {
@@ -1,8 +1,10 @@
 package runtime
 
 import (
+	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
@@ -25,6 +27,36 @@ type Configuration struct {
 	TCPServices    map[string]*TCPServiceInfo    `json:"tcpServices,omitempty"`
 	UDPRouters     map[string]*UDPRouterInfo     `json:"udpRouters,omitempty"`
 	UDPServices    map[string]*UDPServiceInfo    `json:"udpServices,omitempty"`
+	Conflicts      []*ConflictInfo               `json:"conflicts,omitempty"`
+	LabelErrors    []*LabelErrorInfo             `json:"labelErrors,omitempty"`
+	RejectedReload *RejectedReload               `json:"rejectedReload,omitempty"`
+
+	// Namespaces are the namespaces of the resources defining the elements, for the providers having namespaces.
+	Namespaces []dynamic.ElementNamespace `json:"-"`
+}
+
+// RejectedReload describes the last dynamic configuration rejected because some of its routers failed to build,
+// the previous configuration being kept.
+type RejectedReload struct {
+	Date  time.Time `json:"date"`
+	Error string    `json:"error"`
+}
+
+// ConflictInfo holds information about an element dropped because it is defined multiple times
+// with different configurations by a provider.
+type ConflictInfo struct {
+	dynamic.Conflict // dynamic configuration
+	// Status reports whether the element is enabled or not.
+	// It is always set to warning, as the element is dropped but the rest of the configuration is not affected.
+	Status string `json:"status,omitempty"`
+}
+
+// LabelErrorInfo holds information about a label which cannot be decoded by a provider.
+type LabelErrorInfo struct {
+	dynamic.LabelError // dynamic configuration
+	// Status reports whether the element is enabled or not.
+	// It is always set to disabled, as the source defining the label is dropped by the provider.
+	Status string `json:"status,omitempty"`
 }
 
 // NewConfig returns a Configuration initialized with the given conf. It never returns nil.
@@ -33,7 +65,15 @@ func NewConfig(conf dynamic.Configuration) *Configuration {
 		return &Configuration{}
 	}
 
-	runtimeConfig := &Configuration{}
+	runtimeConfig := &Configuration{Namespaces: conf.Namespaces}
+
+	for _, conflict := range conf.Conflicts {
+		runtimeConfig.Conflicts = append(runtimeConfig.Conflicts, &ConflictInfo{Conflict: conflict, Status: StatusWarning})
+	}
+
+	for _, labelError := range conf.LabelErrors {
+		runtimeConfig.LabelErrors = append(runtimeConfig.LabelErrors, &LabelErrorInfo{LabelError: labelError, Status: StatusDisabled})
+	}
 
 	if conf.HTTP != nil {
 		routers := conf.HTTP.Routers
@@ -223,6 +263,90 @@ func (c *Configuration) PopulateUsedBy() {
 	}
 }
 
+// FailingRouters returns the names of the routers disabled because of their errors, with their protocol, sorted.
+func (c *Configuration) FailingRouters() []string {
+	var failing []string
+
+	for name, info := range c.Routers {
+		if info.Status == StatusDisabled {
+			failing = append(failing, name+" (http)")
+		}
+	}
+	for name, info := range c.TCPRouters {
+		if info.Status == StatusDisabled {
+			failing = append(failing, name+" (tcp)")
+		}
+	}
+	for name, info := range c.UDPRouters {
+		if info.Status == StatusDisabled {
+			failing = append(failing, name+" (udp)")
+		}
+	}
+
+	sort.Strings(failing)
+
+	return failing
+}
+
+// MarkStale adds a warning to all the elements of the given providers,
+// whose configuration is stale because they did not send any update since the given time.
+func (c *Configuration) MarkStale(staleProviders map[string]time.Time) {
+	if len(staleProviders) == 0 {
+		return
+	}
+
+	staleErr := func(elementName string) error {
+		providerName := getProviderName(elementName)
+		lastUpdate, ok := staleProviders[providerName]
+		if !ok {
+			return nil
+		}
+
+		return fmt.Errorf("the configuration of the provider %s is stale: no update received since %s", providerName, lastUpdate.Format(time.RFC3339))
+	}
+
+	for name, info := range c.Routers {
+		if err := staleErr(name); err != nil {
+			info.AddError(err, false)
+		}
+	}
+	for name, info := range c.Services {
+		if err := staleErr(name); err != nil {
+			info.AddError(err, false)
+		}
+	}
+	for name, info := range c.Middlewares {
+		if err := staleErr(name); err != nil {
+			info.AddError(err, false)
+		}
+	}
+	for name, info := range c.TCPRouters {
+		if err := staleErr(name); err != nil {
+			info.AddError(err, false)
+		}
+	}
+	for name, info := range c.TCPServices {
+		if err := staleErr(name); err != nil {
+			info.AddError(err, false)
+		}
+	}
+	for name, info := range c.TCPMiddlewares {
+		if err := staleErr(name); err != nil {
+			info.AddError(err, false)
+		}
+	}
+	for name, info := range c.UDPRouters {
+		if err := staleErr(name); err != nil {
+			info.AddError(err, false)
+		}
+	}
+	for name, info := range c.UDPServices {
+		if err := staleErr(name); err != nil {
+			info.AddError(err, false)
+		}
+	}
+}
+
 func contains(entryPoints []string, entryPointName string) bool {
 	for _, name := range entryPoints {
 		if name == entryPointName {
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/tls"
 )
 
 // GetRoutersByEntryPoints returns all the http routers by entry points name and routers name.
@@ -77,6 +79,9 @@ type RouterInfo struct {
 	// It is the caller's responsibility to set the initial status.
 	Status string   `json:"status,omitempty"`
 	Using  []string `json:"using,omitempty"` // Effective entry points used by that router.
+	// Certificates are the certificates served for the domains of the rule, keyed by domain.
+	// A domain without certificate is refused by the TLS handshake (strict SNI).
+	Certificates map[string]*tls.CertificateInfo `json:"certificates,omitempty"`
 }
 
 // AddError adds err to r.Err, if it does not already exist.
@@ -143,6 +148,49 @@ type ServiceInfo struct {
 
 	serverStatusMu sync.RWMutex
 	serverStatus   map[string]string // keyed by server URL
+
+	rampStatusMu sync.RWMutex
+	rampStatus   *RampStatus
+
+	serverHealthChecksMu sync.RWMutex
+	serverHealthChecks   map[string]ServerHealthCheck // keyed by server URL
+}
+
+// ServerHealthCheck holds the outcome of the health checks of a server.
+type ServerHealthCheck struct {
+	// Status is the status of the server resulting from the health checks: UP or DOWN.
+	Status string `json:"status,omitempty"`
+	Passed uint64 `json:"passed"`
+	Failed uint64 `json:"failed"`
+	// ConsecutiveFailures is the number of health checks which failed since the last one which passed.
+	ConsecutiveFailures uint64 `json:"consecutiveFailures"`
+	// LastLatency is the duration of the last health check, in seconds.
+	LastLatency float64 `json:"lastLatency"`
+	// LastError is the reason of the failure of the last health check, if it failed.
+	LastError string    `json:"lastError,omitempty"`
+	LastCheck time.Time `json:"lastCheck"`
+}
+
+// States of the weight ramp of a weighted service.
+const (
+	RampRunning   = "running"
+	RampCompleted = "completed"
+	RampAborted   = "aborted"
+)
+
+// RampStatus holds the progress of the weight ramp of a weighted service.
+type RampStatus struct {
+	// Service is the service whose weight is ramped.
+	Service string `json:"service,omitempty"`
+	// State is the state of the ramp: running, completed, or aborted.
+	State string `json:"state,omitempty"`
+	// Weight is the current weight of the service.
+	Weight float64 `json:"weight"`
+	// Progress is the progress of the ramp, between 0 and 1.
+	Progress float64 `json:"progress"`
+	// ErrorRatio is the ratio of 5XX responses of the service measured over the last interval.
+	ErrorRatio float64   `json:"errorRatio"`
+	StartedAt  time.Time `json:"startedAt"`
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -178,6 +226,58 @@ func (s *ServiceInfo) UpdateServerStatus(server, status string) {
 	s.serverStatus[server] = status
 }
 
+// UpdateRampStatus sets the status of the weight ramp of the service.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) UpdateRampStatus(status RampStatus) {
+	s.rampStatusMu.Lock()
+	defer s.rampStatusMu.Unlock()
+
+	s.rampStatus = &status
+}
+
+// GetRampStatus returns the status of the weight ramp of the service, if any.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetRampStatus() *RampStatus {
+	s.rampStatusMu.RLock()
+	defer s.rampStatusMu.RUnlock()
+
+	if s.rampStatus == nil {
+		return nil
+	}
+
+	status := *s.rampStatus
+	return &status
+}
+
+// UpdateServerHealthCheck sets the outcome of the health checks of the server in the ServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) UpdateServerHealthCheck(server string, healthCheck ServerHealthCheck) {
+	s.serverHealthChecksMu.Lock()
+	defer s.serverHealthChecksMu.Unlock()
+
+	if s.serverHealthChecks == nil {
+		s.serverHealthChecks = make(map[string]ServerHealthCheck)
+	}
+	s.serverHealthChecks[server] = healthCheck
+}
+
+// GetServerHealthChecks returns the outcome of the health checks of all the servers in ServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetServerHealthChecks() map[string]ServerHealthCheck {
+	s.serverHealthChecksMu.RLock()
+	defer s.serverHealthChecksMu.RUnlock()
+
+	if len(s.serverHealthChecks) == 0 {
+		return nil
+	}
+
+	healthChecks := make(map[string]ServerHealthCheck, len(s.serverHealthChecks))
+	for k, v := range s.serverHealthChecks {
+		healthChecks[k] = v
+	}
+	return healthChecks
+}
+
 // GetAllStatus returns all the statuses of all the servers in ServiceInfo.
 // It is the responsibility of the caller to check that s is not nil.
 func (s *ServiceInfo) GetAllStatus() map[string]string {
@@ -2,6 +2,7 @@ package runtime_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -688,3 +689,95 @@ func TestPopulateUsedBy(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfig_conflicts(t *testing.T) {
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{},
+		Conflicts: []dynamic.Conflict{
+			{Kind: "router", Name: "foo@myprovider", Sources: []string{"container-1", "container-2"}},
+		},
+	}
+
+	runtimeConf := runtime.NewConfig(conf)
+
+	expected := []*runtime.ConflictInfo{
+		{
+			Conflict: dynamic.Conflict{Kind: "router", Name: "foo@myprovider", Sources: []string{"container-1", "container-2"}},
+			Status:   runtime.StatusWarning,
+		},
+	}
+	assert.Equal(t, expected, runtimeConf.Conflicts)
+}
+
+func TestConfiguration_MarkStale(t *testing.T) {
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo@stale":  {Service: "foo@stale"},
+				"bar@active": {Service: "bar@active"},
+			},
+			Services: map[string]*dynamic.Service{
+				"foo@stale": {},
+			},
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers: map[string]*dynamic.TCPRouter{
+				"foo@stale": {Service: "foo@stale"},
+			},
+		},
+	}
+
+	runtimeConf := runtime.NewConfig(conf)
+	runtimeConf.MarkStale(map[string]time.Time{"stale": time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)})
+
+	expectedErr := []string{"the configuration of the provider stale is stale: no update received since 2021-01-01T00:00:00Z"}
+
+	assert.Equal(t, runtime.StatusWarning, runtimeConf.Routers["foo@stale"].Status)
+	assert.Equal(t, expectedErr, runtimeConf.Routers["foo@stale"].Err)
+	assert.Equal(t, runtime.StatusWarning, runtimeConf.Services["foo@stale"].Status)
+	assert.Equal(t, expectedErr, runtimeConf.Services["foo@stale"].Err)
+	assert.Equal(t, runtime.StatusWarning, runtimeConf.TCPRouters["foo@stale"].Status)
+	assert.Equal(t, expectedErr, runtimeConf.TCPRouters["foo@stale"].Err)
+
+	assert.Equal(t, runtime.StatusEnabled, runtimeConf.Routers["bar@active"].Status)
+	assert.Empty(t, runtimeConf.Routers["bar@active"].Err)
+}
+
+func TestConfiguration_FailingRouters(t *testing.T) {
+	conf := &runtime.Configuration{
+		Routers: map[string]*runtime.RouterInfo{
+			"foo@file": {Status: runtime.StatusEnabled},
+			"bar@file": {Status: runtime.StatusDisabled},
+			"baz@file": {Status: runtime.StatusWarning},
+		},
+		TCPRouters: map[string]*runtime.TCPRouterInfo{
+			"bar@file": {Status: runtime.StatusDisabled},
+		},
+		UDPRouters: map[string]*runtime.UDPRouterInfo{
+			"foo@file": {Status: runtime.StatusDisabled},
+			"bar@file": {Status: runtime.StatusEnabled},
+		},
+	}
+
+	assert.Equal(t, []string{"bar@file (http)", "bar@file (tcp)", "foo@file (udp)"}, conf.FailingRouters())
+	assert.Empty(t, (&runtime.Configuration{}).FailingRouters())
+}
+
+func TestNewConfig_labelErrors(t *testing.T) {
+	conf := dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{},
+		LabelErrors: []dynamic.LabelError{
+			{Provider: "myprovider", Source: "container-1", Label: "traefik.htp.routers.foo.rule", Reason: "unknown label"},
+		},
+	}
+
+	runtimeConf := runtime.NewConfig(conf)
+
+	expected := []*runtime.LabelErrorInfo{
+		{
+			LabelError: dynamic.LabelError{Provider: "myprovider", Source: "container-1", Label: "traefik.htp.routers.foo.rule", Reason: "unknown label"},
+			Status:     runtime.StatusDisabled,
+		},
+	}
+	assert.Equal(t, expected, runtimeConf.LabelErrors)
+}
@@ -0,0 +1,154 @@
+package static
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Changes holds the differences between two static configurations.
+// The new entry points and the new providers can be applied without a restart,
+// whereas the other changes, listed by option name, require one.
+type Changes struct {
+	EntryPoints     EntryPoints
+	Providers       Providers
+	RestartRequired []string
+}
+
+// HasAdditions returns true if there are entry points or providers to add.
+func (c Changes) HasAdditions() bool {
+	return len(c.EntryPoints) > 0 || len(c.AddedProviders()) > 0
+}
+
+// AddedProviders returns the names of the providers to add.
+func (c Changes) AddedProviders() []string {
+	var names []string
+
+	value := reflect.ValueOf(c.Providers)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			names = append(names, jsonName(value.Type().Field(i)))
+		}
+	}
+
+	return names
+}
+
+// Apply returns the given configuration, with the new entry points and the new providers.
+func (c Changes) Apply(conf Configuration) Configuration {
+	entryPoints := make(EntryPoints, len(conf.EntryPoints)+len(c.EntryPoints))
+	for name, entryPoint := range conf.EntryPoints {
+		entryPoints[name] = entryPoint
+	}
+	for name, entryPoint := range c.EntryPoints {
+		entryPoints[name] = entryPoint
+	}
+	conf.EntryPoints = entryPoints
+
+	providers := Providers{}
+	if conf.Providers != nil {
+		providers = *conf.Providers
+	}
+
+	providersValue := reflect.ValueOf(&providers).Elem()
+	addedValue := reflect.ValueOf(c.Providers)
+	for i := 0; i < addedValue.NumField(); i++ {
+		field := addedValue.Field(i)
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			providersValue.Field(i).Set(field)
+		}
+	}
+	conf.Providers = &providers
+
+	return conf
+}
+
+// Diff returns the changes from the current static configuration to the updated one.
+func Diff(current, updated *Configuration) Changes {
+	changes := Changes{EntryPoints: make(EntryPoints)}
+
+	for name, entryPoint := range updated.EntryPoints {
+		currentEntryPoint, ok := current.EntryPoints[name]
+		if !ok {
+			changes.EntryPoints[name] = entryPoint
+			continue
+		}
+
+		if !jsonEqual(currentEntryPoint, entryPoint) {
+			changes.RestartRequired = append(changes.RestartRequired, "entryPoints."+name)
+		}
+	}
+
+	for name := range current.EntryPoints {
+		if _, ok := updated.EntryPoints[name]; !ok {
+			changes.RestartRequired = append(changes.RestartRequired, "entryPoints."+name)
+		}
+	}
+
+	currentProviders, updatedProviders := Providers{}, Providers{}
+	if current.Providers != nil {
+		currentProviders = *current.Providers
+	}
+	if updated.Providers != nil {
+		updatedProviders = *updated.Providers
+	}
+
+	currentValue := reflect.ValueOf(currentProviders)
+	updatedValue := reflect.ValueOf(updatedProviders)
+	addedValue := reflect.ValueOf(&changes.Providers).Elem()
+
+	for i := 0; i < currentValue.NumField(); i++ {
+		currentField, updatedField := currentValue.Field(i), updatedValue.Field(i)
+
+		// A provider which is not enabled yet can be added.
+		if currentField.Kind() == reflect.Ptr && currentField.IsNil() && !updatedField.IsNil() {
+			addedValue.Field(i).Set(updatedField)
+			continue
+		}
+
+		if !jsonEqual(currentField.Interface(), updatedField.Interface()) {
+			changes.RestartRequired = append(changes.RestartRequired, "providers."+jsonName(currentValue.Type().Field(i)))
+		}
+	}
+
+	// The other options, including the metrics whose registries are only created on startup, require a restart.
+	currentConfValue, updatedConfValue := reflect.ValueOf(*current), reflect.ValueOf(*updated)
+	for i := 0; i < currentConfValue.NumField(); i++ {
+		name := jsonName(currentConfValue.Type().Field(i))
+		if name == "entryPoints" || name == "providers" {
+			continue
+		}
+
+		if !jsonEqual(currentConfValue.Field(i).Interface(), updatedConfValue.Field(i).Interface()) {
+			changes.RestartRequired = append(changes.RestartRequired, name)
+		}
+	}
+
+	sort.Strings(changes.RestartRequired)
+
+	return changes
+}
+
+// jsonEqual compares the options of two elements of the configuration.
+// The elements are compared through their JSON representation,
+// which ignores the internal state the providers gather once they are started.
+func jsonEqual(a, b interface{}) bool {
+	rawA, errA := json.Marshal(a)
+	rawB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+
+	return string(rawA) == string(rawB)
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
@@ -0,0 +1,141 @@
+package static
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/provider/docker"
+	"github.com/traefik/traefik/v2/pkg/provider/file"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestDiff(t *testing.T) {
+	current := func() *Configuration {
+		return &Configuration{
+			Global: &Global{CheckNewVersion: true},
+			EntryPoints: EntryPoints{
+				"web": {Address: ":80"},
+			},
+			Providers: &Providers{
+				ProvidersThrottleDuration: ptypes.Duration(2),
+				File:                      &file.Provider{Filename: "dynamic.toml"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		desc                    string
+		update                  func(conf *Configuration)
+		expectedEntryPoints     []string
+		expectedProviders       []string
+		expectedRestartRequired []string
+	}{
+		{
+			desc:   "no change",
+			update: func(conf *Configuration) {},
+		},
+		{
+			desc: "added entry point",
+			update: func(conf *Configuration) {
+				conf.EntryPoints["websecure"] = &EntryPoint{Address: ":443"}
+			},
+			expectedEntryPoints: []string{"websecure"},
+		},
+		{
+			desc: "replaced entry point",
+			update: func(conf *Configuration) {
+				conf.EntryPoints = EntryPoints{"other": {Address: ":8000"}}
+			},
+			expectedEntryPoints:     []string{"other"},
+			expectedRestartRequired: []string{"entryPoints.web"},
+		},
+		{
+			desc: "modified entry point",
+			update: func(conf *Configuration) {
+				conf.EntryPoints["web"].Address = ":8080"
+			},
+			expectedRestartRequired: []string{"entryPoints.web"},
+		},
+		{
+			desc: "added provider",
+			update: func(conf *Configuration) {
+				conf.Providers.Docker = &docker.Provider{Endpoint: "unix:///var/run/docker.sock"}
+			},
+			expectedProviders: []string{"docker"},
+		},
+		{
+			desc: "modified and removed provider options",
+			update: func(conf *Configuration) {
+				conf.Providers.File = nil
+				conf.Providers.ProvidersThrottleDuration = ptypes.Duration(3)
+			},
+			expectedRestartRequired: []string{"providers.file", "providers.providersThrottleDuration"},
+		},
+		{
+			desc: "other options",
+			update: func(conf *Configuration) {
+				conf.Global.CheckNewVersion = false
+				conf.Metrics = &types.Metrics{Prometheus: &types.Prometheus{}}
+			},
+			expectedRestartRequired: []string{"global", "metrics"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			updated := current()
+			test.update(updated)
+
+			changes := Diff(current(), updated)
+
+			var entryPoints []string
+			for name := range changes.EntryPoints {
+				entryPoints = append(entryPoints, name)
+			}
+
+			assert.Equal(t, test.expectedEntryPoints, entryPoints)
+			assert.Equal(t, test.expectedProviders, changes.AddedProviders())
+			assert.Equal(t, test.expectedRestartRequired, changes.RestartRequired)
+			assert.Equal(t, len(test.expectedEntryPoints) > 0 || len(test.expectedProviders) > 0, changes.HasAdditions())
+		})
+	}
+}
+
+func TestChanges_Apply(t *testing.T) {
+	fileProvider := &file.Provider{Filename: "dynamic.toml"}
+	dockerProvider := &docker.Provider{Endpoint: "unix:///var/run/docker.sock"}
+
+	conf := Configuration{
+		Global: &Global{CheckNewVersion: true},
+		EntryPoints: EntryPoints{
+			"web": {Address: ":80"},
+		},
+		Providers: &Providers{File: fileProvider},
+	}
+
+	changes := Changes{
+		EntryPoints: EntryPoints{"websecure": {Address: ":443"}},
+		Providers:   Providers{Docker: dockerProvider},
+	}
+
+	applied := changes.Apply(conf)
+
+	expected := Configuration{
+		Global: &Global{CheckNewVersion: true},
+		EntryPoints: EntryPoints{
+			"web":       {Address: ":80"},
+			"websecure": {Address: ":443"},
+		},
+		Providers: &Providers{File: fileProvider, Docker: dockerProvider},
+	}
+
+	assert.Equal(t, expected, applied)
+
+	// The given configuration is left untouched.
+	assert.Len(t, conf.EntryPoints, 1)
+	assert.Nil(t, conf.Providers.Docker)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/types"
@@ -11,13 +12,18 @@ import (
 
 // EntryPoint holds the entry point configuration.
 type EntryPoint struct {
-	Address          string                `description:"Entry point address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
-	Transport        *EntryPointsTransport `description:"Configures communication between clients and Traefik." json:"transport,omitempty" toml:"transport,omitempty" yaml:"transport,omitempty" export:"true"`
-	ProxyProtocol    *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	ForwardedHeaders *ForwardedHeaders     `description:"Trust client forwarding headers." json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty" export:"true"`
-	HTTP             HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
-	EnableHTTP3      bool                  `description:"Enable HTTP3." json:"enableHTTP3,omitempty" toml:"enableHTTP3,omitempty" yaml:"enableHTTP3,omitempty" export:"true"`
-	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	Address             string                `description:"Entry point address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Transport           *EntryPointsTransport `description:"Configures communication between clients and Traefik." json:"transport,omitempty" toml:"transport,omitempty" yaml:"transport,omitempty" export:"true"`
+	ProxyProtocol       *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ConnectionRateLimit *ConnectionRateLimit  `description:"Limits the rate of new connections, before the TLS handshake." json:"connectionRateLimit,omitempty" toml:"connectionRateLimit,omitempty" yaml:"connectionRateLimit,omitempty" export:"true"`
+	ConnectionLimit     *ConnectionLimit      `description:"Limits the number of concurrent connections, before the TLS handshake." json:"connectionLimit,omitempty" toml:"connectionLimit,omitempty" yaml:"connectionLimit,omitempty" export:"true"`
+	ForwardedHeaders    *ForwardedHeaders     `description:"Trust client forwarding headers." json:"forwardedHeaders,omitempty" toml:"forwardedHeaders,omitempty" yaml:"forwardedHeaders,omitempty" export:"true"`
+	HTTP                HTTPConfig            `description:"HTTP configuration." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" export:"true"`
+	EnableHTTP3         bool                  `description:"Enable HTTP3." json:"enableHTTP3,omitempty" toml:"enableHTTP3,omitempty" yaml:"enableHTTP3,omitempty" export:"true"`
+	UDP                 *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	SNIInspection       *SNIInspection        `description:"Configures the reading of the TLS ClientHello used to route the TCP connections by SNI." json:"sniInspection,omitempty" toml:"sniInspection,omitempty" yaml:"sniInspection,omitempty" export:"true"`
+	Observability       *ObservabilityConfig  `description:"Observability configuration of the entry point." json:"observability,omitempty" toml:"observability,omitempty" yaml:"observability,omitempty" export:"true"`
+	ReusePort           bool                  `description:"Enables EntryPoints from the same or different processes listening on the same TCP/UDP port." json:"reusePort,omitempty" toml:"reusePort,omitempty" yaml:"reusePort,omitempty" export:"true"`
 }
 
 // GetAddress strips any potential protocol part of the address field of the
@@ -50,6 +56,8 @@ func (ep *EntryPoint) SetDefaults() {
 	ep.ForwardedHeaders = &ForwardedHeaders{}
 	ep.UDP = &UDPConfig{}
 	ep.UDP.SetDefaults()
+	ep.Observability = &ObservabilityConfig{}
+	ep.Observability.SetDefaults()
 }
 
 // HTTPConfig is the HTTP configuration of an entry point.
@@ -57,6 +65,8 @@ type HTTPConfig struct {
 	Redirections *Redirections `description:"Set of redirection" json:"redirections,omitempty" toml:"redirections,omitempty" yaml:"redirections,omitempty" export:"true"`
 	Middlewares  []string      `description:"Default middlewares for the routers linked to the entry point." json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"  export:"true"`
 	TLS          *TLSConfig    `description:"Default TLS configuration for the routers linked to the entry point." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty"  export:"true"`
+
+	PriorityStrategy string `description:"Strategy computing the priority of the routers without an explicit one: length (of the rule) or specificity (of the matchers)." json:"priorityStrategy,omitempty" toml:"priorityStrategy,omitempty" yaml:"priorityStrategy,omitempty" export:"true"`
 }
 
 // Redirections is a set of redirection for an entry point.
@@ -70,6 +80,8 @@ type RedirectEntryPoint struct {
 	Scheme    string `description:"Scheme used for the redirection." json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty" export:"true"`
 	Permanent bool   `description:"Applies a permanent redirection." json:"permanent,omitempty" toml:"permanent,omitempty" yaml:"permanent,omitempty" export:"true"`
 	Priority  int    `description:"Priority of the generated router." json:"priority,omitempty" toml:"priority,omitempty" yaml:"priority,omitempty" export:"true"`
+
+	ExcludedPathPrefixes []string `description:"Path prefixes of the requests which are not redirected." json:"excludedPathPrefixes,omitempty" toml:"excludedPathPrefixes,omitempty" yaml:"excludedPathPrefixes,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -96,6 +108,69 @@ type ForwardedHeaders struct {
 type ProxyProtocol struct {
 	Insecure   bool     `description:"Trust all." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
 	TrustedIPs []string `description:"Trust only selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	Required   bool     `description:"Reject the connections from the trusted IPs without a PROXY protocol header." json:"required,omitempty" toml:"required,omitempty" yaml:"required,omitempty" export:"true"`
+}
+
+// Connection rate limit policies.
+const (
+	ConnectionRateLimitPolicyReject = "reject"
+	ConnectionRateLimitPolicyQueue  = "queue"
+)
+
+// ConnectionRateLimit limits the rate at which new connections are accepted on an entry point.
+type ConnectionRateLimit struct {
+	Average       int64           `description:"Maximum average number of new connections per second, for all the sources. 0 means no global limit." json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	Burst         int64           `description:"Maximum number of new connections allowed to go through at once, for all the sources." json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+	SourceAverage int64           `description:"Maximum average number of new connections per second, for each source IP. 0 means no per-source limit." json:"sourceAverage,omitempty" toml:"sourceAverage,omitempty" yaml:"sourceAverage,omitempty" export:"true"`
+	SourceBurst   int64           `description:"Maximum number of new connections allowed to go through at once, for each source IP." json:"sourceBurst,omitempty" toml:"sourceBurst,omitempty" yaml:"sourceBurst,omitempty" export:"true"`
+	Policy        string          `description:"What to do with connections over the limit: reject or queue." json:"policy,omitempty" toml:"policy,omitempty" yaml:"policy,omitempty" export:"true"`
+	MaxDelay      ptypes.Duration `description:"Maximum duration a connection is queued before being rejected, when the policy is queue." json:"maxDelay,omitempty" toml:"maxDelay,omitempty" yaml:"maxDelay,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *ConnectionRateLimit) SetDefaults() {
+	c.Burst = 1
+	c.SourceBurst = 1
+	c.Policy = ConnectionRateLimitPolicyReject
+	c.MaxDelay = ptypes.Duration(time.Second)
+}
+
+// ConnectionLimit limits the number of concurrent connections on an entry point.
+type ConnectionLimit struct {
+	MaxConnections       int64 `description:"Maximum number of concurrent connections, for all the sources. 0 means no global limit." json:"maxConnections,omitempty" toml:"maxConnections,omitempty" yaml:"maxConnections,omitempty" export:"true"`
+	SourceMaxConnections int64 `description:"Maximum number of concurrent connections, for each source IP. 0 means no per-source limit." json:"sourceMaxConnections,omitempty" toml:"sourceMaxConnections,omitempty" yaml:"sourceMaxConnections,omitempty" export:"true"`
+}
+
+// SNIInspection configures the reading of the TLS ClientHello of the connections, before routing them by SNI.
+type SNIInspection struct {
+	ClientHelloTimeout ptypes.Duration `description:"Maximum duration to wait for the first bytes of the connection, after which it is routed as a connection without SNI. 0 means waiting until the read timeout." json:"clientHelloTimeout,omitempty" toml:"clientHelloTimeout,omitempty" yaml:"clientHelloTimeout,omitempty" export:"true"`
+	MaxBytes           int             `description:"Maximum number of bytes read to find the SNI, a bigger ClientHello being routed as a connection without SNI. 0 means no limit." json:"maxBytes,omitempty" toml:"maxBytes,omitempty" yaml:"maxBytes,omitempty" export:"true"`
+	DefaultService     string          `description:"Qualified name of the TCP service handling the connections without SNI, including the non-TLS ones." json:"defaultService,omitempty" toml:"defaultService,omitempty" yaml:"defaultService,omitempty" export:"true"`
+}
+
+// ObservabilityConfig enables the access logs and the metrics of the requests received by an entry point,
+// when they are enabled globally, and the detection of its slow requests.
+type ObservabilityConfig struct {
+	AccessLogs   bool          `description:"Enables the access logs of the entry point." json:"accessLogs,omitempty" toml:"accessLogs,omitempty" yaml:"accessLogs,omitempty" export:"true"`
+	Metrics      bool          `description:"Enables the metrics of the entry point." json:"metrics,omitempty" toml:"metrics,omitempty" yaml:"metrics,omitempty" export:"true"`
+	SlowRequests *SlowRequests `description:"Logs and counts the requests in flight for too long." json:"slowRequests,omitempty" toml:"slowRequests,omitempty" yaml:"slowRequests,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *ObservabilityConfig) SetDefaults() {
+	o.AccessLogs = true
+	o.Metrics = true
+}
+
+// SlowRequests configures the detection of the requests in flight for too long,
+// e.g. because of a backend trickling its response, which are logged and counted with their current state.
+type SlowRequests struct {
+	Threshold ptypes.Duration `description:"Duration after which an in-flight request is reported as slow." json:"threshold,omitempty" toml:"threshold,omitempty" yaml:"threshold,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *SlowRequests) SetDefaults() {
+	s.Threshold = ptypes.Duration(30 * time.Second)
 }
 
 // EntryPoints holds the HTTP entry point list.
@@ -103,8 +178,9 @@ type EntryPoints map[string]*EntryPoint
 
 // EntryPointsTransport configures communication between clients and Traefik.
 type EntryPointsTransport struct {
-	LifeCycle          *LifeCycle          `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
-	RespondingTimeouts *RespondingTimeouts `description:"Timeouts for incoming requests to the Traefik instance." json:"respondingTimeouts,omitempty" toml:"respondingTimeouts,omitempty" yaml:"respondingTimeouts,omitempty" export:"true"`
+	LifeCycle            *LifeCycle          `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
+	RespondingTimeouts   *RespondingTimeouts `description:"Timeouts for incoming requests to the Traefik instance." json:"respondingTimeouts,omitempty" toml:"respondingTimeouts,omitempty" yaml:"respondingTimeouts,omitempty" export:"true"`
+	MaxKeepAliveRequests int                 `description:"Maximum number of requests served on a keep-alive connection before closing it. If zero, no limit is set." json:"maxKeepAliveRequests,omitempty" toml:"maxKeepAliveRequests,omitempty" yaml:"maxKeepAliveRequests,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -117,7 +193,8 @@ func (t *EntryPointsTransport) SetDefaults() {
 
 // UDPConfig is the UDP configuration of an entry point.
 type UDPConfig struct {
-	Timeout ptypes.Duration `description:"Timeout defines how long to wait on an idle session before releasing the related resources." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Timeout          ptypes.Duration `description:"Timeout defines how long to wait on an idle session before releasing the related resources." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
+	SourceIPAffinity bool            `description:"Identifies the sessions by the client IP only, ignoring the client port." json:"sourceIPAffinity,omitempty" toml:"sourceIPAffinity,omitempty" yaml:"sourceIPAffinity,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
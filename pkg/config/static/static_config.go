@@ -1,8 +1,10 @@
 package static
 
 import (
+	"errors"
 	"fmt"
 	stdlog "log"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,9 +12,11 @@ import (
 	legolog "github.com/go-acme/lego/v4/log"
 	"github.com/sirupsen/logrus"
 	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/cluster"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/ping"
 	acmeprovider "github.com/traefik/traefik/v2/pkg/provider/acme"
+	"github.com/traefik/traefik/v2/pkg/provider/azure"
 	"github.com/traefik/traefik/v2/pkg/provider/consulcatalog"
 	"github.com/traefik/traefik/v2/pkg/provider/docker"
 	"github.com/traefik/traefik/v2/pkg/provider/ecs"
@@ -28,7 +32,9 @@ import (
 	"github.com/traefik/traefik/v2/pkg/provider/marathon"
 	"github.com/traefik/traefik/v2/pkg/provider/rancher"
 	"github.com/traefik/traefik/v2/pkg/provider/rest"
+	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/traefik/traefik/v2/pkg/tracing/datadog"
 	"github.com/traefik/traefik/v2/pkg/tracing/elastic"
 	"github.com/traefik/traefik/v2/pkg/tracing/haystack"
@@ -73,12 +79,20 @@ type Configuration struct {
 	AccessLog *types.AccessLog  `description:"Access log settings." json:"accessLog,omitempty" toml:"accessLog,omitempty" yaml:"accessLog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Tracing   *Tracing          `description:"OpenTracing configuration." json:"tracing,omitempty" toml:"tracing,omitempty" yaml:"tracing,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
+	Accounting *types.Accounting `description:"Request and response sizes accounting per router." json:"accounting,omitempty" toml:"accounting,omitempty" yaml:"accounting,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
 	HostResolver *types.HostResolverConfig `description:"Enable CNAME Flattening." json:"hostResolver,omitempty" toml:"hostResolver,omitempty" yaml:"hostResolver,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	CertificatesResolvers map[string]CertificateResolver `description:"Certificates resolvers configuration." json:"certificatesResolvers,omitempty" toml:"certificatesResolvers,omitempty" yaml:"certificatesResolvers,omitempty" export:"true"`
 
+	OutboundProxy *types.OutboundProxy `description:"Upstream proxies for the outbound traffic of Traefik." json:"outboundProxy,omitempty" toml:"outboundProxy,omitempty" yaml:"outboundProxy,omitempty" export:"true"`
+
+	SourceRangeSources *types.SourceRangeSources `description:"Files and URLs the IPWhiteList middlewares can read their source ranges from." json:"sourceRangeSources,omitempty" toml:"sourceRangeSources,omitempty" yaml:"sourceRangeSources,omitempty" export:"true"`
+
 	Pilot *Pilot `description:"Traefik Pilot configuration." json:"pilot,omitempty" toml:"pilot,omitempty" yaml:"pilot,omitempty" export:"true"`
 
+	Cluster *cluster.Configuration `description:"Cluster mode: the instances sharing a KV store elect a leader for the ACME operations, and publish their runtime state." json:"cluster,omitempty" toml:"cluster,omitempty" yaml:"cluster,omitempty" export:"true"`
+
 	Experimental *Experimental `description:"experimental features." json:"experimental,omitempty" toml:"experimental,omitempty" yaml:"experimental,omitempty" export:"true"`
 }
 
@@ -91,6 +105,8 @@ type CertificateResolver struct {
 type Global struct {
 	CheckNewVersion    bool `description:"Periodically check if a new version has been released." json:"checkNewVersion,omitempty" toml:"checkNewVersion,omitempty" yaml:"checkNewVersion,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	SendAnonymousUsage bool `description:"Periodically send anonymous usage statistics. If the option is not specified, it will be enabled by default." json:"sendAnonymousUsage,omitempty" toml:"sendAnonymousUsage,omitempty" yaml:"sendAnonymousUsage,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	StrictConfig       bool `description:"Turns the configuration warnings into startup failures: deprecated options, unknown keys and references to missing middlewares in the files of the file provider." json:"strictConfig,omitempty" toml:"strictConfig,omitempty" yaml:"strictConfig,omitempty" export:"true"`
+	StaticReload       bool `description:"Reloads the static configuration on a SIGHUP signal and through the /api/reload endpoint, applying the new entry points and providers." json:"staticReload,omitempty" toml:"staticReload,omitempty" yaml:"staticReload,omitempty" export:"true"`
 }
 
 // ServersTransport options to configure communication between Traefik and the servers.
@@ -103,9 +119,12 @@ type ServersTransport struct {
 
 // API holds the API configuration.
 type API struct {
-	Insecure  bool `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	Dashboard bool `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
-	Debug     bool `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	Insecure           bool                 `description:"Activate API directly on the entryPoint named traefik." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	Dashboard          bool                 `description:"Activate dashboard." json:"dashboard,omitempty" toml:"dashboard,omitempty" yaml:"dashboard,omitempty" export:"true"`
+	Debug              bool                 `description:"Enable additional endpoints for debugging and profiling." json:"debug,omitempty" toml:"debug,omitempty" yaml:"debug,omitempty" export:"true"`
+	Scopes             map[string]*APIScope `description:"Additional read-only API and dashboard instances, only showing the objects of selected providers or namespaces." json:"scopes,omitempty" toml:"scopes,omitempty" yaml:"scopes,omitempty" export:"true"`
+	HistorySize        int                  `description:"Number of the last applied dynamic configurations exposed by the history and diff endpoints. Zero disables the endpoints." json:"historySize,omitempty" toml:"historySize,omitempty" yaml:"historySize,omitempty" export:"true"`
+	AccountKeyRotation bool                 `description:"Enable the endpoint rotating the keys of the ACME accounts of the certificate resolvers." json:"accountKeyRotation,omitempty" toml:"accountKeyRotation,omitempty" yaml:"accountKeyRotation,omitempty" export:"true"`
 	// TODO: Re-enable statistics
 	// Statistics      *types.Statistics `description:"Enable more detailed statistics." json:"statistics,omitempty" toml:"statistics,omitempty" yaml:"statistics,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	DashboardAssets *assetfs.AssetFS `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
@@ -114,13 +133,21 @@ type API struct {
 // SetDefaults sets the default values.
 func (a *API) SetDefaults() {
 	a.Dashboard = true
+	a.HistorySize = 10
+}
+
+// APIScope restricts an API instance to the objects of some providers or namespaces.
+type APIScope struct {
+	Providers  []string `description:"Providers whose objects are shown. Empty means all the providers." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
+	Namespaces []string `description:"Kubernetes namespaces whose objects are shown. Empty means all the namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
 }
 
 // RespondingTimeouts contains timeout configurations for incoming requests to the Traefik instance.
 type RespondingTimeouts struct {
-	ReadTimeout  ptypes.Duration `description:"ReadTimeout is the maximum duration for reading the entire request, including the body. If zero, no timeout is set." json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
-	WriteTimeout ptypes.Duration `description:"WriteTimeout is the maximum duration before timing out writes of the response. If zero, no timeout is set." json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
-	IdleTimeout  ptypes.Duration `description:"IdleTimeout is the maximum amount duration an idle (keep-alive) connection will remain idle before closing itself. If zero, no timeout is set." json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	ReadTimeout       ptypes.Duration `description:"ReadTimeout is the maximum duration for reading the entire request, including the body. If zero, no timeout is set." json:"readTimeout,omitempty" toml:"readTimeout,omitempty" yaml:"readTimeout,omitempty" export:"true"`
+	ReadHeaderTimeout ptypes.Duration `description:"ReadHeaderTimeout is the maximum duration for reading the request headers. If zero, the read timeout is used." json:"readHeaderTimeout,omitempty" toml:"readHeaderTimeout,omitempty" yaml:"readHeaderTimeout,omitempty" export:"true"`
+	WriteTimeout      ptypes.Duration `description:"WriteTimeout is the maximum duration before timing out writes of the response. If zero, no timeout is set." json:"writeTimeout,omitempty" toml:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" export:"true"`
+	IdleTimeout       ptypes.Duration `description:"IdleTimeout is the maximum amount duration an idle (keep-alive) connection will remain idle before closing itself. If zero, no timeout is set." json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -133,12 +160,14 @@ type ForwardingTimeouts struct {
 	DialTimeout           ptypes.Duration `description:"The amount of time to wait until a connection to a backend server can be established. If zero, no timeout exists." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
 	ResponseHeaderTimeout ptypes.Duration `description:"The amount of time to wait for a server's response headers after fully writing the request (including its body, if any). If zero, no timeout exists." json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty" export:"true"`
 	IdleConnTimeout       ptypes.Duration `description:"The maximum period for which an idle HTTP keep-alive connection will remain open before closing itself" json:"idleConnTimeout,omitempty" toml:"idleConnTimeout,omitempty" yaml:"idleConnTimeout,omitempty" export:"true"`
+	TLSHandshakeTimeout   ptypes.Duration `description:"The amount of time to wait for the TLS handshake with a backend server. If zero, no timeout exists." json:"tlsHandshakeTimeout,omitempty" toml:"tlsHandshakeTimeout,omitempty" yaml:"tlsHandshakeTimeout,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
 func (f *ForwardingTimeouts) SetDefaults() {
 	f.DialTimeout = ptypes.Duration(30 * time.Second)
 	f.IdleConnTimeout = ptypes.Duration(90 * time.Second)
+	f.TLSHandshakeTimeout = ptypes.Duration(10 * time.Second)
 }
 
 // LifeCycle contains configurations relevant to the lifecycle (such as the shutdown phase) of Traefik.
@@ -162,6 +191,8 @@ type Tracing struct {
 	Instana       *instana.Config  `description:"Settings for Instana." json:"instana,omitempty" toml:"instana,omitempty" yaml:"instana,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	Haystack      *haystack.Config `description:"Settings for Haystack." json:"haystack,omitempty" toml:"haystack,omitempty" yaml:"haystack,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	Elastic       *elastic.Config  `description:"Settings for Elastic." json:"elastic,omitempty" toml:"elastic,omitempty" yaml:"elastic,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
+
+	SpanAttributes *tracing.SpanAttributes `description:"Customization of the span attributes." json:"spanAttributes,omitempty" toml:"spanAttributes,omitempty" yaml:"spanAttributes,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 }
 
 // SetDefaults sets the default values.
@@ -184,6 +215,7 @@ type Providers struct {
 	Rancher           *rancher.Provider       `description:"Enable Rancher backend with default settings." json:"rancher,omitempty" toml:"rancher,omitempty" yaml:"rancher,omitempty" export:"true" label:"allowEmpty" file:"allowEmpty"`
 	ConsulCatalog     *consulcatalog.Provider `description:"Enable ConsulCatalog backend with default settings." json:"consulCatalog,omitempty" toml:"consulCatalog,omitempty" yaml:"consulCatalog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	Ecs               *ecs.Provider           `description:"Enable AWS ECS backend with default settings." json:"ecs,omitempty" toml:"ecs,omitempty" yaml:"ecs,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Azure             *azure.Provider         `description:"Enable Azure Container Apps and Container Instances backend with default settings." json:"azure,omitempty" toml:"azure,omitempty" yaml:"azure,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	Consul    *consul.Provider `description:"Enable Consul backend with default settings." json:"consul,omitempty" toml:"consul,omitempty" yaml:"consul,omitempty" label:"allowEmpty" file:"allowEmpty"  export:"true"`
 	Etcd      *etcd.Provider   `description:"Enable Etcd backend with default settings." json:"etcd,omitempty" toml:"etcd,omitempty" yaml:"etcd,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -192,6 +224,17 @@ type Providers struct {
 	HTTP      *http.Provider   `description:"Enable HTTP backend with default settings." json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	Plugin map[string]PluginConf `description:"Plugins configuration." json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
+
+	Expiry map[string]ProviderExpiry `description:"Expiry of the configuration of the providers which stop sending updates, by provider name." json:"expiry,omitempty" toml:"expiry,omitempty" yaml:"expiry,omitempty" export:"true"`
+
+	RollbackOnError bool `description:"Keeps serving the previous dynamic configuration when routers which were working fail to build with a new one." json:"rollbackOnError,omitempty" toml:"rollbackOnError,omitempty" yaml:"rollbackOnError,omitempty" export:"true"`
+}
+
+// ProviderExpiry holds the expiry configuration of a provider:
+// its configuration is marked as stale, and then removed, when the provider does not send any update for a while.
+type ProviderExpiry struct {
+	StaleAfter  ptypes.Duration `description:"Duration without update from the provider after which its configuration is marked as stale." json:"staleAfter,omitempty" toml:"staleAfter,omitempty" yaml:"staleAfter,omitempty" export:"true"`
+	RemoveAfter ptypes.Duration `description:"Duration without update from the provider after which its configuration is removed." json:"removeAfter,omitempty" toml:"removeAfter,omitempty" yaml:"removeAfter,omitempty" export:"true"`
 }
 
 // SetEffectiveConfiguration adds missing configuration parameters derived from existing ones.
@@ -296,7 +339,65 @@ func (c *Configuration) ValidateConfiguration() error {
 		acmeEmail = resolver.ACME.Email
 	}
 
-	return nil
+	for name, entryPoint := range c.EntryPoints {
+		if err := rules.ValidatePriorityStrategy(entryPoint.HTTP.PriorityStrategy); err != nil {
+			return fmt.Errorf("invalid entry point %q: %w", name, err)
+		}
+
+		if inspection := entryPoint.SNIInspection; inspection != nil {
+			if inspection.ClientHelloTimeout < 0 || inspection.MaxBytes < 0 {
+				return fmt.Errorf("invalid entry point %q: the SNI inspection limits must be positive", name)
+			}
+
+			if inspection.DefaultService != "" && !strings.Contains(inspection.DefaultService, "@") {
+				return fmt.Errorf("invalid entry point %q: the SNI inspection default service %q must be a qualified name", name, inspection.DefaultService)
+			}
+		}
+	}
+
+	if c.Providers != nil {
+		for name, expiry := range c.Providers.Expiry {
+			if expiry.StaleAfter < 0 || expiry.RemoveAfter < 0 {
+				return fmt.Errorf("invalid expiry for the provider %q: durations must be positive", name)
+			}
+
+			if expiry.StaleAfter > 0 && expiry.RemoveAfter > 0 && expiry.RemoveAfter < expiry.StaleAfter {
+				return fmt.Errorf("invalid expiry for the provider %q: removeAfter must be greater than staleAfter", name)
+			}
+		}
+	}
+
+	if c.Cluster != nil {
+		if len(c.Cluster.Endpoints) == 0 {
+			return errors.New("the cluster mode requires the endpoints of the KV store")
+		}
+
+		if c.Cluster.TTL <= 0 {
+			return errors.New("the TTL of the cluster mode must be positive")
+		}
+	}
+
+	return c.OutboundProxy.Validate()
+}
+
+// Warnings returns the warnings of the configuration, with their location, e.g. the deprecated options.
+// It is meant to be called before SetEffectiveConfiguration, which replaces the deprecated values.
+func (c *Configuration) Warnings() []string {
+	var warnings []string
+
+	for name, resolver := range c.CertificatesResolvers {
+		if resolver.ACME == nil {
+			continue
+		}
+
+		if caServer, ok := acmeV02CAServer(resolver.ACME.CAServer); ok {
+			warnings = append(warnings, fmt.Sprintf("certificatesResolvers.%s.acme.caServer: the CA server %q refers to a v01 endpoint of the ACME API, please change to %q", name, resolver.ACME.CAServer, caServer))
+		}
+	}
+
+	sort.Strings(warnings)
+
+	return warnings
 }
 
 func getSafeACMECAServer(caServerSrc string) string {
@@ -304,19 +405,25 @@ func getSafeACMECAServer(caServerSrc string) string {
 		return DefaultAcmeCAServer
 	}
 
-	if strings.HasPrefix(caServerSrc, "https://acme-v01.api.letsencrypt.org") {
-		caServer := strings.Replace(caServerSrc, "v01", "v02", 1)
+	if caServer, ok := acmeV02CAServer(caServerSrc); ok {
 		log.WithoutContext().
 			Warnf("The CA server %[1]q refers to a v01 endpoint of the ACME API, please change to %[2]q. Fallback to %[2]q.", caServerSrc, caServer)
 		return caServer
 	}
 
+	return caServerSrc
+}
+
+// acmeV02CAServer returns the v02 endpoint of the ACME API replacing the given CA server,
+// if it refers to a v01 endpoint.
+func acmeV02CAServer(caServerSrc string) (string, bool) {
+	if strings.HasPrefix(caServerSrc, "https://acme-v01.api.letsencrypt.org") {
+		return strings.Replace(caServerSrc, "v01", "v02", 1), true
+	}
+
 	if strings.HasPrefix(caServerSrc, "https://acme-staging.api.letsencrypt.org") {
-		caServer := strings.Replace(caServerSrc, "https://acme-staging.api.letsencrypt.org", "https://acme-staging-v02.api.letsencrypt.org", 1)
-		log.WithoutContext().
-			Warnf("The CA server %[1]q refers to a v01 endpoint of the ACME API, please change to %[2]q. Fallback to %[2]q.", caServerSrc, caServer)
-		return caServer
+		return strings.Replace(caServerSrc, "https://acme-staging.api.letsencrypt.org", "https://acme-staging-v02.api.letsencrypt.org", 1), true
 	}
 
-	return caServerSrc
+	return "", false
 }
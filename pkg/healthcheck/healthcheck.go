@@ -25,6 +25,12 @@ const (
 	serverDown = "DOWN"
 )
 
+// Results of a health check, reported through the metrics.
+const (
+	resultPass = "pass"
+	resultFail = "fail"
+)
+
 var (
 	singleton *HealthCheck
 	once      sync.Once
@@ -51,7 +57,10 @@ type BalancerStatusHandler interface {
 }
 
 type metricsHealthcheck struct {
-	serverUpGauge gokitmetrics.Gauge
+	serverUpGauge            gokitmetrics.Gauge
+	healthChecksCounter      gokitmetrics.Counter
+	consecutiveFailuresGauge gokitmetrics.Gauge
+	healthCheckDurationGauge gokitmetrics.Gauge
 }
 
 // Options are the public health check options.
@@ -66,6 +75,8 @@ type Options struct {
 	Interval        time.Duration
 	Timeout         time.Duration
 	LB              Balancer
+	// ServiceInfo records the outcome of the health checks of the servers, reported by the API. It can be nil.
+	ServiceInfo *runtime.ServiceInfo
 }
 
 func (opt Options) String() string {
@@ -82,6 +93,8 @@ type BackendConfig struct {
 	Options
 	name         string
 	disabledURLs []backendURL
+	// healthChecks is the outcome of the health checks of the servers, keyed by server URL.
+	healthChecks map[string]runtime.ServerHealthCheck
 }
 
 func (b *BackendConfig) newRequest(serverURL *url.URL) (*http.Request, error) {
@@ -113,11 +126,35 @@ func (b *BackendConfig) addHeadersAndHost(req *http.Request) *http.Request {
 	return req
 }
 
+// ServerStatusListener is notified of the status transitions of the servers of a service,
+// detected by the health check.
+type ServerStatusListener func(serviceName, serverURL string, up bool)
+
 // HealthCheck struct.
 type HealthCheck struct {
 	Backends map[string]*BackendConfig
 	metrics  metricsHealthcheck
 	cancel   context.CancelFunc
+
+	listenersMu sync.RWMutex
+	listeners   []ServerStatusListener
+}
+
+// AddServerStatusListener adds a listener notified when a server goes down, or comes back up.
+func (hc *HealthCheck) AddServerStatusListener(listener ServerStatusListener) {
+	hc.listenersMu.Lock()
+	defer hc.listenersMu.Unlock()
+
+	hc.listeners = append(hc.listeners, listener)
+}
+
+func (hc *HealthCheck) notifyServerStatus(serviceName string, serverURL *url.URL, up bool) {
+	hc.listenersMu.RLock()
+	defer hc.listenersMu.RUnlock()
+
+	for _, listener := range hc.listeners {
+		listener(serviceName, serverURL.String(), up)
+	}
 }
 
 // SetBackendsConfiguration set backends configuration.
@@ -166,13 +203,15 @@ func (hc *HealthCheck) checkServersLB(ctx context.Context, backend *BackendConfi
 	for _, disabledURL := range backend.disabledURLs {
 		serverUpMetricValue := float64(0)
 
-		if err := checkHealth(disabledURL.url, backend); err == nil {
+		if err := hc.checkServer(backend, disabledURL.url); err == nil {
 			logger.Warnf("Health check up: returning to server list. Backend: %q URL: %q Weight: %d",
 				backend.name, disabledURL.url.String(), disabledURL.weight)
 			if err = backend.LB.UpsertServer(disabledURL.url, roundrobin.Weight(disabledURL.weight)); err != nil {
 				logger.Error(err)
 			}
 			serverUpMetricValue = 1
+
+			hc.notifyServerStatus(backend.name, disabledURL.url, true)
 		} else {
 			logger.Warnf("Health check still failing. Backend: %q URL: %q Reason: %s", backend.name, disabledURL.url.String(), err)
 			newDisabledURLs = append(newDisabledURLs, disabledURL)
@@ -187,7 +226,7 @@ func (hc *HealthCheck) checkServersLB(ctx context.Context, backend *BackendConfi
 	for _, enabledURL := range enabledURLs {
 		serverUpMetricValue := float64(1)
 
-		if err := checkHealth(enabledURL, backend); err != nil {
+		if err := hc.checkServer(backend, enabledURL); err != nil {
 			weight := 1
 			rr, ok := backend.LB.(*roundrobin.RoundRobin)
 			if ok {
@@ -206,6 +245,8 @@ func (hc *HealthCheck) checkServersLB(ctx context.Context, backend *BackendConfi
 
 			backend.disabledURLs = append(backend.disabledURLs, backendURL{enabledURL, weight})
 			serverUpMetricValue = 0
+
+			hc.notifyServerStatus(backend.name, enabledURL, false)
 		}
 
 		labelValues := []string{"service", backend.name, "url", enabledURL.String()}
@@ -213,6 +254,50 @@ func (hc *HealthCheck) checkServersLB(ctx context.Context, backend *BackendConfi
 	}
 }
 
+// checkServer checks the health of the given server, and records the outcome.
+func (hc *HealthCheck) checkServer(backend *BackendConfig, serverURL *url.URL) error {
+	start := time.Now()
+	err := checkHealth(serverURL, backend)
+	hc.recordHealthCheck(backend, serverURL.String(), time.Since(start), err)
+
+	return err
+}
+
+func (hc *HealthCheck) recordHealthCheck(backend *BackendConfig, server string, latency time.Duration, err error) {
+	if backend.healthChecks == nil {
+		backend.healthChecks = make(map[string]runtime.ServerHealthCheck)
+	}
+
+	healthCheck := backend.healthChecks[server]
+	healthCheck.LastLatency = latency.Seconds()
+	healthCheck.LastCheck = time.Now()
+
+	result := resultPass
+	if err != nil {
+		result = resultFail
+		healthCheck.Status = serverDown
+		healthCheck.Failed++
+		healthCheck.ConsecutiveFailures++
+		healthCheck.LastError = err.Error()
+	} else {
+		healthCheck.Status = serverUp
+		healthCheck.Passed++
+		healthCheck.ConsecutiveFailures = 0
+		healthCheck.LastError = ""
+	}
+
+	backend.healthChecks[server] = healthCheck
+
+	if backend.ServiceInfo != nil {
+		backend.ServiceInfo.UpdateServerHealthCheck(server, healthCheck)
+	}
+
+	labelValues := []string{"service", backend.name, "url", server}
+	hc.metrics.healthChecksCounter.With(append(labelValues, "result", result)...).Add(1)
+	hc.metrics.consecutiveFailuresGauge.With(labelValues...).Set(float64(healthCheck.ConsecutiveFailures))
+	hc.metrics.healthCheckDurationGauge.With(labelValues...).Set(healthCheck.LastLatency)
+}
+
 // GetHealthCheck returns the health check which is guaranteed to be a singleton.
 func GetHealthCheck(registry metrics.Registry) *HealthCheck {
 	once.Do(func() {
@@ -225,7 +310,10 @@ func newHealthCheck(registry metrics.Registry) *HealthCheck {
 	return &HealthCheck{
 		Backends: make(map[string]*BackendConfig),
 		metrics: metricsHealthcheck{
-			serverUpGauge: registry.ServiceServerUpGauge(),
+			serverUpGauge:            registry.ServiceServerUpGauge(),
+			healthChecksCounter:      registry.ServiceServerHealthChecksCounter(),
+			consecutiveFailuresGauge: registry.ServiceServerHealthCheckFailuresGauge(),
+			healthCheckDurationGauge: registry.ServiceServerHealthCheckDurationGauge(),
 		},
 	}
 }
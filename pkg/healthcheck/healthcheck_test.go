@@ -34,6 +34,7 @@ func TestSetBackendsConfiguration(t *testing.T) {
 		expectedNumRemovedServers  int
 		expectedNumUpsertedServers int
 		expectedGaugeValue         float64
+		expectedStatusChanges      []bool
 	}{
 		{
 			desc:                       "healthy server staying healthy",
@@ -66,6 +67,7 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			expectedNumRemovedServers:  1,
 			expectedNumUpsertedServers: 0,
 			expectedGaugeValue:         0,
+			expectedStatusChanges:      []bool{false},
 		},
 		{
 			desc:                       "sick server becoming healthy",
@@ -74,6 +76,7 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			expectedNumRemovedServers:  0,
 			expectedNumUpsertedServers: 1,
 			expectedGaugeValue:         1,
+			expectedStatusChanges:      []bool{true},
 		},
 		{
 			desc:                       "sick server staying sick",
@@ -90,6 +93,7 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			expectedNumRemovedServers:  1,
 			expectedNumUpsertedServers: 1,
 			expectedGaugeValue:         1,
+			expectedStatusChanges:      []bool{false, true},
 		},
 	}
 
@@ -106,11 +110,13 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			defer ts.Close()
 
 			lb := &testLoadBalancer{RWMutex: &sync.RWMutex{}}
+			serviceInfo := &runtime.ServiceInfo{}
 			backend := NewBackendConfig(Options{
-				Path:     "/path",
-				Interval: healthCheckInterval,
-				Timeout:  healthCheckTimeout,
-				LB:       lb,
+				Path:        "/path",
+				Interval:    healthCheckInterval,
+				Timeout:     healthCheckTimeout,
+				LB:          lb,
+				ServiceInfo: serviceInfo,
 			}, "backendName")
 
 			serverURL := testhelpers.MustParseURL(ts.URL)
@@ -121,11 +127,29 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			}
 
 			collectingMetrics := &testhelpers.CollectingGauge{}
+			collectingHealthChecks := &testhelpers.CollectingCounter{}
+			collectingFailures := &testhelpers.CollectingGauge{}
 			check := HealthCheck{
 				Backends: make(map[string]*BackendConfig),
-				metrics:  metricsHealthcheck{serverUpGauge: collectingMetrics},
+				metrics: metricsHealthcheck{
+					serverUpGauge:            collectingMetrics,
+					healthChecksCounter:      collectingHealthChecks,
+					consecutiveFailuresGauge: collectingFailures,
+					healthCheckDurationGauge: &testhelpers.CollectingGauge{},
+				},
 			}
 
+			var statusChangesMu sync.Mutex
+			var statusChanges []bool
+			check.AddServerStatusListener(func(serviceName, url string, up bool) {
+				statusChangesMu.Lock()
+				defer statusChangesMu.Unlock()
+
+				assert.Equal(t, "backendName", serviceName)
+				assert.Equal(t, ts.URL, url)
+				statusChanges = append(statusChanges, up)
+			})
+
 			wg := sync.WaitGroup{}
 			wg.Add(1)
 
@@ -150,6 +174,23 @@ func TestSetBackendsConfiguration(t *testing.T) {
 			assert.Equal(t, test.expectedNumRemovedServers, lb.numRemovedServers, "removed servers")
 			assert.Equal(t, test.expectedNumUpsertedServers, lb.numUpsertedServers, "upserted servers")
 			assert.Equal(t, test.expectedGaugeValue, collectingMetrics.GaugeValue, "ServerUp Gauge")
+			assert.Equal(t, float64(len(test.healthSequence)), collectingHealthChecks.CounterValue, "HealthChecks Counter")
+
+			healthCheck := serviceInfo.GetServerHealthChecks()[ts.URL]
+			assert.Equal(t, uint64(len(test.healthSequence)), healthCheck.Passed+healthCheck.Failed)
+			assert.Equal(t, float64(healthCheck.ConsecutiveFailures), collectingFailures.GaugeValue, "ConsecutiveFailures Gauge")
+			if test.expectedGaugeValue == 1 {
+				assert.Equal(t, serverUp, healthCheck.Status)
+				assert.Zero(t, healthCheck.ConsecutiveFailures)
+			} else {
+				assert.Equal(t, serverDown, healthCheck.Status)
+				assert.NotEmpty(t, healthCheck.LastError)
+			}
+
+			statusChangesMu.Lock()
+			defer statusChangesMu.Unlock()
+
+			assert.Equal(t, test.expectedStatusChanges, statusChanges, "server status changes")
 		})
 	}
 }
@@ -504,7 +545,12 @@ func TestNotFollowingRedirects(t *testing.T) {
 	collectingMetrics := &testhelpers.CollectingGauge{}
 	check := HealthCheck{
 		Backends: make(map[string]*BackendConfig),
-		metrics:  metricsHealthcheck{serverUpGauge: collectingMetrics},
+		metrics: metricsHealthcheck{
+			serverUpGauge:            collectingMetrics,
+			healthChecksCounter:      &testhelpers.CollectingCounter{},
+			consecutiveFailuresGauge: &testhelpers.CollectingGauge{},
+			healthCheckDurationGauge: &testhelpers.CollectingGauge{},
+		},
 	}
 
 	wg := sync.WaitGroup{}
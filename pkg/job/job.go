@@ -1,6 +1,7 @@
 package job
 
 import (
+	"math"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -36,3 +37,25 @@ func (b *BackOff) NextBackOff() time.Duration {
 	}
 	return b.ExponentialBackOff.NextBackOff()
 }
+
+// NewAttemptsBackOff creates an exponential backoff spacing a limited number of attempts,
+// starting at the initial interval, and whose last interval does not exceed twice the initial interval.
+// The attempts are not spaced if there are less than two of them, or if the initial interval is not positive.
+func NewAttemptsBackOff(attempts int, initialInterval time.Duration) backoff.BackOff {
+	if attempts < 2 || initialInterval <= 0 {
+		return &backoff.ZeroBackOff{}
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = initialInterval
+
+	// calculate the multiplier for the given number of attempts
+	// so that applying the multiplier for the given number of attempts will not exceed 2 times the initial interval
+	// it allows to control the progression along the attempts
+	b.Multiplier = math.Pow(2, 1/float64(attempts-1))
+	b.MaxElapsedTime = 0
+
+	// according to docs, b.Reset() must be called before using
+	b.Reset()
+	return b
+}
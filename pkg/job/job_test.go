@@ -56,3 +56,25 @@ func TestJobBackOff(t *testing.T) {
 		}
 	}
 }
+
+func TestNewAttemptsBackOff(t *testing.T) {
+	b := NewAttemptsBackOff(1, time.Second)
+	if next := b.NextBackOff(); next != 0 {
+		t.Errorf("expected no wait for a single attempt, got %v", next)
+	}
+
+	b = NewAttemptsBackOff(3, 0)
+	if next := b.NextBackOff(); next != 0 {
+		t.Errorf("expected no wait without an initial interval, got %v", next)
+	}
+
+	initialInterval := 100 * time.Millisecond
+	b = NewAttemptsBackOff(3, initialInterval)
+	for i := 0; i < 2; i++ {
+		next := b.NextBackOff()
+		// The randomization factor of the exponential backoff spreads the intervals by 50%.
+		if next <= 0 || next > 3*initialInterval {
+			t.Errorf("unexpected wait %v for attempt %d", next, i+2)
+		}
+	}
+}
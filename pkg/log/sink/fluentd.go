@@ -0,0 +1,156 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// NewFluentd creates a Writer sending the log entries to a Fluentd server, using the Forward protocol.
+// The JSON log entries are sent as records, the others as records with a message field.
+func NewFluentd(config *types.Fluentd) (*Writer, error) {
+	network, address, err := parseAddress(config.Address, "tcp", "unix")
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := fluentdEncoder{tag: config.Tag}
+
+	return newWriter(network, address, config.BufferSize, encoder.encode), nil
+}
+
+type fluentdEncoder struct {
+	tag string
+}
+
+// encode encodes the entry as a Forward protocol message: [tag, time, record].
+func (f fluentdEncoder) encode(e entry) ([]byte, error) {
+	line := bytes.TrimRight(e.line, "\n")
+
+	var record map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&record); err != nil || record == nil {
+		record = map[string]interface{}{"message": string(line)}
+	}
+
+	if e.level != nil {
+		record["level"] = e.level.String()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(0x93)
+	writeMsgpackString(&buf, f.tag)
+	writeMsgpackInt(&buf, e.time.Unix())
+
+	if err := writeMsgpack(&buf, record); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeMsgpack writes the MessagePack encoding of a value decoded from JSON.
+func writeMsgpack(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case string:
+		writeMsgpackString(buf, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			writeMsgpackInt(buf, i)
+			return nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(0xcb)
+		_ = binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+	case []interface{}:
+		writeMsgpackHeader(buf, len(v), 0x90, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := writeMsgpack(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		writeMsgpackHeader(buf, len(v), 0x80, 0xde, 0xdf)
+		for _, key := range keys {
+			writeMsgpackString(buf, key)
+			if err := writeMsgpack(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type %T", value)
+	}
+
+	return nil
+}
+
+func writeMsgpackString(buf *bytes.Buffer, s string) {
+	switch n := len(s); {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xda)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdb)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+
+	buf.WriteString(s)
+}
+
+func writeMsgpackInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i < 128:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		buf.WriteByte(0xd2)
+		_ = binary.Write(buf, binary.BigEndian, int32(i))
+	default:
+		buf.WriteByte(0xd3)
+		_ = binary.Write(buf, binary.BigEndian, i)
+	}
+}
+
+// writeMsgpackHeader writes the header of an array or a map, using the fix, 16 bits, or 32 bits format.
+func writeMsgpackHeader(buf *bytes.Buffer, n int, fix, b16, b32 byte) {
+	switch {
+	case n < 16:
+		buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(b16)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(b32)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
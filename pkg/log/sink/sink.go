@@ -0,0 +1,225 @@
+package sink
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	dialTimeout  = 5 * time.Second
+	writeTimeout = 5 * time.Second
+)
+
+// entry is a log entry waiting to be sent.
+type entry struct {
+	time time.Time
+	// level is the level of the entry, if known.
+	level *logrus.Level
+	line  []byte
+}
+
+// Writer sends the log entries to a remote sink, asynchronously.
+// Each call to Write is a log entry.
+// The entries are dropped when the sink cannot keep up.
+type Writer struct {
+	network string
+	address string
+	encode  func(e entry) ([]byte, error)
+
+	mu      sync.RWMutex
+	closed  bool
+	entries chan entry
+	done    chan struct{}
+
+	dropped int64
+	conn    net.Conn
+	failing bool
+}
+
+func newWriter(network, address string, bufferSize int, encode func(e entry) ([]byte, error)) *Writer {
+	w := &Writer{
+		network: network,
+		address: address,
+		encode:  encode,
+		entries: make(chan entry, bufferSize),
+		done:    make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+// Write sends a log entry.
+func (w *Writer) Write(p []byte) (int, error) {
+	line := make([]byte, len(p))
+	copy(line, p)
+
+	w.enqueue(entry{time: time.Now(), line: line})
+
+	return len(p), nil
+}
+
+// Close sends the pending log entries, and closes the connection to the sink.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.entries)
+	w.mu.Unlock()
+
+	<-w.done
+
+	return nil
+}
+
+func (w *Writer) enqueue(e entry) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return
+	}
+
+	select {
+	case w.entries <- e:
+	default:
+		atomic.AddInt64(&w.dropped, 1)
+	}
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	for e := range w.entries {
+		msg, err := w.encode(e)
+		if err != nil {
+			// The errors are not logged, as the logs may be sent to this sink.
+			fmt.Fprintf(os.Stderr, "Unable to encode log entry for %s: %v\n", w.address, err)
+			continue
+		}
+
+		err = w.send(msg)
+		switch {
+		case err != nil && !w.failing:
+			fmt.Fprintf(os.Stderr, "Unable to send log entries to %s: %v\n", w.address, err)
+			w.failing = true
+		case err == nil && w.failing:
+			fmt.Fprintf(os.Stderr, "Sending log entries to %s again\n", w.address)
+			w.failing = false
+		}
+
+		if dropped := atomic.SwapInt64(&w.dropped, 0); dropped > 0 {
+			fmt.Fprintf(os.Stderr, "%d log entries dropped, as %s cannot keep up\n", dropped, w.address)
+		}
+	}
+
+	if w.conn != nil {
+		_ = w.conn.Close()
+	}
+}
+
+// send sends a message, reconnecting once if the connection has been lost.
+func (w *Writer) send(msg []byte) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			w.conn, err = w.dial()
+			if err != nil {
+				return err
+			}
+		}
+
+		_ = w.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+		if _, err = w.conn.Write(msg); err == nil {
+			return nil
+		}
+
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	return err
+}
+
+func (w *Writer) dial() (net.Conn, error) {
+	if w.network != "unix" {
+		return net.DialTimeout(w.network, w.address, dialTimeout)
+	}
+
+	// Unix sockets may be datagram (e.g. /dev/log) or stream ones.
+	conn, err := net.DialTimeout("unixgram", w.address, dialTimeout)
+	if err == nil {
+		return conn, nil
+	}
+
+	return net.DialTimeout("unix", w.address, dialTimeout)
+}
+
+// Hook is a logrus hook sending the log entries to a Writer, with their level.
+type Hook struct {
+	writer    *Writer
+	formatter logrus.Formatter
+}
+
+// NewHook creates a Hook, formatting the entries with the given formatter.
+func NewHook(writer *Writer, formatter logrus.Formatter) *Hook {
+	return &Hook{writer: writer, formatter: formatter}
+}
+
+// Levels returns all the levels, as the level filtering is done by the logger.
+func (h *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire sends the log entry.
+func (h *Hook) Fire(logEntry *logrus.Entry) error {
+	line, err := h.formatter.Format(logEntry)
+	if err != nil {
+		return err
+	}
+
+	level := logEntry.Level
+	h.writer.enqueue(entry{time: logEntry.Time, level: &level, line: line})
+
+	return nil
+}
+
+// parseAddress returns the network and the address of a sink address, such as tcp://host:port or unix:///path.
+func parseAddress(address string, networks ...string) (string, string, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid address %q: %w", address, err)
+	}
+
+	for _, network := range networks {
+		if u.Scheme != network {
+			continue
+		}
+
+		if network == "unix" {
+			if u.Path == "" {
+				return "", "", fmt.Errorf("invalid address %q: missing socket path", address)
+			}
+			return network, u.Path, nil
+		}
+
+		if u.Host == "" {
+			return "", "", fmt.Errorf("invalid address %q: missing host", address)
+		}
+		return network, u.Host, nil
+	}
+
+	return "", "", fmt.Errorf("invalid address %q: the scheme must be one of %v", address, networks)
+}
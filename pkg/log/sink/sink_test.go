@@ -0,0 +1,176 @@
+package sink
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestSyslog_udp(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	writer, err := NewSyslog(&types.Syslog{
+		Address:    "udp://" + conn.LocalAddr().String(),
+		Tag:        "traefik",
+		Facility:   "local0",
+		BufferSize: 10,
+	})
+	require.NoError(t, err)
+
+	_, err = writer.Write([]byte("foo bar\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Close())
+
+	expected := `^<134>1 \S+ \S+ traefik ` + strconv.Itoa(os.Getpid()) + ` - - foo bar$`
+	assert.Regexp(t, regexp.MustCompile(expected), string(buf[:n]))
+}
+
+func TestSyslog_tcp(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	writer, err := NewSyslog(&types.Syslog{
+		Address:    "tcp://" + listener.Addr().String(),
+		Tag:        "traefik",
+		Facility:   "daemon",
+		BufferSize: 10,
+	})
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	logger.AddHook(NewHook(writer, logger.Formatter))
+
+	logger.Error("first")
+	logger.Warn("second")
+
+	require.NoError(t, writer.Close())
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	data, err := io.ReadAll(conn)
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(strings.NewReader(string(data)))
+	var messages []string
+	for {
+		length, err := reader.ReadString(' ')
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		n, err := strconv.Atoi(strings.TrimSpace(length))
+		require.NoError(t, err)
+
+		msg := make([]byte, n)
+		_, err = io.ReadFull(reader, msg)
+		require.NoError(t, err)
+
+		messages = append(messages, string(msg))
+	}
+
+	require.Len(t, messages, 2)
+	assert.Regexp(t, `^<27>1 .* - - level=error msg=first$`, messages[0])
+	assert.Regexp(t, `^<28>1 .* - - level=warning msg=second$`, messages[1])
+}
+
+func TestNewSyslog_invalid(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config types.Syslog
+	}{
+		{
+			desc:   "unknown scheme",
+			config: types.Syslog{Address: "http://127.0.0.1:514", Facility: "daemon"},
+		},
+		{
+			desc:   "missing host",
+			config: types.Syslog{Address: "udp://", Facility: "daemon"},
+		},
+		{
+			desc:   "unknown facility",
+			config: types.Syslog{Address: "udp://127.0.0.1:514", Facility: "foo"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewSyslog(&test.config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFluentd(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	writer, err := NewFluentd(&types.Fluentd{
+		Address:    "tcp://" + listener.Addr().String(),
+		Tag:        "traefik",
+		BufferSize: 10,
+	})
+	require.NoError(t, err)
+
+	_, err = writer.Write([]byte(`{"Duration":12,"RequestHost":"foo.com","ok":true}` + "\n"))
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("foo\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Close())
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	data, err := io.ReadAll(conn)
+	require.NoError(t, err)
+
+	first := []byte{0x93, 0xa7}
+	first = append(first, "traefik"...)
+	first = append(first, 0xd2)
+	// The timestamp is skipped.
+	second := []byte{0x83,
+		0xa8, 'D', 'u', 'r', 'a', 't', 'i', 'o', 'n', 0x0c,
+		0xab, 'R', 'e', 'q', 'u', 'e', 's', 't', 'H', 'o', 's', 't', 0xa7, 'f', 'o', 'o', '.', 'c', 'o', 'm',
+		0xa2, 'o', 'k', 0xc3,
+	}
+	third := []byte{0x81, 0xa7, 'm', 'e', 's', 's', 'a', 'g', 'e', 0xa3, 'f', 'o', 'o'}
+
+	messageLen := len(first) + 4 + len(second)
+	require.Len(t, data, messageLen+len(first)+4+len(third))
+
+	assert.Equal(t, first, data[:len(first)])
+	assert.Equal(t, second, data[len(first)+4:messageLen])
+	assert.Equal(t, first, data[messageLen:messageLen+len(first)])
+	assert.Equal(t, third, data[messageLen+len(first)+4:])
+}
@@ -0,0 +1,109 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+var facilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// severities maps the log levels to the syslog severities.
+var severities = map[logrus.Level]int{
+	logrus.PanicLevel: 2,
+	logrus.FatalLevel: 2,
+	logrus.ErrorLevel: 3,
+	logrus.WarnLevel:  4,
+	logrus.InfoLevel:  6,
+	logrus.DebugLevel: 7,
+	logrus.TraceLevel: 7,
+}
+
+const severityInfo = 6
+
+// NewSyslog creates a Writer sending the log entries to a syslog server, using the RFC 5424 format.
+func NewSyslog(config *types.Syslog) (*Writer, error) {
+	network, address, err := parseAddress(config.Address, "udp", "tcp", "unix")
+	if err != nil {
+		return nil, err
+	}
+
+	facility, ok := facilities[config.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", config.Facility)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	tag := config.Tag
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+
+	encoder := syslogEncoder{
+		facility: facility,
+		hostname: hostname,
+		tag:      tag,
+		pid:      os.Getpid(),
+		// Over a stream, the messages are delimited with octet counting (RFC 6587).
+		framed: network == "tcp",
+	}
+
+	return newWriter(network, address, config.BufferSize, encoder.encode), nil
+}
+
+type syslogEncoder struct {
+	facility int
+	hostname string
+	tag      string
+	pid      int
+	framed   bool
+}
+
+func (s syslogEncoder) encode(e entry) ([]byte, error) {
+	severity := severityInfo
+	if e.level != nil {
+		if sev, ok := severities[*e.level]; ok {
+			severity = sev
+		}
+	}
+
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		s.facility*8+severity, e.time.UTC().Format(time.RFC3339Nano), s.hostname, s.tag, s.pid, bytes.TrimRight(e.line, "\n"))
+
+	if s.framed {
+		return []byte(strconv.Itoa(len(msg)) + " " + msg), nil
+	}
+
+	return []byte(msg), nil
+}
@@ -42,6 +42,9 @@ const (
 	ddRetriesTotalName               = "service.retries.total"
 	ddOpenConnsName                  = "service.connections.open"
 	ddServerUpName                   = "service.server.up"
+	ddServerHealthChecksName         = "service.server.healthcheck.total"
+	ddServerHealthCheckFailuresName  = "service.server.healthcheck.consecutive.failures"
+	ddServerHealthCheckDurationName  = "service.server.healthcheck.duration"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -82,6 +85,9 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = datadogClient.NewGauge(ddOpenConnsName)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServerUpName)
+		registry.serviceServerHealthChecksCounter = datadogClient.NewCounter(ddServerHealthChecksName, 1.0)
+		registry.serviceServerHealthCheckFailuresGauge = datadogClient.NewGauge(ddServerHealthCheckFailuresName)
+		registry.serviceServerHealthCheckDurationGauge = datadogClient.NewGauge(ddServerHealthCheckDurationName)
 	}
 
 	return registry
@@ -43,12 +43,15 @@ const (
 	influxDBRouterReqsDurationName = "traefik.router.request.duration"
 	influxDBORouterOpenConnsName   = "traefik.router.connections.open"
 
-	influxDBServiceReqsName         = "traefik.service.requests.total"
-	influxDBServiceReqsTLSName      = "traefik.service.requests.tls.total"
-	influxDBServiceReqsDurationName = "traefik.service.request.duration"
-	influxDBServiceRetriesTotalName = "traefik.service.retries.total"
-	influxDBServiceOpenConnsName    = "traefik.service.connections.open"
-	influxDBServiceServerUpName     = "traefik.service.server.up"
+	influxDBServiceReqsName                      = "traefik.service.requests.total"
+	influxDBServiceReqsTLSName                   = "traefik.service.requests.tls.total"
+	influxDBServiceReqsDurationName              = "traefik.service.request.duration"
+	influxDBServiceRetriesTotalName              = "traefik.service.retries.total"
+	influxDBServiceOpenConnsName                 = "traefik.service.connections.open"
+	influxDBServiceServerUpName                  = "traefik.service.server.up"
+	influxDBServiceServerHealthChecksName        = "traefik.service.server.healthcheck.total"
+	influxDBServiceServerHealthCheckFailuresName = "traefik.service.server.healthcheck.consecutive.failures"
+	influxDBServiceServerHealthCheckDurationName = "traefik.service.server.healthcheck.duration"
 )
 
 const (
@@ -65,38 +68,46 @@ func RegisterInfluxDB(ctx context.Context, config *types.InfluxDB) Registry {
 		influxDBTicker = initInfluxDBTicker(ctx, config)
 	}
 
+	return newInfluxDBRegistry(influxDBClient, config.AddEntryPointsLabels, config.AddRoutersLabels, config.AddServicesLabels)
+}
+
+// newInfluxDBRegistry creates a Registry of the metrics sent by the given InfluxDB client.
+func newInfluxDBRegistry(client *influx.Influx, addEntryPointsLabels, addRoutersLabels, addServicesLabels bool) Registry {
 	registry := &standardRegistry{
-		configReloadsCounter:           influxDBClient.NewCounter(influxDBConfigReloadsName),
-		configReloadsFailureCounter:    influxDBClient.NewCounter(influxDBConfigReloadsFailureName),
-		lastConfigReloadSuccessGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadSuccessName),
-		lastConfigReloadFailureGauge:   influxDBClient.NewGauge(influxDBLastConfigReloadFailureName),
-		tlsCertsNotAfterTimestampGauge: influxDBClient.NewGauge(influxDBTLSCertsNotAfterTimestampName),
-	}
-
-	if config.AddEntryPointsLabels {
-		registry.epEnabled = config.AddEntryPointsLabels
-		registry.entryPointReqsCounter = influxDBClient.NewCounter(influxDBEntryPointReqsName)
-		registry.entryPointReqsTLSCounter = influxDBClient.NewCounter(influxDBEntryPointReqsTLSName)
-		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBEntryPointReqDurationName), time.Second)
-		registry.entryPointOpenConnsGauge = influxDBClient.NewGauge(influxDBEntryPointOpenConnsName)
-	}
-
-	if config.AddRoutersLabels {
-		registry.routerEnabled = config.AddRoutersLabels
-		registry.routerReqsCounter = influxDBClient.NewCounter(influxDBRouterReqsName)
-		registry.routerReqsTLSCounter = influxDBClient.NewCounter(influxDBRouterReqsTLSName)
-		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBRouterReqsDurationName), time.Second)
-		registry.routerOpenConnsGauge = influxDBClient.NewGauge(influxDBORouterOpenConnsName)
-	}
-
-	if config.AddServicesLabels {
-		registry.svcEnabled = config.AddServicesLabels
-		registry.serviceReqsCounter = influxDBClient.NewCounter(influxDBServiceReqsName)
-		registry.serviceReqsTLSCounter = influxDBClient.NewCounter(influxDBServiceReqsTLSName)
-		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(influxDBClient.NewHistogram(influxDBServiceReqsDurationName), time.Second)
-		registry.serviceRetriesCounter = influxDBClient.NewCounter(influxDBServiceRetriesTotalName)
-		registry.serviceOpenConnsGauge = influxDBClient.NewGauge(influxDBServiceOpenConnsName)
-		registry.serviceServerUpGauge = influxDBClient.NewGauge(influxDBServiceServerUpName)
+		configReloadsCounter:           client.NewCounter(influxDBConfigReloadsName),
+		configReloadsFailureCounter:    client.NewCounter(influxDBConfigReloadsFailureName),
+		lastConfigReloadSuccessGauge:   client.NewGauge(influxDBLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:   client.NewGauge(influxDBLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge: client.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+	}
+
+	if addEntryPointsLabels {
+		registry.epEnabled = addEntryPointsLabels
+		registry.entryPointReqsCounter = client.NewCounter(influxDBEntryPointReqsName)
+		registry.entryPointReqsTLSCounter = client.NewCounter(influxDBEntryPointReqsTLSName)
+		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(client.NewHistogram(influxDBEntryPointReqDurationName), time.Second)
+		registry.entryPointOpenConnsGauge = client.NewGauge(influxDBEntryPointOpenConnsName)
+	}
+
+	if addRoutersLabels {
+		registry.routerEnabled = addRoutersLabels
+		registry.routerReqsCounter = client.NewCounter(influxDBRouterReqsName)
+		registry.routerReqsTLSCounter = client.NewCounter(influxDBRouterReqsTLSName)
+		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(client.NewHistogram(influxDBRouterReqsDurationName), time.Second)
+		registry.routerOpenConnsGauge = client.NewGauge(influxDBORouterOpenConnsName)
+	}
+
+	if addServicesLabels {
+		registry.svcEnabled = addServicesLabels
+		registry.serviceReqsCounter = client.NewCounter(influxDBServiceReqsName)
+		registry.serviceReqsTLSCounter = client.NewCounter(influxDBServiceReqsTLSName)
+		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(client.NewHistogram(influxDBServiceReqsDurationName), time.Second)
+		registry.serviceRetriesCounter = client.NewCounter(influxDBServiceRetriesTotalName)
+		registry.serviceOpenConnsGauge = client.NewGauge(influxDBServiceOpenConnsName)
+		registry.serviceServerUpGauge = client.NewGauge(influxDBServiceServerUpName)
+		registry.serviceServerHealthChecksCounter = client.NewCounter(influxDBServiceServerHealthChecksName)
+		registry.serviceServerHealthCheckFailuresGauge = client.NewGauge(influxDBServiceServerHealthCheckFailuresName)
+		registry.serviceServerHealthCheckDurationGauge = client.NewGauge(influxDBServiceServerHealthCheckDurationName)
 	}
 
 	return registry
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	kitlog "github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics/influx"
+	influxdb "github.com/influxdata/influxdb1-client/v2"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+var (
+	influxDB2Client *influx.Influx
+	influxDB2Ticker *time.Ticker
+)
+
+// RegisterInfluxDB2 registers the metrics pusher if this didn't happen yet and creates an InfluxDB v2 Registry instance.
+// The metrics are the ones of the InfluxDB Registry, written with the InfluxDB v2 API.
+func RegisterInfluxDB2(ctx context.Context, config *types.InfluxDB2) Registry {
+	if influxDB2Client == nil {
+		influxDB2Client = influx.New(
+			map[string]string{},
+			influxdb.BatchPointsConfig{},
+			kitlog.LoggerFunc(func(keyvals ...interface{}) error {
+				log.WithoutContext().WithField(log.MetricsProviderName, "influxdb2").Info(keyvals)
+				return nil
+			}))
+	}
+	if influxDB2Ticker == nil {
+		influxDB2Ticker = initInfluxDB2Ticker(ctx, config)
+	}
+
+	return newInfluxDBRegistry(influxDB2Client, config.AddEntryPointsLabels, config.AddRoutersLabels, config.AddServicesLabels)
+}
+
+// initInfluxDB2Ticker initializes the metrics pusher.
+func initInfluxDB2Ticker(ctx context.Context, config *types.InfluxDB2) *time.Ticker {
+	report := time.NewTicker(time.Duration(config.PushInterval))
+
+	writer, err := newInfluxDB2Writer(config)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Unable to create the InfluxDB v2 writer: %v", err)
+		return report
+	}
+
+	safe.Go(func() {
+		influxDB2Client.WriteLoop(ctx, report.C, writer)
+	})
+
+	return report
+}
+
+// StopInfluxDB2 stops the internal influxDB2Ticker which controls the pushing of metrics to InfluxDB, and resets it to `nil`.
+func StopInfluxDB2() {
+	if influxDB2Ticker != nil {
+		influxDB2Ticker.Stop()
+	}
+	influxDB2Ticker = nil
+}
+
+// influxDB2Writer writes the points with the write endpoint of the InfluxDB v2 API.
+type influxDB2Writer struct {
+	client   *http.Client
+	writeURL string
+	token    string
+}
+
+func newInfluxDB2Writer(config *types.InfluxDB2) (*influxDB2Writer, error) {
+	if config.Org == "" || config.Bucket == "" {
+		return nil, errors.New("the organization and the bucket are required")
+	}
+
+	address := config.Address
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+
+	writeURL, err := url.Parse(strings.TrimSuffix(address, "/") + "/api/v2/write")
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", config.Address, err)
+	}
+
+	query := url.Values{}
+	query.Set("org", config.Org)
+	query.Set("bucket", config.Bucket)
+	query.Set("precision", "ns")
+	writeURL.RawQuery = query.Encode()
+
+	return &influxDB2Writer{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		writeURL: writeURL.String(),
+		token:    config.Token,
+	}, nil
+}
+
+// Write sends the points in line protocol.
+func (w *influxDB2Writer) Write(bp influxdb.BatchPoints) error {
+	var body bytes.Buffer
+	for _, point := range bp.Points() {
+		body.WriteString(point.PrecisionString("ns"))
+		body.WriteByte('\n')
+	}
+
+	if body.Len() == 0 {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, w.writeURL, &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.token != "" {
+		req.Header.Set("Authorization", "Token "+w.token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error while writing to InfluxDB: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("error while writing to InfluxDB: %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestInfluxDB2(t *testing.T) {
+	c := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v2/write" || req.Header.Get("Authorization") != "Token secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		query := req.URL.Query()
+		if query.Get("org") != "traefik" || query.Get("bucket") != "metrics" || query.Get("precision") != "ns" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, "can't read body "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Only the first push is checked.
+		select {
+		case c <- string(body):
+		default:
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	influxDB2Registry := RegisterInfluxDB2(context.Background(), &types.InfluxDB2{
+		Address:              ts.URL,
+		Token:                "secret",
+		Org:                  "traefik",
+		Bucket:               "metrics",
+		PushInterval:         ptypes.Duration(time.Second),
+		AddEntryPointsLabels: true,
+		AddServicesLabels:    true,
+	})
+	defer StopInfluxDB2()
+
+	if !influxDB2Registry.IsEpEnabled() || influxDB2Registry.IsRouterEnabled() || !influxDB2Registry.IsSvcEnabled() {
+		t.Fatalf("InfluxDB v2 registry must be epEnabled and svcEnabled")
+	}
+
+	expected := []string{
+		`(traefik\.config\.reload\.total count=1) [\d]{19}`,
+		`(traefik\.entrypoint\.requests\.total,code=200,entrypoint=test,method=GET count=1) [\d]{19}`,
+		`(traefik\.service\.requests\.total,code=404,method=GET,service=test count=1) [\d]{19}`,
+	}
+
+	influxDB2Registry.ConfigReloadsCounter().Add(1)
+	influxDB2Registry.EntryPointReqsCounter().With("entrypoint", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
+	influxDB2Registry.ServiceReqsCounter().With("service", "test", "code", strconv.Itoa(http.StatusNotFound), "method", http.MethodGet).Add(1)
+
+	select {
+	case msg := <-c:
+		assertMessage(t, msg, expected)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the metrics")
+	}
+}
+
+func TestInfluxDB2Writer(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      types.InfluxDB2
+		expectedURL string
+		expectErr   bool
+	}{
+		{
+			desc:        "address with scheme",
+			config:      types.InfluxDB2{Address: "https://influxdb:8086/", Org: "my org", Bucket: "metrics"},
+			expectedURL: "https://influxdb:8086/api/v2/write?bucket=metrics&org=my+org&precision=ns",
+		},
+		{
+			desc:        "address without scheme",
+			config:      types.InfluxDB2{Address: "influxdb:8086", Org: "traefik", Bucket: "metrics"},
+			expectedURL: "http://influxdb:8086/api/v2/write?bucket=metrics&org=traefik&precision=ns",
+		},
+		{
+			desc:      "missing bucket",
+			config:    types.InfluxDB2{Address: "http://influxdb:8086", Org: "traefik"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			writer, err := newInfluxDB2Writer(&test.config)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedURL, writer.writeURL)
+		})
+	}
+}
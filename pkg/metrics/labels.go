@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// labelsRewriter rewrites the label values of the metrics, to control their cardinality.
+type labelsRewriter struct {
+	drop           map[string]struct{}
+	aggregateCodes bool
+
+	hashThreshold int
+	hashBuckets   uint32
+
+	mu sync.RWMutex
+	// hashed holds, for each hashed label, the values kept as is.
+	hashed map[string]map[string]struct{}
+}
+
+func newLabelsRewriter(config *types.MetricsLabels) *labelsRewriter {
+	rewriter := &labelsRewriter{
+		drop:           make(map[string]struct{}),
+		aggregateCodes: config.AggregateCodes,
+		hashThreshold:  config.HashThreshold,
+		hashBuckets:    1,
+		hashed:         make(map[string]map[string]struct{}),
+	}
+
+	if config.HashBuckets > 0 {
+		rewriter.hashBuckets = uint32(config.HashBuckets)
+	}
+
+	for _, name := range config.Drop {
+		rewriter.drop[name] = struct{}{}
+	}
+
+	for _, name := range config.Hash {
+		rewriter.hashed[name] = make(map[string]struct{})
+	}
+
+	return rewriter
+}
+
+// rewrite returns the rewritten label values, given as label names and values pairs.
+func (r *labelsRewriter) rewrite(labelValues []string) []string {
+	rewritten := make([]string, len(labelValues))
+	copy(rewritten, labelValues)
+
+	for i := 0; i+1 < len(rewritten); i += 2 {
+		rewritten[i+1] = r.rewriteValue(rewritten[i], rewritten[i+1])
+	}
+
+	return rewritten
+}
+
+func (r *labelsRewriter) rewriteValue(name, value string) string {
+	if _, ok := r.drop[name]; ok {
+		return ""
+	}
+
+	if name == "code" && r.aggregateCodes && len(value) == 3 {
+		return value[:1] + "xx"
+	}
+
+	if _, ok := r.hashed[name]; ok {
+		return r.hashValue(name, value)
+	}
+
+	return value
+}
+
+// hashValue keeps the first distinct values of the label as is,
+// and spreads the next ones over the hash buckets.
+// A value is always rewritten the same way, so that the gauges are consistently increased and decreased.
+func (r *labelsRewriter) hashValue(name, value string) string {
+	r.mu.RLock()
+	_, kept := r.hashed[name][value]
+	full := len(r.hashed[name]) >= r.hashThreshold
+	r.mu.RUnlock()
+
+	if kept {
+		return value
+	}
+
+	if !full {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		values := r.hashed[name]
+		if _, ok := values[value]; ok || len(values) < r.hashThreshold {
+			values[value] = struct{}{}
+			return value
+		}
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+
+	return fmt.Sprintf("hash_%d", h.Sum32()%r.hashBuckets)
+}
+
+// RewriteLabels returns a registry rewriting the label values of the metrics of the given registry,
+// according to the given configuration.
+func RewriteLabels(registry Registry, config *types.MetricsLabels) Registry {
+	if config == nil {
+		return registry
+	}
+
+	rewriter := newLabelsRewriter(config)
+
+	return &standardRegistry{
+		epEnabled:                             registry.IsEpEnabled(),
+		routerEnabled:                         registry.IsRouterEnabled(),
+		svcEnabled:                            registry.IsSvcEnabled(),
+		configReloadsCounter:                  rewriter.counter(registry.ConfigReloadsCounter()),
+		configReloadsFailureCounter:           rewriter.counter(registry.ConfigReloadsFailureCounter()),
+		lastConfigReloadSuccessGauge:          rewriter.gauge(registry.LastConfigReloadSuccessGauge()),
+		lastConfigReloadFailureGauge:          rewriter.gauge(registry.LastConfigReloadFailureGauge()),
+		tlsCertsNotAfterTimestampGauge:        rewriter.gauge(registry.TLSCertsNotAfterTimestampGauge()),
+		entryPointReqsCounter:                 rewriter.counter(registry.EntryPointReqsCounter()),
+		entryPointReqsTLSCounter:              rewriter.counter(registry.EntryPointReqsTLSCounter()),
+		entryPointReqDurationHistogram:        rewriter.histogram(registry.EntryPointReqDurationHistogram()),
+		entryPointOpenConnsGauge:              rewriter.gauge(registry.EntryPointOpenConnsGauge()),
+		entryPointRejectedConnsCounter:        rewriter.counter(registry.EntryPointRejectedConnsCounter()),
+		entryPointSlowReqsCounter:             rewriter.counter(registry.EntryPointSlowReqsCounter()),
+		entryPointUDPSessionsGauge:            rewriter.gauge(registry.EntryPointUDPSessionsGauge()),
+		routerReqsCounter:                     rewriter.counter(registry.RouterReqsCounter()),
+		routerReqsTLSCounter:                  rewriter.counter(registry.RouterReqsTLSCounter()),
+		routerReqDurationHistogram:            rewriter.histogram(registry.RouterReqDurationHistogram()),
+		routerOpenConnsGauge:                  rewriter.gauge(registry.RouterOpenConnsGauge()),
+		routerConnsCounter:                    rewriter.counter(registry.RouterConnsCounter()),
+		routerConnDurationHistogram:           rewriter.histogram(registry.RouterConnDurationHistogram()),
+		routerConnBytesCounter:                rewriter.counter(registry.RouterConnBytesCounter()),
+		serviceReqsCounter:                    rewriter.counter(registry.ServiceReqsCounter()),
+		serviceReqsTLSCounter:                 rewriter.counter(registry.ServiceReqsTLSCounter()),
+		serviceReqDurationHistogram:           rewriter.histogram(registry.ServiceReqDurationHistogram()),
+		serviceOpenConnsGauge:                 rewriter.gauge(registry.ServiceOpenConnsGauge()),
+		serviceRetriesCounter:                 rewriter.counter(registry.ServiceRetriesCounter()),
+		serviceDNSFailuresCounter:             rewriter.counter(registry.ServiceDNSFailuresCounter()),
+		serviceServerUpGauge:                  rewriter.gauge(registry.ServiceServerUpGauge()),
+		serviceServerHealthChecksCounter:      rewriter.counter(registry.ServiceServerHealthChecksCounter()),
+		serviceServerHealthCheckFailuresGauge: rewriter.gauge(registry.ServiceServerHealthCheckFailuresGauge()),
+		serviceServerHealthCheckDurationGauge: rewriter.gauge(registry.ServiceServerHealthCheckDurationGauge()),
+		serviceFailoverGauge:                  rewriter.gauge(registry.ServiceFailoverGauge()),
+		middlewareRejectedReqsCounter:         rewriter.counter(registry.MiddlewareRejectedReqsCounter()),
+		rulesCacheRequestsCounter:             rewriter.counter(registry.RulesCacheRequestsCounter()),
+		providerSuppressedReloadsCounter:      rewriter.counter(registry.ProviderSuppressedReloadsCounter()),
+	}
+}
+
+func (r *labelsRewriter) counter(counter metrics.Counter) metrics.Counter {
+	if counter == nil {
+		return nil
+	}
+	return &rewrittenCounter{counter: counter, rewriter: r}
+}
+
+func (r *labelsRewriter) gauge(gauge metrics.Gauge) metrics.Gauge {
+	if gauge == nil {
+		return nil
+	}
+	return &rewrittenGauge{gauge: gauge, rewriter: r}
+}
+
+func (r *labelsRewriter) histogram(histogram ScalableHistogram) ScalableHistogram {
+	if histogram == nil {
+		return nil
+	}
+	return &rewrittenHistogram{histogram: histogram, rewriter: r}
+}
+
+type rewrittenCounter struct {
+	counter  metrics.Counter
+	rewriter *labelsRewriter
+}
+
+func (c *rewrittenCounter) With(labelValues ...string) metrics.Counter {
+	return &rewrittenCounter{counter: c.counter.With(c.rewriter.rewrite(labelValues)...), rewriter: c.rewriter}
+}
+
+func (c *rewrittenCounter) Add(delta float64) {
+	c.counter.Add(delta)
+}
+
+type rewrittenGauge struct {
+	gauge    metrics.Gauge
+	rewriter *labelsRewriter
+}
+
+func (g *rewrittenGauge) With(labelValues ...string) metrics.Gauge {
+	return &rewrittenGauge{gauge: g.gauge.With(g.rewriter.rewrite(labelValues)...), rewriter: g.rewriter}
+}
+
+func (g *rewrittenGauge) Set(value float64) {
+	g.gauge.Set(value)
+}
+
+func (g *rewrittenGauge) Add(delta float64) {
+	g.gauge.Add(delta)
+}
+
+type rewrittenHistogram struct {
+	histogram ScalableHistogram
+	rewriter  *labelsRewriter
+}
+
+func (h *rewrittenHistogram) With(labelValues ...string) ScalableHistogram {
+	return &rewrittenHistogram{histogram: h.histogram.With(h.rewriter.rewrite(labelValues)...), rewriter: h.rewriter}
+}
+
+func (h *rewrittenHistogram) Observe(v float64) {
+	h.histogram.Observe(v)
+}
+
+func (h *rewrittenHistogram) ObserveFromStart(start time.Time) {
+	h.histogram.ObserveFromStart(start)
+}
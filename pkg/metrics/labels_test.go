@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestLabelsRewriter_rewrite(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      types.MetricsLabels
+		labelValues []string
+		expected    []string
+	}{
+		{
+			desc:        "no rewrite",
+			config:      types.MetricsLabels{},
+			labelValues: []string{"service", "foo", "method", "GET", "code", "404"},
+			expected:    []string{"service", "foo", "method", "GET", "code", "404"},
+		},
+		{
+			desc:        "drop",
+			config:      types.MetricsLabels{Drop: []string{"method", "protocol"}},
+			labelValues: []string{"service", "foo", "method", "GET", "protocol", "http"},
+			expected:    []string{"service", "foo", "method", "", "protocol", ""},
+		},
+		{
+			desc:        "aggregate codes",
+			config:      types.MetricsLabels{AggregateCodes: true},
+			labelValues: []string{"service", "foo", "code", "404"},
+			expected:    []string{"service", "foo", "code", "4xx"},
+		},
+		{
+			desc:        "hash under the threshold",
+			config:      types.MetricsLabels{Hash: []string{"service"}, HashThreshold: 1, HashBuckets: 4},
+			labelValues: []string{"service", "foo"},
+			expected:    []string{"service", "foo"},
+		},
+		{
+			desc:        "odd number of label values",
+			config:      types.MetricsLabels{Drop: []string{"service"}},
+			labelValues: []string{"service", "foo", "method"},
+			expected:    []string{"service", "", "method"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rewriter := newLabelsRewriter(&test.config)
+			assert.Equal(t, test.expected, rewriter.rewrite(test.labelValues))
+		})
+	}
+}
+
+func TestLabelsRewriter_hashValue(t *testing.T) {
+	rewriter := newLabelsRewriter(&types.MetricsLabels{Hash: []string{"service"}, HashThreshold: 2, HashBuckets: 4})
+
+	assert.Equal(t, "foo", rewriter.hashValue("service", "foo"))
+	assert.Equal(t, "bar", rewriter.hashValue("service", "bar"))
+
+	// Over the threshold, the values are hashed, always the same way.
+	hashed := rewriter.hashValue("service", "baz")
+	assert.Regexp(t, `^hash_[0-3]$`, hashed)
+	assert.Equal(t, hashed, rewriter.hashValue("service", "baz"))
+
+	// The values kept as is stay so.
+	assert.Equal(t, "foo", rewriter.hashValue("service", "foo"))
+}
+
+func TestRewriteLabels(t *testing.T) {
+	registry := newCollectingRetryMetrics()
+	assert.Same(t, registry, RewriteLabels(registry, nil))
+
+	rewritten := RewriteLabels(registry, &types.MetricsLabels{Drop: []string{"method"}, AggregateCodes: true})
+
+	rewritten.ServiceReqsCounter().With("service", "foo", "method", "GET", "code", "502").Add(1)
+	rewritten.ServiceReqDurationHistogram().With("service", "foo", "code", "200").Observe(2)
+
+	cReqsCounter := registry.ServiceReqsCounter().(*counterMock)
+	assert.Equal(t, float64(1), cReqsCounter.counterValue)
+	assert.Equal(t, []string{"service", "foo", "method", "", "code", "5xx"}, cReqsCounter.lastLabelValues)
+
+	cReqDurationHistogram := registry.ServiceReqDurationHistogram().(*histogramMock)
+	assert.Equal(t, float64(2), cReqDurationHistogram.lastHistogramValue)
+	assert.Equal(t, []string{"service", "foo", "code", "2xx"}, cReqDurationHistogram.lastLabelValues)
+
+	// Metrics missing from the registry stay missing.
+	assert.Nil(t, rewritten.ConfigReloadsCounter())
+}
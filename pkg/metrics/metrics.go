@@ -31,12 +31,18 @@ type Registry interface {
 	EntryPointReqsTLSCounter() metrics.Counter
 	EntryPointReqDurationHistogram() ScalableHistogram
 	EntryPointOpenConnsGauge() metrics.Gauge
+	EntryPointRejectedConnsCounter() metrics.Counter
+	EntryPointSlowReqsCounter() metrics.Counter
+	EntryPointUDPSessionsGauge() metrics.Gauge
 
 	// router metrics
 	RouterReqsCounter() metrics.Counter
 	RouterReqsTLSCounter() metrics.Counter
 	RouterReqDurationHistogram() ScalableHistogram
 	RouterOpenConnsGauge() metrics.Gauge
+	RouterConnsCounter() metrics.Counter
+	RouterConnDurationHistogram() ScalableHistogram
+	RouterConnBytesCounter() metrics.Counter
 
 	// service metrics
 	ServiceReqsCounter() metrics.Counter
@@ -44,7 +50,21 @@ type Registry interface {
 	ServiceReqDurationHistogram() ScalableHistogram
 	ServiceOpenConnsGauge() metrics.Gauge
 	ServiceRetriesCounter() metrics.Counter
+	ServiceDNSFailuresCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
+	ServiceServerHealthChecksCounter() metrics.Counter
+	ServiceServerHealthCheckFailuresGauge() metrics.Gauge
+	ServiceServerHealthCheckDurationGauge() metrics.Gauge
+	ServiceFailoverGauge() metrics.Gauge
+
+	// middleware metrics
+	MiddlewareRejectedReqsCounter() metrics.Counter
+
+	// rules metrics
+	RulesCacheRequestsCounter() metrics.Counter
+
+	// provider metrics
+	ProviderSuppressedReloadsCounter() metrics.Counter
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -66,16 +86,30 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
 	var entryPointOpenConnsGauge []metrics.Gauge
+	var entryPointRejectedConnsCounter []metrics.Counter
+	var entryPointSlowReqsCounter []metrics.Counter
+	var entryPointUDPSessionsGauge []metrics.Gauge
 	var routerReqsCounter []metrics.Counter
 	var routerReqsTLSCounter []metrics.Counter
 	var routerReqDurationHistogram []ScalableHistogram
 	var routerOpenConnsGauge []metrics.Gauge
+	var routerConnsCounter []metrics.Counter
+	var routerConnDurationHistogram []ScalableHistogram
+	var routerConnBytesCounter []metrics.Counter
 	var serviceReqsCounter []metrics.Counter
 	var serviceReqsTLSCounter []metrics.Counter
 	var serviceReqDurationHistogram []ScalableHistogram
 	var serviceOpenConnsGauge []metrics.Gauge
 	var serviceRetriesCounter []metrics.Counter
+	var serviceDNSFailuresCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
+	var serviceServerHealthChecksCounter []metrics.Counter
+	var serviceServerHealthCheckFailuresGauge []metrics.Gauge
+	var serviceServerHealthCheckDurationGauge []metrics.Gauge
+	var serviceFailoverGauge []metrics.Gauge
+	var middlewareRejectedReqsCounter []metrics.Counter
+	var rulesCacheRequestsCounter []metrics.Counter
+	var providerSuppressedReloadsCounter []metrics.Counter
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -105,6 +139,15 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.EntryPointOpenConnsGauge() != nil {
 			entryPointOpenConnsGauge = append(entryPointOpenConnsGauge, r.EntryPointOpenConnsGauge())
 		}
+		if r.EntryPointRejectedConnsCounter() != nil {
+			entryPointRejectedConnsCounter = append(entryPointRejectedConnsCounter, r.EntryPointRejectedConnsCounter())
+		}
+		if r.EntryPointSlowReqsCounter() != nil {
+			entryPointSlowReqsCounter = append(entryPointSlowReqsCounter, r.EntryPointSlowReqsCounter())
+		}
+		if r.EntryPointUDPSessionsGauge() != nil {
+			entryPointUDPSessionsGauge = append(entryPointUDPSessionsGauge, r.EntryPointUDPSessionsGauge())
+		}
 		if r.RouterReqsCounter() != nil {
 			routerReqsCounter = append(routerReqsCounter, r.RouterReqsCounter())
 		}
@@ -117,6 +160,15 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.RouterOpenConnsGauge() != nil {
 			routerOpenConnsGauge = append(routerOpenConnsGauge, r.RouterOpenConnsGauge())
 		}
+		if r.RouterConnsCounter() != nil {
+			routerConnsCounter = append(routerConnsCounter, r.RouterConnsCounter())
+		}
+		if r.RouterConnDurationHistogram() != nil {
+			routerConnDurationHistogram = append(routerConnDurationHistogram, r.RouterConnDurationHistogram())
+		}
+		if r.RouterConnBytesCounter() != nil {
+			routerConnBytesCounter = append(routerConnBytesCounter, r.RouterConnBytesCounter())
+		}
 		if r.ServiceReqsCounter() != nil {
 			serviceReqsCounter = append(serviceReqsCounter, r.ServiceReqsCounter())
 		}
@@ -132,60 +184,112 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceRetriesCounter() != nil {
 			serviceRetriesCounter = append(serviceRetriesCounter, r.ServiceRetriesCounter())
 		}
+		if r.ServiceDNSFailuresCounter() != nil {
+			serviceDNSFailuresCounter = append(serviceDNSFailuresCounter, r.ServiceDNSFailuresCounter())
+		}
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
+		if r.ServiceServerHealthChecksCounter() != nil {
+			serviceServerHealthChecksCounter = append(serviceServerHealthChecksCounter, r.ServiceServerHealthChecksCounter())
+		}
+		if r.ServiceServerHealthCheckFailuresGauge() != nil {
+			serviceServerHealthCheckFailuresGauge = append(serviceServerHealthCheckFailuresGauge, r.ServiceServerHealthCheckFailuresGauge())
+		}
+		if r.ServiceServerHealthCheckDurationGauge() != nil {
+			serviceServerHealthCheckDurationGauge = append(serviceServerHealthCheckDurationGauge, r.ServiceServerHealthCheckDurationGauge())
+		}
+		if r.ServiceFailoverGauge() != nil {
+			serviceFailoverGauge = append(serviceFailoverGauge, r.ServiceFailoverGauge())
+		}
+		if r.MiddlewareRejectedReqsCounter() != nil {
+			middlewareRejectedReqsCounter = append(middlewareRejectedReqsCounter, r.MiddlewareRejectedReqsCounter())
+		}
+		if r.RulesCacheRequestsCounter() != nil {
+			rulesCacheRequestsCounter = append(rulesCacheRequestsCounter, r.RulesCacheRequestsCounter())
+		}
+		if r.ProviderSuppressedReloadsCounter() != nil {
+			providerSuppressedReloadsCounter = append(providerSuppressedReloadsCounter, r.ProviderSuppressedReloadsCounter())
+		}
 	}
 
 	return &standardRegistry{
-		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
-		routerEnabled:                  len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0 || len(routerOpenConnsGauge) > 0,
-		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
-		configReloadsFailureCounter:    multi.NewCounter(configReloadsFailureCounter...),
-		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
-		lastConfigReloadFailureGauge:   multi.NewGauge(lastConfigReloadFailureGauge...),
-		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
-		entryPointReqsCounter:          multi.NewCounter(entryPointReqsCounter...),
-		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
-		entryPointReqDurationHistogram: NewMultiHistogram(entryPointReqDurationHistogram...),
-		entryPointOpenConnsGauge:       multi.NewGauge(entryPointOpenConnsGauge...),
-		routerReqsCounter:              multi.NewCounter(routerReqsCounter...),
-		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
-		routerReqDurationHistogram:     NewMultiHistogram(routerReqDurationHistogram...),
-		routerOpenConnsGauge:           multi.NewGauge(routerOpenConnsGauge...),
-		serviceReqsCounter:             multi.NewCounter(serviceReqsCounter...),
-		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
-		serviceReqDurationHistogram:    NewMultiHistogram(serviceReqDurationHistogram...),
-		serviceOpenConnsGauge:          multi.NewGauge(serviceOpenConnsGauge...),
-		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
-		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
+		epEnabled:                             len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0 || len(entryPointOpenConnsGauge) > 0,
+		svcEnabled:                            len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceOpenConnsGauge) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
+		routerEnabled:                         len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0 || len(routerOpenConnsGauge) > 0 || len(routerConnsCounter) > 0,
+		configReloadsCounter:                  multi.NewCounter(configReloadsCounter...),
+		configReloadsFailureCounter:           multi.NewCounter(configReloadsFailureCounter...),
+		lastConfigReloadSuccessGauge:          multi.NewGauge(lastConfigReloadSuccessGauge...),
+		lastConfigReloadFailureGauge:          multi.NewGauge(lastConfigReloadFailureGauge...),
+		tlsCertsNotAfterTimestampGauge:        multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		entryPointReqsCounter:                 multi.NewCounter(entryPointReqsCounter...),
+		entryPointReqsTLSCounter:              multi.NewCounter(entryPointReqsTLSCounter...),
+		entryPointReqDurationHistogram:        NewMultiHistogram(entryPointReqDurationHistogram...),
+		entryPointOpenConnsGauge:              multi.NewGauge(entryPointOpenConnsGauge...),
+		entryPointRejectedConnsCounter:        multi.NewCounter(entryPointRejectedConnsCounter...),
+		entryPointSlowReqsCounter:             multi.NewCounter(entryPointSlowReqsCounter...),
+		entryPointUDPSessionsGauge:            multi.NewGauge(entryPointUDPSessionsGauge...),
+		routerReqsCounter:                     multi.NewCounter(routerReqsCounter...),
+		routerReqsTLSCounter:                  multi.NewCounter(routerReqsTLSCounter...),
+		routerReqDurationHistogram:            NewMultiHistogram(routerReqDurationHistogram...),
+		routerOpenConnsGauge:                  multi.NewGauge(routerOpenConnsGauge...),
+		routerConnsCounter:                    multi.NewCounter(routerConnsCounter...),
+		routerConnDurationHistogram:           NewMultiHistogram(routerConnDurationHistogram...),
+		routerConnBytesCounter:                multi.NewCounter(routerConnBytesCounter...),
+		serviceReqsCounter:                    multi.NewCounter(serviceReqsCounter...),
+		serviceReqsTLSCounter:                 multi.NewCounter(serviceReqsTLSCounter...),
+		serviceReqDurationHistogram:           NewMultiHistogram(serviceReqDurationHistogram...),
+		serviceOpenConnsGauge:                 multi.NewGauge(serviceOpenConnsGauge...),
+		serviceRetriesCounter:                 multi.NewCounter(serviceRetriesCounter...),
+		serviceDNSFailuresCounter:             multi.NewCounter(serviceDNSFailuresCounter...),
+		serviceServerUpGauge:                  multi.NewGauge(serviceServerUpGauge...),
+		serviceServerHealthChecksCounter:      multi.NewCounter(serviceServerHealthChecksCounter...),
+		serviceServerHealthCheckFailuresGauge: multi.NewGauge(serviceServerHealthCheckFailuresGauge...),
+		serviceServerHealthCheckDurationGauge: multi.NewGauge(serviceServerHealthCheckDurationGauge...),
+		serviceFailoverGauge:                  multi.NewGauge(serviceFailoverGauge...),
+		middlewareRejectedReqsCounter:         multi.NewCounter(middlewareRejectedReqsCounter...),
+		rulesCacheRequestsCounter:             multi.NewCounter(rulesCacheRequestsCounter...),
+		providerSuppressedReloadsCounter:      multi.NewCounter(providerSuppressedReloadsCounter...),
 	}
 }
 
 type standardRegistry struct {
-	epEnabled                      bool
-	routerEnabled                  bool
-	svcEnabled                     bool
-	configReloadsCounter           metrics.Counter
-	configReloadsFailureCounter    metrics.Counter
-	lastConfigReloadSuccessGauge   metrics.Gauge
-	lastConfigReloadFailureGauge   metrics.Gauge
-	tlsCertsNotAfterTimestampGauge metrics.Gauge
-	entryPointReqsCounter          metrics.Counter
-	entryPointReqsTLSCounter       metrics.Counter
-	entryPointReqDurationHistogram ScalableHistogram
-	entryPointOpenConnsGauge       metrics.Gauge
-	routerReqsCounter              metrics.Counter
-	routerReqsTLSCounter           metrics.Counter
-	routerReqDurationHistogram     ScalableHistogram
-	routerOpenConnsGauge           metrics.Gauge
-	serviceReqsCounter             metrics.Counter
-	serviceReqsTLSCounter          metrics.Counter
-	serviceReqDurationHistogram    ScalableHistogram
-	serviceOpenConnsGauge          metrics.Gauge
-	serviceRetriesCounter          metrics.Counter
-	serviceServerUpGauge           metrics.Gauge
+	epEnabled                             bool
+	routerEnabled                         bool
+	svcEnabled                            bool
+	configReloadsCounter                  metrics.Counter
+	configReloadsFailureCounter           metrics.Counter
+	lastConfigReloadSuccessGauge          metrics.Gauge
+	lastConfigReloadFailureGauge          metrics.Gauge
+	tlsCertsNotAfterTimestampGauge        metrics.Gauge
+	entryPointReqsCounter                 metrics.Counter
+	entryPointReqsTLSCounter              metrics.Counter
+	entryPointReqDurationHistogram        ScalableHistogram
+	entryPointOpenConnsGauge              metrics.Gauge
+	entryPointRejectedConnsCounter        metrics.Counter
+	entryPointSlowReqsCounter             metrics.Counter
+	entryPointUDPSessionsGauge            metrics.Gauge
+	routerReqsCounter                     metrics.Counter
+	routerReqsTLSCounter                  metrics.Counter
+	routerReqDurationHistogram            ScalableHistogram
+	routerOpenConnsGauge                  metrics.Gauge
+	routerConnsCounter                    metrics.Counter
+	routerConnDurationHistogram           ScalableHistogram
+	routerConnBytesCounter                metrics.Counter
+	serviceReqsCounter                    metrics.Counter
+	serviceReqsTLSCounter                 metrics.Counter
+	serviceReqDurationHistogram           ScalableHistogram
+	serviceOpenConnsGauge                 metrics.Gauge
+	serviceRetriesCounter                 metrics.Counter
+	serviceDNSFailuresCounter             metrics.Counter
+	serviceServerUpGauge                  metrics.Gauge
+	serviceServerHealthChecksCounter      metrics.Counter
+	serviceServerHealthCheckFailuresGauge metrics.Gauge
+	serviceServerHealthCheckDurationGauge metrics.Gauge
+	serviceFailoverGauge                  metrics.Gauge
+	middlewareRejectedReqsCounter         metrics.Counter
+	rulesCacheRequestsCounter             metrics.Counter
+	providerSuppressedReloadsCounter      metrics.Counter
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -236,6 +340,18 @@ func (r *standardRegistry) EntryPointOpenConnsGauge() metrics.Gauge {
 	return r.entryPointOpenConnsGauge
 }
 
+func (r *standardRegistry) EntryPointRejectedConnsCounter() metrics.Counter {
+	return r.entryPointRejectedConnsCounter
+}
+
+func (r *standardRegistry) EntryPointSlowReqsCounter() metrics.Counter {
+	return r.entryPointSlowReqsCounter
+}
+
+func (r *standardRegistry) EntryPointUDPSessionsGauge() metrics.Gauge {
+	return r.entryPointUDPSessionsGauge
+}
+
 func (r *standardRegistry) RouterReqsCounter() metrics.Counter {
 	return r.routerReqsCounter
 }
@@ -252,6 +368,18 @@ func (r *standardRegistry) RouterOpenConnsGauge() metrics.Gauge {
 	return r.routerOpenConnsGauge
 }
 
+func (r *standardRegistry) RouterConnsCounter() metrics.Counter {
+	return r.routerConnsCounter
+}
+
+func (r *standardRegistry) RouterConnDurationHistogram() ScalableHistogram {
+	return r.routerConnDurationHistogram
+}
+
+func (r *standardRegistry) RouterConnBytesCounter() metrics.Counter {
+	return r.routerConnBytesCounter
+}
+
 func (r *standardRegistry) ServiceReqsCounter() metrics.Counter {
 	return r.serviceReqsCounter
 }
@@ -272,10 +400,42 @@ func (r *standardRegistry) ServiceRetriesCounter() metrics.Counter {
 	return r.serviceRetriesCounter
 }
 
+func (r *standardRegistry) ServiceDNSFailuresCounter() metrics.Counter {
+	return r.serviceDNSFailuresCounter
+}
+
 func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
 
+func (r *standardRegistry) ServiceServerHealthChecksCounter() metrics.Counter {
+	return r.serviceServerHealthChecksCounter
+}
+
+func (r *standardRegistry) ServiceServerHealthCheckFailuresGauge() metrics.Gauge {
+	return r.serviceServerHealthCheckFailuresGauge
+}
+
+func (r *standardRegistry) ServiceServerHealthCheckDurationGauge() metrics.Gauge {
+	return r.serviceServerHealthCheckDurationGauge
+}
+
+func (r *standardRegistry) ServiceFailoverGauge() metrics.Gauge {
+	return r.serviceFailoverGauge
+}
+
+func (r *standardRegistry) MiddlewareRejectedReqsCounter() metrics.Counter {
+	return r.middlewareRejectedReqsCounter
+}
+
+func (r *standardRegistry) RulesCacheRequestsCounter() metrics.Counter {
+	return r.rulesCacheRequestsCounter
+}
+
+func (r *standardRegistry) ProviderSuppressedReloadsCounter() metrics.Counter {
+	return r.providerSuppressedReloadsCounter
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
@@ -0,0 +1,625 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/version"
+)
+
+var (
+	openTelemetryTicker *time.Ticker
+
+	// openTelemetryExporters are the exporters of all the registered registries, pushed by the openTelemetryTicker.
+	openTelemetryExporters   []*otlpExporter
+	openTelemetryExportersMu sync.Mutex
+)
+
+const (
+	otelConfigReloadsName           = "traefik.config.reloads.total"
+	otelConfigReloadsFailureName    = "traefik.config.reloads.failure.total"
+	otelLastConfigReloadSuccessName = "traefik.config.reload.last.success.timestamp"
+	otelLastConfigReloadFailureName = "traefik.config.reload.last.failure.timestamp"
+
+	otelTLSCertsNotAfterTimestampName = "traefik.tls.certs.not.after.timestamp"
+
+	otelEntryPointReqsName          = "traefik.entrypoint.requests.total"
+	otelEntryPointReqsTLSName       = "traefik.entrypoint.requests.tls.total"
+	otelEntryPointReqDurationName   = "traefik.entrypoint.request.duration"
+	otelEntryPointOpenConnsName     = "traefik.entrypoint.open.connections"
+	otelEntryPointRejectedConnsName = "traefik.entrypoint.rejected.connections.total"
+	otelEntryPointSlowReqsName      = "traefik.entrypoint.slow.requests.total"
+	otelEntryPointUDPSessionsName   = "traefik.entrypoint.udp.sessions"
+
+	otelRouterReqsName        = "traefik.router.requests.total"
+	otelRouterReqsTLSName     = "traefik.router.requests.tls.total"
+	otelRouterReqDurationName = "traefik.router.request.duration"
+	otelRouterOpenConnsName   = "traefik.router.open.connections"
+	otelRouterConnsName       = "traefik.router.connections.total"
+	otelRouterConnDuration    = "traefik.router.connection.duration"
+	otelRouterConnBytesName   = "traefik.router.connection.bytes.total"
+
+	otelServiceReqsName                      = "traefik.service.requests.total"
+	otelServiceReqsTLSName                   = "traefik.service.requests.tls.total"
+	otelServiceReqDurationName               = "traefik.service.request.duration"
+	otelServiceOpenConnsName                 = "traefik.service.open.connections"
+	otelServiceRetriesTotalName              = "traefik.service.retries.total"
+	otelServiceDNSFailuresTotalName          = "traefik.service.dns.failures.total"
+	otelServiceServerUpName                  = "traefik.service.server.up"
+	otelServiceServerHealthChecksName        = "traefik.service.server.healthcheck.total"
+	otelServiceServerHealthCheckFailuresName = "traefik.service.server.healthcheck.consecutive.failures"
+	otelServiceServerHealthCheckDurationName = "traefik.service.server.healthcheck.duration"
+	otelServiceFailoverName                  = "traefik.service.failover"
+
+	// middleware level.
+	otelMiddlewareRejectedReqsName = "traefik.middleware.rejected.requests.total"
+
+	// rules level.
+	otelRulesCacheRequestsName = "traefik.rules.cache.requests.total"
+
+	// provider level.
+	otelProviderSuppressedReloadsName = "traefik.provider.suppressed.reloads.total"
+)
+
+// aggregationTemporalityCumulative is the OTLP value of AGGREGATION_TEMPORALITY_CUMULATIVE.
+const aggregationTemporalityCumulative = 2
+
+// RegisterOpenTelemetry registers the metrics pusher if this didn't happen yet and creates an OpenTelemetry Registry instance.
+// Only the OTLP/HTTP protocol is supported: nil is returned if the address is not an HTTP URL, such as an OTLP/gRPC endpoint.
+func RegisterOpenTelemetry(ctx context.Context, config *types.OpenTelemetry) Registry {
+	if err := validateOTLPAddress(config.Address); err != nil {
+		log.FromContext(ctx).Errorf("Unable to register the OpenTelemetry metrics: %v", err)
+		return nil
+	}
+
+	exporter := newOTLPExporter(config)
+
+	openTelemetryExportersMu.Lock()
+	openTelemetryExporters = append(openTelemetryExporters, exporter)
+	openTelemetryExportersMu.Unlock()
+
+	if openTelemetryTicker == nil {
+		openTelemetryTicker = initOpenTelemetryTicker(ctx, config)
+	}
+
+	buckets := config.ExplicitBoundaries
+
+	registry := &standardRegistry{
+		configReloadsCounter:             exporter.newCounter(otelConfigReloadsName),
+		configReloadsFailureCounter:      exporter.newCounter(otelConfigReloadsFailureName),
+		lastConfigReloadSuccessGauge:     exporter.newGauge(otelLastConfigReloadSuccessName),
+		lastConfigReloadFailureGauge:     exporter.newGauge(otelLastConfigReloadFailureName),
+		tlsCertsNotAfterTimestampGauge:   exporter.newGauge(otelTLSCertsNotAfterTimestampName),
+		middlewareRejectedReqsCounter:    exporter.newCounter(otelMiddlewareRejectedReqsName),
+		rulesCacheRequestsCounter:        exporter.newCounter(otelRulesCacheRequestsName),
+		providerSuppressedReloadsCounter: exporter.newCounter(otelProviderSuppressedReloadsName),
+	}
+
+	if config.AddEntryPointsLabels {
+		registry.epEnabled = config.AddEntryPointsLabels
+		registry.entryPointReqsCounter = exporter.newCounter(otelEntryPointReqsName)
+		registry.entryPointReqsTLSCounter = exporter.newCounter(otelEntryPointReqsTLSName)
+		registry.entryPointReqDurationHistogram, _ = NewHistogramWithScale(exporter.newHistogram(otelEntryPointReqDurationName, buckets), time.Second)
+		registry.entryPointOpenConnsGauge = exporter.newGauge(otelEntryPointOpenConnsName)
+		registry.entryPointRejectedConnsCounter = exporter.newCounter(otelEntryPointRejectedConnsName)
+		registry.entryPointSlowReqsCounter = exporter.newCounter(otelEntryPointSlowReqsName)
+		registry.entryPointUDPSessionsGauge = exporter.newGauge(otelEntryPointUDPSessionsName)
+	}
+
+	if config.AddRoutersLabels {
+		registry.routerEnabled = config.AddRoutersLabels
+		registry.routerReqsCounter = exporter.newCounter(otelRouterReqsName)
+		registry.routerReqsTLSCounter = exporter.newCounter(otelRouterReqsTLSName)
+		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(exporter.newHistogram(otelRouterReqDurationName, buckets), time.Second)
+		registry.routerOpenConnsGauge = exporter.newGauge(otelRouterOpenConnsName)
+		registry.routerConnsCounter = exporter.newCounter(otelRouterConnsName)
+		registry.routerConnDurationHistogram, _ = NewHistogramWithScale(exporter.newHistogram(otelRouterConnDuration, buckets), time.Second)
+		registry.routerConnBytesCounter = exporter.newCounter(otelRouterConnBytesName)
+	}
+
+	if config.AddServicesLabels {
+		registry.svcEnabled = config.AddServicesLabels
+		registry.serviceReqsCounter = exporter.newCounter(otelServiceReqsName)
+		registry.serviceReqsTLSCounter = exporter.newCounter(otelServiceReqsTLSName)
+		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(exporter.newHistogram(otelServiceReqDurationName, buckets), time.Second)
+		registry.serviceRetriesCounter = exporter.newCounter(otelServiceRetriesTotalName)
+		registry.serviceDNSFailuresCounter = exporter.newCounter(otelServiceDNSFailuresTotalName)
+		registry.serviceOpenConnsGauge = exporter.newGauge(otelServiceOpenConnsName)
+		registry.serviceServerUpGauge = exporter.newGauge(otelServiceServerUpName)
+		registry.serviceServerHealthChecksCounter = exporter.newCounter(otelServiceServerHealthChecksName)
+		registry.serviceServerHealthCheckFailuresGauge = exporter.newGauge(otelServiceServerHealthCheckFailuresName)
+		registry.serviceServerHealthCheckDurationGauge = exporter.newGauge(otelServiceServerHealthCheckDurationName)
+		registry.serviceFailoverGauge = exporter.newGauge(otelServiceFailoverName)
+	}
+
+	return registry
+}
+
+// validateOTLPAddress checks that the address is the URL of an OTLP/HTTP endpoint.
+func validateOTLPAddress(address string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid collector address %q: %w", address, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid collector address %q: only the OTLP/HTTP protocol is supported, the address must be an http or https URL", address)
+	}
+
+	return nil
+}
+
+// initOpenTelemetryTicker initializes the metrics pusher, pushing the metrics of all the registered exporters.
+func initOpenTelemetryTicker(ctx context.Context, config *types.OpenTelemetry) *time.Ticker {
+	report := time.NewTicker(time.Duration(config.PushInterval))
+
+	safe.Go(func() {
+		logger := log.FromContext(ctx)
+
+		for {
+			select {
+			case <-report.C:
+				openTelemetryExportersMu.Lock()
+				exporters := openTelemetryExporters
+				openTelemetryExportersMu.Unlock()
+
+				for _, exporter := range exporters {
+					if err := exporter.push(ctx); err != nil {
+						logger.Errorf("Unable to push metrics to the OpenTelemetry collector %s: %v", exporter.address, err)
+					}
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+
+	return report
+}
+
+// StopOpenTelemetry stops the internal openTelemetryTicker which controls the pushing of metrics to the OpenTelemetry collector and resets it to `nil`,
+// along with the registered exporters.
+func StopOpenTelemetry() {
+	if openTelemetryTicker != nil {
+		openTelemetryTicker.Stop()
+	}
+	openTelemetryTicker = nil
+
+	openTelemetryExportersMu.Lock()
+	openTelemetryExporters = nil
+	openTelemetryExportersMu.Unlock()
+}
+
+// otlpExporter holds the metrics and pushes them to an OTLP/HTTP collector, using the JSON encoding.
+type otlpExporter struct {
+	address     string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+	start       time.Time
+
+	mu         sync.RWMutex
+	counters   []*otelSeriesSet
+	gauges     []*otelSeriesSet
+	histograms []*otelSeriesSet
+}
+
+func newOTLPExporter(config *types.OpenTelemetry) *otlpExporter {
+	return &otlpExporter{
+		address:     config.Address,
+		headers:     config.Headers,
+		serviceName: config.ServiceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		start:       time.Now(),
+	}
+}
+
+func (e *otlpExporter) newCounter(name string) *otelCounter {
+	set := newOTelSeriesSet(name, nil)
+
+	e.mu.Lock()
+	e.counters = append(e.counters, set)
+	e.mu.Unlock()
+
+	return &otelCounter{set: set}
+}
+
+func (e *otlpExporter) newGauge(name string) *otelGauge {
+	set := newOTelSeriesSet(name, nil)
+
+	e.mu.Lock()
+	e.gauges = append(e.gauges, set)
+	e.mu.Unlock()
+
+	return &otelGauge{set: set}
+}
+
+func (e *otlpExporter) newHistogram(name string, buckets []float64) *otelHistogram {
+	bounds := make([]float64, len(buckets))
+	copy(bounds, buckets)
+	sort.Float64s(bounds)
+
+	set := newOTelSeriesSet(name, bounds)
+
+	e.mu.Lock()
+	e.histograms = append(e.histograms, set)
+	e.mu.Unlock()
+
+	return &otelHistogram{set: set}
+}
+
+func (e *otlpExporter) push(ctx context.Context) error {
+	body, err := json.Marshal(e.export(time.Now()))
+	if err != nil {
+		return fmt.Errorf("unable to encode metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range e.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
+
+// export builds the OTLP ExportMetricsServiceRequest for the current values of the metrics.
+func (e *otlpExporter) export(now time.Time) otlpRequest {
+	start := strconv.FormatInt(e.start.UnixNano(), 10)
+	end := strconv.FormatInt(now.UnixNano(), 10)
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var otlpMetrics []otlpMetric
+
+	for _, set := range e.counters {
+		points := set.numberDataPoints(start, end)
+		if len(points) == 0 {
+			continue
+		}
+
+		otlpMetrics = append(otlpMetrics, otlpMetric{
+			Name: set.name,
+			Sum: &otlpSum{
+				DataPoints:             points,
+				AggregationTemporality: aggregationTemporalityCumulative,
+				IsMonotonic:            true,
+			},
+		})
+	}
+
+	for _, set := range e.gauges {
+		points := set.numberDataPoints("", end)
+		if len(points) == 0 {
+			continue
+		}
+
+		otlpMetrics = append(otlpMetrics, otlpMetric{
+			Name:  set.name,
+			Gauge: &otlpGauge{DataPoints: points},
+		})
+	}
+
+	for _, set := range e.histograms {
+		points := set.histogramDataPoints(start, end)
+		if len(points) == 0 {
+			continue
+		}
+
+		otlpMetrics = append(otlpMetrics, otlpMetric{
+			Name: set.name,
+			Unit: "s",
+			Histogram: &otlpHistogram{
+				DataPoints:             points,
+				AggregationTemporality: aggregationTemporalityCumulative,
+			},
+		})
+	}
+
+	return otlpRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource: otlpResource{
+				Attributes: []otlpAttribute{
+					newOTLPAttribute("service.name", e.serviceName),
+					newOTLPAttribute("service.version", version.Version),
+				},
+			},
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope:   otlpScope{Name: "traefik", Version: version.Version},
+				Metrics: otlpMetrics,
+			}},
+		}},
+	}
+}
+
+// otelSeriesSet holds all the series (one per set of label values) of a metric.
+type otelSeriesSet struct {
+	name   string
+	bounds []float64
+
+	series sync.Map
+}
+
+func newOTelSeriesSet(name string, bounds []float64) *otelSeriesSet {
+	return &otelSeriesSet{name: name, bounds: bounds}
+}
+
+func (s *otelSeriesSet) get(labelValues []string) *otelSeries {
+	key := strings.Join(labelValues, "\x00")
+
+	if series, ok := s.series.Load(key); ok {
+		return series.(*otelSeries)
+	}
+
+	series, _ := s.series.LoadOrStore(key, &otelSeries{
+		labelValues:  labelValues,
+		bucketCounts: make([]uint64, len(s.bounds)+1),
+	})
+
+	return series.(*otelSeries)
+}
+
+func (s *otelSeriesSet) sorted() []*otelSeries {
+	var all []*otelSeries
+	s.series.Range(func(_, value interface{}) bool {
+		all = append(all, value.(*otelSeries))
+		return true
+	})
+
+	sort.Slice(all, func(i, j int) bool {
+		return strings.Join(all[i].labelValues, ",") < strings.Join(all[j].labelValues, ",")
+	})
+
+	return all
+}
+
+func (s *otelSeriesSet) numberDataPoints(start, end string) []otlpNumberDataPoint {
+	var points []otlpNumberDataPoint
+	for _, series := range s.sorted() {
+		series.mu.Lock()
+		value := series.value
+		series.mu.Unlock()
+
+		points = append(points, otlpNumberDataPoint{
+			Attributes:        otlpAttributes(series.labelValues),
+			StartTimeUnixNano: start,
+			TimeUnixNano:      end,
+			AsDouble:          value,
+		})
+	}
+
+	return points
+}
+
+func (s *otelSeriesSet) histogramDataPoints(start, end string) []otlpHistogramDataPoint {
+	var points []otlpHistogramDataPoint
+	for _, series := range s.sorted() {
+		series.mu.Lock()
+		bucketCounts := make([]string, len(series.bucketCounts))
+		for i, c := range series.bucketCounts {
+			bucketCounts[i] = strconv.FormatUint(c, 10)
+		}
+		point := otlpHistogramDataPoint{
+			Attributes:        otlpAttributes(series.labelValues),
+			StartTimeUnixNano: start,
+			TimeUnixNano:      end,
+			Count:             strconv.FormatUint(series.count, 10),
+			Sum:               series.value,
+			BucketCounts:      bucketCounts,
+			ExplicitBounds:    s.bounds,
+		}
+		series.mu.Unlock()
+
+		points = append(points, point)
+	}
+
+	return points
+}
+
+type otelSeries struct {
+	labelValues []string
+
+	mu           sync.Mutex
+	value        float64
+	count        uint64
+	bucketCounts []uint64
+}
+
+// otelCounter is a go-kit counter for the OpenTelemetry exporter.
+type otelCounter struct {
+	set         *otelSeriesSet
+	labelValues []string
+}
+
+// With returns a new counter with the given label values.
+func (c *otelCounter) With(labelValues ...string) metrics.Counter {
+	return &otelCounter{set: c.set, labelValues: appendLabelValues(c.labelValues, labelValues)}
+}
+
+// Add adds the given delta to the counter.
+func (c *otelCounter) Add(delta float64) {
+	series := c.set.get(c.labelValues)
+
+	series.mu.Lock()
+	series.value += delta
+	series.mu.Unlock()
+}
+
+// otelGauge is a go-kit gauge for the OpenTelemetry exporter.
+type otelGauge struct {
+	set         *otelSeriesSet
+	labelValues []string
+}
+
+// With returns a new gauge with the given label values.
+func (g *otelGauge) With(labelValues ...string) metrics.Gauge {
+	return &otelGauge{set: g.set, labelValues: appendLabelValues(g.labelValues, labelValues)}
+}
+
+// Set sets the given value to the gauge.
+func (g *otelGauge) Set(value float64) {
+	series := g.set.get(g.labelValues)
+
+	series.mu.Lock()
+	series.value = value
+	series.mu.Unlock()
+}
+
+// Add adds the given delta to the gauge.
+func (g *otelGauge) Add(delta float64) {
+	series := g.set.get(g.labelValues)
+
+	series.mu.Lock()
+	series.value += delta
+	series.mu.Unlock()
+}
+
+// otelHistogram is a go-kit histogram for the OpenTelemetry exporter.
+type otelHistogram struct {
+	set         *otelSeriesSet
+	labelValues []string
+}
+
+// With returns a new histogram with the given label values.
+func (h *otelHistogram) With(labelValues ...string) metrics.Histogram {
+	return &otelHistogram{set: h.set, labelValues: appendLabelValues(h.labelValues, labelValues)}
+}
+
+// Observe records a new value into the histogram.
+func (h *otelHistogram) Observe(value float64) {
+	series := h.set.get(h.labelValues)
+
+	// The bucket i holds the values in (bounds[i-1], bounds[i]], the last one holds the values above the last bound.
+	index := sort.SearchFloat64s(h.set.bounds, value)
+
+	series.mu.Lock()
+	series.value += value
+	series.count++
+	series.bucketCounts[index]++
+	series.mu.Unlock()
+}
+
+func appendLabelValues(current, labelValues []string) []string {
+	values := make([]string, 0, len(current)+len(labelValues))
+	values = append(values, current...)
+	return append(values, labelValues...)
+}
+
+func otlpAttributes(labelValues []string) []otlpAttribute {
+	var attributes []otlpAttribute
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		attributes = append(attributes, newOTLPAttribute(labelValues[i], labelValues[i+1]))
+	}
+	return attributes
+}
+
+// The following types are the JSON representation of the OTLP ExportMetricsServiceRequest message,
+// following the Protobuf JSON mapping (64 bits integers are encoded as strings).
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpMetric struct {
+	Name      string         `json:"name"`
+	Unit      string         `json:"unit,omitempty"`
+	Sum       *otlpSum       `json:"sum,omitempty"`
+	Gauge     *otlpGauge     `json:"gauge,omitempty"`
+	Histogram *otlpHistogram `json:"histogram,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                      `json:"aggregationTemporality"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	AsDouble          float64         `json:"asDouble"`
+}
+
+type otlpHistogramDataPoint struct {
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	BucketCounts      []string        `json:"bucketCounts"`
+	ExplicitBounds    []float64       `json:"explicitBounds"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+func newOTLPAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpAnyValue{StringValue: value}}
+}
@@ -0,0 +1,178 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestOpenTelemetry(t *testing.T) {
+	t.Cleanup(func() {
+		StopOpenTelemetry()
+	})
+
+	c := make(chan *otlpRequest, 5)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		request := &otlpRequest{}
+		require.NoError(t, json.Unmarshal(body, request))
+
+		c <- request
+	}))
+	defer ts.Close()
+
+	config := &types.OpenTelemetry{}
+	config.SetDefaults()
+	config.Address = ts.URL
+	config.Headers = map[string]string{"Authorization": "Bearer token"}
+	config.PushInterval = ptypes.Duration(10 * time.Millisecond)
+	config.ExplicitBoundaries = []float64{1, 5}
+	config.AddRoutersLabels = true
+
+	registry := RegisterOpenTelemetry(context.Background(), config)
+
+	if !registry.IsEpEnabled() || !registry.IsRouterEnabled() || !registry.IsSvcEnabled() {
+		t.Errorf("OpenTelemetry registry should return true for IsEnabled(), IsRouterEnabled() and IsSvcEnabled()")
+	}
+
+	registry.ConfigReloadsCounter().Add(1)
+	registry.LastConfigReloadSuccessGauge().Set(42)
+	registry.ServiceReqsCounter().With("service", "test", "code", "200").Add(2)
+	registry.ServiceReqDurationHistogram().With("service", "test").Observe(2)
+	registry.ServiceReqDurationHistogram().With("service", "test").Observe(10)
+
+	var request *otlpRequest
+	select {
+	case request = <-c:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout while waiting for metrics")
+	}
+
+	require.Len(t, request.ResourceMetrics, 1)
+	resourceMetrics := request.ResourceMetrics[0]
+	assert.Contains(t, resourceMetrics.Resource.Attributes, newOTLPAttribute("service.name", "traefik"))
+	require.Len(t, resourceMetrics.ScopeMetrics, 1)
+
+	otlpMetrics := make(map[string]otlpMetric)
+	for _, metric := range resourceMetrics.ScopeMetrics[0].Metrics {
+		otlpMetrics[metric.Name] = metric
+	}
+
+	require.Contains(t, otlpMetrics, otelConfigReloadsName)
+	reloads := otlpMetrics[otelConfigReloadsName]
+	require.NotNil(t, reloads.Sum)
+	assert.True(t, reloads.Sum.IsMonotonic)
+	require.Len(t, reloads.Sum.DataPoints, 1)
+	assert.Equal(t, float64(1), reloads.Sum.DataPoints[0].AsDouble)
+
+	require.Contains(t, otlpMetrics, otelLastConfigReloadSuccessName)
+	lastReload := otlpMetrics[otelLastConfigReloadSuccessName]
+	require.NotNil(t, lastReload.Gauge)
+	require.Len(t, lastReload.Gauge.DataPoints, 1)
+	assert.Equal(t, float64(42), lastReload.Gauge.DataPoints[0].AsDouble)
+
+	require.Contains(t, otlpMetrics, otelServiceReqsName)
+	serviceReqs := otlpMetrics[otelServiceReqsName]
+	require.NotNil(t, serviceReqs.Sum)
+	require.Len(t, serviceReqs.Sum.DataPoints, 1)
+	assert.Equal(t, float64(2), serviceReqs.Sum.DataPoints[0].AsDouble)
+	assert.Equal(t, []otlpAttribute{newOTLPAttribute("service", "test"), newOTLPAttribute("code", "200")}, serviceReqs.Sum.DataPoints[0].Attributes)
+
+	require.Contains(t, otlpMetrics, otelServiceReqDurationName)
+	duration := otlpMetrics[otelServiceReqDurationName]
+	require.NotNil(t, duration.Histogram)
+	require.Len(t, duration.Histogram.DataPoints, 1)
+	assert.Equal(t, "2", duration.Histogram.DataPoints[0].Count)
+	assert.Equal(t, float64(12), duration.Histogram.DataPoints[0].Sum)
+	assert.Equal(t, []string{"0", "1", "1"}, duration.Histogram.DataPoints[0].BucketCounts)
+	assert.Equal(t, []float64{1, 5}, duration.Histogram.DataPoints[0].ExplicitBounds)
+}
+
+func TestOpenTelemetry_severalRegistries(t *testing.T) {
+	t.Cleanup(func() {
+		StopOpenTelemetry()
+	})
+
+	var servers []chan struct{}
+	var configs []*types.OpenTelemetry
+	for i := 0; i < 2; i++ {
+		c := make(chan struct{}, 5)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case c <- struct{}{}:
+			default:
+			}
+		}))
+		defer ts.Close()
+
+		config := &types.OpenTelemetry{}
+		config.SetDefaults()
+		config.Address = ts.URL
+		config.PushInterval = ptypes.Duration(10 * time.Millisecond)
+
+		servers = append(servers, c)
+		configs = append(configs, config)
+	}
+
+	for _, config := range configs {
+		require.NotNil(t, RegisterOpenTelemetry(context.Background(), config))
+	}
+
+	for i, c := range servers {
+		select {
+		case <-c:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout while waiting for the metrics of the registry %d", i)
+		}
+	}
+}
+
+func TestRegisterOpenTelemetry_unsupportedAddress(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		address string
+	}{
+		{
+			desc:    "OTLP/gRPC endpoint",
+			address: "localhost:4317",
+		},
+		{
+			desc:    "gRPC scheme",
+			address: "grpc://localhost:4317",
+		},
+		{
+			desc:    "no host",
+			address: "http:///v1/metrics",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Cleanup(func() {
+				StopOpenTelemetry()
+			})
+
+			config := &types.OpenTelemetry{}
+			config.SetDefaults()
+			config.Address = test.address
+
+			assert.Nil(t, RegisterOpenTelemetry(context.Background(), config))
+		})
+	}
+}
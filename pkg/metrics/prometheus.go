@@ -34,11 +34,14 @@ const (
 	tlsCertsNotAfterTimestamp = metricsTLSPrefix + "certs_not_after"
 
 	// entry point.
-	metricEntryPointPrefix     = MetricNamePrefix + "entrypoint_"
-	entryPointReqsTotalName    = metricEntryPointPrefix + "requests_total"
-	entryPointReqsTLSTotalName = metricEntryPointPrefix + "requests_tls_total"
-	entryPointReqDurationName  = metricEntryPointPrefix + "request_duration_seconds"
-	entryPointOpenConnsName    = metricEntryPointPrefix + "open_connections"
+	metricEntryPointPrefix      = MetricNamePrefix + "entrypoint_"
+	entryPointReqsTotalName     = metricEntryPointPrefix + "requests_total"
+	entryPointReqsTLSTotalName  = metricEntryPointPrefix + "requests_tls_total"
+	entryPointReqDurationName   = metricEntryPointPrefix + "request_duration_seconds"
+	entryPointOpenConnsName     = metricEntryPointPrefix + "open_connections"
+	entryPointRejectedConnsName = metricEntryPointPrefix + "rejected_connections_total"
+	entryPointUDPSessionsName   = metricEntryPointPrefix + "udp_sessions"
+	entryPointSlowReqsName      = metricEntryPointPrefix + "slow_requests_total"
 
 	// router level.
 	metricRouterPrefix     = MetricNamePrefix + "router_"
@@ -46,15 +49,35 @@ const (
 	routerReqsTLSTotalName = metricRouterPrefix + "requests_tls_total"
 	routerReqDurationName  = metricRouterPrefix + "request_duration_seconds"
 	routerOpenConnsName    = metricRouterPrefix + "open_connections"
+	routerConnsTotalName   = metricRouterPrefix + "connections_total"
+	routerConnDurationName = metricRouterPrefix + "connection_duration_seconds"
+	routerConnBytesName    = metricRouterPrefix + "connection_bytes_total"
 
 	// service level.
-	metricServicePrefix     = MetricNamePrefix + "service_"
-	serviceReqsTotalName    = metricServicePrefix + "requests_total"
-	serviceReqsTLSTotalName = metricServicePrefix + "requests_tls_total"
-	serviceReqDurationName  = metricServicePrefix + "request_duration_seconds"
-	serviceOpenConnsName    = metricServicePrefix + "open_connections"
-	serviceRetriesTotalName = metricServicePrefix + "retries_total"
-	serviceServerUpName     = metricServicePrefix + "server_up"
+	metricServicePrefix                             = MetricNamePrefix + "service_"
+	serviceReqsTotalName                            = metricServicePrefix + "requests_total"
+	serviceReqsTLSTotalName                         = metricServicePrefix + "requests_tls_total"
+	serviceReqDurationName                          = metricServicePrefix + "request_duration_seconds"
+	serviceOpenConnsName                            = metricServicePrefix + "open_connections"
+	serviceRetriesTotalName                         = metricServicePrefix + "retries_total"
+	serviceDNSFailuresTotalName                     = metricServicePrefix + "dns_failures_total"
+	serviceServerUpName                             = metricServicePrefix + "server_up"
+	serviceServerHealthChecksTotalName              = metricServicePrefix + "server_health_checks_total"
+	serviceServerHealthCheckConsecutiveFailuresName = metricServicePrefix + "server_health_check_consecutive_failures"
+	serviceServerHealthCheckDurationName            = metricServicePrefix + "server_health_check_duration_seconds"
+	serviceFailoverName                             = metricServicePrefix + "failover"
+
+	// middleware level.
+	metricMiddlewarePrefix          = MetricNamePrefix + "middleware_"
+	middlewareRejectedReqsTotalName = metricMiddlewarePrefix + "rejected_requests_total"
+
+	// rules level.
+	metricRulesPrefix           = MetricNamePrefix + "rules_"
+	rulesCacheRequestsTotalName = metricRulesPrefix + "cache_requests_total"
+
+	// provider level.
+	metricProviderPrefix               = MetricNamePrefix + "provider_"
+	providerSuppressedReloadsTotalName = metricProviderPrefix + "suppressed_reloads_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -79,9 +102,11 @@ func PrometheusHandler() http.Handler {
 }
 
 // RegisterPrometheus registers all Prometheus metrics.
+// The operation label is added to the requests metrics of the routers and services if addOperationLabel is true,
+// which must be the case when the requests are classified into operations.
 // It must be called only once and failing to register the metrics will lead to a panic.
-func RegisterPrometheus(ctx context.Context, config *types.Prometheus) Registry {
-	standardRegistry := initStandardRegistry(config)
+func RegisterPrometheus(ctx context.Context, config *types.Prometheus, addOperationLabel bool) Registry {
+	standardRegistry := initStandardRegistry(config, addOperationLabel)
 
 	if err := promRegistry.Register(stdprometheus.NewProcessCollector(stdprometheus.ProcessCollectorOpts{})); err != nil {
 		var arErr stdprometheus.AlreadyRegisteredError
@@ -104,12 +129,19 @@ func RegisterPrometheus(ctx context.Context, config *types.Prometheus) Registry
 	return standardRegistry
 }
 
-func initStandardRegistry(config *types.Prometheus) Registry {
+func initStandardRegistry(config *types.Prometheus, addOperationLabel bool) Registry {
 	buckets := []float64{0.1, 0.3, 1.2, 5.0}
 	if config.Buckets != nil {
 		buckets = config.Buckets
 	}
 
+	routerReqsLabels := []string{"code", "method", "protocol", "router", "service"}
+	serviceReqsLabels := []string{"code", "method", "protocol", "service"}
+	if addOperationLabel {
+		routerReqsLabels = append(routerReqsLabels, "operation")
+		serviceReqsLabels = append(serviceReqsLabels, "operation")
+	}
+
 	safe.Go(func() {
 		promState.ListenValueUpdates()
 	})
@@ -134,6 +166,18 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestamp,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	middlewareRejectedReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: middlewareRejectedReqsTotalName,
+		Help: "How many requests were rejected by a middleware, partitioned by reason.",
+	}, []string{"middleware", "reason"})
+	rulesCacheRequests := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: rulesCacheRequestsTotalName,
+		Help: "How many requests were made to the rules caches, partitioned by cache and result.",
+	}, []string{"cache", "result"})
+	providerSuppressedReloads := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+		Name: providerSuppressedReloadsTotalName,
+		Help: "How many configuration reloads of a provider were suppressed by coalescing its changes, partitioned by provider.",
+	}, []string{"provider"})
 
 	promState.describers = []func(chan<- *stdprometheus.Desc){
 		configReloads.cv.Describe,
@@ -141,17 +185,23 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		lastConfigReloadSuccess.gv.Describe,
 		lastConfigReloadFailure.gv.Describe,
 		tlsCertsNotAfterTimesptamp.gv.Describe,
+		middlewareRejectedReqs.cv.Describe,
+		rulesCacheRequests.cv.Describe,
+		providerSuppressedReloads.cv.Describe,
 	}
 
 	reg := &standardRegistry{
-		epEnabled:                      config.AddEntryPointsLabels,
-		routerEnabled:                  config.AddRoutersLabels,
-		svcEnabled:                     config.AddServicesLabels,
-		configReloadsCounter:           configReloads,
-		configReloadsFailureCounter:    configReloadsFailures,
-		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
-		lastConfigReloadFailureGauge:   lastConfigReloadFailure,
-		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimesptamp,
+		epEnabled:                        config.AddEntryPointsLabels,
+		routerEnabled:                    config.AddRoutersLabels,
+		svcEnabled:                       config.AddServicesLabels,
+		configReloadsCounter:             configReloads,
+		configReloadsFailureCounter:      configReloadsFailures,
+		lastConfigReloadSuccessGauge:     lastConfigReloadSuccess,
+		lastConfigReloadFailureGauge:     lastConfigReloadFailure,
+		tlsCertsNotAfterTimestampGauge:   tlsCertsNotAfterTimesptamp,
+		middlewareRejectedReqsCounter:    middlewareRejectedReqs,
+		rulesCacheRequestsCounter:        rulesCacheRequests,
+		providerSuppressedReloadsCounter: providerSuppressedReloads,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -172,25 +222,43 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: entryPointOpenConnsName,
 			Help: "How many open connections exist on an entrypoint, partitioned by method and protocol.",
 		}, []string{"method", "protocol", "entrypoint"})
+		entryPointRejectedConns := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointRejectedConnsName,
+			Help: "How many connections were rejected on an entrypoint, partitioned by reason.",
+		}, []string{"entrypoint", "reason"})
+		entryPointSlowReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: entryPointSlowReqsName,
+			Help: "How many requests received on an entrypoint were in flight for longer than the slow requests threshold, partitioned by router, service, and state.",
+		}, []string{"entrypoint", "router", "service", "state"})
+		entryPointUDPSessions := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: entryPointUDPSessionsName,
+			Help: "How many UDP sessions are active on an entrypoint.",
+		}, []string{"entrypoint"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			entryPointReqs.cv.Describe,
 			entryPointReqsTLS.cv.Describe,
 			entryPointReqDurations.hv.Describe,
 			entryPointOpenConns.gv.Describe,
+			entryPointRejectedConns.cv.Describe,
+			entryPointSlowReqs.cv.Describe,
+			entryPointUDPSessions.gv.Describe,
 		}...)
 
 		reg.entryPointReqsCounter = entryPointReqs
 		reg.entryPointReqsTLSCounter = entryPointReqsTLS
 		reg.entryPointReqDurationHistogram, _ = NewHistogramWithScale(entryPointReqDurations, time.Second)
 		reg.entryPointOpenConnsGauge = entryPointOpenConns
+		reg.entryPointRejectedConnsCounter = entryPointRejectedConns
+		reg.entryPointSlowReqsCounter = entryPointSlowReqs
+		reg.entryPointUDPSessionsGauge = entryPointUDPSessions
 	}
 
 	if config.AddRoutersLabels {
 		routerReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: routerReqsTotalName,
 			Help: "How many HTTP requests are processed on a router, partitioned by service, status code, protocol, and method.",
-		}, []string{"code", "method", "protocol", "router", "service"})
+		}, routerReqsLabels)
 		routerReqsTLS := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: routerReqsTLSTotalName,
 			Help: "How many HTTP requests with TLS are processed on a router, partitioned by service, TLS Version, and TLS cipher Used.",
@@ -199,29 +267,48 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name:    routerReqDurationName,
 			Help:    "How long it took to process the request on a router, partitioned by service, status code, protocol, and method.",
 			Buckets: buckets,
-		}, []string{"code", "method", "protocol", "router", "service"})
+		}, routerReqsLabels)
 		routerOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 			Name: routerOpenConnsName,
 			Help: "How many open connections exist on a router, partitioned by service, method, and protocol.",
 		}, []string{"method", "protocol", "router", "service"})
+		routerConns := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: routerConnsTotalName,
+			Help: "How many TCP connections and UDP sessions are closed on a router, partitioned by service and protocol.",
+		}, []string{"protocol", "router", "service"})
+		routerConnDurations := newHistogramFrom(promState.collectors, stdprometheus.HistogramOpts{
+			Name:    routerConnDurationName,
+			Help:    "How long the TCP connections and UDP sessions lasted on a router, partitioned by service and protocol.",
+			Buckets: buckets,
+		}, []string{"protocol", "router", "service"})
+		routerConnBytes := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: routerConnBytesName,
+			Help: "How many bytes the closed TCP connections and UDP sessions transferred on a router, partitioned by service, protocol, and direction.",
+		}, []string{"direction", "protocol", "router", "service"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			routerReqs.cv.Describe,
 			routerReqsTLS.cv.Describe,
 			routerReqDurations.hv.Describe,
 			routerOpenConns.gv.Describe,
+			routerConns.cv.Describe,
+			routerConnDurations.hv.Describe,
+			routerConnBytes.cv.Describe,
 		}...)
 		reg.routerReqsCounter = routerReqs
 		reg.routerReqsTLSCounter = routerReqsTLS
 		reg.routerReqDurationHistogram, _ = NewHistogramWithScale(routerReqDurations, time.Second)
 		reg.routerOpenConnsGauge = routerOpenConns
+		reg.routerConnsCounter = routerConns
+		reg.routerConnDurationHistogram, _ = NewHistogramWithScale(routerConnDurations, time.Second)
+		reg.routerConnBytesCounter = routerConnBytes
 	}
 
 	if config.AddServicesLabels {
 		serviceReqs := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: serviceReqsTotalName,
 			Help: "How many HTTP requests processed on a service, partitioned by status code, protocol, and method.",
-		}, []string{"code", "method", "protocol", "service"})
+		}, serviceReqsLabels)
 		serviceReqsTLS := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
 			Name: serviceReqsTLSTotalName,
 			Help: "How many HTTP requests with TLS processed on a service, partitioned by TLS version and TLS cipher.",
@@ -230,7 +317,7 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name:    serviceReqDurationName,
 			Help:    "How long it took to process the request on a service, partitioned by status code, protocol, and method.",
 			Buckets: buckets,
-		}, []string{"code", "method", "protocol", "service"})
+		}, serviceReqsLabels)
 		serviceOpenConns := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 			Name: serviceOpenConnsName,
 			Help: "How many open connections exist on a service, partitioned by method and protocol.",
@@ -239,10 +326,30 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceRetriesTotalName,
 			Help: "How many request retries happened on a service.",
 		}, []string{"service"})
+		serviceDNSFailures := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceDNSFailuresTotalName,
+			Help: "How many requests failed on a service because a server hostname could not be resolved.",
+		}, []string{"service"})
 		serviceServerUp := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
 		}, []string{"service", "url"})
+		serviceServerHealthChecks := newCounterFrom(promState.collectors, stdprometheus.CounterOpts{
+			Name: serviceServerHealthChecksTotalName,
+			Help: "How many health checks of a service server passed or failed, partitioned by result.",
+		}, []string{"service", "url", "result"})
+		serviceServerHealthCheckFailures := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceServerHealthCheckConsecutiveFailuresName,
+			Help: "How many consecutive health checks of a service server failed.",
+		}, []string{"service", "url"})
+		serviceServerHealthCheckDuration := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceServerHealthCheckDurationName,
+			Help: "How long the last health check of a service server took.",
+		}, []string{"service", "url"})
+		serviceFailover := newGaugeFrom(promState.collectors, stdprometheus.GaugeOpts{
+			Name: serviceFailoverName,
+			Help: "failover service has switched the traffic to its fallback service, described by gauge value of 0 or 1.",
+		}, []string{"service"})
 
 		promState.describers = append(promState.describers, []func(chan<- *stdprometheus.Desc){
 			serviceReqs.cv.Describe,
@@ -250,7 +357,12 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceReqDurations.hv.Describe,
 			serviceOpenConns.gv.Describe,
 			serviceRetries.cv.Describe,
+			serviceDNSFailures.cv.Describe,
 			serviceServerUp.gv.Describe,
+			serviceServerHealthChecks.cv.Describe,
+			serviceServerHealthCheckFailures.gv.Describe,
+			serviceServerHealthCheckDuration.gv.Describe,
+			serviceFailover.gv.Describe,
 		}...)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -258,7 +370,12 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceReqDurationHistogram, _ = NewHistogramWithScale(serviceReqDurations, time.Second)
 		reg.serviceOpenConnsGauge = serviceOpenConns
 		reg.serviceRetriesCounter = serviceRetries
+		reg.serviceDNSFailuresCounter = serviceDNSFailures
 		reg.serviceServerUpGauge = serviceServerUp
+		reg.serviceServerHealthChecksCounter = serviceServerHealthChecks
+		reg.serviceServerHealthCheckFailuresGauge = serviceServerHealthCheckFailures
+		reg.serviceServerHealthCheckDurationGauge = serviceServerHealthCheckDuration
+		reg.serviceFailoverGauge = serviceFailover
 	}
 
 	return reg
@@ -305,6 +422,31 @@ func OnConfigurationUpdate(conf dynamic.Configuration, entryPoints []string) {
 		}
 	}
 
+	// The TCP and UDP routers and services are labels of the connections metrics.
+	if conf.TCP != nil {
+		for name := range conf.TCP.Routers {
+			dynamicConfig.routers[name] = true
+		}
+
+		for serviceName := range conf.TCP.Services {
+			if _, ok := dynamicConfig.services[serviceName]; !ok {
+				dynamicConfig.services[serviceName] = make(map[string]bool)
+			}
+		}
+	}
+
+	if conf.UDP != nil {
+		for name := range conf.UDP.Routers {
+			dynamicConfig.routers[name] = true
+		}
+
+		for serviceName := range conf.UDP.Services {
+			if _, ok := dynamicConfig.services[serviceName]; !ok {
+				dynamicConfig.services[serviceName] = make(map[string]bool)
+			}
+		}
+	}
+
 	promState.SetDynamicConfig(dynamicConfig)
 }
 
@@ -70,7 +70,7 @@ func TestRegisterPromState(t *testing.T) {
 			actualNbRegistries := 0
 			for _, prom := range test.prometheusSlice {
 				if test.initPromState {
-					initStandardRegistry(prom)
+					initStandardRegistry(prom, false)
 				}
 				if registerPromState(context.Background()) {
 					actualNbRegistries++
@@ -104,7 +104,7 @@ func TestPrometheus(t *testing.T) {
 	// Reset state of global promState.
 	defer promState.reset()
 
-	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddRoutersLabels: true, AddServicesLabels: true})
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddRoutersLabels: true, AddServicesLabels: true}, false)
 	defer promRegistry.Unregister(promState)
 
 	if !prometheusRegistry.IsEpEnabled() || !prometheusRegistry.IsRouterEnabled() || !prometheusRegistry.IsSvcEnabled() {
@@ -133,6 +133,18 @@ func TestPrometheus(t *testing.T) {
 		EntryPointOpenConnsGauge().
 		With("method", http.MethodGet, "protocol", "http", "entrypoint", "http").
 		Set(1)
+	prometheusRegistry.
+		EntryPointRejectedConnsCounter().
+		With("entrypoint", "http", "reason", "rate_limit").
+		Add(1)
+	prometheusRegistry.
+		EntryPointSlowReqsCounter().
+		With("entrypoint", "http", "router", "demo", "service", "service1", "state", "waiting_backend").
+		Add(1)
+	prometheusRegistry.
+		EntryPointUDPSessionsGauge().
+		With("entrypoint", "udp").
+		Set(1)
 
 	prometheusRegistry.
 		RouterReqsCounter().
@@ -150,6 +162,18 @@ func TestPrometheus(t *testing.T) {
 		RouterOpenConnsGauge().
 		With("router", "demo", "service", "service1", "method", http.MethodGet, "protocol", "http").
 		Set(1)
+	prometheusRegistry.
+		RouterConnsCounter().
+		With("router", "demo", "service", "service1", "protocol", "tcp").
+		Add(1)
+	prometheusRegistry.
+		RouterConnDurationHistogram().
+		With("router", "demo", "service", "service1", "protocol", "tcp").
+		Observe(10)
+	prometheusRegistry.
+		RouterConnBytesCounter().
+		With("router", "demo", "service", "service1", "protocol", "tcp", "direction", "in").
+		Add(512)
 
 	prometheusRegistry.
 		ServiceReqsCounter().
@@ -171,10 +195,45 @@ func TestPrometheus(t *testing.T) {
 		ServiceRetriesCounter().
 		With("service", "service1").
 		Add(1)
+	prometheusRegistry.
+		ServiceDNSFailuresCounter().
+		With("service", "service1").
+		Add(1)
 	prometheusRegistry.
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
 		Set(1)
+	prometheusRegistry.
+		ServiceServerHealthChecksCounter().
+		With("service", "service1", "url", "http://127.0.0.10:80", "result", "fail").
+		Add(1)
+	prometheusRegistry.
+		ServiceServerHealthCheckFailuresGauge().
+		With("service", "service1", "url", "http://127.0.0.10:80").
+		Set(3)
+	prometheusRegistry.
+		ServiceServerHealthCheckDurationGauge().
+		With("service", "service1", "url", "http://127.0.0.10:80").
+		Set(2)
+	prometheusRegistry.
+		ServiceFailoverGauge().
+		With("service", "service1").
+		Set(1)
+
+	prometheusRegistry.
+		MiddlewareRejectedReqsCounter().
+		With("middleware", "limit@file", "reason", "max_count").
+		Add(1)
+
+	prometheusRegistry.
+		RulesCacheRequestsCounter().
+		With("cache", "rule", "result", "hit").
+		Add(1)
+
+	prometheusRegistry.
+		ProviderSuppressedReloadsCounter().
+		With("provider", "consul").
+		Add(1)
 
 	delayForTrackingCompletion()
 
@@ -239,6 +298,31 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, entryPointOpenConnsName, 1),
 		},
+		{
+			name: entryPointRejectedConnsName,
+			labels: map[string]string{
+				"entrypoint": "http",
+				"reason":     "rate_limit",
+			},
+			assert: buildCounterAssert(t, entryPointRejectedConnsName, 1),
+		},
+		{
+			name: entryPointSlowReqsName,
+			labels: map[string]string{
+				"entrypoint": "http",
+				"router":     "demo",
+				"service":    "service1",
+				"state":      "waiting_backend",
+			},
+			assert: buildCounterAssert(t, entryPointSlowReqsName, 1),
+		},
+		{
+			name: entryPointUDPSessionsName,
+			labels: map[string]string{
+				"entrypoint": "udp",
+			},
+			assert: buildGaugeAssert(t, entryPointUDPSessionsName, 1),
+		},
 		{
 			name: routerReqsTotalName,
 			labels: map[string]string{
@@ -281,6 +365,34 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, routerOpenConnsName, 1),
 		},
+		{
+			name: routerConnsTotalName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+				"router":   "demo",
+			},
+			assert: buildCounterAssert(t, routerConnsTotalName, 1),
+		},
+		{
+			name: routerConnDurationName,
+			labels: map[string]string{
+				"protocol": "tcp",
+				"service":  "service1",
+				"router":   "demo",
+			},
+			assert: buildHistogramAssert(t, routerConnDurationName, 1),
+		},
+		{
+			name: routerConnBytesName,
+			labels: map[string]string{
+				"direction": "in",
+				"protocol":  "tcp",
+				"service":   "service1",
+				"router":    "demo",
+			},
+			assert: buildCounterAssert(t, routerConnBytesName, 512),
+		},
 		{
 			name: serviceReqsTotalName,
 			labels: map[string]string{
@@ -326,6 +438,13 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGreaterThanCounterAssert(t, serviceRetriesTotalName, 1),
 		},
+		{
+			name: serviceDNSFailuresTotalName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildCounterAssert(t, serviceDNSFailuresTotalName, 1),
+		},
 		{
 			name: serviceServerUpName,
 			labels: map[string]string{
@@ -334,6 +453,61 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGaugeAssert(t, serviceServerUpName, 1),
 		},
+		{
+			name: serviceServerHealthChecksTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"url":     "http://127.0.0.10:80",
+				"result":  "fail",
+			},
+			assert: buildCounterAssert(t, serviceServerHealthChecksTotalName, 1),
+		},
+		{
+			name: serviceServerHealthCheckConsecutiveFailuresName,
+			labels: map[string]string{
+				"service": "service1",
+				"url":     "http://127.0.0.10:80",
+			},
+			assert: buildGaugeAssert(t, serviceServerHealthCheckConsecutiveFailuresName, 3),
+		},
+		{
+			name: serviceServerHealthCheckDurationName,
+			labels: map[string]string{
+				"service": "service1",
+				"url":     "http://127.0.0.10:80",
+			},
+			assert: buildGaugeAssert(t, serviceServerHealthCheckDurationName, 2),
+		},
+		{
+			name: serviceFailoverName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildGaugeAssert(t, serviceFailoverName, 1),
+		},
+		{
+			name: middlewareRejectedReqsTotalName,
+			labels: map[string]string{
+				"middleware": "limit@file",
+				"reason":     "max_count",
+			},
+			assert: buildCounterAssert(t, middlewareRejectedReqsTotalName, 1),
+		},
+		{
+			name: rulesCacheRequestsTotalName,
+			labels: map[string]string{
+				"cache":  "rule",
+				"result": "hit",
+			},
+			assert: buildCounterAssert(t, rulesCacheRequestsTotalName, 1),
+		},
+		{
+			name: providerSuppressedReloadsTotalName,
+			labels: map[string]string{
+				"provider": "consul",
+			},
+			assert: buildCounterAssert(t, providerSuppressedReloadsTotalName, 1),
+		},
 	}
 
 	for _, test := range testCases {
@@ -364,7 +538,7 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 	// Reset state of global promState.
 	defer promState.reset()
 
-	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true})
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true}, false)
 	defer promRegistry.Unregister(promState)
 
 	conf := dynamic.Configuration{
@@ -420,11 +594,60 @@ func TestPrometheusMetricRemoval(t *testing.T) {
 	assertMetricsExist(t, mustScrape(), entryPointReqsTotalName)
 }
 
+func TestPrometheusMetricRemoval_tcpAndUDP(t *testing.T) {
+	promState = newPrometheusState()
+	promRegistry = prometheus.NewRegistry()
+	// Reset state of global promState.
+	defer promState.reset()
+
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddRoutersLabels: true}, false)
+	defer promRegistry.Unregister(promState)
+
+	conf := dynamic.Configuration{
+		HTTP: th.BuildConfiguration(),
+		TCP: &dynamic.TCPConfiguration{
+			Routers:  map[string]*dynamic.TCPRouter{"tcp@file": {Service: "db@file"}},
+			Services: map[string]*dynamic.TCPService{"db@file": {}},
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  map[string]*dynamic.UDPRouter{"udp@file": {Service: "dns@file"}},
+			Services: map[string]*dynamic.UDPService{"dns@file": {}},
+		},
+	}
+
+	OnConfigurationUpdate(conf, nil)
+
+	prometheusRegistry.
+		RouterConnsCounter().
+		With("router", "old@file", "service", "old@file", "protocol", "tcp").
+		Add(1)
+
+	delayForTrackingCompletion()
+
+	assertMetricsExist(t, mustScrape(), routerConnsTotalName)
+	assertMetricsAbsent(t, mustScrape(), routerConnsTotalName)
+
+	// The metrics of the TCP and UDP services of the active configuration are kept.
+	prometheusRegistry.
+		RouterConnsCounter().
+		With("router", "tcp@file", "service", "db@file", "protocol", "tcp").
+		Add(1)
+	prometheusRegistry.
+		RouterConnsCounter().
+		With("router", "udp@file", "service", "dns@file", "protocol", "udp").
+		Add(1)
+
+	delayForTrackingCompletion()
+
+	assertMetricsExist(t, mustScrape(), routerConnsTotalName)
+	assertCounterValue(t, 1, findMetricFamily(routerConnsTotalName, mustScrape()), "router", "udp@file", "service", "dns@file", "protocol", "udp")
+}
+
 func TestPrometheusRemovedMetricsReset(t *testing.T) {
 	// Reset state of global promState.
 	defer promState.reset()
 
-	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true})
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddEntryPointsLabels: true, AddServicesLabels: true}, false)
 	defer promRegistry.Unregister(promState)
 
 	labelNamesValues := []string{
@@ -458,6 +681,31 @@ func TestPrometheusRemovedMetricsReset(t *testing.T) {
 	assertCounterValue(t, 1, findMetricFamily(serviceReqsTotalName, metricsFamilies), labelNamesValues...)
 }
 
+func TestPrometheusOperationLabel(t *testing.T) {
+	promState = newPrometheusState()
+	promRegistry = prometheus.NewRegistry()
+	// Reset state of global promState.
+	defer promState.reset()
+
+	prometheusRegistry := RegisterPrometheus(context.Background(), &types.Prometheus{AddRoutersLabels: true, AddServicesLabels: true}, true)
+	defer promRegistry.Unregister(promState)
+
+	prometheusRegistry.
+		RouterReqsCounter().
+		With("router", "demo", "service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "operation", "users.get").
+		Add(1)
+	prometheusRegistry.
+		ServiceReqsCounter().
+		With("service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http", "operation", "users.get").
+		Add(1)
+
+	delayForTrackingCompletion()
+
+	metricsFamilies := mustScrape()
+	assertCounterValue(t, 1, findMetricFamily(routerReqsTotalName, metricsFamilies), "router", "demo", "operation", "users.get")
+	assertCounterValue(t, 1, findMetricFamily(serviceReqsTotalName, metricsFamilies), "service", "service1", "operation", "users.get")
+}
+
 // Tracking and gathering the metrics happens concurrently.
 // In practice this is no problem, because in case a tracked metric would miss
 // the current scrape, it would just be there in the next one.
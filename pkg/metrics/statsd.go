@@ -34,12 +34,15 @@ const (
 	statsdRouterReqsDurationName = "router.request.duration"
 	statsdRouterOpenConnsName    = "router.connections.open"
 
-	statsdServiceReqsName         = "service.request.total"
-	statsdServiceReqsTLSName      = "service.request.tls.total"
-	statsdServiceReqsDurationName = "service.request.duration"
-	statsdServiceRetriesTotalName = "service.retries.total"
-	statsdServiceServerUpName     = "service.server.up"
-	statsdServiceOpenConnsName    = "service.connections.open"
+	statsdServiceReqsName                      = "service.request.total"
+	statsdServiceReqsTLSName                   = "service.request.tls.total"
+	statsdServiceReqsDurationName              = "service.request.duration"
+	statsdServiceRetriesTotalName              = "service.retries.total"
+	statsdServiceServerUpName                  = "service.server.up"
+	statsdServiceServerHealthChecksName        = "service.server.healthcheck.total"
+	statsdServiceServerHealthCheckFailuresName = "service.server.healthcheck.consecutive.failures"
+	statsdServiceServerHealthCheckDurationName = "service.server.healthcheck.duration"
+	statsdServiceOpenConnsName                 = "service.connections.open"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -90,6 +93,9 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdServiceRetriesTotalName, 1.0)
 		registry.serviceOpenConnsGauge = statsdClient.NewGauge(statsdServiceOpenConnsName)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServiceServerUpName)
+		registry.serviceServerHealthChecksCounter = statsdClient.NewCounter(statsdServiceServerHealthChecksName, 1.0)
+		registry.serviceServerHealthCheckFailuresGauge = statsdClient.NewGauge(statsdServiceServerHealthCheckFailuresName)
+		registry.serviceServerHealthCheckDurationGauge = statsdClient.NewGauge(statsdServiceServerHealthCheckDurationName)
 	}
 
 	return registry
@@ -0,0 +1,281 @@
+package metrics
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+// trafficWindow is the rolling window over which the requests are counted by the TrafficRegistry.
+const trafficWindow = time.Minute
+
+// TrafficSnapshot is the live traffic recorded by a TrafficRegistry.
+type TrafficSnapshot struct {
+	// Window is the duration over which the requests are counted,
+	// which is shorter than the rolling window until it has elapsed since the start of Traefik.
+	Window time.Duration
+	// EntryPointOpenConns are the current open connections of the entry points, by entry point name.
+	EntryPointOpenConns map[string]float64
+	// EntryPointRequests are the requests of the entry points over the window, by entry point name.
+	EntryPointRequests map[string]float64
+	// RouterRequests are the requests of the HTTP routers over the window, by router name.
+	RouterRequests map[string]float64
+}
+
+// TrafficRegistry is an in-memory registry of the entry points and routers metrics,
+// which keeps the requests over a rolling window and the current open connections,
+// to give the live traffic of Traefik without an external metrics backend.
+type TrafficRegistry struct {
+	*standardRegistry
+
+	window  time.Duration
+	startAt time.Time
+	now     func() time.Time
+
+	mu                  sync.Mutex
+	entryPointReqs      map[string]*rollingCounter
+	entryPointOpenConns map[string]map[string]float64
+	routerReqs          map[string]*rollingCounter
+}
+
+// RegisterTraffic registers the metrics of the live traffic.
+func RegisterTraffic() *TrafficRegistry {
+	standardRegistry := &standardRegistry{
+		epEnabled:     true,
+		routerEnabled: true,
+	}
+
+	tr := &TrafficRegistry{
+		standardRegistry:    standardRegistry,
+		window:              trafficWindow,
+		startAt:             time.Now(),
+		now:                 time.Now,
+		entryPointReqs:      make(map[string]*rollingCounter),
+		entryPointOpenConns: make(map[string]map[string]float64),
+		routerReqs:          make(map[string]*rollingCounter),
+	}
+
+	standardRegistry.entryPointReqsCounter = &trafficCounter{label: "entrypoint", add: tr.addEntryPointRequests}
+	standardRegistry.entryPointOpenConnsGauge = &trafficGauge{registry: tr}
+	standardRegistry.routerReqsCounter = &trafficCounter{label: "router", add: tr.addRouterRequests}
+
+	return tr
+}
+
+// Snapshot returns the live traffic of the entry points and of the routers.
+func (tr *TrafficRegistry) Snapshot() TrafficSnapshot {
+	now := tr.now()
+
+	window := tr.window
+	if elapsed := now.Sub(tr.startAt).Truncate(time.Second) + time.Second; elapsed < window {
+		window = elapsed
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	snapshot := TrafficSnapshot{
+		Window:              window,
+		EntryPointOpenConns: make(map[string]float64, len(tr.entryPointOpenConns)),
+		EntryPointRequests:  sumRollingCounters(tr.entryPointReqs, now),
+		RouterRequests:      sumRollingCounters(tr.routerReqs, now),
+	}
+
+	for entryPoint, gauges := range tr.entryPointOpenConns {
+		for _, value := range gauges {
+			snapshot.EntryPointOpenConns[entryPoint] += value
+		}
+	}
+
+	return snapshot
+}
+
+func (tr *TrafficRegistry) addEntryPointRequests(entryPoint string, delta float64) {
+	tr.addRequests(tr.entryPointReqs, entryPoint, delta)
+}
+
+func (tr *TrafficRegistry) addRouterRequests(router string, delta float64) {
+	tr.addRequests(tr.routerReqs, router, delta)
+}
+
+func (tr *TrafficRegistry) addRequests(counters map[string]*rollingCounter, name string, delta float64) {
+	now := tr.now()
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	counter, ok := counters[name]
+	if !ok {
+		counter = newRollingCounter(tr.window)
+		counters[name] = counter
+	}
+
+	counter.add(now, delta)
+}
+
+// updateOpenConns updates the open connections of an entry point for the given labels,
+// and forgets the labels without open connections.
+func (tr *TrafficRegistry) updateOpenConns(entryPoint, labelsKey string, update func(float64) float64) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	gauges, ok := tr.entryPointOpenConns[entryPoint]
+	if !ok {
+		gauges = make(map[string]float64)
+		tr.entryPointOpenConns[entryPoint] = gauges
+	}
+
+	value := update(gauges[labelsKey])
+	if value != 0 {
+		gauges[labelsKey] = value
+		return
+	}
+
+	delete(gauges, labelsKey)
+	if len(gauges) == 0 {
+		delete(tr.entryPointOpenConns, entryPoint)
+	}
+}
+
+// sumRollingCounters returns the sums of the counters over their window,
+// and forgets the counters without requests over their window.
+func sumRollingCounters(counters map[string]*rollingCounter, now time.Time) map[string]float64 {
+	sums := make(map[string]float64, len(counters))
+
+	for name, counter := range counters {
+		sum := counter.sum(now)
+		if sum == 0 {
+			delete(counters, name)
+			continue
+		}
+
+		sums[name] = sum
+	}
+
+	return sums
+}
+
+// trafficCounter is a counter of the TrafficRegistry, which counts by the value of its label.
+type trafficCounter struct {
+	label string
+	value string
+	add   func(value string, delta float64)
+}
+
+// With returns a new trafficCounter with the given labels.
+func (c *trafficCounter) With(labelValues ...string) metrics.Counter {
+	value := c.value
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == c.label {
+			value = labelValues[i+1]
+		}
+	}
+
+	return &trafficCounter{label: c.label, value: value, add: c.add}
+}
+
+// Add adds the given delta to the counter.
+func (c *trafficCounter) Add(delta float64) {
+	if c.value == "" {
+		return
+	}
+
+	c.add(c.value, delta)
+}
+
+// trafficGauge is the open connections gauge of the entry points of the TrafficRegistry.
+type trafficGauge struct {
+	registry    *TrafficRegistry
+	labelValues []string
+}
+
+// With returns a new trafficGauge with the given labels.
+func (g *trafficGauge) With(labelValues ...string) metrics.Gauge {
+	var newLabelValues []string
+	newLabelValues = append(newLabelValues, g.labelValues...)
+	newLabelValues = append(newLabelValues, labelValues...)
+
+	return &trafficGauge{registry: g.registry, labelValues: newLabelValues}
+}
+
+// Set sets the given value to the gauge.
+func (g *trafficGauge) Set(value float64) {
+	g.update(func(float64) float64 { return value })
+}
+
+// Add adds the given delta to the gauge.
+func (g *trafficGauge) Add(delta float64) {
+	g.update(func(value float64) float64 { return value + delta })
+}
+
+func (g *trafficGauge) update(update func(float64) float64) {
+	var entryPoint string
+	for i := 0; i+1 < len(g.labelValues); i += 2 {
+		if g.labelValues[i] == "entrypoint" {
+			entryPoint = g.labelValues[i+1]
+		}
+	}
+
+	if entryPoint == "" {
+		return
+	}
+
+	g.registry.updateOpenConns(entryPoint, strings.Join(g.labelValues, ","), update)
+}
+
+// rollingCounter counts over a rolling window, with buckets of one second.
+type rollingCounter struct {
+	buckets []float64
+	// last is the Unix time, in seconds, of the last updated bucket.
+	last int64
+}
+
+func newRollingCounter(window time.Duration) *rollingCounter {
+	size := int(window / time.Second)
+	if size < 1 {
+		size = 1
+	}
+
+	return &rollingCounter{buckets: make([]float64, size)}
+}
+
+func (c *rollingCounter) add(now time.Time, delta float64) {
+	c.buckets[c.advance(now)] += delta
+}
+
+func (c *rollingCounter) sum(now time.Time) float64 {
+	c.advance(now)
+
+	var sum float64
+	for _, value := range c.buckets {
+		sum += value
+	}
+
+	return sum
+}
+
+// advance resets the buckets which went out of the window since the last update,
+// and returns the index of the current bucket.
+func (c *rollingCounter) advance(now time.Time) int {
+	size := int64(len(c.buckets))
+	current := now.Unix()
+
+	switch {
+	case current-c.last >= size:
+		for i := range c.buckets {
+			c.buckets[i] = 0
+		}
+	case current > c.last:
+		for sec := c.last + 1; sec <= current; sec++ {
+			c.buckets[sec%size] = 0
+		}
+	}
+
+	if current > c.last {
+		c.last = current
+	}
+
+	return int(c.last % size)
+}
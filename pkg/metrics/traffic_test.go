@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrafficRegistry(t *testing.T) {
+	registry := RegisterTraffic()
+
+	start := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	registry.startAt = start
+	registry.now = func() time.Time { return now }
+
+	assert.True(t, registry.IsEpEnabled())
+	assert.True(t, registry.IsRouterEnabled())
+	assert.False(t, registry.IsSvcEnabled())
+
+	registry.EntryPointReqsCounter().With("entrypoint", "web", "code", "200").Add(1)
+	registry.EntryPointReqsCounter().With("entrypoint", "web").With("code", "404").Add(2)
+	registry.EntryPointReqsCounter().With("entrypoint", "websecure", "code", "200").Add(1)
+	registry.RouterReqsCounter().With("router", "foo@file", "service", "foo@file", "code", "200").Add(3)
+	registry.RouterReqsCounter().With("router", "bar@file", "service", "bar@file", "code", "200").Add(1)
+
+	// Requests without the label of the counter are ignored.
+	registry.EntryPointReqsCounter().With("code", "200").Add(1)
+
+	openConns := registry.EntryPointOpenConnsGauge().With("entrypoint", "web", "method", "GET", "protocol", "http")
+	openConns.Add(1)
+	registry.EntryPointOpenConnsGauge().With("entrypoint", "web", "method", "POST", "protocol", "http").Add(1)
+
+	now = start.Add(10 * time.Second)
+
+	snapshot := registry.Snapshot()
+	assert.Equal(t, 11*time.Second, snapshot.Window)
+	assert.Equal(t, map[string]float64{"web": 3, "websecure": 1}, snapshot.EntryPointRequests)
+	assert.Equal(t, map[string]float64{"foo@file": 3, "bar@file": 1}, snapshot.RouterRequests)
+	assert.Equal(t, map[string]float64{"web": 2}, snapshot.EntryPointOpenConns)
+
+	registry.EntryPointReqsCounter().With("entrypoint", "web", "code", "200").Add(1)
+	openConns.Add(-1)
+
+	now = start.Add(65 * time.Second)
+
+	// The requests of the first second went out of the rolling window.
+	snapshot = registry.Snapshot()
+	assert.Equal(t, trafficWindow, snapshot.Window)
+	assert.Equal(t, map[string]float64{"web": 1}, snapshot.EntryPointRequests)
+	assert.Empty(t, snapshot.RouterRequests)
+	assert.Equal(t, map[string]float64{"web": 1}, snapshot.EntryPointOpenConns)
+
+	now = start.Add(time.Hour)
+
+	snapshot = registry.Snapshot()
+	assert.Empty(t, snapshot.EntryPointRequests)
+	assert.Empty(t, registry.entryPointReqs)
+	assert.Empty(t, registry.routerReqs)
+}
+
+func TestRollingCounter(t *testing.T) {
+	start := time.Unix(1000, 0)
+
+	counter := newRollingCounter(5 * time.Second)
+
+	counter.add(start, 1)
+	counter.add(start.Add(500*time.Millisecond), 1)
+	counter.add(start.Add(2*time.Second), 2)
+	assert.Equal(t, 4.0, counter.sum(start.Add(4*time.Second)))
+
+	// The requests of the first second went out of the window.
+	assert.Equal(t, 2.0, counter.sum(start.Add(5*time.Second)))
+
+	counter.add(start.Add(6*time.Second), 1)
+	assert.Equal(t, 3.0, counter.sum(start.Add(6*time.Second)))
+
+	assert.Equal(t, 0.0, counter.sum(start.Add(time.Minute)))
+}
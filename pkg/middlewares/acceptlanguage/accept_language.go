@@ -0,0 +1,93 @@
+package acceptlanguage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"golang.org/x/text/language"
+)
+
+const (
+	typeName = "AcceptLanguage"
+)
+
+const defaultHeaderName = "X-Language"
+
+// acceptLanguage is a middleware which sets the language of the configured list
+// best matching the Accept-Language header of the request in a request header.
+type acceptLanguage struct {
+	next       http.Handler
+	name       string
+	headerName string
+	languages  []string
+	matcher    language.Matcher
+}
+
+// New creates a new AcceptLanguage middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.AcceptLanguage, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Languages) == 0 {
+		return nil, errors.New("languages cannot be empty")
+	}
+
+	var tags []language.Tag
+	for _, lang := range config.Languages {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return nil, fmt.Errorf("invalid language %q: %w", lang, err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	headerName := config.HeaderName
+	if headerName == "" {
+		headerName = defaultHeaderName
+	}
+
+	return &acceptLanguage{
+		next:       next,
+		name:       name,
+		headerName: headerName,
+		languages:  config.Languages,
+		matcher:    language.NewMatcher(tags),
+	}, nil
+}
+
+func (a *acceptLanguage) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return a.name, tracing.SpanKindNoneEnum
+}
+
+func (a *acceptLanguage) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	lang := a.match(req)
+
+	log.FromContext(middlewares.GetLoggerCtx(req.Context(), a.name, typeName)).Debugf("Setting %s header to %s", a.headerName, lang)
+	req.Header.Set(a.headerName, lang)
+
+	a.next.ServeHTTP(rw, req)
+}
+
+// match returns the configured language best matching the Accept-Language header,
+// or the first configured language if none matches.
+func (a *acceptLanguage) match(req *http.Request) string {
+	accepted, _, err := language.ParseAcceptLanguage(strings.Join(req.Header.Values("Accept-Language"), ","))
+	if err != nil || len(accepted) == 0 {
+		return a.languages[0]
+	}
+
+	_, index, confidence := a.matcher.Match(accepted...)
+	if confidence == language.No {
+		return a.languages[0]
+	}
+
+	return a.languages[index]
+}
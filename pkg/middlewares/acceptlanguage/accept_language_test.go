@@ -0,0 +1,143 @@
+package acceptlanguage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.AcceptLanguage
+		expectedError bool
+	}{
+		{
+			desc:   "valid languages",
+			config: dynamic.AcceptLanguage{Languages: []string{"en", "fr-CA"}},
+		},
+		{
+			desc:          "no language",
+			config:        dynamic.AcceptLanguage{},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid language",
+			config:        dynamic.AcceptLanguage{Languages: []string{"en", "not a language"}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "foo-accept-language")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.AcceptLanguage
+		acceptLanguage []string
+		expectedHeader string
+		expectedValue  string
+	}{
+		{
+			desc:           "exact match",
+			config:         dynamic.AcceptLanguage{Languages: []string{"en", "fr"}},
+			acceptLanguage: []string{"fr"},
+			expectedHeader: "X-Language",
+			expectedValue:  "fr",
+		},
+		{
+			desc:           "best match with quality values",
+			config:         dynamic.AcceptLanguage{Languages: []string{"en", "fr", "de"}},
+			acceptLanguage: []string{"de-CH;q=0.5, fr-CH, en;q=0.8"},
+			expectedHeader: "X-Language",
+			expectedValue:  "fr",
+		},
+		{
+			desc:           "multiple Accept-Language headers",
+			config:         dynamic.AcceptLanguage{Languages: []string{"en", "de"}},
+			acceptLanguage: []string{"ja", "de-AT;q=0.9"},
+			expectedHeader: "X-Language",
+			expectedValue:  "de",
+		},
+		{
+			desc:           "configured language is kept as is",
+			config:         dynamic.AcceptLanguage{Languages: []string{"en-US", "pt-BR"}},
+			acceptLanguage: []string{"pt-br"},
+			expectedHeader: "X-Language",
+			expectedValue:  "pt-BR",
+		},
+		{
+			desc:           "no match falls back to the first language",
+			config:         dynamic.AcceptLanguage{Languages: []string{"en", "fr"}},
+			acceptLanguage: []string{"ja"},
+			expectedHeader: "X-Language",
+			expectedValue:  "en",
+		},
+		{
+			desc:           "no Accept-Language header falls back to the first language",
+			config:         dynamic.AcceptLanguage{Languages: []string{"fr", "en"}},
+			expectedHeader: "X-Language",
+			expectedValue:  "fr",
+		},
+		{
+			desc:           "invalid Accept-Language header falls back to the first language",
+			config:         dynamic.AcceptLanguage{Languages: []string{"en", "fr"}},
+			acceptLanguage: []string{"fr;q=foo"},
+			expectedHeader: "X-Language",
+			expectedValue:  "en",
+		},
+		{
+			desc:           "custom header name",
+			config:         dynamic.AcceptLanguage{Languages: []string{"en", "fr"}, HeaderName: "X-Locale"},
+			acceptLanguage: []string{"fr-FR"},
+			expectedHeader: "X-Locale",
+			expectedValue:  "fr",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var value string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				value = req.Header.Get(test.expectedHeader)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "foo-accept-language")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for _, acceptLanguage := range test.acceptLanguage {
+				req.Header.Add("Accept-Language", acceptLanguage)
+			}
+			// The header set by the client is overridden.
+			req.Header.Set(test.expectedHeader, "xx")
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedValue, value)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package accesslog
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// LogsConnections returns whether the TCP connections and the UDP sessions are logged.
+func (h *Handler) LogsConnections() bool {
+	return h.config.Connections
+}
+
+// LogConnection writes a TCP connection or a UDP session to the access log, when it is closed.
+// The given data holds the fields of the connection, including its StartUTC from which its duration is computed.
+func (h *Handler) LogConnection(core CoreLogData) {
+	if !h.config.Connections {
+		return
+	}
+
+	// n.b. take care to perform time arithmetic using UTC to avoid errors at DST boundaries.
+	core[Duration] = time.Now().UTC().Sub(core[StartUTC].(time.Time))
+
+	if clientAddr, ok := core[ClientAddr].(string); ok {
+		core[ClientHost], core[ClientPort] = silentSplitHostPort(clientAddr)
+	}
+
+	if h.config.BufferingSize > 0 {
+		h.logHandlerChan <- handlerParams{
+			logDataTable: &LogData{Core: core},
+			connection:   true,
+		}
+	} else {
+		h.logTheConnection(core)
+	}
+}
+
+func (h *Handler) logTheConnection(core CoreLogData) {
+	if !h.keepConnectionLog(core[Duration].(time.Duration)) {
+		return
+	}
+
+	fields := logrus.Fields{}
+
+	for k, v := range core {
+		if h.config.Fields.Keep(k) {
+			fields[k] = v
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logger.WithFields(fields).Println()
+}
+
+// keepConnectionLog applies the filters to a connection, among which only the minimum duration is relevant.
+func (h *Handler) keepConnectionLog(duration time.Duration) bool {
+	if h.config.Filters == nil || h.config.Filters.MinDuration == 0 {
+		return true
+	}
+
+	return ptypes.Duration(duration) > h.config.Filters.MinDuration
+}
@@ -0,0 +1,25 @@
+package accesslog
+
+import (
+	"net/http"
+)
+
+// DecisionPrefix is the prefix of the map keys used for the decision metadata attached by the middlewares.
+const DecisionPrefix = "decision_"
+
+// AddDecision attaches a decision metadata of a middleware to the log data of the request, if any.
+// The metadata is logged under the DecisionPrefix + key field.
+func AddDecision(req *http.Request, key string, value interface{}) {
+	table := GetLogData(req)
+	if table != nil {
+		table.Core[DecisionPrefix+key] = value
+	}
+}
+
+// SetRejectedBy records the name of the middleware rejecting the request in the log data of the request, if any.
+func SetRejectedBy(req *http.Request, middlewareName string) {
+	table := GetLogData(req)
+	if table != nil {
+		table.Core[RejectedBy] = middlewareName
+	}
+}
@@ -0,0 +1,31 @@
+package accesslog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddDecision(t *testing.T) {
+	logDataTable := &LogData{Core: make(CoreLogData)}
+	req := httptest.NewRequest(http.MethodGet, "/some/path", nil)
+	req = req.WithContext(context.WithValue(req.Context(), DataTableKey, logDataTable))
+
+	AddDecision(req, "RateLimitBucket", "10.0.0.1")
+	SetRejectedBy(req, "ratelimit@file")
+
+	assert.Equal(t, "10.0.0.1", logDataTable.Core["decision_RateLimitBucket"])
+	assert.Equal(t, "ratelimit@file", logDataTable.Core[RejectedBy])
+}
+
+func TestAddDecision_withoutLogData(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/some/path", nil)
+
+	assert.NotPanics(t, func() {
+		AddDecision(req, "RateLimitBucket", "10.0.0.1")
+		SetRejectedBy(req, "ratelimit@file")
+	})
+}
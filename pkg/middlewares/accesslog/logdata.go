@@ -70,11 +70,22 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// RejectedBy is the map key used for the name of the middleware which rejected the request, if any.
+	RejectedBy = "RejectedBy"
+	// RequestID is the map key used for the ID of the request, set by the RequestID middleware.
+	RequestID = "RequestID"
 
 	// TLSVersion is the version of TLS used in the request.
 	TLSVersion = "TLSVersion"
 	// TLSCipher is the cipher used in the request.
 	TLSCipher = "TLSCipher"
+
+	// ConnectionProtocol is the map key used for the protocol of a TCP connection or a UDP session: tcp | udp.
+	ConnectionProtocol = "ConnectionProtocol"
+	// ConnectionBytesIn is the map key used for the number of bytes received from the client during a TCP connection or a UDP session.
+	ConnectionBytesIn = "ConnectionBytesIn"
+	// ConnectionBytesOut is the map key used for the number of bytes sent to the client during a TCP connection or a UDP session.
+	ConnectionBytesOut = "ConnectionBytesOut"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -116,8 +127,13 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[RejectedBy] = struct{}{}
+	allCoreKeys[RequestID] = struct{}{}
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
+	allCoreKeys[ConnectionProtocol] = struct{}{}
+	allCoreKeys[ConnectionBytesIn] = struct{}{}
+	allCoreKeys[ConnectionBytesOut] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
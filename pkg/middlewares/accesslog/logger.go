@@ -18,6 +18,7 @@ import (
 	"github.com/sirupsen/logrus"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/log/sink"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -50,6 +51,8 @@ func (n noopCloser) Close() error {
 
 type handlerParams struct {
 	logDataTable *LogData
+	// connection is true when the data is the one of a TCP connection or a UDP session.
+	connection bool
 }
 
 // Handler will write each request and its response to the access log.
@@ -57,6 +60,7 @@ type Handler struct {
 	config         *types.AccessLog
 	logger         *logrus.Logger
 	file           io.WriteCloser
+	sinks          []io.WriteCloser
 	mu             sync.Mutex
 	httpCodeRanges types.HTTPCodeRanges
 	logHandlerChan chan handlerParams
@@ -74,13 +78,22 @@ func WrapHandler(handler *Handler) alice.Constructor {
 
 // NewHandler creates a new Handler.
 func NewHandler(config *types.AccessLog) (*Handler, error) {
-	var file io.WriteCloser = noopCloser{os.Stdout}
-	if len(config.FilePath) > 0 {
+	sinks, err := newSinks(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var file io.WriteCloser
+	switch {
+	case len(config.FilePath) > 0:
 		f, err := openAccessLogFile(config.FilePath)
 		if err != nil {
+			closeSinks(sinks)
 			return nil, fmt.Errorf("error opening access log file: %w", err)
 		}
 		file = f
+	case len(sinks) == 0:
+		file = noopCloser{os.Stdout}
 	}
 	logHandlerChan := make(chan handlerParams, config.BufferingSize)
 
@@ -97,7 +110,7 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 	}
 
 	logger := &logrus.Logger{
-		Out:       file,
+		Out:       output(file, sinks),
 		Formatter: formatter,
 		Hooks:     make(logrus.LevelHooks),
 		Level:     logrus.InfoLevel,
@@ -118,6 +131,7 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		config:         config,
 		logger:         logger,
 		file:           file,
+		sinks:          sinks,
 		logHandlerChan: logHandlerChan,
 	}
 
@@ -134,6 +148,11 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 		go func() {
 			defer logHandler.wg.Done()
 			for handlerParams := range logHandler.logHandlerChan {
+				if handlerParams.connection {
+					logHandler.logTheConnection(handlerParams.logDataTable.Core)
+					continue
+				}
+
 				logHandler.logTheRoundTrip(handlerParams.logDataTable)
 			}
 		}()
@@ -142,6 +161,54 @@ func NewHandler(config *types.AccessLog) (*Handler, error) {
 	return logHandler, nil
 }
 
+// newSinks creates the remote outputs of the access log.
+func newSinks(config *types.AccessLog) ([]io.WriteCloser, error) {
+	var sinks []io.WriteCloser
+
+	if config.Syslog != nil {
+		writer, err := sink.NewSyslog(config.Syslog)
+		if err != nil {
+			return nil, fmt.Errorf("error creating access log syslog output: %w", err)
+		}
+		sinks = append(sinks, writer)
+	}
+
+	if config.Fluentd != nil {
+		writer, err := sink.NewFluentd(config.Fluentd)
+		if err != nil {
+			closeSinks(sinks)
+			return nil, fmt.Errorf("error creating access log fluentd output: %w", err)
+		}
+		sinks = append(sinks, writer)
+	}
+
+	return sinks, nil
+}
+
+func closeSinks(sinks []io.WriteCloser) {
+	for _, s := range sinks {
+		_ = s.Close()
+	}
+}
+
+// output returns the writer of the access log entries, writing to the file, if any, and to the sinks.
+func output(file io.Writer, sinks []io.WriteCloser) io.Writer {
+	var writers []io.Writer
+	if file != nil {
+		writers = append(writers, file)
+	}
+
+	for _, s := range sinks {
+		writers = append(writers, s)
+	}
+
+	if len(writers) == 1 {
+		return writers[0]
+	}
+
+	return io.MultiWriter(writers...)
+}
+
 func openAccessLogFile(filePath string) (*os.File, error) {
 	dir := filepath.Dir(filePath)
 
@@ -251,6 +318,12 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 func (h *Handler) Close() error {
 	close(h.logHandlerChan)
 	h.wg.Wait()
+
+	closeSinks(h.sinks)
+
+	if h.file == nil {
+		return nil
+	}
 	return h.file.Close()
 }
 
@@ -271,7 +344,7 @@ func (h *Handler) Rotate() error {
 	}
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	h.logger.Out = h.file
+	h.logger.Out = output(h.file, h.sinks)
 	return nil
 }
 
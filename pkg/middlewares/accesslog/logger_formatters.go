@@ -3,6 +3,7 @@ package accesslog
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -33,6 +34,21 @@ func (f *CommonLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		elapsedMillis = v.(time.Duration).Nanoseconds() / 1000000
 	}
 
+	if protocol, ok := entry.Data[ConnectionProtocol].(string); ok {
+		_, err := fmt.Fprintf(b, "%s - - [%s] \"%s\" %v %v %s %s %s %dms\n",
+			toLog(entry.Data, ClientHost, defaultValue, false),
+			timestamp,
+			strings.ToUpper(protocol),
+			toLog(entry.Data, ConnectionBytesIn, defaultValue, true),
+			toLog(entry.Data, ConnectionBytesOut, defaultValue, true),
+			toLog(entry.Data, RouterName, `"-"`, true),
+			toLog(entry.Data, ServiceName, `"-"`, true),
+			toLog(entry.Data, ServiceAddr, `"-"`, true),
+			elapsedMillis)
+
+		return b.Bytes(), err
+	}
+
 	_, err := fmt.Fprintf(b, "%s - %s [%s] \"%s %s %s\" %v %v %s %s %v %s %s %dms\n",
 		toLog(entry.Data, ClientHost, defaultValue, false),
 		toLog(entry.Data, ClientUsername, defaultValue, false),
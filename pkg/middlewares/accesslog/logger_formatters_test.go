@@ -79,6 +79,37 @@ func TestCommonLogFormatter_Format(t *testing.T) {
 				ServiceURL:             "http://10.0.0.2/toto",
 			},
 			expectedLog: `10.0.0.1 - Client [10/Nov/2009:14:00:00 -0900] "GET /foo http" 123 132 "referer" "agent" - "foo" "http://10.0.0.2/toto" 123000ms
+`,
+		},
+		{
+			name: "TCP connection",
+			data: map[string]interface{}{
+				StartUTC:           time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+				Duration:           1500 * time.Millisecond,
+				ClientHost:         "10.0.0.1",
+				ConnectionProtocol: "tcp",
+				ConnectionBytesIn:  int64(120),
+				ConnectionBytesOut: int64(4096),
+				RouterName:         "db@file",
+				ServiceName:        "db@file",
+				ServiceAddr:        "10.0.0.2:5432",
+			},
+			expectedLog: `10.0.0.1 - - [10/Nov/2009:23:00:00 +0000] "TCP" 120 4096 "db@file" "db@file" "10.0.0.2:5432" 1500ms
+`,
+		},
+		{
+			name: "UDP session without server",
+			data: map[string]interface{}{
+				StartUTC:           time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+				Duration:           2 * time.Second,
+				ClientHost:         "10.0.0.1",
+				ConnectionProtocol: "udp",
+				ConnectionBytesIn:  int64(64),
+				ConnectionBytesOut: int64(0),
+				RouterName:         "dns@file",
+				ServiceName:        "dns@file",
+			},
+			expectedLog: `10.0.0.1 - - [10/Nov/2009:23:00:00 +0000] "UDP" 64 0 "dns@file" "dns@file" "-" 2000ms
 `,
 		},
 	}
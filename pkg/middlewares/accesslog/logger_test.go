@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -468,6 +469,31 @@ func TestLoggerJSON(t *testing.T) {
 	}
 }
 
+func TestNewLogHandlerOutputSyslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	config := &types.AccessLog{
+		Format: CommonFormat,
+		Syslog: &types.Syslog{
+			Address:    "udp://" + conn.LocalAddr().String(),
+			Tag:        "traefik",
+			Facility:   "local0",
+			BufferSize: 10,
+		},
+	}
+	doLogging(t, config)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^<134>1 \S+ \S+ traefik \d+ - - TestHost - TestUser \[[^]]+\] "POST testpath HTTP/0.0" 123 12`, string(buf[:n]))
+}
+
 func TestNewLogHandlerOutputStdout(t *testing.T) {
 	testCases := []struct {
 		desc        string
@@ -673,6 +699,121 @@ func TestNewLogHandlerOutputStdout(t *testing.T) {
 	}
 }
 
+func TestHandler_LogConnection(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   types.AccessLog
+		expected map[string]interface{}
+	}{
+		{
+			desc:   "connections not logged",
+			config: types.AccessLog{Format: JSONFormat},
+		},
+		{
+			desc:   "connection logged",
+			config: types.AccessLog{Format: JSONFormat, Connections: true},
+			expected: map[string]interface{}{
+				ConnectionProtocol: "tcp",
+				ConnectionBytesIn:  float64(120),
+				ConnectionBytesOut: float64(4096),
+				RouterName:         "db@file",
+				ServiceName:        "db@file",
+				ServiceAddr:        "10.0.0.2:5432",
+				ClientAddr:         "10.0.0.1:4242",
+				ClientHost:         "10.0.0.1",
+				ClientPort:         "4242",
+			},
+		},
+		{
+			desc:   "buffered connection logged",
+			config: types.AccessLog{Format: JSONFormat, Connections: true, BufferingSize: 10},
+			expected: map[string]interface{}{
+				ConnectionProtocol: "tcp",
+				ConnectionBytesIn:  float64(120),
+				ConnectionBytesOut: float64(4096),
+				RouterName:         "db@file",
+				ServiceName:        "db@file",
+				ServiceAddr:        "10.0.0.2:5432",
+				ClientAddr:         "10.0.0.1:4242",
+				ClientHost:         "10.0.0.1",
+				ClientPort:         "4242",
+			},
+		},
+		{
+			desc: "connection logged, the status codes filter being ignored",
+			config: types.AccessLog{
+				Format:      JSONFormat,
+				Connections: true,
+				Filters:     &types.AccessLogFilters{StatusCodes: []string{"500"}},
+			},
+			expected: map[string]interface{}{
+				ConnectionProtocol: "tcp",
+				ConnectionBytesIn:  float64(120),
+				ConnectionBytesOut: float64(4096),
+				RouterName:         "db@file",
+				ServiceName:        "db@file",
+				ServiceAddr:        "10.0.0.2:5432",
+				ClientAddr:         "10.0.0.1:4242",
+				ClientHost:         "10.0.0.1",
+				ClientPort:         "4242",
+			},
+		},
+		{
+			desc: "connection shorter than the minimum duration",
+			config: types.AccessLog{
+				Format:      JSONFormat,
+				Connections: true,
+				Filters:     &types.AccessLogFilters{MinDuration: ptypes.Duration(time.Hour)},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			logFilePath := filepath.Join(t.TempDir(), logFileNameSuffix)
+
+			config := test.config
+			config.FilePath = logFilePath
+
+			handler, err := NewHandler(&config)
+			require.NoError(t, err)
+
+			start := time.Now().Add(-time.Second)
+			handler.LogConnection(CoreLogData{
+				StartUTC:           start.UTC(),
+				ConnectionProtocol: "tcp",
+				ConnectionBytesIn:  int64(120),
+				ConnectionBytesOut: int64(4096),
+				RouterName:         "db@file",
+				ServiceName:        "db@file",
+				ServiceAddr:        "10.0.0.2:5432",
+				ClientAddr:         "10.0.0.1:4242",
+			})
+
+			require.NoError(t, handler.Close())
+
+			logData, err := os.ReadFile(logFilePath)
+			require.NoError(t, err)
+
+			if test.expected == nil {
+				assert.Empty(t, logData)
+				return
+			}
+
+			var fields map[string]interface{}
+			require.NoError(t, json.Unmarshal(logData, &fields))
+
+			for key, value := range test.expected {
+				assert.Equal(t, value, fields[key], key)
+			}
+			assert.GreaterOrEqual(t, fields[Duration], float64(time.Second))
+		})
+	}
+}
+
 func assertValidLogData(t *testing.T, expected string, logData []byte) {
 	t.Helper()
 
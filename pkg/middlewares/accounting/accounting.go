@@ -0,0 +1,146 @@
+package accounting
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/accounting"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+)
+
+const typeName = "Accounting"
+
+type accountant interface {
+	Add(routerName, serviceName string, requestBytes, responseBytes int64)
+}
+
+var _ accountant = (*accounting.Accountant)(nil)
+
+// accountingMiddleware counts the request and response body sizes of a router.
+type accountingMiddleware struct {
+	next        http.Handler
+	accountant  accountant
+	routerName  string
+	serviceName string
+}
+
+// New creates a new accounting middleware for a router.
+func New(ctx context.Context, next http.Handler, accountant accountant, routerName, serviceName string) http.Handler {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, routerName, typeName)).Debug("Creating middleware")
+
+	return &accountingMiddleware{
+		next:        next,
+		accountant:  accountant,
+		routerName:  routerName,
+		serviceName: serviceName,
+	}
+}
+
+// WrapRouterHandler wraps the accounting middleware to alice.Constructor.
+func WrapRouterHandler(ctx context.Context, accountant accountant, routerName, serviceName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return New(ctx, next, accountant, routerName, serviceName), nil
+	}
+}
+
+func (a *accountingMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var reqReader *countingReader
+	if req.Body != nil && req.Body != http.NoBody {
+		reqReader = &countingReader{source: req.Body}
+		req.Body = reqReader
+	}
+
+	crw := newCountingResponseWriter(rw)
+
+	a.next.ServeHTTP(crw, req)
+
+	var requestBytes int64
+	if reqReader != nil {
+		requestBytes = atomic.LoadInt64(&reqReader.count)
+	}
+
+	a.accountant.Add(a.routerName, a.serviceName, requestBytes, crw.size())
+}
+
+type countingReader struct {
+	count  int64 // must be first to ensure 64-bit alignment for atomic operations.
+	source io.ReadCloser
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.source.Read(p)
+	atomic.AddInt64(&r.count, int64(n))
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	return r.source.Close()
+}
+
+type counter interface {
+	http.ResponseWriter
+	size() int64
+}
+
+func newCountingResponseWriter(rw http.ResponseWriter) counter {
+	crw := &countingResponseWriter{rw: rw}
+	if _, ok := rw.(http.CloseNotifier); !ok {
+		return crw
+	}
+	return &countingResponseWriterWithCloseNotify{crw}
+}
+
+// countingResponseWriter is a wrapper of type http.ResponseWriter
+// that counts the number of bytes written in the response body.
+type countingResponseWriter struct {
+	rw    http.ResponseWriter
+	count int64
+}
+
+func (c *countingResponseWriter) Header() http.Header {
+	return c.rw.Header()
+}
+
+func (c *countingResponseWriter) Write(b []byte) (int, error) {
+	n, err := c.rw.Write(b)
+	c.count += int64(n)
+	return n, err
+}
+
+func (c *countingResponseWriter) WriteHeader(statusCode int) {
+	c.rw.WriteHeader(statusCode)
+}
+
+func (c *countingResponseWriter) Flush() {
+	if f, ok := c.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (c *countingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := c.rw.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, fmt.Errorf("not a hijacker: %T", c.rw)
+}
+
+func (c *countingResponseWriter) size() int64 {
+	return c.count
+}
+
+type countingResponseWriterWithCloseNotify struct {
+	*countingResponseWriter
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone away.
+func (c *countingResponseWriterWithCloseNotify) CloseNotify() <-chan bool {
+	return c.rw.(http.CloseNotifier).CloseNotify()
+}
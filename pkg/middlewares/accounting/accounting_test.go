@@ -0,0 +1,51 @@
+package accounting
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type collectingAccountant struct {
+	routerName    string
+	serviceName   string
+	requestBytes  int64
+	responseBytes int64
+}
+
+func (c *collectingAccountant) Add(routerName, serviceName string, requestBytes, responseBytes int64) {
+	c.routerName = routerName
+	c.serviceName = serviceName
+	c.requestBytes += requestBytes
+	c.responseBytes += responseBytes
+}
+
+func TestAccountingMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write(append(body, body...))
+	})
+
+	accountant := &collectingAccountant{}
+	handler := New(context.Background(), next, accountant, "router", "service")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello"))
+	rw := httptest.NewRecorder()
+
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, "router", accountant.routerName)
+	assert.Equal(t, "service", accountant.serviceName)
+	assert.Equal(t, int64(5), accountant.requestBytes)
+	assert.Equal(t, int64(10), accountant.responseBytes)
+}
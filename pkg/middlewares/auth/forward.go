@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -15,7 +16,9 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/tracing"
+	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/vulcand/oxy/forward"
 	"github.com/vulcand/oxy/utils"
 )
@@ -24,8 +27,13 @@ const (
 	xForwardedURI     = "X-Forwarded-Uri"
 	xForwardedMethod  = "X-Forwarded-Method"
 	forwardedTypeName = "ForwardedAuthType"
+
+	// defaultMaxBodySize is the default maximum size of the request body forwarded to the authentication server.
+	defaultMaxBodySize = 1 << 20
 )
 
+var errBodyTooLarge = errors.New("request body too large")
+
 // hopHeaders Hop-by-hop headers to be removed in the authentication request.
 // http://www.w3.org/Protocols/rfc2616/rfc2616-sec13.html
 // Proxy-Authorization header is forwarded to the authentication server (see https://tools.ietf.org/html/rfc7235#section-4.4).
@@ -47,10 +55,14 @@ type forwardAuth struct {
 	client                   http.Client
 	trustForwardHeader       bool
 	authRequestHeaders       []string
+	cache                    *authCache
+	forwardBody              bool
+	maxBodySize              int64
 }
 
 // NewForward creates a forward auth middleware.
-func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAuth, name string) (http.Handler, error) {
+// The proxy, if any, is used to reach the authentication server instead of the environment variables.
+func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAuth, name string, proxy *types.Proxy) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, forwardedTypeName)).Debug("Creating middleware")
 
 	fa := &forwardAuth{
@@ -60,6 +72,20 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 		name:                name,
 		trustForwardHeader:  config.TrustForwardHeader,
 		authRequestHeaders:  config.AuthRequestHeaders,
+		forwardBody:         config.ForwardBody,
+		maxBodySize:         config.MaxBodySize,
+	}
+
+	if fa.maxBodySize < 0 {
+		return nil, errors.New("maxBodySize should be >= 0")
+	}
+	if fa.maxBodySize == 0 {
+		fa.maxBodySize = defaultMaxBodySize
+	}
+
+	// The authentication of a request depends on its body, which is not part of the cache key.
+	if config.ForwardBody && config.Cache != nil {
+		return nil, errors.New("the forward auth cache cannot be enabled when the request body is forwarded")
 	}
 
 	// Ensure our request client does not follow redirects
@@ -70,15 +96,19 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 		Timeout: 30 * time.Second,
 	}
 
-	if config.TLS != nil {
-		tlsConfig, err := config.TLS.CreateTLSConfig()
-		if err != nil {
-			return nil, err
+	if config.TLS != nil || proxy != nil {
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+
+		if config.TLS != nil {
+			tlsConfig, err := config.TLS.CreateTLSConfig()
+			if err != nil {
+				return nil, err
+			}
+
+			tr.TLSClientConfig = tlsConfig
 		}
 
-		tr := http.DefaultTransport.(*http.Transport).Clone()
-		tr.TLSClientConfig = tlsConfig
-		fa.client.Transport = tr
+		fa.client.Transport = proxy.WrapTransport(tr)
 	}
 
 	if config.AuthResponseHeadersRegex != "" {
@@ -89,6 +119,14 @@ func NewForward(ctx context.Context, next http.Handler, config dynamic.ForwardAu
 		fa.authResponseHeadersRegex = re
 	}
 
+	if config.Cache != nil {
+		cache, err := newAuthCache(config.Cache, config.TrustForwardHeader)
+		if err != nil {
+			return nil, err
+		}
+		fa.cache = cache
+	}
+
 	return fa, nil
 }
 
@@ -99,7 +137,44 @@ func (fa *forwardAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
 func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), fa.name, forwardedTypeName))
 
-	forwardReq, err := http.NewRequest(http.MethodGet, fa.address, nil)
+	var cacheKey string
+	if fa.cache != nil {
+		cacheKey = fa.cache.key(req)
+
+		if header, ok := fa.cache.get(cacheKey); ok {
+			logger.Debug("Authentication response found in cache")
+			accesslog.AddDecision(req, "ForwardAuthCached", true)
+
+			fa.setAuthResponseHeaders(req, header)
+
+			req.RequestURI = req.URL.RequestURI()
+			fa.next.ServeHTTP(rw, req)
+			return
+		}
+	}
+
+	var forwardBody io.Reader
+	if fa.forwardBody {
+		body, err := fa.readBody(req)
+		if err != nil {
+			logMessage := fmt.Sprintf("Error reading request body. Cause: %s", err)
+			logger.Debug(logMessage)
+			tracing.SetErrorWithEvent(req, logMessage)
+
+			if errors.Is(err, errBodyTooLarge) {
+				accesslog.SetRejectedBy(req, fa.name)
+				rw.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		forwardBody = bytes.NewReader(body)
+	}
+
+	forwardReq, err := http.NewRequest(http.MethodGet, fa.address, forwardBody)
 	tracing.LogRequest(tracing.GetSpan(req), forwardReq)
 	if err != nil {
 		logMessage := fmt.Sprintf("Error calling %s. Cause %s", fa.address, err)
@@ -110,12 +185,12 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	// Ensure tracing headers are in the request before we copy the headers to the
-	// forwardReq.
-	tracing.InjectRequestHeaders(req)
-
 	writeHeader(req, forwardReq, fa.trustForwardHeader, fa.authRequestHeaders)
 
+	// The tracing headers are injected after the filtering of the request headers,
+	// so that the trace is propagated to the authentication server.
+	tracing.InjectHeaders(req, forwardReq.Header)
+
 	forwardResponse, forwardErr := fa.client.Do(forwardReq)
 	if forwardErr != nil {
 		logMessage := fmt.Sprintf("Error calling %s. Cause: %s", fa.address, forwardErr)
@@ -137,10 +212,13 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 	defer forwardResponse.Body.Close()
 
+	accesslog.AddDecision(req, "ForwardAuthStatus", forwardResponse.StatusCode)
+
 	// Pass the forward response's body and selected headers if it
 	// didn't return a response within the range of [200, 300).
 	if forwardResponse.StatusCode < http.StatusOK || forwardResponse.StatusCode >= http.StatusMultipleChoices {
 		logger.Debugf("Remote error %s. StatusCode: %d", fa.address, forwardResponse.StatusCode)
+		accesslog.SetRejectedBy(req, fa.name)
 
 		utils.CopyHeaders(rw.Header(), forwardResponse.Header)
 		utils.RemoveHeaders(rw.Header(), hopHeaders...)
@@ -171,11 +249,56 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if fa.cache != nil {
+		if err := fa.cache.set(cacheKey, forwardResponse); err != nil {
+			logger.Errorf("Error while caching the authentication response: %v", err)
+		}
+	}
+
+	fa.setAuthResponseHeaders(req, forwardResponse.Header)
+
+	req.RequestURI = req.URL.RequestURI()
+	fa.next.ServeHTTP(rw, req)
+}
+
+// readBody reads the request body, up to the maximum body size, and replaces it with the read bytes,
+// for the request to be forwarded to the service.
+func (fa *forwardAuth) readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.ContentLength > fa.maxBodySize {
+		return nil, errBodyTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, fa.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > fa.maxBodySize {
+		return nil, errBodyTooLarge
+	}
+
+	if err = req.Body.Close(); err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.TransferEncoding = nil
+
+	return body, nil
+}
+
+// setAuthResponseHeaders copies the selected headers of the authentication response to the request.
+func (fa *forwardAuth) setAuthResponseHeaders(req *http.Request, authHeader http.Header) {
 	for _, headerName := range fa.authResponseHeaders {
 		headerKey := http.CanonicalHeaderKey(headerName)
 		req.Header.Del(headerKey)
-		if len(forwardResponse.Header[headerKey]) > 0 {
-			req.Header[headerKey] = append([]string(nil), forwardResponse.Header[headerKey]...)
+		if len(authHeader[headerKey]) > 0 {
+			req.Header[headerKey] = append([]string(nil), authHeader[headerKey]...)
 		}
 	}
 
@@ -186,15 +309,12 @@ func (fa *forwardAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 			}
 		}
 
-		for headerKey, headerValues := range forwardResponse.Header {
+		for headerKey, headerValues := range authHeader {
 			if fa.authResponseHeadersRegex.MatchString(headerKey) {
 				req.Header[headerKey] = append([]string(nil), headerValues...)
 			}
 		}
 	}
-
-	req.RequestURI = req.URL.RequestURI()
-	fa.next.ServeHTTP(rw, req)
 }
 
 func writeHeader(req, forwardReq *http.Request, trustForwardHeader bool, allowedHeaders []string) {
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/vulcand/oxy/forward"
+)
+
+// authCache caches the successful responses of the authentication server,
+// keyed by a hash of the request method, host, and URI, as forwarded to the authentication server, and of the key headers.
+// When keyPathSegments is positive, only the leading segments of the path of the URI are part of the key.
+type authCache struct {
+	ttl                time.Duration
+	keyHeaders         []string
+	keyPathSegments    int
+	trustForwardHeader bool
+	entries            *ttlmap.TtlMap
+}
+
+type authCacheEntry struct {
+	header    http.Header
+	expiresAt time.Time
+}
+
+func newAuthCache(config *dynamic.ForwardAuthCache, trustForwardHeader bool) (*authCache, error) {
+	if len(config.KeyHeaders) == 0 {
+		return nil, errors.New("the forward auth cache requires at least one key header")
+	}
+
+	if config.TTL <= 0 {
+		return nil, errors.New("the forward auth cache requires a positive TTL")
+	}
+
+	if config.KeyPathSegments < 0 {
+		return nil, errors.New("the forward auth cache requires a non-negative number of key path segments")
+	}
+
+	if config.MaxEntries <= 0 {
+		return nil, errors.New("the forward auth cache requires a positive maximum number of entries")
+	}
+
+	entries, err := ttlmap.NewConcurrent(config.MaxEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	keyHeaders := make([]string, 0, len(config.KeyHeaders))
+	for _, name := range config.KeyHeaders {
+		keyHeaders = append(keyHeaders, http.CanonicalHeaderKey(name))
+	}
+
+	return &authCache{
+		ttl:                time.Duration(config.TTL),
+		keyHeaders:         keyHeaders,
+		keyPathSegments:    config.KeyPathSegments,
+		trustForwardHeader: trustForwardHeader,
+		entries:            entries,
+	}, nil
+}
+
+// key returns the cache key of the request.
+// The method, the host, and the URI are the ones sent to the authentication server, see writeHeader.
+func (c *authCache) key(req *http.Request) string {
+	hash := sha256.New()
+
+	writeKeyPart(hash, c.forwardedValue(req, xForwardedMethod, req.Method))
+	writeKeyPart(hash, c.forwardedValue(req, forward.XForwardedHost, req.Host))
+
+	uri := c.forwardedValue(req, xForwardedURI, req.URL.RequestURI())
+	if c.keyPathSegments > 0 {
+		path := uri
+		if i := strings.IndexAny(path, "?#"); i >= 0 {
+			path = path[:i]
+		}
+		uri = pathPrefix(path, c.keyPathSegments)
+	}
+	writeKeyPart(hash, uri)
+
+	for _, name := range c.keyHeaders {
+		values := req.Header[name]
+
+		_, _ = fmt.Fprintf(hash, "%d", len(values))
+		for _, value := range values {
+			writeKeyPart(hash, value)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// forwardedValue returns the value of the given forwarded header if it is trusted, or else the given value.
+func (c *authCache) forwardedValue(req *http.Request, name, value string) string {
+	if forwarded := req.Header.Get(name); forwarded != "" && c.trustForwardHeader {
+		return forwarded
+	}
+
+	return value
+}
+
+// writeKeyPart writes a length-prefixed part of the key, so that the parts cannot be confused with each other.
+func writeKeyPart(w io.Writer, part string) {
+	_, _ = fmt.Fprintf(w, "%d:%s", len(part), part)
+}
+
+// pathPrefix returns the given number of leading segments of the path.
+func pathPrefix(path string, segments int) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", segments+1)
+	if len(parts) > segments {
+		parts = parts[:segments]
+	}
+
+	return "/" + strings.Join(parts, "/")
+}
+
+// get returns the headers of the cached authentication response, if any.
+func (c *authCache) get(key string) (http.Header, bool) {
+	value, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	entry := value.(*authCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+
+	return entry.header, true
+}
+
+// set caches the authentication response, unless the authentication server forbids it.
+func (c *authCache) set(key string, resp *http.Response) error {
+	for _, directive := range strings.Split(resp.Header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return nil
+		}
+	}
+
+	entry := &authCacheEntry{
+		header:    resp.Header.Clone(),
+		expiresAt: time.Now().Add(c.ttl),
+	}
+
+	// The TTL map has a resolution of a second, the actual expiry is checked on get.
+	return c.entries.Set(key, entry, int(math.Ceil(c.ttl.Seconds())))
+}
@@ -6,12 +6,16 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	tracingMiddleware "github.com/traefik/traefik/v2/pkg/middlewares/tracing"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
@@ -32,7 +36,7 @@ func TestForwardAuthFail(t *testing.T) {
 
 	middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
 		Address: server.URL,
-	}, "authTest")
+	}, "authTest", nil)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(middleware)
@@ -77,7 +81,7 @@ func TestForwardAuthSuccess(t *testing.T) {
 		AuthResponseHeaders:      []string{"X-Auth-User", "X-Auth-Group"},
 		AuthResponseHeadersRegex: "^Foo-",
 	}
-	middleware, err := NewForward(context.Background(), next, auth, "authTest")
+	middleware, err := NewForward(context.Background(), next, auth, "authTest", nil)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(middleware)
@@ -110,7 +114,7 @@ func TestForwardAuthRedirect(t *testing.T) {
 
 	auth := dynamic.ForwardAuth{Address: authTs.URL}
 
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest", nil)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(authMiddleware)
@@ -161,7 +165,7 @@ func TestForwardAuthRemoveHopByHopHeaders(t *testing.T) {
 
 	auth := dynamic.ForwardAuth{Address: authTs.URL}
 
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest", nil)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(authMiddleware)
@@ -207,7 +211,7 @@ func TestForwardAuthFailResponseHeaders(t *testing.T) {
 	auth := dynamic.ForwardAuth{
 		Address: authTs.URL,
 	}
-	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest")
+	authMiddleware, err := NewForward(context.Background(), next, auth, "authTest", nil)
 	require.NoError(t, err)
 
 	ts := httptest.NewServer(authMiddleware)
@@ -245,6 +249,338 @@ func TestForwardAuthFailResponseHeaders(t *testing.T) {
 	assert.Equal(t, "Forbidden\n", string(body))
 }
 
+func TestForwardAuthCache(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		cacheControl       string
+		keyPathSegments    int
+		trustForwardHeader bool
+		requests           []*http.Request
+		expectedHits       int32
+	}{
+		{
+			desc: "identical credentials",
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+			},
+			expectedHits: 1,
+		},
+		{
+			desc: "different paths",
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+				newCredentialsRequest("http://foo.bar/api/v2", "token1"),
+			},
+			expectedHits: 2,
+		},
+		{
+			desc: "different queries",
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1?user=1", "token1"),
+				newCredentialsRequest("http://foo.bar/api/v1?user=2", "token1"),
+			},
+			expectedHits: 2,
+		},
+		{
+			desc: "different methods",
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+				withMethod(newCredentialsRequest("http://foo.bar/api/v1", "token1"), http.MethodDelete),
+			},
+			expectedHits: 2,
+		},
+		{
+			desc:               "different trusted forwarded URIs",
+			trustForwardHeader: true,
+			requests: []*http.Request{
+				withHeader(newCredentialsRequest("http://foo.bar/", "token1"), xForwardedURI, "/api/v1"),
+				withHeader(newCredentialsRequest("http://foo.bar/", "token1"), xForwardedURI, "/admin/v1"),
+			},
+			expectedHits: 2,
+		},
+		{
+			desc:            "same leading path segments",
+			keyPathSegments: 1,
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+				newCredentialsRequest("http://foo.bar/api/v2?user=2", "token1"),
+			},
+			expectedHits: 1,
+		},
+		{
+			desc: "different credentials",
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+				newCredentialsRequest("http://foo.bar/api/v1", "token2"),
+				newCredentialsRequest("http://foo.bar/api/v1", ""),
+			},
+			expectedHits: 3,
+		},
+		{
+			desc: "different hosts",
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+				newCredentialsRequest("http://bar.foo/api/v1", "token1"),
+			},
+			expectedHits: 2,
+		},
+		{
+			desc:            "different leading path segments",
+			keyPathSegments: 1,
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+				newCredentialsRequest("http://foo.bar/admin/v1", "token1"),
+			},
+			expectedHits: 2,
+		},
+		{
+			desc:         "no-store directive",
+			cacheControl: "private, no-store",
+			requests: []*http.Request{
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+				newCredentialsRequest("http://foo.bar/api/v1", "token1"),
+			},
+			expectedHits: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var hits int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+				if test.cacheControl != "" {
+					w.Header().Set("Cache-Control", test.cacheControl)
+				}
+				w.Header().Set("X-Auth-User", "user@example.com")
+				fmt.Fprintln(w, "Success")
+			}))
+			t.Cleanup(server.Close)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "user@example.com", r.Header.Get("X-Auth-User"))
+				fmt.Fprintln(w, "traefik")
+			})
+
+			auth := dynamic.ForwardAuth{
+				Address:             server.URL,
+				AuthResponseHeaders: []string{"X-Auth-User"},
+				TrustForwardHeader:  test.trustForwardHeader,
+				Cache: &dynamic.ForwardAuthCache{
+					TTL:             ptypes.Duration(time.Minute),
+					MaxEntries:      10,
+					KeyHeaders:      []string{"Authorization"},
+					KeyPathSegments: test.keyPathSegments,
+				},
+			}
+			middleware, err := NewForward(context.Background(), next, auth, "authTest", nil)
+			require.NoError(t, err)
+
+			for _, req := range test.requests {
+				// The client may not forge the headers set from the authentication response.
+				req.Header.Set("X-Auth-User", "forged")
+
+				rw := httptest.NewRecorder()
+				middleware.ServeHTTP(rw, req)
+
+				assert.Equal(t, http.StatusOK, rw.Code)
+				assert.Equal(t, "traefik\n", rw.Body.String())
+			}
+
+			assert.Equal(t, test.expectedHits, atomic.LoadInt32(&hits))
+		})
+	}
+}
+
+func TestForwardAuthCacheExpiry(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		fmt.Fprintln(w, "Success")
+	}))
+	t.Cleanup(server.Close)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "traefik")
+	})
+
+	auth := dynamic.ForwardAuth{
+		Address: server.URL,
+		Cache: &dynamic.ForwardAuthCache{
+			TTL:        ptypes.Duration(100 * time.Millisecond),
+			MaxEntries: 10,
+			KeyHeaders: []string{"Authorization"},
+		},
+	}
+	middleware, err := NewForward(context.Background(), next, auth, "authTest", nil)
+	require.NoError(t, err)
+
+	middleware.ServeHTTP(httptest.NewRecorder(), newCredentialsRequest("http://foo.bar", "token1"))
+	middleware.ServeHTTP(httptest.NewRecorder(), newCredentialsRequest("http://foo.bar", "token1"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	time.Sleep(200 * time.Millisecond)
+
+	middleware.ServeHTTP(httptest.NewRecorder(), newCredentialsRequest("http://foo.bar", "token1"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+}
+
+func TestForwardAuthCacheWithoutKeyHeaders(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	auth := dynamic.ForwardAuth{
+		Address: "http://auth.example.com",
+		Cache: &dynamic.ForwardAuthCache{
+			TTL:        ptypes.Duration(time.Minute),
+			MaxEntries: 10,
+		},
+	}
+	_, err := NewForward(context.Background(), next, auth, "authTest", nil)
+	require.Error(t, err)
+}
+
+func newCredentialsRequest(url, token string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func withMethod(req *http.Request, method string) *http.Request {
+	req.Method = method
+	return req
+}
+
+func withHeader(req *http.Request, name, value string) *http.Request {
+	req.Header.Set(name, value)
+	return req
+}
+
+func TestForwardAuthForwardBody(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		body               string
+		chunked            bool
+		maxBodySize        int64
+		expectedStatusCode int
+		expectedAuthBody   string
+	}{
+		{
+			desc:               "body forwarded",
+			body:               "signed body",
+			maxBodySize:        20,
+			expectedStatusCode: http.StatusOK,
+			expectedAuthBody:   "signed body",
+		},
+		{
+			desc:               "chunked body forwarded",
+			body:               "signed body",
+			chunked:            true,
+			maxBodySize:        20,
+			expectedStatusCode: http.StatusOK,
+			expectedAuthBody:   "signed body",
+		},
+		{
+			desc:               "empty body",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "body too large",
+			body:               "signed body",
+			maxBodySize:        5,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:               "chunked body too large",
+			body:               "signed body",
+			chunked:            true,
+			maxBodySize:        5,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, test.expectedAuthBody, string(body))
+			}))
+			t.Cleanup(server.Close)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, test.body, string(body))
+				assert.Equal(t, int64(len(test.body)), r.ContentLength)
+			})
+
+			middleware, err := NewForward(context.Background(), next, dynamic.ForwardAuth{
+				Address:     server.URL,
+				ForwardBody: true,
+				MaxBodySize: test.maxBodySize,
+			}, "authTest", nil)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com", strings.NewReader(test.body))
+			if test.chunked {
+				req.ContentLength = -1
+			}
+
+			recorder := httptest.NewRecorder()
+			middleware.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+		})
+	}
+}
+
+func TestForwardAuthForwardBodyInvalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.ForwardAuth
+	}{
+		{
+			desc: "negative max body size",
+			config: dynamic.ForwardAuth{
+				Address:     "http://auth.example.com",
+				ForwardBody: true,
+				MaxBodySize: -1,
+			},
+		},
+		{
+			desc: "with cache",
+			config: dynamic.ForwardAuth{
+				Address:     "http://auth.example.com",
+				ForwardBody: true,
+				Cache: &dynamic.ForwardAuthCache{
+					KeyHeaders: []string{"Authorization"},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewForward(context.Background(), http.NotFoundHandler(), test.config, "authTest", nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
 func Test_writeHeader(t *testing.T) {
 	testCases := []struct {
 		name                      string
@@ -467,9 +803,43 @@ func TestForwardAuthUsesTracing(t *testing.T) {
 	tracer := mocktracer.New()
 	opentracing.SetGlobalTracer(tracer)
 
-	tr, _ := tracing.NewTracing("testApp", 100, &mockBackend{tracer})
+	tr, _ := tracing.NewTracing("testApp", 100, &mockBackend{tracer}, nil)
+
+	next, err := NewForward(context.Background(), next, auth, "authTest", nil)
+	require.NoError(t, err)
+
+	next = tracingMiddleware.NewEntryPoint(context.Background(), tr, "tracingTest", next)
+
+	ts := httptest.NewServer(next)
+	t.Cleanup(ts.Close)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestForwardAuthUsesTracing_authRequestHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("Mockpfx-Ids-Traceid"))
+		assert.Equal(t, "foo", r.Header.Get("X-Auth-Foo"))
+		assert.Empty(t, r.Header.Get("X-Bar"))
+	}))
+	t.Cleanup(server.Close)
+
+	next := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	auth := dynamic.ForwardAuth{
+		Address:            server.URL,
+		AuthRequestHeaders: []string{"X-Auth-Foo"},
+	}
+
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+
+	tr, _ := tracing.NewTracing("testApp", 100, &mockBackend{tracer}, nil)
 
-	next, err := NewForward(context.Background(), next, auth, "authTest")
+	next, err := NewForward(context.Background(), next, auth, "authTest", nil)
 	require.NoError(t, err)
 
 	next = tracingMiddleware.NewEntryPoint(context.Background(), tr, "tracingTest", next)
@@ -478,6 +848,9 @@ func TestForwardAuthUsesTracing(t *testing.T) {
 	t.Cleanup(ts.Close)
 
 	req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("X-Auth-Foo", "foo")
+	req.Header.Set("X-Bar", "bar")
+
 	res, err := http.DefaultClient.Do(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, res.StatusCode)
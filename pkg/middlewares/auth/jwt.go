@@ -0,0 +1,392 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	jwtTypeName = "JWT"
+)
+
+// jwksMinRefreshInterval is the minimum interval between two fetches of the JSON Web Key Set,
+// whether they succeeded or not.
+const jwksMinRefreshInterval = time.Minute
+
+type jwtAuth struct {
+	next          http.Handler
+	name          string
+	keys          []interface{}
+	jwks          *jwks
+	issuer        string
+	audiences     []string
+	forwardClaims map[string]string
+	now           func() time.Time
+}
+
+// NewJWT creates a JWT middleware.
+func NewJWT(ctx context.Context, next http.Handler, config dynamic.JWT, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, jwtTypeName)).Debug("Creating middleware")
+
+	if len(config.Keys) == 0 && config.JWKSURL == "" {
+		return nil, errors.New("keys or jwksURL must be set")
+	}
+
+	var keys []interface{}
+	for _, value := range config.Keys {
+		key, err := parseKey(value)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	j := &jwtAuth{
+		next:          next,
+		name:          name,
+		keys:          keys,
+		issuer:        config.Issuer,
+		audiences:     config.Audiences,
+		forwardClaims: config.ForwardClaims,
+		now:           time.Now,
+	}
+
+	if config.JWKSURL != "" {
+		refreshInterval := time.Duration(config.JWKSRefreshInterval)
+		if refreshInterval <= 0 {
+			return nil, fmt.Errorf("invalid jwksRefreshInterval: %s", refreshInterval)
+		}
+
+		j.jwks = &jwks{
+			url:             config.JWKSURL,
+			refreshInterval: refreshInterval,
+			client:          &http.Client{Timeout: 10 * time.Second},
+			now:             time.Now,
+		}
+	}
+
+	return j, nil
+}
+
+// parseKey parses a PEM encoded public key or certificate, or returns the value as an HMAC secret.
+// A value looking like PEM is never used as an HMAC secret,
+// otherwise a public key failing to decode (e.g. with escaped newlines) would let anyone knowing it sign tokens.
+func parseKey(value string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		if value == "" {
+			return nil, errors.New("empty key")
+		}
+		if strings.Contains(value, "-----BEGIN") {
+			return nil, errors.New("invalid PEM encoded key")
+		}
+		return []byte(value), nil
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+	}
+}
+
+func (j *jwtAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return j.name, tracing.SpanKindNoneEnum
+}
+
+func (j *jwtAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := middlewares.GetLoggerCtx(req.Context(), j.name, jwtTypeName)
+	logger := log.FromContext(ctx)
+
+	raw, ok := bearerToken(req)
+	if !ok {
+		logger.Debug("Authentication failed: no bearer token")
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := j.validate(ctx, raw)
+	if err != nil {
+		logger.Debugf("Authentication failed: %v", err)
+		tracing.SetErrorWithEvent(req, "Authentication failed")
+
+		rw.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		if subject, ok := claims["sub"].(string); ok {
+			logData.Core[accesslog.ClientUsername] = subject
+		}
+	}
+
+	for header, claim := range j.forwardClaims {
+		// The clients cannot set the headers of the claims.
+		req.Header.Del(header)
+
+		if value, ok := claimValue(claims[claim]); ok {
+			req.Header.Set(header, value)
+		}
+	}
+
+	j.next.ServeHTTP(rw, req)
+}
+
+// validate verifies the signature and the claims of the token, and returns its claims.
+func (j *jwtAuth) validate(ctx context.Context, raw string) (map[string]interface{}, error) {
+	token, err := josejwt.ParseSigned(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(token.Headers) != 1 {
+		return nil, errors.New("unexpected number of signatures")
+	}
+
+	var registered josejwt.Claims
+	claims := make(map[string]interface{})
+
+	if err = j.verify(ctx, token, &registered, &claims); err != nil {
+		return nil, err
+	}
+
+	if registered.Expiry == nil {
+		return nil, errors.New("missing exp claim")
+	}
+
+	expected := josejwt.Expected{
+		Issuer: j.issuer,
+		Time:   j.now(),
+	}
+
+	if err = registered.Validate(expected); err != nil {
+		return nil, err
+	}
+
+	if len(j.audiences) > 0 && !containsAny(registered.Audience, j.audiences) {
+		return nil, josejwt.ErrInvalidAudience
+	}
+
+	return claims, nil
+}
+
+// verify verifies the signature of the token with the matching keys, and decodes its claims.
+func (j *jwtAuth) verify(ctx context.Context, token *josejwt.JSONWebToken, dest ...interface{}) error {
+	keys := j.keys
+
+	if j.jwks != nil {
+		set, err := j.jwks.keys(ctx, token.Headers[0].KeyID)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range set {
+			keys = append(keys, key.Key)
+		}
+	}
+
+	for _, key := range keys {
+		if err := token.Claims(key, dest...); err == nil {
+			return nil
+		}
+	}
+
+	return errors.New("invalid signature")
+}
+
+func bearerToken(req *http.Request) (string, bool) {
+	authorization := req.Header.Get("Authorization")
+
+	const prefix = "bearer "
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(authorization[len(prefix):])
+
+	return token, token != ""
+}
+
+func containsAny(audience josejwt.Audience, audiences []string) bool {
+	for _, aud := range audiences {
+		if audience.Contains(aud) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// claimValue returns the header value of a claim: the strings as is, and the other values JSON encoded.
+func claimValue(claim interface{}) (string, bool) {
+	switch value := claim.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		return string(data), true
+	}
+}
+
+// jwks is a JSON Web Key Set fetched from a URL, and cached.
+// The key set is fetched again in the background, triggered by the requests, while the cached one is used.
+type jwks struct {
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+	now             func() time.Time
+
+	mu  sync.Mutex
+	set *jose.JSONWebKeySet
+	// err is the error of the last fetch, returned while there is no key set.
+	err error
+	// loaded is the time of the last successful fetch.
+	loaded time.Time
+	// attempted is the time of the last fetch, successful or not.
+	attempted time.Time
+	// fetching is closed when the fetch in progress is done, and is nil if there is none.
+	fetching chan struct{}
+}
+
+// keys returns the keys matching the key ID, or all the keys if the key ID is empty.
+// The key set is fetched again when it is older than the refresh interval, or when the key ID is unknown,
+// at most once per jwksMinRefreshInterval, failed fetches included.
+// Only the requests received while there is no key set yet wait for the fetch.
+func (k *jwks) keys(ctx context.Context, kid string) ([]jose.JSONWebKey, error) {
+	k.mu.Lock()
+
+	if k.set == nil {
+		k.refresh(ctx)
+		fetching := k.fetching
+		k.mu.Unlock()
+
+		if fetching != nil {
+			select {
+			case <-fetching:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		k.mu.Lock()
+	}
+
+	set, err := k.set, k.err
+	if set != nil && k.now().Sub(k.loaded) >= k.refreshInterval {
+		k.refresh(ctx)
+	}
+
+	k.mu.Unlock()
+
+	if set == nil {
+		return nil, err
+	}
+
+	if kid == "" {
+		return set.Keys, nil
+	}
+
+	keys := set.Key(kid)
+	if len(keys) == 0 {
+		// The token is rejected, and the tokens signed with the key are accepted once the key set is fetched again.
+		k.mu.Lock()
+		k.refresh(ctx)
+		k.mu.Unlock()
+	}
+
+	return keys, nil
+}
+
+// refresh fetches the key set in the background, unless a fetch is in progress,
+// or the last one happened less than jwksMinRefreshInterval ago.
+// It must be called with the lock held.
+func (k *jwks) refresh(ctx context.Context) {
+	if k.fetching != nil || (!k.attempted.IsZero() && k.now().Sub(k.attempted) < jwksMinRefreshInterval) {
+		return
+	}
+
+	fetching := make(chan struct{})
+	k.fetching = fetching
+	k.attempted = k.now()
+
+	logger := log.FromContext(ctx)
+
+	safe.Go(func() {
+		defer close(fetching)
+
+		set, err := k.fetch()
+
+		k.mu.Lock()
+		defer k.mu.Unlock()
+
+		k.fetching = nil
+
+		if err != nil {
+			if k.set != nil {
+				logger.Errorf("Unable to refresh the JSON Web Key Set, using the cached one: %v", err)
+			}
+			k.err = err
+			return
+		}
+
+		k.set = set
+		k.err = nil
+		k.loaded = k.now()
+	})
+}
+
+func (k *jwks) fetch() (*jose.JSONWebKeySet, error) {
+	resp, err := k.client.Get(k.url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the JSON Web Key Set: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch the JSON Web Key Set: unexpected status code %d", resp.StatusCode)
+	}
+
+	set := &jose.JSONWebKeySet{}
+	if err := json.NewDecoder(resp.Body).Decode(set); err != nil {
+		return nil, fmt.Errorf("unable to decode the JSON Web Key Set: %w", err)
+	}
+
+	return set, nil
+}
@@ -2,7 +2,10 @@ package buffering
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
@@ -16,17 +19,29 @@ const (
 	typeName = "Buffer"
 )
 
+// errDiskUsageExceeded is returned when spilling a request body to disk would exceed the disk usage allowed to the middleware.
+var errDiskUsageExceeded = errors.New("maximum disk usage of the buffered request bodies reached")
+
 type buffer struct {
 	name   string
 	buffer *oxybuffer.Buffer
+
+	// memRequestBodyBytes is the size above which a request body is spilled to disk.
+	memRequestBodyBytes int64
+	// diskUsage is the disk usage of the request bodies, nil if it is not limited.
+	diskUsage *diskUsage
 }
 
 // New creates a buffering middleware.
 func New(ctx context.Context, next http.Handler, config dynamic.Buffering, name string) (http.Handler, error) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
 	logger.Debug("Creating middleware")
-	logger.Debugf("Setting up buffering: request limits: %d (mem), %d (max), response limits: %d (mem), %d (max) with retry: '%s'",
-		config.MemRequestBodyBytes, config.MaxRequestBodyBytes, config.MemResponseBodyBytes, config.MaxResponseBodyBytes, config.RetryExpression)
+	logger.Debugf("Setting up buffering: request limits: %d (mem), %d (max), %d (disk), response limits: %d (mem), %d (max) with retry: '%s'",
+		config.MemRequestBodyBytes, config.MaxRequestBodyBytes, config.MaxDiskRequestBodyBytes, config.MemResponseBodyBytes, config.MaxResponseBodyBytes, config.RetryExpression)
+
+	if config.MaxDiskRequestBodyBytes < 0 {
+		return nil, errors.New("maxDiskRequestBodyBytes should be >= 0")
+	}
 
 	oxyBuffer, err := oxybuffer.New(
 		next,
@@ -35,15 +50,31 @@ func New(ctx context.Context, next http.Handler, config dynamic.Buffering, name
 		oxybuffer.MemResponseBodyBytes(config.MemResponseBodyBytes),
 		oxybuffer.MaxResponseBodyBytes(config.MaxResponseBodyBytes),
 		oxybuffer.CondSetter(len(config.RetryExpression) > 0, oxybuffer.Retry(config.RetryExpression)),
+		oxybuffer.ErrorHandler(&errorHandler{}),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &buffer{
+	b := &buffer{
 		name:   name,
 		buffer: oxyBuffer,
-	}, nil
+	}
+
+	if config.MaxDiskRequestBodyBytes > 0 {
+		// Mirrors the memory threshold applied by the underlying buffer.
+		b.memRequestBodyBytes = config.MemRequestBodyBytes
+		if b.memRequestBodyBytes == 0 {
+			b.memRequestBodyBytes = oxybuffer.DefaultMemBodyBytes
+		}
+		if config.MaxRequestBodyBytes > 0 && config.MaxRequestBodyBytes < b.memRequestBodyBytes {
+			b.memRequestBodyBytes = config.MaxRequestBodyBytes
+		}
+
+		b.diskUsage = &diskUsage{max: config.MaxDiskRequestBodyBytes}
+	}
+
+	return b, nil
 }
 
 func (b *buffer) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -51,5 +82,76 @@ func (b *buffer) GetTracingInformation() (string, ext.SpanKindEnum) {
 }
 
 func (b *buffer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if b.diskUsage == nil || req.Body == nil || req.Body == http.NoBody {
+		b.buffer.ServeHTTP(rw, req)
+		return
+	}
+
+	body := &diskUsageReader{ReadCloser: req.Body, memBytes: b.memRequestBodyBytes, usage: b.diskUsage}
+	req.Body = body
+
+	// The spilled body is removed from the disk once the request is handled.
+	defer func() { b.diskUsage.release(body.acquired) }()
+
 	b.buffer.ServeHTTP(rw, req)
 }
+
+// diskUsage tracks the number of bytes spilled to disk.
+type diskUsage struct {
+	max  int64
+	used int64
+}
+
+func (d *diskUsage) acquire(n int64) bool {
+	if atomic.AddInt64(&d.used, n) > d.max {
+		atomic.AddInt64(&d.used, -n)
+		return false
+	}
+
+	return true
+}
+
+func (d *diskUsage) release(n int64) {
+	atomic.AddInt64(&d.used, -n)
+}
+
+// diskUsageReader reads a request body, and acquires disk usage for the bytes read beyond the memory threshold,
+// which are spilled to disk.
+type diskUsageReader struct {
+	io.ReadCloser
+	memBytes int64
+	usage    *diskUsage
+
+	read     int64
+	acquired int64
+}
+
+func (r *diskUsageReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.read += int64(n)
+
+	if spilled := r.read - r.memBytes; spilled > r.acquired {
+		if !r.usage.acquire(spilled - r.acquired) {
+			return n, errDiskUsageExceeded
+		}
+		r.acquired = spilled
+	}
+
+	return n, err
+}
+
+// errorHandler answers with a 503 when the disk usage is exceeded, as it is only temporary,
+// and otherwise as the underlying buffer.
+type errorHandler struct {
+	oxybuffer.SizeErrHandler
+}
+
+func (e *errorHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request, err error) {
+	if errors.Is(err, errDiskUsageExceeded) {
+		log.FromContext(req.Context()).Debug(err)
+		http.Error(rw, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	e.SizeErrHandler.ServeHTTP(rw, req, err)
+}
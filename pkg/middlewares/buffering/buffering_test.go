@@ -0,0 +1,92 @@
+package buffering
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestBuffering_maxDiskRequestBodyBytes(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.Buffering
+		body               string
+		expectedStatusCode int
+	}{
+		{
+			desc:               "body in memory",
+			config:             dynamic.Buffering{MemRequestBodyBytes: 10, MaxDiskRequestBodyBytes: 5},
+			body:               strings.Repeat("a", 10),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "body spilled to disk",
+			config:             dynamic.Buffering{MemRequestBodyBytes: 10, MaxDiskRequestBodyBytes: 5},
+			body:               strings.Repeat("a", 15),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "disk usage exceeded",
+			config:             dynamic.Buffering{MemRequestBodyBytes: 10, MaxDiskRequestBodyBytes: 5},
+			body:               strings.Repeat("a", 16),
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			desc:               "no disk usage limit",
+			config:             dynamic.Buffering{MemRequestBodyBytes: 10},
+			body:               strings.Repeat("a", 100),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			desc:               "request body too large",
+			config:             dynamic.Buffering{MemRequestBodyBytes: 10, MaxRequestBodyBytes: 20, MaxDiskRequestBodyBytes: 50},
+			body:               strings.Repeat("a", 30),
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				assert.Equal(t, test.body, string(body))
+
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write([]byte("ok"))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "buffering")
+			require.NoError(t, err)
+
+			// Chunked, so that the size of the body is only known once read.
+			req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader(test.body)))
+			req.ContentLength = -1
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, recorder.Code)
+
+			if b := handler.(*buffer); b.diskUsage != nil {
+				assert.Zero(t, b.diskUsage.used, "disk usage must be released")
+			}
+		})
+	}
+}
+
+func TestBuffering_invalidMaxDiskRequestBodyBytes(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Buffering{MaxDiskRequestBodyBytes: -1}, "buffering")
+	assert.Error(t, err)
+}
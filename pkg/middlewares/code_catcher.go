@@ -0,0 +1,138 @@
+package middlewares
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/vulcand/oxy/utils"
+)
+
+// Compile time validation that the response writer implements http interfaces correctly.
+var _ Stateful = &codeCatcherWithCloseNotify{}
+
+// CodeCatcher is a response writer that detects as soon as possible whether the
+// response is a code within the ranges of codes it watches for. If it is, it
+// drops the response, which is then up to another handler to serve. Otherwise, it forwards it directly to
+// the original response writer without any buffering.
+type CodeCatcher interface {
+	http.ResponseWriter
+	http.Flusher
+	http.Hijacker
+	// GetCode returns the response code, which is a 200 if the next handler did not write it.
+	GetCode() int
+	// IsFilteredCode returns whether the response code is among the ones watched,
+	// in which case nothing has been sent to the client.
+	IsFilteredCode() bool
+}
+
+// NewCodeCatcher creates a CodeCatcher watching for the given ranges of codes.
+func NewCodeCatcher(rw http.ResponseWriter, httpCodeRanges types.HTTPCodeRanges) CodeCatcher {
+	catcher := &codeCatcher{
+		responseWriter: rw,
+		headerMap:      make(http.Header),
+		code:           http.StatusOK, // If the next handler does not call WriteHeader on us, we consider it's a 200.
+		httpCodeRanges: httpCodeRanges,
+	}
+	if _, ok := rw.(http.CloseNotifier); ok {
+		return &codeCatcherWithCloseNotify{catcher}
+	}
+	return catcher
+}
+
+type codeCatcher struct {
+	responseWriter     http.ResponseWriter
+	headerMap          http.Header
+	code               int
+	httpCodeRanges     types.HTTPCodeRanges
+	caughtFilteredCode bool
+	headersSent        bool
+	hijacked           bool
+}
+
+type codeCatcherWithCloseNotify struct {
+	*codeCatcher
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone away.
+func (cc *codeCatcherWithCloseNotify) CloseNotify() <-chan bool {
+	return cc.responseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (cc *codeCatcher) Header() http.Header {
+	if cc.headersSent {
+		return cc.responseWriter.Header()
+	}
+
+	return cc.headerMap
+}
+
+func (cc *codeCatcher) GetCode() int {
+	return cc.code
+}
+
+func (cc *codeCatcher) IsFilteredCode() bool {
+	return cc.caughtFilteredCode
+}
+
+func (cc *codeCatcher) Write(buf []byte) (int, error) {
+	// If WriteHeader was already called from the caller, this is a NOOP.
+	// Otherwise, cc.code is actually a 200 here.
+	cc.WriteHeader(cc.code)
+
+	if cc.caughtFilteredCode {
+		// We don't care about the contents of the response,
+		// since it is replaced by the one of another handler,
+		// so we just drop them.
+		return len(buf), nil
+	}
+
+	return cc.responseWriter.Write(buf)
+}
+
+func (cc *codeCatcher) WriteHeader(code int) {
+	if cc.headersSent || cc.caughtFilteredCode || cc.hijacked {
+		return
+	}
+
+	cc.code = code
+	for _, block := range cc.httpCodeRanges {
+		if cc.code >= block[0] && cc.code <= block[1] {
+			// It will be up to the other handler to send the headers,
+			// so it is out of our hands now.
+			cc.caughtFilteredCode = true
+			return
+		}
+	}
+
+	utils.CopyHeaders(cc.responseWriter.Header(), cc.headerMap)
+	cc.responseWriter.WriteHeader(cc.code)
+	cc.headersSent = true
+}
+
+// Hijack hijacks the connection.
+func (cc *codeCatcher) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hj, ok := cc.responseWriter.(http.Hijacker); ok {
+		cc.hijacked = true
+		return hj.Hijack()
+	}
+	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", cc.responseWriter)
+}
+
+// Flush sends any buffered data to the client.
+func (cc *codeCatcher) Flush() {
+	// If WriteHeader was already called from the caller, this is a NOOP.
+	// Otherwise, cc.code is actually a 200 here.
+	cc.WriteHeader(cc.code)
+
+	if cc.caughtFilteredCode {
+		return
+	}
+
+	if flusher, ok := cc.responseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestCodeCatcher(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		code             int
+		flush            bool
+		expectedFiltered bool
+		expectedCode     int
+		expectedBody     string
+		expectedHeader   string
+	}{
+		{
+			desc:           "implicit 200",
+			expectedCode:   http.StatusOK,
+			expectedBody:   "content",
+			expectedHeader: "value",
+		},
+		{
+			desc:           "code out of the ranges",
+			code:           http.StatusNotFound,
+			expectedCode:   http.StatusNotFound,
+			expectedBody:   "content",
+			expectedHeader: "value",
+		},
+		{
+			desc:             "code within the ranges",
+			code:             http.StatusServiceUnavailable,
+			expectedFiltered: true,
+			expectedCode:     http.StatusOK,
+		},
+		{
+			desc:             "flushed code within the ranges",
+			code:             http.StatusServiceUnavailable,
+			flush:            true,
+			expectedFiltered: true,
+			expectedCode:     http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			httpCodeRanges, err := types.NewHTTPCodeRanges([]string{"500-599"})
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			catcher := NewCodeCatcher(recorder, httpCodeRanges)
+
+			catcher.Header().Set("X-Test", "value")
+			if test.code != 0 {
+				catcher.WriteHeader(test.code)
+			}
+			if test.flush {
+				catcher.Flush()
+			}
+			_, err = catcher.Write([]byte("content"))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedFiltered, catcher.IsFilteredCode())
+			if test.code != 0 {
+				assert.Equal(t, test.code, catcher.GetCode())
+			}
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, test.expectedHeader, recorder.Header().Get("X-Test"))
+			assert.Equal(t, !test.expectedFiltered && test.flush, recorder.Flushed)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package compress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	brotliEncoding = "br"
+	gzipEncoding   = "gzip"
+)
+
+// brotliResponseWriter compresses the response with brotli,
+// once enough bytes have been written to reach the minimum size.
+// Responses already encoded by the backend, and the responses whose content type is not compressible, are passed through.
+type brotliResponseWriter struct {
+	rw           http.ResponseWriter
+	bw           *brotli.Writer
+	minSize      int
+	compressible func(contentType string) bool
+
+	buf        []byte
+	statusCode int
+
+	compressionStarted  bool
+	compressionDisabled bool
+}
+
+func (r *brotliResponseWriter) Header() http.Header {
+	return r.rw.Header()
+}
+
+// WriteHeader saves the status code until the compression is started or disabled.
+func (r *brotliResponseWriter) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+}
+
+func (r *brotliResponseWriter) Write(p []byte) (int, error) {
+	if r.compressionStarted {
+		return r.bw.Write(p)
+	}
+
+	if r.compressionDisabled {
+		return r.rw.Write(p)
+	}
+
+	r.buf = append(r.buf, p...)
+
+	contentLength, _ := strconv.Atoi(r.Header().Get("Content-Length"))
+	if !r.canCompress() || (contentLength > 0 && contentLength < r.minSize) {
+		return len(p), r.startPlain()
+	}
+
+	// Waits for more data until the minimum size is reached, unless the announced length reaches it.
+	if len(r.buf) < r.minSize && contentLength == 0 {
+		return len(p), nil
+	}
+
+	if r.Header().Get("Content-Type") == "" {
+		r.Header().Set("Content-Type", http.DetectContentType(r.buf))
+
+		if !r.canCompress() {
+			return len(p), r.startPlain()
+		}
+	}
+
+	return len(p), r.startCompression()
+}
+
+// canCompress tells whether the response is neither already encoded, nor of an excluded content type.
+func (r *brotliResponseWriter) canCompress() bool {
+	if r.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := r.Header().Get("Content-Type")
+	return contentType == "" || r.compressible(contentType)
+}
+
+func (r *brotliResponseWriter) startCompression() error {
+	r.compressionStarted = true
+
+	r.Header().Set("Content-Encoding", brotliEncoding)
+	// The length of the compressed response is unknown.
+	r.Header().Del("Content-Length")
+	r.writeHeader()
+
+	r.bw = brotli.NewWriterLevel(r.rw, brotli.DefaultCompression)
+
+	if len(r.buf) == 0 {
+		return nil
+	}
+
+	n, err := r.bw.Write(r.buf)
+	if err == nil && n < len(r.buf) {
+		err = io.ErrShortWrite
+	}
+	r.buf = nil
+
+	return err
+}
+
+func (r *brotliResponseWriter) startPlain() error {
+	r.compressionDisabled = true
+
+	r.writeHeader()
+
+	if len(r.buf) == 0 {
+		return nil
+	}
+
+	n, err := r.rw.Write(r.buf)
+	if err == nil && n < len(r.buf) {
+		err = io.ErrShortWrite
+	}
+	r.buf = nil
+
+	return err
+}
+
+func (r *brotliResponseWriter) writeHeader() {
+	if r.statusCode != 0 {
+		r.rw.WriteHeader(r.statusCode)
+	}
+}
+
+// Flush starts the compression, or disables it, before flushing the response,
+// as the minimum size cannot be awaited anymore.
+func (r *brotliResponseWriter) Flush() {
+	if !r.compressionStarted && !r.compressionDisabled {
+		var err error
+		if r.canCompress() && len(r.buf) > 0 {
+			err = r.startCompression()
+		} else {
+			err = r.startPlain()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	if r.compressionStarted {
+		if err := r.bw.Flush(); err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := r.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *brotliResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.rw)
+	}
+
+	return hijacker.Hijack()
+}
+
+// close writes the buffered response, and ends the brotli stream.
+func (r *brotliResponseWriter) close() error {
+	if r.compressionStarted {
+		return r.bw.Close()
+	}
+
+	if !r.compressionDisabled {
+		return r.startPlain()
+	}
+
+	return nil
+}
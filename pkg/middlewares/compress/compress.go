@@ -3,8 +3,12 @@ package compress
 import (
 	"compress/gzip"
 	"context"
+	"errors"
+	"fmt"
 	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/gziphandler"
@@ -23,12 +27,18 @@ type compress struct {
 	next     http.Handler
 	name     string
 	excludes []string
+	includes []string
+	minSize  int
 }
 
 // New creates a new compress middleware.
 func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name string) (http.Handler, error) {
 	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
 
+	if len(conf.ExcludedContentTypes) > 0 && len(conf.IncludedContentTypes) > 0 {
+		return nil, errors.New("excludedContentTypes and includedContentTypes options are mutually exclusive")
+	}
+
 	excludes := []string{"application/grpc"}
 	for _, v := range conf.ExcludedContentTypes {
 		mediaType, _, err := mime.ParseMediaType(v)
@@ -39,7 +49,27 @@ func New(ctx context.Context, next http.Handler, conf dynamic.Compress, name str
 		excludes = append(excludes, mediaType)
 	}
 
-	return &compress{next: next, name: name, excludes: excludes}, nil
+	var includes []string
+	for _, v := range conf.IncludedContentTypes {
+		mediaType, _, err := mime.ParseMediaType(v)
+		if err != nil {
+			return nil, err
+		}
+
+		includes = append(includes, mediaType)
+	}
+
+	if conf.MinResponseBodyBytes < 0 {
+		return nil, fmt.Errorf("minResponseBodyBytes must be greater than or equal to zero: %d", conf.MinResponseBodyBytes)
+	}
+
+	return &compress{
+		next:     next,
+		name:     name,
+		excludes: excludes,
+		includes: includes,
+		minSize:  conf.MinResponseBodyBytes,
+	}, nil
 }
 
 func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -50,10 +80,17 @@ func (c *compress) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 	if contains(c.excludes, mediaType) {
 		c.next.ServeHTTP(rw, req)
-	} else {
-		ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
-		c.gzipHandler(ctx).ServeHTTP(rw, req)
+		return
+	}
+
+	ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
+
+	if acceptsBrotli(req.Header.Values("Accept-Encoding")) {
+		c.brotliHandler(ctx).ServeHTTP(rw, req)
+		return
 	}
+
+	c.gzipHandler(ctx).ServeHTTP(rw, req)
 }
 
 func (c *compress) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -61,10 +98,15 @@ func (c *compress) GetTracingInformation() (string, ext.SpanKindEnum) {
 }
 
 func (c *compress) gzipHandler(ctx context.Context) http.Handler {
+	contentTypes := gziphandler.ContentTypeExceptions(c.excludes)
+	if len(c.includes) > 0 {
+		contentTypes = gziphandler.ContentTypes(c.includes)
+	}
+
 	wrapper, err := gziphandler.GzipHandlerWithOpts(
-		gziphandler.ContentTypeExceptions(c.excludes),
+		contentTypes,
 		gziphandler.CompressionLevel(gzip.DefaultCompression),
-		gziphandler.MinSize(gziphandler.DefaultMinSize))
+		gziphandler.MinSize(c.getMinSize()))
 	if err != nil {
 		log.FromContext(ctx).Error(err)
 	}
@@ -72,6 +114,89 @@ func (c *compress) gzipHandler(ctx context.Context) http.Handler {
 	return wrapper(c.next)
 }
 
+func (c *compress) brotliHandler(ctx context.Context) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Add("Vary", "Accept-Encoding")
+
+		brw := &brotliResponseWriter{
+			rw:           rw,
+			minSize:      c.getMinSize(),
+			compressible: c.isCompressible,
+		}
+
+		c.next.ServeHTTP(brw, req)
+
+		if err := brw.close(); err != nil {
+			log.FromContext(ctx).Error(err)
+		}
+	})
+}
+
+func (c *compress) getMinSize() int {
+	if c.minSize > 0 {
+		return c.minSize
+	}
+
+	return gziphandler.DefaultMinSize
+}
+
+// isCompressible tells whether a response of the given content type should be compressed.
+func (c *compress) isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	if len(c.includes) > 0 {
+		return contains(c.includes, mediaType)
+	}
+
+	return !contains(c.excludes, mediaType)
+}
+
+// acceptsBrotli tells whether brotli is the preferred encoding among the ones accepted by the client,
+// brotli being chosen over gzip when both are equally accepted.
+func acceptsBrotli(acceptEncoding []string) bool {
+	var brotliQValue, gzipQValue float64
+	for _, value := range acceptEncoding {
+		for _, coding := range strings.Split(value, ",") {
+			name, qValue := parseCoding(coding)
+
+			switch name {
+			case brotliEncoding:
+				brotliQValue = qValue
+			case gzipEncoding:
+				gzipQValue = qValue
+			}
+		}
+	}
+
+	return brotliQValue > 0 && brotliQValue >= gzipQValue
+}
+
+// parseCoding parses a content-coding, and its optional quality value, from the Accept-Encoding header.
+func parseCoding(coding string) (string, float64) {
+	parts := strings.Split(coding, ";")
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+
+	qValue := 1.0
+	for _, param := range parts[1:] {
+		param = strings.TrimSpace(param)
+		if !strings.HasPrefix(param, "q=") {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+		if err != nil {
+			return name, 0
+		}
+
+		qValue = value
+	}
+
+	return name, qValue
+}
+
 func contains(values []string, val string) bool {
 	for _, v := range values {
 		if v == val {
@@ -1,12 +1,14 @@
 package compress
 
 import (
+	"compress/gzip"
 	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/andybalholm/brotli"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/gziphandler"
@@ -20,6 +22,7 @@ const (
 	contentTypeHeader     = "Content-Type"
 	varyHeader            = "Vary"
 	gzipValue             = "gzip"
+	brotliValue           = "br"
 )
 
 func TestShouldCompressWhenNoContentEncodingHeader(t *testing.T) {
@@ -296,6 +299,270 @@ func TestIntegrationShouldCompress(t *testing.T) {
 	}
 }
 
+func TestNewCompress(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		conf          dynamic.Compress
+		expectedError bool
+	}{
+		{
+			desc: "empty configuration",
+		},
+		{
+			desc: "included content types and minimum size",
+			conf: dynamic.Compress{
+				IncludedContentTypes: []string{"text/html", "application/json"},
+				MinResponseBodyBytes: 512,
+			},
+		},
+		{
+			desc: "both included and excluded content types",
+			conf: dynamic.Compress{
+				ExcludedContentTypes: []string{"text/event-stream"},
+				IncludedContentTypes: []string{"text/html"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid included content type",
+			conf: dynamic.Compress{
+				IncludedContentTypes: []string{"text/html; charset"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "negative minimum size",
+			conf: dynamic.Compress{
+				MinResponseBodyBytes: -1,
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := New(context.Background(), next, test.conf, "test")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCompress_encodings(t *testing.T) {
+	baseBody := generateBytes(gziphandler.DefaultMinSize)
+
+	testCases := []struct {
+		desc             string
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{
+			desc:             "brotli",
+			acceptEncoding:   "br",
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:             "gzip",
+			acceptEncoding:   "gzip",
+			expectedEncoding: gzipValue,
+		},
+		{
+			desc:             "brotli preferred over gzip",
+			acceptEncoding:   "gzip, deflate, br",
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:             "gzip with a higher quality value",
+			acceptEncoding:   "br;q=0.5, gzip",
+			expectedEncoding: gzipValue,
+		},
+		{
+			desc:             "brotli refused",
+			acceptEncoding:   "br;q=0",
+			expectedEncoding: "",
+		},
+		{
+			desc:             "unsupported encoding",
+			acceptEncoding:   "deflate",
+			expectedEncoding: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, err := rw.Write(baseBody)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, test.acceptEncoding)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedEncoding, rw.Header().Get(contentEncodingHeader))
+			assert.Equal(t, acceptEncodingHeader, rw.Header().Get(varyHeader))
+
+			var reader io.Reader = rw.Body
+			switch test.expectedEncoding {
+			case brotliValue:
+				reader = brotli.NewReader(rw.Body)
+			case gzipValue:
+				reader, err = gzip.NewReader(rw.Body)
+				require.NoError(t, err)
+			}
+
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, baseBody, body)
+		})
+	}
+}
+
+func TestCompress_brotli(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		conf             dynamic.Compress
+		contentEncoding  string
+		contentType      string
+		bodySize         int
+		expectedEncoding string
+	}{
+		{
+			desc:             "body larger than the default minimum size",
+			bodySize:         gziphandler.DefaultMinSize,
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:     "body smaller than the default minimum size",
+			bodySize: gziphandler.DefaultMinSize - 1,
+		},
+		{
+			desc:             "body larger than the configured minimum size",
+			conf:             dynamic.Compress{MinResponseBodyBytes: 10},
+			bodySize:         10,
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:            "precompressed body",
+			contentEncoding: brotliValue,
+			bodySize:        gziphandler.DefaultMinSize,
+			// The encoding set by the backend is kept as is.
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:        "excluded content type",
+			conf:        dynamic.Compress{ExcludedContentTypes: []string{"text/event-stream"}},
+			contentType: "text/event-stream",
+			bodySize:    gziphandler.DefaultMinSize,
+		},
+		{
+			desc:             "included content type",
+			conf:             dynamic.Compress{IncludedContentTypes: []string{"application/json"}},
+			contentType:      "application/json; charset=utf-8",
+			bodySize:         gziphandler.DefaultMinSize,
+			expectedEncoding: brotliValue,
+		},
+		{
+			desc:        "not included content type",
+			conf:        dynamic.Compress{IncludedContentTypes: []string{"application/json"}},
+			contentType: "text/html",
+			bodySize:    gziphandler.DefaultMinSize,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			baseBody := generateBytes(test.bodySize)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if test.contentEncoding != "" {
+					rw.Header().Set(contentEncodingHeader, test.contentEncoding)
+				}
+				if test.contentType != "" {
+					rw.Header().Set(contentTypeHeader, test.contentType)
+				}
+
+				rw.WriteHeader(http.StatusCreated)
+				_, err := rw.Write(baseBody)
+				assert.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, test.conf, "test")
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://localhost", nil)
+			req.Header.Set(acceptEncodingHeader, brotliValue)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, http.StatusCreated, rw.Code)
+			assert.Equal(t, test.expectedEncoding, rw.Header().Get(contentEncodingHeader))
+
+			if test.expectedEncoding == "" || test.contentEncoding != "" {
+				assert.Equal(t, baseBody, rw.Body.Bytes())
+				return
+			}
+
+			body, err := io.ReadAll(brotli.NewReader(rw.Body))
+			require.NoError(t, err)
+			assert.Equal(t, baseBody, body)
+		})
+	}
+}
+
+func TestCompress_brotliFlush(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set(contentTypeHeader, "text/plain")
+		rw.WriteHeader(http.StatusOK)
+
+		_, err := rw.Write([]byte("first chunk"))
+		assert.NoError(t, err)
+		rw.(http.Flusher).Flush()
+
+		_, err = rw.Write([]byte(" and second chunk"))
+		assert.NoError(t, err)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Compress{}, "test")
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	req := testhelpers.MustNewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set(acceptEncodingHeader, brotliValue)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, brotliValue, resp.Header.Get(contentEncodingHeader))
+
+	body, err := io.ReadAll(brotli.NewReader(resp.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "first chunk and second chunk", string(body))
+}
+
 func generateBytes(length int) []byte {
 	var value []byte
 	for i := 0; i < length; i++ {
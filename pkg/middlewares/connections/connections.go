@@ -0,0 +1,185 @@
+package connections
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/udp"
+)
+
+const (
+	protocolTCP = "tcp"
+	protocolUDP = "udp"
+)
+
+// Observer writes the TCP connections and the UDP sessions of the routers to the access log,
+// and records their metrics, when they are closed.
+type Observer struct {
+	accessLog *accesslog.Handler
+	registry  metrics.Registry
+}
+
+// NewObserver creates a new Observer.
+// The access log and the metrics registry are optional.
+func NewObserver(accessLog *accesslog.Handler, registry metrics.Registry) *Observer {
+	return &Observer{
+		accessLog: accessLog,
+		registry:  registry,
+	}
+}
+
+// Enabled returns whether the connections are either logged or measured.
+func (o *Observer) Enabled() bool {
+	return o != nil && (o.logsConnections() || o.measuresConnections())
+}
+
+func (o *Observer) logsConnections() bool {
+	return o.accessLog != nil && o.accessLog.LogsConnections()
+}
+
+func (o *Observer) measuresConnections() bool {
+	return o.registry != nil && o.registry.IsRouterEnabled() && o.registry.RouterConnsCounter() != nil
+}
+
+// WrapTCP returns a handler observing the connections of the given router, forwarded to the given service handler.
+func (o *Observer) WrapTCP(routerName, serviceName string, next tcp.Handler) tcp.Handler {
+	if !o.Enabled() {
+		return next
+	}
+
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		start := time.Now()
+
+		observed := &observedConn{WriteCloser: conn}
+		next.ServeTCP(observed)
+
+		o.record(connection{
+			protocol:    protocolTCP,
+			routerName:  routerName,
+			serviceName: serviceName,
+			clientAddr:  conn.RemoteAddr(),
+			serverAddr:  observed.serverAddr(),
+			start:       start,
+			bytesIn:     atomic.LoadInt64(&observed.bytesIn),
+			bytesOut:    atomic.LoadInt64(&observed.bytesOut),
+		})
+	})
+}
+
+// WrapUDP returns a handler observing the sessions of the given router, forwarded to the given service handler.
+func (o *Observer) WrapUDP(routerName, serviceName string, next udp.Handler) udp.Handler {
+	if !o.Enabled() {
+		return next
+	}
+
+	return udp.HandlerFunc(func(conn *udp.Conn) {
+		start := time.Now()
+
+		next.ServeUDP(conn)
+
+		o.record(connection{
+			protocol:    protocolUDP,
+			routerName:  routerName,
+			serviceName: serviceName,
+			clientAddr:  conn.RemoteAddr(),
+			serverAddr:  conn.ServerAddr(),
+			start:       start,
+			bytesIn:     conn.BytesIn(),
+			bytesOut:    conn.BytesOut(),
+		})
+	})
+}
+
+// connection is a closed TCP connection or UDP session.
+type connection struct {
+	protocol    string
+	routerName  string
+	serviceName string
+	clientAddr  net.Addr
+	serverAddr  string
+	start       time.Time
+	bytesIn     int64
+	bytesOut    int64
+}
+
+func (o *Observer) record(c connection) {
+	if o.measuresConnections() {
+		labels := []string{"protocol", c.protocol, "router", c.routerName, "service", c.serviceName}
+
+		o.registry.RouterConnsCounter().With(labels...).Add(1)
+		o.registry.RouterConnDurationHistogram().With(labels...).ObserveFromStart(c.start)
+		o.registry.RouterConnBytesCounter().With(append(labels, "direction", "in")...).Add(float64(c.bytesIn))
+		o.registry.RouterConnBytesCounter().With(append(labels, "direction", "out")...).Add(float64(c.bytesOut))
+	}
+
+	if o.logsConnections() {
+		core := accesslog.CoreLogData{
+			accesslog.StartUTC:           c.start.UTC(),
+			accesslog.StartLocal:         c.start.Local(),
+			accesslog.ConnectionProtocol: c.protocol,
+			accesslog.RouterName:         c.routerName,
+			accesslog.ServiceName:        c.serviceName,
+			accesslog.ConnectionBytesIn:  c.bytesIn,
+			accesslog.ConnectionBytesOut: c.bytesOut,
+		}
+
+		if c.clientAddr != nil {
+			core[accesslog.ClientAddr] = c.clientAddr.String()
+		}
+
+		if c.serverAddr != "" {
+			core[accesslog.ServiceAddr] = c.serverAddr
+		}
+
+		o.accessLog.LogConnection(core)
+	}
+}
+
+// observedConn counts the bytes received from and sent to the client,
+// and records the address of the server the connection is forwarded to.
+type observedConn struct {
+	// bytesIn and bytesOut are accessed atomically.
+	bytesIn  int64
+	bytesOut int64
+
+	tcp.WriteCloser
+
+	mu     sync.Mutex
+	server string
+}
+
+func (c *observedConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	atomic.AddInt64(&c.bytesIn, int64(n))
+	return n, err
+}
+
+func (c *observedConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	atomic.AddInt64(&c.bytesOut, int64(n))
+	return n, err
+}
+
+// SetServerAddr records the address of the server the connection is forwarded to.
+func (c *observedConn) SetServerAddr(addr string) {
+	c.mu.Lock()
+	c.server = addr
+	c.mu.Unlock()
+}
+
+// Unwrap returns the wrapped connection.
+func (c *observedConn) Unwrap() tcp.WriteCloser {
+	return c.WriteCloser
+}
+
+func (c *observedConn) serverAddr() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.server
+}
@@ -0,0 +1,327 @@
+package connections
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/traefik/traefik/v2/pkg/udp"
+)
+
+func TestObserver_Enabled(t *testing.T) {
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+
+	disabledLog, err := accesslog.NewHandler(&types.AccessLog{FilePath: logFilePath, Format: accesslog.JSONFormat})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = disabledLog.Close() })
+
+	enabledLog, err := accesslog.NewHandler(&types.AccessLog{FilePath: logFilePath, Format: accesslog.JSONFormat, Connections: true})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = enabledLog.Close() })
+
+	testCases := []struct {
+		desc     string
+		observer *Observer
+		expected bool
+	}{
+		{
+			desc: "nil observer",
+		},
+		{
+			desc:     "no access log and no metrics",
+			observer: NewObserver(nil, metrics.NewVoidRegistry()),
+		},
+		{
+			desc:     "connections not logged",
+			observer: NewObserver(disabledLog, nil),
+		},
+		{
+			desc:     "connections logged",
+			observer: NewObserver(enabledLog, nil),
+			expected: true,
+		},
+		{
+			desc:     "routers metrics",
+			observer: NewObserver(nil, newCollectingRegistry()),
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.observer.Enabled())
+		})
+	}
+}
+
+func TestObserver_WrapTCP(t *testing.T) {
+	backend, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	go func() {
+		conn, err := backend.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		b := make([]byte, 4)
+		if _, err := io.ReadFull(conn, b); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("PONG PONG"))
+	}()
+
+	proxy, err := tcp.NewProxy(backend.Addr().String(), 0, nil)
+	require.NoError(t, err)
+
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+	accessLog, err := accesslog.NewHandler(&types.AccessLog{FilePath: logFilePath, Format: accesslog.JSONFormat, Connections: true})
+	require.NoError(t, err)
+
+	registry := newCollectingRegistry()
+	handler := NewObserver(accessLog, registry).WrapTCP("db@file", "db@file", proxy)
+
+	frontend, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = frontend.Close() })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		conn, err := frontend.Accept()
+		if err != nil {
+			return
+		}
+
+		handler.ServeTCP(conn.(*net.TCPConn))
+	}()
+
+	client, err := net.Dial("tcp", frontend.Addr().String())
+	require.NoError(t, err)
+
+	_, err = client.Write([]byte("PING"))
+	require.NoError(t, err)
+
+	response, err := io.ReadAll(client)
+	require.NoError(t, err)
+	assert.Equal(t, "PONG PONG", string(response))
+	require.NoError(t, client.Close())
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the connection to be closed")
+	}
+
+	require.NoError(t, accessLog.Close())
+
+	fields := readLogFields(t, logFilePath)
+	assert.Equal(t, "tcp", fields[accesslog.ConnectionProtocol])
+	assert.Equal(t, "db@file", fields[accesslog.RouterName])
+	assert.Equal(t, "db@file", fields[accesslog.ServiceName])
+	assert.Equal(t, backend.Addr().String(), fields[accesslog.ServiceAddr])
+	assert.Equal(t, client.LocalAddr().String(), fields[accesslog.ClientAddr])
+	assert.Equal(t, float64(4), fields[accesslog.ConnectionBytesIn])
+	assert.Equal(t, float64(9), fields[accesslog.ConnectionBytesOut])
+
+	labels := "protocol,tcp,router,db@file,service,db@file"
+	assert.Equal(t, map[string]float64{labels: 1}, registry.conns.values())
+	assert.Equal(t, map[string]float64{labels: 1}, registry.durations.values())
+	assert.Equal(t, map[string]float64{labels + ",direction,in": 4, labels + ",direction,out": 9}, registry.bytes.values())
+}
+
+func TestObserver_WrapUDP(t *testing.T) {
+	backend, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backend.Close() })
+
+	go func() {
+		b := make([]byte, 1024)
+		for {
+			n, addr, err := backend.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			_, _ = backend.WriteTo([]byte(strings.Repeat(string(b[:n]), 2)), addr)
+		}
+	}()
+
+	proxy, err := udp.NewProxy(backend.LocalAddr().String(), nil, false)
+	require.NoError(t, err)
+
+	logFilePath := filepath.Join(t.TempDir(), "access.log")
+	accessLog, err := accesslog.NewHandler(&types.AccessLog{FilePath: logFilePath, Format: accesslog.JSONFormat, Connections: true})
+	require.NoError(t, err)
+
+	registry := newCollectingRegistry()
+	handler := NewObserver(accessLog, registry).WrapUDP("dns@file", "dns@file", proxy)
+
+	listener, err := udp.Listen(net.ListenConfig{}, "udp", "127.0.0.1:0", 500*time.Millisecond, false)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		handler.ServeUDP(conn)
+	}()
+
+	client, err := net.Dial("udp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err = client.Write([]byte("PING"))
+	require.NoError(t, err)
+
+	b := make([]byte, 1024)
+	n, err := client.Read(b)
+	require.NoError(t, err)
+	assert.Equal(t, "PINGPING", string(b[:n]))
+
+	// The session is closed once it times out.
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the session to be closed")
+	}
+
+	require.NoError(t, accessLog.Close())
+
+	fields := readLogFields(t, logFilePath)
+	assert.Equal(t, "udp", fields[accesslog.ConnectionProtocol])
+	assert.Equal(t, "dns@file", fields[accesslog.RouterName])
+	assert.Equal(t, backend.LocalAddr().String(), fields[accesslog.ServiceAddr])
+	assert.Equal(t, client.LocalAddr().String(), fields[accesslog.ClientAddr])
+	assert.Equal(t, float64(4), fields[accesslog.ConnectionBytesIn])
+	assert.Equal(t, float64(8), fields[accesslog.ConnectionBytesOut])
+
+	labels := "protocol,udp,router,dns@file,service,dns@file"
+	assert.Equal(t, map[string]float64{labels: 1}, registry.conns.values())
+	assert.Equal(t, map[string]float64{labels + ",direction,in": 4, labels + ",direction,out": 8}, registry.bytes.values())
+}
+
+func readLogFields(t *testing.T, logFilePath string) map[string]interface{} {
+	t.Helper()
+
+	logData, err := os.ReadFile(logFilePath)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(logData, &fields))
+
+	return fields
+}
+
+// collectingRegistry is a metrics.Registry collecting the connections metrics of the routers.
+type collectingRegistry struct {
+	metrics.Registry
+
+	conns     *collectingCounter
+	durations *collectingHistogram
+	bytes     *collectingCounter
+}
+
+func newCollectingRegistry() *collectingRegistry {
+	return &collectingRegistry{
+		Registry:  metrics.NewVoidRegistry(),
+		conns:     newCollectingCounter(),
+		durations: &collectingHistogram{counter: newCollectingCounter()},
+		bytes:     newCollectingCounter(),
+	}
+}
+
+func (r *collectingRegistry) IsRouterEnabled() bool {
+	return true
+}
+
+func (r *collectingRegistry) RouterConnsCounter() gokitmetrics.Counter {
+	return r.conns
+}
+
+func (r *collectingRegistry) RouterConnDurationHistogram() metrics.ScalableHistogram {
+	return r.durations
+}
+
+func (r *collectingRegistry) RouterConnBytesCounter() gokitmetrics.Counter {
+	return r.bytes
+}
+
+// collectingCounter sums the values added by label values.
+type collectingCounter struct {
+	mu     *sync.Mutex
+	sums   map[string]float64
+	labels string
+}
+
+func newCollectingCounter() *collectingCounter {
+	return &collectingCounter{mu: &sync.Mutex{}, sums: make(map[string]float64)}
+}
+
+func (c *collectingCounter) With(labelValues ...string) gokitmetrics.Counter {
+	return &collectingCounter{mu: c.mu, sums: c.sums, labels: strings.Join(labelValues, ",")}
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sums[c.labels] += delta
+}
+
+func (c *collectingCounter) values() map[string]float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	values := make(map[string]float64)
+	for labels, sum := range c.sums {
+		values[labels] = sum
+	}
+
+	return values
+}
+
+// collectingHistogram counts the observations by label values.
+type collectingHistogram struct {
+	counter *collectingCounter
+}
+
+func (h *collectingHistogram) With(labelValues ...string) metrics.ScalableHistogram {
+	return &collectingHistogram{counter: h.counter.With(labelValues...).(*collectingCounter)}
+}
+
+func (h *collectingHistogram) Observe(float64) {
+	h.counter.Add(1)
+}
+
+func (h *collectingHistogram) ObserveFromStart(time.Time) {
+	h.counter.Add(1)
+}
+
+func (h *collectingHistogram) values() map[string]float64 {
+	return h.counter.values()
+}
@@ -4,34 +4,44 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
 	"net"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	texttemplate "text/template"
+	"time"
 
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/sirupsen/logrus"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/traefik/traefik/v2/pkg/types"
 	"github.com/vulcand/oxy/utils"
 )
 
 // Compile time validation that the response recorder implements http interfaces correctly.
-var (
-	_ middlewares.Stateful = &responseRecorderWithCloseNotify{}
-	_ middlewares.Stateful = &codeCatcherWithCloseNotify{}
-)
+var _ middlewares.Stateful = &responseRecorderWithCloseNotify{}
 
 const (
 	typeName   = "customError"
 	backendURL = "http://0.0.0.0"
 )
 
+const (
+	defaultContentType = "text/html; charset=utf-8"
+	requestIDHeader    = "X-Request-Id"
+)
+
 type serviceBuilder interface {
 	BuildHTTP(ctx context.Context, serviceName string) (http.Handler, error)
 }
@@ -43,6 +53,12 @@ type customErrors struct {
 	backendHandler http.Handler
 	httpCodeRanges types.HTTPCodeRanges
 	backendQuery   string
+
+	template    pageTemplate
+	contentType string
+
+	retryAttempts        int
+	retryInitialInterval time.Duration
 }
 
 // New creates a new custom error pages middleware.
@@ -54,18 +70,44 @@ func New(ctx context.Context, next http.Handler, config dynamic.ErrorPage, servi
 		return nil, err
 	}
 
-	backend, err := serviceBuilder.BuildHTTP(ctx, config.Service)
-	if err != nil {
-		return nil, err
+	if config.Retry != nil && config.Retry.Attempts <= 0 {
+		return nil, fmt.Errorf("incorrect (or empty) value for retry attempts (%d)", config.Retry.Attempts)
 	}
 
-	return &customErrors{
+	c := &customErrors{
 		name:           name,
 		next:           next,
-		backendHandler: backend,
 		httpCodeRanges: httpCodeRanges,
 		backendQuery:   config.Query,
-	}, nil
+	}
+
+	if config.Template != "" {
+		if config.Service != "" {
+			return nil, errors.New("service and template cannot be both defined")
+		}
+
+		c.contentType = config.ContentType
+		if c.contentType == "" {
+			c.contentType = defaultContentType
+		}
+
+		c.template, err = newPageTemplate(name, config.Template, c.contentType)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		c.backendHandler, err = serviceBuilder.BuildHTTP(ctx, config.Service)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Retry != nil {
+		c.retryAttempts = config.Retry.Attempts
+		c.retryInitialInterval = time.Duration(config.Retry.InitialInterval)
+	}
+
+	return c, nil
 }
 
 func (c *customErrors) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -76,21 +118,20 @@ func (c *customErrors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	ctx := middlewares.GetLoggerCtx(req.Context(), c.name, typeName)
 	logger := log.FromContext(ctx)
 
-	if c.backendHandler == nil {
+	if c.backendHandler == nil && c.template == nil {
 		logger.Error("Error pages: no backend handler.")
 		tracing.SetErrorWithEvent(req, "Error pages: no backend handler.")
 		c.next.ServeHTTP(rw, req)
 		return
 	}
 
-	catcher := newCodeCatcher(rw, c.httpCodeRanges)
-	c.next.ServeHTTP(catcher, req)
-	if !catcher.isFilteredCode() {
+	catcher := c.serveNext(rw, req)
+	if !catcher.IsFilteredCode() {
 		return
 	}
 
 	// check the recorder code against the configured http status code ranges
-	code := catcher.getCode()
+	code := catcher.GetCode()
 	for _, block := range c.httpCodeRanges {
 		if code < block[0] || code > block[1] {
 			continue
@@ -98,6 +139,11 @@ func (c *customErrors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 		logger.Debugf("Caught HTTP Status Code %d, returning error page", code)
 
+		if c.template != nil {
+			c.serveTemplate(rw, req, code)
+			return
+		}
+
 		var query string
 		if len(c.backendQuery) > 0 {
 			query = "/" + strings.TrimPrefix(c.backendQuery, "/")
@@ -131,144 +177,144 @@ func (c *customErrors) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func newRequest(baseURL string) (*http.Request, error) {
-	u, err := url.Parse(baseURL)
-	if err != nil {
-		return nil, fmt.Errorf("error pages: error when parse URL: %w", err)
-	}
+// serveTemplate writes the error page rendered from the template, with the given status code.
+func (c *customErrors) serveTemplate(rw http.ResponseWriter, req *http.Request, code int) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName))
 
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("error pages: error when create query: %w", err)
+	page := &bytes.Buffer{}
+	if err := c.template.Execute(page, newPageData(req, code)); err != nil {
+		logger.Errorf("Error while rendering the error page: %v", err)
+		rw.WriteHeader(code)
+		_, err = fmt.Fprint(rw, http.StatusText(code))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+		}
+		return
 	}
 
-	req.RequestURI = u.RequestURI()
-	return req, nil
-}
+	rw.Header().Set("Content-Type", c.contentType)
+	rw.Header().Set("Content-Length", strconv.Itoa(page.Len()))
+	rw.WriteHeader(code)
 
-type responseInterceptor interface {
-	http.ResponseWriter
-	http.Flusher
-	getCode() int
-	isFilteredCode() bool
+	if _, err := rw.Write(page.Bytes()); err != nil {
+		logger.Error(err)
+	}
 }
 
-// codeCatcher is a response writer that detects as soon as possible whether the
-// response is a code within the ranges of codes it watches for. If it is, it
-// simply drops the data from the response. Otherwise, it forwards it directly to
-// the original client (its responseWriter) without any buffering.
-type codeCatcher struct {
-	headerMap          http.Header
-	code               int
-	httpCodeRanges     types.HTTPCodeRanges
-	firstWrite         bool
-	caughtFilteredCode bool
-	responseWriter     http.ResponseWriter
-	headersSent        bool
-}
+// serveNext forwards the request to the next handler, and retries it on a server error,
+// as long as the request can safely be sent again, before falling back to the error page.
+func (c *customErrors) serveNext(rw http.ResponseWriter, req *http.Request) middlewares.CodeCatcher {
+	catcher := middlewares.NewCodeCatcher(rw, c.httpCodeRanges)
+	c.next.ServeHTTP(catcher, req)
 
-type codeCatcherWithCloseNotify struct {
-	*codeCatcher
-}
+	if c.retryAttempts < 2 || !isRetryable(req) {
+		return catcher
+	}
 
-// CloseNotify returns a channel that receives at most a
-// single value (true) when the client connection has gone away.
-func (cc *codeCatcherWithCloseNotify) CloseNotify() <-chan bool {
-	return cc.responseWriter.(http.CloseNotifier).CloseNotify()
-}
+	backOff := job.NewAttemptsBackOff(c.retryAttempts, c.retryInitialInterval)
+	for attempt := 2; attempt <= c.retryAttempts; attempt++ {
+		// The response of a filtered code is dropped, so nothing has been sent to the client yet.
+		if !catcher.IsFilteredCode() || catcher.GetCode() < http.StatusInternalServerError {
+			return catcher
+		}
 
-func newCodeCatcher(rw http.ResponseWriter, httpCodeRanges types.HTTPCodeRanges) responseInterceptor {
-	catcher := &codeCatcher{
-		headerMap:      make(http.Header),
-		code:           http.StatusOK, // If backend does not call WriteHeader on us, we consider it's a 200.
-		responseWriter: rw,
-		httpCodeRanges: httpCodeRanges,
-		firstWrite:     true,
-	}
-	if _, ok := rw.(http.CloseNotifier); ok {
-		return &codeCatcherWithCloseNotify{catcher}
+		select {
+		case <-time.After(backOff.NextBackOff()):
+		case <-req.Context().Done():
+			return catcher
+		}
+
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName)).
+			Debugf("Caught HTTP Status Code %d, new attempt %d for request: %v", catcher.GetCode(), attempt, req.URL)
+
+		catcher = middlewares.NewCodeCatcher(rw, c.httpCodeRanges)
+		c.next.ServeHTTP(catcher, req)
 	}
+
 	return catcher
 }
 
-func (cc *codeCatcher) Header() http.Header {
-	if cc.headerMap == nil {
-		cc.headerMap = make(http.Header)
+// isRetryable returns whether the request is idempotent and has no body,
+// so that it can be sent again to the service.
+func isRetryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+	default:
+		return false
 	}
 
-	return cc.headerMap
+	return req.ContentLength == 0 && (req.Body == nil || req.Body == http.NoBody)
 }
 
-func (cc *codeCatcher) getCode() int {
-	return cc.code
-}
-
-// isFilteredCode returns whether the codeCatcher received a response code among the ones it is watching,
-// and for which the response should be deferred to the error handler.
-func (cc *codeCatcher) isFilteredCode() bool {
-	return cc.caughtFilteredCode
-}
+func newRequest(baseURL string) (*http.Request, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("error pages: error when parse URL: %w", err)
+	}
 
-func (cc *codeCatcher) Write(buf []byte) (int, error) {
-	if !cc.firstWrite {
-		if cc.caughtFilteredCode {
-			// We don't care about the contents of the response,
-			// since we want to serve the ones from the error page,
-			// so we just drop them.
-			return len(buf), nil
-		}
-		return cc.responseWriter.Write(buf)
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error pages: error when create query: %w", err)
 	}
-	cc.firstWrite = false
 
-	// If WriteHeader was already called from the caller, this is a NOOP.
-	// Otherwise, cc.code is actually a 200 here.
-	cc.WriteHeader(cc.code)
+	req.RequestURI = u.RequestURI()
+	return req, nil
+}
 
-	if cc.caughtFilteredCode {
-		return len(buf), nil
-	}
-	return cc.responseWriter.Write(buf)
+// pageTemplate renders an error page.
+type pageTemplate interface {
+	Execute(wr io.Writer, data interface{}) error
 }
 
-func (cc *codeCatcher) WriteHeader(code int) {
-	if cc.headersSent || cc.caughtFilteredCode {
-		return
+// newPageTemplate parses the template of an error page.
+// The HTML pages are rendered with the html/template package, which escapes the values inserted in the page.
+func newPageTemplate(name, text, contentType string) (pageTemplate, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content type %q: %w", contentType, err)
 	}
 
-	cc.code = code
-	for _, block := range cc.httpCodeRanges {
-		if cc.code >= block[0] && cc.code <= block[1] {
-			cc.caughtFilteredCode = true
-			break
+	if mediaType == "text/html" {
+		tmpl, err := htmltemplate.New(name).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("error pages: error while parsing template: %w", err)
 		}
+		return tmpl, nil
 	}
-	// it will be up to the other response recorder to send the headers,
-	// so it is out of our hands now.
-	if cc.caughtFilteredCode {
-		return
+
+	tmpl, err := texttemplate.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error pages: error while parsing template: %w", err)
 	}
-	utils.CopyHeaders(cc.responseWriter.Header(), cc.Header())
-	cc.responseWriter.WriteHeader(cc.code)
-	cc.headersSent = true
+	return tmpl, nil
 }
 
-// Hijack hijacks the connection.
-func (cc *codeCatcher) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	if hj, ok := cc.responseWriter.(http.Hijacker); ok {
-		return hj.Hijack()
-	}
-	return nil, nil, fmt.Errorf("%T is not a http.Hijacker", cc.responseWriter)
+// pageData holds the data available in the template of an error page.
+type pageData struct {
+	StatusCode  int
+	StatusText  string
+	RequestID   string
+	OriginalURL string
 }
 
-// Flush sends any buffered data to the client.
-func (cc *codeCatcher) Flush() {
-	// If WriteHeader was already called from the caller, this is a NOOP.
-	// Otherwise, cc.code is actually a 200 here.
-	cc.WriteHeader(cc.code)
+func newPageData(req *http.Request, code int) pageData {
+	requestID := req.Header.Get(requestIDHeader)
+	if logData := accesslog.GetLogData(req); logData != nil {
+		if id, ok := logData.Core[accesslog.RequestID].(string); ok {
+			requestID = id
+		}
+	}
 
-	if flusher, ok := cc.responseWriter.(http.Flusher); ok {
-		flusher.Flush()
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	return pageData{
+		StatusCode:  code,
+		StatusText:  http.StatusText(code),
+		RequestID:   requestID,
+		OriginalURL: scheme + "://" + req.Host + req.URL.RequestURI(),
 	}
 }
 
@@ -3,12 +3,16 @@ package customerrors
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
@@ -153,6 +157,233 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestHandler_retry(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		retry            *dynamic.Retry
+		method           string
+		body             string
+		backendCodes     []int
+		expectedCode     int
+		expectedBody     string
+		expectedAttempts int
+	}{
+		{
+			desc:             "no retry",
+			method:           http.MethodGet,
+			backendCodes:     []int{http.StatusBadGateway, http.StatusOK},
+			expectedCode:     http.StatusBadGateway,
+			expectedBody:     "My error page.",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "successful retry",
+			retry:            &dynamic.Retry{Attempts: 3},
+			method:           http.MethodGet,
+			backendCodes:     []int{http.StatusBadGateway, http.StatusOK},
+			expectedCode:     http.StatusOK,
+			expectedBody:     http.StatusText(http.StatusOK),
+			expectedAttempts: 2,
+		},
+		{
+			desc:             "all attempts failed",
+			retry:            &dynamic.Retry{Attempts: 3, InitialInterval: ptypes.Duration(time.Millisecond)},
+			method:           http.MethodGet,
+			backendCodes:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK},
+			expectedCode:     http.StatusInternalServerError,
+			expectedBody:     "My error page.",
+			expectedAttempts: 3,
+		},
+		{
+			desc:             "no retry on a client error",
+			retry:            &dynamic.Retry{Attempts: 3},
+			method:           http.MethodGet,
+			backendCodes:     []int{http.StatusNotFound, http.StatusOK},
+			expectedCode:     http.StatusNotFound,
+			expectedBody:     "My error page.",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "no retry on a non idempotent request",
+			retry:            &dynamic.Retry{Attempts: 3},
+			method:           http.MethodPost,
+			backendCodes:     []int{http.StatusBadGateway, http.StatusOK},
+			expectedCode:     http.StatusBadGateway,
+			expectedBody:     "My error page.",
+			expectedAttempts: 1,
+		},
+		{
+			desc:             "no retry on a request with a body",
+			retry:            &dynamic.Retry{Attempts: 3},
+			method:           http.MethodPut,
+			body:             "foo",
+			backendCodes:     []int{http.StatusBadGateway, http.StatusOK},
+			expectedCode:     http.StatusBadGateway,
+			expectedBody:     "My error page.",
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			serviceBuilderMock := &mockServiceBuilder{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, "My error page.")
+			})}
+
+			var attempts int
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				code := test.backendCodes[attempts]
+				attempts++
+
+				w.WriteHeader(code)
+				fmt.Fprintln(w, http.StatusText(code))
+			})
+
+			errorPage := dynamic.ErrorPage{Service: "error", Status: []string{"400-599"}, Retry: test.retry}
+			errorPageHandler, err := New(context.Background(), handler, errorPage, serviceBuilderMock, "test")
+			require.NoError(t, err)
+
+			var body io.Reader
+			if test.body != "" {
+				body = strings.NewReader(test.body)
+			}
+
+			req := httptest.NewRequest(test.method, "http://localhost/test", body)
+
+			recorder := httptest.NewRecorder()
+			errorPageHandler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), test.expectedBody)
+			assert.Equal(t, test.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestNew_retryWithoutAttempts(t *testing.T) {
+	errorPage := dynamic.ErrorPage{Service: "error", Status: []string{"500-599"}, Retry: &dynamic.Retry{}}
+
+	_, err := New(context.Background(), http.NotFoundHandler(), errorPage, &mockServiceBuilder{}, "test")
+	require.Error(t, err)
+}
+
+func TestHandler_template(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		errorPage           dynamic.ErrorPage
+		requestID           string
+		backendCode         int
+		expectedCode        int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			desc: "not in the range",
+			errorPage: dynamic.ErrorPage{
+				Status:   []string{"500-599"},
+				Template: "Error {{ .StatusCode }}",
+			},
+			backendCode:  http.StatusNotFound,
+			expectedCode: http.StatusNotFound,
+			expectedBody: "Not Found\n",
+		},
+		{
+			desc: "HTML page",
+			errorPage: dynamic.ErrorPage{
+				Status:   []string{"500-599"},
+				Template: "<p>{{ .StatusCode }} {{ .StatusText }}: {{ .OriginalURL }} ({{ .RequestID }})</p>",
+			},
+			requestID:           "42",
+			backendCode:         http.StatusBadGateway,
+			expectedCode:        http.StatusBadGateway,
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "<p>502 Bad Gateway: http://localhost/test?foo=&lt;bar&gt; (42)</p>",
+		},
+		{
+			desc: "JSON response",
+			errorPage: dynamic.ErrorPage{
+				Status:      []string{"500-599"},
+				Template:    `{"code":{{ .StatusCode }},"url":"{{ .OriginalURL }}","requestId":"{{ .RequestID }}"}`,
+				ContentType: "application/json",
+			},
+			backendCode:         http.StatusServiceUnavailable,
+			expectedCode:        http.StatusServiceUnavailable,
+			expectedContentType: "application/json",
+			expectedBody:        `{"code":503,"url":"http://localhost/test?foo=<bar>","requestId":""}`,
+		},
+		{
+			desc: "rendering error",
+			errorPage: dynamic.ErrorPage{
+				Status:   []string{"500-599"},
+				Template: "{{ .Unknown }}",
+			},
+			backendCode:  http.StatusInternalServerError,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: http.StatusText(http.StatusInternalServerError),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.backendCode)
+				fmt.Fprintln(w, http.StatusText(test.backendCode))
+			})
+
+			errorPageHandler, err := New(context.Background(), handler, test.errorPage, &mockServiceBuilder{}, "test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/test?foo=<bar>", nil)
+			if test.requestID != "" {
+				req.Header.Set("X-Request-Id", test.requestID)
+			}
+
+			recorder := httptest.NewRecorder()
+			errorPageHandler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+		})
+	}
+}
+
+func TestNew_template(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		errorPage dynamic.ErrorPage
+	}{
+		{
+			desc:      "service and template",
+			errorPage: dynamic.ErrorPage{Service: "error", Status: []string{"500-599"}, Template: "Error"},
+		},
+		{
+			desc:      "invalid template",
+			errorPage: dynamic.ErrorPage{Status: []string{"500-599"}, Template: "{{ .StatusCode"},
+		},
+		{
+			desc:      "invalid content type",
+			errorPage: dynamic.ErrorPage{Status: []string{"500-599"}, Template: "Error", ContentType: "text/"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.errorPage, &mockServiceBuilder{}, "test")
+			require.Error(t, err)
+		})
+	}
+}
+
 type mockServiceBuilder struct {
 	handler http.Handler
 }
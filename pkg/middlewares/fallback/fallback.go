@@ -0,0 +1,135 @@
+package fallback
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+const typeName = "Fallback"
+
+type serviceBuilder interface {
+	BuildHTTP(ctx context.Context, serviceName string) (http.Handler, error)
+}
+
+// fallback is a middleware that replays the request against a fallback service,
+// when the response of the next handler has one of the configured status codes.
+type fallback struct {
+	name                string
+	next                http.Handler
+	fallbackHandler     http.Handler
+	httpCodeRanges      types.HTTPCodeRanges
+	path                string
+	maxRequestBodyBytes int64
+}
+
+// New creates a new fallback middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Fallback, serviceBuilder serviceBuilder, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Status) == 0 {
+		return nil, fmt.Errorf("no status code for the fallback %s", name)
+	}
+
+	httpCodeRanges, err := types.NewHTTPCodeRanges(config.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackHandler, err := serviceBuilder.BuildHTTP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fallback{
+		name:                name,
+		next:                next,
+		fallbackHandler:     fallbackHandler,
+		httpCodeRanges:      httpCodeRanges,
+		path:                config.Path,
+		maxRequestBodyBytes: config.MaxRequestBodyBytes,
+	}, nil
+}
+
+func (f *fallback) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return f.name, tracing.SpanKindNoneEnum
+}
+
+func (f *fallback) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), f.name, typeName))
+
+	body, replayable, err := readBody(req, f.maxRequestBodyBytes)
+	if err != nil {
+		logger.Debugf("Error while reading the request body: %v", err)
+		http.Error(rw, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if !replayable {
+		logger.Debugf("The request body is larger than %d bytes, the request cannot fall back", f.maxRequestBodyBytes)
+		f.next.ServeHTTP(rw, req)
+		return
+	}
+
+	if body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	catcher := middlewares.NewCodeCatcher(rw, f.httpCodeRanges)
+	f.next.ServeHTTP(catcher, req)
+
+	if !catcher.IsFilteredCode() {
+		// Sends the headers when the next handler did not write anything.
+		catcher.WriteHeader(catcher.GetCode())
+		return
+	}
+
+	logger.Debugf("Caught HTTP Status Code %d, falling back", catcher.GetCode())
+
+	fallbackReq := req.Clone(req.Context())
+	if body != nil {
+		fallbackReq.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if len(f.path) > 0 {
+		fallbackReq.URL.Path = f.path
+		fallbackReq.URL.RawPath = ""
+		fallbackReq.RequestURI = fallbackReq.URL.RequestURI()
+	}
+
+	f.fallbackHandler.ServeHTTP(rw, fallbackReq)
+}
+
+// readBody reads the request body, unless it is larger than limit.
+// When the body is too large to be replayed, the request body is left readable from the start,
+// and replayable is false.
+func readBody(req *http.Request, limit int64) (body []byte, replayable bool, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true, nil
+	}
+
+	body, err = io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if int64(len(body)) > limit {
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+
+		return nil, false, nil
+	}
+
+	return body, true, nil
+}
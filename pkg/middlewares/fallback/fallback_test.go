@@ -0,0 +1,153 @@
+package fallback
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+type mockServiceBuilder struct {
+	handler http.Handler
+}
+
+func (m *mockServiceBuilder) BuildHTTP(_ context.Context, _ string) (http.Handler, error) {
+	return m.handler, nil
+}
+
+func TestFallback(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           dynamic.Fallback
+		requestBody      string
+		primaryCode      int
+		expectedCode     int
+		expectedBody     string
+		expectedHeader   string
+		expectedFallback bool
+	}{
+		{
+			desc:           "primary response",
+			config:         dynamic.Fallback{Status: []string{"404"}, MaxRequestBodyBytes: 1024},
+			primaryCode:    http.StatusOK,
+			expectedCode:   http.StatusOK,
+			expectedBody:   "primary GET /foo/bar ",
+			expectedHeader: "primary",
+		},
+		{
+			desc:           "primary response with a status code out of the ranges",
+			config:         dynamic.Fallback{Status: []string{"404", "502-504"}, MaxRequestBodyBytes: 1024},
+			primaryCode:    http.StatusInternalServerError,
+			expectedCode:   http.StatusInternalServerError,
+			expectedBody:   "primary GET /foo/bar ",
+			expectedHeader: "primary",
+		},
+		{
+			desc:             "fallback response",
+			config:           dynamic.Fallback{Status: []string{"404", "502-504"}, MaxRequestBodyBytes: 1024},
+			primaryCode:      http.StatusServiceUnavailable,
+			expectedCode:     http.StatusOK,
+			expectedBody:     "fallback GET /foo/bar ",
+			expectedHeader:   "fallback",
+			expectedFallback: true,
+		},
+		{
+			desc:             "fallback response with path",
+			config:           dynamic.Fallback{Status: []string{"404"}, Path: "/index.html", MaxRequestBodyBytes: 1024},
+			primaryCode:      http.StatusNotFound,
+			expectedCode:     http.StatusOK,
+			expectedBody:     "fallback GET /index.html ",
+			expectedHeader:   "fallback",
+			expectedFallback: true,
+		},
+		{
+			desc:             "fallback response with replayed body",
+			config:           dynamic.Fallback{Status: []string{"404"}, MaxRequestBodyBytes: 1024},
+			requestBody:      "hello",
+			primaryCode:      http.StatusNotFound,
+			expectedCode:     http.StatusOK,
+			expectedBody:     "fallback POST /foo/bar hello",
+			expectedHeader:   "fallback",
+			expectedFallback: true,
+		},
+		{
+			desc:           "body too large to be replayed",
+			config:         dynamic.Fallback{Status: []string{"404"}, MaxRequestBodyBytes: 4},
+			requestBody:    "hello",
+			primaryCode:    http.StatusNotFound,
+			expectedCode:   http.StatusNotFound,
+			expectedBody:   "primary POST /foo/bar hello",
+			expectedHeader: "primary",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var fallbackCalled bool
+			fallbackHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				fallbackCalled = true
+
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				rw.Header().Set("X-Served-By", "fallback")
+				_, _ = fmt.Fprintf(rw, "fallback %s %s %s", req.Method, req.RequestURI, body)
+			})
+
+			primaryHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				rw.Header().Set("X-Served-By", "primary")
+				rw.WriteHeader(test.primaryCode)
+				_, _ = fmt.Fprintf(rw, "primary %s %s %s", req.Method, req.RequestURI, body)
+			})
+
+			handler, err := New(context.Background(), primaryHandler, test.config, &mockServiceBuilder{handler: fallbackHandler}, "test")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/foo/bar", nil)
+			if test.requestBody != "" {
+				req = httptest.NewRequest(http.MethodPost, "/foo/bar", strings.NewReader(test.requestBody))
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedFallback, fallbackCalled)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			assert.Equal(t, []string{test.expectedHeader}, recorder.Header()["X-Served-By"])
+		})
+	}
+}
+
+func TestFallback_noWrite(t *testing.T) {
+	primaryHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Served-By", "primary")
+	})
+
+	handler, err := New(context.Background(), primaryHandler, dynamic.Fallback{Status: []string{"404"}}, &mockServiceBuilder{}, "test")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "primary", recorder.Header().Get("X-Served-By"))
+}
+
+func TestNew_noStatus(t *testing.T) {
+	_, err := New(context.Background(), http.NotFoundHandler(), dynamic.Fallback{Service: "foo"}, &mockServiceBuilder{}, "test")
+	require.Error(t, err)
+}
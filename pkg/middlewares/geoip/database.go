@@ -0,0 +1,130 @@
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// reloadCheckInterval is the minimum duration between two checks of the modification of a database file.
+const reloadCheckInterval = 10 * time.Second
+
+var (
+	databasesMu sync.Mutex
+	// databases holds the opened databases by path, shared by the middlewares and kept across the configuration reloads.
+	databases = make(map[string]*database)
+)
+
+// getDatabase returns the database of the given MaxMind DB file, which is opened on first use.
+func getDatabase(path string) (*database, error) {
+	databasesMu.Lock()
+	defer databasesMu.Unlock()
+
+	if db, ok := databases[path]; ok {
+		return db, nil
+	}
+
+	db := &database{path: path, now: time.Now}
+	if err := db.load(); err != nil {
+		return nil, err
+	}
+
+	databases[path] = db
+
+	return db, nil
+}
+
+// database is a MaxMind DB file, which is read again when it changes.
+type database struct {
+	path string
+	now  func() time.Time
+
+	mu        sync.RWMutex
+	reader    *maxminddb.Reader
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// lookup decodes the record of the given IP into result.
+func (d *database) lookup(ip net.IP, result interface{}) error {
+	d.reloadIfModified()
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.reader.Lookup(ip, result)
+}
+
+// reloadIfModified reads the file again if it was modified since it was read,
+// at most once per reloadCheckInterval.
+// The current database is kept if the file cannot be read.
+func (d *database) reloadIfModified() {
+	d.mu.RLock()
+	checkedAt := d.checkedAt
+	d.mu.RUnlock()
+
+	if d.now().Sub(checkedAt) < reloadCheckInterval {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Another lookup checked the file in the meantime.
+	if d.now().Sub(d.checkedAt) < reloadCheckInterval {
+		return
+	}
+
+	d.checkedAt = d.now()
+
+	info, err := os.Stat(d.path)
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to check the GeoIP database %s: %v", d.path, err)
+		return
+	}
+
+	if info.ModTime().Equal(d.modTime) {
+		return
+	}
+
+	if err := d.read(info.ModTime()); err != nil {
+		log.WithoutContext().Errorf("Unable to reload the GeoIP database %s: %v", d.path, err)
+		return
+	}
+
+	log.WithoutContext().Infof("GeoIP database %s reloaded", d.path)
+}
+
+func (d *database) load() error {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return fmt.Errorf("unable to open the GeoIP database: %w", err)
+	}
+
+	d.checkedAt = d.now()
+
+	return d.read(info.ModTime())
+}
+
+// read reads the whole file, so that it can be replaced while it is in use.
+func (d *database) read(modTime time.Time) error {
+	content, err := os.ReadFile(d.path)
+	if err != nil {
+		return fmt.Errorf("unable to read the GeoIP database: %w", err)
+	}
+
+	reader, err := maxminddb.FromBytes(content)
+	if err != nil {
+		return fmt.Errorf("invalid GeoIP database %s: %w", d.path, err)
+	}
+
+	d.reader = reader
+	d.modTime = modTime
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "GeoIP"
+)
+
+// Reasons of the rejections, reported in the rejected requests metric.
+const (
+	reasonCountry = "country"
+	reasonASN     = "asn"
+)
+
+// location is the record of an IP in the databases.
+// The countries and the cities databases share the country and city fields,
+// and the autonomous systems database holds the autonomous_system_number field.
+type location struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	ASN uint `maxminddb:"autonomous_system_number"`
+}
+
+// geoIP is a middleware which allows or denies the requests by the location of the client IP,
+// and forwards this location to the backends.
+type geoIP struct {
+	next         http.Handler
+	name         string
+	db           *database
+	asnDB        *database
+	strategy     ip.Strategy
+	rejectedReqs gokitmetrics.Counter
+
+	allowedCountries map[string]struct{}
+	deniedCountries  map[string]struct{}
+	allowedASNs      map[uint]struct{}
+	deniedASNs       map[uint]struct{}
+	allowUnknown     bool
+
+	countryHeader string
+	cityHeader    string
+	asnHeader     string
+}
+
+// New creates a new GeoIP middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.GeoIP, rejectedReqs gokitmetrics.Counter, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	countryLookup := len(config.AllowedCountries) > 0 || len(config.DeniedCountries) > 0 || config.CountryHeader != "" || config.CityHeader != ""
+	asnLookup := len(config.AllowedASNs) > 0 || len(config.DeniedASNs) > 0 || config.ASNHeader != ""
+
+	if !countryLookup && !asnLookup {
+		return nil, errors.New("no allowed or denied lists, nor headers, are configured")
+	}
+
+	if countryLookup && config.Database == "" {
+		return nil, errors.New("the countries and the cities require a database")
+	}
+
+	if asnLookup && config.ASNDatabase == "" {
+		return nil, errors.New("the autonomous systems require an ASN database")
+	}
+
+	strategy, err := config.IPStrategy.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &geoIP{
+		next:             next,
+		name:             name,
+		strategy:         strategy,
+		rejectedReqs:     rejectedReqs,
+		allowedCountries: countrySet(config.AllowedCountries),
+		deniedCountries:  countrySet(config.DeniedCountries),
+		allowedASNs:      asnSet(config.AllowedASNs),
+		deniedASNs:       asnSet(config.DeniedASNs),
+		allowUnknown:     config.AllowUnknown,
+		countryHeader:    config.CountryHeader,
+		cityHeader:       config.CityHeader,
+		asnHeader:        config.ASNHeader,
+	}
+
+	if countryLookup {
+		g.db, err = getDatabase(config.Database)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if asnLookup {
+		g.asnDB, err = getDatabase(config.ASNDatabase)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+func (g *geoIP) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return g.name, tracing.SpanKindNoneEnum
+}
+
+func (g *geoIP) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := middlewares.GetLoggerCtx(req.Context(), g.name, typeName)
+	logger := log.FromContext(ctx)
+
+	clientIP := g.strategy.GetIP(req)
+
+	loc, err := g.locate(clientIP)
+	if err != nil {
+		logger.Debugf("Unable to locate %s: %v", clientIP, err)
+	}
+
+	if reason, err := g.check(loc); err != nil {
+		logMessage := fmt.Sprintf("rejecting request from %s: %v", clientIP, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+		accesslog.SetRejectedBy(req, g.name)
+
+		if g.rejectedReqs != nil {
+			g.rejectedReqs.With("middleware", g.name, "reason", reason).Add(1)
+		}
+
+		http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	// The headers sent by the client are never forwarded, to prevent spoofing.
+	setHeader(req, g.countryHeader, loc.Country.ISOCode)
+	setHeader(req, g.cityHeader, loc.City.Names["en"])
+
+	var asn string
+	if loc.ASN != 0 {
+		asn = strconv.FormatUint(uint64(loc.ASN), 10)
+	}
+	setHeader(req, g.asnHeader, asn)
+
+	g.next.ServeHTTP(rw, req)
+}
+
+// locate looks up the client IP in the databases.
+// An unknown location is returned for an invalid or a private IP.
+func (g *geoIP) locate(clientIP string) (location, error) {
+	var loc location
+
+	parsedIP := net.ParseIP(clientIP)
+	if parsedIP == nil {
+		return loc, fmt.Errorf("invalid IP %q", clientIP)
+	}
+
+	if ipv4 := parsedIP.To4(); ipv4 != nil {
+		parsedIP = ipv4
+	}
+
+	if g.db != nil {
+		if err := g.db.lookup(parsedIP, &loc); err != nil {
+			return loc, err
+		}
+	}
+
+	if g.asnDB != nil {
+		var asn location
+		if err := g.asnDB.lookup(parsedIP, &asn); err != nil {
+			return loc, err
+		}
+		loc.ASN = asn.ASN
+	}
+
+	return loc, nil
+}
+
+// check returns the reason and the error of the rejection of the given location, if any.
+func (g *geoIP) check(loc location) (string, error) {
+	country := strings.ToUpper(loc.Country.ISOCode)
+
+	if _, ok := g.deniedCountries[country]; ok && country != "" {
+		return reasonCountry, fmt.Errorf("country %s is denied", country)
+	}
+
+	if len(g.allowedCountries) > 0 {
+		_, ok := g.allowedCountries[country]
+		if country == "" && !g.allowUnknown || country != "" && !ok {
+			return reasonCountry, fmt.Errorf("country %q is not allowed", country)
+		}
+	}
+
+	if _, ok := g.deniedASNs[loc.ASN]; ok && loc.ASN != 0 {
+		return reasonASN, fmt.Errorf("autonomous system %d is denied", loc.ASN)
+	}
+
+	if len(g.allowedASNs) > 0 {
+		_, ok := g.allowedASNs[loc.ASN]
+		if loc.ASN == 0 && !g.allowUnknown || loc.ASN != 0 && !ok {
+			return reasonASN, fmt.Errorf("autonomous system %d is not allowed", loc.ASN)
+		}
+	}
+
+	return "", nil
+}
+
+func setHeader(req *http.Request, name, value string) {
+	if name == "" {
+		return
+	}
+
+	if value == "" {
+		req.Header.Del(name)
+		return
+	}
+
+	req.Header.Set(name, value)
+}
+
+func countrySet(countries []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(countries))
+	for _, country := range countries {
+		set[strings.ToUpper(strings.TrimSpace(country))] = struct{}{}
+	}
+
+	return set
+}
+
+func asnSet(asns []uint) map[uint]struct{} {
+	set := make(map[uint]struct{}, len(asns))
+	for _, asn := range asns {
+		set[asn] = struct{}{}
+	}
+
+	return set
+}
@@ -0,0 +1,271 @@
+package geoip
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func writeTestDatabases(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	cityDB := filepath.Join(dir, "city.mmdb")
+	writeDatabase(t, cityDB, map[string]map[string]interface{}{
+		"1.2.3.0/24": {
+			"country": map[string]interface{}{"iso_code": "FR"},
+			"city":    map[string]interface{}{"names": map[string]interface{}{"en": "Lyon"}},
+		},
+		"5.6.7.0/24": {
+			"country": map[string]interface{}{"iso_code": "US"},
+		},
+	})
+
+	asnDB := filepath.Join(dir, "asn.mmdb")
+	writeDatabase(t, asnDB, map[string]map[string]interface{}{
+		"1.2.3.0/24": {"autonomous_system_number": uint(64500)},
+		"5.6.7.0/24": {"autonomous_system_number": uint(64501)},
+	})
+
+	return cityDB, asnDB
+}
+
+func TestNew(t *testing.T) {
+	cityDB, asnDB := writeTestDatabases(t)
+
+	testCases := []struct {
+		desc      string
+		config    dynamic.GeoIP
+		expectErr bool
+	}{
+		{
+			desc:   "countries",
+			config: dynamic.GeoIP{Database: cityDB, AllowedCountries: []string{"FR"}},
+		},
+		{
+			desc:   "autonomous systems",
+			config: dynamic.GeoIP{ASNDatabase: asnDB, DeniedASNs: []uint{64500}},
+		},
+		{
+			desc:      "nothing configured",
+			config:    dynamic.GeoIP{Database: cityDB},
+			expectErr: true,
+		},
+		{
+			desc:      "countries without database",
+			config:    dynamic.GeoIP{ASNDatabase: asnDB, CountryHeader: "X-Country"},
+			expectErr: true,
+		},
+		{
+			desc:      "autonomous systems without database",
+			config:    dynamic.GeoIP{Database: cityDB, AllowedASNs: []uint{64500}},
+			expectErr: true,
+		},
+		{
+			desc:      "missing database",
+			config:    dynamic.GeoIP{Database: filepath.Join(t.TempDir(), "missing.mmdb"), CountryHeader: "X-Country"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, nil, "foo")
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGeoIP_ServeHTTP(t *testing.T) {
+	cityDB, asnDB := writeTestDatabases(t)
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.GeoIP
+		remoteAddr      string
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:           "allowed country",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"fr"}},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "country not allowed",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"FR"}},
+			remoteAddr:     "5.6.7.8:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "denied country",
+			config:         dynamic.GeoIP{DeniedCountries: []string{"US"}},
+			remoteAddr:     "5.6.7.8:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unknown country",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"FR"}},
+			remoteAddr:     "10.0.0.1:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "unknown country allowed",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"FR"}, AllowUnknown: true},
+			remoteAddr:     "10.0.0.1:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "unknown country not denied",
+			config:         dynamic.GeoIP{DeniedCountries: []string{"US"}},
+			remoteAddr:     "10.0.0.1:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "allowed autonomous system",
+			config:         dynamic.GeoIP{AllowedASNs: []uint{64500}},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "denied autonomous system",
+			config:         dynamic.GeoIP{DeniedASNs: []uint{64501}},
+			remoteAddr:     "5.6.7.8:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "allowed country in a denied autonomous system",
+			config:         dynamic.GeoIP{AllowedCountries: []string{"FR"}, DeniedASNs: []uint{64500}},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "headers",
+			config:         dynamic.GeoIP{CountryHeader: "X-Country", CityHeader: "X-City", ASNHeader: "X-ASN"},
+			remoteAddr:     "1.2.3.4:1234",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-Country": "FR",
+				"X-City":    "Lyon",
+				"X-ASN":     "64500",
+			},
+		},
+		{
+			desc:           "spoofed headers removed",
+			config:         dynamic.GeoIP{CountryHeader: "X-Country", CityHeader: "X-City", ASNHeader: "X-ASN"},
+			remoteAddr:     "10.0.0.1:1234",
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"X-Country": "",
+				"X-City":    "",
+				"X-ASN":     "",
+			},
+		},
+		{
+			desc: "IP strategy",
+			config: dynamic.GeoIP{
+				AllowedCountries: []string{"FR"},
+				IPStrategy:       &dynamic.IPStrategy{Depth: 1},
+			},
+			remoteAddr:     "5.6.7.8:1234",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := test.config
+			config.Database = cityDB
+			config.ASNDatabase = asnDB
+
+			var forwarded http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header.Clone()
+			})
+
+			handler, err := New(context.Background(), next, config, nil, "foo")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			req.RemoteAddr = test.remoteAddr
+			req.Header.Set("X-Forwarded-For", "1.2.3.4")
+			req.Header.Set("X-Country", "XX")
+			req.Header.Set("X-City", "Spoofed")
+			req.Header.Set("X-ASN", "1")
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expectedStatus, rw.Code)
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, forwarded.Get(name))
+			}
+		})
+	}
+}
+
+func TestDatabase_reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	writeDatabase(t, path, map[string]map[string]interface{}{
+		"1.2.3.0/24": {"country": map[string]interface{}{"iso_code": "FR"}},
+	})
+
+	db, err := getDatabase(path)
+	require.NoError(t, err)
+
+	now := time.Now()
+	db.now = func() time.Time { return now }
+
+	var loc location
+	require.NoError(t, db.lookup(net.ParseIP("1.2.3.4").To4(), &loc))
+	assert.Equal(t, "FR", loc.Country.ISOCode)
+
+	writeDatabase(t, path, map[string]map[string]interface{}{
+		"1.2.3.0/24": {"country": map[string]interface{}{"iso_code": "DE"}},
+	})
+	modTime := now.Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+
+	// The file is not checked again before the check interval.
+	loc = location{}
+	require.NoError(t, db.lookup(net.ParseIP("1.2.3.4").To4(), &loc))
+	assert.Equal(t, "FR", loc.Country.ISOCode)
+
+	now = now.Add(reloadCheckInterval)
+
+	loc = location{}
+	require.NoError(t, db.lookup(net.ParseIP("1.2.3.4").To4(), &loc))
+	assert.Equal(t, "DE", loc.Country.ISOCode)
+
+	// An invalid file does not replace the database.
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0o600))
+	modTime = modTime.Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	now = now.Add(reloadCheckInterval)
+
+	loc = location{}
+	require.NoError(t, db.lookup(net.ParseIP("1.2.3.4").To4(), &loc))
+	assert.Equal(t, "DE", loc.Country.ISOCode)
+}
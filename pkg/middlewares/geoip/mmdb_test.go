@@ -0,0 +1,143 @@
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeDatabase writes an IPv4 MaxMind DB file holding the given records by network.
+// The records are maps of strings, unsigned integers, and maps.
+func writeDatabase(t *testing.T, path string, records map[string]map[string]interface{}) {
+	t.Helper()
+
+	var nodes []*treeNode
+	newNode := func() *treeNode {
+		n := &treeNode{index: len(nodes), data: [2]int{-1, -1}}
+		nodes = append(nodes, n)
+		return n
+	}
+
+	root := newNode()
+
+	var dataSection bytes.Buffer
+
+	networks := make([]string, 0, len(records))
+	for network := range records {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network)
+		require.NoError(t, err)
+
+		offset := dataSection.Len()
+		encodeValue(&dataSection, records[network])
+
+		ones, _ := ipNet.Mask.Size()
+		ip := ipNet.IP.To4()
+
+		current := root
+		for i := 0; i < ones; i++ {
+			bit := (ip[i/8] >> (7 - uint(i%8))) & 1
+			if i == ones-1 {
+				current.data[bit] = offset
+				break
+			}
+
+			if current.children[bit] == nil {
+				current.children[bit] = newNode()
+			}
+			current = current.children[bit]
+		}
+	}
+
+	nodeCount := len(nodes)
+	recordValue := func(n *treeNode, bit int) uint32 {
+		switch {
+		case n.children[bit] != nil:
+			return uint32(n.children[bit].index)
+		case n.data[bit] >= 0:
+			return uint32(nodeCount + 16 + n.data[bit])
+		default:
+			return uint32(nodeCount)
+		}
+	}
+
+	var file bytes.Buffer
+	for _, n := range nodes {
+		for bit := 0; bit < 2; bit++ {
+			value := recordValue(n, bit)
+			file.Write([]byte{byte(value >> 16), byte(value >> 8), byte(value)})
+		}
+	}
+
+	file.Write(make([]byte, 16))
+	file.Write(dataSection.Bytes())
+	file.WriteString("\xAB\xCD\xEFMaxMind.com")
+	encodeValue(&file, map[string]interface{}{
+		"binary_format_major_version": uint(2),
+		"binary_format_minor_version": uint(0),
+		"build_epoch":                 uint(1),
+		"database_type":               "Test",
+		"ip_version":                  uint(4),
+		"node_count":                  uint(nodeCount),
+		"record_size":                 uint(24),
+	})
+
+	require.NoError(t, os.WriteFile(path, file.Bytes(), 0o600))
+}
+
+type treeNode struct {
+	index    int
+	children [2]*treeNode
+	data     [2]int
+}
+
+// encodeValue encodes a value of the data section.
+func encodeValue(buf *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		writeControl(buf, 2, len(v))
+		buf.WriteString(v)
+	case uint:
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(v))
+		trimmed := bytes.TrimLeft(b[:], "\x00")
+		writeControl(buf, 9, len(trimmed))
+		buf.Write(trimmed)
+	case map[string]interface{}:
+		writeControl(buf, 7, len(v))
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			encodeValue(buf, key)
+			encodeValue(buf, v[key])
+		}
+	default:
+		panic("unsupported type")
+	}
+}
+
+// writeControl writes the control byte of a value of the given type and size, the size being less than 29.
+func writeControl(buf *bytes.Buffer, dataType, size int) {
+	if dataType < 8 {
+		buf.WriteByte(byte(dataType<<5 | size))
+		return
+	}
+
+	// Extended type.
+	buf.WriteByte(byte(size))
+	buf.WriteByte(byte(dataType - 7))
+}
@@ -18,20 +18,8 @@ const (
 )
 
 func handleDeprecation(ctx context.Context, cfg *dynamic.Headers) {
-	if cfg.SSLRedirect {
-		log.FromContext(ctx).Warn("SSLRedirect is deprecated, please use entrypoint redirection instead.")
-	}
-	if cfg.SSLTemporaryRedirect {
-		log.FromContext(ctx).Warn("SSLTemporaryRedirect is deprecated, please use entrypoint redirection instead.")
-	}
-	if cfg.SSLHost != "" {
-		log.FromContext(ctx).Warn("SSLHost is deprecated, please use RedirectRegex middleware instead.")
-	}
-	if cfg.SSLForceHost {
-		log.FromContext(ctx).Warn("SSLForceHost is deprecated, please use RedirectScheme middleware instead.")
-	}
-	if cfg.FeaturePolicy != "" {
-		log.FromContext(ctx).Warn("FeaturePolicy is deprecated, please use PermissionsPolicy header instead.")
+	for _, deprecation := range cfg.Deprecations() {
+		log.FromContext(ctx).Warnf("%s is deprecated, please use %s instead.", deprecation.Option, deprecation.Replacement)
 	}
 }
 
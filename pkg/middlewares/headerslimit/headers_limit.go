@@ -0,0 +1,130 @@
+package headerslimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "HeadersLimit"
+)
+
+// Reasons of the rejections, reported in the rejected requests metric.
+const (
+	reasonMaxCount       = "max_count"
+	reasonMaxHeaderBytes = "max_header_bytes"
+	reasonMaxTotalBytes  = "max_total_bytes"
+)
+
+// headersLimit is a middleware which rejects the requests whose headers exceed the limits,
+// before they reach the backends.
+type headersLimit struct {
+	next           http.Handler
+	name           string
+	maxCount       int
+	maxHeaderBytes int64
+	maxTotalBytes  int64
+	rejectedReqs   gokitmetrics.Counter
+}
+
+// New creates a new HeadersLimit middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.HeadersLimit, rejectedReqs gokitmetrics.Counter, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.MaxCount < 0 || config.MaxHeaderBytes < 0 || config.MaxTotalBytes < 0 {
+		return nil, errors.New("limits cannot be negative")
+	}
+
+	if config.MaxCount == 0 && config.MaxHeaderBytes == 0 && config.MaxTotalBytes == 0 {
+		return nil, errors.New("at least one limit must be set")
+	}
+
+	return &headersLimit{
+		next:           next,
+		name:           name,
+		maxCount:       config.MaxCount,
+		maxHeaderBytes: config.MaxHeaderBytes,
+		maxTotalBytes:  config.MaxTotalBytes,
+		rejectedReqs:   rejectedReqs,
+	}, nil
+}
+
+func (h *headersLimit) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return h.name, tracing.SpanKindNoneEnum
+}
+
+func (h *headersLimit) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	reason, err := h.check(req)
+	if err != nil {
+		logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), h.name, typeName))
+
+		logMessage := fmt.Sprintf("rejecting request: %v", err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		if h.rejectedReqs != nil {
+			h.rejectedReqs.With("middleware", h.name, "reason", reason).Add(1)
+		}
+
+		http.Error(rw, http.StatusText(http.StatusRequestHeaderFieldsTooLarge), http.StatusRequestHeaderFieldsTooLarge)
+		return
+	}
+
+	h.next.ServeHTTP(rw, req)
+}
+
+// check returns the reason of the rejection of the request, and an error describing it, when its headers exceed the limits.
+// The size of a header field is the size of its name and of its value,
+// and each value of a multi-valued header counts as a field, as the Host header does.
+func (h *headersLimit) check(req *http.Request) (string, error) {
+	var count int
+	var totalBytes int64
+
+	var largestName string
+	var largestBytes int64
+
+	addField := func(name, value string) {
+		size := int64(len(name) + len(value))
+
+		count++
+		totalBytes += size
+
+		if size > largestBytes {
+			largestName = name
+			largestBytes = size
+		}
+	}
+
+	if req.Host != "" {
+		addField("Host", req.Host)
+	}
+
+	for name, values := range req.Header {
+		for _, value := range values {
+			addField(name, value)
+		}
+	}
+
+	if h.maxCount > 0 && count > h.maxCount {
+		return reasonMaxCount, fmt.Errorf("%d header fields, exceeding the limit of %d", count, h.maxCount)
+	}
+
+	if h.maxHeaderBytes > 0 && largestBytes > h.maxHeaderBytes {
+		return reasonMaxHeaderBytes, fmt.Errorf("header %s of %d bytes, exceeding the limit of %d bytes", largestName, largestBytes, h.maxHeaderBytes)
+	}
+
+	if h.maxTotalBytes > 0 && totalBytes > h.maxTotalBytes {
+		return reasonMaxTotalBytes, fmt.Errorf("headers of %d bytes, exceeding the limit of %d bytes", totalBytes, h.maxTotalBytes)
+	}
+
+	return "", nil
+}
@@ -0,0 +1,155 @@
+package headerslimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// collectingCounter is a metrics.Counter implementation that enables access to the counter value and last label values.
+type collectingCounter struct {
+	counterValue    float64
+	lastLabelValues []string
+}
+
+func (c *collectingCounter) With(labelValues ...string) metrics.Counter {
+	c.lastLabelValues = labelValues
+	return c
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.counterValue += delta
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.HeadersLimit
+		expectedError bool
+	}{
+		{
+			desc:   "all limits",
+			config: dynamic.HeadersLimit{MaxCount: 10, MaxHeaderBytes: 100, MaxTotalBytes: 1000},
+		},
+		{
+			desc:   "single limit",
+			config: dynamic.HeadersLimit{MaxTotalBytes: 1000},
+		},
+		{
+			desc:          "no limit",
+			config:        dynamic.HeadersLimit{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative limit",
+			config:        dynamic.HeadersLimit{MaxCount: 10, MaxHeaderBytes: -1},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, nil, "foo-headers-limit")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHeadersLimit(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		config         dynamic.HeadersLimit
+		headers        map[string][]string
+		expectedStatus int
+		expectedReason string
+	}{
+		{
+			desc:           "within the limits",
+			config:         dynamic.HeadersLimit{MaxCount: 3, MaxHeaderBytes: 20, MaxTotalBytes: 50},
+			headers:        map[string][]string{"X-Foo": {"bar"}, "X-Bar": {"foo"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "too many header fields",
+			config:         dynamic.HeadersLimit{MaxCount: 3},
+			headers:        map[string][]string{"X-Foo": {"bar"}, "X-Bar": {"foo", "baz"}},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+			expectedReason: reasonMaxCount,
+		},
+		{
+			desc:           "header field too large",
+			config:         dynamic.HeadersLimit{MaxHeaderBytes: 20},
+			headers:        map[string][]string{"X-Foo": {strings.Repeat("a", 16)}},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+			expectedReason: reasonMaxHeaderBytes,
+		},
+		{
+			desc:           "headers too large",
+			config:         dynamic.HeadersLimit{MaxTotalBytes: 30},
+			headers:        map[string][]string{"X-Foo": {"bar"}, "X-Bar": {"foo"}, "X-Baz": {"foo"}},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+			expectedReason: reasonMaxTotalBytes,
+		},
+		{
+			desc:           "host counted as a header field",
+			config:         dynamic.HeadersLimit{MaxCount: 2},
+			headers:        map[string][]string{"X-Foo": {"bar"}, "X-Bar": {"foo"}},
+			expectedStatus: http.StatusRequestHeaderFieldsTooLarge,
+			expectedReason: reasonMaxCount,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var called bool
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = true
+			})
+
+			counter := &collectingCounter{}
+
+			handler, err := New(context.Background(), next, test.config, counter, "foo-headers-limit")
+			require.NoError(t, err)
+
+			// The Host of the request is example.com, i.e. a field of 15 bytes.
+			req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			for name, values := range test.headers {
+				req.Header[name] = values
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+
+			if test.expectedReason == "" {
+				assert.True(t, called)
+				assert.Zero(t, counter.counterValue)
+				return
+			}
+
+			assert.False(t, called)
+			assert.Equal(t, float64(1), counter.counterValue)
+			assert.Equal(t, []string{"middleware", "foo-headers-limit", "reason", test.expectedReason}, counter.lastLabelValues)
+		})
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/tracing"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 const (
@@ -21,13 +24,14 @@ const (
 // ipWhiteLister is a middleware that provides Checks of the Requesting IP against a set of Whitelists.
 type ipWhiteLister struct {
 	next        http.Handler
-	whiteLister *ip.Checker
+	sourceRange *sourceRange
 	strategy    ip.Strategy
 	name        string
 }
 
 // New builds a new IPWhiteLister given a list of CIDR-Strings to whitelist.
-func New(ctx context.Context, next http.Handler, config dynamic.IPWhiteList, name string) (http.Handler, error) {
+// The files and the URLs of the list must be allowed by the sources.
+func New(ctx context.Context, next http.Handler, config dynamic.IPWhiteList, name string, sources *types.SourceRangeSources) (http.Handler, error) {
 	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
 	logger.Debug("Creating middleware")
 
@@ -35,9 +39,9 @@ func New(ctx context.Context, next http.Handler, config dynamic.IPWhiteList, nam
 		return nil, errors.New("sourceRange is empty, IPWhiteLister not created")
 	}
 
-	checker, err := ip.NewChecker(config.SourceRange)
+	sourceRange, err := newSourceRange(middlewares.GetLoggerCtx(ctx, name, typeName), config.SourceRange, time.Duration(config.RefreshInterval), sources)
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse CIDR whitelist %s: %w", config.SourceRange, err)
+		return nil, err
 	}
 
 	strategy, err := config.IPStrategy.Get()
@@ -49,7 +53,7 @@ func New(ctx context.Context, next http.Handler, config dynamic.IPWhiteList, nam
 
 	return &ipWhiteLister{
 		strategy:    strategy,
-		whiteLister: checker,
+		sourceRange: sourceRange,
 		next:        next,
 		name:        name,
 	}, nil
@@ -63,15 +67,19 @@ func (wl *ipWhiteLister) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	ctx := middlewares.GetLoggerCtx(req.Context(), wl.name, typeName)
 	logger := log.FromContext(ctx)
 
-	err := wl.whiteLister.IsAuthorized(wl.strategy.GetIP(req))
+	clientIP := wl.strategy.GetIP(req)
+	accesslog.AddDecision(req, "IPWhiteListClientIP", clientIP)
+
+	err := wl.sourceRange.Checker(ctx).IsAuthorized(clientIP)
 	if err != nil {
 		logMessage := fmt.Sprintf("rejecting request %+v: %v", req, err)
 		logger.Debug(logMessage)
 		tracing.SetErrorWithEvent(req, logMessage)
+		accesslog.SetRejectedBy(req, wl.name)
 		reject(ctx, rw)
 		return
 	}
-	logger.Debugf("Accept %s: %+v", wl.strategy.GetIP(req), req)
+	logger.Debugf("Accept %s: %+v", clientIP, req)
 
 	wl.next.ServeHTTP(rw, req)
 }
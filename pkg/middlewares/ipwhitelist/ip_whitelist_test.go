@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 )
 
 func TestNewIPWhiteLister(t *testing.T) {
@@ -38,7 +39,7 @@ func TestNewIPWhiteLister(t *testing.T) {
 			t.Parallel()
 
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
-			whiteLister, err := New(context.Background(), next, test.whiteList, "traefikTest")
+			whiteLister, err := New(context.Background(), next, test.whiteList, "traefikTest", nil)
 
 			if test.expectedError {
 				assert.Error(t, err)
@@ -81,7 +82,7 @@ func TestIPWhiteLister_ServeHTTP(t *testing.T) {
 			t.Parallel()
 
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
-			whiteLister, err := New(context.Background(), next, test.whiteList, "traefikTest")
+			whiteLister, err := New(context.Background(), next, test.whiteList, "traefikTest", nil)
 			require.NoError(t, err)
 
 			recorder := httptest.NewRecorder()
@@ -92,9 +93,19 @@ func TestIPWhiteLister_ServeHTTP(t *testing.T) {
 				req.RemoteAddr = test.remoteAddr
 			}
 
+			logData := &accesslog.LogData{Core: make(accesslog.CoreLogData)}
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
 			whiteLister.ServeHTTP(recorder, req)
 
 			assert.Equal(t, test.expected, recorder.Code)
+
+			if test.expected == http.StatusForbidden {
+				assert.Equal(t, "traefikTest", logData.Core[accesslog.RejectedBy])
+			} else {
+				assert.NotContains(t, logData.Core, accesslog.RejectedBy)
+			}
+			assert.Contains(t, logData.Core, accesslog.DecisionPrefix+"IPWhiteListClientIP")
 		})
 	}
 }
@@ -0,0 +1,314 @@
+package ipwhitelist
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// defaultRefreshInterval is the interval between two reads of the sources, when it is not configured.
+const defaultRefreshInterval = time.Minute
+
+var (
+	sourcesMu sync.Mutex
+	// sources holds the files and URLs listing IPs and CIDRs by location,
+	// shared by the middlewares and kept across the configuration reloads.
+	sources = make(map[string]*source)
+)
+
+// getSource returns the source of the given file or URL, which is read on first use.
+func getSource(location string) *source {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	if src, ok := sources[location]; ok {
+		return src
+	}
+
+	src := &source{
+		location: location,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		now:      time.Now,
+	}
+
+	sources[location] = src
+
+	return src
+}
+
+// sourceRange is the allowed IPs and CIDRs, some of them being listed by files or URLs which are read again periodically.
+// The checker is built again when the entries of one of the sources changed.
+type sourceRange struct {
+	ranges          []string
+	sources         []*source
+	refreshInterval time.Duration
+
+	mu      sync.Mutex
+	checker *ip.Checker
+	// versions are the versions of the entries of the sources the checker was built with.
+	versions []uint64
+}
+
+// newSourceRange creates a sourceRange, reading the sources which were never read successfully.
+// The files and the URLs must be allowed by the static configuration.
+func newSourceRange(ctx context.Context, entries []string, refreshInterval time.Duration, allowed *types.SourceRangeSources) (*sourceRange, error) {
+	if refreshInterval < 0 {
+		return nil, fmt.Errorf("invalid refreshInterval: %s", refreshInterval)
+	}
+
+	if refreshInterval == 0 {
+		refreshInterval = defaultRefreshInterval
+	}
+
+	s := &sourceRange{refreshInterval: refreshInterval}
+
+	for _, entry := range entries {
+		if isSource(entry) {
+			if !allowedSource(entry, allowed) {
+				return nil, fmt.Errorf("the source range %s is not allowed by the sourceRangeSources static configuration", entry)
+			}
+
+			s.sources = append(s.sources, getSource(entry))
+		} else {
+			s.ranges = append(s.ranges, entry)
+		}
+	}
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// isSource returns true if the entry is a file or a URL listing IPs and CIDRs, rather than an IP or a CIDR.
+func isSource(entry string) bool {
+	return strings.HasPrefix(entry, "http://") || strings.HasPrefix(entry, "https://") || filepath.IsAbs(entry)
+}
+
+func allowedSource(entry string, allowed *types.SourceRangeSources) bool {
+	if filepath.IsAbs(entry) {
+		return allowed.AllowsFile(entry)
+	}
+
+	return allowed.AllowsURL(entry)
+}
+
+// Checker returns the checker of the current entries of the sources,
+// which triggers the refresh of the sources older than the refresh interval.
+func (s *sourceRange) Checker(ctx context.Context) *ip.Checker {
+	if len(s.sources) == 0 {
+		return s.checker
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(ctx); err != nil {
+		log.FromContext(ctx).Errorf("Unable to update the source range, using the current one: %v", err)
+	}
+
+	return s.checker
+}
+
+func (s *sourceRange) load(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.loadLocked(ctx)
+}
+
+// loadLocked builds the checker of all the IPs and CIDRs, if the entries of one of the sources changed.
+func (s *sourceRange) loadLocked(ctx context.Context) error {
+	ranges := append([]string{}, s.ranges...)
+	versions := make([]uint64, len(s.sources))
+	changed := s.checker == nil
+
+	for i, src := range s.sources {
+		entries, version, err := src.entries(ctx, s.refreshInterval)
+		if err != nil {
+			return fmt.Errorf("unable to read the source range %s: %w", src.location, err)
+		}
+
+		ranges = append(ranges, entries...)
+		versions[i] = version
+		changed = changed || version != s.versions[i]
+	}
+
+	if !changed {
+		return nil
+	}
+
+	checker, err := ip.NewChecker(ranges)
+	if err != nil {
+		// The entries are not part of the error, as the ones of the sources must not be exposed.
+		return fmt.Errorf("cannot parse CIDR whitelist: %w", err)
+	}
+
+	s.checker = checker
+	s.versions = versions
+
+	return nil
+}
+
+// source is a file or a URL listing IPs and CIDRs.
+// It is read again in the background, triggered by the middlewares using it,
+// and its last successfully read entries are kept when it cannot be read.
+type source struct {
+	location string
+	client   *http.Client
+	now      func() time.Time
+
+	mu      sync.Mutex
+	list    []string
+	version uint64
+	// err is the error of the last read, returned while the source was never read successfully.
+	err        error
+	loaded     time.Time
+	refreshing bool
+}
+
+// entries returns the last successfully read entries of the source, and their version.
+// The source is read first if it was never read successfully, at most once per refresh interval,
+// and is read again in the background when its entries are older than the refresh interval.
+func (s *source) entries(ctx context.Context, refreshInterval time.Duration) ([]string, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stale := s.now().Sub(s.loaded) >= refreshInterval
+
+	if s.list == nil {
+		if !stale && s.err != nil {
+			return nil, 0, s.err
+		}
+
+		list, err := s.read()
+		s.loaded = s.now()
+		s.err = err
+		if err != nil {
+			return nil, 0, err
+		}
+
+		s.list = list
+		s.version++
+
+		return s.list, s.version, nil
+	}
+
+	if stale && !s.refreshing {
+		s.refresh(ctx)
+	}
+
+	return s.list, s.version, nil
+}
+
+// refresh reads the source in the background.
+// It must be called with the lock held.
+func (s *source) refresh(ctx context.Context) {
+	s.refreshing = true
+
+	logger := log.FromContext(ctx)
+
+	safe.Go(func() {
+		list, err := s.read()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.refreshing = false
+		// The failed reads are not retried before the next interval, not to hammer the sources.
+		s.loaded = s.now()
+
+		if err != nil {
+			logger.Errorf("Unable to refresh the source range %s, using the current one: %v", s.location, err)
+			return
+		}
+
+		if !equalEntries(s.list, list) {
+			s.list = list
+			s.version++
+		}
+	})
+}
+
+// read reads the entries of the source, which must all be valid IPs or CIDRs.
+func (s *source) read() ([]string, error) {
+	if filepath.IsAbs(s.location) {
+		file, err := os.Open(s.location)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = file.Close() }()
+
+		return parseSourceRange(file)
+	}
+
+	resp, err := s.client.Get(s.location)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return parseSourceRange(resp.Body)
+}
+
+func equalEntries(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseSourceRange reads one IP or CIDR per line, ignoring the empty lines and the comments starting with #.
+// The invalid lines are reported by their number only, not to expose the content of the source.
+func parseSourceRange(r io.Reader) ([]string, error) {
+	var entries []string
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(strings.SplitN(scanner.Text(), "#", 2)[0])
+		if line == "" {
+			continue
+		}
+
+		if _, err := ip.NewChecker([]string{line}); err != nil {
+			return nil, fmt.Errorf("invalid IP or CIDR on line %d", number)
+		}
+
+		entries = append(entries, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("no IPs listed")
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,284 @@
+package ipwhitelist
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestNewSourceRange(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "whitelist.txt")
+	err := os.WriteFile(filePath, []byte("# offices\n10.0.0.0/24\n\n20.20.20.20 # partner\n"), 0o600)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/cdn" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = rw.Write([]byte("30.30.30.0/24\n"))
+	}))
+	t.Cleanup(server.Close)
+
+	allowed := &types.SourceRangeSources{
+		Directories: []string{dir},
+		URLs:        []string{server.URL + "/"},
+	}
+
+	testCases := []struct {
+		desc            string
+		entries         []string
+		refreshInterval time.Duration
+		authorized      []string
+		unauthorized    []string
+		expectedError   bool
+	}{
+		{
+			desc:         "IPs and CIDRs",
+			entries:      []string{"40.40.40.40", "50.50.50.0/24"},
+			authorized:   []string{"40.40.40.40", "50.50.50.1"},
+			unauthorized: []string{"10.0.0.1"},
+		},
+		{
+			desc:         "file",
+			entries:      []string{filePath},
+			authorized:   []string{"10.0.0.1", "20.20.20.20"},
+			unauthorized: []string{"20.20.20.21"},
+		},
+		{
+			desc:         "URL",
+			entries:      []string{server.URL + "/cdn"},
+			authorized:   []string{"30.30.30.1"},
+			unauthorized: []string{"10.0.0.1"},
+		},
+		{
+			desc:         "IPs, file and URL",
+			entries:      []string{"40.40.40.40", filePath, server.URL + "/cdn"},
+			authorized:   []string{"40.40.40.40", "10.0.0.1", "30.30.30.1"},
+			unauthorized: []string{"50.50.50.1"},
+		},
+		{
+			desc:          "missing file",
+			entries:       []string{filepath.Join(dir, "missing.txt")},
+			expectedError: true,
+		},
+		{
+			desc:          "file not allowed",
+			entries:       []string{"/etc/passwd"},
+			expectedError: true,
+		},
+		{
+			desc:          "file out of the allowed directory",
+			entries:       []string{filepath.Join(dir, "..", "whitelist.txt")},
+			expectedError: true,
+		},
+		{
+			desc:          "URL not allowed",
+			entries:       []string{"http://169.254.169.254/latest/meta-data/"},
+			expectedError: true,
+		},
+		{
+			desc:          "URL not found",
+			entries:       []string{server.URL + "/missing"},
+			expectedError: true,
+		},
+		{
+			desc:            "invalid refresh interval",
+			entries:         []string{"40.40.40.40"},
+			refreshInterval: -time.Second,
+			expectedError:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			sourceRange, err := newSourceRange(context.Background(), test.entries, test.refreshInterval, allowed)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			checker := sourceRange.Checker(context.Background())
+			for _, addr := range test.authorized {
+				assert.NoError(t, checker.IsAuthorized(addr))
+			}
+			for _, addr := range test.unauthorized {
+				assert.Error(t, checker.IsAuthorized(addr))
+			}
+		})
+	}
+}
+
+func TestSourceRange_refresh(t *testing.T) {
+	var mu sync.Mutex
+	body, statusCode := "10.0.0.1", http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		rw.WriteHeader(statusCode)
+		_, _ = rw.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	allowed := &types.SourceRangeSources{URLs: []string{server.URL}}
+
+	now := time.Now()
+	src := getSource(server.URL)
+	src.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return now
+	}
+
+	sourceRange, err := newSourceRange(context.Background(), []string{server.URL}, time.Minute, allowed)
+	require.NoError(t, err)
+
+	update := func(newBody string, newStatusCode int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, statusCode = newBody, newStatusCode
+	}
+
+	advance := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now = now.Add(time.Minute)
+	}
+
+	waitRefresh := func() {
+		assert.Eventually(t, func() bool {
+			src.mu.Lock()
+			defer src.mu.Unlock()
+
+			return !src.refreshing
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	update("10.0.0.2", http.StatusOK)
+
+	// The source is not read again before the refresh interval.
+	assert.NoError(t, sourceRange.Checker(context.Background()).IsAuthorized("10.0.0.1"))
+
+	advance()
+	sourceRange.Checker(context.Background())
+
+	assert.Eventually(t, func() bool {
+		return sourceRange.Checker(context.Background()).IsAuthorized("10.0.0.2") == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Error(t, sourceRange.Checker(context.Background()).IsAuthorized("10.0.0.1"))
+
+	// A failed read keeps the current entries.
+	update("", http.StatusInternalServerError)
+
+	advance()
+	sourceRange.Checker(context.Background())
+	waitRefresh()
+
+	assert.NoError(t, sourceRange.Checker(context.Background()).IsAuthorized("10.0.0.2"))
+
+	// A list with an invalid entry keeps the current entries.
+	update("10.0.0.3\nfoo", http.StatusOK)
+
+	advance()
+	sourceRange.Checker(context.Background())
+	waitRefresh()
+
+	assert.NoError(t, sourceRange.Checker(context.Background()).IsAuthorized("10.0.0.2"))
+}
+
+func TestSourceRange_shared(t *testing.T) {
+	var reads int32
+	var failing int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&reads, 1)
+
+		if atomic.LoadInt32(&failing) == 1 {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write([]byte("10.0.0.1"))
+	}))
+	defer server.Close()
+
+	allowed := &types.SourceRangeSources{URLs: []string{server.URL}}
+
+	first, err := newSourceRange(context.Background(), []string{server.URL}, time.Minute, allowed)
+	require.NoError(t, err)
+
+	// The source is shared by the middlewares, and is not read again when a middleware is created.
+	second, err := newSourceRange(context.Background(), []string{server.URL, "10.0.0.2"}, time.Hour, allowed)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reads))
+	assert.NoError(t, first.Checker(context.Background()).IsAuthorized("10.0.0.1"))
+	assert.NoError(t, second.Checker(context.Background()).IsAuthorized("10.0.0.1"))
+	assert.NoError(t, second.Checker(context.Background()).IsAuthorized("10.0.0.2"))
+
+	// The middlewares are still created with the last entries when the source cannot be read.
+	atomic.StoreInt32(&failing, 1)
+
+	third, err := newSourceRange(context.Background(), []string{server.URL}, time.Nanosecond, allowed)
+	require.NoError(t, err)
+
+	assert.NoError(t, third.Checker(context.Background()).IsAuthorized("10.0.0.1"))
+}
+
+func TestSourceRange_unreadSource(t *testing.T) {
+	var reads int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&reads, 1)
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	allowed := &types.SourceRangeSources{URLs: []string{server.URL}}
+
+	// A source which was never read fails the creation of the middlewares,
+	// and is not read again before the refresh interval.
+	_, err := newSourceRange(context.Background(), []string{server.URL}, time.Minute, allowed)
+	assert.Error(t, err)
+
+	_, err = newSourceRange(context.Background(), []string{server.URL}, time.Minute, allowed)
+	assert.Error(t, err)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reads))
+}
+
+func TestParseSourceRange(t *testing.T) {
+	entries, err := parseSourceRange(strings.NewReader("  10.0.0.1  \n# comment\n\n10.0.1.0/24 # office\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"10.0.0.1", "10.0.1.0/24"}, entries)
+
+	_, err = parseSourceRange(strings.NewReader("# comment\n"))
+	assert.Error(t, err)
+
+	// The content of the source is not part of the error.
+	_, err = parseSourceRange(strings.NewReader("10.0.0.1\nroot:x:0:0:root:/root:/bin/bash\n"))
+	assert.EqualError(t, err, "invalid IP or CIDR on line 2")
+}
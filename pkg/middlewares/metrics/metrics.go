@@ -13,6 +13,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/operation"
 	"github.com/traefik/traefik/v2/pkg/middlewares/retry"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
 )
@@ -33,6 +34,8 @@ type metricsMiddleware struct {
 	reqDurationHistogram metrics.ScalableHistogram
 	openConnsGauge       gokitmetrics.Gauge
 	baseLabels           []string
+	// operationLabel adds the operation of the classified requests to the labels of the requests counter and duration histogram.
+	operationLabel bool
 }
 
 // NewEntryPointMiddleware creates a new metrics middleware for an Entrypoint.
@@ -60,6 +63,7 @@ func NewRouterMiddleware(ctx context.Context, next http.Handler, registry metric
 		reqDurationHistogram: registry.RouterReqDurationHistogram(),
 		openConnsGauge:       registry.RouterOpenConnsGauge(),
 		baseLabels:           []string{"router", routerName, "service", serviceName},
+		operationLabel:       true,
 	}
 }
 
@@ -74,6 +78,7 @@ func NewServiceMiddleware(ctx context.Context, next http.Handler, registry metri
 		reqDurationHistogram: registry.ServiceReqDurationHistogram(),
 		openConnsGauge:       registry.ServiceOpenConnsGauge(),
 		baseLabels:           []string{"service", serviceName},
+		operationLabel:       true,
 	}
 }
 
@@ -120,6 +125,10 @@ func (m *metricsMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 
 	m.next.ServeHTTP(recorder, req)
 
+	if op, classified := operation.GetOperation(req.Context()); m.operationLabel && classified {
+		labels = append(labels, "operation", op)
+	}
+
 	labels = append(labels, "code", strconv.Itoa(recorder.getCode()))
 
 	histograms := m.reqDurationHistogram.With(labels...)
@@ -0,0 +1,139 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+type key struct{}
+
+var operationKey key
+
+// methodPlaceholder is replaced, in the operation names, by the lowercased request method.
+const methodPlaceholder = "{method}"
+
+// placeholder matches the placeholders of the path templates.
+var placeholder = regexp.MustCompile(`\{[^/{}]*\}`)
+
+// Classifier classifies the requests into low-cardinality operations,
+// e.g. GET /users/123/orders/456 into users.orders.get.
+type Classifier struct {
+	operations []operation
+}
+
+type operation struct {
+	name    string
+	methods map[string]struct{}
+	path    *regexp.Regexp
+}
+
+// New creates a new Classifier, which classifies the requests with the first matching operation.
+func New(operations []types.MetricsOperation) (*Classifier, error) {
+	classifier := &Classifier{}
+
+	for _, op := range operations {
+		if op.Name == "" {
+			return nil, fmt.Errorf("operation name is required, path: %q, path regex: %q", op.Path, op.PathRegex)
+		}
+
+		path, err := compilePath(op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %s: %w", op.Name, err)
+		}
+
+		var methods map[string]struct{}
+		if len(op.Methods) > 0 {
+			methods = make(map[string]struct{})
+			for _, method := range op.Methods {
+				methods[strings.ToUpper(method)] = struct{}{}
+			}
+		}
+
+		classifier.operations = append(classifier.operations, operation{
+			name:    op.Name,
+			methods: methods,
+			path:    path,
+		})
+	}
+
+	return classifier, nil
+}
+
+// compilePath compiles the path template of the operation, or its path regex if the template is empty.
+func compilePath(op types.MetricsOperation) (*regexp.Regexp, error) {
+	if op.Path == "" {
+		if op.PathRegex == "" {
+			return nil, nil
+		}
+
+		return regexp.Compile(op.PathRegex)
+	}
+
+	if !strings.HasPrefix(op.Path, "/") {
+		return nil, fmt.Errorf("path template %q must start with a /", op.Path)
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	last := 0
+	for _, loc := range placeholder.FindAllStringIndex(op.Path, -1) {
+		expr.WriteString(regexp.QuoteMeta(op.Path[last:loc[0]]))
+		expr.WriteString("[^/]+")
+		last = loc[1]
+	}
+
+	expr.WriteString(regexp.QuoteMeta(op.Path[last:]))
+	expr.WriteString("$")
+
+	return regexp.Compile(expr.String())
+}
+
+// Classify returns the operation of the request, or an empty string if the request matches no operation.
+func (c *Classifier) Classify(req *http.Request) string {
+	for _, op := range c.operations {
+		if op.methods != nil {
+			if _, ok := op.methods[req.Method]; !ok {
+				continue
+			}
+		}
+
+		if op.path != nil && !op.path.MatchString(req.URL.Path) {
+			continue
+		}
+
+		return strings.ReplaceAll(op.name, methodPlaceholder, strings.ToLower(req.Method))
+	}
+
+	return ""
+}
+
+func (c *Classifier) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+	// The operation is set even if the request matches no operation,
+	// so that the metrics of the classified requests always have the operation label.
+	req = req.WithContext(context.WithValue(req.Context(), operationKey, c.Classify(req)))
+
+	next.ServeHTTP(rw, req)
+}
+
+// GetOperation returns the operation of the request set in the context by the classifier,
+// which is empty if the request matches no operation, and whether the request was classified.
+func GetOperation(ctx context.Context) (string, bool) {
+	op, ok := ctx.Value(operationKey).(string)
+	return op, ok
+}
+
+// WrapHandler Wraps a Classifier to alice.Constructor.
+func WrapHandler(c *Classifier) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			c.ServeHTTP(rw, req, next)
+		}), nil
+	}
+}
@@ -0,0 +1,151 @@
+package operation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestClassifier_Classify(t *testing.T) {
+	operations := []types.MetricsOperation{
+		{Name: "users.orders.{method}", Path: "/users/{id}/orders/{id}"},
+		{Name: "users.create", Methods: []string{"post"}, Path: "/users"},
+		{Name: "users.get", Methods: []string{http.MethodGet}, Path: "/users/{id}"},
+		{Name: "reports.export", PathRegex: `^/reports/[0-9]+\.(csv|json)$`},
+		{Name: "files.get", Path: "/files/{name}.json"},
+	}
+
+	testCases := []struct {
+		desc     string
+		method   string
+		path     string
+		expected string
+	}{
+		{
+			desc:     "path template with method placeholder",
+			method:   http.MethodGet,
+			path:     "/users/123/orders/456",
+			expected: "users.orders.get",
+		},
+		{
+			desc:     "path template with another method",
+			method:   http.MethodDelete,
+			path:     "/users/123/orders/456",
+			expected: "users.orders.delete",
+		},
+		{
+			desc:     "placeholder does not match several segments",
+			method:   http.MethodGet,
+			path:     "/users/123/orders/456/items",
+			expected: "",
+		},
+		{
+			desc:     "lowercased method",
+			method:   http.MethodPost,
+			path:     "/users",
+			expected: "users.create",
+		},
+		{
+			desc:     "method not matching",
+			method:   http.MethodPut,
+			path:     "/users/123",
+			expected: "",
+		},
+		{
+			desc:     "path regex",
+			method:   http.MethodGet,
+			path:     "/reports/42.csv",
+			expected: "reports.export",
+		},
+		{
+			desc:     "placeholder inside a segment",
+			method:   http.MethodGet,
+			path:     "/files/foo.json",
+			expected: "files.get",
+		},
+		{
+			desc:     "template literal is not a regex",
+			method:   http.MethodGet,
+			path:     "/files/fooxjson",
+			expected: "",
+		},
+		{
+			desc:     "no matching operation",
+			method:   http.MethodGet,
+			path:     "/foo",
+			expected: "",
+		},
+	}
+
+	classifier, err := New(operations)
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(test.method, "http://localhost"+test.path, nil)
+
+			assert.Equal(t, test.expected, classifier.Classify(req))
+		})
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		operation types.MetricsOperation
+	}{
+		{
+			desc:      "missing name",
+			operation: types.MetricsOperation{Path: "/users"},
+		},
+		{
+			desc:      "invalid path regex",
+			operation: types.MetricsOperation{Name: "foo", PathRegex: "/users/(["},
+		},
+		{
+			desc:      "relative path template",
+			operation: types.MetricsOperation{Name: "foo", Path: "users/{id}"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New([]types.MetricsOperation{test.operation})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWrapHandler(t *testing.T) {
+	classifier, err := New([]types.MetricsOperation{{Name: "users.{method}", Path: "/users/{id}"}})
+	require.NoError(t, err)
+
+	var op string
+	var classified bool
+	handler, err := WrapHandler(classifier)(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		op, classified = GetOperation(req.Context())
+	}))
+	require.NoError(t, err)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/users/123", nil))
+	assert.Equal(t, "users.get", op)
+	assert.True(t, classified)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil))
+	assert.Equal(t, "", op)
+	assert.True(t, classified)
+
+	_, classified = GetOperation(context.Background())
+	assert.False(t, classified)
+}
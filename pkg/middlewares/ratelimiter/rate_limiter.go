@@ -12,6 +12,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/vulcand/oxy/utils"
 	"golang.org/x/time/rate"
@@ -116,6 +117,8 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		logger.Infof("ignoring token bucket amount > 1: %d", amount)
 	}
 
+	accesslog.AddDecision(r, "RateLimitBucket", source)
+
 	var bucket *rate.Limiter
 	if rlSource, exists := rl.buckets.Get(source); exists {
 		bucket = rlSource.(*rate.Limiter)
@@ -130,6 +133,7 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	res := bucket.Reserve()
 	if !res.OK() {
+		accesslog.SetRejectedBy(r, rl.name)
 		http.Error(w, "No bursty traffic allowed", http.StatusTooManyRequests)
 		return
 	}
@@ -137,6 +141,7 @@ func (rl *rateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	delay := res.Delay()
 	if delay > rl.maxDelay {
 		res.Cancel()
+		accesslog.SetRejectedBy(r, rl.name)
 		rl.serveDelayError(ctx, w, r, delay)
 		return
 	}
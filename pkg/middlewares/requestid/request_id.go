@@ -0,0 +1,207 @@
+package requestid
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "RequestID"
+)
+
+const (
+	defaultHeaderName = "X-Request-Id"
+	formatUUID        = "uuid"
+	formatULID        = "ulid"
+)
+
+// maxLength is the maximum length of the incoming request IDs which are reused.
+const maxLength = 128
+
+// crockford is the Crockford's Base32 alphabet, used to encode the ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// requestID is a middleware setting a unique ID on each request.
+type requestID struct {
+	next       http.Handler
+	name       string
+	headerName string
+	generate   func() (string, error)
+}
+
+// New creates a new RequestID middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RequestID, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	headerName := config.HeaderName
+	if headerName == "" {
+		headerName = defaultHeaderName
+	}
+
+	var generate func() (string, error)
+	switch config.Format {
+	case "", formatUUID:
+		generate = newUUID
+	case formatULID:
+		generate = func() (string, error) {
+			return newULID(time.Now())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported request ID format: %s", config.Format)
+	}
+
+	return &requestID{
+		next:       next,
+		name:       name,
+		headerName: http.CanonicalHeaderKey(headerName),
+		generate:   generate,
+	}, nil
+}
+
+func (r *requestID) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *requestID) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	id := req.Header.Get(r.headerName)
+	if !isValid(id) {
+		var err error
+		id, err = r.generate()
+		if err != nil {
+			logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName))
+			logger.Errorf("Unable to generate a request ID: %v", err)
+
+			r.next.ServeHTTP(rw, req)
+			return
+		}
+	}
+
+	req.Header.Set(r.headerName, id)
+
+	if logData := accesslog.GetLogData(req); logData != nil {
+		logData.Core[accesslog.RequestID] = id
+	}
+
+	if span := tracing.GetSpan(req); span != nil {
+		span.SetTag("http.request_id", id)
+	}
+
+	r.next.ServeHTTP(&responseWriter{ResponseWriter: rw, headerName: r.headerName, id: id}, req)
+}
+
+// isValid tells whether an incoming request ID can be reused,
+// i.e. it is not empty, not too long, and only made of visible ASCII characters.
+func isValid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// newULID returns a ULID: a 48 bits timestamp in milliseconds, followed by 80 random bits,
+// encoded with the Crockford's Base32 alphabet.
+func newULID(now time.Time) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	ms := uint64(now.UnixNano() / int64(time.Millisecond))
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	// The 128 bits are encoded as 26 characters of 5 bits, the first character holding the 3 most significant bits.
+	dst := make([]byte, 26)
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(dst), nil
+}
+
+// responseWriter sets the request ID header on the response,
+// overriding the one the next handlers may have set.
+type responseWriter struct {
+	http.ResponseWriter
+	headerName  string
+	id          string
+	headersSent bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.headersSent {
+		w.ResponseWriter.Header().Set(w.headerName, w.id)
+		w.headersSent = true
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.headersSent {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Hijack hijacks the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", w.ResponseWriter)
+	}
+
+	return hijacker.Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (w *responseWriter) Flush() {
+	if !w.headersSent {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
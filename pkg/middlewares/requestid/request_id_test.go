@@ -0,0 +1,156 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+)
+
+var (
+	uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulidRegexp = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.RequestID
+		expectErr bool
+	}{
+		{
+			desc:   "defaults",
+			config: dynamic.RequestID{},
+		},
+		{
+			desc:   "ULID format",
+			config: dynamic.RequestID{Format: "ulid"},
+		},
+		{
+			desc:      "unsupported format",
+			config:    dynamic.RequestID{Format: "foo"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "foo")
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRequestID_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		config     dynamic.RequestID
+		incoming   map[string]string
+		backend    map[string]string
+		expectedID *regexp.Regexp
+		header     string
+	}{
+		{
+			desc:       "generated UUID",
+			expectedID: uuidRegexp,
+			header:     "X-Request-Id",
+		},
+		{
+			desc:       "generated ULID with a custom header",
+			config:     dynamic.RequestID{HeaderName: "x-correlation-id", Format: "ulid"},
+			expectedID: ulidRegexp,
+			header:     "X-Correlation-Id",
+		},
+		{
+			desc:       "reused incoming ID",
+			incoming:   map[string]string{"X-Request-Id": "my-id"},
+			expectedID: regexp.MustCompile(`^my-id$`),
+			header:     "X-Request-Id",
+		},
+		{
+			desc:       "invalid incoming ID",
+			incoming:   map[string]string{"X-Request-Id": "my id"},
+			expectedID: uuidRegexp,
+			header:     "X-Request-Id",
+		},
+		{
+			desc:       "too long incoming ID",
+			incoming:   map[string]string{"X-Request-Id": strings.Repeat("a", maxLength+1)},
+			expectedID: uuidRegexp,
+			header:     "X-Request-Id",
+		},
+		{
+			desc:       "backend response header overridden",
+			backend:    map[string]string{"X-Request-Id": "backend"},
+			expectedID: uuidRegexp,
+			header:     "X-Request-Id",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var forwarded string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				forwarded = req.Header.Get(test.header)
+
+				for name, value := range test.backend {
+					rw.Header().Set(name, value)
+				}
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", nil)
+			for name, value := range test.incoming {
+				req.Header.Set(name, value)
+			}
+
+			logData := &accesslog.LogData{Core: accesslog.CoreLogData{}}
+			req = req.WithContext(context.WithValue(req.Context(), accesslog.DataTableKey, logData))
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Regexp(t, test.expectedID, forwarded)
+			assert.Equal(t, forwarded, recorder.Header().Get(test.header))
+			assert.Equal(t, forwarded, logData.Core[accesslog.RequestID])
+		})
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	now := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	first, err := newULID(now)
+	require.NoError(t, err)
+
+	second, err := newULID(now.Add(time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Regexp(t, ulidRegexp, first)
+	assert.Regexp(t, ulidRegexp, second)
+
+	// The timestamp is encoded in the first 10 characters, so that the ULIDs sort by time.
+	assert.Equal(t, "01EZPKNX80", first[:10])
+	assert.Less(t, first, second)
+}
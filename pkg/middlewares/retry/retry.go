@@ -5,15 +5,14 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"math"
 	"net"
 	"net/http"
 	"net/http/httptrace"
 	"time"
 
-	"github.com/cenkalti/backoff/v4"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/tracing"
@@ -37,11 +36,6 @@ type Listener interface {
 // each of them about a retry attempt.
 type Listeners []Listener
 
-// nexter returns the duration to wait before retrying the operation.
-type nexter interface {
-	NextBackOff() time.Duration
-}
-
 // retry is a middleware that retries requests.
 type retry struct {
 	attempts        int
@@ -82,7 +76,7 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	attempts := 1
-	backOff := r.newBackOff()
+	backOff := job.NewAttemptsBackOff(r.attempts, r.initialInterval)
 	currentInterval := 0 * time.Millisecond
 	for {
 		select {
@@ -123,24 +117,6 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func (r *retry) newBackOff() nexter {
-	if r.attempts < 2 || r.initialInterval <= 0 {
-		return &backoff.ZeroBackOff{}
-	}
-
-	b := backoff.NewExponentialBackOff()
-	b.InitialInterval = r.initialInterval
-
-	// calculate the multiplier for the given number of attempts
-	// so that applying the multiplier for the given number of attempts will not exceed 2 times the initial interval
-	// it allows to control the progression along the attempts
-	b.Multiplier = math.Pow(2, 1/float64(r.attempts-1))
-
-	// according to docs, b.Reset() must be called before using
-	b.Reset()
-	return b
-}
-
 // Retried exists to implement the Listener interface. It calls Retried on each of its slice entries.
 func (l Listeners) Retried(req *http.Request, attempt int) {
 	for _, listener := range l {
@@ -0,0 +1,252 @@
+package rewritebody
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "RewriteBody"
+
+	defaultContentType = "text/html"
+	defaultMaxBodySize = 1024 * 1024
+)
+
+type rewrite struct {
+	regex       *regexp.Regexp
+	replacement []byte
+}
+
+// rewriteBody is a middleware which applies regular expression replacements to the bodies of the responses
+// of the selected content types.
+type rewriteBody struct {
+	name         string
+	next         http.Handler
+	rewrites     []rewrite
+	contentTypes []string
+	maxBodySize  int64
+}
+
+// New creates a new RewriteBody middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RewriteBody, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.Rewrites) == 0 {
+		return nil, errors.New("no rewrites")
+	}
+
+	if config.MaxBodySize < 0 {
+		return nil, fmt.Errorf("invalid maximum body size: %d", config.MaxBodySize)
+	}
+
+	rewrites := make([]rewrite, 0, len(config.Rewrites))
+	for _, rw := range config.Rewrites {
+		regex, err := regexp.Compile(rw.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", rw.Regex, err)
+		}
+
+		rewrites = append(rewrites, rewrite{regex: regex, replacement: []byte(rw.Replacement)})
+	}
+
+	contentTypes := []string{defaultContentType}
+	if len(config.ContentTypes) > 0 {
+		contentTypes = make([]string, 0, len(config.ContentTypes))
+		for _, contentType := range config.ContentTypes {
+			contentTypes = append(contentTypes, strings.ToLower(strings.TrimSpace(contentType)))
+		}
+	}
+
+	maxBodySize := config.MaxBodySize
+	if maxBodySize == 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+
+	return &rewriteBody{
+		name:         name,
+		next:         next,
+		rewrites:     rewrites,
+		contentTypes: contentTypes,
+		maxBodySize:  maxBodySize,
+	}, nil
+}
+
+func (r *rewriteBody) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return r.name, tracing.SpanKindNoneEnum
+}
+
+func (r *rewriteBody) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The bodies are rewritten uncompressed, the compress middleware can compress them afterwards.
+	req.Header.Del("Accept-Encoding")
+
+	writer := &responseWriter{
+		rw:       rw,
+		rewriter: r,
+		head:     req.Method == http.MethodHead,
+	}
+
+	r.next.ServeHTTP(writer, req)
+
+	if err := writer.finish(); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), r.name, typeName)).Errorf("Unable to write the rewritten body: %v", err)
+	}
+}
+
+// rewritable returns whether the body of a response with the given status code and headers is rewritten.
+func (r *rewriteBody) rewritable(code int, header http.Header) bool {
+	if code == http.StatusNoContent || code == http.StatusNotModified || code == http.StatusPartialContent {
+		return false
+	}
+
+	if encoding := header.Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		return false
+	}
+
+	if contentLength := header.Get("Content-Length"); contentLength != "" {
+		length, err := strconv.ParseInt(contentLength, 10, 64)
+		if err == nil && length > r.maxBodySize {
+			return false
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	for _, contentType := range r.contentTypes {
+		if contentType == mediaType || strings.HasSuffix(contentType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(contentType, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *rewriteBody) rewrite(body []byte) []byte {
+	for _, rw := range r.rewrites {
+		body = rw.regex.ReplaceAll(body, rw.replacement)
+	}
+
+	return body
+}
+
+// responseWriter buffers the bodies to rewrite, and forwards the other ones unchanged.
+type responseWriter struct {
+	rw       http.ResponseWriter
+	rewriter *rewriteBody
+	head     bool
+
+	wroteHeader bool
+	code        int
+	buffering   bool
+	buf         bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.rw.Header()
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+
+	// Informational responses are forwarded, the final response follows.
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.rw.WriteHeader(code)
+		return
+	}
+
+	w.wroteHeader = true
+	w.code = code
+	w.buffering = !w.head && w.rewriter.rewritable(code, w.rw.Header())
+
+	if !w.buffering {
+		w.rw.WriteHeader(code)
+	}
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.buffering {
+		return w.rw.Write(p)
+	}
+
+	if int64(w.buf.Len()+len(p)) > w.rewriter.maxBodySize {
+		// The body is too large to be rewritten, it is forwarded unchanged.
+		if err := w.stream(); err != nil {
+			return 0, err
+		}
+
+		return w.rw.Write(p)
+	}
+
+	return w.buf.Write(p)
+}
+
+// Hijack hijacks the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.rw.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, fmt.Errorf("not a hijacker: %T", w.rw)
+}
+
+// Flush sends any buffered data to the client, unless the body is being buffered to be rewritten.
+func (w *responseWriter) Flush() {
+	if w.buffering {
+		return
+	}
+
+	if flusher, ok := w.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// stream gives up rewriting the body, and forwards what was buffered.
+func (w *responseWriter) stream() error {
+	w.buffering = false
+	w.rw.WriteHeader(w.code)
+
+	_, err := w.rw.Write(w.buf.Bytes())
+	w.buf.Reset()
+
+	return err
+}
+
+// finish rewrites the buffered body, if any, and writes it.
+func (w *responseWriter) finish() error {
+	if !w.buffering {
+		return nil
+	}
+	w.buffering = false
+
+	body := w.rewriter.rewrite(w.buf.Bytes())
+
+	w.rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.rw.WriteHeader(w.code)
+
+	_, err := w.rw.Write(body)
+
+	return err
+}
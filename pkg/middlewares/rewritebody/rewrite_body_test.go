@@ -0,0 +1,199 @@
+package rewritebody
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    dynamic.RewriteBody
+		expectErr bool
+	}{
+		{
+			desc:   "valid",
+			config: dynamic.RewriteBody{Rewrites: []dynamic.BodyRewrite{{Regex: "foo(.*)", Replacement: "bar$1"}}},
+		},
+		{
+			desc:      "no rewrites",
+			config:    dynamic.RewriteBody{},
+			expectErr: true,
+		},
+		{
+			desc:      "invalid regex",
+			config:    dynamic.RewriteBody{Rewrites: []dynamic.BodyRewrite{{Regex: "foo(", Replacement: "bar"}}},
+			expectErr: true,
+		},
+		{
+			desc: "negative max body size",
+			config: dynamic.RewriteBody{
+				Rewrites:    []dynamic.BodyRewrite{{Regex: "foo", Replacement: "bar"}},
+				MaxBodySize: -1,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "foo")
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRewriteBody_ServeHTTP(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.RewriteBody
+		method          string
+		code            int
+		headers         map[string]string
+		body            string
+		expectedBody    string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc:         "HTML rewritten",
+			headers:      map[string]string{"Content-Type": "text/html", "Content-Length": "42"},
+			body:         `<a href="http://backend.internal/foo">foo</a>`,
+			expectedBody: `<a href="/legacy/foo">foo</a>`,
+			expectedHeaders: map[string]string{
+				"Content-Length": strconv.Itoa(len(`<a href="/legacy/foo">foo</a>`)),
+			},
+		},
+		{
+			desc:         "content type with parameters",
+			headers:      map[string]string{"Content-Type": "text/html; charset=utf-8"},
+			body:         `http://backend.internal/foo`,
+			expectedBody: `/legacy/foo`,
+		},
+		{
+			desc:         "content type not selected",
+			headers:      map[string]string{"Content-Type": "application/json"},
+			body:         `{"url":"http://backend.internal/foo"}`,
+			expectedBody: `{"url":"http://backend.internal/foo"}`,
+		},
+		{
+			desc:         "content type wildcard",
+			config:       dynamic.RewriteBody{ContentTypes: []string{"application/*"}},
+			headers:      map[string]string{"Content-Type": "application/json"},
+			body:         `{"url":"http://backend.internal/foo"}`,
+			expectedBody: `{"url":"/legacy/foo"}`,
+		},
+		{
+			desc:         "compressed body",
+			headers:      map[string]string{"Content-Type": "text/html", "Content-Encoding": "gzip"},
+			body:         `http://backend.internal/foo`,
+			expectedBody: `http://backend.internal/foo`,
+		},
+		{
+			desc:         "body too large",
+			config:       dynamic.RewriteBody{MaxBodySize: 10},
+			headers:      map[string]string{"Content-Type": "text/html"},
+			body:         `http://backend.internal/foo`,
+			expectedBody: `http://backend.internal/foo`,
+		},
+		{
+			desc:         "declared length too large",
+			config:       dynamic.RewriteBody{MaxBodySize: 10},
+			headers:      map[string]string{"Content-Type": "text/html", "Content-Length": "27"},
+			body:         `http://backend.internal/foo`,
+			expectedBody: `http://backend.internal/foo`,
+		},
+		{
+			desc:    "no content",
+			code:    http.StatusNoContent,
+			headers: map[string]string{"Content-Type": "text/html"},
+		},
+		{
+			desc:   "HEAD request",
+			method: http.MethodHead,
+			headers: map[string]string{
+				"Content-Type":   "text/html",
+				"Content-Length": "27",
+			},
+			expectedHeaders: map[string]string{"Content-Length": "27"},
+		},
+		{
+			desc:         "several rewrites applied in order",
+			config:       dynamic.RewriteBody{Rewrites: []dynamic.BodyRewrite{{Regex: "foo", Replacement: "bar"}, {Regex: "bar", Replacement: "baz"}}},
+			headers:      map[string]string{"Content-Type": "text/html"},
+			body:         `foo bar`,
+			expectedBody: `baz baz`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := test.config
+			if len(config.Rewrites) == 0 {
+				config.Rewrites = []dynamic.BodyRewrite{{Regex: `http://backend\.internal/(\w+)`, Replacement: "/legacy/$1"}}
+			}
+
+			code := test.code
+			if code == 0 {
+				code = http.StatusOK
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Empty(t, req.Header.Get("Accept-Encoding"))
+
+				for k, v := range test.headers {
+					rw.Header().Set(k, v)
+				}
+				rw.WriteHeader(code)
+
+				if test.body == "" {
+					return
+				}
+
+				// The body is written in several chunks.
+				for _, chunk := range strings.SplitAfter(test.body, "/") {
+					_, err := rw.Write([]byte(chunk))
+					require.NoError(t, err)
+				}
+			})
+
+			handler, err := New(context.Background(), next, config, "foo")
+			require.NoError(t, err)
+
+			method := test.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, "http://localhost", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, code, rw.Code)
+			assert.Equal(t, test.expectedBody, rw.Body.String())
+
+			for k, v := range test.expectedHeaders {
+				assert.Equal(t, v, rw.Header().Get(k))
+			}
+		})
+	}
+}
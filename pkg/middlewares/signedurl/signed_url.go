@@ -0,0 +1,201 @@
+package signedurl
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "SignedURL"
+)
+
+const (
+	encodingHex       = "hex"
+	encodingBase64URL = "base64url"
+)
+
+// signedURL is a middleware which only lets through the requests whose URL holds
+// a valid HMAC signature, and an expiry timestamp not yet reached.
+type signedURL struct {
+	next           http.Handler
+	name           string
+	secret         []byte
+	hash           func() hash.Hash
+	encoding       string
+	expiresParam   string
+	signatureParam string
+	now            func() time.Time
+}
+
+// New creates a new SignedURL middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.SignedURL, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if config.Secret == "" {
+		return nil, errors.New("secret cannot be empty")
+	}
+
+	if config.ExpiresParam == "" || config.SignatureParam == "" {
+		return nil, errors.New("expiresParam and signatureParam cannot be empty")
+	}
+
+	if config.ExpiresParam == config.SignatureParam {
+		return nil, fmt.Errorf("expiresParam and signatureParam must differ: %q", config.ExpiresParam)
+	}
+
+	var hashFunc func() hash.Hash
+	switch strings.ToLower(config.Algorithm) {
+	case "sha1":
+		hashFunc = sha1.New
+	case "", "sha256":
+		hashFunc = sha256.New
+	case "sha512":
+		hashFunc = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %q", config.Algorithm)
+	}
+
+	encoding := strings.ToLower(config.Encoding)
+	switch encoding {
+	case "":
+		encoding = encodingHex
+	case encodingHex, encodingBase64URL:
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %q", config.Encoding)
+	}
+
+	return &signedURL{
+		next:           next,
+		name:           name,
+		secret:         []byte(config.Secret),
+		hash:           hashFunc,
+		encoding:       encoding,
+		expiresParam:   config.ExpiresParam,
+		signatureParam: config.SignatureParam,
+		now:            time.Now,
+	}, nil
+}
+
+func (s *signedURL) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return s.name, tracing.SpanKindNoneEnum
+}
+
+func (s *signedURL) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := middlewares.GetLoggerCtx(req.Context(), s.name, typeName)
+	logger := log.FromContext(ctx)
+
+	if err := s.validate(req.URL); err != nil {
+		logMessage := fmt.Sprintf("Rejecting request to %s: %v", req.URL.Path, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+		reject(ctx, rw)
+		return
+	}
+
+	s.next.ServeHTTP(rw, req)
+}
+
+// validate checks that the URL is signed, and not expired.
+func (s *signedURL) validate(u *url.URL) error {
+	query := u.Query()
+
+	rawExpires := query.Get(s.expiresParam)
+	if rawExpires == "" {
+		return fmt.Errorf("missing %s parameter", s.expiresParam)
+	}
+
+	expires, err := strconv.ParseInt(rawExpires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s parameter: %w", s.expiresParam, err)
+	}
+
+	rawSignature := query.Get(s.signatureParam)
+	if rawSignature == "" {
+		return fmt.Errorf("missing %s parameter", s.signatureParam)
+	}
+
+	signature, err := s.decode(rawSignature)
+	if err != nil {
+		return fmt.Errorf("invalid %s parameter: %w", s.signatureParam, err)
+	}
+
+	if !hmac.Equal(signature, s.sign(u)) {
+		return errors.New("invalid signature")
+	}
+
+	// The expiry is checked once the signature is known to be valid, to not leak it for tampered URLs.
+	if s.now().Unix() > expires {
+		return fmt.Errorf("expired at %s", time.Unix(expires, 0).UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// sign computes the HMAC of the escaped path, and of the raw query without the signature parameter,
+// i.e. of "/path?foo=bar&expires=1600000000" for "/path?foo=bar&expires=1600000000&signature=abcd".
+func (s *signedURL) sign(u *url.URL) []byte {
+	var params []string
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		if param == "" {
+			continue
+		}
+
+		key := param
+		if i := strings.Index(param, "="); i >= 0 {
+			key = param[:i]
+		}
+
+		if unescaped, err := url.QueryUnescape(key); err == nil && unescaped == s.signatureParam {
+			continue
+		}
+
+		params = append(params, param)
+	}
+
+	message := u.EscapedPath()
+	if len(params) > 0 {
+		message += "?" + strings.Join(params, "&")
+	}
+
+	mac := hmac.New(s.hash, s.secret)
+	_, _ = mac.Write([]byte(message))
+
+	return mac.Sum(nil)
+}
+
+func (s *signedURL) decode(signature string) ([]byte, error) {
+	if s.encoding == encodingBase64URL {
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(signature, "="))
+	}
+
+	return hex.DecodeString(signature)
+}
+
+func reject(ctx context.Context, rw http.ResponseWriter) {
+	statusCode := http.StatusForbidden
+
+	rw.WriteHeader(statusCode)
+	_, err := rw.Write([]byte(http.StatusText(statusCode)))
+	if err != nil {
+		log.FromContext(ctx).Error(err)
+	}
+}
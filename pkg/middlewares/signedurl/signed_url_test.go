@@ -0,0 +1,187 @@
+package signedurl
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNewSignedURL(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.SignedURL
+		expectedError bool
+	}{
+		{
+			desc:   "default config",
+			config: dynamic.SignedURL{Secret: "secret", ExpiresParam: "expires", SignatureParam: "signature"},
+		},
+		{
+			desc:          "no secret",
+			config:        dynamic.SignedURL{ExpiresParam: "expires", SignatureParam: "signature"},
+			expectedError: true,
+		},
+		{
+			desc:          "same parameters",
+			config:        dynamic.SignedURL{Secret: "secret", ExpiresParam: "foo", SignatureParam: "foo"},
+			expectedError: true,
+		},
+		{
+			desc:          "unsupported algorithm",
+			config:        dynamic.SignedURL{Secret: "secret", Algorithm: "md5", ExpiresParam: "expires", SignatureParam: "signature"},
+			expectedError: true,
+		},
+		{
+			desc:          "unsupported encoding",
+			config:        dynamic.SignedURL{Secret: "secret", Encoding: "base32", ExpiresParam: "expires", SignatureParam: "signature"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			_, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSignedURL_ServeHTTP(t *testing.T) {
+	now := time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC)
+
+	testCases := []struct {
+		desc           string
+		config         dynamic.SignedURL
+		url            string
+		expectedStatus int
+	}{
+		{
+			desc:           "valid signature",
+			url:            "/download/file.zip?expires=1600000000&signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip?expires=1600000000"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "valid signature with other parameters",
+			url:            "/download/file.zip?user=foo&expires=1600000000&signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip?user=foo&expires=1600000000"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "valid signature with escaped path",
+			url:            "/download/my%20file.zip?expires=1600000000&signature=" + sign(sha256.New, hex.EncodeToString, "/download/my%20file.zip?expires=1600000000"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc: "valid signature with custom configuration",
+			config: dynamic.SignedURL{
+				Algorithm:      "sha1",
+				Encoding:       "base64url",
+				ExpiresParam:   "exp",
+				SignatureParam: "hmac",
+			},
+			url:            "/download/file.zip?exp=1600000000&hmac=" + sign(sha1.New, base64.URLEncoding.EncodeToString, "/download/file.zip?exp=1600000000"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			desc:           "expired",
+			url:            "/download/file.zip?expires=1599999999&signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip?expires=1599999999"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "tampered expiry",
+			url:            "/download/file.zip?expires=1700000000&signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip?expires=1600000000"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "tampered path",
+			url:            "/download/other.zip?expires=1600000000&signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip?expires=1600000000"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "tampered query",
+			url:            "/download/file.zip?user=bar&expires=1600000000&signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip?user=foo&expires=1600000000"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "added parameter",
+			url:            "/download/file.zip?expires=1600000000&signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip?expires=1600000000") + "&user=foo",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "missing signature",
+			url:            "/download/file.zip?expires=1600000000",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "missing expiry",
+			url:            "/download/file.zip?signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "invalid expiry",
+			url:            "/download/file.zip?expires=tomorrow&signature=" + sign(sha256.New, hex.EncodeToString, "/download/file.zip?expires=tomorrow"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			desc:           "invalid signature encoding",
+			url:            "/download/file.zip?expires=1600000000&signature=foo",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := test.config
+			config.Secret = "secret"
+			if config.ExpiresParam == "" {
+				config.ExpiresParam = "expires"
+			}
+			if config.SignatureParam == "" {
+				config.SignatureParam = "signature"
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			handler, err := New(context.Background(), next, config, "traefikTest")
+			require.NoError(t, err)
+
+			handler.(*signedURL).now = func() time.Time { return now }
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost"+test.url, nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func sign(hashFunc func() hash.Hash, encode func([]byte) string, message string) string {
+	mac := hmac.New(hashFunc, []byte("secret"))
+	_, _ = mac.Write([]byte(message))
+
+	return encode(mac.Sum(nil))
+}
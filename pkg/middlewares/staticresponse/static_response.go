@@ -0,0 +1,86 @@
+package staticresponse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "StaticResponse"
+)
+
+// staticResponse is a middleware which answers the requests with a static response,
+// without forwarding them to the next handler.
+type staticResponse struct {
+	name       string
+	statusCode int
+	headers    map[string]string
+	body       []byte
+}
+
+// New creates a new StaticResponse middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.StaticResponse, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	statusCode := config.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	if statusCode < 100 || statusCode > 599 {
+		return nil, fmt.Errorf("invalid status code: %d", statusCode)
+	}
+
+	if config.Body != "" && config.BodyFile != "" {
+		return nil, errors.New("body and bodyFile cannot be both set")
+	}
+
+	body := []byte(config.Body)
+	if config.BodyFile != "" {
+		var err error
+		body, err = os.ReadFile(config.BodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the body file: %w", err)
+		}
+	}
+
+	return &staticResponse{
+		name:       name,
+		statusCode: statusCode,
+		headers:    config.Headers,
+		body:       body,
+	}, nil
+}
+
+func (s *staticResponse) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return s.name, tracing.SpanKindNoneEnum
+}
+
+func (s *staticResponse) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	for name, value := range s.headers {
+		rw.Header().Set(name, value)
+	}
+
+	if len(s.body) > 0 && rw.Header().Get("Content-Type") == "" {
+		rw.Header().Set("Content-Type", http.DetectContentType(s.body))
+	}
+
+	rw.WriteHeader(s.statusCode)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := rw.Write(s.body); err != nil {
+		log.FromContext(middlewares.GetLoggerCtx(req.Context(), s.name, typeName)).Debugf("Unable to write the response body: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package staticresponse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.StaticResponse
+		expectedError bool
+	}{
+		{
+			desc:   "empty configuration",
+			config: dynamic.StaticResponse{},
+		},
+		{
+			desc:   "inline body",
+			config: dynamic.StaticResponse{StatusCode: http.StatusOK, Body: "foo"},
+		},
+		{
+			desc:          "invalid status code",
+			config:        dynamic.StaticResponse{StatusCode: 42},
+			expectedError: true,
+		},
+		{
+			desc:          "body and body file",
+			config:        dynamic.StaticResponse{Body: "foo", BodyFile: "foo.html"},
+			expectedError: true,
+		},
+		{
+			desc:          "missing body file",
+			config:        dynamic.StaticResponse{BodyFile: filepath.Join("fixtures", "missing.html")},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			_, err := New(context.Background(), next, test.config, "test")
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStaticResponse(t *testing.T) {
+	bodyFile := filepath.Join(t.TempDir(), "maintenance.html")
+	err := os.WriteFile(bodyFile, []byte("<html><body>Under maintenance</body></html>"), 0o600)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc            string
+		config          dynamic.StaticResponse
+		method          string
+		expectedStatus  int
+		expectedHeaders map[string]string
+		expectedBody    string
+	}{
+		{
+			desc:           "default status code",
+			config:         dynamic.StaticResponse{},
+			method:         http.MethodGet,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc: "inline body and headers",
+			config: dynamic.StaticResponse{
+				StatusCode: http.StatusOK,
+				Headers:    map[string]string{"Retry-After": "3600", "Content-Type": "application/json"},
+				Body:       `{"status":"maintenance"}`,
+			},
+			method:          http.MethodGet,
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"Retry-After": "3600", "Content-Type": "application/json"},
+			expectedBody:    `{"status":"maintenance"}`,
+		},
+		{
+			desc:            "body file",
+			config:          dynamic.StaticResponse{BodyFile: bodyFile},
+			method:          http.MethodGet,
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedHeaders: map[string]string{"Content-Type": "text/html; charset=utf-8"},
+			expectedBody:    "<html><body>Under maintenance</body></html>",
+		},
+		{
+			desc:           "head request",
+			config:         dynamic.StaticResponse{Body: "foo"},
+			method:         http.MethodHead,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				t.Error("the request must not be forwarded")
+			})
+
+			handler, err := New(context.Background(), next, test.config, "test")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(test.method, "http://localhost", nil)
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedBody, recorder.Body.String())
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, recorder.Header().Get(name))
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package tcpinflightconn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const typeName = "InFlightConnTCP"
+
+// inFlightConn is a middleware that limits the number of concurrent connections of each source IP.
+type inFlightConn struct {
+	next tcp.Handler
+	name string
+
+	maxConns int64
+
+	mu sync.Mutex
+	// conns holds the number of open connections of each source IP.
+	conns map[string]int64
+}
+
+// New builds a new TCP in flight connection limiter.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPInFlightConn, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if config.Amount <= 0 {
+		return nil, errors.New("amount must be greater than zero, in flight connection limiter not created")
+	}
+
+	return &inFlightConn{
+		next:     next,
+		name:     name,
+		maxConns: config.Amount,
+		conns:    make(map[string]int64),
+	}, nil
+}
+
+func (i *inFlightConn) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), i.name, typeName)
+	logger := log.FromContext(ctx)
+
+	ip, err := getSourceIP(conn)
+	if err != nil {
+		logger.Errorf("Cannot determine the source IP of the connection from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	if !i.acquire(ip) {
+		logger.Debugf("Connection from %s rejected: limit reached", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	i.next.ServeTCP(&limitedConn{WriteCloser: conn, limiter: i, ip: ip})
+}
+
+func (i *inFlightConn) acquire(ip string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.conns[ip] >= i.maxConns {
+		return false
+	}
+
+	i.conns[ip]++
+
+	return true
+}
+
+func (i *inFlightConn) release(ip string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.conns[ip]--
+	if i.conns[ip] <= 0 {
+		delete(i.conns, ip)
+	}
+}
+
+func getSourceIP(conn tcp.WriteCloser) (string, error) {
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return "", fmt.Errorf("invalid remote address: %w", err)
+	}
+
+	return ip, nil
+}
+
+// limitedConn is a connection holding a slot of an inFlightConn, until it is closed.
+type limitedConn struct {
+	tcp.WriteCloser
+	limiter *inFlightConn
+	ip      string
+	once    sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	c.once.Do(func() { c.limiter.release(c.ip) })
+	return c.WriteCloser.Close()
+}
+
+// Unwrap returns the wrapped connection.
+func (c *limitedConn) Unwrap() tcp.WriteCloser {
+	return c.WriteCloser
+}
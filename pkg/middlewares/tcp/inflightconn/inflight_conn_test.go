@@ -0,0 +1,148 @@
+package tcpinflightconn
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+type fakeConn struct {
+	net.Conn
+	remoteAddr string
+	closed     bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", c.remoteAddr)
+	return addr
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	return nil
+}
+
+func TestNewInFlightConn(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPInFlightConn
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			config:        dynamic.TCPInFlightConn{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative amount",
+			config:        dynamic.TCPInFlightConn{Amount: -1},
+			expectedError: true,
+		},
+		{
+			desc:   "valid config",
+			config: dynamic.TCPInFlightConn{Amount: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+			middleware, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, middleware)
+			}
+		})
+	}
+}
+
+func TestInFlightConn_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		amount      int64
+		remoteAddrs []string
+		expected    []bool
+	}{
+		{
+			desc:        "limit per source IP",
+			amount:      1,
+			remoteAddrs: []string{"10.0.0.1:1234", "10.0.0.2:1234", "10.0.0.1:1235"},
+			expected:    []bool{true, true, false},
+		},
+		{
+			desc:        "several connections allowed",
+			amount:      2,
+			remoteAddrs: []string{"10.0.0.1:1234", "10.0.0.1:1235", "10.0.0.1:1236"},
+			expected:    []bool{true, true, false},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The connections are kept open by the next handler.
+			var served []tcp.WriteCloser
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				served = append(served, conn)
+			})
+
+			middleware, err := New(context.Background(), next, dynamic.TCPInFlightConn{Amount: test.amount}, "traefikTest")
+			require.NoError(t, err)
+
+			for i, remoteAddr := range test.remoteAddrs {
+				conn := &fakeConn{remoteAddr: remoteAddr}
+				middleware.ServeTCP(conn)
+
+				assert.Equal(t, !test.expected[i], conn.closed, "connection %d from %s", i, remoteAddr)
+			}
+
+			for _, conn := range served {
+				require.NoError(t, conn.Close())
+			}
+
+			assert.Empty(t, middleware.(*inFlightConn).conns)
+		})
+	}
+}
+
+func TestInFlightConn_release(t *testing.T) {
+	var served tcp.WriteCloser
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served = conn
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPInFlightConn{Amount: 1}, "traefikTest")
+	require.NoError(t, err)
+
+	middleware.ServeTCP(&fakeConn{remoteAddr: "10.0.0.1:1234"})
+	require.NotNil(t, served)
+
+	// Closing the connection twice releases its slot only once.
+	require.NoError(t, served.Close())
+	require.NoError(t, served.Close())
+
+	conn := &fakeConn{remoteAddr: "10.0.0.1:1235"}
+	middleware.ServeTCP(conn)
+	assert.False(t, conn.closed)
+
+	conn = &fakeConn{remoteAddr: "10.0.0.1:1236"}
+	middleware.ServeTCP(conn)
+	assert.True(t, conn.closed)
+}
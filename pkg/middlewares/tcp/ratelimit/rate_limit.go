@@ -0,0 +1,110 @@
+package tcpratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName   = "RateLimiterTCP"
+	maxSources = 65536
+)
+
+// rateLimiter is a middleware that limits the rate of new connections of each source IP.
+type rateLimiter struct {
+	next tcp.Handler
+	name string
+
+	rate  rate.Limit
+	burst int
+	// ttl is how long (in seconds) an idle bucket is kept.
+	ttl int
+
+	mu      sync.Mutex
+	buckets *ttlmap.TtlMap
+}
+
+// New builds a new TCP connection rate limiter.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPRateLimit, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if config.Average <= 0 {
+		return nil, errors.New("average must be greater than zero, rate limiter not created")
+	}
+
+	buckets, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, err
+	}
+
+	burst := int(config.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		next:    next,
+		name:    name,
+		rate:    rate.Limit(config.Average),
+		burst:   burst,
+		buckets: buckets,
+		// A bucket is full again after burst/rate seconds,
+		// at which point forgetting it is equivalent to keeping it.
+		ttl: int(int64(burst)/config.Average) + 1,
+	}, nil
+}
+
+func (r *rateLimiter) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), r.name, typeName)
+	logger := log.FromContext(ctx)
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Errorf("Cannot determine the source IP of the connection from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	allowed, err := r.allow(ip)
+	if err != nil {
+		logger.Errorf("Error while limiting the connection from %s: %v", conn.RemoteAddr(), err)
+	}
+
+	if !allowed {
+		logger.Debugf("Connection from %s rejected: rate limit reached", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	r.next.ServeTCP(conn)
+}
+
+func (r *rateLimiter) allow(ip string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var bucket *rate.Limiter
+	if rlSource, exists := r.buckets.Get(ip); exists {
+		bucket = rlSource.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(r.rate, r.burst)
+	}
+
+	// The bucket is set even when it already exists, to push back its expiry time.
+	if err := r.buckets.Set(ip, bucket, r.ttl); err != nil {
+		return false, fmt.Errorf("could not insert bucket: %w", err)
+	}
+
+	return bucket.Allow(), nil
+}
@@ -0,0 +1,108 @@
+package tcpratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+type fakeConn struct {
+	net.Conn
+	remoteAddr string
+	closed     bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", c.remoteAddr)
+	return addr
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	return nil
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPRateLimit
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			config:        dynamic.TCPRateLimit{Burst: 1},
+			expectedError: true,
+		},
+		{
+			desc:   "valid config",
+			config: dynamic.TCPRateLimit{Average: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+			middleware, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, middleware)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.TCPRateLimit
+		remoteAddrs []string
+		expected    []bool
+	}{
+		{
+			desc:        "default burst",
+			config:      dynamic.TCPRateLimit{Average: 1},
+			remoteAddrs: []string{"10.0.0.1:1234", "10.0.0.1:1235"},
+			expected:    []bool{true, false},
+		},
+		{
+			desc:        "limit per source IP",
+			config:      dynamic.TCPRateLimit{Average: 1, Burst: 2},
+			remoteAddrs: []string{"10.0.0.1:1234", "10.0.0.1:1235", "10.0.0.2:1234", "10.0.0.1:1236"},
+			expected:    []bool{true, true, true, false},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+			middleware, err := New(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			for i, remoteAddr := range test.remoteAddrs {
+				conn := &fakeConn{remoteAddr: remoteAddr}
+				middleware.ServeTCP(conn)
+
+				assert.Equal(t, !test.expected[i], conn.closed, "connection %d from %s", i, remoteAddr)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package tcpsnilimit
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName       = "SNILimitTCP"
+	maxServerNames = 65536
+
+	// defaultHandshakeTimeout bounds the TLS handshake done to read the server name of the terminated connections,
+	// as the router removes the deadlines of the connections.
+	defaultHandshakeTimeout = 10 * time.Second
+)
+
+// sniLimiter is a middleware that limits the number of concurrent connections,
+// and the rate of new connections, for each server name (SNI).
+type sniLimiter struct {
+	next tcp.Handler
+	name string
+
+	handshakeTimeout time.Duration
+
+	maxConns int64
+
+	rate  rate.Limit
+	burst int
+	// ttl is how long (in seconds) an idle bucket is kept.
+	ttl     int
+	buckets *ttlmap.TtlMap
+
+	mu sync.Mutex
+	// conns holds the number of open connections of each server name.
+	conns map[string]int64
+}
+
+// New builds a new TCP SNI limiter.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPSNILimit, name string) (tcp.Handler, error) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName))
+	logger.Debug("Creating middleware")
+
+	if config.MaxConnections <= 0 && config.Average <= 0 {
+		return nil, errors.New("maxConnections and average are empty, SNI limiter not created")
+	}
+
+	limiter := &sniLimiter{
+		next:             next,
+		name:             name,
+		handshakeTimeout: defaultHandshakeTimeout,
+		maxConns:         config.MaxConnections,
+		conns:            make(map[string]int64),
+	}
+
+	if config.Average > 0 {
+		buckets, err := ttlmap.NewConcurrent(maxServerNames)
+		if err != nil {
+			return nil, err
+		}
+
+		limiter.buckets = buckets
+		limiter.rate = rate.Limit(config.Average)
+		limiter.burst = int(config.Burst)
+		if limiter.burst < 1 {
+			limiter.burst = 1
+		}
+
+		// A bucket is full again after burst/rate seconds,
+		// at which point forgetting it is equivalent to keeping it.
+		limiter.ttl = int(int64(limiter.burst)/config.Average) + 1
+	}
+
+	return limiter, nil
+}
+
+func (l *sniLimiter) ServeTCP(conn tcp.WriteCloser) {
+	ctx := middlewares.GetLoggerCtx(context.Background(), l.name, typeName)
+	logger := log.FromContext(ctx)
+
+	serverName, err := getServerName(conn, l.handshakeTimeout)
+	if err != nil {
+		logger.Debugf("Error while reading the server name of the connection from %s: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+
+	allowed, err := l.acquire(serverName)
+	if err != nil {
+		logger.Errorf("Error while limiting the connection from %s: %v", conn.RemoteAddr(), err)
+	}
+
+	if !allowed {
+		logger.Debugf("Connection from %s to %q rejected: limit reached", conn.RemoteAddr(), serverName)
+		conn.Close()
+		return
+	}
+
+	l.next.ServeTCP(&limitedConn{WriteCloser: conn, limiter: l, serverName: serverName})
+}
+
+func (l *sniLimiter) acquire(serverName string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxConns > 0 && l.conns[serverName] >= l.maxConns {
+		return false, nil
+	}
+
+	if l.buckets != nil {
+		var bucket *rate.Limiter
+		if rlSource, exists := l.buckets.Get(serverName); exists {
+			bucket = rlSource.(*rate.Limiter)
+		} else {
+			bucket = rate.NewLimiter(l.rate, l.burst)
+		}
+
+		// The bucket is set even when it already exists, to push back its expiry time.
+		if err := l.buckets.Set(serverName, bucket, l.ttl); err != nil {
+			return false, fmt.Errorf("could not insert bucket: %w", err)
+		}
+
+		if !bucket.Allow() {
+			return false, nil
+		}
+	}
+
+	l.conns[serverName]++
+
+	return true, nil
+}
+
+func (l *sniLimiter) release(serverName string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.conns[serverName]--
+	if l.conns[serverName] <= 0 {
+		delete(l.conns, serverName)
+	}
+}
+
+// getServerName returns the server name (SNI) of the connection,
+// looking for the connection of the router under the wrappers of the other middlewares.
+func getServerName(conn tcp.WriteCloser, handshakeTimeout time.Duration) (string, error) {
+	for {
+		switch typedConn := conn.(type) {
+		case *tcp.Conn:
+			// TLS passthrough: the server name was read by the router.
+			return typedConn.ServerName, nil
+		case *tls.Conn:
+			// TLS termination: the server name is known once the handshake is done.
+			if err := handshake(typedConn, handshakeTimeout); err != nil {
+				return "", err
+			}
+			return types.CanonicalDomain(typedConn.ConnectionState().ServerName), nil
+		}
+
+		unwrapper, ok := conn.(tcp.Unwrapper)
+		if !ok {
+			return "", nil
+		}
+		conn = unwrapper.Unwrap()
+	}
+}
+
+// handshake runs the TLS handshake of the connection within the given timeout.
+// The deadline is removed afterwards, as the router left the connection without deadline.
+func handshake(conn *tls.Conn, timeout time.Duration) error {
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+
+	return conn.SetDeadline(time.Time{})
+}
+
+// limitedConn is a connection holding a slot of a sniLimiter, until it is closed.
+type limitedConn struct {
+	tcp.WriteCloser
+	limiter    *sniLimiter
+	serverName string
+	once       sync.Once
+}
+
+func (c *limitedConn) Close() error {
+	c.once.Do(func() { c.limiter.release(c.serverName) })
+	return c.WriteCloser.Close()
+}
+
+// Unwrap returns the wrapped connection.
+func (c *limitedConn) Unwrap() tcp.WriteCloser {
+	return c.WriteCloser
+}
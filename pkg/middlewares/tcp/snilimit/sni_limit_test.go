@@ -0,0 +1,200 @@
+package tcpsnilimit
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+type fakeConn struct {
+	net.Conn
+	closed bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	return nil
+}
+
+func TestNewSNILimiter(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPSNILimit
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			config:        dynamic.TCPSNILimit{Burst: 1},
+			expectedError: true,
+		},
+		{
+			desc:   "max connections",
+			config: dynamic.TCPSNILimit{MaxConnections: 1},
+		},
+		{
+			desc:   "rate limit",
+			config: dynamic.TCPSNILimit{Average: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+			limiter, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, limiter)
+			}
+		})
+	}
+}
+
+func TestSNILimiter_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.TCPSNILimit
+		serverNames []string
+		expected    []bool
+	}{
+		{
+			desc:        "max connections",
+			config:      dynamic.TCPSNILimit{MaxConnections: 1},
+			serverNames: []string{"foo.example.com", "bar.example.com", "foo.example.com"},
+			expected:    []bool{true, true, false},
+		},
+		{
+			desc:        "rate limit",
+			config:      dynamic.TCPSNILimit{Average: 1, Burst: 2},
+			serverNames: []string{"foo.example.com", "foo.example.com", "bar.example.com", "foo.example.com"},
+			expected:    []bool{true, true, true, false},
+		},
+		{
+			desc:        "rejected by max connections does not consume tokens",
+			config:      dynamic.TCPSNILimit{MaxConnections: 1, Average: 1, Burst: 2},
+			serverNames: []string{"foo.example.com", "foo.example.com"},
+			expected:    []bool{true, false},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The connections are kept open by the next handler.
+			var served []tcp.WriteCloser
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				served = append(served, conn)
+			})
+
+			limiter, err := New(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			for i, serverName := range test.serverNames {
+				conn := &fakeConn{}
+				limiter.ServeTCP(&tcp.Conn{WriteCloser: conn, ServerName: serverName})
+
+				assert.Equal(t, !test.expected[i], conn.closed, "connection %d to %s", i, serverName)
+			}
+
+			for _, conn := range served {
+				require.NoError(t, conn.Close())
+			}
+
+			assert.Empty(t, limiter.(*sniLimiter).conns)
+		})
+	}
+}
+
+func TestSNILimiter_release(t *testing.T) {
+	var served tcp.WriteCloser
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served = conn
+	})
+
+	limiter, err := New(context.Background(), next, dynamic.TCPSNILimit{MaxConnections: 1}, "traefikTest")
+	require.NoError(t, err)
+
+	limiter.ServeTCP(&tcp.Conn{WriteCloser: &fakeConn{}, ServerName: "foo.example.com"})
+	require.NotNil(t, served)
+
+	// Closing the connection twice releases its slot only once.
+	require.NoError(t, served.Close())
+	require.NoError(t, served.Close())
+
+	conn := &fakeConn{}
+	limiter.ServeTCP(&tcp.Conn{WriteCloser: conn, ServerName: "foo.example.com"})
+	assert.False(t, conn.closed)
+
+	conn = &fakeConn{}
+	limiter.ServeTCP(&tcp.Conn{WriteCloser: conn, ServerName: "foo.example.com"})
+	assert.True(t, conn.closed)
+}
+
+type wrappedConn struct {
+	tcp.WriteCloser
+}
+
+func (c *wrappedConn) Unwrap() tcp.WriteCloser {
+	return c.WriteCloser
+}
+
+func TestSNILimiter_ServeTCP_wrappedConn(t *testing.T) {
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+	limiter, err := New(context.Background(), next, dynamic.TCPSNILimit{MaxConnections: 1}, "traefikTest")
+	require.NoError(t, err)
+
+	// The connections of other server names are not limited together.
+	foo := &fakeConn{}
+	limiter.ServeTCP(&wrappedConn{WriteCloser: &wrappedConn{WriteCloser: &tcp.Conn{WriteCloser: foo, ServerName: "foo.example.com"}}})
+	assert.False(t, foo.closed)
+
+	bar := &fakeConn{}
+	limiter.ServeTCP(&wrappedConn{WriteCloser: &tcp.Conn{WriteCloser: bar, ServerName: "bar.example.com"}})
+	assert.False(t, bar.closed)
+
+	assert.Len(t, limiter.(*sniLimiter).conns, 2)
+}
+
+func TestGetServerName_handshakeTimeout(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() {
+		_ = serverConn.Close()
+		_ = clientConn.Close()
+	})
+
+	// The client never sends its ClientHello.
+	conn := tls.Server(serverConn, &tls.Config{})
+
+	start := time.Now()
+	_, err := getServerName(&wrappedConn{WriteCloser: conn}, 50*time.Millisecond)
+	require.Error(t, err)
+
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr))
+	assert.True(t, netErr.Timeout())
+	assert.Less(t, int64(time.Since(start)), int64(5*time.Second))
+}
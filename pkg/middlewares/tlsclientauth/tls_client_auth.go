@@ -0,0 +1,129 @@
+package tlsclientauth
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/tracing"
+)
+
+const (
+	typeName = "TLSClientAuth"
+)
+
+// tlsClientAuth is a middleware verifying the client certificates of the requests against the client authentication policy of a router.
+type tlsClientAuth struct {
+	next       http.Handler
+	name       string
+	pool       *x509.CertPool
+	required   bool
+	statusCode int
+}
+
+// New builds a new tlsClientAuth middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.RouterTLSClientAuth, name string) (http.Handler, error) {
+	log.FromContext(middlewares.GetLoggerCtx(ctx, name, typeName)).Debug("Creating middleware")
+
+	if len(config.CAFiles) == 0 {
+		return nil, errors.New("caFiles is required")
+	}
+
+	pool := x509.NewCertPool()
+	for _, caFile := range config.CAFiles {
+		data, err := caFile.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		if !pool.AppendCertsFromPEM(data) {
+			if caFile.IsPath() {
+				return nil, fmt.Errorf("invalid certificate(s) in %s", caFile)
+			}
+			return nil, errors.New("invalid certificate(s) content")
+		}
+	}
+
+	var required bool
+	switch config.ClientAuthType {
+	case "", "RequireAndVerifyClientCert":
+		required = true
+	case "VerifyClientCertIfGiven":
+	default:
+		return nil, fmt.Errorf("unsupported client auth type %q for a router", config.ClientAuthType)
+	}
+
+	statusCode := config.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusForbidden
+	}
+
+	if http.StatusText(statusCode) == "" || statusCode < http.StatusBadRequest {
+		return nil, fmt.Errorf("invalid status code %d", statusCode)
+	}
+
+	return &tlsClientAuth{
+		next:       next,
+		name:       name,
+		pool:       pool,
+		required:   required,
+		statusCode: statusCode,
+	}, nil
+}
+
+func (c *tlsClientAuth) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *tlsClientAuth) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.FromContext(middlewares.GetLoggerCtx(req.Context(), c.name, typeName))
+
+	if err := c.verify(req); err != nil {
+		logMessage := fmt.Sprintf("rejecting request %+v: %v", req, err)
+		logger.Debug(logMessage)
+		tracing.SetErrorWithEvent(req, logMessage)
+
+		rw.WriteHeader(c.statusCode)
+		_, _ = rw.Write([]byte(http.StatusText(c.statusCode)))
+		return
+	}
+
+	c.next.ServeHTTP(rw, req)
+}
+
+// verify verifies the chain of the client certificate, if any.
+func (c *tlsClientAuth) verify(req *http.Request) error {
+	if req.TLS == nil {
+		return errors.New("the request is not a TLS request")
+	}
+
+	certificates := req.TLS.PeerCertificates
+	if len(certificates) == 0 {
+		if c.required {
+			return errors.New("no client certificate")
+		}
+		return nil
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         c.pool,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	for _, certificate := range certificates[1:] {
+		opts.Intermediates.AddCert(certificate)
+	}
+
+	if _, err := certificates[0].Verify(opts); err != nil {
+		return fmt.Errorf("invalid client certificate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package tlsclientauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
+)
+
+func TestNew(t *testing.T) {
+	caPEM, _, _ := generateCA(t)
+
+	testCases := []struct {
+		desc      string
+		config    dynamic.RouterTLSClientAuth
+		expectErr bool
+	}{
+		{
+			desc:   "defaults",
+			config: dynamic.RouterTLSClientAuth{CAFiles: []traefiktls.FileOrContent{traefiktls.FileOrContent(caPEM)}},
+		},
+		{
+			desc:      "missing CA files",
+			config:    dynamic.RouterTLSClientAuth{},
+			expectErr: true,
+		},
+		{
+			desc:      "invalid CA content",
+			config:    dynamic.RouterTLSClientAuth{CAFiles: []traefiktls.FileOrContent{"-----BEGIN CERTIFICATE-----\nfoo"}},
+			expectErr: true,
+		},
+		{
+			desc: "unsupported client auth type",
+			config: dynamic.RouterTLSClientAuth{
+				CAFiles:        []traefiktls.FileOrContent{traefiktls.FileOrContent(caPEM)},
+				ClientAuthType: "RequestClientCert",
+			},
+			expectErr: true,
+		},
+		{
+			desc: "invalid status code",
+			config: dynamic.RouterTLSClientAuth{
+				CAFiles:    []traefiktls.FileOrContent{traefiktls.FileOrContent(caPEM)},
+				StatusCode: http.StatusOK,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), http.NotFoundHandler(), test.config, "foo")
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTLSClientAuth_ServeHTTP(t *testing.T) {
+	caPEM, caCert, caKey := generateCA(t)
+	clientCert := generateClientCert(t, caCert, caKey)
+
+	_, otherCACert, otherCAKey := generateCA(t)
+	untrustedCert := generateClientCert(t, otherCACert, otherCAKey)
+
+	testCases := []struct {
+		desc           string
+		clientAuthType string
+		statusCode     int
+		tls            *tls.ConnectionState
+		expected       int
+	}{
+		{
+			desc:     "valid client certificate",
+			tls:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{clientCert}},
+			expected: http.StatusOK,
+		},
+		{
+			desc:     "missing client certificate",
+			tls:      &tls.ConnectionState{},
+			expected: http.StatusForbidden,
+		},
+		{
+			desc:           "missing optional client certificate",
+			clientAuthType: "VerifyClientCertIfGiven",
+			tls:            &tls.ConnectionState{},
+			expected:       http.StatusOK,
+		},
+		{
+			desc:           "untrusted optional client certificate",
+			clientAuthType: "VerifyClientCertIfGiven",
+			tls:            &tls.ConnectionState{PeerCertificates: []*x509.Certificate{untrustedCert}},
+			expected:       http.StatusForbidden,
+		},
+		{
+			desc:       "untrusted client certificate with a custom status code",
+			statusCode: http.StatusUnauthorized,
+			tls:        &tls.ConnectionState{PeerCertificates: []*x509.Certificate{untrustedCert}},
+			expected:   http.StatusUnauthorized,
+		},
+		{
+			desc:     "request without TLS",
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.RouterTLSClientAuth{
+				CAFiles:        []traefiktls.FileOrContent{traefiktls.FileOrContent(caPEM)},
+				ClientAuthType: test.clientAuthType,
+				StatusCode:     test.statusCode,
+			}
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+
+			handler, err := New(context.Background(), next, config, "foo")
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "https://foo.localhost", nil)
+			req.TLS = test.tls
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expected, recorder.Code)
+		})
+	}
+}
+
+func generateCA(t *testing.T) (string, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), cert, key
+}
+
+func generateClientCert(t *testing.T, caCert *x509.Certificate, caKey *ecdsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
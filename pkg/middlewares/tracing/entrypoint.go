@@ -9,6 +9,7 @@ import (
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
+	"github.com/traefik/traefik/v2/pkg/middlewares/operation"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 )
 
@@ -44,7 +45,11 @@ func (e *entryPointMiddleware) ServeHTTP(rw http.ResponseWriter, req *http.Reque
 	defer finish()
 
 	ext.Component.Set(span, e.ServiceName)
+	if op, _ := operation.GetOperation(req.Context()); op != "" {
+		span.SetTag("operation", op)
+	}
 	tracing.LogRequest(span, req)
+	e.LogRequestHeaders(span, req)
 
 	req = req.WithContext(tracing.WithTracing(req.Context(), e.Tracing))
 
@@ -19,11 +19,13 @@ func TestEntryPointMiddleware(t *testing.T) {
 	}
 
 	testCases := []struct {
-		desc          string
-		entryPoint    string
-		spanNameLimit int
-		tracing       *trackingBackenMock
-		expected      expected
+		desc           string
+		entryPoint     string
+		spanNameLimit  int
+		spanAttributes *tracing.SpanAttributes
+		headers        map[string]string
+		tracing        *trackingBackenMock
+		expected       expected
 	}{
 		{
 			desc:          "no truncation test",
@@ -61,14 +63,46 @@ func TestEntryPointMiddleware(t *testing.T) {
 				OperationName: "EntryPoint te... ww... 0c15301b",
 			},
 		},
+		{
+			desc:       "span attributes",
+			entryPoint: "test",
+			spanAttributes: &tracing.SpanAttributes{
+				Static:          map[string]string{"env": "prod"},
+				CaptureHeaders:  true,
+				RedactedHeaders: []string{"authorization"},
+			},
+			headers: map[string]string{
+				"Authorization": "Basic Zm9vOmJhcg==",
+				"X-Foo":         "bar",
+			},
+			tracing: &trackingBackenMock{
+				tracer: &MockTracer{Span: &MockSpan{Tags: make(map[string]interface{})}},
+			},
+			expected: expected{
+				Tags: map[string]interface{}{
+					"span.kind":                         ext.SpanKindRPCServerEnum,
+					"http.method":                       http.MethodGet,
+					"component":                         "",
+					"http.url":                          "http://www.test.com",
+					"http.host":                         "www.test.com",
+					"env":                               "prod",
+					"http.request.header.authorization": "REDACTED",
+					"http.request.header.x-foo":         "bar",
+				},
+				OperationName: "EntryPoint test www.test.com",
+			},
+		},
 	}
 
 	for _, test := range testCases {
 		t.Run(test.desc, func(t *testing.T) {
-			newTracing, err := tracing.NewTracing("", test.spanNameLimit, test.tracing)
+			newTracing, err := tracing.NewTracing("", test.spanNameLimit, test.tracing, test.spanAttributes)
 			require.NoError(t, err)
 
 			req := httptest.NewRequest(http.MethodGet, "http://www.test.com", nil)
+			for name, value := range test.headers {
+				req.Header.Set(name, value)
+			}
 			rw := httptest.NewRecorder()
 
 			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
@@ -0,0 +1,327 @@
+package watchdog
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+// States of the in-flight requests.
+const (
+	StateReadingRequest    = "reading_request"
+	StateWaitingBackend    = "waiting_backend"
+	StateStreamingResponse = "streaming_response"
+)
+
+// checkInterval is the interval between the checks of the in-flight requests.
+const checkInterval = time.Second
+
+type key struct{}
+
+var requestKey key
+
+// Watchdog tracks the in-flight requests, and reports the ones exceeding their threshold,
+// with their router, their service, their server, and their current state.
+// A slow request is counted once, and logged again each time its duration exceeds another multiple of the threshold,
+// so that the requests which never complete keep being reported.
+type Watchdog struct {
+	slowReqsCounter gokitmetrics.Counter
+
+	mu       sync.Mutex
+	requests map[*request]struct{}
+	cancel   context.CancelFunc
+}
+
+// New creates a new Watchdog, which counts the slow requests with the given counter, if not nil.
+func New(slowReqsCounter gokitmetrics.Counter) *Watchdog {
+	return &Watchdog{
+		slowReqsCounter: slowReqsCounter,
+		requests:        make(map[*request]struct{}),
+	}
+}
+
+// Start starts checking the in-flight requests, until the watchdog is stopped.
+func (w *Watchdog) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w.mu.Lock()
+	w.cancel = cancel
+	w.mu.Unlock()
+
+	safe.Go(func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				w.check(now)
+			}
+		}
+	})
+}
+
+// Stop stops checking the in-flight requests.
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+// WrapEntryPointHandler returns a constructor tracking the requests received on the entry point,
+// which are slow once in flight for longer than the threshold.
+func (w *Watchdog) WrapEntryPointHandler(entryPointName string, threshold time.Duration) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			w.serveHTTP(rw, req, next, entryPointName, threshold)
+		}), nil
+	}
+}
+
+func (w *Watchdog) serveHTTP(rw http.ResponseWriter, req *http.Request, next http.Handler, entryPointName string, threshold time.Duration) {
+	r := &request{
+		entryPoint: entryPointName,
+		method:     req.Method,
+		host:       req.Host,
+		path:       req.URL.Path,
+		start:      time.Now(),
+		threshold:  threshold,
+		state:      StateWaitingBackend,
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0 {
+		r.state = StateReadingRequest
+		req.Body = &bodyReader{ReadCloser: req.Body, request: r}
+	}
+
+	w.mu.Lock()
+	w.requests[r] = struct{}{}
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		delete(w.requests, r)
+		w.mu.Unlock()
+
+		r.done(time.Now())
+	}()
+
+	next.ServeHTTP(newResponseWriter(rw, r), req.WithContext(context.WithValue(req.Context(), requestKey, r)))
+}
+
+// check reports the in-flight requests exceeding their threshold.
+func (w *Watchdog) check(now time.Time) {
+	w.mu.Lock()
+	requests := make([]*request, 0, len(w.requests))
+	for r := range w.requests {
+		requests = append(requests, r)
+	}
+	w.mu.Unlock()
+
+	for _, r := range requests {
+		if !r.report(now) {
+			continue
+		}
+
+		if w.slowReqsCounter != nil {
+			r.mu.Lock()
+			labels := []string{"entrypoint", r.entryPoint, "router", r.router, "service", r.service, "state", r.state}
+			r.mu.Unlock()
+
+			w.slowReqsCounter.With(labels...).Add(1)
+		}
+	}
+}
+
+// WrapRouterHandler returns a constructor recording the router handling the tracked requests.
+func WrapRouterHandler(routerName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if r, ok := req.Context().Value(requestKey).(*request); ok {
+				r.mu.Lock()
+				r.router = routerName
+				r.mu.Unlock()
+			}
+
+			next.ServeHTTP(rw, req)
+		}), nil
+	}
+}
+
+// WrapServerHandler returns a constructor recording the service, and the server chosen by its load balancer,
+// handling the tracked requests.
+func WrapServerHandler(serviceName string) alice.Constructor {
+	return func(next http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if r, ok := req.Context().Value(requestKey).(*request); ok {
+				r.mu.Lock()
+				r.service = serviceName
+				// The load balancer sets the URL of the chosen server on the request.
+				r.server = req.URL.Scheme + "://" + req.URL.Host
+				r.mu.Unlock()
+			}
+
+			next.ServeHTTP(rw, req)
+		}), nil
+	}
+}
+
+// request is an in-flight request.
+type request struct {
+	entryPoint string
+	method     string
+	host       string
+	path       string
+	start      time.Time
+	threshold  time.Duration
+
+	mu       sync.Mutex
+	router   string
+	service  string
+	server   string
+	state    string
+	reported int
+}
+
+func (r *request) setState(state string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The response can be streamed before the request is fully read.
+	if r.state == StateStreamingResponse {
+		return
+	}
+
+	r.state = state
+}
+
+// report logs the request if it exceeds another multiple of its threshold,
+// and reports whether it is the first time the request is reported.
+func (r *request) report(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	duration := now.Sub(r.start)
+	if r.threshold <= 0 || duration < time.Duration(r.reported+1)*r.threshold {
+		return false
+	}
+
+	first := r.reported == 0
+	r.reported = int(duration / r.threshold)
+
+	r.logger().Warnf("Slow request %s %s%s in flight for %s, state: %s", r.method, r.host, r.path, duration.Round(time.Millisecond), r.state)
+
+	return first
+}
+
+// done logs the completion of the request, if it has been reported as slow.
+func (r *request) done(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reported == 0 {
+		return
+	}
+
+	r.logger().Warnf("Slow request %s %s%s completed after %s", r.method, r.host, r.path, now.Sub(r.start).Round(time.Millisecond))
+}
+
+func (r *request) logger() logrus.FieldLogger {
+	return log.WithoutContext().WithFields(logrus.Fields{
+		log.EntryPointName: r.entryPoint,
+		log.RouterName:     r.router,
+		log.ServiceName:    r.service,
+		log.ServerName:     r.server,
+	})
+}
+
+// bodyReader records the end of the reading of the request body.
+type bodyReader struct {
+	io.ReadCloser
+	request *request
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if errors.Is(err, io.EOF) {
+		b.request.setState(StateWaitingBackend)
+	}
+
+	return n, err
+}
+
+// responseWriter records the start of the streaming of the response.
+type responseWriter struct {
+	http.ResponseWriter
+	request *request
+}
+
+type responseWriterWithCloseNotify struct {
+	*responseWriter
+}
+
+func newResponseWriter(rw http.ResponseWriter, r *request) http.ResponseWriter {
+	w := &responseWriter{ResponseWriter: rw, request: r}
+	if _, ok := rw.(http.CloseNotifier); !ok {
+		return w
+	}
+
+	return &responseWriterWithCloseNotify{w}
+}
+
+// CloseNotify returns a channel that receives at most a
+// single value (true) when the client connection has gone away.
+func (r *responseWriterWithCloseNotify) CloseNotify() <-chan bool {
+	return r.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (r *responseWriter) WriteHeader(code int) {
+	// The informational responses are not the beginning of the final response.
+	if code >= http.StatusOK || code == http.StatusSwitchingProtocols {
+		r.request.setState(StateStreamingResponse)
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseWriter) Write(p []byte) (int, error) {
+	r.request.setState(StateStreamingResponse)
+
+	return r.ResponseWriter.Write(p)
+}
+
+// Hijack hijacks the connection.
+func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", r.ResponseWriter)
+	}
+
+	r.request.setState(StateStreamingResponse)
+
+	return hijacker.Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *responseWriter) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
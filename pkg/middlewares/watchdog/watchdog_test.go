@@ -0,0 +1,159 @@
+package watchdog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containous/alice"
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectingCounter is a metrics.Counter implementation that enables access to the counter value and last label values.
+type collectingCounter struct {
+	mu              sync.Mutex
+	counterValue    float64
+	lastLabelValues []string
+}
+
+func (c *collectingCounter) With(labelValues ...string) metrics.Counter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastLabelValues = labelValues
+	return c
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counterValue += delta
+}
+
+func TestWatchdog(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		method        string
+		body          string
+		handler       func(rw http.ResponseWriter, req *http.Request)
+		expectedState string
+	}{
+		{
+			desc:          "waiting on backend",
+			method:        http.MethodGet,
+			handler:       func(rw http.ResponseWriter, req *http.Request) {},
+			expectedState: StateWaitingBackend,
+		},
+		{
+			desc:          "reading request",
+			method:        http.MethodPost,
+			body:          "foo",
+			handler:       func(rw http.ResponseWriter, req *http.Request) {},
+			expectedState: StateReadingRequest,
+		},
+		{
+			desc:   "request read",
+			method: http.MethodPost,
+			body:   "foo",
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = req.Body.Read(make([]byte, 10))
+				_, _ = req.Body.Read(make([]byte, 10))
+			},
+			expectedState: StateWaitingBackend,
+		},
+		{
+			desc:   "streaming response",
+			method: http.MethodGet,
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			},
+			expectedState: StateStreamingResponse,
+		},
+		{
+			desc:   "informational response",
+			method: http.MethodGet,
+			handler: func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusEarlyHints)
+			},
+			expectedState: StateWaitingBackend,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &collectingCounter{}
+			w := New(counter)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				test.handler(rw, req)
+				close(started)
+				<-release
+			})
+
+			handler, err := alice.New(
+				w.WrapEntryPointHandler("web", time.Minute),
+				WrapRouterHandler("router@file"),
+				WrapServerHandler("service@file"),
+			).Then(next)
+			require.NoError(t, err)
+
+			var body io.Reader
+			if test.body != "" {
+				body = strings.NewReader(test.body)
+			}
+
+			req := httptest.NewRequest(test.method, "http://10.10.10.10:8080/foo", body)
+
+			done := make(chan struct{})
+			go func() {
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+				close(done)
+			}()
+
+			<-started
+
+			// The request is not slow yet.
+			w.check(time.Now())
+			assert.Equal(t, float64(0), counter.counterValue)
+
+			w.check(time.Now().Add(time.Minute))
+			assert.Equal(t, float64(1), counter.counterValue)
+			assert.Equal(t, []string{"entrypoint", "web", "router", "router@file", "service", "service@file", "state", test.expectedState}, counter.lastLabelValues)
+
+			// A slow request is only counted once.
+			w.check(time.Now().Add(3 * time.Minute))
+			assert.Equal(t, float64(1), counter.counterValue)
+
+			close(release)
+			<-done
+
+			// The completed requests are not tracked anymore.
+			w.mu.Lock()
+			assert.Empty(t, w.requests)
+			w.mu.Unlock()
+		})
+	}
+}
+
+func TestWrapServerHandler_untracked(t *testing.T) {
+	var called bool
+	handler, err := alice.New(WrapRouterHandler("router@file"), WrapServerHandler("service@file")).
+		Then(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) { called = true }))
+	require.NoError(t, err)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://10.10.10.10:8080/foo", nil))
+
+	assert.True(t, called)
+}
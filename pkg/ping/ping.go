@@ -4,13 +4,20 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
 )
 
 // Handler expose ping routes.
 type Handler struct {
-	EntryPoint            string `description:"EntryPoint" export:"true" json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
-	ManualRouting         bool   `description:"Manual routing" json:"manualRouting,omitempty" toml:"manualRouting,omitempty" yaml:"manualRouting,omitempty" export:"true"`
-	TerminatingStatusCode int    `description:"Terminating status code" json:"terminatingStatusCode,omitempty" toml:"terminatingStatusCode,omitempty" yaml:"terminatingStatusCode,omitempty" export:"true"`
+	EntryPoint            string          `description:"EntryPoint" export:"true" json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
+	ManualRouting         bool            `description:"Manual routing" json:"manualRouting,omitempty" toml:"manualRouting,omitempty" yaml:"manualRouting,omitempty" export:"true"`
+	TerminatingStatusCode int             `description:"Terminating status code" json:"terminatingStatusCode,omitempty" toml:"terminatingStatusCode,omitempty" yaml:"terminatingStatusCode,omitempty" export:"true"`
+	DeadProviderThreshold ptypes.Duration `description:"Makes the ping fail when a provider has been failing for longer than this duration. 0 disables the check." json:"deadProviderThreshold,omitempty" toml:"deadProviderThreshold,omitempty" yaml:"deadProviderThreshold,omitempty" export:"true"`
 	terminating           bool
 }
 
@@ -32,6 +39,11 @@ func (h *Handler) ServeHTTP(response http.ResponseWriter, request *http.Request)
 	statusCode := http.StatusOK
 	if h.terminating {
 		statusCode = h.TerminatingStatusCode
+	} else if h.DeadProviderThreshold > 0 {
+		if dead := provider.DeadProviders(time.Duration(h.DeadProviderThreshold)); len(dead) > 0 {
+			log.FromContext(request.Context()).Debugf("Ping failing, providers failing for longer than %s: %s", h.DeadProviderThreshold, strings.Join(dead, ", "))
+			statusCode = http.StatusServiceUnavailable
+		}
 	}
 	response.WriteHeader(statusCode)
 	fmt.Fprint(response, http.StatusText(statusCode))
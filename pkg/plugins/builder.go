@@ -46,6 +46,10 @@ func NewBuilder(client *Client, plugins map[string]Descriptor, localPlugins map[
 			return nil, fmt.Errorf("%s: failed to read manifest: %w", desc.ModuleName, err)
 		}
 
+		if err = checkRuntime(manifest); err != nil {
+			return nil, fmt.Errorf("%s: %w", desc.ModuleName, err)
+		}
+
 		i := interp.New(interp.Options{GoPath: client.GoPath()})
 
 		err = i.Use(stdlib.Symbols)
@@ -65,6 +69,11 @@ func NewBuilder(client *Client, plugins map[string]Descriptor, localPlugins map[
 
 		switch manifest.Type {
 		case "middleware":
+			err = registerMatchers(i, manifest)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", desc.ModuleName, err)
+			}
+
 			pb.middlewareDescriptors[pName] = pluginContext{
 				interpreter: i,
 				GoPath:      client.GoPath(),
@@ -89,6 +98,10 @@ func NewBuilder(client *Client, plugins map[string]Descriptor, localPlugins map[
 			return nil, fmt.Errorf("%s: failed to read manifest: %w", desc.ModuleName, err)
 		}
 
+		if err = checkRuntime(manifest); err != nil {
+			return nil, fmt.Errorf("%s: %w", desc.ModuleName, err)
+		}
+
 		i := interp.New(interp.Options{GoPath: localGoPath})
 
 		err = i.Use(stdlib.Symbols)
@@ -108,6 +121,11 @@ func NewBuilder(client *Client, plugins map[string]Descriptor, localPlugins map[
 
 		switch manifest.Type {
 		case "middleware":
+			err = registerMatchers(i, manifest)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", desc.ModuleName, err)
+			}
+
 			pb.middlewareDescriptors[pName] = pluginContext{
 				interpreter: i,
 				GoPath:      localGoPath,
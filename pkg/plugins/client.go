@@ -16,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/traefik/traefik/v2/pkg/types"
 	"golang.org/x/mod/module"
 	"golang.org/x/mod/zip"
 	"gopkg.in/yaml.v3"
@@ -40,6 +41,8 @@ const (
 type ClientOptions struct {
 	Output string
 	Token  string
+	// Proxy is the upstream proxy used to download the plugins.
+	Proxy *types.Proxy
 }
 
 // Client a Traefik Pilot client.
@@ -78,8 +81,13 @@ func NewClient(opts ClientOptions) (*Client, error) {
 		return nil, fmt.Errorf("failed to create archives directory %s: %w", archivesPath, err)
 	}
 
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	if opts.Proxy != nil {
+		httpClient.Transport = opts.Proxy.WrapTransport(http.DefaultTransport.(*http.Transport).Clone())
+	}
+
 	return &Client{
-		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+		HTTPClient: httpClient,
 		baseURL:    baseURL,
 
 		archives:  archivesPath,
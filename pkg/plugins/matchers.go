@@ -0,0 +1,63 @@
+package plugins
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/rules"
+	"github.com/traefik/yaegi/interp"
+)
+
+// registerMatchers registers the rule matchers declared in the manifest of a middleware plugin.
+// The plugin builds them with its NewMatcher function:
+//
+//	func NewMatcher(name string, values ...string) (func(*http.Request) bool, error)
+func registerMatchers(i *interp.Interpreter, manifest *Manifest) error {
+	if len(manifest.Matchers) == 0 {
+		return nil
+	}
+
+	basePkg := manifest.BasePkg
+	if basePkg == "" {
+		basePkg = strings.ReplaceAll(path.Base(manifest.Import), "-", "_")
+	}
+
+	fnNew, err := i.Eval(basePkg + `.NewMatcher`)
+	if err != nil {
+		return fmt.Errorf("failed to eval NewMatcher: %w", err)
+	}
+
+	for _, name := range manifest.Matchers {
+		err = rules.RegisterMatcher(name, newMatcherConstructor(fnNew, name))
+		if err != nil {
+			return fmt.Errorf("failed to register matcher: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func newMatcherConstructor(fnNew reflect.Value, name string) rules.MatcherConstructor {
+	return func(values ...string) (func(*http.Request) bool, error) {
+		args := []reflect.Value{reflect.ValueOf(name)}
+		for _, value := range values {
+			args = append(args, reflect.ValueOf(value))
+		}
+
+		results := fnNew.Call(args)
+
+		if len(results) > 1 && results[1].Interface() != nil {
+			return nil, results[1].Interface().(error)
+		}
+
+		match, ok := results[0].Interface().(func(*http.Request) bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid matcher type: %T", results[0].Interface())
+		}
+
+		return match, nil
+	}
+}
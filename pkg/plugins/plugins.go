@@ -12,6 +12,8 @@ import (
 
 const localGoPath = "./plugins-local/"
 
+const runtimeYaegi = "yaegi"
+
 // SetupRemotePlugins setup remote plugins environment.
 func SetupRemotePlugins(client *Client, plugins map[string]Descriptor) error {
 	err := checkRemotePluginsConfiguration(plugins)
@@ -144,6 +146,10 @@ func checkLocalPluginManifest(descriptor LocalDescriptor) error {
 		errs = multierror.Append(errs, fmt.Errorf("%s: unsupported type %q", descriptor.ModuleName, m.Type))
 	}
 
+	if err := checkRuntime(m); err != nil {
+		errs = multierror.Append(errs, fmt.Errorf("%s: %w", descriptor.ModuleName, err))
+	}
+
 	if m.Import == "" {
 		errs = multierror.Append(errs, fmt.Errorf("%s: missing import", descriptor.ModuleName))
 	}
@@ -164,5 +170,20 @@ func checkLocalPluginManifest(descriptor LocalDescriptor) error {
 		errs = multierror.Append(errs, fmt.Errorf("%s: missing TestData", descriptor.ModuleName))
 	}
 
+	if len(m.Matchers) > 0 && m.Type != "middleware" {
+		errs = multierror.Append(errs, fmt.Errorf("%s: matchers are only supported by middleware plugins", descriptor.ModuleName))
+	}
+
 	return errs.ErrorOrNil()
 }
+
+// checkRuntime checks that the plugin runs in the Yaegi interpreter, the only supported runtime.
+// Plugins declaring another runtime (e.g. wasm) are rejected, rather than failing later on the import of their code.
+func checkRuntime(m *Manifest) error {
+	switch m.Runtime {
+	case "", runtimeYaegi:
+		return nil
+	default:
+		return fmt.Errorf("unsupported runtime %q, only %q is supported", m.Runtime, runtimeYaegi)
+	}
+}
@@ -19,9 +19,11 @@ type LocalDescriptor struct {
 type Manifest struct {
 	DisplayName   string                 `yaml:"displayName"`
 	Type          string                 `yaml:"type"`
+	Runtime       string                 `yaml:"runtime"`
 	Import        string                 `yaml:"import"`
 	BasePkg       string                 `yaml:"basePkg"`
 	Compatibility string                 `yaml:"compatibility"`
 	Summary       string                 `yaml:"summary"`
 	TestData      map[string]interface{} `yaml:"testData"`
+	Matchers      []string               `yaml:"matchers"`
 }
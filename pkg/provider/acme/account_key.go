@@ -0,0 +1,212 @@
+package acme
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/version"
+	"gopkg.in/square/go-jose.v2"
+)
+
+// RotateAccountKeys replaces the keys of the registered accounts of the resolver by new ones.
+// The new key of an account is only used, and stored, once the CA acknowledged the change,
+// so that the stored accounts are always usable.
+func (p *Provider) RotateAccountKeys(ctx context.Context) error {
+	p.clientMutex.Lock()
+	defer p.clientMutex.Unlock()
+
+	logger := log.FromContext(ctx).WithField(log.ProviderName, p.ResolverName+".acme")
+
+	for i := 0; i < p.accountCount(); i++ {
+		account := p.account
+		if i > 0 {
+			account = p.backupAccounts[i-1]
+		}
+
+		if account == nil || account.Registration == nil {
+			continue
+		}
+
+		newKey, err := rsa.GenerateKey(rand.Reader, 4096)
+		if err != nil {
+			return err
+		}
+
+		err = p.rolloverAccountKey(ctx, account, newKey)
+		if err != nil {
+			return fmt.Errorf("unable to rotate the key of the ACME account %s: %w", account.Email, err)
+		}
+
+		// The accounts are shared with the store, so the rotated account is a copy.
+		rotated := *account
+		rotated.PrivateKey = x509.MarshalPKCS1PrivateKey(newKey)
+
+		if i == 0 {
+			p.account = &rotated
+		} else {
+			p.backupAccounts[i-1] = &rotated
+		}
+
+		// The client holds the previous key, it is built again on next use.
+		if p.clients != nil {
+			p.clients[i] = nil
+		}
+
+		err = p.saveAccount(i)
+		if err != nil {
+			return fmt.Errorf("unable to store the rotated ACME account %s: %w", account.Email, err)
+		}
+
+		logger.Infof("The key of the ACME account %s has been rotated.", account.Email)
+	}
+
+	return nil
+}
+
+// rolloverAccountKey replaces the key of the account by the new key on the CA side,
+// as described in https://tools.ietf.org/html/rfc8555#section-7.3.5.
+func (p *Provider) rolloverAccountKey(ctx context.Context, account *Account, newKey *rsa.PrivateKey) error {
+	oldKey, ok := account.GetPrivateKey().(*rsa.PrivateKey)
+	if !ok {
+		return errors.New("unable to read the current account key")
+	}
+
+	caServer := lego.LEDirectoryProduction
+	if len(p.CAServer) > 0 {
+		caServer = p.CAServer
+	}
+
+	// Reuses the HTTP client configuration of the lego clients.
+	httpClient, err := p.newHTTPClient(account)
+	if err != nil {
+		return err
+	}
+
+	var directory acme.Directory
+	resp, err := doACMERequest(ctx, httpClient, http.MethodGet, caServer, nil)
+	if err != nil {
+		return fmt.Errorf("unable to get the CA directory: %w", err)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&directory)
+	_ = resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("unable to decode the CA directory: %w", err)
+	}
+
+	if len(directory.KeyChangeURL) == 0 {
+		return errors.New("the CA does not support account key rollover")
+	}
+
+	resp, err = doACMERequest(ctx, httpClient, http.MethodHead, directory.NewNonceURL, nil)
+	if err != nil {
+		return fmt.Errorf("unable to get a nonce: %w", err)
+	}
+	_ = resp.Body.Close()
+
+	nonce := resp.Header.Get("Replay-Nonce")
+	if len(nonce) == 0 {
+		return errors.New("unable to get a nonce: no Replay-Nonce header")
+	}
+
+	// The inner JWS is signed with the new key, and holds the account URL and the old key.
+	innerSigner, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: newKey},
+		(&jose.SignerOptions{EmbedJWK: true}).WithHeader("url", directory.KeyChangeURL),
+	)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(keyChange{
+		Account: account.Registration.URI,
+		OldKey:  jose.JSONWebKey{Key: oldKey.Public()},
+	})
+	if err != nil {
+		return err
+	}
+
+	inner, err := innerSigner.Sign(payload)
+	if err != nil {
+		return err
+	}
+
+	// The outer JWS is signed with the old key, as any other request of the account.
+	outerSigner, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: oldKey, KeyID: account.Registration.URI}},
+		(&jose.SignerOptions{NonceSource: staticNonce(nonce)}).WithHeader("url", directory.KeyChangeURL),
+	)
+	if err != nil {
+		return err
+	}
+
+	outer, err := outerSigner.Sign([]byte(inner.FullSerialize()))
+	if err != nil {
+		return err
+	}
+
+	resp, err = doACMERequest(ctx, httpClient, http.MethodPost, directory.KeyChangeURL, strings.NewReader(outer.FullSerialize()))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	return nil
+}
+
+// keyChange is the payload of the inner JWS of a key change request.
+type keyChange struct {
+	Account string          `json:"account"`
+	OldKey  jose.JSONWebKey `json:"oldKey"`
+}
+
+// staticNonce is a jose.NonceSource always providing the same nonce.
+type staticNonce string
+
+func (n staticNonce) Nonce() (string, error) {
+	return string(n), nil
+}
+
+// doACMERequest sends a request to the CA, and returns the problem sent by the CA as an error, if any.
+func doACMERequest(ctx context.Context, httpClient *http.Client, method, uri string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, uri, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", fmt.Sprintf("containous-traefik/%s", version.Version))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/jose+json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	problem := &acme.ProblemDetails{}
+	if err := json.NewDecoder(resp.Body).Decode(problem); err != nil {
+		return nil, fmt.Errorf("%d :: %s :: %s", resp.StatusCode, method, uri)
+	}
+
+	problem.Method = method
+	problem.URL = uri
+
+	return nil, problem
+}
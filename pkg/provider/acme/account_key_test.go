@@ -0,0 +1,227 @@
+package acme
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+// fakeStore is a Store keeping the accounts in memory.
+type fakeStore struct {
+	account        *Account
+	backupAccounts []*Account
+}
+
+func (s *fakeStore) GetAccount(string) (*Account, error) { return s.account, nil }
+
+func (s *fakeStore) SaveAccount(_ string, account *Account) error {
+	s.account = account
+	return nil
+}
+
+func (s *fakeStore) GetBackupAccounts(string) ([]*Account, error) { return s.backupAccounts, nil }
+
+func (s *fakeStore) SaveBackupAccounts(_ string, accounts []*Account) error {
+	s.backupAccounts = accounts
+	return nil
+}
+
+func (s *fakeStore) GetCertificates(string) ([]*CertAndStore, error) { return nil, nil }
+
+func (s *fakeStore) SaveCertificates(string, []*CertAndStore) error { return nil }
+
+// newFakeCA returns a CA server which only implements the key change of the account with the given key.
+// The keys registered by the successful key changes are sent to the keys channel.
+func newFakeCA(t *testing.T, accountURI string, accountKey *rsa.PrivateKey, keys chan<- *rsa.PublicKey) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/directory", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(acme.Directory{
+			NewNonceURL:  server.URL + "/nonce",
+			KeyChangeURL: server.URL + "/key-change",
+		})
+	})
+
+	mux.HandleFunc("/nonce", func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Replay-Nonce", "nonce")
+	})
+
+	mux.HandleFunc("/key-change", func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		outer, err := jose.ParseSigned(string(body))
+		require.NoError(t, err)
+
+		header := outer.Signatures[0].Protected
+		if header.KeyID != accountURI || header.Nonce != "nonce" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(rw, `{"type":"urn:ietf:params:acme:error:unauthorized","detail":"unknown account"}`)
+			return
+		}
+
+		innerPayload, err := outer.Verify(&accountKey.PublicKey)
+		require.NoError(t, err)
+
+		inner, err := jose.ParseSigned(string(innerPayload))
+		require.NoError(t, err)
+
+		newKey := inner.Signatures[0].Protected.JSONWebKey
+		require.NotNil(t, newKey)
+
+		payload, err := inner.Verify(newKey)
+		require.NoError(t, err)
+
+		var change keyChange
+		require.NoError(t, json.Unmarshal(payload, &change))
+		assert.Equal(t, accountURI, change.Account)
+
+		oldKey, ok := change.OldKey.Key.(*rsa.PublicKey)
+		require.True(t, ok)
+		assert.True(t, oldKey.Equal(&accountKey.PublicKey))
+
+		keys <- newKey.Key.(*rsa.PublicKey)
+	})
+
+	return server
+}
+
+func TestProvider_RotateAccountKeys(t *testing.T) {
+	accountKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := make(chan *rsa.PublicKey, 1)
+	server := newFakeCA(t, "https://ca.example.com/acct/1", accountKey, keys)
+
+	account := &Account{
+		Email:        "foo@example.com",
+		Registration: &registration.Resource{URI: "https://ca.example.com/acct/1"},
+		PrivateKey:   x509.MarshalPKCS1PrivateKey(accountKey),
+	}
+
+	store := &fakeStore{account: account}
+	p := &Provider{
+		Configuration: &Configuration{
+			CAServer:       server.URL + "/directory",
+			BackupAccounts: []BackupAccount{{Email: "bar@example.com"}},
+		},
+		Store:          store,
+		account:        account,
+		backupAccounts: []*Account{nil},
+	}
+
+	err = p.RotateAccountKeys(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, keys, 1)
+	newKey := <-keys
+
+	require.NotNil(t, store.account)
+	storedKey, ok := store.account.GetPrivateKey().(*rsa.PrivateKey)
+	require.True(t, ok)
+	assert.True(t, newKey.Equal(&storedKey.PublicKey))
+	assert.Same(t, store.account, p.account)
+
+	// The previous account is left untouched.
+	assert.Equal(t, x509.MarshalPKCS1PrivateKey(accountKey), account.PrivateKey)
+
+	// The backup account is not registered yet, so there is no key to rotate.
+	assert.Nil(t, store.backupAccounts)
+}
+
+func TestProvider_RotateAccountKeys_rejected(t *testing.T) {
+	accountKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys := make(chan *rsa.PublicKey, 1)
+	server := newFakeCA(t, "https://ca.example.com/acct/1", accountKey, keys)
+
+	account := &Account{
+		Email:        "foo@example.com",
+		Registration: &registration.Resource{URI: "https://ca.example.com/acct/2"},
+		PrivateKey:   x509.MarshalPKCS1PrivateKey(accountKey),
+	}
+
+	store := &fakeStore{}
+	p := &Provider{
+		Configuration: &Configuration{CAServer: server.URL + "/directory"},
+		Store:         store,
+		account:       account,
+	}
+
+	err = p.RotateAccountKeys(context.Background())
+
+	var problem *acme.ProblemDetails
+	require.ErrorAs(t, err, &problem)
+	assert.Equal(t, "urn:ietf:params:acme:error:unauthorized", problem.Type)
+
+	// The account keeps its key.
+	assert.Same(t, account, p.account)
+	assert.Nil(t, store.account)
+}
+
+func TestIsRateLimited(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		expected bool
+	}{
+		{
+			desc: "no error",
+		},
+		{
+			desc: "other error",
+			err:  fmt.Errorf("unable to generate a certificate: %w", io.EOF),
+		},
+		{
+			desc:     "rate limited problem",
+			err:      fmt.Errorf("unable to generate a certificate: %w", &acme.ProblemDetails{Type: rateLimitedErr, HTTPStatus: http.StatusTooManyRequests}),
+			expected: true,
+		},
+		{
+			desc:     "too many requests",
+			err:      &acme.ProblemDetails{HTTPStatus: http.StatusTooManyRequests},
+			expected: true,
+		},
+		{
+			desc: "other problem",
+			err:  &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:rejectedIdentifier", HTTPStatus: http.StatusBadRequest},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, isRateLimited(test.err))
+		})
+	}
+}
+
+func TestFindAccount(t *testing.T) {
+	foo := &Account{Email: "foo@example.com"}
+	bar := &Account{Email: "bar@example.com"}
+
+	accounts := []*Account{foo, nil, bar}
+
+	assert.Same(t, bar, findAccount(accounts, "bar@example.com"))
+	assert.Nil(t, findAccount(accounts, "baz@example.com"))
+}
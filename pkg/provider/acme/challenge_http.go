@@ -17,10 +17,20 @@ import (
 	"github.com/traefik/traefik/v2/pkg/safe"
 )
 
+// HTTPChallengeStore shares the HTTP challenges between the instances of a cluster,
+// as the ACME server may reach any of them.
+type HTTPChallengeStore interface {
+	SetHTTPChallenge(token, domain string, keyAuth []byte) error
+	GetHTTPChallenge(token, domain string) ([]byte, error)
+	DeleteHTTPChallenge(token, domain string) error
+}
+
 // ChallengeHTTP HTTP challenge provider implements challenge.Provider.
 type ChallengeHTTP struct {
 	httpChallenges map[string]map[string][]byte
 	lock           sync.RWMutex
+
+	store HTTPChallengeStore
 }
 
 // NewChallengeHTTP creates a new ChallengeHTTP.
@@ -30,6 +40,11 @@ func NewChallengeHTTP() *ChallengeHTTP {
 	}
 }
 
+// SetStore sets the store sharing the HTTP challenges with the other instances of the cluster.
+func (c *ChallengeHTTP) SetStore(store HTTPChallengeStore) {
+	c.store = store
+}
+
 // Present presents a challenge to obtain new ACME certificate.
 func (c *ChallengeHTTP) Present(domain, token, keyAuth string) error {
 	c.lock.Lock()
@@ -41,6 +56,10 @@ func (c *ChallengeHTTP) Present(domain, token, keyAuth string) error {
 
 	c.httpChallenges[token][domain] = []byte(keyAuth)
 
+	if c.store != nil {
+		return c.store.SetHTTPChallenge(token, domain, []byte(keyAuth))
+	}
+
 	return nil
 }
 
@@ -61,6 +80,10 @@ func (c *ChallengeHTTP) CleanUp(domain, token, _ string) error {
 		}
 	}
 
+	if c.store != nil {
+		return c.store.DeleteHTTPChallenge(token, domain)
+	}
+
 	return nil
 }
 
@@ -108,20 +131,14 @@ func (c *ChallengeHTTP) getTokenValue(ctx context.Context, token, domain string)
 	var result []byte
 
 	operation := func() error {
-		c.lock.RLock()
-		defer c.lock.RUnlock()
-
-		if _, ok := c.httpChallenges[token]; !ok {
-			return fmt.Errorf("cannot find challenge for token %s", token)
+		var err error
+		result, err = c.getLocalTokenValue(token, domain)
+		if err != nil && c.store != nil {
+			// The challenge may have been presented by another instance of the cluster.
+			result, err = c.store.GetHTTPChallenge(token, domain)
 		}
 
-		var ok bool
-		result, ok = c.httpChallenges[token][domain]
-		if !ok {
-			return fmt.Errorf("cannot find challenge for domain %s", domain)
-		}
-
-		return nil
+		return err
 	}
 
 	notify := func(err error, time time.Duration) {
@@ -139,6 +156,22 @@ func (c *ChallengeHTTP) getTokenValue(ctx context.Context, token, domain string)
 	return result
 }
 
+func (c *ChallengeHTTP) getLocalTokenValue(token, domain string) ([]byte, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if _, ok := c.httpChallenges[token]; !ok {
+		return nil, fmt.Errorf("cannot find challenge for token %s", token)
+	}
+
+	result, ok := c.httpChallenges[token][domain]
+	if !ok {
+		return nil, fmt.Errorf("cannot find challenge for domain %s", domain)
+	}
+
+	return result, nil
+}
+
 func getPathParam(uri *url.URL) (string, error) {
 	exp := regexp.MustCompile(fmt.Sprintf(`^%s([^/]+)/?$`, http01.ChallengePath("")))
 	parts := exp.FindStringSubmatch(uri.Path)
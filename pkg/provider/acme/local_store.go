@@ -148,6 +148,29 @@ func (s *LocalStore) SaveAccount(resolverName string, account *Account) error {
 	return nil
 }
 
+// GetBackupAccounts returns the ACME backup accounts.
+func (s *LocalStore) GetBackupAccounts(resolverName string) ([]*Account, error) {
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return nil, err
+	}
+
+	return storedData.BackupAccounts, nil
+}
+
+// SaveBackupAccounts stores the ACME backup accounts.
+func (s *LocalStore) SaveBackupAccounts(resolverName string, accounts []*Account) error {
+	storedData, err := s.get(resolverName)
+	if err != nil {
+		return err
+	}
+
+	storedData.BackupAccounts = accounts
+	s.save(resolverName, storedData)
+
+	return nil
+}
+
 // GetCertificates returns ACME Certificates list.
 func (s *LocalStore) GetCertificates(resolverName string) ([]*CertAndStore, error) {
 	storedData, err := s.get(resolverName)
@@ -4,14 +4,18 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"os"
 	"reflect"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
@@ -28,6 +32,13 @@ import (
 	"github.com/traefik/traefik/v2/pkg/version"
 )
 
+// rateLimitedErr is the type of the problems returned by the CA when an account is rate limited.
+const rateLimitedErr = "urn:ietf:params:acme:error:rateLimited"
+
+// clusterSyncInterval is the interval at which the instances which are not the leader of the cluster
+// reload the certificates from the shared store.
+const clusterSyncInterval = time.Minute
+
 // oscpMustStaple enables OSCP stapling as from https://github.com/go-acme/lego/issues/270.
 var oscpMustStaple = false
 
@@ -40,6 +51,12 @@ type Configuration struct {
 	KeyType        string `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
 	EAB            *EAB   `description:"External Account Binding to use." json:"eab,omitempty" toml:"eab,omitempty" yaml:"eab,omitempty"`
 
+	CACertificates   []string `description:"Paths of the PEM encoded certificates of the CA server, trusted in addition to the system pool when caSystemCertPool is enabled." json:"caCertificates,omitempty" toml:"caCertificates,omitempty" yaml:"caCertificates,omitempty" export:"true"`
+	CASystemCertPool bool     `description:"Trust the system certificates pool along with the CA certificates." json:"caSystemCertPool,omitempty" toml:"caSystemCertPool,omitempty" yaml:"caSystemCertPool,omitempty" export:"true"`
+	CAServerName     string   `description:"Server name used to verify the certificate of the CA server." json:"caServerName,omitempty" toml:"caServerName,omitempty" yaml:"caServerName,omitempty" export:"true"`
+
+	BackupAccounts []BackupAccount `description:"Accounts used, in order, when the CA rate limits the primary account." json:"backupAccounts,omitempty" toml:"backupAccounts,omitempty" yaml:"backupAccounts,omitempty"`
+
 	DNSChallenge  *DNSChallenge  `description:"Activate DNS-01 Challenge." json:"dnsChallenge,omitempty" toml:"dnsChallenge,omitempty" yaml:"dnsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	HTTPChallenge *HTTPChallenge `description:"Activate HTTP-01 Challenge." json:"httpChallenge,omitempty" toml:"httpChallenge,omitempty" yaml:"httpChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	TLSChallenge  *TLSChallenge  `description:"Activate TLS-ALPN-01 Challenge." json:"tlsChallenge,omitempty" toml:"tlsChallenge,omitempty" yaml:"tlsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -71,6 +88,31 @@ type EAB struct {
 	HmacEncoded string `description:"Base64 encoded HMAC key from External CA." json:"hmacEncoded,omitempty" toml:"hmacEncoded,omitempty" yaml:"hmacEncoded,omitempty"`
 }
 
+// validate checks that the key identifier and the HMAC key are set,
+// the HMAC key being base64 URL encoded without padding as expected by the CA.
+// A nil EAB is valid.
+func (e *EAB) validate() error {
+	if e == nil {
+		return nil
+	}
+
+	if e.Kid == "" || e.HmacEncoded == "" {
+		return errors.New("both the key identifier and the HMAC key are required")
+	}
+
+	if _, err := base64.RawURLEncoding.DecodeString(e.HmacEncoded); err != nil {
+		return fmt.Errorf("the HMAC key must be base64 URL encoded without padding: %w", err)
+	}
+
+	return nil
+}
+
+// BackupAccount contains the configuration of an ACME account used when the primary account is rate limited.
+type BackupAccount struct {
+	Email string `description:"Email address used for registration." json:"email,omitempty" toml:"email,omitempty" yaml:"email,omitempty"`
+	EAB   *EAB   `description:"External Account Binding to use." json:"eab,omitempty" toml:"eab,omitempty" yaml:"eab,omitempty"`
+}
+
 // DNSChallenge contains DNS challenge configuration.
 type DNSChallenge struct {
 	Provider                string          `description:"Use a DNS-01 based challenge provider rather than HTTPS." json:"provider,omitempty" toml:"provider,omitempty" yaml:"provider,omitempty" export:"true"`
@@ -96,9 +138,17 @@ type Provider struct {
 	TLSChallengeProvider  challenge.Provider
 	HTTPChallengeProvider challenge.Provider
 
+	// Proxy is the upstream proxy used to reach the ACME server, instead of the environment variables.
+	Proxy *types.Proxy
+
+	// IsLeader, when set, reports whether this instance is the leader of its cluster.
+	// Only the leader obtains and renews the certificates, the other instances reload them periodically from the shared store.
+	IsLeader func() bool
+
 	certificates           []*CertAndStore
 	account                *Account
-	client                 *lego.Client
+	backupAccounts         []*Account
+	clients                []*lego.Client
 	certsChan              chan *CertAndStore
 	configurationChan      chan<- dynamic.Message
 	tlsManager             *traefiktls.Manager
@@ -107,6 +157,14 @@ type Provider struct {
 	pool                   *safe.Pool
 	resolvingDomains       map[string]struct{}
 	resolvingDomainsMutex  sync.RWMutex
+	pendingDomains         map[string]pendingDomain
+	pendingDomainsMutex    sync.Mutex
+}
+
+// pendingDomain is a domain for which a certificate is requested while this instance is not the cluster leader.
+type pendingDomain struct {
+	domain   types.Domain
+	tlsStore string
 }
 
 // SetTLSManager sets the tls manager to use.
@@ -133,6 +191,12 @@ func (p *Provider) Init() error {
 		return errors.New("unable to initialize ACME provider with no storage location for the certificates")
 	}
 
+	// The TLS challenge certificates are only served by the instance presenting them,
+	// whereas the ACME server may reach any instance of the cluster.
+	if p.IsLeader != nil && p.TLSChallenge != nil {
+		return errors.New("the TLS challenge is not supported in cluster mode, use the HTTP or DNS challenge instead")
+	}
+
 	var err error
 	p.account, err = p.Store.GetAccount(p.ResolverName)
 	if err != nil {
@@ -145,6 +209,29 @@ func (p *Provider) Init() error {
 		p.account = nil
 	}
 
+	storedBackupAccounts, err := p.Store.GetBackupAccounts(p.ResolverName)
+	if err != nil {
+		return fmt.Errorf("unable to get ACME backup accounts: %w", err)
+	}
+
+	if err := p.EAB.validate(); err != nil {
+		return fmt.Errorf("invalid external account binding: %w", err)
+	}
+
+	p.backupAccounts = make([]*Account, len(p.BackupAccounts))
+	for i, backupAccount := range p.BackupAccounts {
+		if err := backupAccount.EAB.validate(); err != nil {
+			return fmt.Errorf("invalid external account binding of the backup account %s: %w", backupAccount.Email, err)
+		}
+
+		p.backupAccounts[i] = findAccount(storedBackupAccounts, backupAccount.Email)
+
+		if p.backupAccounts[i] != nil && p.backupAccounts[i].Registration != nil && !isAccountMatchingCaServer(ctx, p.backupAccounts[i].Registration.URI, p.CAServer) {
+			logger.Infof("Backup account %s URI does not match the current CAServer. The account will be reset.", backupAccount.Email)
+			p.backupAccounts[i] = nil
+		}
+	}
+
 	p.certificates, err = p.Store.GetCertificates(p.ResolverName)
 	if err != nil {
 		return fmt.Errorf("unable to get ACME certificates : %w", err)
@@ -156,6 +243,17 @@ func (p *Provider) Init() error {
 	return nil
 }
 
+// findAccount returns the account registered with the given email, if any.
+func findAccount(accounts []*Account, email string) *Account {
+	for _, account := range accounts {
+		if account != nil && account.Email == email {
+			return account
+		}
+	}
+
+	return nil
+}
+
 func isAccountMatchingCaServer(ctx context.Context, accountURI, serverURI string) bool {
 	logger := log.FromContext(ctx)
 
@@ -206,17 +304,41 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 }
 
 func (p *Provider) getClient() (*lego.Client, error) {
+	return p.getAccountClient(0)
+}
+
+// accountCount returns the number of accounts of the resolver, the primary account included.
+func (p *Provider) accountCount() int {
+	return 1 + len(p.BackupAccounts)
+}
+
+// getAccountClient returns the client of the account at the given index,
+// the index 0 being the primary account, and the next ones the backup accounts.
+func (p *Provider) getAccountClient(index int) (*lego.Client, error) {
 	p.clientMutex.Lock()
 	defer p.clientMutex.Unlock()
 
 	ctx := log.With(context.Background(), log.Str(log.ProviderName, p.ResolverName+".acme"))
 	logger := log.FromContext(ctx)
 
-	if p.client != nil {
-		return p.client, nil
+	if p.clients == nil {
+		p.clients = make([]*lego.Client, p.accountCount())
+	}
+
+	if p.clients[index] != nil {
+		return p.clients[index], nil
 	}
 
-	account, err := p.initAccount(ctx)
+	var account *Account
+	var err error
+	var eab *EAB
+	if index == 0 {
+		account, err = p.initAccount(ctx)
+		eab = p.EAB
+	} else {
+		account, err = p.initBackupAccount(ctx, index-1)
+		eab = p.BackupAccounts[index-1].EAB
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -234,6 +356,11 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	config.Certificate.KeyType = GetKeyType(ctx, p.KeyType)
 	config.UserAgent = fmt.Sprintf("containous-traefik/%s", version.Version)
 
+	config.HTTPClient, err = p.newHTTPClient(account)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := lego.NewClient(config)
 	if err != nil {
 		return nil, err
@@ -241,7 +368,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 
 	// New users will need to register; be sure to save it
 	if account.GetRegistration() == nil {
-		reg, errR := p.register(ctx, client)
+		reg, errR := p.register(ctx, client, eab)
 		if errR != nil {
 			return nil, errR
 		}
@@ -251,7 +378,7 @@ func (p *Provider) getClient() (*lego.Client, error) {
 
 	// Save the account once before all the certificates generation/storing
 	// No certificate can be generated if account is not initialized
-	err = p.Store.SaveAccount(p.ResolverName, account)
+	err = p.saveAccount(index)
 	if err != nil {
 		return nil, err
 	}
@@ -309,34 +436,125 @@ func (p *Provider) getClient() (*lego.Client, error) {
 		}
 	}
 
-	p.client = client
-	return p.client, nil
+	p.clients[index] = client
+	return client, nil
+}
+
+// saveAccount stores the account at the given index, see getAccountClient.
+func (p *Provider) saveAccount(index int) error {
+	if index == 0 {
+		return p.Store.SaveAccount(p.ResolverName, p.account)
+	}
+
+	// The stored slice is a copy, so that the store never reads the slice while it is updated.
+	backupAccounts := make([]*Account, len(p.backupAccounts))
+	copy(backupAccounts, p.backupAccounts)
+
+	return p.Store.SaveBackupAccounts(p.ResolverName, backupAccounts)
 }
 
 func (p *Provider) initAccount(ctx context.Context) (*Account, error) {
-	if p.account == nil || len(p.account.Email) == 0 {
+	var err error
+	p.account, err = p.completeAccount(ctx, p.account, p.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.account, nil
+}
+
+func (p *Provider) initBackupAccount(ctx context.Context, index int) (*Account, error) {
+	account, err := p.completeAccount(ctx, p.backupAccounts[index], p.BackupAccounts[index].Email)
+	if err != nil {
+		return nil, err
+	}
+
+	p.backupAccounts[index] = account
+
+	return account, nil
+}
+
+// completeAccount creates the account if it does not exist yet, and sets its KeyType if needed.
+func (p *Provider) completeAccount(ctx context.Context, account *Account, email string) (*Account, error) {
+	if account == nil || len(account.Email) == 0 {
 		var err error
-		p.account, err = NewAccount(ctx, p.Email, p.KeyType)
+		account, err = NewAccount(ctx, email, p.KeyType)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// Set the KeyType if not already defined in the account
-	if len(p.account.KeyType) == 0 {
-		p.account.KeyType = GetKeyType(ctx, p.KeyType)
+	if len(account.KeyType) == 0 {
+		account.KeyType = GetKeyType(ctx, p.KeyType)
 	}
 
-	return p.account, nil
+	return account, nil
+}
+
+// newHTTPClient returns the HTTP client used to reach the CA server,
+// trusting the configured CA certificates, and going through the configured proxy.
+func (p *Provider) newHTTPClient(account *Account) (*http.Client, error) {
+	httpClient := lego.NewConfig(account).HTTPClient
+
+	tr, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		return httpClient, nil
+	}
+
+	if len(p.CACertificates) > 0 || p.CASystemCertPool {
+		rootCAs, err := p.caCertPool()
+		if err != nil {
+			return nil, err
+		}
+
+		tr.TLSClientConfig.RootCAs = rootCAs
+	}
+
+	if p.CAServerName != "" {
+		tr.TLSClientConfig.ServerName = p.CAServerName
+	}
+
+	httpClient.Transport = p.Proxy.WrapTransport(tr)
+
+	return httpClient, nil
 }
 
-func (p *Provider) register(ctx context.Context, client *lego.Client) (*registration.Resource, error) {
+// caCertPool returns the pool of the CA certificates, starting from the system pool when caSystemCertPool is enabled.
+func (p *Provider) caCertPool() (*x509.CertPool, error) {
+	certPool := x509.NewCertPool()
+	if p.CASystemCertPool {
+		systemPool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the system certificates pool: %w", err)
+		}
+
+		if systemPool != nil {
+			certPool = systemPool
+		}
+	}
+
+	for _, path := range p.CACertificates {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA certificates: %w", err)
+		}
+
+		if !certPool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("no PEM encoded certificate found in the CA certificates %s", path)
+		}
+	}
+
+	return certPool, nil
+}
+
+func (p *Provider) register(ctx context.Context, client *lego.Client, eab *EAB) (*registration.Resource, error) {
 	logger := log.FromContext(ctx)
 
-	if p.EAB != nil {
+	if eab != nil {
 		logger.Info("Register with external account binding...")
 
-		eabOptions := registration.RegisterEABOptions{TermsOfServiceAgreed: true, Kid: p.EAB.Kid, HmacEncoded: p.EAB.HmacEncoded}
+		eabOptions := registration.RegisterEABOptions{TermsOfServiceAgreed: true, Kid: eab.Kid, HmacEncoded: eab.HmacEncoded}
 
 		return client.Registration.RegisterWithExternalAccountBinding(eabOptions)
 	}
@@ -451,6 +669,12 @@ func (p *Provider) watchNewDomains(ctx context.Context) {
 }
 
 func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain, tlsStore string) (*certificate.Resource, error) {
+	if !p.isLeader() {
+		log.FromContext(ctx).Debugf("Not the cluster leader, postponing the certificate of the domains %v", domain.ToStrArray())
+		p.addPendingDomain(domain, tlsStore)
+		return nil, nil
+	}
+
 	domains, err := p.getValidDomains(ctx, domain)
 	if err != nil {
 		return nil, err
@@ -467,11 +691,6 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	logger := log.FromContext(ctx)
 	logger.Debugf("Loading ACME certificates %+v...", uncheckedDomains)
 
-	client, err := p.getClient()
-	if err != nil {
-		return nil, fmt.Errorf("cannot get ACME client %w", err)
-	}
-
 	request := certificate.ObtainRequest{
 		Domains:        domains,
 		Bundle:         true,
@@ -479,9 +698,17 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 		PreferredChain: p.PreferredChain,
 	}
 
-	cert, err := client.Certificate.Obtain(request)
+	var cert *certificate.Resource
+	err = p.withFailover(ctx, func(client *lego.Client) error {
+		var errObtain error
+		cert, errObtain = client.Certificate.Obtain(request)
+		if errObtain != nil {
+			return fmt.Errorf("unable to generate a certificate for the domains %v: %w", uncheckedDomains, errObtain)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("unable to generate a certificate for the domains %v: %w", uncheckedDomains, err)
+		return nil, err
 	}
 	if cert == nil {
 		return nil, fmt.Errorf("domains %v do not generate a certificate", uncheckedDomains)
@@ -502,6 +729,51 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	return cert, nil
 }
 
+// withFailover calls fn with the client of the primary account,
+// and then with the clients of the backup accounts, in order, as long as the CA rate limits the account in use.
+func (p *Provider) withFailover(ctx context.Context, fn func(client *lego.Client) error) error {
+	logger := log.FromContext(ctx)
+
+	var err error
+	for i := 0; i < p.accountCount(); i++ {
+		var client *lego.Client
+		client, err = p.getAccountClient(i)
+		if err != nil {
+			return fmt.Errorf("cannot get ACME client %w", err)
+		}
+
+		err = fn(client)
+		if !isRateLimited(err) {
+			return err
+		}
+
+		if i+1 < p.accountCount() {
+			logger.Warnf("The ACME account %s is rate limited, falling back on the backup account %s: %v", p.accountEmail(i), p.accountEmail(i+1), err)
+		}
+	}
+
+	return err
+}
+
+// accountEmail returns the email of the account at the given index, see getAccountClient.
+func (p *Provider) accountEmail(index int) string {
+	if index == 0 {
+		return p.Email
+	}
+
+	return p.BackupAccounts[index-1].Email
+}
+
+// isRateLimited reports whether the error is the response of the CA to a rate limited request.
+func isRateLimited(err error) bool {
+	var problem *acme.ProblemDetails
+	if !errors.As(err, &problem) {
+		return false
+	}
+
+	return problem.Type == rateLimitedErr || problem.HTTPStatus == http.StatusTooManyRequests
+}
+
 func (p *Provider) removeResolvingDomains(resolvingDomains []string) {
 	p.resolvingDomainsMutex.Lock()
 	defer p.resolvingDomainsMutex.Unlock()
@@ -576,8 +848,30 @@ func (p *Provider) watchCertificate(ctx context.Context) {
 	p.certsChan = make(chan *CertAndStore)
 
 	p.pool.GoCtx(func(ctxPool context.Context) {
+		var syncTicks <-chan time.Time
+		if p.IsLeader != nil {
+			ticker := time.NewTicker(clusterSyncInterval)
+			defer ticker.Stop()
+			syncTicks = ticker.C
+		}
+
+		wasLeader := p.isLeader()
+
 		for {
 			select {
+			case <-syncTicks:
+				leader := p.isLeader()
+				if !leader {
+					p.syncFromStore(ctx)
+				} else if !wasLeader {
+					// The certificates of the previous leader may be due for renewal,
+					// and the domains seen while not being the leader may not have been obtained by it.
+					safe.Go(func() {
+						p.renewCertificates(ctx)
+						p.resolvePendingDomains(ctx)
+					})
+				}
+				wasLeader = leader
 			case cert := <-p.certsChan:
 				certUpdated := false
 				for _, domainsCertificate := range p.certificates {
@@ -602,6 +896,71 @@ func (p *Provider) watchCertificate(ctx context.Context) {
 	})
 }
 
+// syncFromStore reloads the certificates obtained by the leader of the cluster from the shared store,
+// along with the account, unless the client of this instance is already built.
+func (p *Provider) syncFromStore(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	p.clientMutex.Lock()
+	if p.account == nil && p.clients == nil {
+		account, err := p.Store.GetAccount(p.ResolverName)
+		if err != nil {
+			logger.Errorf("Unable to reload the ACME account from the store: %v", err)
+		} else {
+			p.account = account
+		}
+	}
+	p.clientMutex.Unlock()
+
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		logger.Errorf("Unable to reload the ACME certificates from the store: %v", err)
+		return
+	}
+
+	if reflect.DeepEqual(certificates, p.certificates) {
+		return
+	}
+
+	logger.Debug("Reloading the ACME certificates obtained by the cluster leader")
+
+	p.certificates = certificates
+	p.refreshCertificates()
+}
+
+// addPendingDomain records a domain to resolve once this instance becomes the cluster leader.
+func (p *Provider) addPendingDomain(domain types.Domain, tlsStore string) {
+	p.pendingDomainsMutex.Lock()
+	defer p.pendingDomainsMutex.Unlock()
+
+	if p.pendingDomains == nil {
+		p.pendingDomains = make(map[string]pendingDomain)
+	}
+
+	key := tlsStore + "/" + strings.Join(domain.ToStrArray(), ",")
+	p.pendingDomains[key] = pendingDomain{domain: domain, tlsStore: tlsStore}
+}
+
+// resolvePendingDomains resolves the certificates of the domains seen while this instance was not the cluster leader.
+// The domains already obtained by the previous leader are skipped, as their certificates are reloaded from the store.
+func (p *Provider) resolvePendingDomains(ctx context.Context) {
+	p.pendingDomainsMutex.Lock()
+	pendingDomains := p.pendingDomains
+	p.pendingDomains = nil
+	p.pendingDomainsMutex.Unlock()
+
+	for _, pending := range pendingDomains {
+		if _, err := p.resolveCertificate(ctx, pending.domain, pending.tlsStore); err != nil {
+			log.FromContext(ctx).Errorf("Unable to obtain ACME certificate for domains %q: %v", strings.Join(pending.domain.ToStrArray(), ","), err)
+		}
+	}
+}
+
+// isLeader returns whether this instance obtains and renews the certificates.
+func (p *Provider) isLeader() bool {
+	return p.IsLeader == nil || p.IsLeader()
+}
+
 func (p *Provider) saveCertificates() error {
 	err := p.Store.SaveCertificates(p.ResolverName, p.certificates)
 
@@ -640,25 +999,29 @@ func (p *Provider) refreshCertificates() {
 func (p *Provider) renewCertificates(ctx context.Context) {
 	logger := log.FromContext(ctx)
 
+	if !p.isLeader() {
+		logger.Debug("Not the cluster leader, skipping the certificates renewal")
+		return
+	}
+
 	logger.Info("Testing certificate renew...")
 	for _, cert := range p.certificates {
 		crt, err := getX509Certificate(ctx, &cert.Certificate)
 		// If there's an error, we assume the cert is broken, and needs update
 		// <= 30 days left, renew certificate
 		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(24*30*time.Hour)) {
-			client, err := p.getClient()
-			if err != nil {
-				logger.Infof("Error renewing certificate from LE : %+v, %v", cert.Domain, err)
-				continue
-			}
-
 			logger.Infof("Renewing certificate from LE : %+v", cert.Domain)
 
-			renewedCert, err := client.Certificate.Renew(certificate.Resource{
-				Domain:      cert.Domain.Main,
-				PrivateKey:  cert.Key,
-				Certificate: cert.Certificate.Certificate,
-			}, true, oscpMustStaple, p.PreferredChain)
+			var renewedCert *certificate.Resource
+			err = p.withFailover(ctx, func(client *lego.Client) error {
+				var errRenew error
+				renewedCert, errRenew = client.Certificate.Renew(certificate.Resource{
+					Domain:      cert.Domain.Main,
+					PrivateKey:  cert.Key,
+					Certificate: cert.Certificate.Certificate,
+				}, true, oscpMustStaple, p.PreferredChain)
+				return errRenew
+			})
 			if err != nil {
 				logger.Errorf("Error renewing certificate from LE: %v, %v", cert.Domain, err)
 				continue
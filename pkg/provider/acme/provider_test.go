@@ -3,10 +3,16 @@ package acme
 import (
 	"context"
 	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -592,3 +598,164 @@ func TestInitAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestEAB_validate(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		eab       *EAB
+		expectErr bool
+	}{
+		{
+			desc: "no external account binding",
+		},
+		{
+			desc: "valid external account binding",
+			eab:  &EAB{Kid: "kid", HmacEncoded: "c2VjcmV0LWhtYWMta2V5"},
+		},
+		{
+			desc:      "missing key identifier",
+			eab:       &EAB{HmacEncoded: "c2VjcmV0LWhtYWMta2V5"},
+			expectErr: true,
+		},
+		{
+			desc:      "missing HMAC key",
+			eab:       &EAB{Kid: "kid"},
+			expectErr: true,
+		},
+		{
+			desc:      "padded HMAC key",
+			eab:       &EAB{Kid: "kid", HmacEncoded: "c2VjcmV0LWhtYWM="},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.eab.validate()
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProvider_newHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caContent := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, caContent, 0o600))
+
+	invalidCAPath := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidCAPath, []byte("invalid"), 0o600))
+
+	testCases := []struct {
+		desc           string
+		caCertificates []string
+		caServerName   string
+		expectErr      bool
+		expectReqErr   bool
+	}{
+		{
+			desc:         "untrusted CA server",
+			expectReqErr: true,
+		},
+		{
+			desc:           "trusted CA server",
+			caCertificates: []string{caPath},
+		},
+		{
+			desc:           "trusted CA server with a server name",
+			caCertificates: []string{caPath},
+			caServerName:   "example.com",
+		},
+		{
+			desc:           "unexpected server name",
+			caCertificates: []string{caPath},
+			caServerName:   "traefik.test",
+			expectReqErr:   true,
+		},
+		{
+			desc:           "missing CA certificates",
+			caCertificates: []string{filepath.Join(t.TempDir(), "missing.pem")},
+			expectErr:      true,
+		},
+		{
+			desc:           "invalid CA certificates",
+			caCertificates: []string{invalidCAPath},
+			expectErr:      true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{Configuration: &Configuration{
+				CACertificates: test.caCertificates,
+				CAServerName:   test.caServerName,
+			}}
+
+			httpClient, err := p.newHTTPClient(&Account{})
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			resp, err := httpClient.Get(server.URL)
+			if test.expectReqErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestProvider_Init_clusterTLSChallenge(t *testing.T) {
+	p := &Provider{
+		Configuration: &Configuration{Storage: "acme.json", TLSChallenge: &TLSChallenge{}},
+		IsLeader:      func() bool { return true },
+	}
+
+	assert.Error(t, p.Init())
+}
+
+func TestProvider_resolveCertificate_notLeader(t *testing.T) {
+	p := &Provider{
+		Configuration: &Configuration{},
+		IsLeader:      func() bool { return false },
+	}
+
+	domain := types.Domain{Main: "traefik.wtf", SANs: []string{"www.traefik.wtf"}}
+
+	cert, err := p.resolveCertificate(context.Background(), domain, "default")
+	require.NoError(t, err)
+	assert.Nil(t, cert)
+
+	_, err = p.resolveCertificate(context.Background(), domain, "default")
+	require.NoError(t, err)
+
+	expected := map[string]pendingDomain{
+		"default/traefik.wtf,www.traefik.wtf": {domain: domain, tlsStore: "default"},
+	}
+	assert.Equal(t, expected, p.pendingDomains)
+
+	// The domains are postponed again while the instance is still not the leader.
+	p.resolvePendingDomains(context.Background())
+	assert.Equal(t, expected, p.pendingDomains)
+}
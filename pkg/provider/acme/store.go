@@ -2,14 +2,17 @@ package acme
 
 // StoredData represents the data managed by Store.
 type StoredData struct {
-	Account      *Account
-	Certificates []*CertAndStore
+	Account        *Account
+	BackupAccounts []*Account `json:",omitempty"`
+	Certificates   []*CertAndStore
 }
 
 // Store is a generic interface that represents a storage.
 type Store interface {
 	GetAccount(string) (*Account, error)
 	SaveAccount(string, *Account) error
+	GetBackupAccounts(string) ([]*Account, error)
+	SaveBackupAccounts(string, []*Account) error
 	GetCertificates(string) ([]*CertAndStore, error)
 	SaveCertificates(string, []*CertAndStore) error
 }
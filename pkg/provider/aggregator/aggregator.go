@@ -59,6 +59,10 @@ func NewProviderAggregator(conf static.Providers) ProviderAggregator {
 		p.quietAddProvider(conf.Ecs)
 	}
 
+	if conf.Azure != nil {
+		p.quietAddProvider(conf.Azure)
+	}
+
 	if conf.ConsulCatalog != nil {
 		p.quietAddProvider(conf.ConsulCatalog)
 	}
@@ -0,0 +1,302 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/job"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+// providerName is the name of the provider, in the logs and in the configuration sent to Traefik.
+const providerName = "azure"
+
+// DefaultTemplateRule The default template for the default rule.
+const DefaultTemplateRule = "Host(`{{ normalize .Name }}`)"
+
+// States of the container apps and container groups which are ready to serve.
+const (
+	provisioningStateSucceeded = "Succeeded"
+	containerGroupStateRunning = "Running"
+)
+
+var _ provider.Provider = (*Provider)(nil)
+
+// Provider holds configurations of the provider.
+type Provider struct {
+	Constraints      string `description:"Constraints is an expression that Traefik matches against the container's tags to determine whether to create any route for that container." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
+	ExposedByDefault bool   `description:"Expose containers by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
+	RefreshSeconds   int    `description:"Polling interval (in seconds)." json:"refreshSeconds,omitempty" toml:"refreshSeconds,omitempty" yaml:"refreshSeconds,omitempty" export:"true"`
+	DefaultRule      string `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	StrictLabels     bool   `description:"Ignore the containers with unknown labels in the traefik namespace." json:"strictLabels,omitempty" toml:"strictLabels,omitempty" yaml:"strictLabels,omitempty" export:"true"`
+
+	// Provider lookup parameters.
+	SubscriptionID  string   `description:"The ID of the Azure subscription whose containers are discovered." json:"subscriptionID,omitempty" toml:"subscriptionID,omitempty" yaml:"subscriptionID,omitempty" export:"true"`
+	ResourceGroups  []string `description:"The resource groups whose containers are discovered, defaults to all the resource groups of the subscription." json:"resourceGroups,omitempty" toml:"resourceGroups,omitempty" yaml:"resourceGroups,omitempty" export:"true"`
+	ContainerApps   bool     `description:"Discover the Container Apps." json:"containerApps,omitempty" toml:"containerApps,omitempty" yaml:"containerApps,omitempty" export:"true"`
+	ContainerGroups bool     `description:"Discover the container groups of Container Instances." json:"containerGroups,omitempty" toml:"containerGroups,omitempty" yaml:"containerGroups,omitempty" export:"true"`
+	TenantID        string   `description:"The ID of the Azure AD tenant of the service principal." json:"tenantID,omitempty" toml:"tenantID,omitempty" yaml:"tenantID,omitempty" export:"true"`
+	ClientID        string   `description:"The client ID of the service principal, or of the user-assigned managed identity." json:"clientID,omitempty" toml:"clientID,omitempty" yaml:"clientID,omitempty" export:"true"`
+	ClientSecret    string   `description:"The client secret of the service principal. The managed identity is used when it is empty." json:"clientSecret,omitempty" toml:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	Endpoint        string   `description:"The Azure Resource Manager endpoint." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty" export:"true"`
+	AuthorityHost   string   `description:"The Azure AD authority host of the service principal." json:"authorityHost,omitempty" toml:"authorityHost,omitempty" yaml:"authorityHost,omitempty" export:"true"`
+	defaultRuleTpl  *template.Template
+}
+
+// azureInstance is a container app, or a container group, exposed by Traefik.
+type azureInstance struct {
+	Name          string
+	ID            string
+	ResourceGroup string
+	Labels        map[string]string
+	ExtraConf     configuration
+
+	// address is the FQDN of the ingress of a container app, or the IP address of a container group.
+	address string
+	ports   []portMapping
+	// scheme is the default scheme of the servers of the instance, if any.
+	scheme string
+	// passHostHeader reports whether the Host header of the requests is forwarded by default.
+	// The container apps are routed by the Azure ingress with the Host header, which must be their FQDN.
+	passHostHeader bool
+	// ready reports whether the instance is provisioned and running.
+	ready bool
+}
+
+type portMapping struct {
+	port     int
+	protocol string
+}
+
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.ExposedByDefault = true
+	p.RefreshSeconds = 15
+	p.DefaultRule = DefaultTemplateRule
+	p.ContainerApps = true
+	p.ContainerGroups = true
+	p.Endpoint = "https://management.azure.com"
+	p.AuthorityHost = "https://login.microsoftonline.com"
+}
+
+// Init the provider.
+func (p *Provider) Init() error {
+	if p.SubscriptionID == "" {
+		return errors.New("the subscription ID is required")
+	}
+
+	if p.ClientSecret != "" && (p.TenantID == "" || p.ClientID == "") {
+		return errors.New("the tenant ID and the client ID of the service principal are required")
+	}
+
+	defaultRuleTpl, err := provider.MakeDefaultRuleTemplate(p.DefaultRule, nil)
+	if err != nil {
+		return fmt.Errorf("error while parsing default rule: %w", err)
+	}
+
+	p.defaultRuleTpl = defaultRuleTpl
+	return nil
+}
+
+// Provide configuration to traefik from Azure.
+func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	pool.GoCtx(func(routineCtx context.Context) {
+		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, providerName))
+		logger := log.FromContext(ctxLog)
+
+		client := p.createClient()
+
+		operation := func() error {
+			err := p.loadConfiguration(ctxLog, client, configurationChan)
+			if err != nil {
+				return fmt.Errorf("failed to get Azure configuration: %w", err)
+			}
+
+			ticker := time.NewTicker(time.Second * time.Duration(p.RefreshSeconds))
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+					err = p.loadConfiguration(ctxLog, client, configurationChan)
+					if err != nil {
+						return fmt.Errorf("failed to refresh Azure configuration: %w", err)
+					}
+
+				case <-routineCtx.Done():
+					return nil
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+			provider.ReportFailure(providerName, err)
+		}
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), routineCtx), notify)
+		if err != nil {
+			logger.Errorf("Cannot connect to Provider api %+v", err)
+		}
+	})
+
+	return nil
+}
+
+// createClient creates the client of the Azure Resource Manager API,
+// authenticated as the service principal when a client secret is configured, and with the managed identity otherwise.
+func (p *Provider) createClient() *armClient {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+
+	var tokens tokenSource
+	if p.ClientSecret != "" {
+		tokens = &servicePrincipalTokenSource{
+			httpClient:    httpClient,
+			authorityHost: strings.TrimSuffix(p.AuthorityHost, "/"),
+			tenantID:      p.TenantID,
+			clientID:      p.ClientID,
+			clientSecret:  p.ClientSecret,
+			scope:         strings.TrimSuffix(p.Endpoint, "/") + "/.default",
+		}
+	} else {
+		tokens = &managedIdentityTokenSource{
+			httpClient: httpClient,
+			clientID:   p.ClientID,
+			resource:   strings.TrimSuffix(p.Endpoint, "/") + "/",
+		}
+	}
+
+	return &armClient{
+		httpClient: httpClient,
+		endpoint:   strings.TrimSuffix(p.Endpoint, "/"),
+		tokens:     &tokenCache{source: tokens},
+	}
+}
+
+func (p *Provider) loadConfiguration(ctx context.Context, client *armClient, configurationChan chan<- dynamic.Message) error {
+	instances, err := p.listInstances(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	configurationChan <- dynamic.Message{
+		ProviderName:  providerName,
+		Configuration: p.buildConfiguration(ctx, instances),
+	}
+
+	return nil
+}
+
+// listInstances lists the container apps and the container groups of the subscription, or of the resource groups.
+func (p *Provider) listInstances(ctx context.Context, client *armClient) ([]azureInstance, error) {
+	scopes := []string{"/subscriptions/" + p.SubscriptionID}
+	if len(p.ResourceGroups) > 0 {
+		scopes = nil
+		for _, resourceGroup := range p.ResourceGroups {
+			scopes = append(scopes, "/subscriptions/"+p.SubscriptionID+"/resourceGroups/"+resourceGroup)
+		}
+	}
+
+	var instances []azureInstance
+	for _, scope := range scopes {
+		if p.ContainerApps {
+			apps, err := client.listContainerApps(ctx, scope)
+			if err != nil {
+				return nil, fmt.Errorf("unable to list the container apps of %s: %w", scope, err)
+			}
+
+			for _, app := range apps {
+				instances = append(instances, p.containerAppInstance(ctx, app))
+			}
+		}
+
+		if p.ContainerGroups {
+			groups, err := client.listContainerGroups(ctx, scope)
+			if err != nil {
+				return nil, fmt.Errorf("unable to list the container groups of %s: %w", scope, err)
+			}
+
+			for _, group := range groups {
+				instances = append(instances, p.containerGroupInstance(ctx, group))
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+func (p *Provider) containerAppInstance(ctx context.Context, app containerApp) azureInstance {
+	instance := azureInstance{
+		Name:          app.Name,
+		ID:            app.ID,
+		ResourceGroup: resourceGroup(app.ID),
+		Labels:        app.Tags,
+		scheme:        "https",
+		ready:         app.Properties.ProvisioningState == provisioningStateSucceeded,
+	}
+
+	if ingress := app.Properties.Configuration.Ingress; ingress != nil && ingress.FQDN != "" {
+		instance.address = ingress.FQDN
+		instance.ports = []portMapping{{port: 443, protocol: "tcp"}}
+	}
+
+	p.setExtraConf(ctx, &instance)
+
+	return instance
+}
+
+func (p *Provider) containerGroupInstance(ctx context.Context, group containerGroup) azureInstance {
+	instance := azureInstance{
+		Name:           group.Name,
+		ID:             group.ID,
+		ResourceGroup:  resourceGroup(group.ID),
+		Labels:         group.Tags,
+		passHostHeader: true,
+		ready:          group.Properties.ProvisioningState == provisioningStateSucceeded,
+	}
+
+	if view := group.Properties.InstanceView; view != nil && view.State != "" {
+		instance.ready = instance.ready && view.State == containerGroupStateRunning
+	}
+
+	if ipAddress := group.Properties.IPAddress; ipAddress != nil {
+		instance.address = ipAddress.IP
+		for _, port := range ipAddress.Ports {
+			instance.ports = append(instance.ports, portMapping{port: port.Port, protocol: strings.ToLower(port.Protocol)})
+		}
+	}
+
+	p.setExtraConf(ctx, &instance)
+
+	return instance
+}
+
+func (p *Provider) setExtraConf(ctx context.Context, instance *azureInstance) {
+	extraConf, err := p.getConfiguration(*instance)
+	if err != nil {
+		log.FromContext(ctx).Errorf("Skip instance %s: %v", instance.Name, err)
+		return
+	}
+
+	instance.ExtraConf = extraConf
+}
+
+// resourceGroup returns the resource group of an Azure resource ID.
+func resourceGroup(id string) string {
+	parts := strings.Split(id, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "resourceGroups") {
+			return parts[i+1]
+		}
+	}
+
+	return ""
+}
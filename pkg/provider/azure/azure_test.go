@@ -0,0 +1,176 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_listInstances(t *testing.T) {
+	var tokenRequests int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tenant/oauth2/v2.0/token", func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+
+		if req.FormValue("grant_type") != "client_credentials" || req.FormValue("client_id") != "client" || req.FormValue("client_secret") != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{"access_token": "token", "expires_in": 3599})
+	})
+
+	mux.HandleFunc("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/containerApps", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if req.URL.Query().Get("page") == "" {
+			_, _ = rw.Write([]byte(`{
+				"value": [{
+					"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/containerApps/app",
+					"name": "app",
+					"tags": {"traefik.enable": "true"},
+					"properties": {
+						"provisioningState": "Succeeded",
+						"configuration": {"ingress": {"fqdn": "app.example.azurecontainerapps.io", "external": true, "targetPort": 8080}}
+					}
+				}],
+				"nextLink": "http://` + req.Host + req.URL.Path + `?api-version=2022-03-01&page=2"
+			}`))
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{
+			"value": [{
+				"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/containerApps/failed",
+				"name": "failed",
+				"properties": {"provisioningState": "Failed"}
+			}]
+		}`))
+	})
+
+	mux.HandleFunc("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = rw.Write([]byte(`{
+			"value": [{
+				"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups/group",
+				"name": "group",
+				"properties": {
+					"provisioningState": "Succeeded",
+					"ipAddress": {"ip": "10.0.0.1", "ports": [{"port": 80, "protocol": "TCP"}]},
+					"instanceView": {"state": "Stopped"}
+				}
+			}]
+		}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	p := Provider{}
+	p.SetDefaults()
+	p.SubscriptionID = "sub"
+	p.ResourceGroups = []string{"rg"}
+	p.TenantID = "tenant"
+	p.ClientID = "client"
+	p.ClientSecret = "secret"
+	p.Endpoint = server.URL
+	p.AuthorityHost = server.URL
+
+	require.NoError(t, p.Init())
+
+	client := p.createClient()
+
+	instances, err := p.listInstances(context.Background(), client)
+	require.NoError(t, err)
+
+	expected := []azureInstance{
+		{
+			Name:          "app",
+			ID:            "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/containerApps/app",
+			ResourceGroup: "rg",
+			Labels:        map[string]string{"traefik.enable": "true"},
+			ExtraConf:     configuration{Enable: true},
+			address:       "app.example.azurecontainerapps.io",
+			ports:         []portMapping{{port: 443, protocol: "tcp"}},
+			scheme:        "https",
+			ready:         true,
+		},
+		{
+			Name:          "failed",
+			ID:            "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/containerApps/failed",
+			ResourceGroup: "rg",
+			ExtraConf:     configuration{Enable: true},
+			scheme:        "https",
+		},
+		{
+			Name:           "group",
+			ID:             "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups/group",
+			ResourceGroup:  "rg",
+			ExtraConf:      configuration{Enable: true},
+			address:        "10.0.0.1",
+			ports:          []portMapping{{port: 80, protocol: "tcp"}},
+			passHostHeader: true,
+		},
+	}
+	assert.Equal(t, expected, instances)
+
+	// The token is reused until it expires.
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests))
+}
+
+func TestProvider_Init(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		provider Provider
+		expErr   bool
+	}{
+		{
+			desc:     "managed identity",
+			provider: Provider{SubscriptionID: "sub"},
+		},
+		{
+			desc:     "service principal",
+			provider: Provider{SubscriptionID: "sub", TenantID: "tenant", ClientID: "client", ClientSecret: "secret"},
+		},
+		{
+			desc:     "missing subscription ID",
+			provider: Provider{},
+			expErr:   true,
+		},
+		{
+			desc:     "service principal without tenant ID",
+			provider: Provider{SubscriptionID: "sub", ClientID: "client", ClientSecret: "secret"},
+			expErr:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.provider.Init()
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// API versions of the Azure Resource Manager resources.
+const (
+	containerAppsAPIVersion   = "2022-03-01"
+	containerGroupsAPIVersion = "2021-10-01"
+)
+
+// managedIdentityEndpoint is the endpoint of the Azure Instance Metadata Service,
+// used to get the tokens of the managed identity when the IDENTITY_ENDPOINT environment variable is not set.
+const managedIdentityEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// tokenRefreshMargin is the duration before the expiration of a token from which it is renewed.
+const tokenRefreshMargin = 5 * time.Minute
+
+type containerApp struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Tags       map[string]string `json:"tags"`
+	Properties struct {
+		ProvisioningState string `json:"provisioningState"`
+		Configuration     struct {
+			Ingress *struct {
+				FQDN       string `json:"fqdn"`
+				External   bool   `json:"external"`
+				TargetPort int    `json:"targetPort"`
+			} `json:"ingress"`
+		} `json:"configuration"`
+	} `json:"properties"`
+}
+
+type containerGroup struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Tags       map[string]string `json:"tags"`
+	Properties struct {
+		ProvisioningState string `json:"provisioningState"`
+		IPAddress         *struct {
+			IP    string `json:"ip"`
+			Ports []struct {
+				Port     int    `json:"port"`
+				Protocol string `json:"protocol"`
+			} `json:"ports"`
+		} `json:"ipAddress"`
+		InstanceView *struct {
+			State string `json:"state"`
+		} `json:"instanceView"`
+	} `json:"properties"`
+}
+
+// armClient is a client of the Azure Resource Manager API.
+type armClient struct {
+	httpClient *http.Client
+	endpoint   string
+	tokens     *tokenCache
+}
+
+// listContainerApps lists the container apps of the given scope, i.e. a subscription or a resource group.
+func (c *armClient) listContainerApps(ctx context.Context, scope string) ([]containerApp, error) {
+	var apps []containerApp
+
+	nextLink := c.endpoint + scope + "/providers/Microsoft.App/containerApps?api-version=" + containerAppsAPIVersion
+	for nextLink != "" {
+		var page struct {
+			Value    []containerApp `json:"value"`
+			NextLink string         `json:"nextLink"`
+		}
+
+		if err := c.get(ctx, nextLink, &page); err != nil {
+			return nil, err
+		}
+
+		apps = append(apps, page.Value...)
+		nextLink = page.NextLink
+	}
+
+	return apps, nil
+}
+
+// listContainerGroups lists the container groups of the given scope, i.e. a subscription or a resource group.
+func (c *armClient) listContainerGroups(ctx context.Context, scope string) ([]containerGroup, error) {
+	var groups []containerGroup
+
+	nextLink := c.endpoint + scope + "/providers/Microsoft.ContainerInstance/containerGroups?api-version=" + containerGroupsAPIVersion
+	for nextLink != "" {
+		var page struct {
+			Value    []containerGroup `json:"value"`
+			NextLink string           `json:"nextLink"`
+		}
+
+		if err := c.get(ctx, nextLink, &page); err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, page.Value...)
+		nextLink = page.NextLink
+	}
+
+	return groups, nil
+}
+
+func (c *armClient) get(ctx context.Context, rawURL string, result interface{}) error {
+	token, err := c.tokens.token(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get an access token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return doJSON(c.httpClient, req, result)
+}
+
+// tokenSource gets the access tokens of the Azure Resource Manager API.
+type tokenSource interface {
+	accessToken(ctx context.Context) (accessToken, error)
+}
+
+// accessToken is the response of the token endpoints.
+type accessToken struct {
+	AccessToken string `json:"access_token"`
+	// ExpiresIn is a number in the responses of Azure AD, and a string in the ones of the managed identity endpoints.
+	ExpiresIn json.Number `json:"expires_in"`
+}
+
+// expiresAt returns the expiration time of the token, from the given time.
+func (t accessToken) expiresAt(now time.Time) time.Time {
+	seconds, err := strconv.ParseInt(t.ExpiresIn.String(), 10, 64)
+	if err != nil {
+		// Tokens are valid for at least an hour, the token is renewed on next use.
+		return now
+	}
+
+	return now.Add(time.Duration(seconds) * time.Second)
+}
+
+// tokenCache reuses the tokens of its source until they are about to expire.
+type tokenCache struct {
+	source tokenSource
+
+	mu        sync.Mutex
+	current   string
+	expiresAt time.Time
+}
+
+func (c *tokenCache) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current != "" && time.Now().Add(tokenRefreshMargin).Before(c.expiresAt) {
+		return c.current, nil
+	}
+
+	token, err := c.source.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.current = token.AccessToken
+	c.expiresAt = token.expiresAt(time.Now())
+
+	return c.current, nil
+}
+
+// servicePrincipalTokenSource gets the tokens of a service principal with the client credentials flow of Azure AD.
+type servicePrincipalTokenSource struct {
+	httpClient    *http.Client
+	authorityHost string
+	tenantID      string
+	clientID      string
+	clientSecret  string
+	scope         string
+}
+
+func (s *servicePrincipalTokenSource) accessToken(ctx context.Context) (accessToken, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.clientID},
+		"client_secret": {s.clientSecret},
+		"scope":         {s.scope},
+	}
+
+	tokenURL := s.authorityHost + "/" + url.PathEscape(s.tenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return accessToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token accessToken
+	if err := doJSON(s.httpClient, req, &token); err != nil {
+		return accessToken{}, err
+	}
+
+	if token.AccessToken == "" {
+		return accessToken{}, errors.New("no access token in the response")
+	}
+
+	return token, nil
+}
+
+// managedIdentityTokenSource gets the tokens of the managed identity of the machine or of the container,
+// from the endpoint of the IDENTITY_ENDPOINT environment variable when it is set (e.g. in Container Apps),
+// and from the Azure Instance Metadata Service otherwise.
+type managedIdentityTokenSource struct {
+	httpClient *http.Client
+	clientID   string
+	resource   string
+}
+
+func (m *managedIdentityTokenSource) accessToken(ctx context.Context) (accessToken, error) {
+	query := url.Values{"resource": {m.resource}}
+	if m.clientID != "" {
+		query.Set("client_id", m.clientID)
+	}
+
+	endpoint := managedIdentityEndpoint
+	header := http.Header{"Metadata": {"true"}}
+	query.Set("api-version", "2018-02-01")
+
+	if identityEndpoint := os.Getenv("IDENTITY_ENDPOINT"); identityEndpoint != "" {
+		endpoint = identityEndpoint
+		header = http.Header{"X-Identity-Header": {os.Getenv("IDENTITY_HEADER")}}
+		query.Set("api-version", "2019-08-01")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return accessToken{}, err
+	}
+	req.Header = header
+
+	var token accessToken
+	if err := doJSON(m.httpClient, req, &token); err != nil {
+		return accessToken{}, err
+	}
+
+	if token.AccessToken == "" {
+		return accessToken{}, errors.New("no access token in the response")
+	}
+
+	return token, nil
+}
+
+// doJSON sends the request, and decodes the JSON body of the response into result.
+func doJSON(httpClient *http.Client, req *http.Request, result interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
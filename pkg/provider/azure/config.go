@@ -0,0 +1,312 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/provider/constraints"
+)
+
+func (p *Provider) buildConfiguration(ctx context.Context, instances []azureInstance) *dynamic.Configuration {
+	configurations := make(map[string]*dynamic.Configuration)
+	var labelErrors []dynamic.LabelError
+
+	for _, instance := range instances {
+		instanceName := getServiceName(instance) + "-" + provider.Normalize(instance.ResourceGroup)
+		ctxContainer := log.With(ctx, log.Str("azure-instance", instanceName))
+
+		if !p.filterInstance(ctxContainer, instance) {
+			continue
+		}
+
+		logger := log.FromContext(ctxContainer)
+
+		confFromLabel, err := p.decodeConfiguration(instance.Labels)
+		if err != nil {
+			logger.Error(err)
+			labelErrors = append(labelErrors, provider.NewLabelErrors(instanceName, err)...)
+			continue
+		}
+
+		var tcpOrUDP bool
+		if len(confFromLabel.TCP.Routers) > 0 || len(confFromLabel.TCP.Services) > 0 {
+			tcpOrUDP = true
+
+			err := p.buildTCPServiceConfiguration(instance, confFromLabel.TCP)
+			if err != nil {
+				logger.Error(err)
+				continue
+			}
+			provider.BuildTCPRouterConfiguration(ctxContainer, confFromLabel.TCP)
+		}
+
+		if len(confFromLabel.UDP.Routers) > 0 || len(confFromLabel.UDP.Services) > 0 {
+			tcpOrUDP = true
+
+			err := p.buildUDPServiceConfiguration(instance, confFromLabel.UDP)
+			if err != nil {
+				logger.Error(err)
+				continue
+			}
+			provider.BuildUDPRouterConfiguration(ctxContainer, confFromLabel.UDP)
+		}
+
+		if tcpOrUDP && len(confFromLabel.HTTP.Routers) == 0 &&
+			len(confFromLabel.HTTP.Middlewares) == 0 &&
+			len(confFromLabel.HTTP.Services) == 0 {
+			configurations[instanceName] = confFromLabel
+			continue
+		}
+
+		err = p.buildServiceConfiguration(ctxContainer, instance, confFromLabel.HTTP)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		serviceName := getServiceName(instance)
+
+		model := struct {
+			Name          string
+			ResourceGroup string
+			Labels        map[string]string
+		}{
+			Name:          serviceName,
+			ResourceGroup: instance.ResourceGroup,
+			Labels:        instance.Labels,
+		}
+
+		provider.BuildRouterConfiguration(ctx, confFromLabel.HTTP, serviceName, p.defaultRuleTpl, model)
+
+		configurations[instanceName] = confFromLabel
+	}
+
+	configuration := provider.Merge(ctx, configurations)
+	configuration.LabelErrors = labelErrors
+
+	return configuration
+}
+
+func (p *Provider) buildTCPServiceConfiguration(instance azureInstance, configuration *dynamic.TCPConfiguration) error {
+	serviceName := getServiceName(instance)
+
+	if len(configuration.Services) == 0 {
+		configuration.Services = make(map[string]*dynamic.TCPService)
+		lb := &dynamic.TCPServersLoadBalancer{}
+		lb.SetDefaults()
+		configuration.Services[serviceName] = &dynamic.TCPService{
+			LoadBalancer: lb,
+		}
+	}
+
+	for name, service := range configuration.Services {
+		err := p.addServerTCP(instance, service.LoadBalancer)
+		if err != nil {
+			return fmt.Errorf("service %q error: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) buildUDPServiceConfiguration(instance azureInstance, configuration *dynamic.UDPConfiguration) error {
+	serviceName := getServiceName(instance)
+
+	if len(configuration.Services) == 0 {
+		configuration.Services = make(map[string]*dynamic.UDPService)
+		lb := &dynamic.UDPServersLoadBalancer{}
+		configuration.Services[serviceName] = &dynamic.UDPService{
+			LoadBalancer: lb,
+		}
+	}
+
+	for name, service := range configuration.Services {
+		err := p.addServerUDP(instance, service.LoadBalancer)
+		if err != nil {
+			return fmt.Errorf("service %q error: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) buildServiceConfiguration(_ context.Context, instance azureInstance, configuration *dynamic.HTTPConfiguration) error {
+	serviceName := getServiceName(instance)
+
+	if len(configuration.Services) == 0 {
+		configuration.Services = make(map[string]*dynamic.Service)
+		lb := &dynamic.ServersLoadBalancer{}
+		lb.SetDefaults()
+		passHostHeader := instance.passHostHeader
+		lb.PassHostHeader = &passHostHeader
+		configuration.Services[serviceName] = &dynamic.Service{
+			LoadBalancer: lb,
+		}
+	}
+
+	for name, service := range configuration.Services {
+		err := p.addServer(instance, service.LoadBalancer)
+		if err != nil {
+			return fmt.Errorf("service %q error: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) filterInstance(ctx context.Context, instance azureInstance) bool {
+	logger := log.FromContext(ctx)
+
+	if !instance.ready {
+		logger.Debugf("Filtering azure instance which is not ready %s (%s)", instance.Name, instance.ID)
+		return false
+	}
+
+	if len(instance.address) == 0 {
+		logger.Debugf("Filtering azure instance without an address %s (%s)", instance.Name, instance.ID)
+		return false
+	}
+
+	if !instance.ExtraConf.Enable {
+		logger.Debugf("Filtering disabled azure instance %s (%s)", instance.Name, instance.ID)
+		return false
+	}
+
+	matches, err := constraints.MatchLabels(instance.Labels, p.Constraints)
+	if err != nil {
+		logger.Errorf("Error matching constraints expression: %v", err)
+		return false
+	}
+	if !matches {
+		logger.Debugf("Container pruned by constraint expression: %q", p.Constraints)
+		return false
+	}
+
+	return true
+}
+
+func (p *Provider) addServerTCP(instance azureInstance, loadBalancer *dynamic.TCPServersLoadBalancer) error {
+	if loadBalancer == nil {
+		return errors.New("load-balancer is not defined")
+	}
+
+	var serverPort string
+	if len(loadBalancer.Servers) > 0 {
+		serverPort = loadBalancer.Servers[0].Port
+		loadBalancer.Servers[0].Port = ""
+	}
+
+	port := getPort(instance, serverPort, "tcp")
+
+	if len(loadBalancer.Servers) == 0 {
+		server := dynamic.TCPServer{}
+
+		loadBalancer.Servers = []dynamic.TCPServer{server}
+	}
+
+	if port == "" {
+		return errors.New("port is missing")
+	}
+
+	loadBalancer.Servers[0].Address = net.JoinHostPort(instance.address, port)
+	return nil
+}
+
+func (p *Provider) addServerUDP(instance azureInstance, loadBalancer *dynamic.UDPServersLoadBalancer) error {
+	if loadBalancer == nil {
+		return errors.New("load-balancer is not defined")
+	}
+
+	var serverPort string
+	if len(loadBalancer.Servers) > 0 {
+		serverPort = loadBalancer.Servers[0].Port
+		loadBalancer.Servers[0].Port = ""
+	}
+
+	port := getPort(instance, serverPort, "udp")
+
+	if len(loadBalancer.Servers) == 0 {
+		server := dynamic.UDPServer{}
+
+		loadBalancer.Servers = []dynamic.UDPServer{server}
+	}
+
+	if port == "" {
+		return errors.New("port is missing")
+	}
+
+	loadBalancer.Servers[0].Address = net.JoinHostPort(instance.address, port)
+	return nil
+}
+
+func (p *Provider) addServer(instance azureInstance, loadBalancer *dynamic.ServersLoadBalancer) error {
+	if loadBalancer == nil {
+		return errors.New("load-balancer is not defined")
+	}
+
+	var serverPort string
+	if len(loadBalancer.Servers) > 0 {
+		serverPort = loadBalancer.Servers[0].Port
+		loadBalancer.Servers[0].Port = ""
+	}
+
+	port := getPort(instance, serverPort, "tcp")
+
+	if len(loadBalancer.Servers) == 0 {
+		server := dynamic.Server{}
+		server.SetDefaults()
+
+		loadBalancer.Servers = []dynamic.Server{server}
+	}
+
+	if port == "" {
+		return errors.New("port is missing")
+	}
+
+	scheme := loadBalancer.Servers[0].Scheme
+	// The default scheme of the instance, e.g. HTTPS for the ingress of the container apps,
+	// is used unless the port or the scheme of the server are configured.
+	if serverPort == "" && scheme == "http" && instance.scheme != "" {
+		scheme = instance.scheme
+	}
+
+	loadBalancer.Servers[0].URL = fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(instance.address, port))
+	loadBalancer.Servers[0].Scheme = ""
+
+	return nil
+}
+
+// getPort returns the port of the server, i.e. the given one if any,
+// and the lowest port of the instance with the given protocol otherwise.
+func getPort(instance azureInstance, serverPort, protocol string) string {
+	if len(serverPort) > 0 {
+		return serverPort
+	}
+
+	var min int
+	for _, port := range instance.ports {
+		if port.protocol != protocol {
+			continue
+		}
+
+		if min == 0 || port.port < min {
+			min = port.port
+		}
+	}
+
+	if min == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(min)
+}
+
+func getServiceName(instance azureInstance) string {
+	return provider.Normalize(instance.Name)
+}
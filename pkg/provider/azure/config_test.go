@@ -0,0 +1,273 @@
+package azure
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func Bool(v bool) *bool { return &v }
+
+func containerAppInstance(name, fqdn string, labels map[string]string) azureInstance {
+	return azureInstance{
+		Name:          name,
+		ID:            "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/containerApps/" + name,
+		ResourceGroup: "rg",
+		Labels:        labels,
+		address:       fqdn,
+		ports:         []portMapping{{port: 443, protocol: "tcp"}},
+		scheme:        "https",
+		ready:         true,
+	}
+}
+
+func containerGroupInstance(name, ip string, labels map[string]string, ports ...portMapping) azureInstance {
+	return azureInstance{
+		Name:           name,
+		ID:             "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups/" + name,
+		ResourceGroup:  "rg",
+		Labels:         labels,
+		address:        ip,
+		ports:          ports,
+		passHostHeader: true,
+		ready:          true,
+	}
+}
+
+func Test_buildConfiguration(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		instances   []azureInstance
+		constraints string
+		expected    *dynamic.Configuration
+	}{
+		{
+			desc:      "container app",
+			instances: []azureInstance{containerAppInstance("app", "app.example.azurecontainerapps.io", nil)},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"app": {
+							Service: "app",
+							Rule:    "Host(`app.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"app": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "https://app.example.azurecontainerapps.io:443",
+									},
+								},
+								PassHostHeader: Bool(false),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "container group with the lowest TCP port",
+			instances: []azureInstance{
+				containerGroupInstance("group", "10.0.0.1", nil,
+					portMapping{port: 53, protocol: "udp"},
+					portMapping{port: 8080, protocol: "tcp"},
+					portMapping{port: 80, protocol: "tcp"},
+				),
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"group": {
+							Service: "group",
+							Rule:    "Host(`group.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"group": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.0.0.1:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "container group with a port label",
+			instances: []azureInstance{
+				containerGroupInstance("group", "10.0.0.1", map[string]string{
+					"traefik.http.services.service.loadbalancer.server.port": "8080",
+				},
+					portMapping{port: 80, protocol: "tcp"},
+					portMapping{port: 8080, protocol: "tcp"},
+				),
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"group": {
+							Service: "service",
+							Rule:    "Host(`group.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"service": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.0.0.1:8080",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "container group with a UDP router",
+			instances: []azureInstance{
+				containerGroupInstance("group", "10.0.0.1", map[string]string{
+					"traefik.udp.routers.dns.entrypoints": "dns",
+				},
+					portMapping{port: 80, protocol: "tcp"},
+					portMapping{port: 53, protocol: "udp"},
+				),
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers: map[string]*dynamic.UDPRouter{
+						"dns": {
+							EntryPoints: []string{"dns"},
+							Service:     "group",
+						},
+					},
+					Services: map[string]*dynamic.UDPService{
+						"group": {
+							LoadBalancer: &dynamic.UDPServersLoadBalancer{
+								Servers: []dynamic.UDPServer{
+									{
+										Address: "10.0.0.1:53",
+									},
+								},
+							},
+						},
+					},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
+		{
+			desc: "disabled, not ready, and constrained instances",
+			instances: []azureInstance{
+				containerAppInstance("disabled", "disabled.example.azurecontainerapps.io", map[string]string{
+					"traefik.enable": "false",
+				}),
+				func() azureInstance {
+					instance := containerAppInstance("provisioning", "provisioning.example.azurecontainerapps.io", nil)
+					instance.ready = false
+					return instance
+				}(),
+				containerAppInstance("internal", "", nil),
+				containerAppInstance("other", "other.example.azurecontainerapps.io", map[string]string{
+					"env": "staging",
+				}),
+			},
+			constraints: "!Label(`env`, `staging`)",
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{
+				SubscriptionID:   "sub",
+				ExposedByDefault: true,
+				DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
+				Constraints:      test.constraints,
+			}
+
+			err := p.Init()
+			require.NoError(t, err)
+
+			for i := 0; i < len(test.instances); i++ {
+				var err error
+				test.instances[i].ExtraConf, err = p.getConfiguration(test.instances[i])
+				require.NoError(t, err)
+			}
+
+			configuration := p.buildConfiguration(context.Background(), test.instances)
+
+			assert.Equal(t, test.expected, configuration)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package azure
+
+import (
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/label"
+)
+
+// configuration Contains information from the labels that are globals (not related to the dynamic configuration) or specific to the provider.
+type configuration struct {
+	Enable bool
+}
+
+func (p *Provider) getConfiguration(instance azureInstance) (configuration, error) {
+	conf := configuration{
+		Enable: p.ExposedByDefault,
+	}
+
+	err := label.Decode(instance.Labels, &conf, "traefik.azure.", "traefik.enable")
+	if err != nil {
+		return configuration{}, err
+	}
+
+	return conf, nil
+}
+
+// decodeConfiguration converts the tags of a container app or of a container group to a configuration.
+// In strict mode, the unknown tags of the traefik namespace are rejected.
+func (p *Provider) decodeConfiguration(labels map[string]string) (*dynamic.Configuration, error) {
+	if !p.StrictLabels {
+		return label.DecodeConfiguration(labels)
+	}
+
+	return label.DecodeConfigurationStrict(labels, "traefik.azure.", "traefik.enable")
+}
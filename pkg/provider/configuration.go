@@ -3,6 +3,7 @@ package provider
 import (
 	"bytes"
 	"context"
+	"errors"
 	"reflect"
 	"sort"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/label"
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
@@ -128,50 +130,65 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 		logger.WithField(log.ServiceName, serviceName).
 			Errorf("Service defined multiple times with different configurations in %v", services[serviceName])
 		delete(configuration.HTTP.Services, serviceName)
+		configuration.Conflicts = append(configuration.Conflicts, dynamic.Conflict{Kind: "service", Name: serviceName, Sources: services[serviceName]})
 	}
 
 	for routerName := range routersToDelete {
 		logger.WithField(log.RouterName, routerName).
 			Errorf("Router defined multiple times with different configurations in %v", routers[routerName])
 		delete(configuration.HTTP.Routers, routerName)
+		configuration.Conflicts = append(configuration.Conflicts, dynamic.Conflict{Kind: "router", Name: routerName, Sources: routers[routerName]})
 	}
 
 	for serviceName := range servicesTCPToDelete {
 		logger.WithField(log.ServiceName, serviceName).
 			Errorf("Service TCP defined multiple times with different configurations in %v", servicesTCP[serviceName])
 		delete(configuration.TCP.Services, serviceName)
+		configuration.Conflicts = append(configuration.Conflicts, dynamic.Conflict{Kind: "tcpService", Name: serviceName, Sources: servicesTCP[serviceName]})
 	}
 
 	for routerName := range routersTCPToDelete {
 		logger.WithField(log.RouterName, routerName).
 			Errorf("Router TCP defined multiple times with different configurations in %v", routersTCP[routerName])
 		delete(configuration.TCP.Routers, routerName)
+		configuration.Conflicts = append(configuration.Conflicts, dynamic.Conflict{Kind: "tcpRouter", Name: routerName, Sources: routersTCP[routerName]})
 	}
 
 	for serviceName := range servicesUDPToDelete {
 		logger.WithField(log.ServiceName, serviceName).
 			Errorf("UDP service defined multiple times with different configurations in %v", servicesUDP[serviceName])
 		delete(configuration.UDP.Services, serviceName)
+		configuration.Conflicts = append(configuration.Conflicts, dynamic.Conflict{Kind: "udpService", Name: serviceName, Sources: servicesUDP[serviceName]})
 	}
 
 	for routerName := range routersUDPToDelete {
 		logger.WithField(log.RouterName, routerName).
 			Errorf("UDP router defined multiple times with different configurations in %v", routersUDP[routerName])
 		delete(configuration.UDP.Routers, routerName)
+		configuration.Conflicts = append(configuration.Conflicts, dynamic.Conflict{Kind: "udpRouter", Name: routerName, Sources: routersUDP[routerName]})
 	}
 
 	for middlewareName := range middlewaresToDelete {
 		logger.WithField(log.MiddlewareName, middlewareName).
 			Errorf("Middleware defined multiple times with different configurations in %v", middlewares[middlewareName])
 		delete(configuration.HTTP.Middlewares, middlewareName)
+		configuration.Conflicts = append(configuration.Conflicts, dynamic.Conflict{Kind: "middleware", Name: middlewareName, Sources: middlewares[middlewareName]})
 	}
 
 	for middlewareName := range middlewaresTCPToDelete {
 		logger.WithField(log.MiddlewareName, middlewareName).
 			Errorf("TCP Middleware defined multiple times with different configurations in %v", middlewaresTCP[middlewareName])
 		delete(configuration.TCP.Middlewares, middlewareName)
+		configuration.Conflicts = append(configuration.Conflicts, dynamic.Conflict{Kind: "tcpMiddleware", Name: middlewareName, Sources: middlewaresTCP[middlewareName]})
 	}
 
+	sort.Slice(configuration.Conflicts, func(i, j int) bool {
+		if configuration.Conflicts[i].Kind == configuration.Conflicts[j].Kind {
+			return configuration.Conflicts[i].Name < configuration.Conflicts[j].Name
+		}
+		return configuration.Conflicts[i].Kind < configuration.Conflicts[j].Kind
+	})
+
 	return configuration
 }
 
@@ -300,6 +317,22 @@ func AddMiddleware(configuration *dynamic.HTTPConfiguration, middlewareName stri
 	return reflect.DeepEqual(configuration.Middlewares[middlewareName], middleware)
 }
 
+// NewLabelErrors returns the errors of the labels of the given source (e.g. a container name),
+// from the error returned while decoding them.
+func NewLabelErrors(source string, err error) []dynamic.LabelError {
+	var decodeErr *label.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return []dynamic.LabelError{{Source: source, Reason: err.Error()}}
+	}
+
+	labelErrors := make([]dynamic.LabelError, 0, len(decodeErr.Errors))
+	for _, labelErr := range decodeErr.Errors {
+		labelErrors = append(labelErrors, dynamic.LabelError{Source: source, Label: labelErr.Label, Reason: labelErr.Reason})
+	}
+
+	return labelErrors
+}
+
 // MakeDefaultRuleTemplate Creates the default rule template.
 func MakeDefaultRuleTemplate(defaultRule string, funcMap template.FuncMap) (*template.Template, error) {
 	defaultFuncMap := sprig.TxtFuncMap()
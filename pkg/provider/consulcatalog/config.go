@@ -8,7 +8,6 @@ import (
 
 	"github.com/hashicorp/consul/api"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
-	"github.com/traefik/traefik/v2/pkg/config/label"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/constraints"
@@ -16,6 +15,7 @@ import (
 
 func (p *Provider) buildConfiguration(ctx context.Context, items []itemData) *dynamic.Configuration {
 	configurations := make(map[string]*dynamic.Configuration)
+	var labelErrors []dynamic.LabelError
 
 	for _, item := range items {
 		svcName := provider.Normalize(item.Node + "-" + item.Name + "-" + item.ID)
@@ -27,9 +27,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, items []itemData) *dy
 
 		logger := log.FromContext(ctxSvc)
 
-		confFromLabel, err := label.DecodeConfiguration(item.Labels)
+		confFromLabel, err := p.decodeConfiguration(item.Labels)
 		if err != nil {
 			logger.Error(err)
+			labelErrors = append(labelErrors, provider.NewLabelErrors(svcName, err)...)
 			continue
 		}
 
@@ -82,7 +83,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, items []itemData) *dy
 		configurations[svcName] = confFromLabel
 	}
 
-	return provider.Merge(ctx, configurations)
+	configuration := provider.Merge(ctx, configurations)
+	configuration.LabelErrors = labelErrors
+
+	return configuration
 }
 
 func (p *Provider) keepContainer(ctx context.Context, item itemData) bool {
@@ -857,6 +857,9 @@ func Test_buildConfiguration(t *testing.T) {
 					Middlewares: map[string]*dynamic.Middleware{},
 					Services:    map[string]*dynamic.Service{},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "service", Name: "Service1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -913,6 +916,9 @@ func Test_buildConfiguration(t *testing.T) {
 					Middlewares: map[string]*dynamic.Middleware{},
 					Services:    map[string]*dynamic.Service{},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "service", Name: "Service1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -1150,6 +1156,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "middleware", Name: "Middleware1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -1225,6 +1234,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "middleware", Name: "Middleware1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -1281,6 +1293,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -1351,6 +1366,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -3,6 +3,7 @@ package consulcatalog
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"text/template"
@@ -26,15 +27,16 @@ const DefaultTemplateRule = "Host(`{{ normalize .Name }}`)"
 var _ provider.Provider = (*Provider)(nil)
 
 type itemData struct {
-	ID        string
-	Node      string
-	Name      string
-	Address   string
-	Port      string
-	Status    string
-	Labels    map[string]string
-	Tags      []string
-	ExtraConf configuration
+	ID         string
+	Node       string
+	Datacenter string
+	Name       string
+	Address    string
+	Port       string
+	Status     string
+	Labels     map[string]string
+	Tags       []string
+	ExtraConf  configuration
 }
 
 // Provider holds configurations of the provider.
@@ -48,6 +50,9 @@ type Provider struct {
 	Cache             bool            `description:"Use local agent caching for catalog reads." json:"cache,omitempty" toml:"cache,omitempty" yaml:"cache,omitempty" export:"true"`
 	ExposedByDefault  bool            `description:"Expose containers by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
 	DefaultRule       string          `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	Datacenters       []string        `description:"Datacenters whose services are discovered, named <name>-<datacenter>. Defaults to the datacenter of the endpoint." json:"datacenters,omitempty" toml:"datacenters,omitempty" yaml:"datacenters,omitempty" export:"true"`
+	PreparedQueries   bool            `description:"Resolve the instances of the services by executing the prepared queries named after them, e.g. to fail over to other datacenters." json:"preparedQueries,omitempty" toml:"preparedQueries,omitempty" yaml:"preparedQueries,omitempty" export:"true"`
+	StrictLabels      bool            `description:"Ignore the services with unknown labels in the traefik namespace." json:"strictLabels,omitempty" toml:"strictLabels,omitempty" yaml:"strictLabels,omitempty" export:"true"`
 
 	client         *api.Client
 	defaultRuleTpl *template.Template
@@ -58,6 +63,7 @@ type EndpointConfig struct {
 	Address          string                  `description:"The address of the Consul server" json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
 	Scheme           string                  `description:"The URI scheme for the Consul server" json:"scheme,omitempty" toml:"scheme,omitempty" yaml:"scheme,omitempty"`
 	DataCenter       string                  `description:"Data center to use. If not provided, the default agent data center is used" json:"datacenter,omitempty" toml:"datacenter,omitempty" yaml:"datacenter,omitempty"`
+	Partition        string                  `description:"Admin partition to use (Consul Enterprise). If not provided, the partition of the token is used" json:"partition,omitempty" toml:"partition,omitempty" yaml:"partition,omitempty"`
 	Token            string                  `description:"Token is used to provide a per-request ACL token which overrides the agent's default token" json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
 	TLS              *types.ClientTLS        `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
 	HTTPAuth         *EndpointHTTPAuthConfig `description:"Auth info to use for http access" json:"httpAuth,omitempty" toml:"httpAuth,omitempty" yaml:"httpAuth,omitempty" export:"true"`
@@ -137,6 +143,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 		notify := func(err error, time time.Duration) {
 			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+			provider.ReportFailure("consulcatalog", err)
 		}
 
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
@@ -163,38 +170,53 @@ func (p *Provider) loadConfiguration(ctx context.Context, configurationChan chan
 }
 
 func (p *Provider) getConsulServicesData(ctx context.Context) ([]itemData, error) {
-	consulServiceNames, err := p.fetchServices(ctx)
+	datacenters := p.Datacenters
+	if len(datacenters) == 0 {
+		// The datacenter of the endpoint.
+		datacenters = []string{""}
+	}
+
+	var data []itemData
+	for _, datacenter := range datacenters {
+		ctxDC := ctx
+		if datacenter != "" {
+			ctxDC = log.With(ctx, log.Str("datacenter", datacenter))
+		}
+
+		dcData, err := p.getDatacenterServicesData(ctxDC, datacenter)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, dcData...)
+	}
+
+	return data, nil
+}
+
+// getDatacenterServicesData returns the instances of the services of the datacenter,
+// whose names are qualified with the datacenter unless it is the one of the endpoint.
+func (p *Provider) getDatacenterServicesData(ctx context.Context, datacenter string) ([]itemData, error) {
+	consulServiceNames, err := p.fetchServices(ctx, datacenter)
 	if err != nil {
 		return nil, err
 	}
 
 	var data []itemData
 	for _, name := range consulServiceNames {
-		consulServices, statuses, err := p.fetchService(ctx, name)
+		var items []itemData
+		if p.PreparedQueries {
+			items, err = p.fetchPreparedQuery(ctx, name, datacenter)
+		} else {
+			items, err = p.fetchService(ctx, name, datacenter)
+		}
 		if err != nil {
 			return nil, err
 		}
 
-		for _, consulService := range consulServices {
-			address := consulService.ServiceAddress
-			if address == "" {
-				address = consulService.Address
-			}
-
-			status, exists := statuses[consulService.ID+consulService.ServiceID]
-			if !exists {
-				status = api.HealthAny
-			}
-
-			item := itemData{
-				ID:      consulService.ServiceID,
-				Node:    consulService.Node,
-				Name:    consulService.ServiceName,
-				Address: address,
-				Port:    strconv.Itoa(consulService.ServicePort),
-				Labels:  tagsToNeutralLabels(consulService.ServiceTags, p.Prefix),
-				Tags:    consulService.ServiceTags,
-				Status:  status,
+		for _, item := range items {
+			if datacenter != "" {
+				item.Name += "-" + datacenter
 			}
 
 			extraConf, err := p.getConfiguration(item)
@@ -210,23 +232,27 @@ func (p *Provider) getConsulServicesData(ctx context.Context) ([]itemData, error
 	return data, nil
 }
 
-func (p *Provider) fetchService(ctx context.Context, name string) ([]*api.CatalogService, map[string]string, error) {
+func (p *Provider) queryOptions(ctx context.Context, datacenter string) *api.QueryOptions {
+	opts := &api.QueryOptions{AllowStale: p.Stale, RequireConsistent: p.RequireConsistent, UseCache: p.Cache, Datacenter: datacenter}
+	return opts.WithContext(ctx)
+}
+
+func (p *Provider) fetchService(ctx context.Context, name, datacenter string) ([]itemData, error) {
 	var tagFilter string
 	if !p.ExposedByDefault {
 		tagFilter = p.Prefix + ".enable=true"
 	}
 
-	opts := &api.QueryOptions{AllowStale: p.Stale, RequireConsistent: p.RequireConsistent, UseCache: p.Cache}
-	opts = opts.WithContext(ctx)
+	opts := p.queryOptions(ctx, datacenter)
 
 	consulServices, _, err := p.client.Catalog().Service(name, tagFilter, opts)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	healthServices, _, err := p.client.Health().Service(name, tagFilter, false, opts)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Index status by service and node so it can be retrieved from a CatalogService even if the health and services
@@ -240,14 +266,81 @@ func (p *Provider) fetchService(ctx context.Context, name string) ([]*api.Catalo
 		statuses[health.Node.ID+health.Service.ID] = health.Checks.AggregatedStatus()
 	}
 
-	return consulServices, statuses, err
+	var items []itemData
+	for _, consulService := range consulServices {
+		address := consulService.ServiceAddress
+		if address == "" {
+			address = consulService.Address
+		}
+
+		status, exists := statuses[consulService.ID+consulService.ServiceID]
+		if !exists {
+			status = api.HealthAny
+		}
+
+		items = append(items, itemData{
+			ID:         consulService.ServiceID,
+			Node:       consulService.Node,
+			Datacenter: consulService.Datacenter,
+			Name:       consulService.ServiceName,
+			Address:    address,
+			Port:       strconv.Itoa(consulService.ServicePort),
+			Labels:     tagsToNeutralLabels(consulService.ServiceTags, p.Prefix),
+			Tags:       consulService.ServiceTags,
+			Status:     status,
+		})
+	}
+
+	return items, nil
 }
 
-func (p *Provider) fetchServices(ctx context.Context) ([]string, error) {
+// fetchPreparedQuery returns the instances of the service resolved by the prepared query of the same name,
+// which may come from another datacenter when the query fails over.
+func (p *Provider) fetchPreparedQuery(ctx context.Context, name, datacenter string) ([]itemData, error) {
+	result, _, err := p.client.PreparedQuery().Execute(name, p.queryOptions(ctx, datacenter))
+	if err != nil {
+		return nil, fmt.Errorf("unable to execute the prepared query %s: %w", name, err)
+	}
+
+	if result.Failovers > 0 {
+		log.FromContext(ctx).Debugf("Prepared query %s failed over to the datacenter %s", name, result.Datacenter)
+	}
+
+	var items []itemData
+	for _, entry := range result.Nodes {
+		if entry.Service == nil || entry.Node == nil {
+			continue
+		}
+
+		if !p.ExposedByDefault && !contains(entry.Service.Tags, p.Prefix+".enable=true") {
+			continue
+		}
+
+		address := entry.Service.Address
+		if address == "" {
+			address = entry.Node.Address
+		}
+
+		items = append(items, itemData{
+			ID:         entry.Service.ID,
+			Node:       entry.Node.Node,
+			Datacenter: result.Datacenter,
+			Name:       entry.Service.Service,
+			Address:    address,
+			Port:       strconv.Itoa(entry.Service.Port),
+			Labels:     tagsToNeutralLabels(entry.Service.Tags, p.Prefix),
+			Tags:       entry.Service.Tags,
+			Status:     entry.Checks.AggregatedStatus(),
+		})
+	}
+
+	return items, nil
+}
+
+func (p *Provider) fetchServices(ctx context.Context, datacenter string) ([]string, error) {
 	// The query option "Filter" is not supported by /catalog/services.
 	// https://www.consul.io/api/catalog.html#list-services
-	opts := &api.QueryOptions{AllowStale: p.Stale, RequireConsistent: p.RequireConsistent, UseCache: p.Cache}
-	serviceNames, _, err := p.client.Catalog().Services(opts)
+	serviceNames, _, err := p.client.Catalog().Services(p.queryOptions(ctx, datacenter))
 	if err != nil {
 		return nil, err
 	}
@@ -320,5 +413,34 @@ func createClient(cfg *EndpointConfig) (*api.Client, error) {
 		}
 	}
 
+	if cfg.Partition != "" {
+		// The admin partitions are not supported by the Consul client, the partition is set on the requests by the transport.
+		httpClient, err := api.NewHttpClient(api.DefaultConfig().Transport, config.TLSConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		httpClient.Transport = &partitionTransport{partition: cfg.Partition, next: httpClient.Transport}
+		config.HttpClient = httpClient
+	}
+
 	return api.NewClient(&config)
 }
+
+// partitionTransport sets the admin partition on the requests to the Consul API.
+type partitionTransport struct {
+	partition string
+	next      http.RoundTripper
+}
+
+func (t *partitionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	query := req.URL.Query()
+	if query.Get("partition") == "" {
+		query.Set("partition", t.partition)
+		req.URL.RawQuery = query.Encode()
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,157 @@
+package consulcatalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_getConsulServicesData(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		datacenters     []string
+		preparedQueries bool
+		expected        []itemData
+	}{
+		{
+			desc: "datacenter of the endpoint",
+			expected: []itemData{
+				{ID: "web-1", Node: "node-dc1", Datacenter: "dc1", Name: "web", Address: "10.0.1.1", Port: "80", Status: api.HealthPassing},
+			},
+		},
+		{
+			desc:        "multiple datacenters",
+			datacenters: []string{"dc1", "dc2"},
+			expected: []itemData{
+				{ID: "web-1", Node: "node-dc1", Datacenter: "dc1", Name: "web-dc1", Address: "10.0.1.1", Port: "80", Status: api.HealthPassing},
+				{ID: "web-1", Node: "node-dc2", Datacenter: "dc2", Name: "web-dc2", Address: "10.0.2.1", Port: "80", Status: api.HealthPassing},
+			},
+		},
+		{
+			desc:            "prepared queries",
+			datacenters:     []string{"dc1"},
+			preparedQueries: true,
+			expected: []itemData{
+				{ID: "web-1", Node: "node-dc2", Datacenter: "dc2", Name: "web-dc1", Address: "10.0.2.1", Port: "80", Status: api.HealthPassing},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := newFakeConsul(t)
+
+			p := &Provider{}
+			p.SetDefaults()
+			p.Endpoint.Address = server.URL
+			p.Datacenters = test.datacenters
+			p.PreparedQueries = test.preparedQueries
+
+			var err error
+			p.client, err = createClient(p.Endpoint)
+			require.NoError(t, err)
+
+			data, err := p.getConsulServicesData(context.Background())
+			require.NoError(t, err)
+
+			for i := range data {
+				data[i].Labels = nil
+				data[i].Tags = nil
+				data[i].ExtraConf = configuration{}
+			}
+
+			assert.Equal(t, test.expected, data)
+		})
+	}
+}
+
+func TestCreateClient_partition(t *testing.T) {
+	var mu sync.Mutex
+	var partitions []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		partitions = append(partitions, req.URL.Query().Get("partition"))
+		mu.Unlock()
+
+		_ = json.NewEncoder(rw).Encode(map[string][]string{})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := createClient(&EndpointConfig{Address: server.URL, Partition: "team-a"})
+	require.NoError(t, err)
+
+	_, _, err = client.Catalog().Services(&api.QueryOptions{Datacenter: "dc1"})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"team-a"}, partitions)
+}
+
+// newFakeConsul returns a Consul API server with a web service in the dc1 and dc2 datacenters,
+// whose prepared query fails over from dc1 to dc2.
+func newFakeConsul(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	datacenter := func(req *http.Request) string {
+		if dc := req.URL.Query().Get("dc"); dc != "" {
+			return dc
+		}
+		return "dc1"
+	}
+
+	addresses := map[string]string{"dc1": "10.0.1.1", "dc2": "10.0.2.1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/catalog/services", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(map[string][]string{"web": {}})
+	})
+	mux.HandleFunc("/v1/catalog/service/web", func(rw http.ResponseWriter, req *http.Request) {
+		dc := datacenter(req)
+		_ = json.NewEncoder(rw).Encode([]*api.CatalogService{{
+			ID:          "node-" + dc,
+			Node:        "node-" + dc,
+			Datacenter:  dc,
+			ServiceID:   "web-1",
+			ServiceName: "web",
+			Address:     addresses[dc],
+			ServicePort: 80,
+		}})
+	})
+	mux.HandleFunc("/v1/health/service/web", func(rw http.ResponseWriter, req *http.Request) {
+		dc := datacenter(req)
+		_ = json.NewEncoder(rw).Encode([]*api.ServiceEntry{{
+			Node:    &api.Node{ID: "node-" + dc, Node: "node-" + dc},
+			Service: &api.AgentService{ID: "web-1", Service: "web"},
+			Checks:  api.HealthChecks{{Status: api.HealthPassing}},
+		}})
+	})
+	mux.HandleFunc("/v1/query/web/execute", func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(api.PreparedQueryExecuteResponse{
+			Service:    "web",
+			Datacenter: "dc2",
+			Failovers:  1,
+			Nodes: []api.ServiceEntry{{
+				Node:    &api.Node{ID: "node-dc2", Node: "node-dc2", Address: "10.0.2.1"},
+				Service: &api.AgentService{ID: "web-1", Service: "web", Port: 80},
+				Checks:  api.HealthChecks{{Status: api.HealthPassing}},
+			}},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/docker/go-connections/nat"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
-	"github.com/traefik/traefik/v2/pkg/config/label"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/constraints"
@@ -17,6 +16,7 @@ import (
 
 func (p *Provider) buildConfiguration(ctx context.Context, containersInspected []dockerData) *dynamic.Configuration {
 	configurations := make(map[string]*dynamic.Configuration)
+	var labelErrors []dynamic.LabelError
 
 	for _, container := range containersInspected {
 		containerName := getServiceName(container) + "-" + container.ID
@@ -28,9 +28,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, containersInspected [
 
 		logger := log.FromContext(ctxContainer)
 
-		confFromLabel, err := label.DecodeConfiguration(container.Labels)
+		confFromLabel, err := p.decodeConfiguration(container.Labels)
 		if err != nil {
 			logger.Error(err)
+			labelErrors = append(labelErrors, provider.NewLabelErrors(containerName, err)...)
 			continue
 		}
 
@@ -85,7 +86,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, containersInspected [
 		configurations[containerName] = confFromLabel
 	}
 
-	return provider.Merge(ctx, configurations)
+	configuration := provider.Merge(ctx, configurations)
+	configuration.LabelErrors = labelErrors
+
+	return configuration
 }
 
 func (p *Provider) buildTCPServiceConfiguration(ctx context.Context, container dockerData, configuration *dynamic.TCPConfiguration) error {
@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"gopkg.in/yaml.v3"
+)
+
+// labelConfigFrom is the label declaring the path, inside the container, of a file holding the Traefik labels of the container.
+const labelConfigFrom = "traefik.configFrom"
+
+const maxConfigFileSize = 1024 * 1024
+
+// getConfigFileLabels reads the labels from the file, inside the container, declared by the traefik.configFrom label.
+// The labels set on the container take precedence over the ones read from the file.
+func getConfigFileLabels(ctx context.Context, dockerClient client.ContainerAPIClient, container dockerData) (map[string]string, error) {
+	path := container.Labels[labelConfigFrom]
+
+	reader, _, err := dockerClient.CopyFromContainer(ctx, container.ID, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	// The file is copied from the container as a tar archive.
+	tarReader := tar.NewReader(reader)
+
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+
+	if header.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("%s is not a regular file", path)
+	}
+
+	content, err := ioutil.ReadAll(io.LimitReader(tarReader, maxConfigFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+
+	if len(content) > maxConfigFileSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", path, maxConfigFileSize)
+	}
+
+	fileLabels, err := parseConfigFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+
+	labels := make(map[string]string, len(fileLabels)+len(container.Labels))
+	for name, value := range fileLabels {
+		labels[name] = value
+	}
+
+	for name, value := range container.Labels {
+		labels[name] = value
+	}
+
+	return labels, nil
+}
+
+// parseConfigFile converts the YAML content of a configuration file to labels.
+// The labels are either flat (e.g. traefik.http.routers.foo.rule: Host(`foo`)),
+// or nested (e.g. traefik: {http: {routers: {foo: {rule: Host(`foo`)}}}}).
+func parseConfigFile(content []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string)
+	if err := flattenLabels(labels, "", raw); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+func flattenLabels(labels map[string]string, prefix string, value interface{}) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, child := range v {
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+
+			if err := flattenLabels(labels, name, child); err != nil {
+				return err
+			}
+		}
+
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				return fmt.Errorf("invalid value for %s: lists can only hold scalar values", prefix)
+			}
+
+			items = append(items, fmt.Sprint(item))
+		}
+
+		labels[prefix] = strings.Join(items, ",")
+
+	case nil:
+		labels[prefix] = ""
+
+	default:
+		labels[prefix] = fmt.Sprint(v)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	dockertypes "github.com/docker/docker/api/types"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCopyClient struct {
+	dockerclient.APIClient
+	files map[string]string
+	dirs  map[string]bool
+}
+
+func (c *fakeCopyClient) CopyFromContainer(_ context.Context, _, srcPath string) (io.ReadCloser, dockertypes.ContainerPathStat, error) {
+	var header *tar.Header
+	var content string
+	switch {
+	case c.dirs[srcPath]:
+		header = &tar.Header{Name: srcPath, Typeflag: tar.TypeDir, Mode: 0o755}
+	default:
+		var ok bool
+		content, ok = c.files[srcPath]
+		if !ok {
+			return nil, dockertypes.ContainerPathStat{}, errors.New("no such file")
+		}
+		header = &tar.Header{Name: srcPath, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}
+	}
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, dockertypes.ContainerPathStat{}, err
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		return nil, dockertypes.ContainerPathStat{}, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, dockertypes.ContainerPathStat{}, err
+	}
+
+	return ioutil.NopCloser(buf), dockertypes.ContainerPathStat{}, nil
+}
+
+func TestParseConfigFile(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		content       string
+		expected      map[string]string
+		expectedError bool
+	}{
+		{
+			desc:     "empty file",
+			content:  "",
+			expected: map[string]string{},
+		},
+		{
+			desc: "flat labels",
+			content: `
+traefik.http.routers.foo.rule: Host(` + "`foo`" + `)
+traefik.http.services.foo.loadbalancer.server.port: 8080
+traefik.enable: true
+`,
+			expected: map[string]string{
+				"traefik.http.routers.foo.rule":                      "Host(`foo`)",
+				"traefik.http.services.foo.loadbalancer.server.port": "8080",
+				"traefik.enable": "true",
+			},
+		},
+		{
+			desc: "nested labels",
+			content: `
+traefik:
+  http:
+    routers:
+      foo:
+        rule: Host(` + "`foo`" + `)
+        entryPoints:
+          - web
+          - websecure
+    services:
+      foo.loadbalancer.sticky.cookie:
+`,
+			expected: map[string]string{
+				"traefik.http.routers.foo.rule":                        "Host(`foo`)",
+				"traefik.http.routers.foo.entryPoints":                 "web,websecure",
+				"traefik.http.services.foo.loadbalancer.sticky.cookie": "",
+			},
+		},
+		{
+			desc: "list of objects",
+			content: `
+traefik.http.routers.foo.middlewares:
+  - name: foo
+`,
+			expectedError: true,
+		},
+		{
+			desc:          "invalid YAML",
+			content:       "traefik: [",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			labels, err := parseConfigFile([]byte(test.content))
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, labels)
+		})
+	}
+}
+
+func TestGetConfigFileLabels(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		labels        map[string]string
+		expected      map[string]string
+		expectedError bool
+	}{
+		{
+			desc: "container labels take precedence",
+			labels: map[string]string{
+				labelConfigFrom:                 "/traefik.yml",
+				"traefik.http.routers.foo.rule": "Host(`bar`)",
+			},
+			expected: map[string]string{
+				labelConfigFrom:                        "/traefik.yml",
+				"traefik.http.routers.foo.rule":        "Host(`bar`)",
+				"traefik.http.routers.foo.entryPoints": "web",
+			},
+		},
+		{
+			desc:          "missing file",
+			labels:        map[string]string{labelConfigFrom: "/missing.yml"},
+			expectedError: true,
+		},
+		{
+			desc:          "directory",
+			labels:        map[string]string{labelConfigFrom: "/etc"},
+			expectedError: true,
+		},
+	}
+
+	dockerClient := &fakeCopyClient{
+		files: map[string]string{
+			"/traefik.yml": "traefik.http.routers.foo.rule: Host(`foo`)\ntraefik.http.routers.foo.entryPoints: web\n",
+		},
+		dirs: map[string]bool{"/etc": true},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			labels, err := getConfigFileLabels(context.Background(), dockerClient, dockerData{ID: "foo", Labels: test.labels})
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, labels)
+		})
+	}
+}
@@ -373,6 +373,7 @@ func Test_buildConfiguration(t *testing.T) {
 		containers    []dockerData
 		useBindPortIP bool
 		constraints   string
+		strictLabels  bool
 		expected      *dynamic.Configuration
 	}{
 		{
@@ -1105,6 +1106,9 @@ func Test_buildConfiguration(t *testing.T) {
 					Middlewares: map[string]*dynamic.Middleware{},
 					Services:    map[string]*dynamic.Service{},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "service", Name: "Service1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -1188,6 +1192,9 @@ func Test_buildConfiguration(t *testing.T) {
 					Middlewares: map[string]*dynamic.Middleware{},
 					Services:    map[string]*dynamic.Service{},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "service", Name: "Service1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -1489,6 +1496,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "middleware", Name: "Middleware1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -1589,6 +1599,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "middleware", Name: "Middleware1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -1662,6 +1675,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -1757,6 +1773,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -1913,6 +1932,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"Test-", "Test2-"}},
+				},
 			},
 		},
 		{
@@ -3000,6 +3022,148 @@ func Test_buildConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "invalid label value",
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels: map[string]string{
+						"traefik.http.routers.Router1.priority": "high",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+				LabelErrors: []dynamic.LabelError{
+					{Source: "Test-", Label: "traefik.http.routers.Router1.priority", Reason: `strconv.ParseInt: parsing "high": invalid syntax`},
+				},
+			},
+		},
+		{
+			desc: "unknown label ignored without strict labels",
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels: map[string]string{
+						"traefik.htp.routers.Router1.rule": "Host(`foo.bar`)",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"Test": {
+							Service: "Test",
+							Rule:    "Host(`Test.traefik.wtf`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"Test": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://127.0.0.1:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:         "unknown label with strict labels",
+			strictLabels: true,
+			containers: []dockerData{
+				{
+					ServiceName: "Test",
+					Name:        "Test",
+					Labels: map[string]string{
+						"traefik.enable":                   "true",
+						"traefik.docker.network":           "bridge",
+						"traefik.htp.routers.Router1.rule": "Host(`foo.bar`)",
+					},
+					NetworkSettings: networkSettings{
+						Ports: nat.PortMap{
+							nat.Port("80/tcp"): []nat.PortBinding{},
+						},
+						Networks: map[string]*networkData{
+							"bridge": {
+								Name: "bridge",
+								Addr: "127.0.0.1",
+							},
+						},
+					},
+				},
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+				LabelErrors: []dynamic.LabelError{
+					{Source: "Test-", Label: "traefik.htp.routers.Router1.rule", Reason: "unknown label"},
+				},
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -3012,6 +3176,7 @@ func Test_buildConfiguration(t *testing.T) {
 				ExposedByDefault: true,
 				DefaultRule:      "Host(`{{ normalize .Name }}.traefik.wtf`)",
 				UseBindPortIP:    test.useBindPortIP,
+				StrictLabels:     test.strictLabels,
 			}
 			p.Constraints = test.constraints
 
@@ -59,7 +59,20 @@ type Provider struct {
 	Network                 string           `description:"Default Docker network used." json:"network,omitempty" toml:"network,omitempty" yaml:"network,omitempty" export:"true"`
 	SwarmModeRefreshSeconds ptypes.Duration  `description:"Polling interval for swarm mode." json:"swarmModeRefreshSeconds,omitempty" toml:"swarmModeRefreshSeconds,omitempty" yaml:"swarmModeRefreshSeconds,omitempty" export:"true"`
 	HTTPClientTimeout       ptypes.Duration  `description:"Client timeout for HTTP connections." json:"httpClientTimeout,omitempty" toml:"httpClientTimeout,omitempty" yaml:"httpClientTimeout,omitempty" export:"true"`
+	AllowConfigFrom         bool             `description:"Allow the containers to declare, with the traefik.configFrom label, a file inside the container holding their labels." json:"allowConfigFrom,omitempty" toml:"allowConfigFrom,omitempty" yaml:"allowConfigFrom,omitempty" export:"true"`
+	Endpoints               []Endpoint       `description:"Additional Docker server endpoints whose containers are discovered." json:"endpoints,omitempty" toml:"endpoints,omitempty" yaml:"endpoints,omitempty" export:"true"`
+	StrictLabels            bool             `description:"Ignore the containers with unknown labels in the traefik namespace." json:"strictLabels,omitempty" toml:"strictLabels,omitempty" yaml:"strictLabels,omitempty" export:"true"`
 	defaultRuleTpl          *template.Template
+	name                    string
+}
+
+// Endpoint is an additional Docker server endpoint whose containers are discovered.
+// Its routers and services are namespaced under the docker-<name> provider.
+type Endpoint struct {
+	Name     string           `description:"Name of the endpoint, namespacing its routers and services under the docker-<name> provider." json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	Endpoint string           `description:"Docker server endpoint. Can be a tcp or a unix socket endpoint." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	TLS      *types.ClientTLS `description:"Enable Docker TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Network  string           `description:"Default Docker network used, defaults to the network of the provider." json:"network,omitempty" toml:"network,omitempty" yaml:"network,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -80,9 +93,54 @@ func (p *Provider) Init() error {
 	}
 
 	p.defaultRuleTpl = defaultRuleTpl
+
+	names := make(map[string]struct{})
+	for _, endpoint := range p.Endpoints {
+		if endpoint.Name == "" || strings.ContainsAny(endpoint.Name, "@/") {
+			return fmt.Errorf("invalid name %q for the docker endpoint %q", endpoint.Name, endpoint.Endpoint)
+		}
+
+		if _, ok := names[endpoint.Name]; ok {
+			return fmt.Errorf("duplicated docker endpoint name %q", endpoint.Name)
+		}
+		names[endpoint.Name] = struct{}{}
+
+		if endpoint.Endpoint == "" {
+			return fmt.Errorf("missing endpoint for the docker endpoint %q", endpoint.Name)
+		}
+	}
+
 	return nil
 }
 
+// providerName returns the name under which the configuration of the endpoint of the provider is published.
+func (p *Provider) providerName() string {
+	if p.name == "" {
+		return "docker"
+	}
+	return p.name
+}
+
+// endpointProviders returns the provider, followed by a copy of the provider for each additional endpoint.
+func (p *Provider) endpointProviders() []*Provider {
+	providers := []*Provider{p}
+
+	for _, endpoint := range p.Endpoints {
+		endpointProvider := *p
+		endpointProvider.Endpoints = nil
+		endpointProvider.name = "docker-" + endpoint.Name
+		endpointProvider.Endpoint = endpoint.Endpoint
+		endpointProvider.TLS = endpoint.TLS
+		if endpoint.Network != "" {
+			endpointProvider.Network = endpoint.Network
+		}
+
+		providers = append(providers, &endpointProvider)
+	}
+
+	return providers
+}
+
 // dockerData holds the need data to the provider.
 type dockerData struct {
 	ID              string
@@ -161,7 +219,7 @@ func (p *Provider) getClientOpts() ([]client.Opt, error) {
 	}
 
 	if p.TLS != nil {
-		ctx := log.With(context.Background(), log.Str(log.ProviderName, "docker"))
+		ctx := log.With(context.Background(), log.Str(log.ProviderName, p.providerName()))
 
 		conf, err := p.TLS.CreateTLSConfig(ctx)
 		if err != nil {
@@ -189,8 +247,19 @@ func (p *Provider) getClientOpts() ([]client.Opt, error) {
 
 // Provide allows the docker provider to provide configurations to traefik using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
+	for _, endpointProvider := range p.endpointProviders() {
+		endpointProvider.provide(configurationChan, pool)
+	}
+
+	return nil
+}
+
+// provide watches the containers, or the services, of the endpoint of the provider.
+func (p *Provider) provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) {
+	providerName := p.providerName()
+
 	pool.GoCtx(func(routineCtx context.Context) {
-		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, "docker"))
+		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, providerName))
 		logger := log.FromContext(ctxLog)
 
 		operation := func() error {
@@ -198,7 +267,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 			ctx, cancel := context.WithCancel(ctxLog)
 			defer cancel()
 
-			ctx = log.With(ctx, log.Str(log.ProviderName, "docker"))
+			ctx = log.With(ctx, log.Str(log.ProviderName, providerName))
 
 			dockerClient, err := p.createClient()
 			if err != nil {
@@ -229,7 +298,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 			configuration := p.buildConfiguration(ctxLog, dockerDataList)
 			configurationChan <- dynamic.Message{
-				ProviderName:  "docker",
+				ProviderName:  providerName,
 				Configuration: configuration,
 			}
 			if p.Watch {
@@ -240,7 +309,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 					ticker := time.NewTicker(time.Duration(p.SwarmModeRefreshSeconds))
 
 					pool.GoCtx(func(ctx context.Context) {
-						ctx = log.With(ctx, log.Str(log.ProviderName, "docker"))
+						ctx = log.With(ctx, log.Str(log.ProviderName, providerName))
 						logger := log.FromContext(ctx)
 
 						defer close(errChan)
@@ -257,7 +326,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 								configuration := p.buildConfiguration(ctx, services)
 								if configuration != nil {
 									configurationChan <- dynamic.Message{
-										ProviderName:  "docker",
+										ProviderName:  providerName,
 										Configuration: configuration,
 									}
 								}
@@ -291,7 +360,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 						configuration := p.buildConfiguration(ctx, containers)
 						if configuration != nil {
 							message := dynamic.Message{
-								ProviderName:  "docker",
+								ProviderName:  providerName,
 								Configuration: configuration,
 							}
 							select {
@@ -326,14 +395,13 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 		notify := func(err error, time time.Duration) {
 			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+			provider.ReportFailure(providerName, err)
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
 		if err != nil {
 			logger.Errorf("Cannot connect to docker server %+v", err)
 		}
 	})
-
-	return nil
 }
 
 func (p *Provider) listContainers(ctx context.Context, dockerClient client.ContainerAPIClient) ([]dockerData, error) {
@@ -350,6 +418,15 @@ func (p *Provider) listContainers(ctx context.Context, dockerClient client.Conta
 			continue
 		}
 
+		if p.AllowConfigFrom && dData.Labels[labelConfigFrom] != "" {
+			labels, err := getConfigFileLabels(ctx, dockerClient, dData)
+			if err != nil {
+				log.FromContext(ctx).Errorf("Skip container %s: %v", getServiceName(dData), err)
+				continue
+			}
+			dData.Labels = labels
+		}
+
 		extraConf, err := p.getConfiguration(dData)
 		if err != nil {
 			log.FromContext(ctx).Errorf("Skip container %s: %v", getServiceName(dData), err)
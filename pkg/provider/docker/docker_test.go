@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestProvider_Init_endpoints(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		endpoints []Endpoint
+		expectErr bool
+	}{
+		{
+			desc: "valid endpoints",
+			endpoints: []Endpoint{
+				{Name: "host1", Endpoint: "tcp://10.0.0.1:2376"},
+				{Name: "host2", Endpoint: "tcp://10.0.0.2:2376"},
+			},
+		},
+		{
+			desc:      "missing name",
+			endpoints: []Endpoint{{Endpoint: "tcp://10.0.0.1:2376"}},
+			expectErr: true,
+		},
+		{
+			desc:      "invalid name",
+			endpoints: []Endpoint{{Name: "host@1", Endpoint: "tcp://10.0.0.1:2376"}},
+			expectErr: true,
+		},
+		{
+			desc: "duplicated name",
+			endpoints: []Endpoint{
+				{Name: "host1", Endpoint: "tcp://10.0.0.1:2376"},
+				{Name: "host1", Endpoint: "tcp://10.0.0.2:2376"},
+			},
+			expectErr: true,
+		},
+		{
+			desc:      "missing endpoint",
+			endpoints: []Endpoint{{Name: "host1"}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{}
+			p.SetDefaults()
+			p.Endpoints = test.endpoints
+
+			err := p.Init()
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProvider_endpointProviders(t *testing.T) {
+	p := &Provider{}
+	p.SetDefaults()
+	p.Network = "web"
+	p.Endpoints = []Endpoint{
+		{
+			Name:     "host1",
+			Endpoint: "tcp://10.0.0.1:2376",
+			TLS:      &types.ClientTLS{CA: "ca.crt"},
+		},
+		{
+			Name:     "host2",
+			Endpoint: "tcp://10.0.0.2:2376",
+			Network:  "proxy",
+		},
+	}
+	require.NoError(t, p.Init())
+
+	providers := p.endpointProviders()
+	require.Len(t, providers, 3)
+
+	assert.Same(t, p, providers[0])
+	assert.Equal(t, "docker", providers[0].providerName())
+
+	assert.Equal(t, "docker-host1", providers[1].providerName())
+	assert.Equal(t, "tcp://10.0.0.1:2376", providers[1].Endpoint)
+	assert.Equal(t, &types.ClientTLS{CA: "ca.crt"}, providers[1].TLS)
+	assert.Equal(t, "web", providers[1].Network)
+	assert.Empty(t, providers[1].Endpoints)
+
+	assert.Equal(t, "docker-host2", providers[2].providerName())
+	assert.Equal(t, "tcp://10.0.0.2:2376", providers[2].Endpoint)
+	assert.Nil(t, providers[2].TLS)
+	assert.Equal(t, "proxy", providers[2].Network)
+	assert.Equal(t, p.defaultRuleTpl, providers[2].defaultRuleTpl)
+}
@@ -3,6 +3,7 @@ package docker
 import (
 	"fmt"
 
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/label"
 )
 
@@ -39,6 +40,16 @@ func (p *Provider) getConfiguration(container dockerData) (configuration, error)
 	return conf, nil
 }
 
+// decodeConfiguration converts the labels of a container to a configuration.
+// In strict mode, the unknown labels of the traefik namespace are rejected.
+func (p *Provider) decodeConfiguration(labels map[string]string) (*dynamic.Configuration, error) {
+	if !p.StrictLabels {
+		return label.DecodeConfiguration(labels)
+	}
+
+	return label.DecodeConfigurationStrict(labels, "traefik.docker.", "traefik.enable", labelConfigFrom)
+}
+
 // getStringMultipleStrict get multiple string values associated to several labels.
 // Fail if one label is missing.
 func getStringMultipleStrict(labels map[string]string, labelNames ...string) (map[string]string, error) {
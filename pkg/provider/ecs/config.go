@@ -11,7 +11,6 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/docker/go-connections/nat"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
-	"github.com/traefik/traefik/v2/pkg/config/label"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/constraints"
@@ -19,6 +18,7 @@ import (
 
 func (p *Provider) buildConfiguration(ctx context.Context, instances []ecsInstance) *dynamic.Configuration {
 	configurations := make(map[string]*dynamic.Configuration)
+	var labelErrors []dynamic.LabelError
 
 	for _, instance := range instances {
 		instanceName := getServiceName(instance) + "-" + instance.ID
@@ -30,9 +30,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, instances []ecsInstan
 
 		logger := log.FromContext(ctxContainer)
 
-		confFromLabel, err := label.DecodeConfiguration(instance.Labels)
+		confFromLabel, err := p.decodeConfiguration(instance.Labels)
 		if err != nil {
 			logger.Error(err)
+			labelErrors = append(labelErrors, provider.NewLabelErrors(instanceName, err)...)
 			continue
 		}
 
@@ -87,7 +88,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, instances []ecsInstan
 		configurations[instanceName] = confFromLabel
 	}
 
-	return provider.Merge(ctx, configurations)
+	configuration := provider.Merge(ctx, configurations)
+	configuration.LabelErrors = labelErrors
+
+	return configuration
 }
 
 func (p *Provider) buildTCPServiceConfiguration(instance ecsInstance, configuration *dynamic.TCPConfiguration) error {
@@ -993,6 +993,9 @@ func Test_buildConfiguration(t *testing.T) {
 					Middlewares: map[string]*dynamic.Middleware{},
 					Services:    map[string]*dynamic.Service{},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "service", Name: "Service1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -1061,6 +1064,9 @@ func Test_buildConfiguration(t *testing.T) {
 					Middlewares: map[string]*dynamic.Middleware{},
 					Services:    map[string]*dynamic.Service{},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "service", Name: "Service1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -1327,6 +1333,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "middleware", Name: "Middleware1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -1412,6 +1421,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "middleware", Name: "Middleware1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -1475,6 +1487,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"Test-1", "Test-2"}},
+				},
 			},
 		},
 		{
@@ -1555,6 +1570,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"Test-1", "Test-2", "Test-3"}},
+				},
 			},
 		},
 		{
@@ -1692,6 +1710,9 @@ func Test_buildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"Test-", "Test2-"}},
+				},
 			},
 		},
 		{
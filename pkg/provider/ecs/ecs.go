@@ -9,6 +9,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/defaults"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -29,16 +30,31 @@ type Provider struct {
 	ExposedByDefault bool   `description:"Expose services by default" json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
 	RefreshSeconds   int    `description:"Polling interval (in seconds)" json:"refreshSeconds,omitempty" toml:"refreshSeconds,omitempty" yaml:"refreshSeconds,omitempty" export:"true"`
 	DefaultRule      string `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	ServiceTags      bool   `description:"Use the tags of the ECS services as labels." json:"serviceTags,omitempty" toml:"serviceTags,omitempty" yaml:"serviceTags,omitempty" export:"true"`
 
 	// Provider lookup parameters.
-	Clusters             []string `description:"ECS Clusters name" json:"clusters,omitempty" toml:"clusters,omitempty" yaml:"clusters,omitempty" export:"true"`
-	AutoDiscoverClusters bool     `description:"Auto discover cluster" json:"autoDiscoverClusters,omitempty" toml:"autoDiscoverClusters,omitempty" yaml:"autoDiscoverClusters,omitempty" export:"true"`
-	Region               string   `description:"The AWS region to use for requests"  json:"region,omitempty" toml:"region,omitempty" yaml:"region,omitempty" export:"true"`
-	AccessKeyID          string   `description:"The AWS credentials access key to use for making requests" json:"accessKeyID,omitempty" toml:"accessKeyID,omitempty" yaml:"accessKeyID,omitempty"`
-	SecretAccessKey      string   `description:"The AWS credentials access key to use for making requests" json:"secretAccessKey,omitempty" toml:"secretAccessKey,omitempty" yaml:"secretAccessKey,omitempty"`
+	Clusters             []string  `description:"ECS Clusters name" json:"clusters,omitempty" toml:"clusters,omitempty" yaml:"clusters,omitempty" export:"true"`
+	AutoDiscoverClusters bool      `description:"Auto discover cluster" json:"autoDiscoverClusters,omitempty" toml:"autoDiscoverClusters,omitempty" yaml:"autoDiscoverClusters,omitempty" export:"true"`
+	Region               string    `description:"The AWS region to use for requests"  json:"region,omitempty" toml:"region,omitempty" yaml:"region,omitempty" export:"true"`
+	AccessKeyID          string    `description:"The AWS credentials access key to use for making requests" json:"accessKeyID,omitempty" toml:"accessKeyID,omitempty" yaml:"accessKeyID,omitempty"`
+	SecretAccessKey      string    `description:"The AWS credentials access key to use for making requests" json:"secretAccessKey,omitempty" toml:"secretAccessKey,omitempty" yaml:"secretAccessKey,omitempty"`
+	RoleARN              string    `description:"The ARN of the IAM role to assume for making requests" json:"roleARN,omitempty" toml:"roleARN,omitempty" yaml:"roleARN,omitempty" export:"true"`
+	ExternalID           string    `description:"The external ID to use when assuming the IAM role" json:"externalID,omitempty" toml:"externalID,omitempty" yaml:"externalID,omitempty"`
+	Accounts             []Account `description:"Additional AWS accounts and regions whose clusters are discovered" json:"accounts,omitempty" toml:"accounts,omitempty" yaml:"accounts,omitempty" export:"true"`
+	StrictLabels         bool      `description:"Ignore the instances with unknown labels in the traefik namespace." json:"strictLabels,omitempty" toml:"strictLabels,omitempty" yaml:"strictLabels,omitempty" export:"true"`
 	defaultRuleTpl       *template.Template
 }
 
+// Account is an AWS account, or region, whose ECS clusters are discovered in addition to the ones of the provider.
+// Its requests are made with the credentials of the provider, assuming the IAM role if any.
+type Account struct {
+	Region               string   `description:"The AWS region of the clusters, defaults to the region of the provider" json:"region,omitempty" toml:"region,omitempty" yaml:"region,omitempty" export:"true"`
+	RoleARN              string   `description:"The ARN of the IAM role to assume for making requests" json:"roleARN,omitempty" toml:"roleARN,omitempty" yaml:"roleARN,omitempty" export:"true"`
+	ExternalID           string   `description:"The external ID to use when assuming the IAM role" json:"externalID,omitempty" toml:"externalID,omitempty" yaml:"externalID,omitempty"`
+	Clusters             []string `description:"ECS Clusters name, defaults to the default cluster" json:"clusters,omitempty" toml:"clusters,omitempty" yaml:"clusters,omitempty" export:"true"`
+	AutoDiscoverClusters bool     `description:"Auto discover cluster" json:"autoDiscoverClusters,omitempty" toml:"autoDiscoverClusters,omitempty" yaml:"autoDiscoverClusters,omitempty" export:"true"`
+}
+
 type ecsInstance struct {
 	Name                string
 	ID                  string
@@ -64,6 +80,10 @@ type machine struct {
 type awsClient struct {
 	ecs *ecs.ECS
 	ec2 *ec2.EC2
+
+	// clusters are the clusters whose tasks are listed, unless autoDiscoverClusters is true.
+	clusters             []string
+	autoDiscoverClusters bool
 }
 
 // DefaultTemplateRule The default template for the default rule.
@@ -94,7 +114,8 @@ func (p *Provider) Init() error {
 	return nil
 }
 
-func (p *Provider) createClient(logger log.Logger) (*awsClient, error) {
+// createClients creates the clients of the account of the provider, and of the additional accounts.
+func (p *Provider) createClients(logger log.Logger) ([]*awsClient, error) {
 	sess, err := session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
 	})
@@ -112,34 +133,78 @@ func (p *Provider) createClient(logger log.Logger) (*awsClient, error) {
 		p.Region = identity.Region
 	}
 
-	cfg := &aws.Config{
-		Credentials: credentials.NewChainCredentials(
-			[]credentials.Provider{
-				&credentials.StaticProvider{
-					Value: credentials.Value{
-						AccessKeyID:     p.AccessKeyID,
-						SecretAccessKey: p.SecretAccessKey,
-					},
+	creds := credentials.NewChainCredentials(
+		[]credentials.Provider{
+			&credentials.StaticProvider{
+				Value: credentials.Value{
+					AccessKeyID:     p.AccessKeyID,
+					SecretAccessKey: p.SecretAccessKey,
 				},
-				&credentials.EnvProvider{},
-				&credentials.SharedCredentialsProvider{},
-				defaults.RemoteCredProvider(*(defaults.Config()), defaults.Handlers()),
-			}),
+			},
+			&credentials.EnvProvider{},
+			&credentials.SharedCredentialsProvider{},
+			defaults.RemoteCredProvider(*(defaults.Config()), defaults.Handlers()),
+		})
+
+	accounts := append([]Account{{
+		Region:               p.Region,
+		RoleARN:              p.RoleARN,
+		ExternalID:           p.ExternalID,
+		Clusters:             p.Clusters,
+		AutoDiscoverClusters: p.AutoDiscoverClusters,
+	}}, p.Accounts...)
+
+	var clients []*awsClient
+	for _, account := range accounts {
+		region := account.Region
+		if region == "" {
+			region = p.Region
+		}
+
+		clusters := account.Clusters
+		if len(clusters) == 0 {
+			clusters = []string{"default"}
+		}
+
+		cfg := p.createConfig(logger, sess, creds, region, account.RoleARN, account.ExternalID)
+
+		clients = append(clients, &awsClient{
+			ecs:                  ecs.New(sess, cfg),
+			ec2:                  ec2.New(sess, cfg),
+			clusters:             clusters,
+			autoDiscoverClusters: account.AutoDiscoverClusters,
+		})
+	}
+
+	return clients, nil
+}
+
+// createConfig creates the configuration of the clients of an account,
+// whose credentials are the ones of the IAM role when roleARN is not empty.
+func (p *Provider) createConfig(logger log.Logger, sess *session.Session, creds *credentials.Credentials, region, roleARN, externalID string) *aws.Config {
+	cfg := &aws.Config{
+		Credentials: creds,
 	}
 
 	// Set the region if it is defined by the user or resolved from the EC2 metadata.
-	if p.Region != "" {
-		cfg.Region = &p.Region
+	if region != "" {
+		cfg.Region = aws.String(region)
+	}
+
+	if roleARN != "" {
+		// The role is assumed with the credentials of the provider.
+		cfg.Credentials = stscreds.NewCredentials(sess.Copy(cfg), roleARN, func(provider *stscreds.AssumeRoleProvider) {
+			if externalID != "" {
+				provider.ExternalID = aws.String(externalID)
+			}
+		})
 	}
 
 	cfg.WithLogger(aws.LoggerFunc(func(args ...interface{}) {
 		logger.Debug(args...)
 	}))
 
-	return &awsClient{
-		ecs.New(sess, cfg),
-		ec2.New(sess, cfg),
-	}, nil
+	return cfg
 }
 
 // Provide configuration to traefik from ECS.
@@ -149,12 +214,12 @@ func (p Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.P
 		logger := log.FromContext(ctxLog)
 
 		operation := func() error {
-			awsClient, err := p.createClient(logger)
+			awsClients, err := p.createClients(logger)
 			if err != nil {
 				return fmt.Errorf("unable to create AWS client: %w", err)
 			}
 
-			err = p.loadConfiguration(ctxLog, awsClient, configurationChan)
+			err = p.loadConfiguration(ctxLog, awsClients, configurationChan)
 			if err != nil {
 				return fmt.Errorf("failed to get ECS configuration: %w", err)
 			}
@@ -165,7 +230,7 @@ func (p Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.P
 			for {
 				select {
 				case <-ticker.C:
-					err = p.loadConfiguration(ctxLog, awsClient, configurationChan)
+					err = p.loadConfiguration(ctxLog, awsClients, configurationChan)
 					if err != nil {
 						return fmt.Errorf("failed to refresh ECS configuration: %w", err)
 					}
@@ -178,6 +243,7 @@ func (p Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.P
 
 		notify := func(err error, time time.Duration) {
 			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+			provider.ReportFailure("ecs", err)
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), routineCtx), notify)
 		if err != nil {
@@ -188,10 +254,15 @@ func (p Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.P
 	return nil
 }
 
-func (p *Provider) loadConfiguration(ctx context.Context, client *awsClient, configurationChan chan<- dynamic.Message) error {
-	instances, err := p.listInstances(ctx, client)
-	if err != nil {
-		return err
+func (p *Provider) loadConfiguration(ctx context.Context, clients []*awsClient, configurationChan chan<- dynamic.Message) error {
+	var instances []ecsInstance
+	for _, client := range clients {
+		clientInstances, err := p.listInstances(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		instances = append(instances, clientInstances...)
 	}
 
 	configurationChan <- dynamic.Message{
@@ -210,7 +281,7 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 	var clustersArn []*string
 	var clusters []string
 
-	if p.AutoDiscoverClusters {
+	if client.autoDiscoverClusters {
 		input := &ecs.ListClustersInput{}
 		for {
 			result, err := client.ecs.ListClusters(input)
@@ -231,7 +302,7 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 			clusters = append(clusters, *cArn)
 		}
 	} else {
-		clusters = p.Clusters
+		clusters = client.clusters
 	}
 
 	var instances []ecsInstance
@@ -283,6 +354,14 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 			return nil, err
 		}
 
+		var serviceTags map[string]map[string]string
+		if p.ServiceTags {
+			serviceTags, err = p.lookupServiceTags(ctx, client, &c, tasks)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		for key, task := range tasks {
 			containerInstance := ec2Instances[aws.StringValue(task.ContainerInstanceArn)]
 			taskDef := taskDefinitions[key]
@@ -351,7 +430,7 @@ func (p *Provider) listInstances(ctx context.Context, client *awsClient) ([]ecsI
 					ID:                  key[len(key)-12:],
 					containerDefinition: containerDefinition,
 					machine:             mach,
-					Labels:              aws.StringValueMap(containerDefinition.DockerLabels),
+					Labels:              mergeLabels(serviceTags[taskServiceName(task)], aws.StringValueMap(containerDefinition.DockerLabels)),
 				}
 
 				extraConf, err := p.getConfiguration(instance)
@@ -451,14 +530,91 @@ func (p *Provider) lookupTaskDefinitions(ctx context.Context, client *awsClient,
 	return taskDef, nil
 }
 
+// lookupServiceTags returns the tags of the ECS services the tasks belong to, keyed by service name.
+func (p *Provider) lookupServiceTags(ctx context.Context, client *awsClient, clusterName *string, tasks map[string]*ecs.Task) (map[string]map[string]string, error) {
+	logger := log.FromContext(ctx)
+
+	var serviceNames []*string
+	seen := make(map[string]struct{})
+	for _, task := range tasks {
+		name := taskServiceName(task)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		serviceNames = append(serviceNames, aws.String(name))
+	}
+
+	serviceTags := make(map[string]map[string]string)
+	for _, names := range chunk(serviceNames, 10) {
+		resp, err := client.ecs.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{
+			Cluster:  clusterName,
+			Services: names,
+			Include:  []*string{aws.String(ecs.ServiceFieldTags)},
+		})
+		if err != nil {
+			logger.Errorf("Unable to describe services: %v", err)
+			return nil, err
+		}
+
+		for _, service := range resp.Services {
+			tags := make(map[string]string)
+			for _, tag := range service.Tags {
+				tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			serviceTags[aws.StringValue(service.ServiceName)] = tags
+		}
+	}
+
+	return serviceTags, nil
+}
+
+// taskServiceName returns the name of the ECS service which started the task,
+// or an empty string if the task was not started by a service.
+func taskServiceName(task *ecs.Task) string {
+	group := aws.StringValue(task.Group)
+	if !strings.HasPrefix(group, "service:") {
+		return ""
+	}
+
+	return strings.TrimPrefix(group, "service:")
+}
+
+// mergeLabels merges the service tags and the container labels,
+// the container labels taking precedence as they are more specific.
+func mergeLabels(serviceTags, containerLabels map[string]string) map[string]string {
+	if len(serviceTags) == 0 {
+		return containerLabels
+	}
+
+	labels := make(map[string]string, len(serviceTags)+len(containerLabels))
+	for k, v := range serviceTags {
+		labels[k] = v
+	}
+	for k, v := range containerLabels {
+		labels[k] = v
+	}
+
+	return labels
+}
+
 // chunkIDs ECS expects no more than 100 parameters be passed to a API call;
 // thus, pack each string into an array capped at 100 elements.
 func (p *Provider) chunkIDs(ids []*string) [][]*string {
+	return chunk(ids, 100)
+}
+
+// chunk packs the strings into arrays capped at size elements.
+func chunk(ids []*string, size int) [][]*string {
 	var chuncked [][]*string
-	for i := 0; i < len(ids); i += 100 {
+	for i := 0; i < len(ids); i += size {
 		var sliceEnd int
-		if i+100 < len(ids) {
-			sliceEnd = i + 100
+		if i+size < len(ids) {
+			sliceEnd = i + size
 		} else {
 			sliceEnd = len(ids)
 		}
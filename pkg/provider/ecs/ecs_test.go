@@ -4,7 +4,10 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/log"
 )
 
 func TestChunkIDs(t *testing.T) {
@@ -86,3 +89,110 @@ func TestChunkIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskServiceName(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		group    *string
+		expected string
+	}{
+		{
+			desc:     "task started by a service",
+			group:    aws.String("service:whoami"),
+			expected: "whoami",
+		},
+		{
+			desc:  "standalone task",
+			group: aws.String("family:whoami"),
+		},
+		{
+			desc: "no group",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, taskServiceName(&ecs.Task{Group: test.group}))
+		})
+	}
+}
+
+func TestMergeLabels(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		serviceTags     map[string]string
+		containerLabels map[string]string
+		expected        map[string]string
+	}{
+		{
+			desc:            "no service tags",
+			containerLabels: map[string]string{"traefik.enable": "true"},
+			expected:        map[string]string{"traefik.enable": "true"},
+		},
+		{
+			desc:        "no container labels",
+			serviceTags: map[string]string{"traefik.enable": "true"},
+			expected:    map[string]string{"traefik.enable": "true"},
+		},
+		{
+			desc:            "container labels take precedence",
+			serviceTags:     map[string]string{"traefik.enable": "false", "traefik.http.services.foo.loadbalancer.server.port": "80"},
+			containerLabels: map[string]string{"traefik.enable": "true"},
+			expected: map[string]string{
+				"traefik.enable": "true",
+				"traefik.http.services.foo.loadbalancer.server.port": "80",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, mergeLabels(test.serviceTags, test.containerLabels))
+		})
+	}
+}
+
+func TestCreateClients(t *testing.T) {
+	provider := &Provider{
+		Region:   "us-east-1",
+		Clusters: []string{"main"},
+		Accounts: []Account{
+			{
+				RoleARN:    "arn:aws:iam::123456789012:role/traefik",
+				ExternalID: "foo",
+				Clusters:   []string{"foo", "bar"},
+			},
+			{
+				Region:               "eu-west-1",
+				RoleARN:              "arn:aws:iam::210987654321:role/traefik",
+				AutoDiscoverClusters: true,
+			},
+		},
+	}
+
+	clients, err := provider.createClients(log.WithoutContext())
+	require.NoError(t, err)
+	require.Len(t, clients, 3)
+
+	assert.Equal(t, []string{"main"}, clients[0].clusters)
+	assert.False(t, clients[0].autoDiscoverClusters)
+	assert.Equal(t, "us-east-1", aws.StringValue(clients[0].ecs.Config.Region))
+
+	assert.Equal(t, []string{"foo", "bar"}, clients[1].clusters)
+	assert.Equal(t, "us-east-1", aws.StringValue(clients[1].ecs.Config.Region))
+
+	assert.Equal(t, []string{"default"}, clients[2].clusters)
+	assert.True(t, clients[2].autoDiscoverClusters)
+	assert.Equal(t, "eu-west-1", aws.StringValue(clients[2].ec2.Config.Region))
+
+	// The accounts with an IAM role have their own credentials.
+	assert.NotSame(t, clients[0].ecs.Config.Credentials, clients[1].ecs.Config.Credentials)
+	assert.NotSame(t, clients[1].ecs.Config.Credentials, clients[2].ecs.Config.Credentials)
+	assert.Same(t, clients[0].ecs.Config.Credentials, clients[0].ec2.Config.Credentials)
+}
@@ -1,6 +1,7 @@
 package ecs
 
 import (
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/label"
 )
 
@@ -21,3 +22,13 @@ func (p *Provider) getConfiguration(instance ecsInstance) (configuration, error)
 
 	return conf, nil
 }
+
+// decodeConfiguration converts the labels of an instance to a configuration.
+// In strict mode, the unknown labels of the traefik namespace are rejected.
+func (p *Provider) decodeConfiguration(labels map[string]string) (*dynamic.Configuration, error) {
+	if !p.StrictLabels {
+		return label.DecodeConfiguration(labels)
+	}
+
+	return label.DecodeConfigurationStrict(labels, "traefik.ecs.", "traefik.enable")
+}
@@ -29,6 +29,7 @@ type Provider struct {
 	Directory                 string `description:"Load dynamic configuration from one or more .yml or .toml files in a directory." json:"directory,omitempty" toml:"directory,omitempty" yaml:"directory,omitempty" export:"true"`
 	Watch                     bool   `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
 	Filename                  string `description:"Load dynamic configuration from a file." json:"filename,omitempty" toml:"filename,omitempty" yaml:"filename,omitempty" export:"true"`
+	Manifest                  string `description:"Load dynamic configuration from the files listed in a manifest, once they all match their SHA-256 hash." json:"manifest,omitempty" toml:"manifest,omitempty" yaml:"manifest,omitempty" export:"true"`
 	DebugLogGeneratedTemplate bool   `description:"Enable debug logging of generated configuration template." json:"debugLogGeneratedTemplate,omitempty" toml:"debugLogGeneratedTemplate,omitempty" yaml:"debugLogGeneratedTemplate,omitempty" export:"true"`
 }
 
@@ -48,7 +49,14 @@ func (p *Provider) Init() error {
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
 	configuration, err := p.BuildConfiguration()
 	if err != nil {
-		return err
+		// The files listed in the manifest could be being written,
+		// in which case the configuration is provided once they are consistent.
+		if !p.Watch || !errors.Is(err, errInconsistentManifest) {
+			return err
+		}
+
+		log.WithoutContext().WithField(log.ProviderName, providerName).Warnf("Waiting for the files of the manifest to be consistent: %v", err)
+		configuration = nil
 	}
 
 	if p.Watch {
@@ -59,8 +67,10 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 			watchItem = p.Directory
 		case len(p.Filename) > 0:
 			watchItem = filepath.Dir(p.Filename)
+		case len(p.Manifest) > 0:
+			watchItem = filepath.Dir(p.Manifest)
 		default:
-			return errors.New("error using file configuration provider, neither filename or directory defined")
+			return errors.New("error using file configuration provider, neither filename, directory or manifest defined")
 		}
 
 		if err := p.addWatcher(pool, watchItem, configurationChan, p.watcherCallback); err != nil {
@@ -68,12 +78,14 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		}
 	}
 
-	sendConfigToChannel(configurationChan, configuration)
+	if configuration != nil {
+		sendConfigToChannel(configurationChan, configuration)
+	}
 	return nil
 }
 
-// BuildConfiguration loads configuration either from file or a directory
-// specified by 'Filename'/'Directory' and returns a 'Configuration' object.
+// BuildConfiguration loads configuration either from file, a directory, or the files listed in a manifest
+// specified by 'Filename'/'Directory'/'Manifest' and returns a 'Configuration' object.
 func (p *Provider) BuildConfiguration() (*dynamic.Configuration, error) {
 	ctx := log.With(context.Background(), log.Str(log.ProviderName, providerName))
 
@@ -85,7 +97,11 @@ func (p *Provider) BuildConfiguration() (*dynamic.Configuration, error) {
 		return p.loadFileConfig(ctx, p.Filename, true)
 	}
 
-	return nil, errors.New("error using file configuration provider, neither filename or directory defined")
+	if len(p.Manifest) > 0 {
+		return p.loadFileConfigFromManifest(ctx, p.Manifest)
+	}
+
+	return nil, errors.New("error using file configuration provider, neither filename, directory or manifest defined")
 }
 
 func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationChan chan<- dynamic.Message, callback func(chan<- dynamic.Message, fsnotify.Event)) error {
@@ -107,7 +123,8 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 			case <-ctx.Done():
 				return
 			case evt := <-watcher.Events:
-				if p.Directory == "" {
+				// In manifest mode, the listed files can be changed as well as the manifest.
+				if p.Directory == "" && p.Filename != "" {
 					_, evtFileName := filepath.Split(evt.Name)
 					_, confFileName := filepath.Split(p.Filename)
 					if evtFileName == confFileName {
@@ -126,8 +143,11 @@ func (p *Provider) addWatcher(pool *safe.Pool, directory string, configurationCh
 
 func (p *Provider) watcherCallback(configurationChan chan<- dynamic.Message, event fsnotify.Event) {
 	watchItem := p.Filename
-	if len(p.Directory) > 0 {
+	switch {
+	case len(p.Directory) > 0:
 		watchItem = p.Directory
+	case len(p.Filename) == 0:
+		watchItem = p.Manifest
 	}
 
 	logger := log.WithoutContext().WithField(log.ProviderName, providerName)
@@ -138,6 +158,10 @@ func (p *Provider) watcherCallback(configurationChan chan<- dynamic.Message, eve
 	}
 
 	configuration, err := p.BuildConfiguration()
+	if errors.Is(err, errInconsistentManifest) {
+		logger.Debugf("Waiting for the files of the manifest to be consistent: %v", err)
+		return
+	}
 	if err != nil {
 		logger.Errorf("Error occurred during watcher callback: %s", err)
 		return
@@ -202,34 +226,12 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 	}
 
 	if configuration == nil {
-		configuration = &dynamic.Configuration{
-			HTTP: &dynamic.HTTPConfiguration{
-				Routers:           make(map[string]*dynamic.Router),
-				Middlewares:       make(map[string]*dynamic.Middleware),
-				Services:          make(map[string]*dynamic.Service),
-				ServersTransports: make(map[string]*dynamic.ServersTransport),
-			},
-			TCP: &dynamic.TCPConfiguration{
-				Routers:     make(map[string]*dynamic.TCPRouter),
-				Services:    make(map[string]*dynamic.TCPService),
-				Middlewares: make(map[string]*dynamic.TCPMiddleware),
-			},
-			TLS: &dynamic.TLSConfiguration{
-				Stores:  make(map[string]tls.Store),
-				Options: make(map[string]tls.Options),
-			},
-			UDP: &dynamic.UDPConfiguration{
-				Routers:  make(map[string]*dynamic.UDPRouter),
-				Services: make(map[string]*dynamic.UDPService),
-			},
-		}
+		configuration = newConfiguration()
 	}
 
 	configTLSMaps := make(map[*tls.CertAndStores]struct{})
 
 	for _, item := range fileList {
-		logger := log.FromContext(log.With(ctx, log.Str("filename", item.Name())))
-
 		if item.IsDir() {
 			configuration, err = p.loadFileConfigFromDirectory(ctx, filepath.Join(directory, item.Name()), configuration)
 			if err != nil {
@@ -251,109 +253,149 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 			return configuration, fmt.Errorf("%s: %w", filepath.Join(directory, item.Name()), err)
 		}
 
-		for name, conf := range c.HTTP.Routers {
-			if _, exists := configuration.HTTP.Routers[name]; exists {
-				logger.WithField(log.RouterName, name).Warn("HTTP router already configured, skipping")
-			} else {
-				configuration.HTTP.Routers[name] = conf
-			}
+		mergeConfiguration(ctx, filepath.Join(directory, item.Name()), configuration, c, configTLSMaps)
+	}
+
+	addCertificates(configuration, configTLSMaps)
+
+	return configuration, nil
+}
+
+// newConfiguration returns an empty configuration, to merge the configurations of several files into.
+func newConfiguration() *dynamic.Configuration {
+	return &dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers:           make(map[string]*dynamic.Router),
+			Middlewares:       make(map[string]*dynamic.Middleware),
+			Services:          make(map[string]*dynamic.Service),
+			ServersTransports: make(map[string]*dynamic.ServersTransport),
+		},
+		TCP: &dynamic.TCPConfiguration{
+			Routers:     make(map[string]*dynamic.TCPRouter),
+			Services:    make(map[string]*dynamic.TCPService),
+			Middlewares: make(map[string]*dynamic.TCPMiddleware),
+		},
+		TLS: &dynamic.TLSConfiguration{
+			Stores:  make(map[string]tls.Store),
+			Options: make(map[string]tls.Options),
+		},
+		UDP: &dynamic.UDPConfiguration{
+			Routers:  make(map[string]*dynamic.UDPRouter),
+			Services: make(map[string]*dynamic.UDPService),
+		},
+	}
+}
+
+// mergeConfiguration merges the configuration c, loaded from filename, into configuration.
+// The TLS certificates are collected in configTLSMaps, to be added with addCertificates once all the files are merged.
+func mergeConfiguration(ctx context.Context, filename string, configuration, c *dynamic.Configuration, configTLSMaps map[*tls.CertAndStores]struct{}) {
+	logger := log.FromContext(log.With(ctx, log.Str("filename", filepath.Base(filename))))
+
+	for name, conf := range c.HTTP.Routers {
+		if _, exists := configuration.HTTP.Routers[name]; exists {
+			logger.WithField(log.RouterName, name).Warn("HTTP router already configured, skipping")
+		} else {
+			configuration.HTTP.Routers[name] = conf
 		}
+	}
 
-		for name, conf := range c.HTTP.Middlewares {
-			if _, exists := configuration.HTTP.Middlewares[name]; exists {
-				logger.WithField(log.MiddlewareName, name).Warn("HTTP middleware already configured, skipping")
-			} else {
-				configuration.HTTP.Middlewares[name] = conf
-			}
+	for name, conf := range c.HTTP.Middlewares {
+		if _, exists := configuration.HTTP.Middlewares[name]; exists {
+			logger.WithField(log.MiddlewareName, name).Warn("HTTP middleware already configured, skipping")
+		} else {
+			configuration.HTTP.Middlewares[name] = conf
 		}
+	}
 
-		for name, conf := range c.HTTP.Services {
-			if _, exists := configuration.HTTP.Services[name]; exists {
-				logger.WithField(log.ServiceName, name).Warn("HTTP service already configured, skipping")
-			} else {
-				configuration.HTTP.Services[name] = conf
-			}
+	for name, conf := range c.HTTP.Services {
+		if _, exists := configuration.HTTP.Services[name]; exists {
+			logger.WithField(log.ServiceName, name).Warn("HTTP service already configured, skipping")
+		} else {
+			configuration.HTTP.Services[name] = conf
 		}
+	}
 
-		for name, conf := range c.HTTP.ServersTransports {
-			if _, exists := configuration.HTTP.ServersTransports[name]; exists {
-				logger.WithField(log.ServersTransportName, name).Warn("HTTP servers transport already configured, skipping")
-			} else {
-				configuration.HTTP.ServersTransports[name] = conf
-			}
+	for name, conf := range c.HTTP.ServersTransports {
+		if _, exists := configuration.HTTP.ServersTransports[name]; exists {
+			logger.WithField(log.ServersTransportName, name).Warn("HTTP servers transport already configured, skipping")
+		} else {
+			configuration.HTTP.ServersTransports[name] = conf
 		}
+	}
 
-		for name, conf := range c.TCP.Routers {
-			if _, exists := configuration.TCP.Routers[name]; exists {
-				logger.WithField(log.RouterName, name).Warn("TCP router already configured, skipping")
-			} else {
-				configuration.TCP.Routers[name] = conf
-			}
+	for name, conf := range c.TCP.Routers {
+		if _, exists := configuration.TCP.Routers[name]; exists {
+			logger.WithField(log.RouterName, name).Warn("TCP router already configured, skipping")
+		} else {
+			configuration.TCP.Routers[name] = conf
 		}
+	}
 
-		for name, conf := range c.TCP.Middlewares {
-			if _, exists := configuration.TCP.Middlewares[name]; exists {
-				logger.WithField(log.MiddlewareName, name).Warn("TCP middleware already configured, skipping")
-			} else {
-				configuration.TCP.Middlewares[name] = conf
-			}
+	for name, conf := range c.TCP.Middlewares {
+		if _, exists := configuration.TCP.Middlewares[name]; exists {
+			logger.WithField(log.MiddlewareName, name).Warn("TCP middleware already configured, skipping")
+		} else {
+			configuration.TCP.Middlewares[name] = conf
 		}
+	}
 
-		for name, conf := range c.TCP.Services {
-			if _, exists := configuration.TCP.Services[name]; exists {
-				logger.WithField(log.ServiceName, name).Warn("TCP service already configured, skipping")
-			} else {
-				configuration.TCP.Services[name] = conf
-			}
+	for name, conf := range c.TCP.Services {
+		if _, exists := configuration.TCP.Services[name]; exists {
+			logger.WithField(log.ServiceName, name).Warn("TCP service already configured, skipping")
+		} else {
+			configuration.TCP.Services[name] = conf
 		}
+	}
 
-		for name, conf := range c.UDP.Routers {
-			if _, exists := configuration.UDP.Routers[name]; exists {
-				logger.WithField(log.RouterName, name).Warn("UDP router already configured, skipping")
-			} else {
-				configuration.UDP.Routers[name] = conf
-			}
+	for name, conf := range c.UDP.Routers {
+		if _, exists := configuration.UDP.Routers[name]; exists {
+			logger.WithField(log.RouterName, name).Warn("UDP router already configured, skipping")
+		} else {
+			configuration.UDP.Routers[name] = conf
 		}
+	}
 
-		for name, conf := range c.UDP.Services {
-			if _, exists := configuration.UDP.Services[name]; exists {
-				logger.WithField(log.ServiceName, name).Warn("UDP service already configured, skipping")
-			} else {
-				configuration.UDP.Services[name] = conf
-			}
+	for name, conf := range c.UDP.Services {
+		if _, exists := configuration.UDP.Services[name]; exists {
+			logger.WithField(log.ServiceName, name).Warn("UDP service already configured, skipping")
+		} else {
+			configuration.UDP.Services[name] = conf
 		}
+	}
 
-		for _, conf := range c.TLS.Certificates {
-			if _, exists := configTLSMaps[conf]; exists {
-				logger.Warnf("TLS configuration %v already configured, skipping", conf)
-			} else {
-				configTLSMaps[conf] = struct{}{}
-			}
+	for _, conf := range c.TLS.Certificates {
+		if _, exists := configTLSMaps[conf]; exists {
+			logger.Warnf("TLS configuration %v already configured, skipping", conf)
+		} else {
+			configTLSMaps[conf] = struct{}{}
 		}
+	}
 
-		for name, conf := range c.TLS.Options {
-			if _, exists := configuration.TLS.Options[name]; exists {
-				logger.Warnf("TLS options %v already configured, skipping", name)
-			} else {
-				if configuration.TLS.Options == nil {
-					configuration.TLS.Options = map[string]tls.Options{}
-				}
-				configuration.TLS.Options[name] = conf
+	for name, conf := range c.TLS.Options {
+		if _, exists := configuration.TLS.Options[name]; exists {
+			logger.Warnf("TLS options %v already configured, skipping", name)
+		} else {
+			if configuration.TLS.Options == nil {
+				configuration.TLS.Options = map[string]tls.Options{}
 			}
+			configuration.TLS.Options[name] = conf
 		}
+	}
 
-		for name, conf := range c.TLS.Stores {
-			if _, exists := configuration.TLS.Stores[name]; exists {
-				logger.Warnf("TLS store %v already configured, skipping", name)
-			} else {
-				if configuration.TLS.Stores == nil {
-					configuration.TLS.Stores = map[string]tls.Store{}
-				}
-				configuration.TLS.Stores[name] = conf
+	for name, conf := range c.TLS.Stores {
+		if _, exists := configuration.TLS.Stores[name]; exists {
+			logger.Warnf("TLS store %v already configured, skipping", name)
+		} else {
+			if configuration.TLS.Stores == nil {
+				configuration.TLS.Stores = map[string]tls.Store{}
 			}
+			configuration.TLS.Stores[name] = conf
 		}
 	}
+}
 
+// addCertificates adds the TLS certificates collected by mergeConfiguration to configuration.
+func addCertificates(configuration *dynamic.Configuration, configTLSMaps map[*tls.CertAndStores]struct{}) {
 	if len(configTLSMaps) > 0 && configuration.TLS == nil {
 		configuration.TLS = &dynamic.TLSConfiguration{}
 	}
@@ -361,15 +403,23 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 	for conf := range configTLSMaps {
 		configuration.TLS.Certificates = append(configuration.TLS.Certificates, conf)
 	}
-
-	return configuration, nil
 }
 
 // CreateConfiguration creates a provider configuration from content using templating.
 func (p *Provider) CreateConfiguration(ctx context.Context, filename string, funcMap template.FuncMap, templateObjects interface{}) (*dynamic.Configuration, error) {
+	renderedTemplate, err := p.renderTemplate(ctx, filename, funcMap, templateObjects)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.decodeConfiguration(filename, renderedTemplate)
+}
+
+// renderTemplate renders the template of the configuration file.
+func (p *Provider) renderTemplate(ctx context.Context, filename string, funcMap template.FuncMap, templateObjects interface{}) (string, error) {
 	tmplContent, err := readFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("error reading configuration file: %s - %w", filename, err)
+		return "", fmt.Errorf("error reading configuration file: %s - %w", filename, err)
 	}
 
 	defaultFuncMap := sprig.TxtFuncMap()
@@ -383,13 +433,13 @@ func (p *Provider) CreateConfiguration(ctx context.Context, filename string, fun
 
 	_, err = tmpl.Parse(tmplContent)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	var buffer bytes.Buffer
 	err = tmpl.Execute(&buffer, templateObjects)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	renderedTemplate := buffer.String()
@@ -399,7 +449,7 @@ func (p *Provider) CreateConfiguration(ctx context.Context, filename string, fun
 		logger.Debugf("Rendering results: %s", renderedTemplate)
 	}
 
-	return p.decodeConfiguration(filename, renderedTemplate)
+	return renderedTemplate, nil
 }
 
 // DecodeConfiguration Decodes a *types.Configuration from a content.
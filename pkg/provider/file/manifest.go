@@ -0,0 +1,125 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/tls"
+)
+
+// errInconsistentManifest is returned when the files listed in a manifest do not match their hash,
+// which is expected while they are being written.
+var errInconsistentManifest = errors.New("inconsistent manifest")
+
+// manifestEntry is a file listed in a manifest.
+type manifestEntry struct {
+	path string
+	hash string
+}
+
+// readManifest reads a manifest, in the format of the sha256sum command output:
+// one "<SHA-256 hash>  <path>" line per file, the paths being relative to the manifest directory.
+// Empty lines and lines starting with # are ignored.
+func readManifest(manifest string) ([]manifestEntry, error) {
+	content, err := os.ReadFile(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []manifestEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: invalid line %d of %s", errInconsistentManifest, line, manifest)
+		}
+
+		hash := strings.ToLower(fields[0])
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("%w: invalid SHA-256 hash on line %d of %s", errInconsistentManifest, line, manifest)
+		}
+
+		// The binary mode marker of sha256sum.
+		path := strings.TrimPrefix(fields[1], "*")
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(manifest), path)
+		}
+
+		entries = append(entries, manifestEntry{path: path, hash: hash})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// checkManifestEntries checks that the files listed in a manifest match their hash.
+func checkManifestEntries(entries []manifestEntry) error {
+	for _, entry := range entries {
+		content, err := os.ReadFile(entry.path)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errInconsistentManifest, err)
+		}
+
+		sum := sha256.Sum256(content)
+		if hex.EncodeToString(sum[:]) != entry.hash {
+			return fmt.Errorf("%w: %s does not match its hash", errInconsistentManifest, entry.path)
+		}
+	}
+
+	return nil
+}
+
+// loadFileConfigFromManifest loads the configuration from the files listed in the manifest,
+// only if they all match their hash in the manifest, before and after being loaded.
+func (p *Provider) loadFileConfigFromManifest(ctx context.Context, manifest string) (*dynamic.Configuration, error) {
+	entries, err := readManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkManifestEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := newConfiguration()
+	configTLSMaps := make(map[*tls.CertAndStores]struct{})
+
+	for _, entry := range entries {
+		var c *dynamic.Configuration
+		c, err = p.loadFileConfig(ctx, entry.path, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.path, err)
+		}
+
+		mergeConfiguration(ctx, entry.path, configuration, c, configTLSMaps)
+	}
+
+	// A file could have been changed while the configuration was loaded.
+	err = checkManifestEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	addCertificates(configuration, configTLSMaps)
+
+	return configuration, nil
+}
@@ -0,0 +1,163 @@
+package file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+func TestLoadFileConfigFromManifest(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		manifest           func(t *testing.T, dir string) string
+		expectedError      bool
+		expectedNumRouter  int
+		expectedNumService int
+	}{
+		{
+			desc: "consistent files",
+			manifest: func(t *testing.T, dir string) string {
+				t.Helper()
+
+				return manifestLine(t, dir, "dir01_file01.toml") + manifestLine(t, dir, "dir01_file02.toml")
+			},
+			expectedNumRouter:  2,
+			expectedNumService: 3,
+		},
+		{
+			desc: "comments, empty lines and binary mode marker",
+			manifest: func(t *testing.T, dir string) string {
+				t.Helper()
+
+				line := manifestLine(t, dir, "dir01_file02.toml")
+				return "# generated\n\n" + line[:66] + "*" + line[66:]
+			},
+			expectedNumService: 3,
+		},
+		{
+			desc: "only the listed files are loaded",
+			manifest: func(t *testing.T, dir string) string {
+				t.Helper()
+
+				return manifestLine(t, dir, "dir01_file01.toml")
+			},
+			expectedNumRouter: 2,
+		},
+		{
+			desc: "file not matching its hash",
+			manifest: func(t *testing.T, dir string) string {
+				t.Helper()
+
+				line := manifestLine(t, dir, "dir01_file01.toml")
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "dir01_file01.toml"), []byte("[http.routers]\n"), 0o644))
+				return line
+			},
+			expectedError: true,
+		},
+		{
+			desc: "missing file",
+			manifest: func(t *testing.T, dir string) string {
+				t.Helper()
+
+				return fmt.Sprintf("%x  missing.toml\n", sha256.Sum256(nil))
+			},
+			expectedError: true,
+		},
+		{
+			desc: "invalid hash",
+			manifest: func(t *testing.T, dir string) string {
+				t.Helper()
+
+				return "foo  dir01_file01.toml\n"
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			require.NoError(t, copyFile("./fixtures/toml/dir01_file01.toml", filepath.Join(dir, "dir01_file01.toml")))
+			require.NoError(t, copyFile("./fixtures/toml/dir01_file02.toml", filepath.Join(dir, "dir01_file02.toml")))
+
+			manifest := filepath.Join(dir, "manifest")
+			require.NoError(t, os.WriteFile(manifest, []byte(test.manifest(t, dir)), 0o644))
+
+			provider := &Provider{Manifest: manifest}
+			configuration, err := provider.BuildConfiguration()
+
+			if test.expectedError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, errInconsistentManifest)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, configuration.HTTP.Routers, test.expectedNumRouter)
+			assert.Len(t, configuration.HTTP.Services, test.expectedNumService)
+		})
+	}
+}
+
+func TestProvideWithManifest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, copyFile("./fixtures/toml/dir01_file01.toml", filepath.Join(dir, "dir01_file01.toml")))
+	require.NoError(t, copyFile("./fixtures/toml/dir01_file02.toml", filepath.Join(dir, "dir01_file02.toml")))
+
+	// The manifest is written before the files it lists.
+	manifest := filepath.Join(dir, "manifest")
+	content := manifestLine(t, dir, "dir01_file01.toml") + manifestLine(t, dir, "dir01_file02.toml")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dir01_file02.toml"), []byte("[http.services]\n"), 0o644))
+	require.NoError(t, os.WriteFile(manifest, []byte(content), 0o644))
+
+	provider := &Provider{Manifest: manifest, Watch: true}
+	configChan := make(chan dynamic.Message)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		err := provider.Provide(configChan, safe.NewPool(ctx))
+		assert.NoError(t, err)
+	}()
+
+	select {
+	case <-configChan:
+		t.Fatal("The configuration should not be provided while the files are inconsistent")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	require.NoError(t, copyFile("./fixtures/toml/dir01_file02.toml", filepath.Join(dir, "dir01_file02.toml")))
+
+	select {
+	case conf := <-configChan:
+		assert.Len(t, conf.Configuration.HTTP.Routers, 2)
+		assert.Len(t, conf.Configuration.HTTP.Services, 3)
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for config")
+	}
+}
+
+func manifestLine(t *testing.T, dir, filename string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(dir, filename))
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:]) + "  " + filename + "\n"
+}
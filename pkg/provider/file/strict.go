@@ -0,0 +1,274 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/BurntSushi/toml"
+	"github.com/traefik/paerser/parser"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"gopkg.in/yaml.v3"
+)
+
+// Check loads the configuration files of the provider, and returns the problems found, with their location:
+// the unknown keys, the deprecated options, and the references to the middlewares missing from the configuration of the provider.
+func (p *Provider) Check() ([]string, error) {
+	ctx := log.With(context.Background(), log.Str(log.ProviderName, providerName))
+
+	filenames, err := p.configurationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+
+	middlewares := make(map[string]struct{})
+	tcpMiddlewares := make(map[string]struct{})
+
+	// references holds the locations of the references to the middlewares, by middleware name.
+	references := make(map[string][]string)
+	tcpReferences := make(map[string][]string)
+
+	for _, filename := range filenames {
+		content, err := p.renderTemplate(ctx, filename, template.FuncMap{}, false)
+		if err != nil {
+			return nil, err
+		}
+
+		configuration, err := p.decodeConfiguration(filename, content)
+		if err != nil {
+			unknown, unknownErr := unknownKeys(content, strings.ToLower(filepath.Ext(filename)), reflect.TypeOf(dynamic.Configuration{}))
+			if unknownErr != nil || len(unknown) == 0 {
+				problems = append(problems, fmt.Sprintf("%s: %v", filename, err))
+				continue
+			}
+
+			for _, key := range unknown {
+				problems = append(problems, fmt.Sprintf("%s: %s: unknown key", filename, key))
+			}
+			continue
+		}
+
+		for name, middleware := range configuration.HTTP.Middlewares {
+			middlewares[name] = struct{}{}
+
+			if middleware.Headers != nil {
+				for _, deprecation := range middleware.Headers.Deprecations() {
+					problems = append(problems, fmt.Sprintf("%s: http.middlewares.%s.headers.%s: deprecated option, please use %s instead", filename, name, deprecation.Option, deprecation.Replacement))
+				}
+			}
+
+			if middleware.Chain != nil {
+				for _, ref := range middleware.Chain.Middlewares {
+					references[ref] = append(references[ref], fmt.Sprintf("%s: http.middlewares.%s.chain.middlewares", filename, name))
+				}
+			}
+		}
+
+		for name, router := range configuration.HTTP.Routers {
+			for _, ref := range router.Middlewares {
+				references[ref] = append(references[ref], fmt.Sprintf("%s: http.routers.%s.middlewares", filename, name))
+			}
+		}
+
+		for name := range configuration.TCP.Middlewares {
+			tcpMiddlewares[name] = struct{}{}
+		}
+
+		for name, router := range configuration.TCP.Routers {
+			for _, ref := range router.Middlewares {
+				tcpReferences[ref] = append(tcpReferences[ref], fmt.Sprintf("%s: tcp.routers.%s.middlewares", filename, name))
+			}
+		}
+	}
+
+	problems = append(problems, missingReferences(references, middlewares)...)
+	problems = append(problems, missingReferences(tcpReferences, tcpMiddlewares)...)
+
+	sort.Strings(problems)
+
+	return problems, nil
+}
+
+// missingReferences returns the references to the middlewares of the provider which are not defined.
+// The references to the middlewares of the other providers cannot be checked, and are ignored.
+func missingReferences(references map[string][]string, middlewares map[string]struct{}) []string {
+	var problems []string
+
+	for ref, locations := range references {
+		name := ref
+		if i := strings.LastIndex(ref, "@"); i >= 0 {
+			if ref[i+1:] != providerName {
+				continue
+			}
+			name = ref[:i]
+		}
+
+		if _, ok := middlewares[name]; ok {
+			continue
+		}
+
+		for _, location := range locations {
+			problems = append(problems, fmt.Sprintf("%s: middleware %q does not exist", location, ref))
+		}
+	}
+
+	return problems
+}
+
+// configurationFiles returns the configuration files of the provider.
+func (p *Provider) configurationFiles() ([]string, error) {
+	if len(p.Directory) > 0 {
+		var filenames []string
+		err := filepath.Walk(p.Directory, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".toml", ".yaml", ".yml":
+				if !info.IsDir() {
+					filenames = append(filenames, path)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to read directory %s: %w", p.Directory, err)
+		}
+
+		return filenames, nil
+	}
+
+	if len(p.Filename) > 0 {
+		return []string{p.Filename}, nil
+	}
+
+	if len(p.Manifest) > 0 {
+		entries, err := readManifest(p.Manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		var filenames []string
+		for _, entry := range entries {
+			filenames = append(filenames, entry.path)
+		}
+
+		return filenames, nil
+	}
+
+	return nil, nil
+}
+
+// unknownKeys returns the paths of the keys of the configuration file content which match no field of the given type,
+// the fields being matched like the file decoder does, by their case-insensitive name.
+func unknownKeys(content, extension string, rType reflect.Type) ([]string, error) {
+	data := make(map[string]interface{})
+
+	switch extension {
+	case ".toml":
+		if _, err := toml.Decode(content, &data); err != nil {
+			return nil, err
+		}
+	case ".yml", ".yaml":
+		if err := yaml.Unmarshal([]byte(content), &data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file extension: %s", extension)
+	}
+
+	keys := unknownKeysIn(data, rType, "")
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+func unknownKeysIn(value interface{}, rType reflect.Type, path string) []string {
+	for rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+
+	var keys []string
+
+	switch rType.Kind() {
+	case reflect.Struct:
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		for key, child := range data {
+			field, ok := findField(rType, key)
+			if !ok {
+				keys = append(keys, joinPath(path, key))
+				continue
+			}
+
+			keys = append(keys, unknownKeysIn(child, field.Type, joinPath(path, key))...)
+		}
+
+	case reflect.Map:
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		for key, child := range data {
+			keys = append(keys, unknownKeysIn(child, rType.Elem(), joinPath(path, key))...)
+		}
+
+	case reflect.Slice:
+		switch items := value.(type) {
+		case []interface{}:
+			for i, item := range items {
+				keys = append(keys, unknownKeysIn(item, rType.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		case []map[string]interface{}:
+			for i, item := range items {
+				keys = append(keys, unknownKeysIn(item, rType.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return keys
+}
+
+// findField finds the exported field matching the key, including the fields of the embedded structs.
+func findField(rType reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < rType.NumField(); i++ {
+		field := rType.Field(i)
+		if !parser.IsExported(field) {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if embedded, ok := findField(field.Type, key); ok {
+				return embedded, true
+			}
+		}
+
+		if strings.EqualFold(field.Name, key) {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
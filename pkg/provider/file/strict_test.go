@@ -0,0 +1,158 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_Check(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		files    map[string]string
+		expected []string
+	}{
+		{
+			desc: "valid configuration",
+			files: map[string]string{
+				"dynamic.yaml": `
+http:
+  routers:
+    foo:
+      rule: Host(` + "`foo`" + `)
+      service: foo
+      middlewares:
+        - bar
+        - bar@file
+        - baz@docker
+  middlewares:
+    bar:
+      headers:
+        frameDeny: true
+  services:
+    foo:
+      loadBalancer:
+        servers:
+          - url: http://127.0.0.1
+`,
+			},
+		},
+		{
+			desc: "unknown keys in YAML",
+			files: map[string]string{
+				"dynamic.yaml": `
+http:
+  routers:
+    foo:
+      rul: Host(` + "`foo`" + `)
+      service: foo
+  services:
+    foo:
+      loadBalancer:
+        servers:
+          - ulr: http://127.0.0.1
+`,
+			},
+			expected: []string{
+				"dynamic.yaml: http.routers.foo.rul: unknown key",
+				"dynamic.yaml: http.services.foo.loadBalancer.servers[0].ulr: unknown key",
+			},
+		},
+		{
+			desc: "unknown keys in TOML",
+			files: map[string]string{
+				"dynamic.toml": `
+[http.routers.foo]
+  rul = "Host(` + "`foo`" + `)"
+  service = "foo"
+
+[[http.services.foo.loadBalancer.servers]]
+  ulr = "http://127.0.0.1"
+`,
+			},
+			expected: []string{
+				"dynamic.toml: http.routers.foo.rul: unknown key",
+				"dynamic.toml: http.services.foo.loadBalancer.servers[0].ulr: unknown key",
+			},
+		},
+		{
+			desc: "deprecated options",
+			files: map[string]string{
+				"dynamic.yaml": `
+http:
+  middlewares:
+    foo:
+      headers:
+        sslRedirect: true
+        featurePolicy: foo
+`,
+			},
+			expected: []string{
+				"dynamic.yaml: http.middlewares.foo.headers.featurePolicy: deprecated option, please use PermissionsPolicy header instead",
+				"dynamic.yaml: http.middlewares.foo.headers.sslRedirect: deprecated option, please use entrypoint redirection instead",
+			},
+		},
+		{
+			desc: "missing middlewares across files",
+			files: map[string]string{
+				"routers.yaml": `
+http:
+  routers:
+    foo:
+      rule: Host(` + "`foo`" + `)
+      service: foo
+      middlewares:
+        - bar
+        - typo@file
+tcp:
+  routers:
+    foo:
+      rule: HostSNI(` + "`*`" + `)
+      service: foo
+      middlewares:
+        - baz
+`,
+				"middlewares.yaml": `
+http:
+  middlewares:
+    bar:
+      chain:
+        middlewares:
+          - missing
+`,
+			},
+			expected: []string{
+				`middlewares.yaml: http.middlewares.bar.chain.middlewares: middleware "missing" does not exist`,
+				`routers.yaml: http.routers.foo.middlewares: middleware "typo@file" does not exist`,
+				`routers.yaml: tcp.routers.foo.middlewares: middleware "baz" does not exist`,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			directory := t.TempDir()
+			for name, content := range test.files {
+				err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0o600)
+				require.NoError(t, err)
+			}
+
+			provider := &Provider{Directory: directory}
+
+			problems, err := provider.Check()
+			require.NoError(t, err)
+
+			for i, problem := range problems {
+				problems[i] = problem[len(directory)+1:]
+			}
+
+			assert.Equal(t, test.expected, problems)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Health is the liveness of a provider.
+type Health struct {
+	// LastRefresh is the date of the last successful refresh of the configuration of the provider.
+	LastRefresh *time.Time `json:"lastRefresh,omitempty"`
+	// LastError is the last error of the provider, e.g. a connection error.
+	LastError string `json:"lastError,omitempty"`
+	// LastErrorDate is the date of the last error of the provider.
+	LastErrorDate *time.Time `json:"lastErrorDate,omitempty"`
+	// ConsecutiveFailures is the number of failures of the provider since its last successful refresh.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// FailingSince is the date of the first of the consecutive failures of the provider.
+	FailingSince *time.Time `json:"failingSince,omitempty"`
+}
+
+// HealthRegistry tracks the liveness of the providers.
+type HealthRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]*Health
+	now       func() time.Time
+}
+
+// NewHealthRegistry creates a new HealthRegistry.
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		providers: make(map[string]*Health),
+		now:       time.Now,
+	}
+}
+
+var healthRegistry = NewHealthRegistry()
+
+// ReportRefresh records a successful refresh of the configuration of the provider, e.g. a configuration sent.
+func ReportRefresh(providerName string) {
+	healthRegistry.ReportRefresh(providerName)
+}
+
+// ReportFailure records a failure of the provider, e.g. a connection error.
+func ReportFailure(providerName string, err error) {
+	healthRegistry.ReportFailure(providerName, err)
+}
+
+// ProvidersHealth returns the liveness of the providers which reported a refresh or a failure, by name.
+func ProvidersHealth() map[string]Health {
+	return healthRegistry.Health()
+}
+
+// DeadProviders returns the names of the providers failing continuously for longer than the given duration.
+func DeadProviders(threshold time.Duration) []string {
+	return healthRegistry.DeadProviders(threshold)
+}
+
+// ReportRefresh records a successful refresh of the configuration of the provider.
+func (r *HealthRegistry) ReportRefresh(providerName string) {
+	if providerName == "" {
+		return
+	}
+
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	health := r.get(providerName)
+	health.LastRefresh = &now
+	health.ConsecutiveFailures = 0
+	health.FailingSince = nil
+}
+
+// ReportFailure records a failure of the provider.
+func (r *HealthRegistry) ReportFailure(providerName string, err error) {
+	if providerName == "" || err == nil {
+		return
+	}
+
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	health := r.get(providerName)
+	health.LastError = err.Error()
+	health.LastErrorDate = &now
+	health.ConsecutiveFailures++
+	if health.FailingSince == nil {
+		health.FailingSince = &now
+	}
+}
+
+// Health returns the liveness of the providers, by name.
+func (r *HealthRegistry) Health() map[string]Health {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	providers := make(map[string]Health, len(r.providers))
+	for name, health := range r.providers {
+		providers[name] = *health
+	}
+
+	return providers
+}
+
+// DeadProviders returns the names of the providers failing continuously for longer than the given duration, sorted.
+func (r *HealthRegistry) DeadProviders(threshold time.Duration) []string {
+	now := r.now()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var dead []string
+	for name, health := range r.providers {
+		if health.FailingSince != nil && now.Sub(*health.FailingSince) >= threshold {
+			dead = append(dead, name)
+		}
+	}
+
+	sort.Strings(dead)
+
+	return dead
+}
+
+func (r *HealthRegistry) get(providerName string) *Health {
+	health, ok := r.providers[providerName]
+	if !ok {
+		health = &Health{}
+		r.providers[providerName] = health
+	}
+
+	return health
+}
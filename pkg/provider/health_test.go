@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthRegistry(t *testing.T) {
+	now := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	registry := NewHealthRegistry()
+	registry.now = func() time.Time { return now }
+
+	registry.ReportRefresh("file")
+	registry.ReportRefresh("docker")
+
+	now = now.Add(time.Minute)
+	registry.ReportFailure("docker", errors.New("connection refused"))
+	failingSince := now
+
+	now = now.Add(time.Minute)
+	registry.ReportFailure("docker", errors.New("connection reset"))
+
+	health := registry.Health()
+	require.Len(t, health, 2)
+	assert.Equal(t, 0, health["file"].ConsecutiveFailures)
+	assert.Nil(t, health["file"].FailingSince)
+
+	docker := health["docker"]
+	assert.Equal(t, 2, docker.ConsecutiveFailures)
+	assert.Equal(t, "connection reset", docker.LastError)
+	assert.Equal(t, now, *docker.LastErrorDate)
+	require.NotNil(t, docker.FailingSince)
+	assert.Equal(t, failingSince, *docker.FailingSince)
+
+	assert.Equal(t, []string{"docker"}, registry.DeadProviders(time.Minute))
+	assert.Empty(t, registry.DeadProviders(5*time.Minute))
+
+	now = now.Add(time.Minute)
+	registry.ReportRefresh("docker")
+
+	docker = registry.Health()["docker"]
+	assert.Equal(t, 0, docker.ConsecutiveFailures)
+	assert.Nil(t, docker.FailingSince)
+	assert.Equal(t, "connection reset", docker.LastError)
+	assert.Equal(t, now, *docker.LastRefresh)
+
+	assert.Empty(t, registry.DeadProviders(time.Minute))
+}
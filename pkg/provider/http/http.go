@@ -2,10 +2,14 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"io"
+	"mime"
 	"net/http"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -22,14 +26,23 @@ import (
 
 var _ provider.Provider = (*Provider)(nil)
 
+// errNotModified is returned when the configuration has not been modified since the last fetch.
+var errNotModified = errors.New("configuration not modified")
+
 // Provider is a provider.Provider implementation that queries an HTTP(s) endpoint for a configuration.
 type Provider struct {
-	Endpoint              string           `description:"Load configuration from this endpoint." json:"endpoint" toml:"endpoint" yaml:"endpoint"`
-	PollInterval          ptypes.Duration  `description:"Polling interval for endpoint." json:"pollInterval,omitempty" toml:"pollInterval,omitempty" yaml:"pollInterval,omitempty" export:"true"`
-	PollTimeout           ptypes.Duration  `description:"Polling timeout for endpoint." json:"pollTimeout,omitempty" toml:"pollTimeout,omitempty" yaml:"pollTimeout,omitempty" export:"true"`
-	TLS                   *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Endpoint              string            `description:"Load configuration from this endpoint." json:"endpoint" toml:"endpoint" yaml:"endpoint"`
+	PollInterval          ptypes.Duration   `description:"Polling interval for endpoint." json:"pollInterval,omitempty" toml:"pollInterval,omitempty" yaml:"pollInterval,omitempty" export:"true"`
+	PollTimeout           ptypes.Duration   `description:"Polling timeout for endpoint." json:"pollTimeout,omitempty" toml:"pollTimeout,omitempty" yaml:"pollTimeout,omitempty" export:"true"`
+	TLS                   *types.ClientTLS  `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	Headers               map[string]string `description:"Define custom headers to be sent to the endpoint." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
 	httpClient            *http.Client
+	proxy                 *types.Proxy
 	lastConfigurationHash uint64
+	// lastETag and lastModified are the validators of the last fetched configuration,
+	// sent with the next requests for the endpoint to answer that the configuration has not been modified.
+	lastETag     string
+	lastModified string
 }
 
 // SetDefaults sets the default values.
@@ -52,20 +65,29 @@ func (p *Provider) Init() error {
 		Timeout: time.Duration(p.PollTimeout),
 	}
 
-	if p.TLS != nil {
-		tlsConfig, err := p.TLS.CreateTLSConfig(context.Background())
-		if err != nil {
-			return fmt.Errorf("unable to create TLS configuration: %w", err)
-		}
+	if p.TLS != nil || p.proxy != nil {
+		transport := &http.Transport{}
+
+		if p.TLS != nil {
+			tlsConfig, err := p.TLS.CreateTLSConfig(context.Background())
+			if err != nil {
+				return fmt.Errorf("unable to create TLS configuration: %w", err)
+			}
 
-		p.httpClient.Transport = &http.Transport{
-			TLSClientConfig: tlsConfig,
+			transport.TLSClientConfig = tlsConfig
 		}
+
+		p.httpClient.Transport = p.proxy.WrapTransport(transport)
 	}
 
 	return nil
 }
 
+// SetProxy sets the upstream proxy used to reach the endpoint.
+func (p *Provider) SetProxy(proxy *types.Proxy) {
+	p.proxy = proxy
+}
+
 // Provide allows the provider to provide configurations to traefik using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
 	pool.GoCtx(func(routineCtx context.Context) {
@@ -79,7 +101,12 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 			for {
 				select {
 				case <-ticker.C:
-					configData, err := p.fetchConfigurationData()
+					configData, extension, err := p.fetchConfigurationData()
+					if errors.Is(err, errNotModified) {
+						// Keep-alive telling that the endpoint is still reachable.
+						configurationChan <- dynamic.Message{ProviderName: "http"}
+						continue
+					}
 					if err != nil {
 						return fmt.Errorf("cannot fetch configuration data: %w", err)
 					}
@@ -93,12 +120,14 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 					hash := fnvHasher.Sum64()
 					if hash == p.lastConfigurationHash {
+						// Keep-alive telling that the endpoint is still reachable.
+						configurationChan <- dynamic.Message{ProviderName: "http"}
 						continue
 					}
 
 					p.lastConfigurationHash = hash
 
-					configuration, err := decodeConfiguration(configData)
+					configuration, err := decodeConfiguration(configData, extension)
 					if err != nil {
 						return fmt.Errorf("cannot decode configuration data: %w", err)
 					}
@@ -116,6 +145,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 		notify := func(err error, time time.Duration) {
 			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+			provider.ReportFailure("http", err)
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
 		if err != nil {
@@ -126,24 +156,79 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	return nil
 }
 
-// fetchConfigurationData fetches the configuration data from the configured endpoint.
-func (p *Provider) fetchConfigurationData() ([]byte, error) {
-	res, err := p.httpClient.Get(p.Endpoint)
+// fetchConfigurationData fetches the configuration data from the configured endpoint,
+// along with the extension of its format.
+// It returns errNotModified if the endpoint answers that the configuration has not been modified since the last fetch.
+func (p *Provider) fetchConfigurationData() ([]byte, string, error) {
+	req, err := http.NewRequest(http.MethodGet, p.Endpoint, nil)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	for name, value := range p.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if p.lastETag != "" {
+		req.Header.Set("If-None-Match", p.lastETag)
+	}
+	if p.lastModified != "" {
+		req.Header.Set("If-Modified-Since", p.lastModified)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
 	}
 
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotModified {
+		return nil, "", errNotModified
+	}
+
 	if res.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received non-ok response code: %d", res.StatusCode)
+		return nil, "", fmt.Errorf("received non-ok response code: %d", res.StatusCode)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	p.lastETag = res.Header.Get("ETag")
+	p.lastModified = res.Header.Get("Last-Modified")
+
+	return data, formatExtension(res), nil
+}
+
+// formatExtension returns the extension of the format of the configuration data,
+// from the content type of the response, or else from the path of the endpoint.
+// It defaults to YAML, which is a superset of JSON.
+func formatExtension(res *http.Response) string {
+	if mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type")); err == nil {
+		switch {
+		case strings.HasSuffix(mediaType, "toml"):
+			return ".toml"
+		case strings.HasSuffix(mediaType, "json"):
+			return ".json"
+		case strings.HasSuffix(mediaType, "yaml"):
+			return ".yaml"
+		}
+	}
+
+	if res.Request != nil {
+		switch ext := path.Ext(res.Request.URL.Path); ext {
+		case ".toml", ".json", ".yml", ".yaml":
+			return ext
+		}
 	}
 
-	return io.ReadAll(res.Body)
+	return ".yaml"
 }
 
 // decodeConfiguration decodes and returns the dynamic configuration from the given data.
-func decodeConfiguration(data []byte) (*dynamic.Configuration, error) {
+func decodeConfiguration(data []byte, extension string) (*dynamic.Configuration, error) {
 	configuration := &dynamic.Configuration{
 		HTTP: &dynamic.HTTPConfiguration{
 			Routers:           make(map[string]*dynamic.Router),
@@ -165,7 +250,7 @@ func decodeConfiguration(data []byte) (*dynamic.Configuration, error) {
 		},
 	}
 
-	err := file.DecodeContent(string(data), ".yaml", configuration)
+	err := file.DecodeContent(string(data), extension, configuration)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +105,7 @@ func TestProvider_fetchConfigurationData(t *testing.T) {
 			err := provider.Init()
 			require.NoError(t, err)
 
-			configData, err := provider.fetchConfigurationData()
+			configData, _, err := provider.fetchConfigurationData()
 			if test.expErr {
 				require.Error(t, err)
 				return
@@ -117,6 +117,103 @@ func TestProvider_fetchConfigurationData(t *testing.T) {
 	}
 }
 
+func TestProvider_fetchConfigurationData_conditionalRequests(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			assert.Equal(t, "Wed, 21 Oct 2015 07:28:00 GMT", req.Header.Get("If-Modified-Since"))
+
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		rw.Header().Set("ETag", `"v1"`)
+		rw.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		rw.Header().Set("Content-Type", "application/toml")
+		_, _ = fmt.Fprint(rw, "[http]")
+	}))
+	defer server.Close()
+
+	provider := Provider{
+		Endpoint:     server.URL,
+		PollInterval: ptypes.Duration(1 * time.Second),
+		PollTimeout:  ptypes.Duration(1 * time.Second),
+		Headers:      map[string]string{"Authorization": "Bearer token"},
+	}
+
+	err := provider.Init()
+	require.NoError(t, err)
+
+	configData, extension, err := provider.fetchConfigurationData()
+	require.NoError(t, err)
+
+	assert.Equal(t, []byte("[http]"), configData)
+	assert.Equal(t, ".toml", extension)
+
+	_, _, err = provider.fetchConfigurationData()
+	assert.ErrorIs(t, err, errNotModified)
+
+	assert.Equal(t, 2, requests)
+}
+
+func TestProvider_formatExtension(t *testing.T) {
+	tests := []struct {
+		desc         string
+		contentType  string
+		endpoint     string
+		expExtension string
+	}{
+		{
+			desc:         "TOML content type",
+			contentType:  "application/toml; charset=utf-8",
+			endpoint:     "http://localhost:8080/config",
+			expExtension: ".toml",
+		},
+		{
+			desc:         "JSON content type",
+			contentType:  "application/json",
+			endpoint:     "http://localhost:8080/config.toml",
+			expExtension: ".json",
+		},
+		{
+			desc:         "YAML content type",
+			contentType:  "application/x-yaml",
+			endpoint:     "http://localhost:8080/config",
+			expExtension: ".yaml",
+		},
+		{
+			desc:         "extension of the endpoint",
+			contentType:  "text/plain",
+			endpoint:     "http://localhost:8080/config.toml?version=2",
+			expExtension: ".toml",
+		},
+		{
+			desc:         "default",
+			endpoint:     "http://localhost:8080/config",
+			expExtension: ".yaml",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, test.endpoint, nil)
+			res := &http.Response{Header: http.Header{}, Request: req}
+			if test.contentType != "" {
+				res.Header.Set("Content-Type", test.contentType)
+			}
+
+			assert.Equal(t, test.expExtension, formatExtension(res))
+		})
+	}
+}
+
 func TestProvider_decodeConfiguration(t *testing.T) {
 	tests := []struct {
 		desc       string
@@ -159,7 +256,7 @@ func TestProvider_decodeConfiguration(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
-			configuration, err := decodeConfiguration(test.configData)
+			configuration, err := decodeConfiguration(test.configData, ".yaml")
 			if test.expErr {
 				require.Error(t, err)
 				return
@@ -251,5 +348,15 @@ func TestProvider_ProvideConfigurationOnlyOnceIfUnchanged(t *testing.T) {
 
 	time.Sleep(time.Second)
 
-	assert.Equal(t, 1, len(configurationChan))
+	var configurations, keepAlives int
+	for len(configurationChan) > 0 {
+		if message := <-configurationChan; message.Configuration != nil {
+			configurations++
+		} else {
+			keepAlives++
+		}
+	}
+
+	assert.Equal(t, 1, configurations)
+	assert.NotZero(t, keepAlives)
 }
@@ -0,0 +1,295 @@
+package crd
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/generated/clientset/versioned/scheme"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the Kubernetes Events emitted on the IngressRoutes.
+const (
+	eventReasonServerDown     = "ServerDown"
+	eventReasonServerUp       = "ServerUp"
+	eventReasonRouterDisabled = "RouterDisabled"
+)
+
+const statusUpdateTimeout = 10 * time.Second
+
+// statusUpdater updates the status of an IngressRoute with the status of the given object.
+type statusUpdater func(ctx context.Context, ingressRoute *v1alpha1.IngressRoute) error
+
+// statusReporter emits Kubernetes Events on the IngressRoutes, and updates their status,
+// when the servers of their services go down or come back up, or when their routers are disabled because of errors.
+type statusReporter struct {
+	recorder     record.EventRecorder
+	updateStatus statusUpdater
+
+	mu                   sync.Mutex
+	disabledRouters      map[string]struct{}
+	serviceIngressRoutes map[string][]*v1alpha1.IngressRoute
+}
+
+func newStatusReporter(recorder record.EventRecorder, updateStatus statusUpdater) *statusReporter {
+	return &statusReporter{
+		recorder:             recorder,
+		updateStatus:         updateStatus,
+		disabledRouters:      make(map[string]struct{}),
+		serviceIngressRoutes: make(map[string][]*v1alpha1.IngressRoute),
+	}
+}
+
+// newClientStatusReporter returns a statusReporter emitting the Events and updating the statuses with the given client.
+func newClientStatusReporter(client *clientWrapper) *statusReporter {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.csKube.CoreV1().Events("")})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "traefik"})
+
+	updateStatus := func(ctx context.Context, ingressRoute *v1alpha1.IngressRoute) error {
+		// The routers are always set, so that a merge patch removes the routers which are not reported anymore.
+		data, err := json.Marshal(map[string]interface{}{
+			"status": map[string]interface{}{"routers": ingressRoute.Status.Routers},
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = client.csCrd.TraefikV1alpha1().IngressRoutes(ingressRoute.Namespace).
+			Patch(ctx, ingressRoute.Name, types.MergePatchType, data, metav1.PatchOptions{}, "status")
+		return err
+	}
+
+	return newStatusReporter(recorder, updateStatus)
+}
+
+// ServerStatusChanged emits an Event on the IngressRoutes using the service, when a server of the service goes down or comes back up.
+// It does nothing unless the Events are enabled.
+func (p *Provider) ServerStatusChanged(serviceName, serverURL string, up bool) {
+	reporter, ok := p.statusReporter.Get().(*statusReporter)
+	if !ok {
+		return
+	}
+
+	reporter.serverStatusChanged(serviceName, serverURL, up)
+}
+
+// UpdateRuntimeStatus emits an Event on the IngressRoutes whose routers got disabled because of errors,
+// and updates the status of the IngressRoutes with the status of their routers.
+// It does nothing unless the Events are enabled.
+func (p *Provider) UpdateRuntimeStatus(rtConf *runtime.Configuration) {
+	reporter, ok := p.statusReporter.Get().(*statusReporter)
+	if !ok {
+		return
+	}
+
+	ingressRoutes, _ := p.routerIngressRoutes.Get().(map[string]*v1alpha1.IngressRoute)
+
+	reporter.update(rtConf, ingressRoutes)
+}
+
+func (r *statusReporter) serverStatusChanged(serviceName, serverURL string, up bool) {
+	r.mu.Lock()
+	ingressRoutes := r.serviceIngressRoutes[serviceName]
+	r.mu.Unlock()
+
+	for _, ingressRoute := range ingressRoutes {
+		if up {
+			r.recorder.Eventf(ingressRoute, corev1.EventTypeNormal, eventReasonServerUp, "Server %s of service %s is up", serverURL, serviceName)
+		} else {
+			r.recorder.Eventf(ingressRoute, corev1.EventTypeWarning, eventReasonServerDown, "Server %s of service %s is down", serverURL, serviceName)
+		}
+	}
+}
+
+// update reports the status of the routers of the runtime configuration built from the given IngressRoutes, by router key.
+func (r *statusReporter) update(rtConf *runtime.Configuration, ingressRoutes map[string]*v1alpha1.IngressRoute) {
+	var routerNames []string
+	for name := range rtConf.Routers {
+		routerNames = append(routerNames, name)
+	}
+	sort.Strings(routerNames)
+
+	statuses := make(map[string]*v1alpha1.IngressRouteStatus)
+	keyIngressRoutes := make(map[string]*v1alpha1.IngressRoute)
+	routersIngressRoutes := make(map[string]*v1alpha1.IngressRoute)
+	disabledRouters := make(map[string]struct{})
+
+	r.mu.Lock()
+	previouslyDisabled := r.disabledRouters
+	r.mu.Unlock()
+
+	for _, name := range routerNames {
+		ingressRoute, ok := ingressRoutes[strings.TrimSuffix(name, providerNamespaceSeparator+providerName)]
+		if !ok || !strings.HasSuffix(name, providerNamespaceSeparator+providerName) {
+			continue
+		}
+
+		routerInfo := rtConf.Routers[name]
+		routersIngressRoutes[name] = ingressRoute
+
+		key := ingressRoute.Namespace + "/" + ingressRoute.Name
+		if statuses[key] == nil {
+			statuses[key] = &v1alpha1.IngressRouteStatus{}
+			keyIngressRoutes[key] = ingressRoute
+		}
+
+		statuses[key].Routers = append(statuses[key].Routers, v1alpha1.RouterStatus{
+			Name:   name,
+			Status: routerInfo.Status,
+			Errors: routerInfo.Err,
+		})
+
+		if routerInfo.Status != runtime.StatusDisabled {
+			continue
+		}
+
+		disabledRouters[name] = struct{}{}
+		if _, ok := previouslyDisabled[name]; !ok {
+			r.recorder.Eventf(ingressRoute, corev1.EventTypeWarning, eventReasonRouterDisabled, "Router %s is disabled: %s", name, strings.Join(routerInfo.Err, ", "))
+		}
+	}
+
+	serviceIngressRoutes := make(map[string][]*v1alpha1.IngressRoute)
+	for serviceName := range rtConf.Services {
+		serviceIngressRoutes[serviceName] = findServiceIngressRoutes(rtConf, routersIngressRoutes, serviceName)
+	}
+
+	r.mu.Lock()
+	r.disabledRouters = disabledRouters
+	r.serviceIngressRoutes = serviceIngressRoutes
+	r.mu.Unlock()
+
+	var keys []string
+	for key := range statuses {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var toUpdate []*v1alpha1.IngressRoute
+	for _, key := range keys {
+		ingressRoute, status := keyIngressRoutes[key], statuses[key]
+		if reflect.DeepEqual(ingressRoute.Status, *status) {
+			continue
+		}
+
+		// The IngressRoute objects are shared with the informers, so they must not be modified.
+		updated := ingressRoute.DeepCopy()
+		updated.Status = *status
+		toUpdate = append(toUpdate, updated)
+	}
+
+	if len(toUpdate) == 0 {
+		return
+	}
+
+	safe.Go(func() {
+		for _, ingressRoute := range toUpdate {
+			ctx, cancel := context.WithTimeout(context.Background(), statusUpdateTimeout)
+			if err := r.updateStatus(ctx, ingressRoute); err != nil {
+				log.WithoutContext().WithField(log.ProviderName, providerName).
+					Errorf("Unable to update the status of the IngressRoute %s/%s: %v", ingressRoute.Namespace, ingressRoute.Name, err)
+			}
+			cancel()
+		}
+	})
+}
+
+// findServiceIngressRoutes returns the IngressRoutes of the routers using the service,
+// directly or through the services using it.
+func findServiceIngressRoutes(rtConf *runtime.Configuration, routersIngressRoutes map[string]*v1alpha1.IngressRoute, serviceName string) []*v1alpha1.IngressRoute {
+	users := map[string]struct{}{serviceName: {}}
+	queue := []string{serviceName}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for name, serviceInfo := range rtConf.Services {
+			if _, ok := users[name]; ok || !usesService(serviceInfo.Service, name, current) {
+				continue
+			}
+
+			users[name] = struct{}{}
+			queue = append(queue, name)
+		}
+	}
+
+	seen := make(map[*v1alpha1.IngressRoute]struct{})
+	var ingressRoutes []*v1alpha1.IngressRoute
+	for routerName, ingressRoute := range routersIngressRoutes {
+		if _, ok := users[qualifyName(rtConf.Routers[routerName].Service, routerName)]; !ok {
+			continue
+		}
+
+		if _, ok := seen[ingressRoute]; ok {
+			continue
+		}
+
+		seen[ingressRoute] = struct{}{}
+		ingressRoutes = append(ingressRoutes, ingressRoute)
+	}
+
+	sort.Slice(ingressRoutes, func(i, j int) bool {
+		if ingressRoutes[i].Namespace != ingressRoutes[j].Namespace {
+			return ingressRoutes[i].Namespace < ingressRoutes[j].Namespace
+		}
+		return ingressRoutes[i].Name < ingressRoutes[j].Name
+	})
+
+	return ingressRoutes
+}
+
+// usesService reports whether the service, named serviceName, uses the child service.
+func usesService(service *dynamic.Service, serviceName, child string) bool {
+	switch {
+	case service.Weighted != nil:
+		for _, wrrService := range service.Weighted.Services {
+			if qualifyName(wrrService.Name, serviceName) == child {
+				return true
+			}
+		}
+	case service.Mirroring != nil:
+		if qualifyName(service.Mirroring.Service, serviceName) == child {
+			return true
+		}
+		for _, mirror := range service.Mirroring.Mirrors {
+			if qualifyName(mirror.Name, serviceName) == child {
+				return true
+			}
+		}
+	case service.Failover != nil:
+		return qualifyName(service.Failover.Service, serviceName) == child || qualifyName(service.Failover.Fallback, serviceName) == child
+	}
+
+	return false
+}
+
+// qualifyName qualifies the name of an element referenced by the element named parentName,
+// with the provider of the parent element when the name is not qualified.
+func qualifyName(name, parentName string) string {
+	if strings.Contains(name, providerNamespaceSeparator) {
+		return name
+	}
+
+	parts := strings.SplitN(parentName, providerNamespaceSeparator, 2)
+	if len(parts) != 2 {
+		return name
+	}
+
+	return name + providerNamespaceSeparator + parts[1]
+}
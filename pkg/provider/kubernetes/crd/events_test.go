@@ -0,0 +1,85 @@
+package crd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestStatusReporter(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+
+	updated := make(chan *v1alpha1.IngressRoute, 10)
+	reporter := newStatusReporter(recorder, func(_ context.Context, ingressRoute *v1alpha1.IngressRoute) error {
+		updated <- ingressRoute
+		return nil
+	})
+
+	ingressRoute := &v1alpha1.IngressRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	ingressRoutes := map[string]*v1alpha1.IngressRoute{
+		"default-test-foo": ingressRoute,
+		"default-test-bar": ingressRoute,
+	}
+
+	rtConf := runtime.NewConfig(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"default-test-foo@kubernetescrd": {Service: "wrr"},
+				"default-test-bar@kubernetescrd": {Service: "whoami@file"},
+				"other@file":                     {Service: "whoami"},
+			},
+			Services: map[string]*dynamic.Service{
+				"wrr@kubernetescrd": {
+					Weighted: &dynamic.WeightedRoundRobin{Services: []dynamic.WRRService{{Name: "default-whoami-80"}}},
+				},
+				"default-whoami-80@kubernetescrd": {LoadBalancer: &dynamic.ServersLoadBalancer{}},
+				"whoami@file":                     {LoadBalancer: &dynamic.ServersLoadBalancer{}},
+			},
+		},
+	})
+	rtConf.Routers["default-test-foo@kubernetescrd"].Status = runtime.StatusEnabled
+	rtConf.Routers["default-test-bar@kubernetescrd"].AddError(assert.AnError, true)
+	rtConf.Routers["other@file"].AddError(assert.AnError, true)
+
+	reporter.update(rtConf, ingressRoutes)
+
+	assert.Equal(t, "Warning RouterDisabled Router default-test-bar@kubernetescrd is disabled: "+assert.AnError.Error(), <-recorder.Events)
+
+	select {
+	case ir := <-updated:
+		assert.Equal(t, "default", ir.Namespace)
+		assert.Equal(t, "test", ir.Name)
+		assert.Equal(t, v1alpha1.IngressRouteStatus{
+			Routers: []v1alpha1.RouterStatus{
+				{Name: "default-test-bar@kubernetescrd", Status: runtime.StatusDisabled, Errors: []string{assert.AnError.Error()}},
+				{Name: "default-test-foo@kubernetescrd", Status: runtime.StatusEnabled},
+			},
+		}, ir.Status)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the status of the IngressRoute was not updated")
+	}
+
+	// The informer objects are not modified.
+	assert.Empty(t, ingressRoute.Status.Routers)
+
+	// The router disabled event is only emitted once.
+	reporter.update(rtConf, ingressRoutes)
+	assert.Empty(t, recorder.Events)
+
+	reporter.serverStatusChanged("default-whoami-80@kubernetescrd", "http://10.10.0.1:80", false)
+	assert.Equal(t, "Warning ServerDown Server http://10.10.0.1:80 of service default-whoami-80@kubernetescrd is down", <-recorder.Events)
+
+	reporter.serverStatusChanged("whoami@file", "http://10.10.0.2:80", true)
+	assert.Equal(t, "Normal ServerUp Server http://10.10.0.2:80 of service whoami@file is up", <-recorder.Events)
+
+	reporter.serverStatusChanged("unknown@file", "http://10.10.0.3:80", false)
+	assert.Empty(t, recorder.Events)
+}
@@ -49,7 +49,11 @@ type Provider struct {
 	LabelSelector       string          `description:"Kubernetes label selector to use." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
 	IngressClass        string          `description:"Value of kubernetes.io/ingress.class annotation to watch for." json:"ingressClass,omitempty" toml:"ingressClass,omitempty" yaml:"ingressClass,omitempty" export:"true"`
 	ThrottleDuration    ptypes.Duration `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
+	Zone                string          `description:"Zone of the Traefik instance, used to route preferentially to the endpoints of the same zone of the Services enabling topology-aware hints." json:"zone,omitempty" toml:"zone,omitempty" yaml:"zone,omitempty" export:"true"`
+	EmitEvents          bool            `description:"Emit Kubernetes Events, and update the status of the IngressRoutes, when their routers are disabled or the servers of their services go down or up." json:"emitEvents,omitempty" toml:"emitEvents,omitempty" yaml:"emitEvents,omitempty" export:"true"`
 	lastConfiguration   safe.Safe
+	routerIngressRoutes safe.Safe
+	statusReporter      safe.Safe
 }
 
 // SetDefaults sets the default values.
@@ -87,6 +91,7 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	}
 
 	client.labelSelector = p.LabelSelector
+	client.zone = p.Zone
 	return client, nil
 }
 
@@ -106,6 +111,10 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		return err
 	}
 
+	if p.EmitEvents {
+		p.statusReporter.Set(newClientStatusReporter(k8sClient))
+	}
+
 	if p.AllowCrossNamespace == nil || *p.AllowCrossNamespace {
 		logger.Warn("Cross-namespace reference between IngressRoutes and resources is enabled, please ensure that this is expected (see AllowCrossNamespace option)")
 	}
@@ -146,6 +155,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 						logger.Error("Unable to hash the configuration")
 					case p.lastConfiguration.Get() == confHash:
 						logger.Debugf("Skipping Kubernetes event kind %T", event)
+						provider.ReportRefresh(providerName)
 					default:
 						p.lastConfiguration.Set(confHash)
 						configurationChan <- dynamic.Message{
@@ -164,6 +174,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 		notify := func(err error, time time.Duration) {
 			logger.Errorf("Provider connection error: %v; retrying in %s", err, time)
+			provider.ReportFailure(providerName, err)
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxPool), notify)
 		if err != nil {
@@ -176,10 +187,11 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client) *dynamic.Configuration {
 	tlsConfigs := make(map[string]*tls.CertAndStores)
+	namespaces := provider.Namespaces{}
 	conf := &dynamic.Configuration{
-		HTTP: p.loadIngressRouteConfiguration(ctx, client, tlsConfigs),
-		TCP:  p.loadIngressRouteTCPConfiguration(ctx, client, tlsConfigs),
-		UDP:  p.loadIngressRouteUDPConfiguration(ctx, client),
+		HTTP: p.loadIngressRouteConfiguration(ctx, client, tlsConfigs, namespaces),
+		TCP:  p.loadIngressRouteTCPConfiguration(ctx, client, tlsConfigs, namespaces),
+		UDP:  p.loadIngressRouteUDPConfiguration(ctx, client, namespaces),
 		TLS: &dynamic.TLSConfiguration{
 			Certificates: getTLSConfig(tlsConfigs),
 			Options:      buildTLSOptions(ctx, client),
@@ -191,93 +203,36 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		id := provider.Normalize(makeID(middleware.Namespace, middleware.Name))
 		ctxMid := log.With(ctx, log.Str(log.MiddlewareName, id))
 
-		basicAuth, err := createBasicAuthMiddleware(client, middleware.Namespace, middleware.Spec.BasicAuth)
-		if err != nil {
-			log.FromContext(ctxMid).Errorf("Error while reading basic auth middleware: %v", err)
-			continue
-		}
-
-		digestAuth, err := createDigestAuthMiddleware(client, middleware.Namespace, middleware.Spec.DigestAuth)
-		if err != nil {
-			log.FromContext(ctxMid).Errorf("Error while reading digest auth middleware: %v", err)
-			continue
-		}
-
-		forwardAuth, err := createForwardAuthMiddleware(client, middleware.Namespace, middleware.Spec.ForwardAuth)
-		if err != nil {
-			log.FromContext(ctxMid).Errorf("Error while reading forward auth middleware: %v", err)
-			continue
-		}
-
-		errorPage, errorPageService, err := p.createErrorPageMiddleware(client, middleware.Namespace, middleware.Spec.Errors)
-		if err != nil {
-			log.FromContext(ctxMid).Errorf("Error while reading error page middleware: %v", err)
-			continue
-		}
-
-		if errorPage != nil && errorPageService != nil {
-			serviceName := id + "-errorpage-service"
-			errorPage.Service = serviceName
-			conf.HTTP.Services[serviceName] = errorPageService
-		}
-
-		plugin, err := createPluginMiddleware(middleware.Spec.Plugin)
-		if err != nil {
-			log.FromContext(ctxMid).Errorf("Error while reading plugins middleware: %v", err)
-			continue
-		}
-
-		rateLimit, err := createRateLimitMiddleware(middleware.Spec.RateLimit)
-		if err != nil {
-			log.FromContext(ctxMid).Errorf("Error while reading rateLimit middleware: %v", err)
-			continue
-		}
-
-		retry, err := createRetryMiddleware(middleware.Spec.Retry)
+		services := make(map[string]*dynamic.Service)
+		m, err := p.buildMiddleware(ctxMid, client, middleware, services)
+		mergeServices(conf.HTTP.Services, services, middleware.Namespace, namespaces)
 		if err != nil {
-			log.FromContext(ctxMid).Errorf("Error while reading retry middleware: %v", err)
+			log.FromContext(ctxMid).Errorf("Error while reading %v", err)
 			continue
 		}
 
-		conf.HTTP.Middlewares[id] = &dynamic.Middleware{
-			AddPrefix:         middleware.Spec.AddPrefix,
-			StripPrefix:       middleware.Spec.StripPrefix,
-			StripPrefixRegex:  middleware.Spec.StripPrefixRegex,
-			ReplacePath:       middleware.Spec.ReplacePath,
-			ReplacePathRegex:  middleware.Spec.ReplacePathRegex,
-			Chain:             createChainMiddleware(ctxMid, middleware.Namespace, middleware.Spec.Chain),
-			IPWhiteList:       middleware.Spec.IPWhiteList,
-			Headers:           middleware.Spec.Headers,
-			Errors:            errorPage,
-			RateLimit:         rateLimit,
-			RedirectRegex:     middleware.Spec.RedirectRegex,
-			RedirectScheme:    middleware.Spec.RedirectScheme,
-			BasicAuth:         basicAuth,
-			DigestAuth:        digestAuth,
-			ForwardAuth:       forwardAuth,
-			InFlightReq:       middleware.Spec.InFlightReq,
-			Buffering:         middleware.Spec.Buffering,
-			CircuitBreaker:    middleware.Spec.CircuitBreaker,
-			Compress:          middleware.Spec.Compress,
-			PassTLSClientCert: middleware.Spec.PassTLSClientCert,
-			Retry:             retry,
-			ContentType:       middleware.Spec.ContentType,
-			Plugin:            plugin,
-		}
+		conf.HTTP.Middlewares[id] = m
+		namespaces.Add("middleware", id, middleware.Namespace)
 	}
 
 	for _, middlewareTCP := range client.GetMiddlewareTCPs() {
 		id := provider.Normalize(makeID(middlewareTCP.Namespace, middlewareTCP.Name))
 
 		conf.TCP.Middlewares[id] = &dynamic.TCPMiddleware{
-			IPWhiteList: middlewareTCP.Spec.IPWhiteList,
+			IPWhiteList:  middlewareTCP.Spec.IPWhiteList,
+			SNILimit:     middlewareTCP.Spec.SNILimit,
+			InFlightConn: middlewareTCP.Spec.InFlightConn,
+			RateLimit:    middlewareTCP.Spec.RateLimit,
 		}
+		namespaces.Add("tcpMiddleware", id, middlewareTCP.Namespace)
 	}
 
 	cb := configBuilder{client, p.AllowCrossNamespace}
 
 	for _, service := range client.GetTraefikServices() {
-		err := cb.buildTraefikService(ctx, service, conf.HTTP.Services)
+		services := make(map[string]*dynamic.Service)
+		err := cb.buildTraefikService(ctx, service, services)
+		mergeServices(conf.HTTP.Services, services, service.Namespace, namespaces)
 		if err != nil {
 			log.FromContext(ctx).WithField(log.ServiceName, service.Name).
 				Errorf("Error while building TraefikService: %v", err)
@@ -337,6 +292,13 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 					logger.Errorf("Error while reading IdleConnTimeout: %v", err)
 				}
 			}
+
+			if serversTransport.Spec.ForwardingTimeouts.TLSHandshakeTimeout != nil {
+				err := forwardingTimeout.TLSHandshakeTimeout.Set(serversTransport.Spec.ForwardingTimeouts.TLSHandshakeTimeout.String())
+				if err != nil {
+					logger.Errorf("Error while reading TLSHandshakeTimeout: %v", err)
+				}
+			}
 		}
 
 		conf.HTTP.ServersTransports[serversTransport.Name] = &dynamic.ServersTransport{
@@ -346,12 +308,137 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			Certificates:        certs,
 			MaxIdleConnsPerHost: serversTransport.Spec.MaxIdleConnsPerHost,
 			ForwardingTimeouts:  forwardingTimeout,
+			DisableHTTP2:        serversTransport.Spec.DisableHTTP2,
 		}
 	}
 
+	conf.Namespaces = namespaces.Elements()
+
 	return conf
 }
 
+// mergeServices adds the given services, defined by a resource of the given namespace, to the conf map.
+func mergeServices(conf, services map[string]*dynamic.Service, namespace string, namespaces provider.Namespaces) {
+	for name, service := range services {
+		conf[name] = service
+	}
+
+	namespaces.AddServices(services, namespace)
+}
+
+// buildMiddleware builds the configuration of the given middleware,
+// and adds the services it defines to the given services map.
+func (p *Provider) buildMiddleware(ctx context.Context, client Client, middleware *v1alpha1.Middleware, services map[string]*dynamic.Service) (*dynamic.Middleware, error) {
+	id := provider.Normalize(makeID(middleware.Namespace, middleware.Name))
+
+	basicAuth, err := createBasicAuthMiddleware(client, middleware.Namespace, middleware.Spec.BasicAuth)
+	if err != nil {
+		return nil, fmt.Errorf("basic auth middleware: %w", err)
+	}
+
+	digestAuth, err := createDigestAuthMiddleware(client, middleware.Namespace, middleware.Spec.DigestAuth)
+	if err != nil {
+		return nil, fmt.Errorf("digest auth middleware: %w", err)
+	}
+
+	forwardAuth, err := createForwardAuthMiddleware(client, middleware.Namespace, middleware.Spec.ForwardAuth)
+	if err != nil {
+		return nil, fmt.Errorf("forward auth middleware: %w", err)
+	}
+
+	errorPage, errorPageService, err := p.createErrorPageMiddleware(client, middleware.Namespace, middleware.Spec.Errors)
+	if err != nil {
+		return nil, fmt.Errorf("error page middleware: %w", err)
+	}
+
+	fallback, fallbackService, err := p.createFallbackMiddleware(client, middleware.Namespace, middleware.Spec.Fallback)
+	if err != nil {
+		return nil, fmt.Errorf("fallback middleware: %w", err)
+	}
+
+	signedURL, err := createSignedURLMiddleware(client, middleware.Namespace, middleware.Spec.SignedURL)
+	if err != nil {
+		return nil, fmt.Errorf("signed URL middleware: %w", err)
+	}
+
+	jwt, err := createJWTMiddleware(client, middleware.Namespace, middleware.Spec.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("JWT middleware: %w", err)
+	}
+
+	plugin, err := createPluginMiddleware(middleware.Spec.Plugin)
+	if err != nil {
+		return nil, fmt.Errorf("plugins middleware: %w", err)
+	}
+
+	ipWhiteList, err := createIPWhiteListMiddleware(middleware.Spec.IPWhiteList)
+	if err != nil {
+		return nil, fmt.Errorf("ipWhiteList middleware: %w", err)
+	}
+
+	rateLimit, err := createRateLimitMiddleware(middleware.Spec.RateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("rateLimit middleware: %w", err)
+	}
+
+	retry, err := createRetryMiddleware(middleware.Spec.Retry)
+	if err != nil {
+		return nil, fmt.Errorf("retry middleware: %w", err)
+	}
+
+	chain, err := p.createChainMiddleware(ctx, middleware.Namespace, middleware.Spec.Chain)
+	if err != nil {
+		return nil, fmt.Errorf("chain middleware: %w", err)
+	}
+
+	if errorPage != nil && errorPageService != nil {
+		serviceName := id + "-errorpage-service"
+		errorPage.Service = serviceName
+		services[serviceName] = errorPageService
+	}
+
+	if fallback != nil && fallbackService != nil {
+		serviceName := id + "-fallback-service"
+		fallback.Service = serviceName
+		services[serviceName] = fallbackService
+	}
+
+	return &dynamic.Middleware{
+		AddPrefix:         middleware.Spec.AddPrefix,
+		StripPrefix:       middleware.Spec.StripPrefix,
+		StripPrefixRegex:  middleware.Spec.StripPrefixRegex,
+		ReplacePath:       middleware.Spec.ReplacePath,
+		ReplacePathRegex:  middleware.Spec.ReplacePathRegex,
+		Chain:             chain,
+		IPWhiteList:       ipWhiteList,
+		Headers:           middleware.Spec.Headers,
+		Errors:            errorPage,
+		Fallback:          fallback,
+		RateLimit:         rateLimit,
+		RedirectRegex:     middleware.Spec.RedirectRegex,
+		RedirectScheme:    middleware.Spec.RedirectScheme,
+		BasicAuth:         basicAuth,
+		DigestAuth:        digestAuth,
+		ForwardAuth:       forwardAuth,
+		InFlightReq:       middleware.Spec.InFlightReq,
+		Buffering:         middleware.Spec.Buffering,
+		CircuitBreaker:    middleware.Spec.CircuitBreaker,
+		Compress:          middleware.Spec.Compress,
+		PassTLSClientCert: middleware.Spec.PassTLSClientCert,
+		Retry:             retry,
+		ContentType:       middleware.Spec.ContentType,
+		AcceptLanguage:    middleware.Spec.AcceptLanguage,
+		SignedURL:         signedURL,
+		HeadersLimit:      middleware.Spec.HeadersLimit,
+		StaticResponse:    middleware.Spec.StaticResponse,
+		JWT:               jwt,
+		RequestID:         middleware.Spec.RequestID,
+		RewriteBody:       middleware.Spec.RewriteBody,
+		GeoIP:             middleware.Spec.GeoIP,
+		Plugin:            plugin,
+	}, nil
+}
+
 func getServicePort(svc *corev1.Service, port intstr.IntOrString) (*corev1.ServicePort, error) {
 	if svc == nil {
 		return nil, errors.New("service is not defined")
@@ -403,6 +490,26 @@ func createPluginMiddleware(plugins map[string]apiextensionv1.JSON) (map[string]
 	return pc, nil
 }
 
+func createIPWhiteListMiddleware(ipWhiteList *v1alpha1.IPWhiteList) (*dynamic.IPWhiteList, error) {
+	if ipWhiteList == nil {
+		return nil, nil
+	}
+
+	wl := &dynamic.IPWhiteList{
+		SourceRange: ipWhiteList.SourceRange,
+		IPStrategy:  ipWhiteList.IPStrategy,
+	}
+
+	if ipWhiteList.RefreshInterval != nil {
+		err := wl.RefreshInterval.Set(ipWhiteList.RefreshInterval.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return wl, nil
+}
+
 func createRateLimitMiddleware(rateLimit *v1alpha1.RateLimit) (*dynamic.RateLimit, error) {
 	if rateLimit == nil {
 		return nil, nil
@@ -445,9 +552,22 @@ func (p *Provider) createErrorPageMiddleware(client Client, namespace string, er
 		return nil, nil, nil
 	}
 
+	retry, err := createRetryMiddleware(errorPage.Retry)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	errorPageMiddleware := &dynamic.ErrorPage{
-		Status: errorPage.Status,
-		Query:  errorPage.Query,
+		Status:      errorPage.Status,
+		Query:       errorPage.Query,
+		Retry:       retry,
+		Template:    errorPage.Template,
+		ContentType: errorPage.ContentType,
+	}
+
+	// The error page is rendered from the template, without any service.
+	if errorPage.Template != "" && errorPage.Service.Name == "" {
+		return errorPageMiddleware, nil, nil
 	}
 
 	balancerServerHTTP, err := configBuilder{client, p.AllowCrossNamespace}.buildServersLB(namespace, errorPage.Service.LoadBalancerSpec)
@@ -458,6 +578,28 @@ func (p *Provider) createErrorPageMiddleware(client Client, namespace string, er
 	return errorPageMiddleware, balancerServerHTTP, nil
 }
 
+func (p *Provider) createFallbackMiddleware(client Client, namespace string, fallback *v1alpha1.Fallback) (*dynamic.Fallback, *dynamic.Service, error) {
+	if fallback == nil {
+		return nil, nil, nil
+	}
+
+	fallbackMiddleware := &dynamic.Fallback{}
+	fallbackMiddleware.SetDefaults()
+
+	fallbackMiddleware.Status = fallback.Status
+	fallbackMiddleware.Path = fallback.Path
+	if fallback.MaxRequestBodyBytes != nil {
+		fallbackMiddleware.MaxRequestBodyBytes = *fallback.MaxRequestBodyBytes
+	}
+
+	balancerServerHTTP, err := configBuilder{client, p.AllowCrossNamespace}.buildServersLB(namespace, fallback.Service.LoadBalancerSpec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fallbackMiddleware, balancerServerHTTP, nil
+}
+
 func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *v1alpha1.ForwardAuth) (*dynamic.ForwardAuth, error) {
 	if auth == nil {
 		return nil, nil
@@ -472,6 +614,16 @@ func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *v1alp
 		AuthResponseHeaders:      auth.AuthResponseHeaders,
 		AuthResponseHeadersRegex: auth.AuthResponseHeadersRegex,
 		AuthRequestHeaders:       auth.AuthRequestHeaders,
+		ForwardBody:              auth.ForwardBody,
+		MaxBodySize:              auth.MaxBodySize,
+	}
+
+	if auth.Cache != nil {
+		cache, err := createForwardAuthCache(auth.Cache)
+		if err != nil {
+			return nil, err
+		}
+		forwardAuth.Cache = cache
 	}
 
 	if auth.TLS == nil {
@@ -503,6 +655,27 @@ func createForwardAuthMiddleware(k8sClient Client, namespace string, auth *v1alp
 	return forwardAuth, nil
 }
 
+func createForwardAuthCache(cache *v1alpha1.ForwardAuthCache) (*dynamic.ForwardAuthCache, error) {
+	c := &dynamic.ForwardAuthCache{
+		KeyHeaders:      cache.KeyHeaders,
+		KeyPathSegments: cache.KeyPathSegments,
+	}
+	c.SetDefaults()
+
+	if cache.MaxEntries != nil {
+		c.MaxEntries = *cache.MaxEntries
+	}
+
+	if cache.TTL != nil {
+		err := c.TTL.Set(cache.TTL.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
 func loadCASecret(namespace, secretName string, k8sClient Client) (string, error) {
 	secret, ok, err := k8sClient.GetSecret(namespace, secretName)
 	if err != nil {
@@ -586,6 +759,129 @@ func createDigestAuthMiddleware(client Client, namespace string, digestAuth *v1a
 	}, nil
 }
 
+func createSignedURLMiddleware(client Client, namespace string, signedURL *v1alpha1.SignedURL) (*dynamic.SignedURL, error) {
+	if signedURL == nil {
+		return nil, nil
+	}
+
+	if signedURL.Secret == "" {
+		return nil, fmt.Errorf("signed URL secret must be set")
+	}
+
+	secret, err := loadSignedURLSecret(namespace, signedURL.Secret, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signed URL secret: %w", err)
+	}
+
+	config := &dynamic.SignedURL{}
+	config.SetDefaults()
+	config.Secret = secret
+
+	if signedURL.Algorithm != "" {
+		config.Algorithm = signedURL.Algorithm
+	}
+	if signedURL.Encoding != "" {
+		config.Encoding = signedURL.Encoding
+	}
+	if signedURL.ExpiresParam != "" {
+		config.ExpiresParam = signedURL.ExpiresParam
+	}
+	if signedURL.SignatureParam != "" {
+		config.SignatureParam = signedURL.SignatureParam
+	}
+
+	return config, nil
+}
+
+func loadSignedURLSecret(namespace, secretName string, k8sClient Client) (string, error) {
+	secret, ok, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, secretName, err)
+	}
+	if !ok {
+		return "", fmt.Errorf("secret '%s/%s' not found", namespace, secretName)
+	}
+	if secret == nil {
+		return "", fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, secretName)
+	}
+	if len(secret.Data) != 1 {
+		return "", fmt.Errorf("found %d elements for secret '%s/%s', must be single element exactly", len(secret.Data), namespace, secretName)
+	}
+
+	for _, v := range secret.Data {
+		if len(v) == 0 {
+			return "", fmt.Errorf("secret '%s/%s' is empty", namespace, secretName)
+		}
+		return string(v), nil
+	}
+
+	return "", nil
+}
+
+func createJWTMiddleware(client Client, namespace string, jwt *v1alpha1.JWT) (*dynamic.JWT, error) {
+	if jwt == nil {
+		return nil, nil
+	}
+
+	config := &dynamic.JWT{
+		JWKSURL:       jwt.JWKSURL,
+		Issuer:        jwt.Issuer,
+		Audiences:     jwt.Audiences,
+		ForwardClaims: jwt.ForwardClaims,
+	}
+	config.SetDefaults()
+
+	if jwt.JWKSRefreshInterval != nil {
+		err := config.JWKSRefreshInterval.Set(jwt.JWKSRefreshInterval.String())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if jwt.KeysSecret != "" {
+		keys, err := loadJWTKeys(namespace, jwt.KeysSecret, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWT keys: %w", err)
+		}
+		config.Keys = keys
+	}
+
+	return config, nil
+}
+
+func loadJWTKeys(namespace, secretName string, k8sClient Client) ([]string, error) {
+	secret, ok, err := k8sClient.GetSecret(namespace, secretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret '%s/%s': %w", namespace, secretName, err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret '%s/%s' not found", namespace, secretName)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("data for secret '%s/%s' must not be nil", namespace, secretName)
+	}
+
+	var names []string
+	for name := range secret.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var keys []string
+	for _, name := range names {
+		if len(secret.Data[name]) == 0 {
+			return nil, fmt.Errorf("key %q of secret '%s/%s' is empty", name, namespace, secretName)
+		}
+		keys = append(keys, string(secret.Data[name]))
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("secret '%s/%s' is empty", namespace, secretName)
+	}
+
+	return keys, nil
+}
+
 func getAuthCredentials(k8sClient Client, authSecret, namespace string) ([]string, error) {
 	if authSecret == "" {
 		return nil, fmt.Errorf("auth secret must be set")
@@ -637,9 +933,9 @@ func loadAuthCredentials(namespace, secretName string, k8sClient Client) ([]stri
 	return credentials, nil
 }
 
-func createChainMiddleware(ctx context.Context, namespace string, chain *v1alpha1.Chain) *dynamic.Chain {
+func (p *Provider) createChainMiddleware(ctx context.Context, namespace string, chain *v1alpha1.Chain) (*dynamic.Chain, error) {
 	if chain == nil {
-		return nil
+		return nil, nil
 	}
 
 	var mds []string
@@ -653,13 +949,18 @@ func createChainMiddleware(ctx context.Context, namespace string, chain *v1alpha
 			continue
 		}
 
-		ns := mi.Namespace
-		if len(ns) == 0 {
-			ns = namespace
+		ns := namespace
+		if len(mi.Namespace) > 0 {
+			if !isNamespaceAllowed(p.AllowCrossNamespace, namespace, mi.Namespace) {
+				return nil, fmt.Errorf("middleware %s/%s is not in the chain middleware namespace %s", mi.Namespace, mi.Name, namespace)
+			}
+
+			ns = mi.Namespace
 		}
+
 		mds = append(mds, makeID(ns, mi.Name))
 	}
-	return &dynamic.Chain{Middlewares: mds}
+	return &dynamic.Chain{Middlewares: mds}, nil
 }
 
 func buildTLSOptions(ctx context.Context, client Client) map[string]tls.Options {
@@ -677,24 +978,13 @@ func buildTLSOptions(ctx context.Context, client Client) map[string]tls.Options
 		var clientCAs []tls.FileOrContent
 
 		for _, secretName := range tlsOption.Spec.ClientAuth.SecretNames {
-			secret, exists, err := client.GetSecret(tlsOption.Namespace, secretName)
-			if err != nil {
-				logger.Errorf("Failed to fetch secret %s/%s: %v", tlsOption.Namespace, secretName, err)
-				continue
-			}
-
-			if !exists {
-				logger.Warnf("Secret %s/%s does not exist", tlsOption.Namespace, secretName)
-				continue
-			}
-
-			cert, err := getCABlocks(secret, tlsOption.Namespace, secretName)
+			cert, err := loadClientCA(client, tlsOption.Namespace, secretName)
 			if err != nil {
-				logger.Errorf("Failed to extract CA from secret %s/%s: %v", tlsOption.Namespace, secretName, err)
+				logger.Error(err)
 				continue
 			}
 
-			clientCAs = append(clientCAs, tls.FileOrContent(cert))
+			clientCAs = append(clientCAs, cert)
 		}
 
 		id := makeID(tlsOption.Namespace, tlsOption.Name)
@@ -703,18 +993,7 @@ func buildTLSOptions(ctx context.Context, client Client) map[string]tls.Options
 			id = tlsOption.Name
 			nsDefault = append(nsDefault, tlsOption.Namespace)
 		}
-		tlsOptions[id] = tls.Options{
-			MinVersion:       tlsOption.Spec.MinVersion,
-			MaxVersion:       tlsOption.Spec.MaxVersion,
-			CipherSuites:     tlsOption.Spec.CipherSuites,
-			CurvePreferences: tlsOption.Spec.CurvePreferences,
-			ClientAuth: tls.ClientAuth{
-				CAFiles:        clientCAs,
-				ClientAuthType: tlsOption.Spec.ClientAuth.ClientAuthType,
-			},
-			SniStrict:                tlsOption.Spec.SniStrict,
-			PreferServerCipherSuites: tlsOption.Spec.PreferServerCipherSuites,
-		}
+		tlsOptions[id] = makeTLSOptions(tlsOption, clientCAs)
 	}
 
 	if len(nsDefault) > 1 {
@@ -725,6 +1004,39 @@ func buildTLSOptions(ctx context.Context, client Client) map[string]tls.Options
 	return tlsOptions
 }
 
+func makeTLSOptions(tlsOption *v1alpha1.TLSOption, clientCAs []tls.FileOrContent) tls.Options {
+	return tls.Options{
+		MinVersion:       tlsOption.Spec.MinVersion,
+		MaxVersion:       tlsOption.Spec.MaxVersion,
+		CipherSuites:     tlsOption.Spec.CipherSuites,
+		CurvePreferences: tlsOption.Spec.CurvePreferences,
+		ClientAuth: tls.ClientAuth{
+			CAFiles:        clientCAs,
+			ClientAuthType: tlsOption.Spec.ClientAuth.ClientAuthType,
+		},
+		SniStrict:                tlsOption.Spec.SniStrict,
+		PreferServerCipherSuites: tlsOption.Spec.PreferServerCipherSuites,
+	}
+}
+
+func loadClientCA(client Client, namespace, secretName string) (tls.FileOrContent, error) {
+	secret, exists, err := client.GetSecret(namespace, secretName)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	if !exists {
+		return "", fmt.Errorf("secret %s/%s does not exist", namespace, secretName)
+	}
+
+	cert, err := getCABlocks(secret, namespace, secretName)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract CA from secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	return tls.FileOrContent(cert), nil
+}
+
 func buildTLSStores(ctx context.Context, client Client) map[string]tls.Store {
 	tlsStoreCRD := client.GetTLSStores()
 	var tlsStores map[string]tls.Store
@@ -23,7 +23,7 @@ const (
 	httpProtocol       = "http"
 )
 
-func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.HTTPConfiguration {
+func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores, namespaces provider.Namespaces) *dynamic.HTTPConfiguration {
 	conf := &dynamic.HTTPConfiguration{
 		Routers:           map[string]*dynamic.Router{},
 		Middlewares:       map[string]*dynamic.Middleware{},
@@ -31,6 +31,8 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 		ServersTransports: map[string]*dynamic.ServersTransport{},
 	}
 
+	routerIngressRoutes := make(map[string]*v1alpha1.IngressRoute)
+
 	for _, ingressRoute := range client.GetIngressRoutes() {
 		ctxRt := log.With(ctx, log.Str("ingress", ingressRoute.Name), log.Str("namespace", ingressRoute.Namespace))
 		logger := log.FromContext(ctxRt)
@@ -53,94 +55,110 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 		cb := configBuilder{client, p.AllowCrossNamespace}
 
 		for _, route := range ingressRoute.Spec.Routes {
-			if route.Kind != "Rule" {
-				logger.Errorf("Unsupported match kind: %s. Only \"Rule\" is supported for now.", route.Kind)
-				continue
-			}
-
-			if len(route.Match) == 0 {
-				logger.Errorf("Empty match rule")
-				continue
-			}
-
-			serviceKey, err := makeServiceKey(route.Match, ingressName)
+			services := make(map[string]*dynamic.Service)
+			key, router, err := p.buildRoute(ctxRt, cb, ingressRoute, ingressName, route, services)
+			mergeServices(conf.Services, services, ingressRoute.Namespace, namespaces)
 			if err != nil {
 				logger.Error(err)
 				continue
 			}
 
-			mds, err := p.makeMiddlewareKeys(ctx, ingressRoute.Namespace, route.Middlewares)
-			if err != nil {
-				logger.Errorf("Failed to create middleware keys: %v", err)
-				continue
-			}
+			conf.Routers[key] = router
+			namespaces.Add("router", key, ingressRoute.Namespace)
+			routerIngressRoutes[key] = ingressRoute
+		}
+	}
 
-			normalized := provider.Normalize(makeID(ingressRoute.Namespace, serviceKey))
-			serviceName := normalized
+	p.routerIngressRoutes.Set(routerIngressRoutes)
 
-			if len(route.Services) > 1 {
-				spec := v1alpha1.ServiceSpec{
-					Weighted: &v1alpha1.WeightedRoundRobin{
-						Services: route.Services,
-					},
-				}
+	return conf
+}
 
-				errBuild := cb.buildServicesLB(ctx, ingressRoute.Namespace, spec, serviceName, conf.Services)
-				if errBuild != nil {
-					logger.Error(errBuild)
-					continue
-				}
-			} else if len(route.Services) == 1 {
-				fullName, serversLB, err := cb.nameAndService(ctx, ingressRoute.Namespace, route.Services[0].LoadBalancerSpec)
-				if err != nil {
-					logger.Error(err)
-					continue
-				}
+// buildRoute builds the router of the given route of the IngressRoute,
+// and adds the services it defines to the given services map.
+func (p *Provider) buildRoute(ctx context.Context, cb configBuilder, ingressRoute *v1alpha1.IngressRoute, ingressName string, route v1alpha1.Route, services map[string]*dynamic.Service) (string, *dynamic.Router, error) {
+	if route.Kind != "Rule" {
+		return "", nil, fmt.Errorf("unsupported match kind: %s. Only \"Rule\" is supported for now", route.Kind)
+	}
 
-				if serversLB != nil {
-					conf.Services[serviceName] = serversLB
-				} else {
-					serviceName = fullName
-				}
-			}
+	if len(route.Match) == 0 {
+		return "", nil, errors.New("empty match rule")
+	}
 
-			conf.Routers[normalized] = &dynamic.Router{
-				Middlewares: mds,
-				Priority:    route.Priority,
-				EntryPoints: ingressRoute.Spec.EntryPoints,
-				Rule:        route.Match,
-				Service:     serviceName,
-			}
+	serviceKey, err := makeServiceKey(route.Match, ingressName)
+	if err != nil {
+		return "", nil, err
+	}
 
-			if ingressRoute.Spec.TLS != nil {
-				tlsConf := &dynamic.RouterTLSConfig{
-					CertResolver: ingressRoute.Spec.TLS.CertResolver,
-					Domains:      ingressRoute.Spec.TLS.Domains,
-				}
+	mds, err := p.makeMiddlewareKeys(ctx, ingressRoute.Namespace, route.Middlewares)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create middleware keys: %w", err)
+	}
+
+	normalized := provider.Normalize(makeID(ingressRoute.Namespace, serviceKey))
+	serviceName := normalized
+
+	if len(route.Services) > 1 {
+		spec := v1alpha1.ServiceSpec{
+			Weighted: &v1alpha1.WeightedRoundRobin{
+				Services: route.Services,
+			},
+		}
 
-				if ingressRoute.Spec.TLS.Options != nil && len(ingressRoute.Spec.TLS.Options.Name) > 0 {
-					tlsOptionsName := ingressRoute.Spec.TLS.Options.Name
-					// Is a Kubernetes CRD reference, (i.e. not a cross-provider reference)
-					ns := ingressRoute.Spec.TLS.Options.Namespace
-					if !strings.Contains(tlsOptionsName, providerNamespaceSeparator) {
-						if len(ns) == 0 {
-							ns = ingressRoute.Namespace
-						}
-						tlsOptionsName = makeID(ns, tlsOptionsName)
-					} else if len(ns) > 0 {
-						logger.
-							WithField("TLSoptions", ingressRoute.Spec.TLS.Options.Name).
-							Warnf("namespace %q is ignored in cross-provider context", ns)
-					}
-
-					tlsConf.Options = tlsOptionsName
+		err = cb.buildServicesLB(ctx, ingressRoute.Namespace, spec, serviceName, services)
+		if err != nil {
+			return "", nil, err
+		}
+	} else if len(route.Services) == 1 {
+		fullName, serversLB, err := cb.nameAndService(ctx, ingressRoute.Namespace, route.Services[0].LoadBalancerSpec)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if serversLB != nil {
+			services[serviceName] = serversLB
+		} else {
+			serviceName = fullName
+		}
+	}
+
+	router := &dynamic.Router{
+		Middlewares:      mds,
+		Priority:         route.Priority,
+		EntryPoints:      ingressRoute.Spec.EntryPoints,
+		Rule:             route.Match,
+		Service:          serviceName,
+		SkipRedirections: route.SkipRedirections,
+		IPStrategy:       route.IPStrategy,
+	}
+
+	if ingressRoute.Spec.TLS != nil {
+		tlsConf := &dynamic.RouterTLSConfig{
+			CertResolver: ingressRoute.Spec.TLS.CertResolver,
+			Domains:      ingressRoute.Spec.TLS.Domains,
+		}
+
+		if ingressRoute.Spec.TLS.Options != nil && len(ingressRoute.Spec.TLS.Options.Name) > 0 {
+			tlsOptionsName := ingressRoute.Spec.TLS.Options.Name
+			// Is a Kubernetes CRD reference, (i.e. not a cross-provider reference)
+			ns := ingressRoute.Spec.TLS.Options.Namespace
+			if !strings.Contains(tlsOptionsName, providerNamespaceSeparator) {
+				if len(ns) == 0 {
+					ns = ingressRoute.Namespace
 				}
-				conf.Routers[normalized].TLS = tlsConf
+				tlsOptionsName = makeID(ns, tlsOptionsName)
+			} else if len(ns) > 0 {
+				log.FromContext(ctx).
+					WithField("TLSoptions", ingressRoute.Spec.TLS.Options.Name).
+					Warnf("namespace %q is ignored in cross-provider context", ns)
 			}
+
+			tlsConf.Options = tlsOptionsName
 		}
+		router.TLS = tlsConf
 	}
 
-	return conf
+	return normalized, router, nil
 }
 
 func (p *Provider) makeMiddlewareKeys(ctx context.Context, ingRouteNamespace string, middlewares []v1alpha1.MiddlewareRef) ([]string, error) {
@@ -214,6 +232,7 @@ func (c configBuilder) buildServicesLB(ctx context.Context, namespace string, tS
 		wrrServices = append(wrrServices, dynamic.WRRService{
 			Name:   fullName,
 			Weight: weight,
+			Match:  service.Match,
 		})
 	}
 
@@ -252,6 +271,7 @@ func (c configBuilder) buildMirroring(ctx context.Context, tService *v1alpha1.Tr
 		mirrorServices = append(mirrorServices, dynamic.MirrorService{
 			Name:    mirroredName,
 			Percent: mirror.Percent,
+			Match:   mirror.Match,
 		})
 	}
 
@@ -10,12 +10,13 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
 )
 
-func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.TCPConfiguration {
+func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores, namespaces provider.Namespaces) *dynamic.TCPConfiguration {
 	conf := &dynamic.TCPConfiguration{
 		Routers:     map[string]*dynamic.TCPRouter{},
 		Middlewares: map[string]*dynamic.TCPMiddleware{},
@@ -75,11 +76,13 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 				// i.e. the service on top is directly a load balancer of servers.
 				if len(route.Services) == 1 {
 					conf.Services[serviceName] = balancerServerTCP
+					namespaces.Add("tcpService", serviceName, ingressRouteTCP.Namespace)
 					break
 				}
 
 				serviceKey := fmt.Sprintf("%s-%s-%s", serviceName, service.Name, &service.Port)
 				conf.Services[serviceKey] = balancerServerTCP
+				namespaces.Add("tcpService", serviceKey, ingressRouteTCP.Namespace)
 
 				srv := dynamic.TCPWRRService{Name: serviceKey}
 				srv.SetDefaults()
@@ -91,6 +94,7 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 					conf.Services[serviceName] = &dynamic.TCPService{Weighted: &dynamic.TCPWeightedRoundRobin{}}
 				}
 				conf.Services[serviceName].Weighted.Services = append(conf.Services[serviceName].Weighted.Services, srv)
+				namespaces.Add("tcpService", serviceName, ingressRouteTCP.Namespace)
 			}
 
 			conf.Routers[serviceName] = &dynamic.TCPRouter{
@@ -99,6 +103,7 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 				Rule:        route.Match,
 				Service:     serviceName,
 			}
+			namespaces.Add("tcpRouter", serviceName, ingressRouteTCP.Namespace)
 
 			if ingressRouteTCP.Spec.TLS != nil {
 				conf.Routers[serviceName].TLS = &dynamic.RouterTCPTLSConfig{
@@ -2,6 +2,7 @@ package crd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -17,6 +18,8 @@ import (
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	kubefake "k8s.io/client-go/kubernetes/fake"
@@ -1299,6 +1302,8 @@ func TestLoadIngressRouteTCPs(t *testing.T) {
 
 			clientMock := newClientMock(test.paths...)
 			conf := p.loadConfigurationFromCRD(context.Background(), clientMock)
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -1375,6 +1380,50 @@ func TestLoadIngressRoutes(t *testing.T) {
 				TLS: &dynamic.TLSConfiguration{},
 			},
 		},
+		{
+			desc:  "Simple Ingress Route, skipping the redirections",
+			paths: []string{"services.yml", "with_skip_redirections.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers: map[string]*dynamic.Router{
+						"default-test-route-6b204d94623b3df4370c": {
+							EntryPoints:      []string{"foo"},
+							Service:          "default-test-route-6b204d94623b3df4370c",
+							Rule:             "Host(`foo.com`) && PathPrefix(`/bar`)",
+							Priority:         12,
+							SkipRedirections: true,
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"default-test-route-6b204d94623b3df4370c": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:80",
+									},
+									{
+										URL: "http://10.10.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
 		{
 			desc:  "Simple Ingress Route with middleware",
 			paths: []string{"services.yml", "with_middleware.yml"},
@@ -2395,6 +2444,80 @@ func TestLoadIngressRoutes(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "One ingress Route with two different services, with a request match",
+			paths: []string{"services.yml", "with_two_services_match.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers: map[string]*dynamic.Router{
+						"default-test-route-77c62dfe9517144aeeaa": {
+							EntryPoints: []string{"web"},
+							Service:     "default-test-route-77c62dfe9517144aeeaa",
+							Rule:        "Host(`foo.com`) && PathPrefix(`/foo`)",
+							Priority:    12,
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"default-test-route-77c62dfe9517144aeeaa": {
+							Weighted: &dynamic.WeightedRoundRobin{
+								Services: []dynamic.WRRService{
+									{
+										Name:   "default-whoami-80",
+										Weight: Int(1),
+									},
+									{
+										Name:   "default-whoami2-8080",
+										Weight: Int(0),
+										Match: &dynamic.RequestMatch{
+											Header: "X-Canary",
+											Values: []string{"always"},
+										},
+									},
+								},
+							},
+						},
+						"default-whoami-80": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:80",
+									},
+									{
+										URL: "http://10.10.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+						"default-whoami2-8080": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.3:8080",
+									},
+									{
+										URL: "http://10.10.0.4:8080",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc:         "Ingress class",
 			paths:        []string{"services.yml", "simple.yml"},
@@ -3092,6 +3215,38 @@ func TestLoadIngressRoutes(t *testing.T) {
 								},
 							},
 						},
+						"default-forwardauthcache": {
+							ForwardAuth: &dynamic.ForwardAuth{
+								Address: "test.com",
+								Cache: &dynamic.ForwardAuthCache{
+									TTL:             types.Duration(42 * time.Second),
+									MaxEntries:      1000,
+									KeyHeaders:      []string{"Authorization"},
+									KeyPathSegments: 1,
+								},
+							},
+						},
+						"default-signedurl": {
+							SignedURL: &dynamic.SignedURL{
+								Secret:         "foobar",
+								Algorithm:      "sha256",
+								Encoding:       "base64url",
+								ExpiresParam:   "expires",
+								SignatureParam: "signature",
+							},
+						},
+						"default-jwt": {
+							JWT: &dynamic.JWT{
+								Keys:                []string{"foobar", "barbaz"},
+								JWKSURL:             "https://issuer.localhost/.well-known/jwks.json",
+								JWKSRefreshInterval: types.Duration(5 * time.Minute),
+								Issuer:              "https://issuer.localhost",
+								Audiences:           []string{"api"},
+								ForwardClaims: map[string]string{
+									"X-User": "sub",
+								},
+							},
+						},
 					},
 					Services: map[string]*dynamic.Service{},
 				},
@@ -3141,6 +3296,81 @@ func TestLoadIngressRoutes(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:  "Simple Ingress Route, with error page middleware rendered from a template",
+			paths: []string{"services.yml", "with_error_page_template.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers:           map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{
+						"default-errorpage": {
+							Errors: &dynamic.ErrorPage{
+								Status:      []string{"500-599"},
+								Template:    "{{ .StatusCode }}",
+								ContentType: "text/plain",
+							},
+						},
+					},
+					Services: map[string]*dynamic.Service{},
+				},
+			},
+		},
+		{
+			desc:  "Simple Ingress Route, with fallback middleware",
+			paths: []string{"services.yml", "with_fallback.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers:           map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{
+						"default-fallback": {
+							Fallback: &dynamic.Fallback{
+								Status:              []string{"404"},
+								Service:             "default-fallback-fallback-service",
+								Path:                "/index.html",
+								MaxRequestBodyBytes: 1048576,
+							},
+						},
+					},
+					Services: map[string]*dynamic.Service{
+						"default-fallback-fallback-service": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:80",
+									},
+									{
+										URL: "http://10.10.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc:  "Simple Ingress Route, with options",
 			paths: []string{"services.yml", "with_options.yml"},
@@ -3455,7 +3685,9 @@ func TestLoadIngressRoutes(t *testing.T) {
 								DialTimeout:           types.Duration(42 * time.Second),
 								ResponseHeaderTimeout: types.Duration(42 * time.Second),
 								IdleConnTimeout:       types.Duration(42 * time.Millisecond),
+								TLSHandshakeTimeout:   types.Duration(42 * time.Second),
 							},
+							DisableHTTP2: true,
 						},
 					},
 					Routers:     map[string]*dynamic.Router{},
@@ -3481,6 +3713,8 @@ func TestLoadIngressRoutes(t *testing.T) {
 
 			clientMock := newClientMock(test.paths...)
 			conf := p.loadConfigurationFromCRD(context.Background(), clientMock)
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -3899,6 +4133,8 @@ func TestLoadIngressRouteUDPs(t *testing.T) {
 
 			clientMock := newClientMock(test.paths...)
 			conf := p.loadConfigurationFromCRD(context.Background(), clientMock)
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -4378,6 +4614,112 @@ func TestCrossNamespace(t *testing.T) {
 				TLS: &dynamic.TLSConfiguration{},
 			},
 		},
+		{
+			desc:  "HTTP chain middleware cross namespace disallowed",
+			paths: []string{"services.yml", "with_chain_middleware_cross_namespace.yml"},
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers: map[string]*dynamic.Router{
+						"default-test-crossnamespace-route-6b204d94623b3df4370c": {
+							EntryPoints: []string{"foo"},
+							Service:     "default-test-crossnamespace-route-6b204d94623b3df4370c",
+							Rule:        "Host(`foo.com`) && PathPrefix(`/bar`)",
+							Priority:    12,
+							Middlewares: []string{"default-test-chain"},
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{
+						"cross-ns-stripprefix": {
+							StripPrefix: &dynamic.StripPrefix{
+								Prefixes: []string{"/stripit"},
+							},
+						},
+					},
+					Services: map[string]*dynamic.Service{
+						"default-test-crossnamespace-route-6b204d94623b3df4370c": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:80",
+									},
+									{
+										URL: "http://10.10.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
+		{
+			desc:                "HTTP chain middleware cross namespace allowed",
+			paths:               []string{"services.yml", "with_chain_middleware_cross_namespace.yml"},
+			allowCrossNamespace: true,
+			expected: &dynamic.Configuration{
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					ServersTransports: map[string]*dynamic.ServersTransport{},
+					Routers: map[string]*dynamic.Router{
+						"default-test-crossnamespace-route-6b204d94623b3df4370c": {
+							EntryPoints: []string{"foo"},
+							Service:     "default-test-crossnamespace-route-6b204d94623b3df4370c",
+							Rule:        "Host(`foo.com`) && PathPrefix(`/bar`)",
+							Priority:    12,
+							Middlewares: []string{"default-test-chain"},
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{
+						"default-test-chain": {
+							Chain: &dynamic.Chain{
+								Middlewares: []string{"cross-ns-stripprefix"},
+							},
+						},
+						"cross-ns-stripprefix": {
+							StripPrefix: &dynamic.StripPrefix{
+								Prefixes: []string{"/stripit"},
+							},
+						},
+					},
+					Services: map[string]*dynamic.Service{
+						"default-test-crossnamespace-route-6b204d94623b3df4370c": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:80",
+									},
+									{
+										URL: "http://10.10.0.2:80",
+									},
+								},
+								PassHostHeader: Bool(true),
+							},
+						},
+					},
+				},
+				TLS: &dynamic.TLSConfiguration{},
+			},
+		},
 		{
 			desc:                "HTTP cross namespace allowed",
 			paths:               []string{"services.yml", "with_cross_namespace.yml"},
@@ -4857,8 +5199,10 @@ func TestCrossNamespace(t *testing.T) {
 				objects := k8s.MustParseYaml(yamlContent)
 				for _, obj := range objects {
 					switch o := obj.(type) {
-					case *corev1.Service, *corev1.Endpoints, *corev1.Secret:
+					case *corev1.Service, *corev1.Secret:
 						k8sObjects = append(k8sObjects, o)
+					case *corev1.Endpoints:
+						k8sObjects = append(k8sObjects, endpointSlicesFromEndpoints(o)...)
 					case *v1alpha1.IngressRoute:
 						crdObjects = append(crdObjects, o)
 					case *v1alpha1.IngressRouteTCP:
@@ -4900,7 +5244,57 @@ func TestCrossNamespace(t *testing.T) {
 
 			p.AllowCrossNamespace = Bool(test.allowCrossNamespace)
 			conf := p.loadConfigurationFromCRD(context.Background(), client)
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 			assert.Equal(t, test.expected, conf)
 		})
 	}
 }
+
+// endpointSlicesFromEndpoints returns the EndpointSlices mirroring the subsets of the Endpoints,
+// as the Kubernetes EndpointSlice mirroring controller does.
+func endpointSlicesFromEndpoints(endpoints *corev1.Endpoints) []runtime.Object {
+	var slices []runtime.Object
+	for i, subset := range endpoints.Subsets {
+		slice := &discoveryv1beta1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%d", endpoints.Name, i),
+				Namespace: endpoints.Namespace,
+				Labels:    map[string]string{discoveryv1beta1.LabelServiceName: endpoints.Name},
+			},
+			AddressType: discoveryv1beta1.AddressTypeIPv4,
+		}
+
+		for _, address := range subset.Addresses {
+			slice.Endpoints = append(slice.Endpoints, discoveryv1beta1.Endpoint{Addresses: []string{address.IP}})
+		}
+
+		for _, port := range subset.Ports {
+			port := port
+			slice.Ports = append(slice.Ports, discoveryv1beta1.EndpointPort{Name: &port.Name, Port: &port.Port, Protocol: &port.Protocol})
+		}
+
+		slices = append(slices, slice)
+	}
+
+	return slices
+}
+
+func TestLoadNamespaces(t *testing.T) {
+	p := Provider{}
+	p.SetDefaults()
+
+	conf := p.loadConfigurationFromCRD(context.Background(), newClientMock("services.yml", "with_middleware.yml", "tcp/services.yml", "tcp/simple.yml", "udp/services.yml", "udp/simple.yml"))
+
+	expected := []dynamic.ElementNamespace{
+		{Kind: "middleware", Name: "default-stripprefix", Namespace: "default"},
+		{Kind: "middleware", Name: "foo-addprefix", Namespace: "foo"},
+		{Kind: "router", Name: "default-test2-route-23c7f4c450289ee29016", Namespace: "default"},
+		{Kind: "service", Name: "default-test2-route-23c7f4c450289ee29016", Namespace: "default"},
+		{Kind: "tcpRouter", Name: "default-test.route-fdd3e9338e47a45efefc", Namespace: "default"},
+		{Kind: "tcpService", Name: "default-test.route-fdd3e9338e47a45efefc", Namespace: "default"},
+		{Kind: "udpRouter", Name: "default-test.route-0", Namespace: "default"},
+		{Kind: "udpService", Name: "default-test.route-0", Namespace: "default"},
+	}
+	assert.Equal(t, expected, conf.Namespaces)
+}
@@ -9,11 +9,12 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 )
 
-func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client Client) *dynamic.UDPConfiguration {
+func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client Client, namespaces provider.Namespaces) *dynamic.UDPConfiguration {
 	conf := &dynamic.UDPConfiguration{
 		Routers:  map[string]*dynamic.UDPRouter{},
 		Services: map[string]*dynamic.UDPService{},
@@ -49,11 +50,13 @@ func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client
 				// i.e. the service on top is directly a load balancer of servers.
 				if len(route.Services) == 1 {
 					conf.Services[serviceName] = balancerServerUDP
+					namespaces.Add("udpService", serviceName, ingressRouteUDP.Namespace)
 					break
 				}
 
 				serviceKey := fmt.Sprintf("%s-%s-%s", serviceName, service.Name, &service.Port)
 				conf.Services[serviceKey] = balancerServerUDP
+				namespaces.Add("udpService", serviceKey, ingressRouteUDP.Namespace)
 
 				srv := dynamic.UDPWRRService{Name: serviceKey}
 				srv.SetDefaults()
@@ -65,12 +68,14 @@ func (p *Provider) loadIngressRouteUDPConfiguration(ctx context.Context, client
 					conf.Services[serviceName] = &dynamic.UDPService{Weighted: &dynamic.UDPWeightedRoundRobin{}}
 				}
 				conf.Services[serviceName].Weighted.Services = append(conf.Services[serviceName].Weighted.Services, srv)
+				namespaces.Add("udpService", serviceName, ingressRouteUDP.Namespace)
 			}
 
 			conf.Routers[serviceName] = &dynamic.UDPRouter{
 				EntryPoints: ingressRouteUDP.Spec.EntryPoints,
 				Service:     serviceName,
 			}
+			namespaces.Add("udpRouter", serviceName, ingressRouteUDP.Namespace)
 		}
 	}
 
@@ -94,10 +99,20 @@ func (p *Provider) createLoadBalancerServerUDP(client Client, parentNamespace st
 
 	udpService := &dynamic.UDPService{
 		LoadBalancer: &dynamic.UDPServersLoadBalancer{
-			Servers: servers,
+			Transparent: service.Transparent,
+			Servers:     servers,
 		},
 	}
 
+	if service.ProxyProtocol != nil {
+		udpService.LoadBalancer.ProxyProtocol = &dynamic.ProxyProtocol{}
+		udpService.LoadBalancer.ProxyProtocol.SetDefaults()
+
+		if service.ProxyProtocol.Version != 0 {
+			udpService.LoadBalancer.ProxyProtocol.Version = service.ProxyProtocol.Version
+		}
+	}
+
 	return udpService, nil
 }
 
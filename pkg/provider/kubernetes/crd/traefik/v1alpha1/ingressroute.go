@@ -22,6 +22,10 @@ type Route struct {
 	Priority    int             `json:"priority,omitempty"`
 	Services    []Service       `json:"services,omitempty"`
 	Middlewares []MiddlewareRef `json:"middlewares,omitempty"`
+	// SkipRedirections excludes the requests matched by the route from the redirections of the entry points.
+	SkipRedirections bool `json:"skipRedirections,omitempty"`
+	// IPStrategy defines how the ClientIP matchers of the route get the client IP.
+	IPStrategy *dynamic.IPStrategy `json:"ipStrategy,omitempty"`
 }
 
 // TLS contains the TLS certificates configuration of the routes.
@@ -86,6 +90,8 @@ type LoadBalancerSpec struct {
 // Service defines an upstream to proxy traffic.
 type Service struct {
 	LoadBalancerSpec `json:",inline"`
+
+	Match *dynamic.RequestMatch `json:"match,omitempty"`
 }
 
 // MiddlewareRef is a ref to the Middleware resources.
@@ -97,13 +103,31 @@ type MiddlewareRef struct {
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:storageversion
+// +kubebuilder:subresource:status
 
 // IngressRoute is an Ingress CRD specification.
 type IngressRoute struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
-	Spec IngressRouteSpec `json:"spec"`
+	Spec   IngressRouteSpec   `json:"spec"`
+	Status IngressRouteStatus `json:"status,omitempty"`
+}
+
+// IngressRouteStatus is the status of an IngressRoute, reported by Traefik.
+type IngressRouteStatus struct {
+	// Routers holds the status of the routers built from the IngressRoute.
+	Routers []RouterStatus `json:"routers,omitempty"`
+}
+
+// RouterStatus is the status of a router built from an IngressRoute.
+type RouterStatus struct {
+	// Name is the name of the router.
+	Name string `json:"name"`
+	// Status is the status of the router: enabled, disabled, or warning.
+	Status string `json:"status"`
+	// Errors holds the errors of the router.
+	Errors []string `json:"errors,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -29,9 +29,10 @@ type MiddlewareSpec struct {
 	ReplacePath       *dynamic.ReplacePath           `json:"replacePath,omitempty"`
 	ReplacePathRegex  *dynamic.ReplacePathRegex      `json:"replacePathRegex,omitempty"`
 	Chain             *Chain                         `json:"chain,omitempty"`
-	IPWhiteList       *dynamic.IPWhiteList           `json:"ipWhiteList,omitempty"`
+	IPWhiteList       *IPWhiteList                   `json:"ipWhiteList,omitempty"`
 	Headers           *dynamic.Headers               `json:"headers,omitempty"`
 	Errors            *ErrorPage                     `json:"errors,omitempty"`
+	Fallback          *Fallback                      `json:"fallback,omitempty"`
 	RateLimit         *RateLimit                     `json:"rateLimit,omitempty"`
 	RedirectRegex     *dynamic.RedirectRegex         `json:"redirectRegex,omitempty"`
 	RedirectScheme    *dynamic.RedirectScheme        `json:"redirectScheme,omitempty"`
@@ -45,6 +46,14 @@ type MiddlewareSpec struct {
 	PassTLSClientCert *dynamic.PassTLSClientCert     `json:"passTLSClientCert,omitempty"`
 	Retry             *Retry                         `json:"retry,omitempty"`
 	ContentType       *dynamic.ContentType           `json:"contentType,omitempty"`
+	AcceptLanguage    *dynamic.AcceptLanguage        `json:"acceptLanguage,omitempty"`
+	SignedURL         *SignedURL                     `json:"signedURL,omitempty"`
+	HeadersLimit      *dynamic.HeadersLimit          `json:"headersLimit,omitempty"`
+	StaticResponse    *dynamic.StaticResponse        `json:"staticResponse,omitempty"`
+	JWT               *JWT                           `json:"jwt,omitempty"`
+	RequestID         *dynamic.RequestID             `json:"requestId,omitempty"`
+	RewriteBody       *dynamic.RewriteBody           `json:"rewriteBody,omitempty"`
+	GeoIP             *dynamic.GeoIP                 `json:"geoIP,omitempty"`
 	Plugin            map[string]apiextensionv1.JSON `json:"plugin,omitempty"`
 }
 
@@ -52,9 +61,22 @@ type MiddlewareSpec struct {
 
 // ErrorPage holds the custom error page configuration.
 type ErrorPage struct {
-	Status  []string `json:"status,omitempty"`
-	Service Service  `json:"service,omitempty"`
-	Query   string   `json:"query,omitempty"`
+	Status      []string `json:"status,omitempty"`
+	Service     Service  `json:"service,omitempty"`
+	Query       string   `json:"query,omitempty"`
+	Retry       *Retry   `json:"retry,omitempty"`
+	Template    string   `json:"template,omitempty"`
+	ContentType string   `json:"contentType,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// Fallback holds the fallback configuration.
+type Fallback struct {
+	Status              []string `json:"status,omitempty"`
+	Service             Service  `json:"service,omitempty"`
+	Path                string   `json:"path,omitempty"`
+	MaxRequestBodyBytes *int64   `json:"maxRequestBodyBytes,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -76,6 +98,41 @@ type BasicAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// SignedURL holds the signed URL configuration.
+type SignedURL struct {
+	// Secret is the name of the referenced Kubernetes Secret holding the HMAC secret.
+	Secret         string `json:"secret,omitempty"`
+	Algorithm      string `json:"algorithm,omitempty"`
+	Encoding       string `json:"encoding,omitempty"`
+	ExpiresParam   string `json:"expiresParam,omitempty"`
+	SignatureParam string `json:"signatureParam,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// IPWhiteList holds the ip white list configuration.
+type IPWhiteList struct {
+	// SourceRange holds the allowed IPs and CIDRs, and the absolute paths of the files or the HTTP(S) URLs listing them.
+	SourceRange     []string            `json:"sourceRange,omitempty"`
+	IPStrategy      *dynamic.IPStrategy `json:"ipStrategy,omitempty"`
+	RefreshInterval *intstr.IntOrString `json:"refreshInterval,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// JWT holds the JWT middleware configuration.
+type JWT struct {
+	// KeysSecret is the name of the referenced Kubernetes Secret holding the keys.
+	KeysSecret          string              `json:"keysSecret,omitempty"`
+	JWKSURL             string              `json:"jwksURL,omitempty"`
+	JWKSRefreshInterval *intstr.IntOrString `json:"jwksRefreshInterval,omitempty"`
+	Issuer              string              `json:"issuer,omitempty"`
+	Audiences           []string            `json:"audiences,omitempty"`
+	ForwardClaims       map[string]string   `json:"forwardClaims,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // DigestAuth holds the Digest HTTP authentication configuration.
 type DigestAuth struct {
 	Secret       string `json:"secret,omitempty"`
@@ -88,12 +145,25 @@ type DigestAuth struct {
 
 // ForwardAuth holds the http forward authentication configuration.
 type ForwardAuth struct {
-	Address                  string     `json:"address,omitempty"`
-	TrustForwardHeader       bool       `json:"trustForwardHeader,omitempty"`
-	AuthResponseHeaders      []string   `json:"authResponseHeaders,omitempty"`
-	AuthResponseHeadersRegex string     `json:"authResponseHeadersRegex,omitempty"`
-	AuthRequestHeaders       []string   `json:"authRequestHeaders,omitempty"`
-	TLS                      *ClientTLS `json:"tls,omitempty"`
+	Address                  string            `json:"address,omitempty"`
+	TrustForwardHeader       bool              `json:"trustForwardHeader,omitempty"`
+	AuthResponseHeaders      []string          `json:"authResponseHeaders,omitempty"`
+	AuthResponseHeadersRegex string            `json:"authResponseHeadersRegex,omitempty"`
+	AuthRequestHeaders       []string          `json:"authRequestHeaders,omitempty"`
+	TLS                      *ClientTLS        `json:"tls,omitempty"`
+	Cache                    *ForwardAuthCache `json:"cache,omitempty"`
+	ForwardBody              bool              `json:"forwardBody,omitempty"`
+	MaxBodySize              int64             `json:"maxBodySize,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ForwardAuthCache holds the configuration of the cache of the successful authentication responses.
+type ForwardAuthCache struct {
+	TTL             *intstr.IntOrString `json:"ttl,omitempty"`
+	MaxEntries      *int                `json:"maxEntries,omitempty"`
+	KeyHeaders      []string            `json:"keyHeaders,omitempty"`
+	KeyPathSegments int                 `json:"keyPathSegments,omitempty"`
 }
 
 // ClientTLS holds TLS specific configurations as client.
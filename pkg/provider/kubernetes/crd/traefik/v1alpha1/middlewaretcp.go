@@ -20,7 +20,10 @@ type MiddlewareTCP struct {
 
 // MiddlewareTCPSpec holds the MiddlewareTCP configuration.
 type MiddlewareTCPSpec struct {
-	IPWhiteList *dynamic.TCPIPWhiteList `json:"ipWhiteList,omitempty"`
+	IPWhiteList  *dynamic.TCPIPWhiteList  `json:"ipWhiteList,omitempty"`
+	SNILimit     *dynamic.TCPSNILimit     `json:"sniLimit,omitempty"`
+	InFlightConn *dynamic.TCPInFlightConn `json:"inFlightConn,omitempty"`
+	RateLimit    *dynamic.TCPRateLimit    `json:"rateLimit,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
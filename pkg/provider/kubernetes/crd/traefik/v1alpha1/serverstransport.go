@@ -48,6 +48,8 @@ type ForwardingTimeouts struct {
 	ResponseHeaderTimeout *intstr.IntOrString `json:"responseHeaderTimeout,omitempty"`
 	// The maximum period for which an idle HTTP keep-alive connection will remain open before closing itself.
 	IdleConnTimeout *intstr.IntOrString `json:"idleConnTimeout,omitempty"`
+	// The amount of time to wait for the TLS handshake with a backend server. If zero, no timeout exists.
+	TLSHandshakeTimeout *intstr.IntOrString `json:"tlsHandshakeTimeout,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
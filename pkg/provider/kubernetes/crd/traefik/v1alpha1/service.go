@@ -56,7 +56,8 @@ type Mirroring struct {
 type MirrorService struct {
 	LoadBalancerSpec `json:",inline"`
 
-	Percent int `json:"percent,omitempty"`
+	Percent int                   `json:"percent,omitempty"`
+	Match   *dynamic.RequestMatch `json:"match,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
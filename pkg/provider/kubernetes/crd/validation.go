@@ -0,0 +1,184 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	"github.com/traefik/traefik/v2/pkg/tls"
+)
+
+// Validation holds the result of the validation of a resource.
+type Validation struct {
+	// ProviderName is the name of the provider of the configuration.
+	ProviderName string
+	// Configuration is the configuration built from the cluster resources, with the validated resource applied.
+	Configuration *dynamic.Configuration
+	// Routers, Middlewares and TLSOptions are the names of the configuration elements built from the validated resource.
+	Routers     []string
+	Middlewares []string
+	TLSOptions  []string
+}
+
+// Validator validates the resources with the logic used by the provider to build the dynamic configuration,
+// so that invalid resources can be rejected when they are applied, instead of being ignored later on.
+type Validator struct {
+	provider *Provider
+	client   Client
+}
+
+// NewValidator creates a Validator,
+// which watches the resources the validated resources may refer to until stopCh is closed.
+func (p *Provider) NewValidator(ctx context.Context, stopCh <-chan struct{}) (*Validator, error) {
+	client, err := p.newK8sClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The events are not needed, the watch only keeps the stores up to date.
+	_, err = client.WatchAll(p.Namespaces, stopCh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validator{provider: p, client: client}, nil
+}
+
+// ValidateIngressRoute validates an IngressRoute.
+// It returns a nil Validation if the IngressRoute is ignored by the provider.
+func (v *Validator) ValidateIngressRoute(ctx context.Context, ingressRoute *v1alpha1.IngressRoute) (*Validation, error) {
+	if !shouldProcessIngress(v.provider.IngressClass, ingressRoute.Annotations[annotationKubernetesIngressClass]) {
+		return nil, nil
+	}
+
+	client := validationClient{Client: v.client, ingressRoute: ingressRoute}
+
+	err := getTLSHTTP(ctx, ingressRoute, client, make(map[string]*tls.CertAndStores))
+	if err != nil {
+		return nil, fmt.Errorf("error configuring TLS: %w", err)
+	}
+
+	ingressName := ingressRoute.Name
+	if len(ingressName) == 0 {
+		ingressName = ingressRoute.GenerateName
+	}
+
+	cb := configBuilder{client, v.provider.AllowCrossNamespace}
+
+	var routers []string
+	for _, route := range ingressRoute.Spec.Routes {
+		key, _, err := v.provider.buildRoute(ctx, cb, ingressRoute, ingressName, route, make(map[string]*dynamic.Service))
+		if err != nil {
+			return nil, err
+		}
+
+		routers = append(routers, key)
+	}
+
+	return &Validation{
+		ProviderName:  providerName,
+		Configuration: v.provider.loadConfigurationFromCRD(ctx, client),
+		Routers:       routers,
+	}, nil
+}
+
+// ValidateMiddleware validates a Middleware.
+func (v *Validator) ValidateMiddleware(ctx context.Context, middleware *v1alpha1.Middleware) (*Validation, error) {
+	client := validationClient{Client: v.client, middleware: middleware}
+
+	_, err := v.provider.buildMiddleware(ctx, client, middleware, make(map[string]*dynamic.Service))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validation{
+		ProviderName:  providerName,
+		Configuration: v.provider.loadConfigurationFromCRD(ctx, client),
+		Middlewares:   []string{provider.Normalize(makeID(middleware.Namespace, middleware.Name))},
+	}, nil
+}
+
+// ValidateTLSOption validates a TLSOption.
+func (v *Validator) ValidateTLSOption(ctx context.Context, tlsOption *v1alpha1.TLSOption) (*Validation, error) {
+	for _, secretName := range tlsOption.Spec.ClientAuth.SecretNames {
+		if _, err := loadClientCA(v.client, tlsOption.Namespace, secretName); err != nil {
+			return nil, err
+		}
+	}
+
+	id := makeID(tlsOption.Namespace, tlsOption.Name)
+	if tlsOption.Name == tls.DefaultTLSConfigName {
+		id = tlsOption.Name
+
+		for _, option := range v.client.GetTLSOptions() {
+			if option.Name == tls.DefaultTLSConfigName && option.Namespace != tlsOption.Namespace {
+				return nil, fmt.Errorf("default TLS options already defined in namespace %s", option.Namespace)
+			}
+		}
+	}
+
+	return &Validation{
+		ProviderName:  providerName,
+		Configuration: v.provider.loadConfigurationFromCRD(ctx, validationClient{Client: v.client, tlsOption: tlsOption}),
+		TLSOptions:    []string{id},
+	}, nil
+}
+
+// validationClient is a Client in which the validated resource replaces the resource with the same namespace and name.
+type validationClient struct {
+	Client
+
+	ingressRoute *v1alpha1.IngressRoute
+	middleware   *v1alpha1.Middleware
+	tlsOption    *v1alpha1.TLSOption
+}
+
+func (c validationClient) GetIngressRoutes() []*v1alpha1.IngressRoute {
+	ingressRoutes := c.Client.GetIngressRoutes()
+	if c.ingressRoute == nil {
+		return ingressRoutes
+	}
+
+	result := []*v1alpha1.IngressRoute{c.ingressRoute}
+	for _, ingressRoute := range ingressRoutes {
+		if ingressRoute.Namespace != c.ingressRoute.Namespace || ingressRoute.Name != c.ingressRoute.Name {
+			result = append(result, ingressRoute)
+		}
+	}
+
+	return result
+}
+
+func (c validationClient) GetMiddlewares() []*v1alpha1.Middleware {
+	middlewares := c.Client.GetMiddlewares()
+	if c.middleware == nil {
+		return middlewares
+	}
+
+	result := []*v1alpha1.Middleware{c.middleware}
+	for _, middleware := range middlewares {
+		if middleware.Namespace != c.middleware.Namespace || middleware.Name != c.middleware.Name {
+			result = append(result, middleware)
+		}
+	}
+
+	return result
+}
+
+func (c validationClient) GetTLSOptions() []*v1alpha1.TLSOption {
+	tlsOptions := c.Client.GetTLSOptions()
+	if c.tlsOption == nil {
+		return tlsOptions
+	}
+
+	result := []*v1alpha1.TLSOption{c.tlsOption}
+	for _, tlsOption := range tlsOptions {
+		if tlsOption.Namespace != c.tlsOption.Namespace || tlsOption.Name != c.tlsOption.Name {
+			result = append(result, tlsOption)
+		}
+	}
+
+	return result
+}
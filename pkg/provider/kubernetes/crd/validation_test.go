@@ -0,0 +1,224 @@
+package crd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidator_ValidateIngressRoute(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		ingressClass    string
+		ingressRoute    *v1alpha1.IngressRoute
+		expectedRouters []string
+		expectedNil     bool
+		expectedError   bool
+	}{
+		{
+			desc: "valid",
+			ingressRoute: newIngressRoute("Host(`foo.com`) && PathPrefix(`/bar`)", "Rule", v1alpha1.Service{
+				LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: intstr.FromInt(80)},
+			}),
+			expectedRouters: []string{"default-test-route-6b204d94623b3df4370c"},
+		},
+		{
+			desc: "unsupported match kind",
+			ingressRoute: newIngressRoute("Host(`foo.com`)", "Foo", v1alpha1.Service{
+				LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: intstr.FromInt(80)},
+			}),
+			expectedError: true,
+		},
+		{
+			desc: "empty match rule",
+			ingressRoute: newIngressRoute("", "Rule", v1alpha1.Service{
+				LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: intstr.FromInt(80)},
+			}),
+			expectedError: true,
+		},
+		{
+			desc: "missing service port",
+			ingressRoute: newIngressRoute("Host(`foo.com`)", "Rule", v1alpha1.Service{
+				LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: intstr.FromInt(8080)},
+			}),
+			expectedError: true,
+		},
+		{
+			desc:         "ignored ingress class",
+			ingressClass: "foo",
+			ingressRoute: newIngressRoute("", "Rule", v1alpha1.Service{
+				LoadBalancerSpec: v1alpha1.LoadBalancerSpec{Name: "whoami", Port: intstr.FromInt(80)},
+			}),
+			expectedNil: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClientMock("services.yml")
+			validator := &Validator{provider: &Provider{IngressClass: test.ingressClass}, client: &client}
+
+			validation, err := validator.ValidateIngressRoute(context.Background(), test.ingressRoute)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if test.expectedNil {
+				assert.Nil(t, validation)
+				return
+			}
+
+			require.NotNil(t, validation)
+			assert.Equal(t, test.expectedRouters, validation.Routers)
+			for _, router := range test.expectedRouters {
+				assert.Contains(t, validation.Configuration.HTTP.Routers, router)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateMiddleware(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		middleware    *v1alpha1.Middleware
+		expectedError bool
+	}{
+		{
+			desc: "valid",
+			middleware: &v1alpha1.Middleware{
+				ObjectMeta: metav1.ObjectMeta{Name: "basicauth", Namespace: "default"},
+				Spec:       v1alpha1.MiddlewareSpec{BasicAuth: &v1alpha1.BasicAuth{Secret: "authsecret"}},
+			},
+		},
+		{
+			desc: "missing secret",
+			middleware: &v1alpha1.Middleware{
+				ObjectMeta: metav1.ObjectMeta{Name: "basicauth", Namespace: "default"},
+				Spec:       v1alpha1.MiddlewareSpec{BasicAuth: &v1alpha1.BasicAuth{Secret: "foo"}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "forward auth without address",
+			middleware: &v1alpha1.Middleware{
+				ObjectMeta: metav1.ObjectMeta{Name: "forwardauth", Namespace: "default"},
+				Spec:       v1alpha1.MiddlewareSpec{ForwardAuth: &v1alpha1.ForwardAuth{}},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClientMock("services.yml", "with_auth.yml")
+			validator := &Validator{provider: &Provider{}, client: &client}
+
+			validation, err := validator.ValidateMiddleware(context.Background(), test.middleware)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			id := makeID(test.middleware.Namespace, test.middleware.Name)
+			assert.Equal(t, []string{id}, validation.Middlewares)
+			assert.Equal(t, test.middleware.Spec.BasicAuth != nil, validation.Configuration.HTTP.Middlewares[id].BasicAuth != nil)
+		})
+	}
+}
+
+func TestValidator_ValidateTLSOption(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		tlsOption          *v1alpha1.TLSOption
+		expectedTLSOptions []string
+		expectedError      bool
+	}{
+		{
+			desc: "valid",
+			tlsOption: &v1alpha1.TLSOption{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec: v1alpha1.TLSOptionSpec{
+					MinVersion: "VersionTLS13",
+					ClientAuth: v1alpha1.ClientAuth{SecretNames: []string{"secret-ca1"}},
+				},
+			},
+			expectedTLSOptions: []string{"default-foo"},
+		},
+		{
+			desc: "missing client CA secret",
+			tlsOption: &v1alpha1.TLSOption{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec: v1alpha1.TLSOptionSpec{
+					ClientAuth: v1alpha1.ClientAuth{SecretNames: []string{"secret-ca3"}},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "default options in the same namespace",
+			tlsOption: &v1alpha1.TLSOption{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "foo"},
+			},
+			expectedTLSOptions: []string{"default"},
+		},
+		{
+			desc: "default options already defined in another namespace",
+			tlsOption: &v1alpha1.TLSOption{
+				ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "bar"},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClientMock("services.yml", "with_tls_options.yml", "with_default_tls_options.yml")
+			validator := &Validator{provider: &Provider{}, client: &client}
+
+			validation, err := validator.ValidateTLSOption(context.Background(), test.tlsOption)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedTLSOptions, validation.TLSOptions)
+			assert.Equal(t, test.tlsOption.Spec.MinVersion, validation.Configuration.TLS.Options[test.expectedTLSOptions[0]].MinVersion)
+		})
+	}
+}
+
+func newIngressRoute(match, kind string, services ...v1alpha1.Service) *v1alpha1.IngressRoute {
+	return &v1alpha1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test.route",
+			Namespace: "default",
+			Annotations: map[string]string{
+				annotationKubernetesIngressClass: traefikDefaultIngressClass,
+			},
+		},
+		Spec: v1alpha1.IngressRouteSpec{
+			EntryPoints: []string{"foo"},
+			Routes: []v1alpha1.Route{
+				{Match: match, Kind: kind, Services: services},
+			},
+		},
+	}
+}
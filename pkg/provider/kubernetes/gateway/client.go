@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	kubeerror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -77,6 +79,7 @@ type clientWrapper struct {
 	watchedNamespaces []string
 
 	labelSelector string
+	zone          string
 }
 
 func createClientFromConfig(c *rest.Config) (*clientWrapper, error) {
@@ -183,7 +186,7 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 
 		factoryKube := informers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, informers.WithNamespace(ns))
 		factoryKube.Core().V1().Services().Informer().AddEventHandler(eventHandler)
-		factoryKube.Core().V1().Endpoints().Informer().AddEventHandler(eventHandler)
+		factoryKube.Discovery().V1beta1().EndpointSlices().Informer().AddEventHandler(eventHandler)
 
 		factorySecret := informers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, informers.WithNamespace(ns), informers.WithTweakListOptions(notOwnedByHelm))
 		factorySecret.Core().V1().Secrets().Informer().AddEventHandler(eventHandler)
@@ -410,16 +413,35 @@ func (c *clientWrapper) GetService(namespace, name string) (*corev1.Service, boo
 	return service, exist, err
 }
 
-// GetEndpoints returns the named endpoints from the given namespace.
+// GetEndpoints returns the endpoints of the named service from the given namespace, built from its EndpointSlices.
 func (c *clientWrapper) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
 	if !c.isWatchedNamespace(namespace) {
 		return nil, false, fmt.Errorf("failed to get endpoints %s/%s: namespace is not within watched namespaces", namespace, name)
 	}
 
-	endpoint, err := c.factoriesKube[c.lookupNamespace(namespace)].Core().V1().Endpoints().Lister().Endpoints(namespace).Get(name)
+	factory := c.factoriesKube[c.lookupNamespace(namespace)]
+
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1beta1.LabelServiceName: name})
+	slices, err := factory.Discovery().V1beta1().EndpointSlices().Lister().EndpointSlices(namespace).List(selector)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(slices) == 0 {
+		return nil, false, nil
+	}
+
+	service, err := factory.Core().V1().Services().Lister().Services(namespace).Get(name)
 	exist, err := translateNotFoundError(err)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !exist {
+		service = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
 
-	return endpoint, exist, err
+	return k8s.EndpointsFromSlices(service, slices, c.zone), true, nil
 }
 
 // GetSecret returns the named secret from the given namespace.
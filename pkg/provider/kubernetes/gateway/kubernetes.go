@@ -45,6 +45,7 @@ type Provider struct {
 	Namespaces       []string              `description:"Kubernetes namespaces." json:"namespaces,omitempty" toml:"namespaces,omitempty" yaml:"namespaces,omitempty" export:"true"`
 	LabelSelector    string                `description:"Kubernetes label selector to select specific GatewayClasses." json:"labelSelector,omitempty" toml:"labelSelector,omitempty" yaml:"labelSelector,omitempty" export:"true"`
 	ThrottleDuration ptypes.Duration       `description:"Kubernetes refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
+	Zone             string                `description:"Zone of the Traefik instance, used to route preferentially to the endpoints of the same zone of the Services enabling topology-aware hints." json:"zone,omitempty" toml:"zone,omitempty" yaml:"zone,omitempty" export:"true"`
 	EntryPoints      map[string]Entrypoint `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
 
 	lastConfiguration safe.Safe
@@ -86,6 +87,7 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 		return nil, err
 	}
 	client.labelSelector = p.LabelSelector
+	client.zone = p.Zone
 
 	return client, nil
 }
@@ -142,6 +144,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 						logger.Error("Unable to hash the configuration")
 					case p.lastConfiguration.Get() == confHash:
 						logger.Debugf("Skipping Kubernetes event kind %T", event)
+						provider.ReportRefresh(providerName)
 					default:
 						p.lastConfiguration.Set(confHash)
 						configurationChan <- dynamic.Message{
@@ -160,6 +163,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 		notify := func(err error, time time.Duration) {
 			logger.Errorf("Provider connection error: %v; retrying in %s", err, time)
+			provider.ReportFailure(providerName, err)
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxPool), notify)
 		if err != nil {
@@ -215,6 +219,7 @@ func (p *Provider) loadConfigurationFromGateway(ctx context.Context, client Clie
 	}
 
 	cfgs := map[string]*dynamic.Configuration{}
+	namespaces := provider.Namespaces{}
 
 	// TODO check if we can only use the default filtering mechanism
 	for _, gateway := range client.GetGateways() {
@@ -232,9 +237,12 @@ func (p *Provider) loadConfigurationFromGateway(ctx context.Context, client Clie
 		}
 
 		cfgs[gateway.Name+gateway.Namespace] = cfg
+		// The routes of a gateway, and the services they define, are in the namespace of the gateway.
+		namespaces.AddConfiguration(cfg, gateway.Namespace)
 	}
 
 	conf := provider.Merge(ctx, cfgs)
+	conf.Namespaces = namespaces.Elements()
 
 	conf.TLS = &dynamic.TLSConfiguration{}
 
@@ -1234,6 +1234,8 @@ func TestLoadHTTPRoutes(t *testing.T) {
 
 			p := Provider{EntryPoints: test.entryPoints}
 			conf := p.loadConfigurationFromGateway(context.Background(), newClientMock(test.paths...))
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -1680,6 +1682,8 @@ func TestLoadTCPRoutes(t *testing.T) {
 
 			p := Provider{EntryPoints: test.entryPoints}
 			conf := p.loadConfigurationFromGateway(context.Background(), newClientMock(test.paths...))
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -2431,6 +2435,8 @@ func TestLoadTLSRoutes(t *testing.T) {
 
 			p := Provider{EntryPoints: test.entryPoints}
 			conf := p.loadConfigurationFromGateway(context.Background(), newClientMock(test.paths...))
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -2722,6 +2728,8 @@ func TestLoadMixedRoutes(t *testing.T) {
 
 			p := Provider{EntryPoints: test.entryPoints}
 			conf := p.loadConfigurationFromGateway(context.Background(), newClientMock(test.paths...))
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 			assert.Equal(t, test.expected, conf)
 		})
 	}
@@ -3167,3 +3175,15 @@ func TestHostSNI(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadNamespaces(t *testing.T) {
+	p := Provider{EntryPoints: map[string]Entrypoint{"web": {Address: ":80"}}}
+	conf := p.loadConfigurationFromGateway(context.Background(), newClientMock("services.yml", "httproute/simple.yml"))
+
+	expected := []dynamic.ElementNamespace{
+		{Kind: "router", Name: "default-http-app-1-my-gateway-web-1c0cf64bde37d9d0df06", Namespace: "default"},
+		{Kind: "service", Name: "default-http-app-1-my-gateway-web-1c0cf64bde37d9d0df06-wrr", Namespace: "default"},
+		{Kind: "service", Name: "default-whoami-80", Namespace: "default"},
+	}
+	assert.Equal(t, expected, conf.Namespaces)
+}
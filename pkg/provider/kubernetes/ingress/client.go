@@ -14,6 +14,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/provider/kubernetes/k8s"
 	traefikversion "github.com/traefik/traefik/v2/pkg/version"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	kubeerror "k8s.io/apimachinery/pkg/api/errors"
@@ -56,6 +57,7 @@ type clientWrapper struct {
 	factoriesIngress     map[string]informers.SharedInformerFactory
 	clusterFactory       informers.SharedInformerFactory
 	ingressLabelSelector string
+	zone                 string
 	isNamespaceAll       bool
 	watchedNamespaces    []string
 }
@@ -171,7 +173,7 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 
 		factoryKube := informers.NewSharedInformerFactoryWithOptions(c.clientset, resyncPeriod, informers.WithNamespace(ns))
 		factoryKube.Core().V1().Services().Informer().AddEventHandler(eventHandler)
-		factoryKube.Core().V1().Endpoints().Informer().AddEventHandler(eventHandler)
+		factoryKube.Discovery().V1beta1().EndpointSlices().Informer().AddEventHandler(eventHandler)
 		c.factoriesKube[ns] = factoryKube
 
 		factorySecret := informers.NewSharedInformerFactoryWithOptions(c.clientset, resyncPeriod, informers.WithNamespace(ns), informers.WithTweakListOptions(notOwnedByHelm))
@@ -449,15 +451,35 @@ func (c *clientWrapper) GetService(namespace, name string) (*corev1.Service, boo
 	return service, exist, err
 }
 
-// GetEndpoints returns the named endpoints from the given namespace.
+// GetEndpoints returns the endpoints of the named service from the given namespace, built from its EndpointSlices.
 func (c *clientWrapper) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
 	if !c.isWatchedNamespace(namespace) {
 		return nil, false, fmt.Errorf("failed to get endpoints %s/%s: namespace is not within watched namespaces", namespace, name)
 	}
 
-	endpoint, err := c.factoriesKube[c.lookupNamespace(namespace)].Core().V1().Endpoints().Lister().Endpoints(namespace).Get(name)
+	factory := c.factoriesKube[c.lookupNamespace(namespace)]
+
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1beta1.LabelServiceName: name})
+	slices, err := factory.Discovery().V1beta1().EndpointSlices().Lister().EndpointSlices(namespace).List(selector)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(slices) == 0 {
+		return nil, false, nil
+	}
+
+	service, err := factory.Core().V1().Services().Lister().Services(namespace).Get(name)
 	exist, err := translateNotFoundError(err)
-	return endpoint, exist, err
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !exist {
+		service = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+
+	return k8s.EndpointsFromSlices(service, slices, c.zone), true, nil
 }
 
 // GetSecret returns the named secret from the given namespace.
@@ -482,39 +504,26 @@ func (c *clientWrapper) GetIngressClasses() ([]*networkingv1.IngressClass, error
 		return nil, errors.New("cluster factory not loaded")
 	}
 
-	var ics []*networkingv1.IngressClass
 	if !supportsNetworkingV1Ingress(serverVersion) {
 		ingressClasses, err := c.clusterFactory.Networking().V1beta1().IngressClasses().Lister().List(labels.Everything())
 		if err != nil {
 			return nil, err
 		}
 
+		var ics []*networkingv1.IngressClass
 		for _, ic := range ingressClasses {
-			if ic.Spec.Controller == traefikDefaultIngressClassController {
-				icN, err := toNetworkingV1IngressClass(ic)
-				if err != nil {
-					log.WithoutContext().Errorf("Failed to convert ingress class %s from networking/v1beta1 to networking/v1: %v", ic.Name, err)
-					continue
-				}
-				ics = append(ics, icN)
+			icN, err := toNetworkingV1IngressClass(ic)
+			if err != nil {
+				log.WithoutContext().Errorf("Failed to convert ingress class %s from networking/v1beta1 to networking/v1: %v", ic.Name, err)
+				continue
 			}
+			ics = append(ics, icN)
 		}
 
 		return ics, nil
 	}
 
-	ingressClasses, err := c.clusterFactory.Networking().V1().IngressClasses().Lister().List(labels.Everything())
-	if err != nil {
-		return nil, err
-	}
-
-	for _, ic := range ingressClasses {
-		if ic.Spec.Controller == traefikDefaultIngressClassController {
-			ics = append(ics, ic)
-		}
-	}
-
-	return ics, nil
+	return c.clusterFactory.Networking().V1().IngressClasses().Lister().List(labels.Everything())
 }
 
 // lookupNamespace returns the lookup namespace key for the given namespace.
@@ -571,12 +580,17 @@ func supportsIngressClass(serverVersion *version.Version) bool {
 	return ingressClassVersion.LessThanOrEqual(serverVersion)
 }
 
-// filterIngressClassByName return a slice containing ingressclasses with the correct name.
-func filterIngressClassByName(ingressClassName string, ics []*networkingv1.IngressClass) []*networkingv1.IngressClass {
+// filterIngressClasses returns a slice containing the ingressclasses handled by Traefik,
+// with the given name if not empty.
+func filterIngressClasses(ingressClassName string, ics []*networkingv1.IngressClass) []*networkingv1.IngressClass {
 	var ingressClasses []*networkingv1.IngressClass
 
 	for _, ic := range ics {
-		if ic.Name == ingressClassName {
+		if ic.Spec.Controller != traefikDefaultIngressClassController {
+			continue
+		}
+
+		if ingressClassName == "" || ic.Name == ingressClassName {
 			ingressClasses = append(ingressClasses, ic)
 		}
 	}
@@ -584,7 +598,28 @@ func filterIngressClassByName(ingressClassName string, ics []*networkingv1.Ingre
 	return ingressClasses
 }
 
-//  Ingress in networking.k8s.io/v1 is supported starting 1.19.
+// findDefaultIngressClass returns the ingressclass marked as the default one of the cluster, whatever its controller.
+// When several ingressclasses are marked as default, the most recently created one is elected, like Kubernetes does.
+func findDefaultIngressClass(ics []*networkingv1.IngressClass) *networkingv1.IngressClass {
+	var defaultIngressClass *networkingv1.IngressClass
+
+	for _, ic := range ics {
+		if ic.Annotations[annotationIsDefaultIngressClass] != "true" {
+			continue
+		}
+
+		if defaultIngressClass == nil ||
+			defaultIngressClass.CreationTimestamp.Before(&ic.CreationTimestamp) ||
+			defaultIngressClass.CreationTimestamp.Equal(&ic.CreationTimestamp) && ic.Name < defaultIngressClass.Name {
+			defaultIngressClass = ic
+		}
+	}
+
+	return defaultIngressClass
+}
+
+//	Ingress in networking.k8s.io/v1 is supported starting 1.19.
+//
 // thus, we query it in K8s starting 1.19.
 func supportsNetworkingV1Ingress(serverVersion *version.Version) bool {
 	ingressNetworkingVersion := version.Must(version.NewVersion("1.19"))
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/api/networking/v1beta1"
 	kubeerror "k8s.io/apimachinery/pkg/api/errors"
@@ -191,37 +192,41 @@ func TestClientIgnoresHelmOwnedSecrets(t *testing.T) {
 	assert.False(t, found)
 }
 
-func TestClientIgnoresEmptyEndpointUpdates(t *testing.T) {
-	emptyEndpoint := &corev1.Endpoints{
+func TestClientIgnoresEmptyEndpointSliceUpdates(t *testing.T) {
+	emptyEndpointSlice := &discoveryv1beta1.EndpointSlice{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            "empty-endpoint",
+			Name:            "empty-endpointslice",
 			Namespace:       "test",
 			ResourceVersion: "1244",
 			Annotations: map[string]string{
 				"test-annotation": "_",
 			},
 		},
+		AddressType: discoveryv1beta1.AddressTypeIPv4,
 	}
 
-	filledEndpoint := &corev1.Endpoints{
+	portName := "testing"
+	port := int32(1337)
+	protocol := corev1.ProtocolTCP
+
+	filledEndpointSlice := &discoveryv1beta1.EndpointSlice{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            "filled-endpoint",
+			Name:            "filled-endpointslice",
 			Namespace:       "test",
 			ResourceVersion: "1234",
 		},
-		Subsets: []corev1.EndpointSubset{{
-			Addresses: []corev1.EndpointAddress{{
-				IP: "10.13.37.1",
-			}},
-			Ports: []corev1.EndpointPort{{
-				Name:     "testing",
-				Port:     1337,
-				Protocol: "tcp",
-			}},
+		AddressType: discoveryv1beta1.AddressTypeIPv4,
+		Endpoints: []discoveryv1beta1.Endpoint{{
+			Addresses: []string{"10.13.37.1"},
+		}},
+		Ports: []discoveryv1beta1.EndpointPort{{
+			Name:     &portName,
+			Port:     &port,
+			Protocol: &protocol,
 		}},
 	}
 
-	kubeClient := kubefake.NewSimpleClientset(emptyEndpoint, filledEndpoint)
+	kubeClient := kubefake.NewSimpleClientset(emptyEndpointSlice, filledEndpointSlice)
 
 	discovery, _ := kubeClient.Discovery().(*fakediscovery.FakeDiscovery)
 	discovery.FakedServerVersion = &version.Info{
@@ -237,50 +242,49 @@ func TestClientIgnoresEmptyEndpointUpdates(t *testing.T) {
 
 	select {
 	case event := <-eventCh:
-		ep, ok := event.(*corev1.Endpoints)
+		slice, ok := event.(*discoveryv1beta1.EndpointSlice)
 		require.True(t, ok)
 
-		assert.True(t, ep.Name == "empty-endpoint" || ep.Name == "filled-endpoint")
+		assert.True(t, slice.Name == "empty-endpointslice" || slice.Name == "filled-endpointslice")
 	case <-time.After(50 * time.Millisecond):
-		assert.Fail(t, "expected to receive event for endpoints")
+		assert.Fail(t, "expected to receive event for endpointslices")
 	}
 
-	emptyEndpoint, err = kubeClient.CoreV1().Endpoints("test").Get(context.TODO(), "empty-endpoint", metav1.GetOptions{})
+	emptyEndpointSlice, err = kubeClient.DiscoveryV1beta1().EndpointSlices("test").Get(context.TODO(), "empty-endpointslice", metav1.GetOptions{})
 	assert.NoError(t, err)
 
-	// Update endpoint annotation and resource version (apparently not done by fake client itself)
+	// Update endpointslice annotation and resource version (apparently not done by fake client itself)
 	// to show an update that should not trigger an update event on our eventCh.
-	// This reflects the behavior of kubernetes controllers which use endpoint annotations for leader election.
-	emptyEndpoint.Annotations["test-annotation"] = "___"
-	emptyEndpoint.ResourceVersion = "1245"
-	_, err = kubeClient.CoreV1().Endpoints("test").Update(context.TODO(), emptyEndpoint, metav1.UpdateOptions{})
+	emptyEndpointSlice.Annotations["test-annotation"] = "___"
+	emptyEndpointSlice.ResourceVersion = "1245"
+	_, err = kubeClient.DiscoveryV1beta1().EndpointSlices("test").Update(context.TODO(), emptyEndpointSlice, metav1.UpdateOptions{})
 	require.NoError(t, err)
 
 	select {
 	case event := <-eventCh:
-		ep, ok := event.(*corev1.Endpoints)
+		slice, ok := event.(*discoveryv1beta1.EndpointSlice)
 		require.True(t, ok)
 
-		assert.Fail(t, "didn't expect to receive event for empty endpoint update", ep.Name)
+		assert.Fail(t, "didn't expect to receive event for empty endpointslice update", slice.Name)
 	case <-time.After(50 * time.Millisecond):
 	}
 
-	filledEndpoint, err = kubeClient.CoreV1().Endpoints("test").Get(context.TODO(), "filled-endpoint", metav1.GetOptions{})
+	filledEndpointSlice, err = kubeClient.DiscoveryV1beta1().EndpointSlices("test").Get(context.TODO(), "filled-endpointslice", metav1.GetOptions{})
 	assert.NoError(t, err)
 
-	filledEndpoint.Subsets[0].Addresses[0].IP = "10.13.37.2"
-	filledEndpoint.ResourceVersion = "1235"
-	_, err = kubeClient.CoreV1().Endpoints("test").Update(context.TODO(), filledEndpoint, metav1.UpdateOptions{})
+	filledEndpointSlice.Endpoints[0].Addresses[0] = "10.13.37.2"
+	filledEndpointSlice.ResourceVersion = "1235"
+	_, err = kubeClient.DiscoveryV1beta1().EndpointSlices("test").Update(context.TODO(), filledEndpointSlice, metav1.UpdateOptions{})
 	require.NoError(t, err)
 
 	select {
 	case event := <-eventCh:
-		ep, ok := event.(*corev1.Endpoints)
+		slice, ok := event.(*discoveryv1beta1.EndpointSlice)
 		require.True(t, ok)
 
-		assert.Equal(t, "filled-endpoint", ep.Name)
+		assert.Equal(t, "filled-endpointslice", slice.Name)
 	case <-time.After(50 * time.Millisecond):
-		assert.Fail(t, "expected to receive event for filled endpoint")
+		assert.Fail(t, "expected to receive event for filled endpointslice")
 	}
 
 	select {
@@ -290,6 +294,60 @@ func TestClientIgnoresEmptyEndpointUpdates(t *testing.T) {
 	}
 }
 
+func TestClientGetEndpoints(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "whoami",
+			Namespace: "test",
+		},
+	}
+
+	port := int32(80)
+
+	endpointSlice := &discoveryv1beta1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "whoami-abcde",
+			Namespace: "test",
+			Labels:    map[string]string{discoveryv1beta1.LabelServiceName: "whoami"},
+		},
+		AddressType: discoveryv1beta1.AddressTypeIPv4,
+		Endpoints: []discoveryv1beta1.Endpoint{{
+			Addresses: []string{"10.10.0.1"},
+		}},
+		Ports: []discoveryv1beta1.EndpointPort{{
+			Port: &port,
+		}},
+	}
+
+	kubeClient := kubefake.NewSimpleClientset(service, endpointSlice)
+
+	discovery, _ := kubeClient.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.FakedServerVersion = &version.Info{
+		GitVersion: "v1.19",
+	}
+
+	client := newClientImpl(kubeClient)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	_, err := client.WatchAll(nil, stopCh)
+	require.NoError(t, err)
+
+	endpoints, found, err := client.GetEndpoints("test", "whoami")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	assert.Equal(t, []corev1.EndpointSubset{{
+		Addresses: []corev1.EndpointAddress{{IP: "10.10.0.1"}},
+		Ports:     []corev1.EndpointPort{{Port: 80}},
+	}}, endpoints.Subsets)
+
+	_, found, err = client.GetEndpoints("test", "missing")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
 func TestClientUsesCorrectServerVersion(t *testing.T) {
 	ingressV1Beta := &v1beta1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
@@ -358,3 +416,75 @@ func TestClientUsesCorrectServerVersion(t *testing.T) {
 	case <-time.After(50 * time.Millisecond):
 	}
 }
+
+func TestFindDefaultIngressClass(t *testing.T) {
+	older := metav1.NewTime(time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(older.Add(time.Hour))
+
+	newIngressClass := func(name string, isDefault bool, creationTimestamp metav1.Time) *networkingv1.IngressClass {
+		ic := &networkingv1.IngressClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: creationTimestamp},
+		}
+		if isDefault {
+			ic.Annotations = map[string]string{annotationIsDefaultIngressClass: "true"}
+		}
+		return ic
+	}
+
+	testCases := []struct {
+		desc           string
+		ingressClasses []*networkingv1.IngressClass
+		expected       string
+	}{
+		{
+			desc:           "no ingressClass",
+			ingressClasses: nil,
+		},
+		{
+			desc: "no default ingressClass",
+			ingressClasses: []*networkingv1.IngressClass{
+				newIngressClass("traefik", false, older),
+			},
+		},
+		{
+			desc: "one default ingressClass",
+			ingressClasses: []*networkingv1.IngressClass{
+				newIngressClass("nginx", false, newer),
+				newIngressClass("traefik", true, older),
+			},
+			expected: "traefik",
+		},
+		{
+			desc: "most recently created default ingressClass",
+			ingressClasses: []*networkingv1.IngressClass{
+				newIngressClass("traefik", true, older),
+				newIngressClass("nginx", true, newer),
+			},
+			expected: "nginx",
+		},
+		{
+			desc: "default ingressClasses created at the same time",
+			ingressClasses: []*networkingv1.IngressClass{
+				newIngressClass("traefik", true, older),
+				newIngressClass("nginx", true, older),
+			},
+			expected: "nginx",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ic := findDefaultIngressClass(test.ingressClasses)
+			if test.expected == "" {
+				assert.Nil(t, ic)
+				return
+			}
+
+			require.NotNil(t, ic)
+			assert.Equal(t, test.expected, ic.Name)
+		})
+	}
+}
@@ -29,6 +29,7 @@ import (
 
 const (
 	annotationKubernetesIngressClass     = "kubernetes.io/ingress.class"
+	annotationIsDefaultIngressClass      = "ingressclass.kubernetes.io/is-default-class"
 	traefikDefaultIngressClass           = "traefik"
 	traefikDefaultIngressClassController = "traefik.io/ingress-controller"
 	defaultPathMatcher                   = "PathPrefix"
@@ -45,6 +46,7 @@ type Provider struct {
 	IngressEndpoint    *EndpointIngress `description:"Kubernetes Ingress Endpoint." json:"ingressEndpoint,omitempty" toml:"ingressEndpoint,omitempty" yaml:"ingressEndpoint,omitempty" export:"true"`
 	ThrottleDuration   ptypes.Duration  `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
 	AllowEmptyServices bool             `description:"Allow creation of services without endpoints." json:"allowEmptyServices,omitempty" toml:"allowEmptyServices,omitempty" yaml:"allowEmptyServices,omitempty" export:"true"`
+	Zone               string           `description:"Zone of the Traefik instance, used to route preferentially to the endpoints of the same zone of the Services enabling topology-aware hints." json:"zone,omitempty" toml:"zone,omitempty" yaml:"zone,omitempty" export:"true"`
 	lastConfiguration  safe.Safe
 }
 
@@ -88,6 +90,7 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	}
 
 	cl.ingressLabelSelector = p.LabelSelector
+	cl.zone = p.Zone
 	return cl, nil
 }
 
@@ -145,6 +148,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 						logger.Error("Unable to hash the configuration")
 					case p.lastConfiguration.Get() == confHash:
 						logger.Debugf("Skipping Kubernetes event kind %T", event)
+						provider.ReportRefresh("kubernetes")
 					default:
 						p.lastConfiguration.Set(confHash)
 						configurationChan <- dynamic.Message{
@@ -163,6 +167,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 		notify := func(err error, time time.Duration) {
 			logger.Errorf("Provider connection error: %s; retrying in %s", err, time)
+			provider.ReportFailure("kubernetes", err)
 		}
 
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxPool), notify)
@@ -191,6 +196,7 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 	}
 
 	var ingressClasses []*networkingv1.IngressClass
+	var defaultIngressClass *networkingv1.IngressClass
 
 	if supportsIngressClass(serverVersion) {
 		ics, err := client.GetIngressClasses()
@@ -198,20 +204,18 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 			log.FromContext(ctx).Warnf("Failed to list ingress classes: %v", err)
 		}
 
-		if p.IngressClass != "" {
-			ingressClasses = filterIngressClassByName(p.IngressClass, ics)
-		} else {
-			ingressClasses = ics
-		}
+		ingressClasses = filterIngressClasses(p.IngressClass, ics)
+		defaultIngressClass = findDefaultIngressClass(ics)
 	}
 
 	ingresses := client.GetIngresses()
 
+	namespaces := provider.Namespaces{}
 	certConfigs := make(map[string]*tls.CertAndStores)
 	for _, ingress := range ingresses {
 		ctx = log.With(ctx, log.Str("ingress", ingress.Name), log.Str("namespace", ingress.Namespace))
 
-		if !p.shouldProcessIngress(ingress, ingressClasses) {
+		if !p.shouldProcessIngress(ingress, ingressClasses, defaultIngressClass) {
 			continue
 		}
 
@@ -263,6 +267,8 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 
 			conf.HTTP.Routers["default-router"] = rt
 			conf.HTTP.Services["default-backend"] = service
+			namespaces.Add("router", "default-router", ingress.Namespace)
+			namespaces.Add("service", "default-backend", ingress.Namespace)
 		}
 
 		routers := map[string][]*dynamic.Router{}
@@ -302,6 +308,7 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 
 				serviceName := provider.Normalize(ingress.Namespace + "-" + pa.Backend.Service.Name + "-" + portString)
 				conf.HTTP.Services[serviceName] = service
+				namespaces.Add("service", serviceName, ingress.Namespace)
 
 				routerKey := strings.TrimPrefix(provider.Normalize(ingress.Name+"-"+ingress.Namespace+"-"+rule.Host+pa.Path), "-")
 				routers[routerKey] = append(routers[routerKey], loadRouter(rule, pa, rtConfig, serviceName))
@@ -311,6 +318,7 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 		for routerKey, conflictingRouters := range routers {
 			if len(conflictingRouters) == 1 {
 				conf.HTTP.Routers[routerKey] = conflictingRouters[0]
+				namespaces.Add("router", routerKey, ingress.Namespace)
 				continue
 			}
 
@@ -324,10 +332,13 @@ func (p *Provider) loadConfigurationFromIngresses(ctx context.Context, client Cl
 				}
 
 				conf.HTTP.Routers[key] = router
+				namespaces.Add("router", key, ingress.Namespace)
 			}
 		}
 	}
 
+	conf.Namespaces = namespaces.Elements()
+
 	certs := getTLSConfig(certConfigs)
 	if len(certs) > 0 {
 		conf.TLS = &dynamic.TLSConfiguration{
@@ -377,22 +388,32 @@ func (p *Provider) updateIngressStatus(ing *networkingv1.Ingress, k8sClient Clie
 	return k8sClient.UpdateIngressStatus(ing, service.Status.LoadBalancer.Ingress)
 }
 
-func (p *Provider) shouldProcessIngress(ingress *networkingv1.Ingress, ingressClasses []*networkingv1.IngressClass) bool {
+func (p *Provider) shouldProcessIngress(ingress *networkingv1.Ingress, ingressClasses []*networkingv1.IngressClass, defaultIngressClass *networkingv1.IngressClass) bool {
 	// configuration through the new kubernetes ingressClass
 	if ingress.Spec.IngressClassName != nil {
-		for _, ic := range ingressClasses {
-			if *ingress.Spec.IngressClassName == ic.ObjectMeta.Name {
-				return true
-			}
-		}
+		return containsIngressClass(ingressClasses, *ingress.Spec.IngressClassName)
+	}
 
-		return false
+	// an Ingress without any class belongs to the default ingressClass of the cluster, if any,
+	// and is ignored when this class is handled by another controller.
+	if ingress.Annotations[annotationKubernetesIngressClass] == "" && defaultIngressClass != nil {
+		return containsIngressClass(ingressClasses, defaultIngressClass.Name)
 	}
 
 	return p.IngressClass == ingress.Annotations[annotationKubernetesIngressClass] ||
 		len(p.IngressClass) == 0 && ingress.Annotations[annotationKubernetesIngressClass] == traefikDefaultIngressClass
 }
 
+func containsIngressClass(ingressClasses []*networkingv1.IngressClass, name string) bool {
+	for _, ic := range ingressClasses {
+		if ic.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func buildHostRule(host string) string {
 	if strings.HasPrefix(host, "*.") {
 		return "HostRegexp(`" + strings.Replace(host, "*.", "{subdomain:[a-zA-Z0-9-]+}.", 1) + "`)"
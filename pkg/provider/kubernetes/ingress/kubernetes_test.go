@@ -1597,6 +1597,62 @@ func TestLoadConfigurationFromIngresses(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:          "v19 Ingress without ingressClass and default traefik ingressClass",
+			serverVersion: "v1.19",
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{},
+				HTTP: &dynamic.HTTPConfiguration{
+					Middlewares: map[string]*dynamic.Middleware{},
+					Routers: map[string]*dynamic.Router{
+						"testing-bar": {
+							Rule:    "PathPrefix(`/bar`)",
+							Service: "testing-service1-80",
+						},
+					},
+					Services: map[string]*dynamic.Service{
+						"testing-service1-80": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								PassHostHeader: Bool(true),
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:8080",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:          "v19 Ingress without ingressClass and default ingressClass of another controller",
+			serverVersion: "v1.19",
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{},
+				HTTP: &dynamic.HTTPConfiguration{
+					Middlewares: map[string]*dynamic.Middleware{},
+					Routers: map[string]*dynamic.Router{
+						"testing-bar": {
+							Rule:    "PathPrefix(`/bar`)",
+							Service: "testing-service1-80",
+						},
+					},
+					Services: map[string]*dynamic.Service{
+						"testing-service1-80": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								PassHostHeader: Bool(true),
+								Servers: []dynamic.Server{
+									{
+										URL: "http://10.10.0.1:8080",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			desc:          "v19 Ingress with named port",
 			serverVersion: "v1.19",
@@ -1705,6 +1761,8 @@ func TestLoadConfigurationFromIngresses(t *testing.T) {
 
 			p := Provider{IngressClass: test.ingressClass, AllowEmptyServices: test.allowEmptyServices}
 			conf := p.loadConfigurationFromIngresses(context.Background(), clientMock)
+			// The namespaces are checked by a dedicated test.
+			conf.Namespaces = nil
 
 			assert.Equal(t, test.expected, conf)
 		})
@@ -1888,3 +1946,23 @@ func TestGetCertificates(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigurationFromIngressesNamespaces(t *testing.T) {
+	desc := "2 ingresses in different namespace with same service name"
+	clientMock := newClientMock("v1.17",
+		generateTestFilename("_ingress", desc),
+		generateTestFilename("_endpoint", desc),
+		generateTestFilename("_service", desc),
+	)
+
+	p := Provider{}
+	conf := p.loadConfigurationFromIngresses(context.Background(), clientMock)
+
+	expected := []dynamic.ElementNamespace{
+		{Kind: "router", Name: "testing-traefik-tchouk-bar", Namespace: "testing"},
+		{Kind: "router", Name: "toto-toto-traefik-tchouk-bar", Namespace: "toto"},
+		{Kind: "service", Name: "testing-service1-tchouk", Namespace: "testing"},
+		{Kind: "service", Name: "toto-service1-tchouk", Namespace: "toto"},
+	}
+	assert.Equal(t, expected, conf.Namespaces)
+}
@@ -0,0 +1,152 @@
+package k8s
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationTopologyAwareHints is the annotation enabling the topology-aware hints of a Service.
+const AnnotationTopologyAwareHints = "service.kubernetes.io/topology-aware-hints"
+
+// EndpointsFromSlices merges the EndpointSlices of a Service into its Endpoints,
+// with one subset per EndpointSlice, the EndpointSlices being sorted by name.
+// When the Service enables the topology-aware hints, and the zone is not empty,
+// only the ready endpoints of the zone are kept,
+// unless some ready endpoints have no zone, or none of them is in the zone.
+// As the Kubernetes EndpointSlice controller assigns the hints of an endpoint to its own zone,
+// the zone of the endpoints is used as their hint.
+func EndpointsFromSlices(service *corev1.Service, slices []*discoveryv1beta1.EndpointSlice, zone string) *corev1.Endpoints {
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      service.Name,
+			Namespace: service.Namespace,
+		},
+	}
+
+	sorted := make([]*discoveryv1beta1.EndpointSlice, len(slices))
+	copy(sorted, slices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	addressType := primaryAddressType(service)
+
+	var inZoneOnly bool
+	if zone != "" && strings.EqualFold(service.Annotations[AnnotationTopologyAwareHints], "auto") {
+		inZoneOnly = useZoneHints(sorted, addressType, zone)
+	}
+
+	for _, slice := range sorted {
+		if !isAddressTypeAccepted(slice.AddressType, addressType) {
+			continue
+		}
+
+		var subset corev1.EndpointSubset
+		for _, endpoint := range slice.Endpoints {
+			if inZoneOnly && endpoint.Topology[corev1.LabelTopologyZone] != zone {
+				continue
+			}
+
+			for _, ip := range endpoint.Addresses {
+				address := corev1.EndpointAddress{
+					IP:        ip,
+					NodeName:  endpoint.NodeName,
+					TargetRef: endpoint.TargetRef,
+				}
+				if endpoint.Hostname != nil {
+					address.Hostname = *endpoint.Hostname
+				}
+
+				if isReady(endpoint) {
+					subset.Addresses = append(subset.Addresses, address)
+				} else {
+					subset.NotReadyAddresses = append(subset.NotReadyAddresses, address)
+				}
+			}
+		}
+
+		for _, port := range slice.Ports {
+			endpointPort := corev1.EndpointPort{AppProtocol: port.AppProtocol}
+			if port.Name != nil {
+				endpointPort.Name = *port.Name
+			}
+			if port.Port != nil {
+				endpointPort.Port = *port.Port
+			}
+			if port.Protocol != nil {
+				endpointPort.Protocol = *port.Protocol
+			}
+
+			subset.Ports = append(subset.Ports, endpointPort)
+		}
+
+		if len(subset.Addresses) == 0 && len(subset.NotReadyAddresses) == 0 {
+			continue
+		}
+
+		endpoints.Subsets = append(endpoints.Subsets, subset)
+	}
+
+	return endpoints
+}
+
+// useZoneHints reports whether the hints can be honored, i.e. whether all the ready endpoints have a zone,
+// and some of them are in the given zone.
+func useZoneHints(slices []*discoveryv1beta1.EndpointSlice, addressType discoveryv1beta1.AddressType, zone string) bool {
+	var inZone bool
+	for _, slice := range slices {
+		if !isAddressTypeAccepted(slice.AddressType, addressType) {
+			continue
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			if !isReady(endpoint) {
+				continue
+			}
+
+			endpointZone, ok := endpoint.Topology[corev1.LabelTopologyZone]
+			if !ok || endpointZone == "" {
+				return false
+			}
+
+			if endpointZone == zone {
+				inZone = true
+			}
+		}
+	}
+
+	return inZone
+}
+
+// primaryAddressType returns the address type of the primary IP family of the Service,
+// or an empty address type when it is unknown.
+func primaryAddressType(service *corev1.Service) discoveryv1beta1.AddressType {
+	if len(service.Spec.IPFamilies) == 0 {
+		return ""
+	}
+
+	if service.Spec.IPFamilies[0] == corev1.IPv6Protocol {
+		return discoveryv1beta1.AddressTypeIPv6
+	}
+
+	return discoveryv1beta1.AddressTypeIPv4
+}
+
+// isAddressTypeAccepted reports whether the EndpointSlices of the given address type are used,
+// the FQDN EndpointSlices being always ignored.
+func isAddressTypeAccepted(sliceType, serviceType discoveryv1beta1.AddressType) bool {
+	if sliceType == discoveryv1beta1.AddressTypeFQDN {
+		return false
+	}
+
+	return serviceType == "" || sliceType == serviceType
+}
+
+// isReady reports whether the endpoint is ready, an unknown state meaning ready.
+func isReady(endpoint discoveryv1beta1.Endpoint) bool {
+	return endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+}
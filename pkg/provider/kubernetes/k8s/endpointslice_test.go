@@ -0,0 +1,195 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEndpointsFromSlices(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		service  *corev1.Service
+		slices   []*discoveryv1beta1.EndpointSlice
+		zone     string
+		expected []corev1.EndpointSubset
+	}{
+		{
+			desc:    "one subset per slice, sorted by name",
+			service: buildService(""),
+			slices: []*discoveryv1beta1.EndpointSlice{
+				buildSlice("whoami-b", discoveryv1beta1.AddressTypeIPv4, 80, buildEndpoint("10.10.0.2", "", true)),
+				buildSlice("whoami-a", discoveryv1beta1.AddressTypeIPv4, 8080, buildEndpoint("10.10.0.1", "", true)),
+			},
+			expected: []corev1.EndpointSubset{
+				{
+					Addresses: []corev1.EndpointAddress{{IP: "10.10.0.1"}},
+					Ports:     []corev1.EndpointPort{{Name: "web", Port: 8080}},
+				},
+				{
+					Addresses: []corev1.EndpointAddress{{IP: "10.10.0.2"}},
+					Ports:     []corev1.EndpointPort{{Name: "web", Port: 80}},
+				},
+			},
+		},
+		{
+			desc:    "not ready endpoints",
+			service: buildService(""),
+			slices: []*discoveryv1beta1.EndpointSlice{
+				buildSlice("whoami-a", discoveryv1beta1.AddressTypeIPv4, 80, buildEndpoint("10.10.0.1", "", true), buildEndpoint("10.10.0.2", "", false)),
+			},
+			expected: []corev1.EndpointSubset{
+				{
+					Addresses:         []corev1.EndpointAddress{{IP: "10.10.0.1"}},
+					NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.10.0.2"}},
+					Ports:             []corev1.EndpointPort{{Name: "web", Port: 80}},
+				},
+			},
+		},
+		{
+			desc:    "FQDN and secondary family slices ignored",
+			service: &corev1.Service{Spec: corev1.ServiceSpec{IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}}},
+			slices: []*discoveryv1beta1.EndpointSlice{
+				buildSlice("whoami-a", discoveryv1beta1.AddressTypeIPv4, 80, buildEndpoint("10.10.0.1", "", true)),
+				buildSlice("whoami-b", discoveryv1beta1.AddressTypeIPv6, 80, buildEndpoint("fd00::1", "", true)),
+				buildSlice("whoami-c", discoveryv1beta1.AddressTypeFQDN, 80, buildEndpoint("whoami.example.com", "", true)),
+			},
+			expected: []corev1.EndpointSubset{
+				{
+					Addresses: []corev1.EndpointAddress{{IP: "fd00::1"}},
+					Ports:     []corev1.EndpointPort{{Name: "web", Port: 80}},
+				},
+			},
+		},
+		{
+			desc:    "topology-aware hints",
+			service: buildService("Auto"),
+			slices: []*discoveryv1beta1.EndpointSlice{
+				buildSlice("whoami-a", discoveryv1beta1.AddressTypeIPv4, 80,
+					buildEndpoint("10.10.0.1", "zone-a", true),
+					buildEndpoint("10.10.0.2", "zone-b", true),
+					buildEndpoint("10.10.0.3", "zone-a", false),
+				),
+			},
+			zone: "zone-a",
+			expected: []corev1.EndpointSubset{
+				{
+					Addresses:         []corev1.EndpointAddress{{IP: "10.10.0.1"}},
+					NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.10.0.3"}},
+					Ports:             []corev1.EndpointPort{{Name: "web", Port: 80}},
+				},
+			},
+		},
+		{
+			desc:    "topology-aware hints disabled",
+			service: buildService(""),
+			slices: []*discoveryv1beta1.EndpointSlice{
+				buildSlice("whoami-a", discoveryv1beta1.AddressTypeIPv4, 80, buildEndpoint("10.10.0.1", "zone-a", true), buildEndpoint("10.10.0.2", "zone-b", true)),
+			},
+			zone: "zone-a",
+			expected: []corev1.EndpointSubset{
+				{
+					Addresses: []corev1.EndpointAddress{{IP: "10.10.0.1"}, {IP: "10.10.0.2"}},
+					Ports:     []corev1.EndpointPort{{Name: "web", Port: 80}},
+				},
+			},
+		},
+		{
+			desc:    "topology-aware hints without zone",
+			service: buildService("auto"),
+			slices: []*discoveryv1beta1.EndpointSlice{
+				buildSlice("whoami-a", discoveryv1beta1.AddressTypeIPv4, 80, buildEndpoint("10.10.0.1", "zone-a", true), buildEndpoint("10.10.0.2", "zone-b", true)),
+			},
+			expected: []corev1.EndpointSubset{
+				{
+					Addresses: []corev1.EndpointAddress{{IP: "10.10.0.1"}, {IP: "10.10.0.2"}},
+					Ports:     []corev1.EndpointPort{{Name: "web", Port: 80}},
+				},
+			},
+		},
+		{
+			desc:    "topology-aware hints with an endpoint without zone",
+			service: buildService("auto"),
+			slices: []*discoveryv1beta1.EndpointSlice{
+				buildSlice("whoami-a", discoveryv1beta1.AddressTypeIPv4, 80, buildEndpoint("10.10.0.1", "zone-a", true), buildEndpoint("10.10.0.2", "", true)),
+			},
+			zone: "zone-a",
+			expected: []corev1.EndpointSubset{
+				{
+					Addresses: []corev1.EndpointAddress{{IP: "10.10.0.1"}, {IP: "10.10.0.2"}},
+					Ports:     []corev1.EndpointPort{{Name: "web", Port: 80}},
+				},
+			},
+		},
+		{
+			desc:    "topology-aware hints without endpoint in the zone",
+			service: buildService("auto"),
+			slices: []*discoveryv1beta1.EndpointSlice{
+				buildSlice("whoami-a", discoveryv1beta1.AddressTypeIPv4, 80, buildEndpoint("10.10.0.1", "zone-b", true), buildEndpoint("10.10.0.2", "zone-c", true)),
+			},
+			zone: "zone-a",
+			expected: []corev1.EndpointSubset{
+				{
+					Addresses: []corev1.EndpointAddress{{IP: "10.10.0.1"}, {IP: "10.10.0.2"}},
+					Ports:     []corev1.EndpointPort{{Name: "web", Port: 80}},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			endpoints := EndpointsFromSlices(test.service, test.slices, test.zone)
+
+			assert.Equal(t, test.expected, endpoints.Subsets)
+		})
+	}
+}
+
+func buildService(topologyAwareHints string) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "whoami",
+			Namespace: "default",
+		},
+	}
+
+	if topologyAwareHints != "" {
+		service.Annotations = map[string]string{AnnotationTopologyAwareHints: topologyAwareHints}
+	}
+
+	return service
+}
+
+func buildSlice(name string, addressType discoveryv1beta1.AddressType, port int32, endpoints ...discoveryv1beta1.Endpoint) *discoveryv1beta1.EndpointSlice {
+	portName := "web"
+
+	return &discoveryv1beta1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		AddressType: addressType,
+		Endpoints:   endpoints,
+		Ports:       []discoveryv1beta1.EndpointPort{{Name: &portName, Port: &port}},
+	}
+}
+
+func buildEndpoint(address, zone string, ready bool) discoveryv1beta1.Endpoint {
+	endpoint := discoveryv1beta1.Endpoint{
+		Addresses:  []string{address},
+		Conditions: discoveryv1beta1.EndpointConditions{Ready: &ready},
+	}
+
+	if zone != "" {
+		endpoint.Topology = map[string]string{corev1.LabelTopologyZone: zone}
+	}
+
+	return endpoint
+}
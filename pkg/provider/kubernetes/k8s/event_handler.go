@@ -2,6 +2,7 @@ package k8s
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -53,6 +54,12 @@ func objChanged(oldObj, newObj interface{}) bool {
 		}
 	}
 
+	if _, ok := oldObj.(*discoveryv1beta1.EndpointSlice); ok {
+		if endpointSliceChanged(oldObj.(*discoveryv1beta1.EndpointSlice), newObj.(*discoveryv1beta1.EndpointSlice)) {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -110,3 +117,70 @@ func subsetsChanged(sa, sb corev1.EndpointSubset) bool {
 
 	return false
 }
+
+func endpointSliceChanged(a, b *discoveryv1beta1.EndpointSlice) bool {
+	if a.AddressType != b.AddressType {
+		return true
+	}
+
+	if len(a.Endpoints) != len(b.Endpoints) {
+		return true
+	}
+
+	if len(a.Ports) != len(b.Ports) {
+		return true
+	}
+
+	for i, ea := range a.Endpoints {
+		if endpointChanged(ea, b.Endpoints[i]) {
+			return true
+		}
+	}
+
+	for i, pa := range a.Ports {
+		pb := b.Ports[i]
+		if !equalStringPtr(pa.Name, pb.Name) {
+			return true
+		}
+
+		if (pa.Port == nil) != (pb.Port == nil) || pa.Port != nil && *pa.Port != *pb.Port {
+			return true
+		}
+
+		if (pa.Protocol == nil) != (pb.Protocol == nil) || pa.Protocol != nil && *pa.Protocol != *pb.Protocol {
+			return true
+		}
+	}
+
+	return false
+}
+
+func endpointChanged(ea, eb discoveryv1beta1.Endpoint) bool {
+	if len(ea.Addresses) != len(eb.Addresses) {
+		return true
+	}
+
+	for i, addr := range ea.Addresses {
+		if addr != eb.Addresses[i] {
+			return true
+		}
+	}
+
+	if (ea.Conditions.Ready == nil || *ea.Conditions.Ready) != (eb.Conditions.Ready == nil || *eb.Conditions.Ready) {
+		return true
+	}
+
+	if !equalStringPtr(ea.Hostname, eb.Hostname) {
+		return true
+	}
+
+	return ea.Topology[corev1.LabelTopologyZone] != eb.Topology[corev1.LabelTopologyZone]
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -477,6 +478,86 @@ func Test_detectChanges(t *testing.T) {
 				}},
 			},
 		},
+		{
+			name: "With same endpointslice endpoints",
+			oldObj: &discoveryv1beta1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1",
+				},
+				Endpoints: []discoveryv1beta1.Endpoint{{
+					Addresses: []string{"10.10.10.10"},
+				}},
+			},
+			newObj: &discoveryv1beta1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "2",
+				},
+				Endpoints: []discoveryv1beta1.Endpoint{{
+					Addresses: []string{"10.10.10.10"},
+				}},
+			},
+		},
+		{
+			name: "With different endpointslice addresses",
+			oldObj: &discoveryv1beta1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1",
+				},
+				Endpoints: []discoveryv1beta1.Endpoint{{
+					Addresses: []string{"10.10.10.10"},
+				}},
+			},
+			newObj: &discoveryv1beta1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "2",
+				},
+				Endpoints: []discoveryv1beta1.Endpoint{{
+					Addresses: []string{"10.10.10.11"},
+				}},
+			},
+			want: true,
+		},
+		{
+			name: "With different endpointslice readiness",
+			oldObj: &discoveryv1beta1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1",
+				},
+				Endpoints: []discoveryv1beta1.Endpoint{{
+					Addresses: []string{"10.10.10.10"},
+				}},
+			},
+			newObj: &discoveryv1beta1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "2",
+				},
+				Endpoints: []discoveryv1beta1.Endpoint{{
+					Addresses:  []string{"10.10.10.10"},
+					Conditions: discoveryv1beta1.EndpointConditions{Ready: func(b bool) *bool { return &b }(false)},
+				}},
+			},
+			want: true,
+		},
+		{
+			name: "With different endpointslice ports",
+			oldObj: &discoveryv1beta1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "1",
+				},
+				Ports: []discoveryv1beta1.EndpointPort{{
+					Port: func(i int32) *int32 { return &i }(80),
+				}},
+			},
+			newObj: &discoveryv1beta1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{
+					ResourceVersion: "2",
+				},
+				Ports: []discoveryv1beta1.EndpointPort{{
+					Port: func(i int32) *int32 { return &i }(8080),
+				}},
+			},
+			want: true,
+		},
 	}
 	for _, test := range tests {
 		test := test
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/abronan/valkeyrie"
@@ -14,14 +16,50 @@ import (
 	"github.com/abronan/valkeyrie/store/redis"
 	"github.com/abronan/valkeyrie/store/zookeeper"
 	"github.com/cenkalti/backoff/v4"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/kv"
 	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
 
+// maxQuietPeriods is the maximum number of quiet periods a change is delayed by the following changes.
+const maxQuietPeriods = 10
+
+var (
+	suppressedReloadsCounterMu sync.RWMutex
+	suppressedReloadsCounter   metrics.Counter = discard.NewCounter()
+)
+
+// SetSuppressedReloadsCounter sets the counter of the configuration reloads suppressed by coalescing the changes,
+// partitioned by provider.
+func SetSuppressedReloadsCounter(counter metrics.Counter) {
+	if counter == nil {
+		counter = discard.NewCounter()
+	}
+
+	suppressedReloadsCounterMu.Lock()
+	suppressedReloadsCounter = counter
+	suppressedReloadsCounterMu.Unlock()
+}
+
+func countSuppressedReloads(providerName string, count int) {
+	if count <= 0 {
+		return
+	}
+
+	suppressedReloadsCounterMu.RLock()
+	counter := suppressedReloadsCounter
+	suppressedReloadsCounterMu.RUnlock()
+
+	counter.With("provider", providerName).Add(float64(count))
+}
+
 // Provider holds configurations of the provider.
 type Provider struct {
 	RootKey string `description:"Root key used for KV store" export:"true" json:"rootKey,omitempty" toml:"rootKey,omitempty" yaml:"rootKey,omitempty"`
@@ -31,6 +69,9 @@ type Provider struct {
 	Password  string           `description:"KV Password" json:"password,omitempty" toml:"password,omitempty" yaml:"password,omitempty"`
 	TLS       *types.ClientTLS `description:"Enable TLS support" export:"true" json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
 
+	WatchQuietPeriod ptypes.Duration `description:"Duration without changes after which the changes of the KV store are applied, 0 to apply each change. The changes are delayed at most ten quiet periods." export:"true" json:"watchQuietPeriod,omitempty" toml:"watchQuietPeriod,omitempty" yaml:"watchQuietPeriod,omitempty"`
+	WatchJitter      ptypes.Duration `description:"Maximum random duration added to the quiet period, to spread the reloads of several instances." export:"true" json:"watchJitter,omitempty" toml:"watchJitter,omitempty" yaml:"watchJitter,omitempty"`
+
 	storeType store.Backend
 	kvClient  store.Store
 	name      string
@@ -39,12 +80,17 @@ type Provider struct {
 // SetDefaults sets the default values.
 func (p *Provider) SetDefaults() {
 	p.RootKey = "traefik"
+	p.WatchQuietPeriod = ptypes.Duration(time.Second)
 }
 
 // Init the provider.
 func (p *Provider) Init(storeType store.Backend, name string) error {
 	ctx := log.With(context.Background(), log.Str(log.ProviderName, name))
 
+	if p.WatchQuietPeriod < 0 || p.WatchJitter < 0 {
+		return errors.New("the watch quiet period and jitter must be positive")
+	}
+
 	p.storeType = storeType
 	p.name = name
 
@@ -72,6 +118,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 	notify := func(err error, time time.Duration) {
 		logger.Errorf("KV connection error: %+v, retrying in %s", err, time)
+		provider.ReportFailure(p.name, err)
 	}
 	err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
 	if err != nil {
@@ -107,6 +154,19 @@ func (p *Provider) watchKv(ctx context.Context, configurationChan chan<- dynamic
 			return fmt.Errorf("failed to watch KV: %w", err)
 		}
 
+		// The changes received during the quiet period are coalesced into a single reload.
+		var (
+			timer   *time.Timer
+			reload  <-chan time.Time
+			first   time.Time
+			pending int
+		)
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
 		for {
 			select {
 			case <-ctx.Done():
@@ -116,16 +176,37 @@ func (p *Provider) watchKv(ctx context.Context, configurationChan chan<- dynamic
 					return errors.New("the WatchTree channel is closed")
 				}
 
-				configuration, errC := p.buildConfiguration()
-				if errC != nil {
-					return errC
+				if p.WatchQuietPeriod <= 0 {
+					if err := p.sendConfiguration(configurationChan); err != nil {
+						return err
+					}
+					continue
 				}
 
-				if configuration != nil {
-					configurationChan <- dynamic.Message{
-						ProviderName:  p.name,
-						Configuration: configuration,
+				now := time.Now()
+				if pending == 0 {
+					first = now
+				}
+				pending++
+
+				delay := p.reloadDelay(now.Sub(first))
+				if timer == nil {
+					timer = time.NewTimer(delay)
+				} else {
+					if !timer.Stop() && reload != nil {
+						<-timer.C
 					}
+					timer.Reset(delay)
+				}
+				reload = timer.C
+			case <-reload:
+				reload = nil
+
+				countSuppressedReloads(p.name, pending-1)
+				pending = 0
+
+				if err := p.sendConfiguration(configurationChan); err != nil {
+					return err
 				}
 			}
 		}
@@ -133,6 +214,7 @@ func (p *Provider) watchKv(ctx context.Context, configurationChan chan<- dynamic
 
 	notify := func(err error, time time.Duration) {
 		log.FromContext(ctx).Errorf("KV connection error: %+v, retrying in %s", err, time)
+		provider.ReportFailure(p.name, err)
 	}
 
 	err := backoff.RetryNotify(safe.OperationWithRecover(operation),
@@ -143,6 +225,41 @@ func (p *Provider) watchKv(ctx context.Context, configurationChan chan<- dynamic
 	return nil
 }
 
+// reloadDelay returns the duration to wait for other changes before reloading the configuration,
+// given the time elapsed since the first of the pending changes.
+func (p *Provider) reloadDelay(elapsed time.Duration) time.Duration {
+	delay := time.Duration(p.WatchQuietPeriod)
+	if p.WatchJitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(p.WatchJitter)))
+	}
+
+	// The reload is not postponed forever by continuous changes.
+	if remaining := maxQuietPeriods*time.Duration(p.WatchQuietPeriod) - elapsed; delay > remaining {
+		if remaining < 0 {
+			return 0
+		}
+		return remaining
+	}
+
+	return delay
+}
+
+func (p *Provider) sendConfiguration(configurationChan chan<- dynamic.Message) error {
+	configuration, err := p.buildConfiguration()
+	if err != nil {
+		return err
+	}
+
+	if configuration != nil {
+		configurationChan <- dynamic.Message{
+			ProviderName:  p.name,
+			Configuration: configuration,
+		}
+	}
+
+	return nil
+}
+
 func (p *Provider) buildConfiguration() (*dynamic.Configuration, error) {
 	pairs, err := p.kvClient.List(p.RootKey, nil)
 	if err != nil {
@@ -3,14 +3,19 @@ package kv
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/abronan/valkeyrie/store"
+	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/label"
 	"github.com/traefik/traefik/v2/pkg/tls"
 	"github.com/traefik/traefik/v2/pkg/types"
 )
@@ -394,7 +399,9 @@ func Test_buildConfiguration(t *testing.T) {
 					},
 				},
 				"Middleware05": {
-					Compress: &dynamic.Compress{},
+					Compress: &dynamic.Compress{
+						MinResponseBodyBytes: 1400,
+					},
 				},
 				"Middleware08": {
 					ForwardAuth: &dynamic.ForwardAuth{
@@ -888,6 +895,52 @@ func Test_buildConfiguration(t *testing.T) {
 	assert.Equal(t, expected, cfg)
 }
 
+func Test_buildConfiguration_labelsLayout(t *testing.T) {
+	labels := map[string]string{
+		"traefik.http.routers.Router0.rule":                                            "Host(`foo.bar`)",
+		"traefik.http.routers.Router0.entryPoints":                                     "web,websecure",
+		"traefik.http.routers.Router0.middlewares":                                     "Middleware0",
+		"traefik.http.routers.Router0.service":                                         "Service0",
+		"traefik.http.routers.Router0.tls.certResolver":                                "foobar",
+		"traefik.http.middlewares.Middleware0.stripPrefix.prefixes":                    "/foo,/bar",
+		"traefik.http.services.Service0.loadBalancer.passHostHeader":                   "true",
+		"traefik.http.services.Service0.loadBalancer.healthCheck.path":                 "/health",
+		"traefik.tcp.routers.TCPRouter0.rule":                                          "HostSNI(`foo.bar`)",
+		"traefik.tcp.routers.TCPRouter0.service":                                       "TCPService0",
+		"traefik.tcp.services.TCPService0.loadBalancer.terminationDelay":               "42",
+		"traefik.http.services.Service0.loadBalancer.sticky.cookie.name":               "foobar",
+		"traefik.http.services.Service0.loadBalancer.sticky.cookie.secure":             "true",
+		"traefik.http.services.Service0.loadBalancer.responseForwarding.flushInterval": "42ms",
+	}
+
+	// The KV keys follow the layout of the labels, with slashes as separators, and the list items as indexed keys.
+	pairs := make(map[string]string, len(labels))
+	for key, value := range labels {
+		key = strings.ReplaceAll(key, ".", "/")
+
+		values := strings.Split(value, ",")
+		if len(values) == 1 || strings.HasSuffix(key, "rule") {
+			pairs[key] = value
+			continue
+		}
+
+		for i, v := range values {
+			pairs[key+"/"+strconv.Itoa(i)] = v
+		}
+	}
+
+	provider := newProviderMock(mapToPairs(pairs))
+
+	cfg, err := provider.buildConfiguration()
+	require.NoError(t, err)
+
+	expected, err := label.DecodeConfiguration(labels)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected.HTTP, cfg.HTTP)
+	assert.Equal(t, expected.TCP, cfg.TCP)
+}
+
 func Test_buildConfiguration_KV_error(t *testing.T) {
 	provider := &Provider{
 		RootKey: "traefik",
@@ -948,6 +1001,139 @@ func TestKvWatchTree(t *testing.T) {
 	}
 }
 
+func TestKvWatchTree_coalescing(t *testing.T) {
+	counter := &suppressedCounter{}
+	SetSuppressedReloadsCounter(counter)
+	t.Cleanup(func() { SetSuppressedReloadsCounter(nil) })
+
+	events := make(chan []*store.KVPair, 10)
+	provider := Provider{
+		RootKey:          "traefik",
+		WatchQuietPeriod: ptypes.Duration(100 * time.Millisecond),
+		name:             "consul",
+		kvClient: &Mock{
+			KVPairs: mapToPairs(map[string]string{"traefik/http/routers/foo/service": "bar"}),
+			WatchTreeMethod: func() <-chan []*store.KVPair {
+				return events
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	configChan := make(chan dynamic.Message, 10)
+	go func() {
+		err := provider.watchKv(ctx, configChan)
+		require.NoError(t, err)
+	}()
+
+	// A bulk update.
+	for i := 0; i < 5; i++ {
+		events <- []*store.KVPair{}
+	}
+
+	select {
+	case msg := <-configChan:
+		assert.Equal(t, "consul", msg.ProviderName)
+		assert.Equal(t, "bar", msg.Configuration.HTTP.Routers["foo"].Service)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the configuration")
+	}
+
+	select {
+	case <-configChan:
+		t.Fatal("The changes must be coalesced into a single configuration")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	assert.Equal(t, float64(4), counter.value("consul"))
+}
+
+func TestProvider_reloadDelay(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		quietPeriod time.Duration
+		jitter      time.Duration
+		elapsed     time.Duration
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{
+			desc:        "quiet period",
+			quietPeriod: time.Second,
+			expectedMin: time.Second,
+			expectedMax: time.Second,
+		},
+		{
+			desc:        "quiet period with jitter",
+			quietPeriod: time.Second,
+			jitter:      500 * time.Millisecond,
+			expectedMin: time.Second,
+			expectedMax: 1500 * time.Millisecond,
+		},
+		{
+			desc:        "bounded by the maximum delay",
+			quietPeriod: time.Second,
+			jitter:      500 * time.Millisecond,
+			elapsed:     9500 * time.Millisecond,
+			expectedMin: 500 * time.Millisecond,
+			expectedMax: 500 * time.Millisecond,
+		},
+		{
+			desc:        "maximum delay exceeded",
+			quietPeriod: time.Second,
+			elapsed:     11 * time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider := Provider{
+				WatchQuietPeriod: ptypes.Duration(test.quietPeriod),
+				WatchJitter:      ptypes.Duration(test.jitter),
+			}
+
+			for i := 0; i < 10; i++ {
+				delay := provider.reloadDelay(test.elapsed)
+				assert.GreaterOrEqual(t, int64(delay), int64(test.expectedMin))
+				assert.LessOrEqual(t, int64(delay), int64(test.expectedMax))
+			}
+		})
+	}
+}
+
+type suppressedCounter struct {
+	mu     sync.Mutex
+	counts map[string]float64
+	labels []string
+	parent *suppressedCounter
+}
+
+func (c *suppressedCounter) With(labelValues ...string) metrics.Counter {
+	return &suppressedCounter{labels: labelValues, parent: c}
+}
+
+func (c *suppressedCounter) Add(delta float64) {
+	c.parent.mu.Lock()
+	defer c.parent.mu.Unlock()
+
+	if c.parent.counts == nil {
+		c.parent.counts = make(map[string]float64)
+	}
+	c.parent.counts[strings.Join(c.labels, ",")] += delta
+}
+
+func (c *suppressedCounter) value(providerName string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts["provider,"+providerName]
+}
+
 func mapToPairs(in map[string]string) []*store.KVPair {
 	var out []*store.KVPair
 	for k, v := range in {
@@ -0,0 +1,335 @@
+package marathon
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	ptls "github.com/traefik/traefik/v2/pkg/tls"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+const (
+	dcosLoginPath    = "/acs/api/v1/auth/login"
+	dcosMarathonPath = "/service/marathon"
+
+	// dcosLoginTokenTTL is the validity of the JWT signed with the private key of the service account to log in.
+	dcosLoginTokenTTL = 5 * time.Minute
+	// dcosTokenRefreshMargin is the duration before its expiration at which the authentication token is renewed.
+	dcosTokenRefreshMargin = 5 * time.Minute
+)
+
+// DCOSServiceAccount holds the DC/OS service account configuration.
+type DCOSServiceAccount struct {
+	UID           string `description:"UID of the DC/OS service account." json:"uid,omitempty" toml:"uid,omitempty" yaml:"uid,omitempty"`
+	PrivateKey    string `description:"PEM private key of the DC/OS service account, or path to the file holding it." json:"privateKey,omitempty" toml:"privateKey,omitempty" yaml:"privateKey,omitempty"`
+	LoginEndpoint string `description:"DC/OS login endpoint, defaults to the login endpoint of the host of the Marathon endpoint." json:"loginEndpoint,omitempty" toml:"loginEndpoint,omitempty" yaml:"loginEndpoint,omitempty"`
+}
+
+// authenticator provides the Authorization header of the requests to Marathon.
+type authenticator interface {
+	authorization() (string, error)
+	// invalidate discards the current credentials, after Marathon rejected them.
+	invalidate()
+}
+
+// authTransport sets the Authorization header of the requests to Marathon.
+type authTransport struct {
+	next http.RoundTripper
+	auth authenticator
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authorization, err := t.auth.authorization()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the Marathon credentials: %w", err)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The credentials are fetched again on the next request, e.g. after a rotation.
+	if resp.StatusCode == http.StatusUnauthorized {
+		t.auth.invalidate()
+	}
+
+	return resp, nil
+}
+
+// fileValue holds the trimmed content of a file, which is read again when the file changes.
+type fileValue struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	value   string
+}
+
+func (f *fileValue) get() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", err
+	}
+
+	if !info.ModTime().Equal(f.modTime) || f.value == "" {
+		content, err := os.ReadFile(f.path)
+		if err != nil {
+			return "", err
+		}
+
+		f.value = strings.TrimSpace(string(content))
+		f.modTime = info.ModTime()
+	}
+
+	if f.value == "" {
+		return "", fmt.Errorf("empty file %s", f.path)
+	}
+
+	return f.value, nil
+}
+
+func (f *fileValue) reset() {
+	f.mu.Lock()
+	f.value = ""
+	f.mu.Unlock()
+}
+
+// basicFileAuth authenticates with a user, and a password read from a file.
+type basicFileAuth struct {
+	user     string
+	password *fileValue
+}
+
+func (b *basicFileAuth) authorization() (string, error) {
+	password, err := b.password.get()
+	if err != nil {
+		return "", err
+	}
+
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(b.user+":"+password)), nil
+}
+
+func (b *basicFileAuth) invalidate() {
+	b.password.reset()
+}
+
+// tokenFileAuth authenticates with a DC/OS token read from a file.
+type tokenFileAuth struct {
+	token *fileValue
+}
+
+func (t *tokenFileAuth) authorization() (string, error) {
+	token, err := t.token.get()
+	if err != nil {
+		return "", err
+	}
+
+	return "token=" + token, nil
+}
+
+func (t *tokenFileAuth) invalidate() {
+	t.token.reset()
+}
+
+// serviceAccountAuth authenticates with a DC/OS token obtained by logging in with a service account,
+// which is renewed before it expires.
+type serviceAccountAuth struct {
+	uid        string
+	privateKey ptls.FileOrContent
+	loginURL   string
+	client     *http.Client
+	now        func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+func newServiceAccountAuth(config *DCOSServiceAccount, endpoint string, client *http.Client) (*serviceAccountAuth, error) {
+	if config.UID == "" || config.PrivateKey == "" {
+		return nil, errors.New("the uid and the private key of the DC/OS service account are required")
+	}
+
+	loginURL := config.LoginEndpoint
+	if loginURL == "" {
+		// The endpoint can hold several Marathon URLs.
+		u, err := url.Parse(strings.TrimSpace(strings.Split(endpoint, ",")[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid Marathon endpoint: %w", err)
+		}
+
+		loginURL = (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: dcosLoginPath}).String()
+	}
+
+	return &serviceAccountAuth{
+		uid:        config.UID,
+		privateKey: ptls.FileOrContent(config.PrivateKey),
+		loginURL:   loginURL,
+		client:     client,
+		now:        time.Now,
+	}, nil
+}
+
+func (s *serviceAccountAuth) authorization() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == "" || !s.refreshAt.IsZero() && !s.now().Before(s.refreshAt) {
+		err := s.login()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "token=" + s.token, nil
+}
+
+func (s *serviceAccountAuth) invalidate() {
+	s.mu.Lock()
+	s.token = ""
+	s.mu.Unlock()
+}
+
+// login obtains a new authentication token from DC/OS.
+// The private key is read on each login, so that it can be rotated.
+func (s *serviceAccountAuth) login() error {
+	key, err := s.readPrivateKey()
+	if err != nil {
+		return fmt.Errorf("unable to read the private key of the DC/OS service account: %w", err)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return err
+	}
+
+	loginToken, err := josejwt.Signed(signer).Claims(map[string]interface{}{
+		"uid": s.uid,
+		"exp": s.now().Add(dcosLoginTokenTTL).Unix(),
+	}).CompactSerialize()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{"uid": s.uid, "token": loginToken})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.loginURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to log in to DC/OS: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unable to log in to DC/OS: %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return fmt.Errorf("invalid DC/OS login response: %w", err)
+	}
+
+	if result.Token == "" {
+		return errors.New("invalid DC/OS login response: no token")
+	}
+
+	s.token = result.Token
+	s.refreshAt = s.tokenRefreshTime(result.Token)
+
+	return nil
+}
+
+// tokenRefreshTime returns the time at which the token is renewed, based on its expiration,
+// or the zero time if it has no known expiration, in which case it is only renewed once rejected.
+func (s *serviceAccountAuth) tokenRefreshTime(token string) time.Time {
+	parsed, err := josejwt.ParseSigned(token)
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims josejwt.Claims
+	if err := parsed.UnsafeClaimsWithoutVerification(&claims); err != nil || claims.Expiry == nil {
+		return time.Time{}
+	}
+
+	expiry := claims.Expiry.Time()
+
+	margin := dcosTokenRefreshMargin
+	if lifetime := expiry.Sub(s.now()); lifetime < 2*margin {
+		margin = lifetime / 2
+	}
+
+	return expiry.Add(-margin)
+}
+
+func (s *serviceAccountAuth) readPrivateKey() (*rsa.PrivateKey, error) {
+	content, err := s.privateKey.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type: %T", key)
+	}
+
+	return rsaKey, nil
+}
+
+// dcosEndpoint adds the path of Marathon on DC/OS to the Marathon URLs without a path,
+// as the Marathon client does when a DC/OS token is configured.
+func dcosEndpoint(endpoint string) string {
+	members := strings.Split(endpoint, ",")
+	for i, member := range members {
+		u, err := url.Parse(strings.TrimSpace(member))
+		if err != nil || strings.Trim(u.Path, "/") != "" {
+			continue
+		}
+
+		u.Path = dcosMarathonPath
+		members[i] = u.String()
+	}
+
+	return strings.Join(members, ",")
+}
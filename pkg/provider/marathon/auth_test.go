@@ -0,0 +1,288 @@
+package marathon
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+)
+
+func TestAuthTransport_basicFile(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("foo\n"), 0o600))
+
+	var authorized int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		user, password, ok := req.BasicAuth()
+		if !ok || user != "user" || password != "bar" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		atomic.AddInt32(&authorized, 1)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &authTransport{
+		next: http.DefaultTransport,
+		auth: &basicFileAuth{user: "user", password: &fileValue{path: passwordFile}},
+	}}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// The password is rotated.
+	require.NoError(t, os.WriteFile(passwordFile, []byte("bar\n"), 0o600))
+
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&authorized))
+}
+
+func TestAuthTransport_tokenFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("foo"), 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(req.Header.Get("Authorization")))
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &authTransport{
+		next: http.DefaultTransport,
+		auth: &tokenFileAuth{token: &fileValue{path: tokenFile}},
+	}}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "token=static")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "token=foo", string(body))
+
+	_, err = (&tokenFileAuth{token: &fileValue{path: filepath.Join(t.TempDir(), "missing")}}).authorization()
+	assert.Error(t, err)
+}
+
+func TestServiceAccountAuth(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("secret")}, nil)
+	require.NoError(t, err)
+
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var logins int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != dcosLoginPath {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var login struct {
+			UID   string `json:"uid"`
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&login); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		parsed, err := josejwt.ParseSigned(login.Token)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var claims struct {
+			UID string `json:"uid"`
+		}
+		if err := parsed.Claims(&privateKey.PublicKey, &claims); err != nil || claims.UID != "traefik" || login.UID != "traefik" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		count := atomic.AddInt32(&logins, 1)
+
+		token, err := josejwt.Signed(signer).Claims(josejwt.Claims{
+			Subject: "traefik",
+			Expiry:  josejwt.NewNumericDate(now.Add(time.Hour)),
+			ID:      strconv.Itoa(int(count)),
+		}).CompactSerialize()
+		if err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_ = json.NewEncoder(rw).Encode(map[string]string{"token": token})
+	}))
+	t.Cleanup(server.Close)
+
+	auth, err := newServiceAccountAuth(&DCOSServiceAccount{UID: "traefik", PrivateKey: keyFile}, server.URL+"/service/marathon", server.Client())
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+dcosLoginPath, auth.loginURL)
+
+	currentTime := now
+	auth.now = func() time.Time { return currentTime }
+
+	first, err := auth.authorization()
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+
+	// The token is reused until it is about to expire.
+	currentTime = now.Add(30 * time.Minute)
+
+	second, err := auth.authorization()
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+
+	currentTime = now.Add(56 * time.Minute)
+
+	third, err := auth.authorization()
+	require.NoError(t, err)
+	assert.NotEqual(t, second, third)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&logins))
+
+	// A rejected token is renewed.
+	auth.invalidate()
+
+	_, err = auth.authorization()
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&logins))
+
+	// An unknown service account cannot log in.
+	auth.uid = "foo"
+	auth.invalidate()
+
+	_, err = auth.authorization()
+	assert.Error(t, err)
+}
+
+func TestNewServiceAccountAuth(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           DCOSServiceAccount
+		endpoint         string
+		expectedLoginURL string
+		expectErr        bool
+	}{
+		{
+			desc:             "login endpoint from the Marathon endpoint",
+			config:           DCOSServiceAccount{UID: "traefik", PrivateKey: "key"},
+			endpoint:         "https://leader.mesos/service/marathon,https://other.mesos",
+			expectedLoginURL: "https://leader.mesos/acs/api/v1/auth/login",
+		},
+		{
+			desc:             "login endpoint",
+			config:           DCOSServiceAccount{UID: "traefik", PrivateKey: "key", LoginEndpoint: "https://master.mesos/acs/api/v1/auth/login"},
+			endpoint:         "https://leader.mesos",
+			expectedLoginURL: "https://master.mesos/acs/api/v1/auth/login",
+		},
+		{
+			desc:      "missing uid",
+			config:    DCOSServiceAccount{PrivateKey: "key"},
+			endpoint:  "https://leader.mesos",
+			expectErr: true,
+		},
+		{
+			desc:      "missing private key",
+			config:    DCOSServiceAccount{UID: "traefik"},
+			endpoint:  "https://leader.mesos",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			auth, err := newServiceAccountAuth(&test.config, test.endpoint, http.DefaultClient)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedLoginURL, auth.loginURL)
+		})
+	}
+}
+
+func TestDCOSEndpoint(t *testing.T) {
+	assert.Equal(t, "https://leader.mesos/service/marathon", dcosEndpoint("https://leader.mesos"))
+	assert.Equal(t, "https://leader.mesos/service/marathon", dcosEndpoint("https://leader.mesos/"))
+	assert.Equal(t, "https://leader.mesos/marathon", dcosEndpoint("https://leader.mesos/marathon"))
+	assert.Equal(t, "https://a.mesos/service/marathon,https://b.mesos/service/marathon", dcosEndpoint("https://a.mesos, https://b.mesos"))
+}
+
+func TestProvider_Init_authentication(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		provider  Provider
+		expectErr bool
+	}{
+		{
+			desc:     "DCOS token file",
+			provider: Provider{DCOSTokenFile: "/token"},
+		},
+		{
+			desc:      "DCOS token and token file",
+			provider:  Provider{DCOSToken: "foo", DCOSTokenFile: "/token"},
+			expectErr: true,
+		},
+		{
+			desc:      "DCOS token and service account",
+			provider:  Provider{DCOSToken: "foo", DCOSServiceAccount: &DCOSServiceAccount{UID: "traefik", PrivateKey: "/key.pem"}},
+			expectErr: true,
+		},
+		{
+			desc:      "basic password and password file",
+			provider:  Provider{Basic: &Basic{HTTPBasicAuthUser: "user", HTTPBasicPassword: "foo", HTTPBasicPasswordFile: "/password"}},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			test.provider.DefaultRule = DefaultTemplateRule
+
+			err := test.provider.Init()
+			if test.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
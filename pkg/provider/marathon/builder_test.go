@@ -203,3 +203,93 @@ func startedAtFromNow(offset time.Duration) func(*marathon.Task) {
 		t.StartedAt = time.Now().Add(-offset).Format(time.RFC3339)
 	}
 }
+
+// Functions related to building pods.
+
+func pod(id string, ops ...func(*marathon.PodStatus)) *marathon.PodStatus {
+	p := &marathon.PodStatus{
+		ID:   id,
+		Spec: &marathon.Pod{ID: id},
+	}
+
+	for _, op := range ops {
+		op(p)
+	}
+
+	return p
+}
+
+func withPodLabel(key, value string) func(*marathon.PodStatus) {
+	return func(p *marathon.PodStatus) {
+		if p.Spec.Labels == nil {
+			p.Spec.Labels = make(map[string]string)
+		}
+		p.Spec.Labels[key] = value
+	}
+}
+
+func podContainerNetwork() func(*marathon.PodStatus) {
+	return func(p *marathon.PodStatus) {
+		p.Spec.Networks = append(p.Spec.Networks, &marathon.PodNetwork{Name: "cni", Mode: marathon.ContainerNetworkMode})
+	}
+}
+
+func withContainer(name string, labels map[string]string, endpoints ...*marathon.PodEndpoint) func(*marathon.PodStatus) {
+	return func(p *marathon.PodStatus) {
+		p.Spec.Containers = append(p.Spec.Containers, &marathon.PodContainer{
+			Name:      name,
+			Labels:    labels,
+			Endpoints: endpoints,
+		})
+	}
+}
+
+func podEndpointPorts(name string, containerPort int, labels map[string]string) *marathon.PodEndpoint {
+	return &marathon.PodEndpoint{
+		Name:          name,
+		ContainerPort: containerPort,
+		Labels:        labels,
+	}
+}
+
+func withInstance(id, agentHostname string, ops ...func(*marathon.PodInstanceStatus)) func(*marathon.PodStatus) {
+	return func(p *marathon.PodStatus) {
+		instance := &marathon.PodInstanceStatus{
+			ID:            id,
+			AgentHostname: agentHostname,
+			Status:        marathon.PodInstanceStateStable,
+		}
+
+		for _, op := range ops {
+			op(instance)
+		}
+
+		p.Instances = append(p.Instances, instance)
+	}
+}
+
+func instanceAddresses(addresses ...string) func(*marathon.PodInstanceStatus) {
+	return func(instance *marathon.PodInstanceStatus) {
+		instance.Networks = append(instance.Networks, &marathon.PodNetworkStatus{Name: "cni", Addresses: addresses})
+	}
+}
+
+// withContainerStatus adds the status of a container, with the host ports allocated to its endpoints.
+func withContainerStatus(name string, state TaskState, healthy string, hostPorts map[string]int) func(*marathon.PodInstanceStatus) {
+	return func(instance *marathon.PodInstanceStatus) {
+		status := &marathon.ContainerStatus{
+			Name:   name,
+			Status: string(state),
+		}
+
+		if healthy != "" {
+			status.Conditions = append(status.Conditions, &marathon.StatusCondition{Name: podConditionHealthy, Value: healthy})
+		}
+
+		for endpointName, hostPort := range hostPorts {
+			status.Endpoints = append(status.Endpoints, &marathon.PodEndpoint{Name: endpointName, HostPort: hostPort})
+		}
+
+		instance.Containers = append(instance.Containers, status)
+	}
+}
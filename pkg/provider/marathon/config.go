@@ -11,14 +11,14 @@ import (
 
 	"github.com/gambol99/go-marathon"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
-	"github.com/traefik/traefik/v2/pkg/config/label"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/constraints"
 )
 
-func (p *Provider) buildConfiguration(ctx context.Context, applications *marathon.Applications) *dynamic.Configuration {
+func (p *Provider) buildConfiguration(ctx context.Context, applications *marathon.Applications, pods []*marathon.PodStatus) *dynamic.Configuration {
 	configurations := make(map[string]*dynamic.Configuration)
+	var labelErrors []dynamic.LabelError
 
 	for _, app := range applications.Apps {
 		ctxApp := log.With(ctx, log.Str("applicationID", app.ID))
@@ -43,9 +43,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, applications *maratho
 			continue
 		}
 
-		confFromLabel, err := label.DecodeConfiguration(labels)
+		confFromLabel, err := p.decodeConfiguration(labels)
 		if err != nil {
 			logger.Error(err)
+			labelErrors = append(labelErrors, provider.NewLabelErrors(app.ID, err)...)
 			continue
 		}
 
@@ -100,7 +101,12 @@ func (p *Provider) buildConfiguration(ctx context.Context, applications *maratho
 		configurations[app.ID] = confFromLabel
 	}
 
-	return provider.Merge(ctx, configurations)
+	labelErrors = append(labelErrors, p.buildPodsConfiguration(ctx, pods, configurations)...)
+
+	configuration := provider.Merge(ctx, configurations)
+	configuration.LabelErrors = labelErrors
+
+	return configuration
 }
 
 func getServiceName(app marathon.Application) string {
@@ -711,6 +711,9 @@ func TestBuildConfiguration(t *testing.T) {
 					Middlewares: map[string]*dynamic.Middleware{},
 					Services:    map[string]*dynamic.Service{},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "service", Name: "Service1", Sources: []string{"/app", "/app2"}},
+				},
 			},
 		},
 		{
@@ -841,6 +844,9 @@ func TestBuildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "middleware", Name: "Middleware1", Sources: []string{"/app", "/app2"}},
+				},
 			},
 		},
 		{
@@ -894,6 +900,9 @@ func TestBuildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"/app", "/app2"}},
+				},
 			},
 		},
 		{
@@ -997,6 +1006,9 @@ func TestBuildConfiguration(t *testing.T) {
 						},
 					},
 				},
+				Conflicts: []dynamic.Conflict{
+					{Kind: "router", Name: "Router1", Sources: []string{"/app", "/app2"}},
+				},
 			},
 		},
 		{
@@ -1814,7 +1826,7 @@ func TestBuildConfiguration(t *testing.T) {
 			err := p.Init()
 			require.NoError(t, err)
 
-			actualConfig := p.buildConfiguration(context.Background(), test.applications)
+			actualConfig := p.buildConfiguration(context.Background(), test.applications, nil)
 
 			assert.NotNil(t, actualConfig)
 			assert.Equal(t, test.expected, actualConfig)
@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"github.com/gambol99/go-marathon"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/label"
 )
 
@@ -17,8 +18,20 @@ type specificConfiguration struct {
 }
 
 func (p *Provider) getConfiguration(app marathon.Application) (configuration, error) {
-	labels := stringValueMap(app.Labels)
+	return p.getConfigurationFromLabels(stringValueMap(app.Labels))
+}
+
+// decodeConfiguration converts the labels of an application to a configuration.
+// In strict mode, the unknown labels of the traefik namespace are rejected.
+func (p *Provider) decodeConfiguration(labels map[string]string) (*dynamic.Configuration, error) {
+	if !p.StrictLabels {
+		return label.DecodeConfiguration(labels)
+	}
+
+	return label.DecodeConfigurationStrict(labels, "traefik.marathon.", "traefik.enable")
+}
 
+func (p *Provider) getConfigurationFromLabels(labels map[string]string) (configuration, error) {
 	conf := configuration{
 		Enable: p.ExposedByDefault,
 		Marathon: specificConfiguration{
@@ -2,6 +2,7 @@ package marathon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -46,23 +47,28 @@ var _ provider.Provider = (*Provider)(nil)
 
 // Provider holds configuration of the provider.
 type Provider struct {
-	Constraints            string           `description:"Constraints is an expression that Traefik matches against the application's labels to determine whether to create any route for that application." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
-	Trace                  bool             `description:"Display additional provider logs." json:"trace,omitempty" toml:"trace,omitempty" yaml:"trace,omitempty" export:"true"`
-	Watch                  bool             `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
-	Endpoint               string           `description:"Marathon server endpoint. You can also specify multiple endpoint for Marathon." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
-	DefaultRule            string           `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
-	ExposedByDefault       bool             `description:"Expose Marathon apps by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
-	DCOSToken              string           `description:"DCOSToken for DCOS environment, This will override the Authorization header." json:"dcosToken,omitempty" toml:"dcosToken,omitempty" yaml:"dcosToken,omitempty"`
-	TLS                    *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
-	DialerTimeout          ptypes.Duration  `description:"Set a dialer timeout for Marathon." json:"dialerTimeout,omitempty" toml:"dialerTimeout,omitempty" yaml:"dialerTimeout,omitempty" export:"true"`
-	ResponseHeaderTimeout  ptypes.Duration  `description:"Set a response header timeout for Marathon." json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty" export:"true"`
-	TLSHandshakeTimeout    ptypes.Duration  `description:"Set a TLS handshake timeout for Marathon." json:"tlsHandshakeTimeout,omitempty" toml:"tlsHandshakeTimeout,omitempty" yaml:"tlsHandshakeTimeout,omitempty" export:"true"`
-	KeepAlive              ptypes.Duration  `description:"Set a TCP Keep Alive time." json:"keepAlive,omitempty" toml:"keepAlive,omitempty" yaml:"keepAlive,omitempty" export:"true"`
-	ForceTaskHostname      bool             `description:"Force to use the task's hostname." json:"forceTaskHostname,omitempty" toml:"forceTaskHostname,omitempty" yaml:"forceTaskHostname,omitempty" export:"true"`
-	Basic                  *Basic           `description:"Enable basic authentication." json:"basic,omitempty" toml:"basic,omitempty" yaml:"basic,omitempty" export:"true"`
-	RespectReadinessChecks bool             `description:"Filter out tasks with non-successful readiness checks during deployments." json:"respectReadinessChecks,omitempty" toml:"respectReadinessChecks,omitempty" yaml:"respectReadinessChecks,omitempty" export:"true"`
+	Constraints            string              `description:"Constraints is an expression that Traefik matches against the application's labels to determine whether to create any route for that application." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
+	Trace                  bool                `description:"Display additional provider logs." json:"trace,omitempty" toml:"trace,omitempty" yaml:"trace,omitempty" export:"true"`
+	Watch                  bool                `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
+	Endpoint               string              `description:"Marathon server endpoint. You can also specify multiple endpoint for Marathon." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	DefaultRule            string              `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	ExposedByDefault       bool                `description:"Expose Marathon apps by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
+	DCOSToken              string              `description:"DCOSToken for DCOS environment, This will override the Authorization header." json:"dcosToken,omitempty" toml:"dcosToken,omitempty" yaml:"dcosToken,omitempty"`
+	DCOSTokenFile          string              `description:"File holding the DCOS token, read again when it changes. This will override the Authorization header." json:"dcosTokenFile,omitempty" toml:"dcosTokenFile,omitempty" yaml:"dcosTokenFile,omitempty"`
+	DCOSServiceAccount     *DCOSServiceAccount `description:"DCOS service account, used to obtain and renew the DCOS token. This will override the Authorization header." json:"dcosServiceAccount,omitempty" toml:"dcosServiceAccount,omitempty" yaml:"dcosServiceAccount,omitempty" export:"true"`
+	TLS                    *types.ClientTLS    `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+	DialerTimeout          ptypes.Duration     `description:"Set a dialer timeout for Marathon." json:"dialerTimeout,omitempty" toml:"dialerTimeout,omitempty" yaml:"dialerTimeout,omitempty" export:"true"`
+	ResponseHeaderTimeout  ptypes.Duration     `description:"Set a response header timeout for Marathon." json:"responseHeaderTimeout,omitempty" toml:"responseHeaderTimeout,omitempty" yaml:"responseHeaderTimeout,omitempty" export:"true"`
+	TLSHandshakeTimeout    ptypes.Duration     `description:"Set a TLS handshake timeout for Marathon." json:"tlsHandshakeTimeout,omitempty" toml:"tlsHandshakeTimeout,omitempty" yaml:"tlsHandshakeTimeout,omitempty" export:"true"`
+	KeepAlive              ptypes.Duration     `description:"Set a TCP Keep Alive time." json:"keepAlive,omitempty" toml:"keepAlive,omitempty" yaml:"keepAlive,omitempty" export:"true"`
+	ForceTaskHostname      bool                `description:"Force to use the task's hostname." json:"forceTaskHostname,omitempty" toml:"forceTaskHostname,omitempty" yaml:"forceTaskHostname,omitempty" export:"true"`
+	Basic                  *Basic              `description:"Enable basic authentication." json:"basic,omitempty" toml:"basic,omitempty" yaml:"basic,omitempty" export:"true"`
+	RespectReadinessChecks bool                `description:"Filter out tasks with non-successful readiness checks during deployments." json:"respectReadinessChecks,omitempty" toml:"respectReadinessChecks,omitempty" yaml:"respectReadinessChecks,omitempty" export:"true"`
+	Pods                   bool                `description:"Expose the Marathon pods endpoints." json:"pods,omitempty" toml:"pods,omitempty" yaml:"pods,omitempty" export:"true"`
+	StrictLabels           bool                `description:"Ignore the applications with unknown labels in the traefik namespace." json:"strictLabels,omitempty" toml:"strictLabels,omitempty" yaml:"strictLabels,omitempty" export:"true"`
 	readyChecker           *readinessChecker
 	marathonClient         marathon.Marathon
+	proxy                  *types.Proxy
 	defaultRuleTpl         *template.Template
 }
 
@@ -80,8 +86,9 @@ func (p *Provider) SetDefaults() {
 
 // Basic holds basic authentication specific configurations.
 type Basic struct {
-	HTTPBasicAuthUser string `description:"Basic authentication User." json:"httpBasicAuthUser,omitempty" toml:"httpBasicAuthUser,omitempty" yaml:"httpBasicAuthUser,omitempty"`
-	HTTPBasicPassword string `description:"Basic authentication Password." json:"httpBasicPassword,omitempty" toml:"httpBasicPassword,omitempty" yaml:"httpBasicPassword,omitempty"`
+	HTTPBasicAuthUser     string `description:"Basic authentication User." json:"httpBasicAuthUser,omitempty" toml:"httpBasicAuthUser,omitempty" yaml:"httpBasicAuthUser,omitempty"`
+	HTTPBasicPassword     string `description:"Basic authentication Password." json:"httpBasicPassword,omitempty" toml:"httpBasicPassword,omitempty" yaml:"httpBasicPassword,omitempty"`
+	HTTPBasicPasswordFile string `description:"File holding the basic authentication Password, read again when it changes." json:"httpBasicPasswordFile,omitempty" toml:"httpBasicPasswordFile,omitempty" yaml:"httpBasicPasswordFile,omitempty"`
 }
 
 // Init the provider.
@@ -101,10 +108,29 @@ func (p *Provider) Init() error {
 		return fmt.Errorf("error while parsing default rule: %w", err)
 	}
 
+	var dcosAuths int
+	for _, set := range []bool{p.DCOSToken != "", p.DCOSTokenFile != "", p.DCOSServiceAccount != nil} {
+		if set {
+			dcosAuths++
+		}
+	}
+	if dcosAuths > 1 {
+		return errors.New("only one of dcosToken, dcosTokenFile and dcosServiceAccount can be set")
+	}
+
+	if p.Basic != nil && p.Basic.HTTPBasicPassword != "" && p.Basic.HTTPBasicPasswordFile != "" {
+		return errors.New("only one of httpBasicPassword and httpBasicPasswordFile can be set")
+	}
+
 	p.defaultRuleTpl = defaultRuleTpl
 	return nil
 }
 
+// SetProxy sets the upstream proxy used to reach Marathon.
+func (p *Provider) SetProxy(proxy *types.Proxy) {
+	p.proxy = proxy
+}
+
 // Provide allows the marathon provider to provide configurations to traefik
 // using the given configuration channel.
 func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.Pool) error {
@@ -114,6 +140,9 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	operation := func() error {
 		confg := marathon.NewDefaultConfig()
 		confg.URL = p.Endpoint
+		if p.DCOSTokenFile != "" || p.DCOSServiceAccount != nil {
+			confg.URL = dcosEndpoint(p.Endpoint)
+		}
 		confg.EventsTransport = marathon.EventsTransportSSE
 		if p.Trace {
 			confg.LogOutput = log.CustomWriterLevel(logrus.DebugLevel, traceMaxScanTokenSize)
@@ -136,17 +165,25 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		if err != nil {
 			return err
 		}
-		confg.HTTPClient = &http.Client{
-			Transport: &http.Transport{
-				DialContext: (&net.Dialer{
-					KeepAlive: time.Duration(p.KeepAlive),
-					Timeout:   time.Duration(p.DialerTimeout),
-				}).DialContext,
-				ResponseHeaderTimeout: time.Duration(p.ResponseHeaderTimeout),
-				TLSHandshakeTimeout:   time.Duration(p.TLSHandshakeTimeout),
-				TLSClientConfig:       TLSConfig,
-			},
+		transport := p.proxy.WrapTransport(&http.Transport{
+			DialContext: (&net.Dialer{
+				KeepAlive: time.Duration(p.KeepAlive),
+				Timeout:   time.Duration(p.DialerTimeout),
+			}).DialContext,
+			ResponseHeaderTimeout: time.Duration(p.ResponseHeaderTimeout),
+			TLSHandshakeTimeout:   time.Duration(p.TLSHandshakeTimeout),
+			TLSClientConfig:       TLSConfig,
+		})
+
+		auth, err := p.createAuthenticator(&http.Client{Transport: transport})
+		if err != nil {
+			return err
+		}
+		if auth != nil {
+			transport = &authTransport{next: transport, auth: auth}
 		}
+
+		confg.HTTPClient = &http.Client{Transport: transport}
 		client, err := marathon.NewClient(confg)
 		if err != nil {
 			logger.Errorf("Failed to create a client for marathon, error: %s", err)
@@ -191,6 +228,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 	notify := func(err error, time time.Duration) {
 		logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+		provider.ReportFailure("marathon", err)
 	}
 	err := backoff.RetryNotify(safe.OperationWithRecover(operation), job.NewBackOff(backoff.NewExponentialBackOff()), notify)
 	if err != nil {
@@ -199,6 +237,20 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 	return nil
 }
 
+// createAuthenticator returns the authenticator of the credentials which can change over time, if any.
+func (p *Provider) createAuthenticator(client *http.Client) (authenticator, error) {
+	switch {
+	case p.DCOSServiceAccount != nil:
+		return newServiceAccountAuth(p.DCOSServiceAccount, p.Endpoint, client)
+	case p.DCOSTokenFile != "":
+		return &tokenFileAuth{token: &fileValue{path: p.DCOSTokenFile}}, nil
+	case p.Basic != nil && p.Basic.HTTPBasicPasswordFile != "" && p.DCOSToken == "":
+		return &basicFileAuth{user: p.Basic.HTTPBasicAuthUser, password: &fileValue{path: p.Basic.HTTPBasicPasswordFile}}, nil
+	default:
+		return nil, nil
+	}
+}
+
 func (p *Provider) getConfigurations(ctx context.Context) *dynamic.Configuration {
 	applications, err := p.getApplications()
 	if err != nil {
@@ -206,7 +258,16 @@ func (p *Provider) getConfigurations(ctx context.Context) *dynamic.Configuration
 		return nil
 	}
 
-	return p.buildConfiguration(ctx, applications)
+	var pods []*marathon.PodStatus
+	if p.Pods {
+		pods, err = p.getPods()
+		if err != nil {
+			log.FromContext(ctx).Errorf("Failed to retrieve Marathon pods: %v", err)
+			return nil
+		}
+	}
+
+	return p.buildConfiguration(ctx, applications, pods)
 }
 
 func (p *Provider) getApplications() (*marathon.Applications, error) {
@@ -217,3 +278,15 @@ func (p *Provider) getApplications() (*marathon.Applications, error) {
 
 	return p.marathonClient.Applications(v)
 }
+
+func (p *Provider) getPods() ([]*marathon.PodStatus, error) {
+	supported, err := p.marathonClient.SupportsPods()
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, errors.New("the Marathon server does not support pods")
+	}
+
+	return p.marathonClient.PodStatuses()
+}
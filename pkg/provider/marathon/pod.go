@@ -0,0 +1,372 @@
+package marathon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gambol99/go-marathon"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/provider"
+	"github.com/traefik/traefik/v2/pkg/provider/constraints"
+)
+
+const podConditionHealthy = "healthy"
+
+// podEndpoint is an endpoint of a container of a Marathon pod.
+// Each endpoint is exposed like an application, with its own routers and services.
+type podEndpoint struct {
+	pod       *marathon.PodStatus
+	container *marathon.PodContainer
+	endpoint  *marathon.PodEndpoint
+}
+
+func (e podEndpoint) id() string {
+	return e.pod.ID + "/" + e.container.Name + "/" + e.endpoint.Name
+}
+
+func (e podEndpoint) serviceName() string {
+	return strings.ReplaceAll(strings.TrimPrefix(e.id(), "/"), "/", "_")
+}
+
+// labels returns the labels of the pod, overridden by the labels of the container, then by the labels of the endpoint.
+func (e podEndpoint) labels() map[string]string {
+	labels := make(map[string]string)
+	for _, lbls := range []map[string]string{e.pod.Spec.Labels, e.container.Labels, e.endpoint.Labels} {
+		for key, value := range lbls {
+			labels[key] = value
+		}
+	}
+
+	if e.pod.Spec.Scheduling != nil && e.pod.Spec.Scheduling.Placement != nil && e.pod.Spec.Scheduling.Placement.Constraints != nil {
+		for i, constraint := range *e.pod.Spec.Scheduling.Placement.Constraints {
+			parts := []string{constraint.FieldName, constraint.Operator}
+			if constraint.Value != "" {
+				parts = append(parts, constraint.Value)
+			}
+
+			key := constraints.MarathonConstraintPrefix + "-" + strconv.Itoa(i)
+			labels[key] = strings.Join(parts, ":")
+		}
+	}
+
+	return labels
+}
+
+// buildPodsConfiguration adds the configurations of the pods endpoints,
+// and returns the errors of the labels which cannot be decoded.
+func (p *Provider) buildPodsConfiguration(ctx context.Context, pods []*marathon.PodStatus, configurations map[string]*dynamic.Configuration) []dynamic.LabelError {
+	var labelErrors []dynamic.LabelError
+
+	for _, pod := range pods {
+		if pod.Spec == nil {
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			for _, endpoint := range container.Endpoints {
+				e := podEndpoint{pod: pod, container: container, endpoint: endpoint}
+
+				ctxEndpoint := log.With(ctx, log.Str("podEndpoint", e.id()))
+				logger := log.FromContext(ctxEndpoint)
+
+				labels := e.labels()
+
+				extraConf, err := p.getConfigurationFromLabels(labels)
+				if err != nil {
+					logger.Errorf("Skip pod endpoint: %v", err)
+					continue
+				}
+
+				if !p.keepApplication(ctxEndpoint, extraConf, labels) {
+					continue
+				}
+
+				confFromLabel, err := p.decodeConfiguration(labels)
+				if err != nil {
+					logger.Error(err)
+					labelErrors = append(labelErrors, provider.NewLabelErrors(e.id(), err)...)
+					continue
+				}
+
+				var tcpOrUDP bool
+				if len(confFromLabel.TCP.Routers) > 0 || len(confFromLabel.TCP.Services) > 0 {
+					tcpOrUDP = true
+
+					err := p.buildPodTCPServiceConfiguration(ctxEndpoint, e, extraConf, confFromLabel.TCP)
+					if err != nil {
+						logger.Error(err)
+						continue
+					}
+					provider.BuildTCPRouterConfiguration(ctxEndpoint, confFromLabel.TCP)
+				}
+
+				if len(confFromLabel.UDP.Routers) > 0 || len(confFromLabel.UDP.Services) > 0 {
+					tcpOrUDP = true
+
+					err := p.buildPodUDPServiceConfiguration(ctxEndpoint, e, extraConf, confFromLabel.UDP)
+					if err != nil {
+						logger.Error(err)
+					} else {
+						provider.BuildUDPRouterConfiguration(ctxEndpoint, confFromLabel.UDP)
+					}
+				}
+
+				if tcpOrUDP && len(confFromLabel.HTTP.Routers) == 0 &&
+					len(confFromLabel.HTTP.Middlewares) == 0 &&
+					len(confFromLabel.HTTP.Services) == 0 {
+					configurations[e.id()] = confFromLabel
+					continue
+				}
+
+				err = p.buildPodServiceConfiguration(ctxEndpoint, e, extraConf, confFromLabel.HTTP)
+				if err != nil {
+					logger.Error(err)
+					continue
+				}
+
+				model := struct {
+					Name   string
+					Labels map[string]string
+				}{
+					Name:   e.id(),
+					Labels: labels,
+				}
+
+				provider.BuildRouterConfiguration(ctxEndpoint, confFromLabel.HTTP, e.serviceName(), p.defaultRuleTpl, model)
+
+				configurations[e.id()] = confFromLabel
+			}
+		}
+	}
+
+	return labelErrors
+}
+
+func (p *Provider) buildPodServiceConfiguration(ctx context.Context, e podEndpoint, extraConf configuration, conf *dynamic.HTTPConfiguration) error {
+	if len(conf.Services) == 0 {
+		conf.Services = make(map[string]*dynamic.Service)
+		lb := &dynamic.ServersLoadBalancer{}
+		lb.SetDefaults()
+		conf.Services[e.serviceName()] = &dynamic.Service{
+			LoadBalancer: lb,
+		}
+	}
+
+	for serviceName, service := range conf.Services {
+		defaultServer := dynamic.Server{}
+		defaultServer.SetDefaults()
+
+		if len(service.LoadBalancer.Servers) > 0 {
+			defaultServer = service.LoadBalancer.Servers[0]
+		}
+
+		var servers []dynamic.Server
+		for _, address := range p.getPodEndpointAddresses(ctx, e, extraConf, defaultServer.Port) {
+			servers = append(servers, dynamic.Server{
+				URL: fmt.Sprintf("%s://%s", defaultServer.Scheme, address),
+			})
+		}
+		if len(servers) == 0 {
+			return fmt.Errorf("no server for the service %s", serviceName)
+		}
+		service.LoadBalancer.Servers = servers
+	}
+
+	return nil
+}
+
+func (p *Provider) buildPodTCPServiceConfiguration(ctx context.Context, e podEndpoint, extraConf configuration, conf *dynamic.TCPConfiguration) error {
+	if len(conf.Services) == 0 {
+		conf.Services = make(map[string]*dynamic.TCPService)
+		lb := &dynamic.TCPServersLoadBalancer{}
+		lb.SetDefaults()
+		conf.Services[e.serviceName()] = &dynamic.TCPService{
+			LoadBalancer: lb,
+		}
+	}
+
+	for serviceName, service := range conf.Services {
+		defaultServer := dynamic.TCPServer{}
+
+		if len(service.LoadBalancer.Servers) > 0 {
+			defaultServer = service.LoadBalancer.Servers[0]
+		}
+
+		var servers []dynamic.TCPServer
+		for _, address := range p.getPodEndpointAddresses(ctx, e, extraConf, defaultServer.Port) {
+			servers = append(servers, dynamic.TCPServer{Address: address})
+		}
+		if len(servers) == 0 {
+			return fmt.Errorf("no server for the service %s", serviceName)
+		}
+		service.LoadBalancer.Servers = servers
+	}
+
+	return nil
+}
+
+func (p *Provider) buildPodUDPServiceConfiguration(ctx context.Context, e podEndpoint, extraConf configuration, conf *dynamic.UDPConfiguration) error {
+	if len(conf.Services) == 0 {
+		conf.Services = make(map[string]*dynamic.UDPService)
+		conf.Services[e.serviceName()] = &dynamic.UDPService{
+			LoadBalancer: &dynamic.UDPServersLoadBalancer{},
+		}
+	}
+
+	for serviceName, service := range conf.Services {
+		defaultServer := dynamic.UDPServer{}
+
+		if len(service.LoadBalancer.Servers) > 0 {
+			defaultServer = service.LoadBalancer.Servers[0]
+		}
+
+		var servers []dynamic.UDPServer
+		for _, address := range p.getPodEndpointAddresses(ctx, e, extraConf, defaultServer.Port) {
+			servers = append(servers, dynamic.UDPServer{Address: address})
+		}
+		if len(servers) == 0 {
+			return fmt.Errorf("no server for the service %s", serviceName)
+		}
+		service.LoadBalancer.Servers = servers
+	}
+
+	return nil
+}
+
+// getPodEndpointAddresses returns the addresses of the endpoint for the running instances of the pod.
+func (p *Provider) getPodEndpointAddresses(ctx context.Context, e podEndpoint, extraConf configuration, serverPort string) []string {
+	logger := log.FromContext(ctx)
+
+	var addresses []string
+	for _, instance := range e.pod.Instances {
+		status := getContainerStatus(instance, e.container.Name)
+		if status == nil || !p.containerFilter(ctx, e, instance, status) {
+			continue
+		}
+
+		host, err := p.getPodInstanceHost(e, instance, extraConf)
+		if err != nil {
+			logger.Errorf("Skip pod instance: %v", err)
+			continue
+		}
+
+		port, err := p.getPodEndpointPort(e, instance, status, serverPort)
+		if err != nil {
+			logger.Errorf("Skip pod instance: %v", err)
+			continue
+		}
+
+		addresses = append(addresses, net.JoinHostPort(host, port))
+	}
+
+	return addresses
+}
+
+func getContainerStatus(instance *marathon.PodInstanceStatus, name string) *marathon.ContainerStatus {
+	for _, status := range instance.Containers {
+		if status.Name == name {
+			return status
+		}
+	}
+
+	return nil
+}
+
+// containerFilter keeps the running containers and, when the readiness checks are respected,
+// filters out the ones with a failing health check.
+func (p *Provider) containerFilter(ctx context.Context, e podEndpoint, instance *marathon.PodInstanceStatus, status *marathon.ContainerStatus) bool {
+	if status.Status != string(taskStateRunning) {
+		return false
+	}
+
+	if !p.RespectReadinessChecks {
+		return true
+	}
+
+	for _, condition := range status.Conditions {
+		if condition.Name == podConditionHealthy && condition.Value != "true" {
+			log.FromContext(ctx).Infof("Filtering unready container %s from pod instance %s", e.container.Name, instance.ID)
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *Provider) getPodInstanceHost(e podEndpoint, instance *marathon.PodInstanceStatus, extraConf configuration) (string, error) {
+	if p.useAgentHost(e.pod.Spec) {
+		if len(instance.AgentHostname) == 0 {
+			return "", fmt.Errorf("host is undefined for instance %q pod %q", instance.ID, e.pod.ID)
+		}
+		return instance.AgentHostname, nil
+	}
+
+	var ipAddresses []string
+	for _, network := range instance.Networks {
+		ipAddresses = append(ipAddresses, network.Addresses...)
+	}
+
+	switch len(ipAddresses) {
+	case 0:
+		return "", fmt.Errorf("missing IP address for Marathon pod %s on instance %s", e.pod.ID, instance.ID)
+	case 1:
+		return ipAddresses[0], nil
+	default:
+		if extraConf.Marathon.IPAddressIdx == math.MinInt32 {
+			return "", fmt.Errorf("found %d instance IP addresses but missing IP address index for Marathon pod %s on instance %s",
+				len(ipAddresses), e.pod.ID, instance.ID)
+		}
+		if extraConf.Marathon.IPAddressIdx < 0 || extraConf.Marathon.IPAddressIdx >= len(ipAddresses) {
+			return "", fmt.Errorf("cannot use IP address index to select from %d instance IP addresses for Marathon pod %s on instance %s",
+				len(ipAddresses), e.pod.ID, instance.ID)
+		}
+
+		return ipAddresses[extraConf.Marathon.IPAddressIdx], nil
+	}
+}
+
+// getPodEndpointPort returns the explicitly specified port if any,
+// otherwise the host port allocated to the endpoint, or its container port when the instance IP address is used.
+func (p *Provider) getPodEndpointPort(e podEndpoint, instance *marathon.PodInstanceStatus, status *marathon.ContainerStatus, serverPort string) (string, error) {
+	if len(serverPort) > 0 {
+		port, err := strconv.Atoi(serverPort)
+		if err != nil {
+			return "", fmt.Errorf("unable to process port for pod %s on instance %s: %w", e.pod.ID, instance.ID, err)
+		}
+		if port <= 0 {
+			return "", fmt.Errorf("explicitly specified port %d must be greater than zero", port)
+		}
+		return serverPort, nil
+	}
+
+	endpoint := e.endpoint
+	for _, allocated := range status.Endpoints {
+		if allocated.Name == e.endpoint.Name {
+			endpoint = allocated
+			break
+		}
+	}
+
+	port := endpoint.ContainerPort
+	if p.useAgentHost(e.pod.Spec) {
+		port = endpoint.HostPort
+	}
+
+	if port <= 0 {
+		return "", errors.New("no port found")
+	}
+
+	return strconv.Itoa(port), nil
+}
+
+// useAgentHost reports whether the pod instances are reached through the hostname of their agent,
+// instead of their IP address on a container network.
+func (p *Provider) useAgentHost(pod *marathon.Pod) bool {
+	return p.ForceTaskHostname || len(pod.Networks) == 0 || pod.Networks[0].Mode != marathon.ContainerNetworkMode
+}
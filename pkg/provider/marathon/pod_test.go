@@ -0,0 +1,276 @@
+package marathon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gambol99/go-marathon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func TestBuildConfigurationPods(t *testing.T) {
+	testCases := []struct {
+		desc                   string
+		pods                   []*marathon.PodStatus
+		respectReadinessChecks bool
+		expected               *dynamic.Configuration
+	}{
+		{
+			desc: "one pod with two containers",
+			pods: []*marathon.PodStatus{
+				pod("/pod",
+					withContainer("web", nil, podEndpointPorts("http", 80, nil)),
+					withContainer("api", nil, podEndpointPorts("http", 8080, nil)),
+					withInstance("instance1", "agent1",
+						withContainerStatus("web", taskStateRunning, "", map[string]int{"http": 31000}),
+						withContainerStatus("api", taskStateRunning, "", map[string]int{"http": 31001}),
+					),
+				),
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"pod_web_http": {
+							Service: "pod_web_http",
+							Rule:    "Host(`pod-web-http.marathon.localhost`)",
+						},
+						"pod_api_http": {
+							Service: "pod_api_http",
+							Rule:    "Host(`pod-api-http.marathon.localhost`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"pod_web_http": {LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers:        []dynamic.Server{{URL: "http://agent1:31000"}},
+							PassHostHeader: Bool(true),
+						}},
+						"pod_api_http": {LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers:        []dynamic.Server{{URL: "http://agent1:31001"}},
+							PassHostHeader: Bool(true),
+						}},
+					},
+				},
+			},
+		},
+		{
+			desc: "labels of the pod, the container, and the endpoint",
+			pods: []*marathon.PodStatus{
+				pod("/pod",
+					podContainerNetwork(),
+					withPodLabel("traefik.enable", "false"),
+					withContainer("web",
+						map[string]string{
+							"traefik.enable":                "true",
+							"traefik.http.routers.web.rule": "Host(`container.localhost`)",
+						},
+						podEndpointPorts("http", 80, map[string]string{
+							"traefik.http.routers.web.rule": "Host(`web.localhost`)",
+						}),
+						podEndpointPorts("metrics", 9000, map[string]string{
+							"traefik.enable": "false",
+						}),
+					),
+					withContainer("sidecar", nil, podEndpointPorts("http", 8080, nil)),
+					withInstance("instance1", "agent1",
+						instanceAddresses("10.0.0.1"),
+						withContainerStatus("web", taskStateRunning, "", nil),
+						withContainerStatus("sidecar", taskStateRunning, "", nil),
+					),
+					withInstance("instance2", "agent2",
+						instanceAddresses("10.0.0.2"),
+						withContainerStatus("web", taskStateRunning, "", nil),
+						withContainerStatus("sidecar", taskStateRunning, "", nil),
+					),
+				),
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"web": {
+							Service: "pod_web_http",
+							Rule:    "Host(`web.localhost`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"pod_web_http": {LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{
+								{URL: "http://10.0.0.1:80"},
+								{URL: "http://10.0.0.2:80"},
+							},
+							PassHostHeader: Bool(true),
+						}},
+					},
+				},
+			},
+		},
+		{
+			desc:                   "containers not running or unhealthy are filtered",
+			respectReadinessChecks: true,
+			pods: []*marathon.PodStatus{
+				pod("/pod",
+					withContainer("web", nil, podEndpointPorts("http", 80, nil)),
+					withInstance("instance1", "agent1",
+						withContainerStatus("web", taskStateRunning, "true", map[string]int{"http": 31000}),
+					),
+					withInstance("instance2", "agent2",
+						withContainerStatus("web", taskStateRunning, "false", map[string]int{"http": 31000}),
+					),
+					withInstance("instance3", "agent3",
+						withContainerStatus("web", taskStateStaging, "", map[string]int{"http": 31000}),
+					),
+					withInstance("instance4", "agent4",
+						withContainerStatus("web", taskStateRunning, "", map[string]int{"http": 31000}),
+					),
+				),
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"pod_web_http": {
+							Service: "pod_web_http",
+							Rule:    "Host(`pod-web-http.marathon.localhost`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"pod_web_http": {LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers: []dynamic.Server{
+								{URL: "http://agent1:31000"},
+								{URL: "http://agent4:31000"},
+							},
+							PassHostHeader: Bool(true),
+						}},
+					},
+				},
+			},
+		},
+		{
+			desc: "unhealthy containers are kept without respecting readiness checks",
+			pods: []*marathon.PodStatus{
+				pod("/pod",
+					withContainer("web", nil, podEndpointPorts("http", 80, nil)),
+					withInstance("instance1", "agent1",
+						withContainerStatus("web", taskStateRunning, "false", map[string]int{"http": 31000}),
+					),
+				),
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers:     map[string]*dynamic.TCPRouter{},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services:    map[string]*dynamic.TCPService{},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"pod_web_http": {
+							Service: "pod_web_http",
+							Rule:    "Host(`pod-web-http.marathon.localhost`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services: map[string]*dynamic.Service{
+						"pod_web_http": {LoadBalancer: &dynamic.ServersLoadBalancer{
+							Servers:        []dynamic.Server{{URL: "http://agent1:31000"}},
+							PassHostHeader: Bool(true),
+						}},
+					},
+				},
+			},
+		},
+		{
+			desc: "TCP endpoint with an explicit port",
+			pods: []*marathon.PodStatus{
+				pod("/pod",
+					withContainer("db", nil, podEndpointPorts("pg", 5432, map[string]string{
+						"traefik.tcp.routers.db.rule":                      "HostSNI(`*`)",
+						"traefik.tcp.services.db.loadbalancer.server.port": "5433",
+					})),
+					withInstance("instance1", "agent1",
+						withContainerStatus("db", taskStateRunning, "", map[string]int{"pg": 31000}),
+					),
+				),
+			},
+			expected: &dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{
+						"db": {
+							Service: "db",
+							Rule:    "HostSNI(`*`)",
+						},
+					},
+					Middlewares: map[string]*dynamic.TCPMiddleware{},
+					Services: map[string]*dynamic.TCPService{
+						"db": {LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers:          []dynamic.TCPServer{{Address: "agent1:5433"}},
+							TerminationDelay: Int(100),
+						}},
+					},
+				},
+				UDP: &dynamic.UDPConfiguration{
+					Routers:  map[string]*dynamic.UDPRouter{},
+					Services: map[string]*dynamic.UDPService{},
+				},
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers:     map[string]*dynamic.Router{},
+					Middlewares: map[string]*dynamic.Middleware{},
+					Services:    map[string]*dynamic.Service{},
+				},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := &Provider{
+				DefaultRule:            "Host(`{{ normalize .Name }}.marathon.localhost`)",
+				ExposedByDefault:       true,
+				RespectReadinessChecks: test.respectReadinessChecks,
+			}
+
+			err := p.Init()
+			require.NoError(t, err)
+
+			actualConfig := p.buildConfiguration(context.Background(), withApplications(), test.pods)
+
+			assert.Equal(t, test.expected, actualConfig)
+		})
+	}
+}
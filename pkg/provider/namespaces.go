@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"sort"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// Namespaces collects the namespaces of the resources defining the elements of a configuration.
+// The names of the elements cannot be used to find their namespace,
+// as a namespace and a name can both contain the separator of the element names.
+type Namespaces map[dynamic.ElementNamespace]struct{}
+
+// Add records that the element of the given kind and name is defined by a resource of the given namespace.
+func (n Namespaces) Add(kind, name, namespace string) {
+	n[dynamic.ElementNamespace{Kind: kind, Name: name, Namespace: namespace}] = struct{}{}
+}
+
+// AddServices records that the given HTTP services are defined by a resource of the given namespace.
+func (n Namespaces) AddServices(services map[string]*dynamic.Service, namespace string) {
+	for name := range services {
+		n.Add("service", name, namespace)
+	}
+}
+
+// AddConfiguration records that the routers, services, and middlewares of the given configuration
+// are defined by resources of the given namespace.
+func (n Namespaces) AddConfiguration(conf *dynamic.Configuration, namespace string) {
+	if conf.HTTP != nil {
+		for name := range conf.HTTP.Routers {
+			n.Add("router", name, namespace)
+		}
+		for name := range conf.HTTP.Middlewares {
+			n.Add("middleware", name, namespace)
+		}
+		n.AddServices(conf.HTTP.Services, namespace)
+	}
+
+	if conf.TCP != nil {
+		for name := range conf.TCP.Routers {
+			n.Add("tcpRouter", name, namespace)
+		}
+		for name := range conf.TCP.Middlewares {
+			n.Add("tcpMiddleware", name, namespace)
+		}
+		for name := range conf.TCP.Services {
+			n.Add("tcpService", name, namespace)
+		}
+	}
+
+	if conf.UDP != nil {
+		for name := range conf.UDP.Routers {
+			n.Add("udpRouter", name, namespace)
+		}
+		for name := range conf.UDP.Services {
+			n.Add("udpService", name, namespace)
+		}
+	}
+}
+
+// Elements returns the recorded namespaces, sorted by kind, name, and namespace.
+func (n Namespaces) Elements() []dynamic.ElementNamespace {
+	if len(n) == 0 {
+		return nil
+	}
+
+	elements := make([]dynamic.ElementNamespace, 0, len(n))
+	for element := range n {
+		elements = append(elements, element)
+	}
+
+	sort.Slice(elements, func(i, j int) bool {
+		if elements[i].Kind != elements[j].Kind {
+			return elements[i].Kind < elements[j].Kind
+		}
+		if elements[i].Name != elements[j].Name {
+			return elements[i].Name < elements[j].Name
+		}
+		return elements[i].Namespace < elements[j].Namespace
+	})
+
+	return elements
+}
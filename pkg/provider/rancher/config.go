@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
-	"github.com/traefik/traefik/v2/pkg/config/label"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/provider/constraints"
@@ -16,6 +15,7 @@ import (
 
 func (p *Provider) buildConfiguration(ctx context.Context, services []rancherData) *dynamic.Configuration {
 	configurations := make(map[string]*dynamic.Configuration)
+	var labelErrors []dynamic.LabelError
 
 	for _, service := range services {
 		ctxService := log.With(ctx, log.Str("service", service.Name))
@@ -26,9 +26,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, services []rancherDat
 
 		logger := log.FromContext(ctxService)
 
-		confFromLabel, err := label.DecodeConfiguration(service.Labels)
+		confFromLabel, err := p.decodeConfiguration(service.Labels)
 		if err != nil {
 			logger.Error(err)
+			labelErrors = append(labelErrors, provider.NewLabelErrors(service.Name, err)...)
 			continue
 		}
 
@@ -81,7 +82,10 @@ func (p *Provider) buildConfiguration(ctx context.Context, services []rancherDat
 		configurations[service.Name] = confFromLabel
 	}
 
-	return provider.Merge(ctx, configurations)
+	configuration := provider.Merge(ctx, configurations)
+	configuration.LabelErrors = labelErrors
+
+	return configuration
 }
 
 func (p *Provider) buildTCPServiceConfiguration(ctx context.Context, service rancherData, configuration *dynamic.TCPConfiguration) error {
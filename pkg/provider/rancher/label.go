@@ -1,6 +1,7 @@
 package rancher
 
 import (
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/label"
 )
 
@@ -20,3 +21,13 @@ func (p *Provider) getConfiguration(service rancherData) (configuration, error)
 
 	return conf, nil
 }
+
+// decodeConfiguration converts the labels of a service to a configuration.
+// In strict mode, the unknown labels of the traefik namespace are rejected.
+func (p *Provider) decodeConfiguration(labels map[string]string) (*dynamic.Configuration, error) {
+	if !p.StrictLabels {
+		return label.DecodeConfiguration(labels)
+	}
+
+	return label.DecodeConfigurationStrict(labels, "traefik.rancher.", "traefik.enable")
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 const (
@@ -40,15 +41,19 @@ var _ provider.Provider = (*Provider)(nil)
 
 // Provider holds configurations of the provider.
 type Provider struct {
-	Constraints               string `description:"Constraints is an expression that Traefik matches against the container's labels to determine whether to create any route for that container." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
-	Watch                     bool   `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
-	DefaultRule               string `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
-	ExposedByDefault          bool   `description:"Expose containers by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
-	EnableServiceHealthFilter bool   `description:"Filter services with unhealthy states and inactive states." json:"enableServiceHealthFilter,omitempty" toml:"enableServiceHealthFilter,omitempty" yaml:"enableServiceHealthFilter,omitempty" export:"true"`
-	RefreshSeconds            int    `description:"Defines the polling interval in seconds." json:"refreshSeconds,omitempty" toml:"refreshSeconds,omitempty" yaml:"refreshSeconds,omitempty" export:"true"`
-	IntervalPoll              bool   `description:"Poll the Rancher metadata service every 'rancher.refreshseconds' (less accurate)." json:"intervalPoll,omitempty" toml:"intervalPoll,omitempty" yaml:"intervalPoll,omitempty" export:"true"`
-	Prefix                    string `description:"Prefix used for accessing the Rancher metadata service." json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Constraints               string    `description:"Constraints is an expression that Traefik matches against the container's labels to determine whether to create any route for that container." json:"constraints,omitempty" toml:"constraints,omitempty" yaml:"constraints,omitempty" export:"true"`
+	Watch                     bool      `description:"Watch provider." json:"watch,omitempty" toml:"watch,omitempty" yaml:"watch,omitempty" export:"true"`
+	DefaultRule               string    `description:"Default rule." json:"defaultRule,omitempty" toml:"defaultRule,omitempty" yaml:"defaultRule,omitempty"`
+	ExposedByDefault          bool      `description:"Expose containers by default." json:"exposedByDefault,omitempty" toml:"exposedByDefault,omitempty" yaml:"exposedByDefault,omitempty" export:"true"`
+	EnableServiceHealthFilter bool      `description:"Filter services with unhealthy states and inactive states." json:"enableServiceHealthFilter,omitempty" toml:"enableServiceHealthFilter,omitempty" yaml:"enableServiceHealthFilter,omitempty" export:"true"`
+	RefreshSeconds            int       `description:"Defines the polling interval in seconds." json:"refreshSeconds,omitempty" toml:"refreshSeconds,omitempty" yaml:"refreshSeconds,omitempty" export:"true"`
+	IntervalPoll              bool      `description:"Poll the Rancher metadata service every 'rancher.refreshseconds' (less accurate)." json:"intervalPoll,omitempty" toml:"intervalPoll,omitempty" yaml:"intervalPoll,omitempty" export:"true"`
+	Prefix                    string    `description:"Prefix used for accessing the Rancher metadata service." json:"prefix,omitempty" toml:"prefix,omitempty" yaml:"prefix,omitempty"`
+	V2                        *V2Config `description:"Discover the workloads with the Rancher v2 (Kubernetes-based) API instead of the Cattle metadata service." json:"v2,omitempty" toml:"v2,omitempty" yaml:"v2,omitempty" export:"true"`
+	StrictLabels              bool      `description:"Ignore the services with unknown labels in the traefik namespace." json:"strictLabels,omitempty" toml:"strictLabels,omitempty" yaml:"strictLabels,omitempty" export:"true"`
 	defaultRuleTpl            *template.Template
+	proxy                     *types.Proxy
+	v2Client                  *v2Client
 }
 
 // SetDefaults sets the default values.
@@ -79,9 +84,22 @@ func (p *Provider) Init() error {
 	}
 
 	p.defaultRuleTpl = defaultRuleTpl
+
+	if p.V2 != nil {
+		p.v2Client, err = newV2Client(p.V2, p.proxy)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// SetProxy sets the upstream proxy used to reach the Rancher v2 API.
+func (p *Provider) SetProxy(proxy *types.Proxy) {
+	p.proxy = proxy
+}
+
 func (p *Provider) createClient(ctx context.Context) (rancher.Client, error) {
 	metadataServiceURL := fmt.Sprintf("http://rancher-metadata.rancher.internal/%s", p.Prefix)
 	client, err := rancher.NewClientAndWait(metadataServiceURL)
@@ -99,6 +117,29 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		ctxLog := log.With(routineCtx, log.Str(log.ProviderName, "rancher"))
 		logger := log.FromContext(ctxLog)
 
+		if p.v2Client != nil {
+			operation := func() error {
+				return p.provideV2(ctxLog, p.v2Client, func(rancherData []rancherData) {
+					logger.Printf("Received Rancher data %+v", rancherData)
+
+					configurationChan <- dynamic.Message{
+						ProviderName:  "rancher",
+						Configuration: p.buildConfiguration(ctxLog, rancherData),
+					}
+				})
+			}
+
+			notify := func(err error, time time.Duration) {
+				logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+				provider.ReportFailure("rancher", err)
+			}
+			err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
+			if err != nil {
+				logger.Errorf("Cannot connect to Provider server: %+v", err)
+			}
+			return
+		}
+
 		operation := func() error {
 			client, err := p.createClient(ctxLog)
 			if err != nil {
@@ -140,6 +181,7 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 		notify := func(err error, time time.Duration) {
 			logger.Errorf("Provider connection error %+v, retrying in %s", err, time)
+			provider.ReportFailure("rancher", err)
 		}
 		err := backoff.RetryNotify(safe.OperationWithRecover(operation), backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxLog), notify)
 		if err != nil {
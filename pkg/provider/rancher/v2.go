@@ -0,0 +1,264 @@
+package rancher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+// Workload states of the Rancher v2 API.
+const (
+	v2Updating = "updating"
+)
+
+// V2Config holds the configuration of the Rancher v2 (Kubernetes-based) API.
+type V2Config struct {
+	Endpoint  string           `description:"Rancher v2 server URL." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Token     string           `description:"Rancher v2 API token, made of the access key and the secret key separated by a colon." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	ProjectID string           `description:"ID of the Rancher v2 project whose workloads are discovered." json:"projectID,omitempty" toml:"projectID,omitempty" yaml:"projectID,omitempty" export:"true"`
+	TLS       *types.ClientTLS `description:"Enable TLS support." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" export:"true"`
+}
+
+type v2Collection struct {
+	Data       json.RawMessage `json:"data"`
+	Pagination *struct {
+		Next string `json:"next"`
+	} `json:"pagination"`
+}
+
+type v2Workload struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	NamespaceID string            `json:"namespaceId"`
+	Labels      map[string]string `json:"labels"`
+	State       string            `json:"state"`
+	Containers  []v2Container     `json:"containers"`
+}
+
+type v2Container struct {
+	Ports []v2ContainerPort `json:"ports"`
+}
+
+type v2ContainerPort struct {
+	ContainerPort int `json:"containerPort"`
+}
+
+type v2Pod struct {
+	Name       string      `json:"name"`
+	WorkloadID string      `json:"workloadId"`
+	State      string      `json:"state"`
+	Status     v2PodStatus `json:"status"`
+}
+
+type v2PodStatus struct {
+	PodIP string `json:"podIp"`
+}
+
+// v2Client queries the workloads and pods of a project with the Rancher v2 API.
+type v2Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func newV2Client(config *V2Config, proxy *types.Proxy) (*v2Client, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("the Rancher v2 endpoint is required")
+	}
+	if config.ProjectID == "" {
+		return nil, errors.New("the Rancher v2 project ID is required")
+	}
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.CreateTLSConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("unable to create TLS configuration: %w", err)
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &v2Client{
+		baseURL: strings.TrimSuffix(config.Endpoint, "/") + "/v3/project/" + url.PathEscape(config.ProjectID),
+		token:   config.Token,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: proxy.WrapTransport(transport),
+		},
+	}, nil
+}
+
+func (c *v2Client) getWorkloads(ctx context.Context) ([]v2Workload, error) {
+	var workloads []v2Workload
+
+	err := c.list(ctx, c.baseURL+"/workloads", func(data json.RawMessage) error {
+		var page []v2Workload
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+
+		workloads = append(workloads, page...)
+		return nil
+	})
+
+	return workloads, err
+}
+
+func (c *v2Client) getPods(ctx context.Context) ([]v2Pod, error) {
+	var pods []v2Pod
+
+	err := c.list(ctx, c.baseURL+"/pods", func(data json.RawMessage) error {
+		var page []v2Pod
+		if err := json.Unmarshal(data, &page); err != nil {
+			return err
+		}
+
+		pods = append(pods, page...)
+		return nil
+	})
+
+	return pods, err
+}
+
+// list fetches all the pages of a collection, and calls addPage with the data of each page.
+func (c *v2Client) list(ctx context.Context, collectionURL string, addPage func(data json.RawMessage) error) error {
+	for collectionURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, collectionURL, nil)
+		if err != nil {
+			return err
+		}
+
+		req.Header.Set("Accept", "application/json")
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		var collection v2Collection
+		err = json.NewDecoder(resp.Body).Decode(&collection)
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("received non-ok response code %d from %s", resp.StatusCode, collectionURL)
+		}
+		if err != nil {
+			return fmt.Errorf("decoding the response from %s: %w", collectionURL, err)
+		}
+
+		if err = addPage(collection.Data); err != nil {
+			return fmt.Errorf("decoding the response from %s: %w", collectionURL, err)
+		}
+
+		collectionURL = ""
+		if collection.Pagination != nil {
+			collectionURL = collection.Pagination.Next
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) provideV2(ctx context.Context, client *v2Client, updateConfiguration func([]rancherData)) error {
+	fetch := func() error {
+		workloads, err := client.getWorkloads(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query the Rancher v2 workloads: %w", err)
+		}
+
+		pods, err := client.getPods(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to query the Rancher v2 pods: %w", err)
+		}
+
+		updateConfiguration(p.parseV2RancherData(ctx, workloads, pods))
+		return nil
+	}
+
+	if err := fetch(); err != nil {
+		return err
+	}
+
+	if !p.Watch {
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Duration(p.RefreshSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := fetch(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// parseV2RancherData maps the workloads of the Rancher v2 API, with the addresses of their running pods,
+// to the same data as the services of the Cattle metadata service.
+func (p *Provider) parseV2RancherData(ctx context.Context, workloads []v2Workload, pods []v2Pod) (rancherDataList []rancherData) {
+	podIPs := make(map[string][]string)
+	for _, pod := range pods {
+		ctxPod := log.With(ctx, log.Str("pod", pod.Name))
+		if pod.Status.PodIP == "" || !containerFilter(ctxPod, pod.Name, "", pod.State) {
+			continue
+		}
+
+		podIPs[pod.WorkloadID] = append(podIPs[pod.WorkloadID], pod.Status.PodIP)
+	}
+
+	for _, workload := range workloads {
+		ctxWorkload := log.With(ctx, log.Str("namespace", workload.NamespaceID), log.Str("workload", workload.Name))
+		logger := log.FromContext(ctxWorkload)
+
+		var port string
+		for _, container := range workload.Containers {
+			if len(container.Ports) > 0 {
+				port = strconv.Itoa(container.Ports[0].ContainerPort)
+				break
+			}
+		}
+
+		state := workload.State
+		if state == v2Updating {
+			state = updatingActive
+		}
+
+		service := rancherData{
+			Name:       workload.Name + "_" + workload.NamespaceID,
+			State:      state,
+			Labels:     workload.Labels,
+			Port:       port,
+			Containers: podIPs[workload.ID],
+		}
+
+		extraConf, err := p.getConfiguration(service)
+		if err != nil {
+			logger.Errorf("Skip workload %s: %v", service.Name, err)
+			continue
+		}
+
+		service.ExtraConf = extraConf
+
+		rancherDataList = append(rancherDataList, service)
+	}
+
+	return rancherDataList
+}
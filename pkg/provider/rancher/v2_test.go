@@ -0,0 +1,112 @@
+package rancher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV2Client(t *testing.T) {
+	var serverURL string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/project/c-abcde:p-fghij/workloads", func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token-abcde:secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if req.URL.Query().Get("marker") == "" {
+			_, _ = fmt.Fprintf(rw, `{"data": [{"id": "deployment:default:whoami", "name": "whoami"}], "pagination": {"next": "%s/v3/project/c-abcde:p-fghij/workloads?marker=1"}}`, serverURL)
+			return
+		}
+
+		_, _ = fmt.Fprint(rw, `{"data": [{"id": "deployment:default:foo", "name": "foo"}], "pagination": {}}`)
+	})
+	mux.HandleFunc("/v3/project/c-abcde:p-fghij/pods", func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(rw, `{"data": [{"name": "whoami-1", "workloadId": "deployment:default:whoami", "state": "running", "status": {"podIp": "10.42.0.1"}}]}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	serverURL = server.URL
+
+	client, err := newV2Client(&V2Config{
+		Endpoint:  server.URL + "/",
+		Token:     "token-abcde:secret",
+		ProjectID: "c-abcde:p-fghij",
+	}, nil)
+	require.NoError(t, err)
+
+	workloads, err := client.getWorkloads(context.Background())
+	require.NoError(t, err)
+	require.Len(t, workloads, 2)
+	assert.Equal(t, "whoami", workloads[0].Name)
+	assert.Equal(t, "foo", workloads[1].Name)
+
+	pods, err := client.getPods(context.Background())
+	require.NoError(t, err)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "10.42.0.1", pods[0].Status.PodIP)
+
+	client.token = "foo"
+	_, err = client.getWorkloads(context.Background())
+	assert.Error(t, err)
+}
+
+func TestNewV2Client_invalidConfig(t *testing.T) {
+	_, err := newV2Client(&V2Config{ProjectID: "c-abcde:p-fghij"}, nil)
+	assert.Error(t, err)
+
+	_, err = newV2Client(&V2Config{Endpoint: "https://rancher.example.com"}, nil)
+	assert.Error(t, err)
+}
+
+func TestParseV2RancherData(t *testing.T) {
+	workloads := []v2Workload{
+		{
+			ID:          "deployment:default:whoami",
+			Name:        "whoami",
+			NamespaceID: "default",
+			Labels:      map[string]string{"traefik.http.routers.whoami.rule": "Host(`whoami.example.com`)"},
+			State:       "updating",
+			Containers: []v2Container{
+				{Ports: []v2ContainerPort{{ContainerPort: 8080}, {ContainerPort: 8443}}},
+			},
+		},
+		{
+			ID:          "deployment:default:disabled",
+			Name:        "disabled",
+			NamespaceID: "default",
+			Labels:      map[string]string{"traefik.enable": "false"},
+			State:       "active",
+		},
+	}
+
+	pods := []v2Pod{
+		{Name: "whoami-1", WorkloadID: "deployment:default:whoami", State: "running", Status: v2PodStatus{PodIP: "10.42.0.1"}},
+		{Name: "whoami-2", WorkloadID: "deployment:default:whoami", State: "running", Status: v2PodStatus{PodIP: "10.42.0.2"}},
+		{Name: "whoami-3", WorkloadID: "deployment:default:whoami", State: "stopped", Status: v2PodStatus{PodIP: "10.42.0.3"}},
+		{Name: "whoami-4", WorkloadID: "deployment:default:whoami", State: "running"},
+	}
+
+	p := Provider{ExposedByDefault: true}
+
+	data := p.parseV2RancherData(context.Background(), workloads, pods)
+	require.Len(t, data, 2)
+
+	assert.Equal(t, "whoami_default", data[0].Name)
+	assert.Equal(t, updatingActive, data[0].State)
+	assert.Equal(t, "8080", data[0].Port)
+	assert.Equal(t, []string{"10.42.0.1", "10.42.0.2"}, data[0].Containers)
+	assert.True(t, data[0].ExtraConf.Enable)
+
+	assert.Equal(t, "disabled_default", data[1].Name)
+	assert.Empty(t, data[1].Containers)
+	assert.False(t, data[1].ExtraConf.Enable)
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 	"net"
 	"regexp"
+	"strings"
 
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/static"
@@ -129,8 +130,13 @@ func (i *Provider) redirection(ctx context.Context, cfg *dynamic.Configuration)
 		rtName := provider.Normalize(name + "-to-" + def.EntryPoint.To)
 		mdName := "redirect-" + rtName
 
+		rule := "HostRegexp(`{host:.+}`)"
+		if len(def.EntryPoint.ExcludedPathPrefixes) > 0 {
+			rule += " && !PathPrefix(`" + strings.Join(def.EntryPoint.ExcludedPathPrefixes, "`, `") + "`)"
+		}
+
 		rt := &dynamic.Router{
-			Rule:        "HostRegexp(`{host:.+}`)",
+			Rule:        rule,
 			EntryPoints: []string{name},
 			Middlewares: []string{mdName},
 			Service:     "noop@internal",
@@ -249,6 +255,10 @@ func (i *Provider) apiConfiguration(cfg *dynamic.Configuration) {
 
 	cfg.HTTP.Services["api"] = &dynamic.Service{}
 
+	for name := range i.staticCfg.API.Scopes {
+		cfg.HTTP.Services["api-"+name] = &dynamic.Service{}
+	}
+
 	if i.staticCfg.API.Dashboard {
 		cfg.HTTP.Services["dashboard"] = &dynamic.Service{}
 	}
@@ -321,6 +331,7 @@ func (i *Provider) serverTransport(cfg *dynamic.Configuration) {
 			DialTimeout:           i.staticCfg.ServersTransport.ForwardingTimeouts.DialTimeout,
 			ResponseHeaderTimeout: i.staticCfg.ServersTransport.ForwardingTimeouts.ResponseHeaderTimeout,
 			IdleConnTimeout:       i.staticCfg.ServersTransport.ForwardingTimeouts.IdleConnTimeout,
+			TLSHandshakeTimeout:   i.staticCfg.ServersTransport.ForwardingTimeouts.TLSHandshakeTimeout,
 		}
 	}
 
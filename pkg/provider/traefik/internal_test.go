@@ -99,6 +99,18 @@ func Test_createConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "api_scopes.json",
+			staticCfg: static.Configuration{
+				API: &static.API{
+					Dashboard: true,
+					Scopes: map[string]*static.APIScope{
+						"team-a": {Namespaces: []string{"team-a"}},
+						"file":   {Providers: []string{"file"}},
+					},
+				},
+			},
+		},
 		{
 			desc: "api_secure_without_dashboard.json",
 			staticCfg: static.Configuration{
@@ -210,6 +222,29 @@ func Test_createConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "redirection_with_exclusions.json",
+			staticCfg: static.Configuration{
+				EntryPoints: map[string]*static.EntryPoint{
+					"web": {
+						Address: ":80",
+						HTTP: static.HTTPConfig{
+							Redirections: &static.Redirections{
+								EntryPoint: &static.RedirectEntryPoint{
+									To:                   "websecure",
+									Scheme:               "https",
+									Permanent:            true,
+									ExcludedPathPrefixes: []string{"/.well-known/acme-challenge/", "/health"},
+								},
+							},
+						},
+					},
+					"websecure": {
+						Address: ":443",
+					},
+				},
+			},
+		},
 		{
 			desc: "redirection_port.json",
 			staticCfg: static.Configuration{
@@ -0,0 +1,219 @@
+package rules
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/gorilla/mux"
+	"github.com/vulcand/predicate"
+)
+
+// Maximum number of entries of the caches.
+// The caches are shared by all the routers, and kept across the configuration reloads.
+const (
+	ruleCacheSize       = 10000
+	hostRegexpCacheSize = 10000
+)
+
+// Names of the caches, used as the cache label of the cache requests metric.
+const (
+	ruleCacheName       = "rule"
+	tcpRuleCacheName    = "tcp_rule"
+	hostRegexpCacheName = "host_regexp"
+)
+
+var (
+	// ruleCache holds the trees of the HTTP rules, by rule.
+	ruleCache = newLRUCache(ruleCacheName, ruleCacheSize)
+	// tcpRuleCache holds the trees of the TCP rules, by rule.
+	tcpRuleCache = newLRUCache(tcpRuleCacheName, ruleCacheSize)
+	// hostRegexpCache holds the routes matching a HostRegexp template, by template.
+	hostRegexpCache = newLRUCache(hostRegexpCacheName, hostRegexpCacheSize)
+)
+
+var (
+	cacheRequestsCounterMu sync.RWMutex
+	cacheRequestsCounter   metrics.Counter = discard.NewCounter()
+)
+
+// SetCacheRequestsCounter sets the counter of the requests to the rules caches,
+// partitioned by cache and result (hit or miss).
+func SetCacheRequestsCounter(counter metrics.Counter) {
+	if counter == nil {
+		counter = discard.NewCounter()
+	}
+
+	cacheRequestsCounterMu.Lock()
+	cacheRequestsCounter = counter
+	cacheRequestsCounterMu.Unlock()
+}
+
+func countCacheRequest(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	cacheRequestsCounterMu.RLock()
+	counter := cacheRequestsCounter
+	cacheRequestsCounterMu.RUnlock()
+
+	counter.With("cache", cache, "result", result).Add(1)
+}
+
+// lruCache is a fixed size cache, safe for concurrent use,
+// evicting the least recently used entry when full.
+type lruCache struct {
+	name string
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+func newLRUCache(name string, size int) *lruCache {
+	return &lruCache{
+		name:    name,
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the value cached for the key, and reports whether it was found.
+func (c *lruCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+
+	countCacheRequest(c.name, ok)
+
+	if !ok {
+		return nil, false
+	}
+
+	return elem.Value.(*lruEntry).value, true
+}
+
+// add caches the value for the key, evicting the least recently used entry if the cache is full.
+func (c *lruCache) add(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *lruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// parseRule returns the tree of an HTTP rule.
+// The tree is a copy of the cached one, as the matchers may modify their values.
+func parseRule(rule string) (*tree, error) {
+	return parseCachedRule(ruleCache, newParser, rule)
+}
+
+// parseTCPRule returns the tree of a TCP rule.
+func parseTCPRule(rule string) (*tree, error) {
+	ruleTree, err := parseCachedRule(tcpRuleCache, newTCPParser, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTCPRule(ruleTree); err != nil {
+		return nil, err
+	}
+
+	return ruleTree, nil
+}
+
+func parseCachedRule(cache *lruCache, buildParser func() (predicate.Parser, error), rule string) (*tree, error) {
+	if cached, ok := cache.get(rule); ok {
+		return cached.(*tree).clone(), nil
+	}
+
+	parser, err := buildParser()
+	if err != nil {
+		return nil, err
+	}
+
+	parse, err := parser.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	buildTree, ok := parse.(treeBuilder)
+	if !ok {
+		return nil, errors.New("cannot parse")
+	}
+
+	ruleTree := buildTree()
+	cache.add(rule, ruleTree.clone())
+
+	return ruleTree, nil
+}
+
+// clone returns a deep copy of the tree.
+func (t *tree) clone() *tree {
+	if t == nil {
+		return nil
+	}
+
+	cloned := &tree{
+		matcher:   t.matcher,
+		not:       t.not,
+		ruleLeft:  t.ruleLeft.clone(),
+		ruleRight: t.ruleRight.clone(),
+	}
+
+	if t.value != nil {
+		cloned.value = make([]string, len(t.value))
+		copy(cloned.value, t.value)
+	}
+
+	return cloned
+}
+
+// hostRegexpRoute returns a route matching the requests whose host matches the HostRegexp template.
+// The route only holds a host matcher, and is not modified once cached, so it can be shared by the routers.
+func hostRegexpRoute(template string) (*mux.Route, error) {
+	if cached, ok := hostRegexpCache.get(template); ok {
+		return cached.(*mux.Route), nil
+	}
+
+	route := mux.NewRouter().Host(template)
+	if err := route.GetError(); err != nil {
+		return nil, err
+	}
+
+	hostRegexpCache.add(template, route)
+
+	return route, nil
+}
@@ -0,0 +1,122 @@
+package rules
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCounter is a metrics.Counter implementation counting the additions by label values.
+type countingCounter struct {
+	mu     *sync.Mutex
+	counts map[string]float64
+	labels []string
+}
+
+func newCountingCounter() *countingCounter {
+	return &countingCounter{mu: &sync.Mutex{}, counts: make(map[string]float64)}
+}
+
+func (c *countingCounter) With(labelValues ...string) metrics.Counter {
+	return &countingCounter{mu: c.mu, counts: c.counts, labels: labelValues}
+}
+
+func (c *countingCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts[strings.Join(c.labels, ",")] += delta
+}
+
+func (c *countingCounter) count(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[strings.Join(labelValues, ",")]
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := newLRUCache("test", 2)
+
+	cache.add("foo", 1)
+	cache.add("bar", 2)
+
+	// foo becomes the most recently used entry.
+	value, ok := cache.get("foo")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	cache.add("baz", 3)
+	assert.Equal(t, 2, cache.len())
+
+	_, ok = cache.get("bar")
+	assert.False(t, ok)
+
+	value, ok = cache.get("foo")
+	require.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	value, ok = cache.get("baz")
+	require.True(t, ok)
+	assert.Equal(t, 3, value)
+
+	cache.add("baz", 4)
+	assert.Equal(t, 2, cache.len())
+
+	value, ok = cache.get("baz")
+	require.True(t, ok)
+	assert.Equal(t, 4, value)
+}
+
+func TestLRUCache_requestsCounter(t *testing.T) {
+	counter := newCountingCounter()
+	SetCacheRequestsCounter(counter)
+	t.Cleanup(func() { SetCacheRequestsCounter(nil) })
+
+	cache := newLRUCache("counted", 10)
+	cache.add("foo", 1)
+
+	cache.get("foo")
+	cache.get("foo")
+	cache.get("bar")
+
+	assert.Equal(t, float64(2), counter.count("cache", "counted", "result", "hit"))
+	assert.Equal(t, float64(1), counter.count("cache", "counted", "result", "miss"))
+}
+
+func TestParseRule_cachedTreeNotModified(t *testing.T) {
+	rule := "Host(`Cached.Example.com`) || HostRegexp(`{sub:[a-z]+}.cached.example.com`)"
+
+	for i := 0; i < 2; i++ {
+		router, err := NewRouter()
+		require.NoError(t, err)
+
+		err = router.AddRoute(rule, 0, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+		require.NoError(t, err)
+
+		// The Host matcher lowercases its values, which must not alter the cached tree.
+		domains, err := ParseDomains(rule)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cached.example.com"}, domains)
+
+		cached, ok := ruleCache.get(rule)
+		require.True(t, ok)
+		assert.Equal(t, []string{"Cached.Example.com"}, cached.(*tree).ruleLeft.value)
+
+		_, ok = hostRegexpCache.get("{sub:[a-z]+}.cached.example.com")
+		assert.True(t, ok)
+
+		req := httptest.NewRequest(http.MethodGet, "http://foo.cached.example.com/", nil)
+		assert.True(t, router.Match(req, &mux.RouteMatch{}))
+
+		req = httptest.NewRequest(http.MethodGet, "http://foo.bar.cached.example.com/", nil)
+		assert.False(t, router.Match(req, &mux.RouteMatch{}))
+	}
+}
@@ -16,43 +16,186 @@ type treeBuilder func() *tree
 
 // ParseDomains extract domains from rule.
 func ParseDomains(rule string) ([]string, error) {
-	parser, err := newParser()
+	ruleTree, err := parseRule(rule)
 	if err != nil {
 		return nil, err
 	}
 
-	parse, err := parser.Parse(rule)
+	return lower(parseDomain(ruleTree)), nil
+}
+
+// ParseHostSNI extracts the HostSNIs declared in a rule.
+// This is a first naive implementation used in TCP routing.
+func ParseHostSNI(rule string) ([]string, error) {
+	ruleTree, err := parseTCPRule(rule)
 	if err != nil {
 		return nil, err
 	}
 
-	buildTree, ok := parse.(treeBuilder)
-	if !ok {
-		return nil, errors.New("cannot parse")
+	return lower(parseDomain(ruleTree)), nil
+}
+
+// ParseSSH extracts the client software versions declared in the SSH matchers of a rule,
+// and reports whether the rule has an SSH matcher.
+// An SSH matcher without values matches all the SSH clients.
+func ParseSSH(rule string) ([]string, bool, error) {
+	ruleTree, err := parseTCPRule(rule)
+	if err != nil {
+		return nil, false, err
 	}
 
-	return lower(parseDomain(buildTree())), nil
+	versions, found := parseSSH(ruleTree)
+
+	return versions, found, nil
 }
 
-// ParseHostSNI extracts the HostSNIs declared in a rule.
-// This is a first naive implementation used in TCP routing.
-func ParseHostSNI(rule string) ([]string, error) {
-	parser, err := newTCPParser()
+// TCPRuleBranch is an alternative of a TCP rule, which matches a connection when one of its branches does.
+// The HostSNI and SSH matchers of a branch only match the connections whose client IP is allowed by its ClientIP matchers.
+type TCPRuleBranch struct {
+	// Domains are the values of the HostSNI matchers of the branch.
+	Domains []string
+	// SSH reports whether the branch has an SSH matcher, matching the client software versions of SSHVersions.
+	SSH         bool
+	SSHVersions []string
+	// ClientIPs are the values of the ClientIP matchers combined with the branch,
+	// a client IP having to match one of the values of each of them.
+	ClientIPs [][]string
+}
+
+// ParseTCPRuleBranches splits a TCP rule into the branches of its || operators,
+// so that its ClientIP matchers only restrict the matchers they are combined with by the && operator.
+func ParseTCPRuleBranches(rule string) ([]TCPRuleBranch, error) {
+	ruleTree, err := parseTCPRule(rule)
 	if err != nil {
 		return nil, err
 	}
 
-	parse, err := parser.Parse(rule)
-	if err != nil {
-		return nil, err
+	return parseTCPRuleBranches(ruleTree)
+}
+
+func parseTCPRuleBranches(tree *tree) ([]TCPRuleBranch, error) {
+	switch tree.matcher {
+	case or:
+		leftBranches, err := parseTCPRuleBranches(tree.ruleLeft)
+		if err != nil {
+			return nil, err
+		}
+
+		rightBranches, err := parseTCPRuleBranches(tree.ruleRight)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(leftBranches, rightBranches...), nil
+	case and:
+		// checkTCPRule ensures that one of the operands only holds ClientIP matchers.
+		rule, clientIPRule := tree.ruleLeft, tree.ruleRight
+		if isClientIPRule(rule) {
+			rule, clientIPRule = clientIPRule, rule
+		}
+
+		clientIPs, err := parseClientIP(clientIPRule)
+		if err != nil {
+			return nil, err
+		}
+
+		branches, err := parseTCPRuleBranches(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range branches {
+			branches[i].ClientIPs = append(branches[i].ClientIPs, clientIPs)
+		}
+
+		return branches, nil
+	case "HostSNI":
+		return []TCPRuleBranch{{Domains: lower(tree.value)}}, nil
+	case "SSH":
+		return []TCPRuleBranch{{SSH: true, SSHVersions: tree.value}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func parseClientIP(tree *tree) ([]string, error) {
+	switch tree.matcher {
+	case or:
+		leftIPs, err := parseClientIP(tree.ruleLeft)
+		if err != nil {
+			return nil, err
+		}
+
+		rightIPs, err := parseClientIP(tree.ruleRight)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(leftIPs, rightIPs...), nil
+	case "ClientIP":
+		if len(tree.value) == 0 {
+			return nil, errors.New("no args for matcher ClientIP")
+		}
+
+		return tree.value, nil
+	default:
+		return nil, nil
 	}
+}
 
-	buildTree, ok := parse.(treeBuilder)
-	if !ok {
-		return nil, errors.New("cannot parse")
+// checkTCPRule checks that the && operators of a TCP rule combine ClientIP matchers with the other matchers,
+// and that the ClientIP matchers are only used with the && operator.
+func checkTCPRule(tree *tree) error {
+	switch tree.matcher {
+	case and:
+		leftClientIP, rightClientIP := isClientIPRule(tree.ruleLeft), isClientIPRule(tree.ruleRight)
+		if leftClientIP == rightClientIP {
+			return errors.New("the && operator of a TCP rule must combine ClientIP matchers with other matchers")
+		}
+
+		if leftClientIP {
+			return checkTCPRule(tree.ruleRight)
+		}
+		return checkTCPRule(tree.ruleLeft)
+	case or:
+		if isClientIPRule(tree.ruleLeft) || isClientIPRule(tree.ruleRight) {
+			return errors.New("the ClientIP matchers of a TCP rule must be combined with other matchers with the && operator")
+		}
+
+		if err := checkTCPRule(tree.ruleLeft); err != nil {
+			return err
+		}
+		return checkTCPRule(tree.ruleRight)
+	case "ClientIP":
+		return errors.New("the ClientIP matchers of a TCP rule must be combined with other matchers with the && operator")
+	default:
+		return nil
 	}
+}
 
-	return lower(parseDomain(buildTree())), nil
+// isClientIPRule reports whether the rule only holds ClientIP matchers combined with the || operator.
+func isClientIPRule(tree *tree) bool {
+	switch tree.matcher {
+	case or:
+		return isClientIPRule(tree.ruleLeft) && isClientIPRule(tree.ruleRight)
+	case "ClientIP":
+		return true
+	default:
+		return false
+	}
+}
+
+func parseSSH(tree *tree) ([]string, bool) {
+	switch tree.matcher {
+	case and, or:
+		leftVersions, leftFound := parseSSH(tree.ruleLeft)
+		rightVersions, rightFound := parseSSH(tree.ruleRight)
+		return append(leftVersions, rightVersions...), leftFound || rightFound
+	case "SSH":
+		return tree.value, true
+	default:
+		return nil, false
+	}
 }
 
 func lower(slice []string) []string {
@@ -150,23 +293,26 @@ func newTCPParser() (predicate.Parser, error) {
 	parserFuncs := make(map[string]interface{})
 
 	// FIXME quircky way of waiting for new rules
-	matcherName := "HostSNI"
-	fn := func(value ...string) treeBuilder {
-		return func() *tree {
-			return &tree{
-				matcher: matcherName,
-				value:   value,
+	for _, matcherName := range []string{"HostSNI", "SSH", "ClientIP"} {
+		matcherName := matcherName
+		fn := func(value ...string) treeBuilder {
+			return func() *tree {
+				return &tree{
+					matcher: matcherName,
+					value:   value,
+				}
 			}
 		}
+		parserFuncs[matcherName] = fn
+		parserFuncs[strings.ToLower(matcherName)] = fn
+		parserFuncs[strings.ToUpper(matcherName)] = fn
+		parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 	}
-	parserFuncs[matcherName] = fn
-	parserFuncs[strings.ToLower(matcherName)] = fn
-	parserFuncs[strings.ToUpper(matcherName)] = fn
-	parserFuncs[strings.Title(strings.ToLower(matcherName))] = fn
 
 	return predicate.NewParser(predicate.Def{
 		Operators: predicate.Operators{
-			OR: orFunc,
+			AND: andFunc,
+			OR:  orFunc,
 		},
 		Functions: parserFuncs,
 	})
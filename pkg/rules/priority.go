@@ -0,0 +1,135 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Strategies computing the priority of the rules without an explicit one.
+const (
+	// PriorityStrategyLength uses the length of the rule, the longest rules being evaluated first.
+	PriorityStrategyLength = "length"
+	// PriorityStrategySpecificity uses the matchers of the rule, the most specific rules being evaluated first.
+	PriorityStrategySpecificity = "specificity"
+)
+
+// Weights of the matchers for the specificity strategy.
+// An exact host is more specific than a host pattern, which is more specific than a path,
+// which is more specific than a path prefix, which is more specific than any other matcher.
+const (
+	hostWeight       = 100000
+	hostRegexpWeight = 50000
+	pathWeight       = 20000
+	pathPrefixWeight = 10000
+	otherWeight      = 1000
+	notWeight        = 1
+	maxPathLength    = 9999
+)
+
+// Route is the rule of a router, with its effective priority.
+type Route struct {
+	Name     string
+	Rule     string
+	Priority int
+}
+
+// ValidatePriorityStrategy checks that the priority strategy is known.
+func ValidatePriorityStrategy(strategy string) error {
+	switch strategy {
+	case "", PriorityStrategyLength, PriorityStrategySpecificity:
+		return nil
+	default:
+		return fmt.Errorf("unknown priority strategy %q, expected %s or %s", strategy, PriorityStrategyLength, PriorityStrategySpecificity)
+	}
+}
+
+// GetPriority returns the priority of a rule: the explicit priority if any,
+// otherwise the priority computed with the given strategy, which defaults to the length strategy.
+func GetPriority(rule string, priority int, strategy string) (int, error) {
+	if priority != 0 {
+		return priority, nil
+	}
+
+	switch strategy {
+	case "", PriorityStrategyLength:
+		return len(rule), nil
+	case PriorityStrategySpecificity:
+		ruleTree, err := parseRule(rule)
+		if err != nil {
+			return 0, fmt.Errorf("error while parsing rule %s: %w", rule, err)
+		}
+
+		// The priority must not be zero, which means no priority.
+		return specificity(ruleTree) + 1, nil
+	default:
+		return 0, ValidatePriorityStrategy(strategy)
+	}
+}
+
+// specificity returns the specificity of a rule:
+// the sum of the weights of its matchers for an and,
+// and the weight of its least specific alternative for an or.
+func specificity(tree *tree) int {
+	switch tree.matcher {
+	case and:
+		return specificity(tree.ruleLeft) + specificity(tree.ruleRight)
+	case or:
+		left, right := specificity(tree.ruleLeft), specificity(tree.ruleRight)
+		if left < right {
+			return left
+		}
+		return right
+	}
+
+	// A negated matcher matches almost all the requests.
+	if tree.not {
+		return notWeight
+	}
+
+	switch tree.matcher {
+	case "Host", "HostHeader":
+		return hostWeight
+	case "HostRegexp":
+		return hostRegexpWeight
+	case "Path":
+		return pathWeight + minPathLength(tree.value)
+	case "PathPrefix":
+		return pathPrefixWeight + minPathLength(tree.value)
+	default:
+		return otherWeight
+	}
+}
+
+// minPathLength returns the length of the shortest literal part of the paths,
+// i.e. the length of the paths before their first variable.
+func minPathLength(paths []string) int {
+	var minLength int
+	for i, path := range paths {
+		if idx := strings.Index(path, "{"); idx >= 0 {
+			path = path[:idx]
+		}
+
+		length := len(path)
+		if length > maxPathLength {
+			length = maxPathLength
+		}
+
+		if i == 0 || length < minLength {
+			minLength = length
+		}
+	}
+
+	return minLength
+}
+
+// SortRoutes sorts the routes in evaluation order: by decreasing priority, then by name,
+// so that the order of the routes with the same priority does not depend on the order of their definition.
+func SortRoutes(routes []Route) {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Priority != routes[j].Priority {
+			return routes[i].Priority > routes[j].Priority
+		}
+		return routes[i].Name < routes[j].Name
+	})
+}
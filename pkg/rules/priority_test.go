@@ -0,0 +1,138 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPriority(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		rule             string
+		priority         int
+		strategy         string
+		expectedPriority int
+		expectedError    bool
+	}{
+		{
+			desc:             "explicit priority",
+			rule:             "Host(`foo.bar`)",
+			priority:         42,
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: 42,
+		},
+		{
+			desc:             "default strategy",
+			rule:             "Host(`foo.bar`)",
+			expectedPriority: 15,
+		},
+		{
+			desc:             "length strategy",
+			rule:             "Host(`foo.bar`)",
+			strategy:         PriorityStrategyLength,
+			expectedPriority: 15,
+		},
+		{
+			desc:             "specificity of host",
+			rule:             "Host(`foo.bar`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: hostWeight + 1,
+		},
+		{
+			desc:             "specificity of host regexp",
+			rule:             "HostRegexp(`{subdomain:[a-z]+}.foo.bar`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: hostRegexpWeight + 1,
+		},
+		{
+			desc:             "specificity of path",
+			rule:             "Path(`/foo`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: pathWeight + 4 + 1,
+		},
+		{
+			desc:             "specificity of path with variable",
+			rule:             "Path(`/foo/{id}`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: pathWeight + 5 + 1,
+		},
+		{
+			desc:             "specificity of path prefix",
+			rule:             "PathPrefix(`/foo`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: pathPrefixWeight + 4 + 1,
+		},
+		{
+			desc:             "specificity of and",
+			rule:             "Host(`foo.bar`) && PathPrefix(`/foo`) && Method(`GET`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: hostWeight + pathPrefixWeight + 4 + otherWeight + 1,
+		},
+		{
+			desc:             "specificity of or",
+			rule:             "Host(`foo.bar`) || PathPrefix(`/foo`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: pathPrefixWeight + 4 + 1,
+		},
+		{
+			desc:             "specificity of several values",
+			rule:             "PathPrefix(`/foo`, `/foobar`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: pathPrefixWeight + 4 + 1,
+		},
+		{
+			desc:             "specificity of not",
+			rule:             "Host(`foo.bar`) && !Path(`/foo`)",
+			strategy:         PriorityStrategySpecificity,
+			expectedPriority: hostWeight + notWeight + 1,
+		},
+		{
+			desc:          "invalid rule",
+			rule:          "Host(`foo.bar`",
+			strategy:      PriorityStrategySpecificity,
+			expectedError: true,
+		},
+		{
+			desc:          "unknown strategy",
+			rule:          "Host(`foo.bar`)",
+			strategy:      "foo",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			priority, err := GetPriority(test.rule, test.priority, test.strategy)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedPriority, priority)
+		})
+	}
+}
+
+func TestSortRoutes(t *testing.T) {
+	routes := []Route{
+		{Name: "c", Priority: 10},
+		{Name: "b", Priority: 20},
+		{Name: "d", Priority: 10},
+		{Name: "a", Priority: 10},
+	}
+
+	SortRoutes(routes)
+
+	assert.Equal(t, []Route{
+		{Name: "b", Priority: 20},
+		{Name: "a", Priority: 10},
+		{Name: "c", Priority: 10},
+		{Name: "d", Priority: 10},
+	}, routes)
+}
@@ -2,7 +2,10 @@ package rules
 
 import (
 	"fmt"
+	"mime"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -10,60 +13,94 @@ import (
 	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
-	"github.com/vulcand/predicate"
+	"golang.org/x/text/language"
 )
 
 var funcs = map[string]func(*mux.Route, ...string) error{
-	"Host":          host,
-	"HostHeader":    host,
-	"HostRegexp":    hostRegexp,
-	"ClientIP":      clientIP,
-	"Path":          path,
-	"PathPrefix":    pathPrefix,
-	"Method":        methods,
-	"Headers":       headers,
-	"HeadersRegexp": headersRegexp,
-	"Query":         query,
+	"Host":           host,
+	"HostHeader":     host,
+	"HostRegexp":     hostRegexp,
+	"ClientIP":       clientIP(&ip.RemoteAddrStrategy{}),
+	"Path":           path,
+	"PathPrefix":     pathPrefix,
+	"Method":         methods,
+	"Headers":        headers,
+	"HeadersRegexp":  headersRegexp,
+	"HeaderRegexp":   headersRegexp,
+	"Query":          query,
+	"QueryRegexp":    queryRegexp,
+	"AcceptLanguage": acceptLanguage,
+	"Accept":         accept,
+}
+
+var matcherNameRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// MatcherConstructor builds a request matcher from the values of a rule matcher,
+// e.g. mobile for DeviceType(`mobile`).
+// It returns an error if the values are invalid.
+type MatcherConstructor func(values ...string) (func(*http.Request) bool, error)
+
+// RegisterMatcher registers a custom rule matcher, usable in the HTTP router rules.
+// It must be called before any router is built.
+func RegisterMatcher(name string, constructor MatcherConstructor) error {
+	if !matcherNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid matcher name %q", name)
+	}
+
+	for matcherName := range funcs {
+		if strings.EqualFold(matcherName, name) {
+			return fmt.Errorf("matcher %s is already defined", name)
+		}
+	}
+
+	funcs[name] = func(route *mux.Route, values ...string) error {
+		match, err := constructor(values...)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for %q matcher: %w", values, name, err)
+		}
+
+		route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+			return match(req)
+		})
+
+		return nil
+	}
+
+	return nil
 }
 
 // Router handle routing with rules.
 type Router struct {
 	*mux.Router
-	parser predicate.Parser
 }
 
 // NewRouter returns a new router instance.
 func NewRouter() (*Router, error) {
-	parser, err := newParser()
-	if err != nil {
-		return nil, err
-	}
-
 	return &Router{
 		Router: mux.NewRouter().SkipClean(true),
-		parser: parser,
 	}, nil
 }
 
 // AddRoute add a new route to the router.
 func (r *Router) AddRoute(rule string, priority int, handler http.Handler) error {
-	parse, err := r.parser.Parse(rule)
+	return r.AddRouteWithIPStrategy(rule, priority, nil, handler)
+}
+
+// AddRouteWithIPStrategy adds a new route to the router,
+// whose ClientIP matchers get the client IP with the given strategy instead of the remote address.
+func (r *Router) AddRouteWithIPStrategy(rule string, priority int, strategy ip.Strategy, handler http.Handler) error {
+	ruleTree, err := parseRule(rule)
 	if err != nil {
 		return fmt.Errorf("error while parsing rule %s: %w", rule, err)
 	}
 
-	buildTree, ok := parse.(treeBuilder)
-	if !ok {
-		return fmt.Errorf("error while parsing rule %s", rule)
-	}
-
 	if priority == 0 {
 		priority = len(rule)
 	}
 
 	route := r.NewRoute().Handler(handler).Priority(priority)
 
-	err = addRuleOnRoute(route, buildTree())
+	err = addRuleOnRoute(route, ruleTree, strategy)
 	if err != nil {
 		route.BuildOnly()
 		return err
@@ -157,25 +194,26 @@ func host(route *mux.Route, hosts ...string) error {
 	return nil
 }
 
-func clientIP(route *mux.Route, clientIPs ...string) error {
-	checker, err := ip.NewChecker(clientIPs)
-	if err != nil {
-		return fmt.Errorf("could not initialize IP Checker for \"ClientIP\" matcher: %w", err)
-	}
-
-	strategy := ip.RemoteAddrStrategy{}
-
-	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
-		ok, err := checker.Contains(strategy.GetIP(req))
+// clientIP returns the ClientIP matcher, getting the client IP with the given strategy.
+func clientIP(strategy ip.Strategy) func(*mux.Route, ...string) error {
+	return func(route *mux.Route, clientIPs ...string) error {
+		checker, err := ip.NewChecker(clientIPs)
 		if err != nil {
-			log.FromContext(req.Context()).Warnf("\"ClientIP\" matcher: could not match remote address : %w", err)
-			return false
+			return fmt.Errorf("could not initialize IP Checker for \"ClientIP\" matcher: %w", err)
 		}
 
-		return ok
-	})
+		route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+			ok, err := checker.Contains(strategy.GetIP(req))
+			if err != nil {
+				log.FromContext(req.Context()).Warnf("\"ClientIP\" matcher: could not match remote address : %v", err)
+				return false
+			}
 
-	return nil
+			return ok
+		})
+
+		return nil
+	}
 }
 
 func hostRegexp(route *mux.Route, hosts ...string) error {
@@ -185,10 +223,12 @@ func hostRegexp(route *mux.Route, hosts ...string) error {
 			return fmt.Errorf("invalid value %q for HostRegexp matcher, non-ASCII characters are not allowed", host)
 		}
 
-		tmpRt := router.Host(host)
-		if tmpRt.GetError() != nil {
-			return tmpRt.GetError()
+		hostRoute, err := hostRegexpRoute(host)
+		if err != nil {
+			return err
 		}
+
+		router.NewRoute().MatcherFunc(hostRoute.Match)
 	}
 	return nil
 }
@@ -216,23 +256,210 @@ func query(route *mux.Route, query ...string) error {
 	return route.GetError()
 }
 
-func addRuleOnRouter(router *mux.Router, rule *tree) error {
+// queryRegexp matches the requests having, for each key=regexp pair,
+// a query parameter named key with a value matching the regular expression.
+func queryRegexp(route *mux.Route, query ...string) error {
+	type queryMatcher struct {
+		key    string
+		regexp *regexp.Regexp
+	}
+
+	var matchers []queryMatcher
+	for _, elem := range query {
+		parts := strings.SplitN(elem, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("invalid value %q for QueryRegexp matcher, expected key=regexp", elem)
+		}
+
+		re, err := regexp.Compile(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid regular expression %q for QueryRegexp matcher: %w", parts[1], err)
+		}
+
+		matchers = append(matchers, queryMatcher{key: parts[0], regexp: re})
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		values := req.URL.Query()
+
+		for _, matcher := range matchers {
+			var found bool
+			for _, value := range values[matcher.key] {
+				if matcher.regexp.MatchString(value) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	return nil
+}
+
+// acceptLanguage matches the requests whose Accept-Language header best matches one of the given languages.
+func acceptLanguage(route *mux.Route, languages ...string) error {
+	var tags []language.Tag
+	for _, lang := range languages {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return fmt.Errorf("invalid language %q for AcceptLanguage matcher: %w", lang, err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	matcher := language.NewMatcher(tags)
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		accepted, _, err := language.ParseAcceptLanguage(strings.Join(req.Header.Values("Accept-Language"), ","))
+		if err != nil || len(accepted) == 0 {
+			return false
+		}
+
+		_, _, confidence := matcher.Match(accepted...)
+		return confidence != language.No
+	})
+
+	return nil
+}
+
+// mediaRange is a media range of an Accept header, e.g. application/*;q=0.8.
+type mediaRange struct {
+	mainType string
+	subType  string
+	params   map[string]string
+	quality  float64
+}
+
+// accept matches the requests whose Accept header accepts one of the given media types, with a non-zero quality.
+// The quality of a media type is the one of the most specific media range matching it (RFC 7231 section 5.3.2).
+func accept(route *mux.Route, mediaTypes ...string) error {
+	var ranges []mediaRange
+	for _, mediaType := range mediaTypes {
+		parsed, ok := parseMediaRange(mediaType)
+		if !ok || parsed.mainType == "*" || parsed.subType == "*" {
+			return fmt.Errorf("invalid media type %q for Accept matcher", mediaType)
+		}
+
+		ranges = append(ranges, parsed)
+	}
+
+	route.MatcherFunc(func(req *http.Request, _ *mux.RouteMatch) bool {
+		values := req.Header.Values("Accept")
+		if len(values) == 0 {
+			return false
+		}
+
+		var accepted []mediaRange
+		for _, value := range values {
+			for _, elem := range strings.Split(value, ",") {
+				if parsed, ok := parseMediaRange(elem); ok {
+					accepted = append(accepted, parsed)
+				}
+			}
+		}
+
+		for _, mediaType := range ranges {
+			if mediaTypeQuality(mediaType, accepted) > 0 {
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return nil
+}
+
+// parseMediaRange parses a media range of an Accept header.
+// Its quality defaults to 1.
+func parseMediaRange(value string) (mediaRange, bool) {
+	mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+	if err != nil {
+		return mediaRange{}, false
+	}
+
+	parts := strings.SplitN(mediaType, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || (parts[0] == "*" && parts[1] != "*") {
+		return mediaRange{}, false
+	}
+
+	result := mediaRange{mainType: parts[0], subType: parts[1], params: params, quality: 1}
+
+	if quality, ok := params["q"]; ok {
+		result.quality, err = strconv.ParseFloat(quality, 64)
+		if err != nil || result.quality < 0 || result.quality > 1 {
+			return mediaRange{}, false
+		}
+
+		delete(params, "q")
+	}
+
+	return result, true
+}
+
+// mediaTypeQuality returns the quality of the media type given by the most specific of the accepted media ranges matching it,
+// or 0 if none matches.
+func mediaTypeQuality(mediaType mediaRange, accepted []mediaRange) float64 {
+	var quality float64
+	bestSpecificity := -1
+
+	for _, acceptedRange := range accepted {
+		specificity, ok := matchMediaRange(acceptedRange, mediaType)
+		if ok && specificity > bestSpecificity {
+			quality, bestSpecificity = acceptedRange.quality, specificity
+		}
+	}
+
+	return quality
+}
+
+// matchMediaRange reports whether the media range matches the media type, and returns how specific the media range is:
+// a type is more specific than a subtype wildcard, which is more specific than */*, and the parameters add to the specificity.
+func matchMediaRange(acceptedRange, mediaType mediaRange) (int, bool) {
+	for name, value := range acceptedRange.params {
+		if !strings.EqualFold(mediaType.params[name], value) {
+			return 0, false
+		}
+	}
+
+	switch {
+	case acceptedRange.mainType == "*":
+		return len(acceptedRange.params), true
+	case acceptedRange.mainType != mediaType.mainType:
+		return 0, false
+	case acceptedRange.subType == "*":
+		return 1000 + len(acceptedRange.params), true
+	case acceptedRange.subType == mediaType.subType:
+		return 2000 + len(acceptedRange.params), true
+	default:
+		return 0, false
+	}
+}
+
+func addRuleOnRouter(router *mux.Router, rule *tree, strategy ip.Strategy) error {
 	switch rule.matcher {
 	case "and":
 		route := router.NewRoute()
-		err := addRuleOnRoute(route, rule.ruleLeft)
+		err := addRuleOnRoute(route, rule.ruleLeft, strategy)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRoute(route, rule.ruleRight)
+		return addRuleOnRoute(route, rule.ruleRight, strategy)
 	case "or":
-		err := addRuleOnRouter(router, rule.ruleLeft)
+		err := addRuleOnRouter(router, rule.ruleLeft, strategy)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRouter(router, rule.ruleRight)
+		return addRuleOnRouter(router, rule.ruleRight, strategy)
 	default:
 		err := checkRule(rule)
 		if err != nil {
@@ -240,9 +467,9 @@ func addRuleOnRouter(router *mux.Router, rule *tree) error {
 		}
 
 		if rule.not {
-			return not(funcs[rule.matcher])(router.NewRoute(), rule.value...)
+			return not(matcher(rule.matcher, strategy))(router.NewRoute(), rule.value...)
 		}
-		return funcs[rule.matcher](router.NewRoute(), rule.value...)
+		return matcher(rule.matcher, strategy)(router.NewRoute(), rule.value...)
 	}
 }
 
@@ -260,24 +487,24 @@ func not(m func(*mux.Route, ...string) error) func(*mux.Route, ...string) error
 	}
 }
 
-func addRuleOnRoute(route *mux.Route, rule *tree) error {
+func addRuleOnRoute(route *mux.Route, rule *tree, strategy ip.Strategy) error {
 	switch rule.matcher {
 	case "and":
-		err := addRuleOnRoute(route, rule.ruleLeft)
+		err := addRuleOnRoute(route, rule.ruleLeft, strategy)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRoute(route, rule.ruleRight)
+		return addRuleOnRoute(route, rule.ruleRight, strategy)
 	case "or":
 		subRouter := route.Subrouter()
 
-		err := addRuleOnRouter(subRouter, rule.ruleLeft)
+		err := addRuleOnRouter(subRouter, rule.ruleLeft, strategy)
 		if err != nil {
 			return err
 		}
 
-		return addRuleOnRouter(subRouter, rule.ruleRight)
+		return addRuleOnRouter(subRouter, rule.ruleRight, strategy)
 	default:
 		err := checkRule(rule)
 		if err != nil {
@@ -285,12 +512,22 @@ func addRuleOnRoute(route *mux.Route, rule *tree) error {
 		}
 
 		if rule.not {
-			return not(funcs[rule.matcher])(route, rule.value...)
+			return not(matcher(rule.matcher, strategy))(route, rule.value...)
 		}
-		return funcs[rule.matcher](route, rule.value...)
+		return matcher(rule.matcher, strategy)(route, rule.value...)
 	}
 }
 
+// matcher returns the function adding the given matcher on a route,
+// the ClientIP matcher getting the client IP with the given strategy when it is not nil.
+func matcher(name string, strategy ip.Strategy) func(*mux.Route, ...string) error {
+	if name == "ClientIP" && strategy != nil {
+		return clientIP(strategy)
+	}
+
+	return funcs[name]
+}
+
 func checkRule(rule *tree) error {
 	if len(rule.value) == 0 {
 		return fmt.Errorf("no args for matcher %s", rule.matcher)
@@ -1,6 +1,7 @@
 package rules
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	"github.com/traefik/traefik/v2/pkg/testhelpers"
 )
@@ -242,6 +244,26 @@ func Test_addRoute(t *testing.T) {
 				"http://localhost/foo": http.StatusOK,
 			},
 		},
+		{
+			desc: "HeaderRegexp with matching header",
+			rule: "HeaderRegexp(`Content-Type`, `application/(text|json)`)",
+			headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "HeaderRegexp without matching header",
+			rule: "HeaderRegexp(`Content-Type`, `application/(text|json)`)",
+			headers: map[string]string{
+				"Content-Type": "application/foo",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
 		{
 			desc: "Query with multiple params",
 			rule: "Query(`foo=bar`, `bar=baz`)",
@@ -250,6 +272,149 @@ func Test_addRoute(t *testing.T) {
 				"http://localhost/foo?bar=baz":         http.StatusNotFound,
 			},
 		},
+		{
+			desc: "QueryRegexp with multiple params",
+			rule: "QueryRegexp(`foo=^ba[rz]$`, `id=^[0-9]+$`)",
+			expected: map[string]int{
+				"http://localhost/foo?foo=bar&id=42":        http.StatusOK,
+				"http://localhost/foo?foo=baz&id=1":         http.StatusOK,
+				"http://localhost/foo?foo=qux&foo=bar&id=1": http.StatusOK,
+				"http://localhost/foo?foo=bar&id=abc":       http.StatusNotFound,
+				"http://localhost/foo?foo=bar":              http.StatusNotFound,
+				"http://localhost/foo?id=42":                http.StatusNotFound,
+			},
+		},
+		{
+			desc: "QueryRegexp with an empty regular expression",
+			rule: "QueryRegexp(`debug=`)",
+			expected: map[string]int{
+				"http://localhost/foo?debug":   http.StatusOK,
+				"http://localhost/foo?debug=1": http.StatusOK,
+				"http://localhost/foo":         http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Not QueryRegexp",
+			rule: "!QueryRegexp(`foo=^bar$`)",
+			expected: map[string]int{
+				"http://localhost/foo?foo=bar": http.StatusNotFound,
+				"http://localhost/foo?foo=baz": http.StatusOK,
+			},
+		},
+		{
+			desc: "AcceptLanguage with a matching language",
+			rule: "AcceptLanguage(`en`, `fr`)",
+			headers: map[string]string{
+				"Accept-Language": "fr-CH, fr;q=0.9, en;q=0.8",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "AcceptLanguage without matching language",
+			rule: "AcceptLanguage(`en`, `fr`)",
+			headers: map[string]string{
+				"Accept-Language": "de-DE, ja;q=0.5",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "AcceptLanguage without Accept-Language header",
+			rule: "AcceptLanguage(`en`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Not AcceptLanguage",
+			rule: "!AcceptLanguage(`fr`)",
+			headers: map[string]string{
+				"Accept-Language": "en-US",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "Accept with a matching media type",
+			rule: "Accept(`application/vnd.myapi.v2+json`)",
+			headers: map[string]string{
+				"Accept": "text/html, application/vnd.myapi.v2+json;q=0.9",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "Accept without matching media type",
+			rule: "Accept(`application/vnd.myapi.v2+json`)",
+			headers: map[string]string{
+				"Accept": "application/vnd.myapi.v1+json",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Accept with a media type excluded by a zero quality",
+			rule: "Accept(`application/vnd.myapi.v2+json`)",
+			headers: map[string]string{
+				"Accept": "application/*, application/vnd.myapi.v2+json;q=0",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Accept with a matching wildcard",
+			rule: "Accept(`application/vnd.myapi.v2+json`)",
+			headers: map[string]string{
+				"Accept": "text/*;q=0, application/*;q=0.5",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "Accept with matching parameters",
+			rule: "Accept(`application/json;version=2`)",
+			headers: map[string]string{
+				"Accept": "application/json;version=1;q=0, application/json;version=2",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
+		{
+			desc: "Accept without matching parameters",
+			rule: "Accept(`application/json;version=2`)",
+			headers: map[string]string{
+				"Accept": "application/json;version=1",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Accept without Accept header",
+			rule: "Accept(`application/json`)",
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "Not Accept",
+			rule: "!Accept(`application/vnd.myapi.v2+json`)",
+			headers: map[string]string{
+				"Accept": "application/vnd.myapi.v1+json",
+			},
+			expected: map[string]int{
+				"http://localhost/foo": http.StatusOK,
+			},
+		},
 		{
 			desc: "Rule with simple path",
 			rule: `Path("/a")`,
@@ -421,6 +586,31 @@ func Test_addRoute(t *testing.T) {
 			rule:          `Query("titi={test")`,
 			expectedError: true,
 		},
+		{
+			desc:          "Rule QueryRegexp without key",
+			rule:          `QueryRegexp("titi")`,
+			expectedError: true,
+		},
+		{
+			desc:          "Rule QueryRegexp with an invalid regular expression",
+			rule:          `QueryRegexp("titi=(test")`,
+			expectedError: true,
+		},
+		{
+			desc:          "Rule AcceptLanguage with an invalid language",
+			rule:          `AcceptLanguage("not a language")`,
+			expectedError: true,
+		},
+		{
+			desc:          "Rule Accept with a wildcard media type",
+			rule:          "Accept(`application/*`)",
+			expectedError: true,
+		},
+		{
+			desc:          "Rule Accept with an invalid media type",
+			rule:          "Accept(`not a media type`)",
+			expectedError: true,
+		},
 		{
 			desc:          "Rule with Path without args",
 			rule:          `Host("tchouk") && Path()`,
@@ -668,6 +858,82 @@ func Test_addRoute(t *testing.T) {
 	}
 }
 
+func TestAddRouteWithIPStrategy(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		strategy      ip.Strategy
+		xForwardedFor string
+		expected      int
+	}{
+		{
+			desc:          "remote address by default",
+			rule:          "ClientIP(`10.0.0.1`)",
+			xForwardedFor: "10.0.0.1",
+			expected:      http.StatusNotFound,
+		},
+		{
+			desc:          "matching forwarded client IP",
+			rule:          "ClientIP(`10.0.0.1`)",
+			strategy:      &ip.DepthStrategy{Depth: 1},
+			xForwardedFor: "10.0.0.1",
+			expected:      http.StatusOK,
+		},
+		{
+			desc:          "non matching forwarded client IP",
+			rule:          "ClientIP(`10.0.0.1`)",
+			strategy:      &ip.DepthStrategy{Depth: 1},
+			xForwardedFor: "10.0.0.2",
+			expected:      http.StatusNotFound,
+		},
+		{
+			desc:          "forwarded client IP with excluded proxies",
+			rule:          "ClientIP(`10.0.0.1`) && Path(`/foo`)",
+			strategy:      &ip.PoolStrategy{Checker: mustNewChecker(t, "192.168.0.0/16")},
+			xForwardedFor: "10.0.0.1, 192.168.0.1",
+			expected:      http.StatusOK,
+		},
+		{
+			desc:          "negated forwarded client IP",
+			rule:          "!ClientIP(`10.0.0.1`)",
+			strategy:      &ip.DepthStrategy{Depth: 1},
+			xForwardedFor: "10.0.0.1",
+			expected:      http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.AddRouteWithIPStrategy(test.rule, 0, test.strategy, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost/foo", nil)
+			req.RemoteAddr = "192.168.0.2:1234"
+			req.Header.Set("X-Forwarded-For", test.xForwardedFor)
+
+			rw := httptest.NewRecorder()
+			router.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.expected, rw.Code)
+		})
+	}
+}
+
+func mustNewChecker(t *testing.T, trustedIPs ...string) *ip.Checker {
+	t.Helper()
+
+	checker, err := ip.NewChecker(trustedIPs)
+	require.NoError(t, err)
+
+	return checker
+}
+
 func Test_addRoutePriority(t *testing.T) {
 	type Case struct {
 		xFrom    string
@@ -892,6 +1158,94 @@ func TestHostRegexp(t *testing.T) {
 	}
 }
 
+func TestRegisterMatcher(t *testing.T) {
+	err := RegisterMatcher("DeviceType", func(values ...string) (func(*http.Request) bool, error) {
+		for _, value := range values {
+			if value != "mobile" && value != "desktop" {
+				return nil, fmt.Errorf("unknown device type %q", value)
+			}
+		}
+
+		return func(req *http.Request) bool {
+			for _, value := range values {
+				if req.Header.Get("X-Device-Type") == value {
+					return true
+				}
+			}
+			return false
+		}, nil
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { delete(funcs, "DeviceType") })
+
+	assert.Error(t, RegisterMatcher("DeviceType", nil))
+	assert.Error(t, RegisterMatcher("host", nil))
+	assert.Error(t, RegisterMatcher("Device-Type", nil))
+
+	testCases := []struct {
+		desc          string
+		rule          string
+		deviceType    string
+		expectedError bool
+		expected      int
+	}{
+		{
+			desc:       "matching",
+			rule:       "DeviceType(`mobile`)",
+			deviceType: "mobile",
+			expected:   http.StatusOK,
+		},
+		{
+			desc:       "matching lower case",
+			rule:       "devicetype(`mobile`, `desktop`)",
+			deviceType: "desktop",
+			expected:   http.StatusOK,
+		},
+		{
+			desc:       "not matching",
+			rule:       "DeviceType(`mobile`)",
+			deviceType: "desktop",
+			expected:   http.StatusNotFound,
+		},
+		{
+			desc:       "negated",
+			rule:       "!DeviceType(`mobile`) && Path(`/foo`)",
+			deviceType: "desktop",
+			expected:   http.StatusOK,
+		},
+		{
+			desc:          "invalid value",
+			rule:          "DeviceType(`tablet`)",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.AddRoute(test.rule, 0, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://tchouk/foo", nil)
+			req.Header.Set("X-Device-Type", test.deviceType)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expected, w.Code)
+		})
+	}
+}
+
 func TestParseDomains(t *testing.T) {
 	testCases := []struct {
 		description   string
@@ -958,3 +1312,149 @@ func TestParseDomains(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSSH(t *testing.T) {
+	testCases := []struct {
+		expression       string
+		expectedVersions []string
+		expectedFound    bool
+		expectedError    bool
+	}{
+		{
+			expression: "HostSNI(`foo.bar`)",
+		},
+		{
+			expression:    "SSH()",
+			expectedFound: true,
+		},
+		{
+			expression:    "ssh()",
+			expectedFound: true,
+		},
+		{
+			expression:       "SSH(`OpenSSH*`, `PuTTY*`)",
+			expectedVersions: []string{"OpenSSH*", "PuTTY*"},
+			expectedFound:    true,
+		},
+		{
+			expression:       "HostSNI(`foo.bar`) || SSH(`OpenSSH*`)",
+			expectedVersions: []string{"OpenSSH*"},
+			expectedFound:    true,
+		},
+		{
+			expression:    "SSH() && HostSNI(`foo.bar`)",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			t.Parallel()
+
+			versions, found, err := ParseSSH(test.expression)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, test.expectedVersions, versions)
+			assert.Equal(t, test.expectedFound, found)
+		})
+	}
+}
+
+func TestParseTCPRuleBranches(t *testing.T) {
+	testCases := []struct {
+		expression       string
+		expectedBranches []TCPRuleBranch
+		expectedError    bool
+	}{
+		{
+			expression:       "HostSNI(`Foo.Bar`)",
+			expectedBranches: []TCPRuleBranch{{Domains: []string{"foo.bar"}}},
+		},
+		{
+			expression: "HostSNI(`foo.bar`) && ClientIP(`10.0.0.0/8`, `::1`)",
+			expectedBranches: []TCPRuleBranch{
+				{Domains: []string{"foo.bar"}, ClientIPs: [][]string{{"10.0.0.0/8", "::1"}}},
+			},
+		},
+		{
+			expression: "clientip(`10.0.0.1`) && HostSNI(`*`)",
+			expectedBranches: []TCPRuleBranch{
+				{Domains: []string{"*"}, ClientIPs: [][]string{{"10.0.0.1"}}},
+			},
+		},
+		{
+			expression: "(HostSNI(`foo.bar`) || SSH()) && (ClientIP(`10.0.0.1`) || ClientIP(`10.0.0.2`))",
+			expectedBranches: []TCPRuleBranch{
+				{Domains: []string{"foo.bar"}, ClientIPs: [][]string{{"10.0.0.1", "10.0.0.2"}}},
+				{SSH: true, SSHVersions: []string{}, ClientIPs: [][]string{{"10.0.0.1", "10.0.0.2"}}},
+			},
+		},
+		{
+			expression: "(HostSNI(`a.com`) && ClientIP(`1.1.1.1`)) || (HostSNI(`b.com`) && ClientIP(`2.2.2.2`))",
+			expectedBranches: []TCPRuleBranch{
+				{Domains: []string{"a.com"}, ClientIPs: [][]string{{"1.1.1.1"}}},
+				{Domains: []string{"b.com"}, ClientIPs: [][]string{{"2.2.2.2"}}},
+			},
+		},
+		{
+			expression: "HostSNI(`a.com`) || (HostSNI(`b.com`) && ClientIP(`2.2.2.2`))",
+			expectedBranches: []TCPRuleBranch{
+				{Domains: []string{"a.com"}},
+				{Domains: []string{"b.com"}, ClientIPs: [][]string{{"2.2.2.2"}}},
+			},
+		},
+		{
+			expression: "HostSNI(`a.com`) && ClientIP(`1.1.1.0/24`) && ClientIP(`1.1.1.1`)",
+			expectedBranches: []TCPRuleBranch{
+				{Domains: []string{"a.com"}, ClientIPs: [][]string{{"1.1.1.0/24"}, {"1.1.1.1"}}},
+			},
+		},
+		{
+			expression: "SSH(`OpenSSH*`) && ClientIP(`10.0.0.0/8`)",
+			expectedBranches: []TCPRuleBranch{
+				{SSH: true, SSHVersions: []string{"OpenSSH*"}, ClientIPs: [][]string{{"10.0.0.0/8"}}},
+			},
+		},
+		{
+			expression:    "HostSNI(`foo.bar`) && !ClientIP(`10.0.0.0/8`)",
+			expectedError: true,
+		},
+		{
+			expression:    "HostSNI(`foo.bar`) || ClientIP(`10.0.0.0/8`)",
+			expectedError: true,
+		},
+		{
+			expression:    "ClientIP(`10.0.0.0/8`)",
+			expectedError: true,
+		},
+		{
+			expression:    "HostSNI(`foo.bar`) && HostSNI(`bar.foo`)",
+			expectedError: true,
+		},
+		{
+			expression:    "HostSNI(`foo.bar`) && ClientIP()",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			t.Parallel()
+
+			branches, err := ParseTCPRuleBranches(test.expression)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedBranches, branches)
+		})
+	}
+}
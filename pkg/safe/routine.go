@@ -37,6 +37,11 @@ func (p *Pool) GoCtx(goroutine routineCtx) {
 	})
 }
 
+// Ctx returns the context of the pool, which is canceled when the pool is stopped.
+func (p *Pool) Ctx() context.Context {
+	return p.ctx
+}
+
 // Stop stops all started routines, waiting for their termination.
 func (p *Pool) Stop() {
 	p.cancel()
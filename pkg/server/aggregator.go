@@ -1,9 +1,14 @@
 package server
 
 import (
+	"net/http"
+	"sort"
+	"strings"
+
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tls"
 )
@@ -87,13 +92,31 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 			}
 		}
 
+		for _, conflict := range configuration.Conflicts {
+			conflict.Name = provider.MakeQualifiedName(pvd, conflict.Name)
+			conf.Conflicts = append(conf.Conflicts, conflict)
+		}
+
+		for _, labelError := range configuration.LabelErrors {
+			labelError.Provider = pvd
+			conf.LabelErrors = append(conf.LabelErrors, labelError)
+		}
+
+		for _, namespace := range configuration.Namespaces {
+			namespace.Name = provider.MakeQualifiedName(pvd, namespace.Name)
+			conf.Namespaces = append(conf.Namespaces, namespace)
+		}
+
 		if configuration.TLS != nil {
 			for _, cert := range configuration.TLS.Certificates {
 				if containsACMETLS1(cert.Stores) && pvd != "tlsalpn.acme" {
 					continue
 				}
 
-				conf.TLS.Certificates = append(conf.TLS.Certificates, cert)
+				// The certificate is copied, as the configuration of the provider is compared with the next one.
+				certificate := *cert
+				certificate.Provider = pvd
+				conf.TLS.Certificates = append(conf.TLS.Certificates, &certificate)
 			}
 
 			for key, store := range configuration.TLS.Stores {
@@ -117,6 +140,33 @@ func mergeConfiguration(configurations dynamic.Configurations, defaultEntryPoint
 		}
 	}
 
+	sort.Slice(conf.Conflicts, func(i, j int) bool {
+		if conf.Conflicts[i].Name == conf.Conflicts[j].Name {
+			return conf.Conflicts[i].Kind < conf.Conflicts[j].Kind
+		}
+		return conf.Conflicts[i].Name < conf.Conflicts[j].Name
+	})
+
+	sort.Slice(conf.LabelErrors, func(i, j int) bool {
+		if conf.LabelErrors[i].Provider != conf.LabelErrors[j].Provider {
+			return conf.LabelErrors[i].Provider < conf.LabelErrors[j].Provider
+		}
+		if conf.LabelErrors[i].Source != conf.LabelErrors[j].Source {
+			return conf.LabelErrors[i].Source < conf.LabelErrors[j].Source
+		}
+		return conf.LabelErrors[i].Label < conf.LabelErrors[j].Label
+	})
+
+	sort.Slice(conf.Namespaces, func(i, j int) bool {
+		if conf.Namespaces[i].Kind != conf.Namespaces[j].Kind {
+			return conf.Namespaces[i].Kind < conf.Namespaces[j].Kind
+		}
+		if conf.Namespaces[i].Name != conf.Namespaces[j].Name {
+			return conf.Namespaces[i].Name < conf.Namespaces[j].Name
+		}
+		return conf.Namespaces[i].Namespace < conf.Namespaces[j].Namespace
+	})
+
 	if len(defaultTLSStoreProviders) > 1 {
 		log.WithoutContext().Errorf("Default TLS Stores defined multiple times in %v", defaultTLSOptionProviders)
 		delete(conf.TLS.Stores, tls.DefaultTLSStoreName)
@@ -178,6 +228,84 @@ func applyModel(cfg dynamic.Configuration) dynamic.Configuration {
 	return cfg
 }
 
+// applySkipRedirections excludes the requests matched by the routers skipping the redirections
+// from the redirection routers of their entry points.
+func applySkipRedirections(cfg dynamic.Configuration) dynamic.Configuration {
+	if cfg.HTTP == nil {
+		return cfg
+	}
+
+	redirections := make(map[string][]*dynamic.Router)
+	for name, rt := range cfg.HTTP.Routers {
+		if !isRedirectionRouter(cfg.HTTP, name, rt) {
+			continue
+		}
+
+		for _, epName := range rt.EntryPoints {
+			redirections[epName] = append(redirections[epName], rt)
+		}
+	}
+
+	if len(redirections) == 0 {
+		return cfg
+	}
+
+	// The routers are sorted, for the rules of the redirection routers to be stable.
+	var names []string
+	for name, rt := range cfg.HTTP.Routers {
+		if rt.SkipRedirections && rt.Rule != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rt := cfg.HTTP.Routers[name]
+
+		if err := checkRule(rt.Rule); err != nil {
+			log.WithoutContext().WithField(log.RouterName, name).
+				Errorf("Unable to exclude the router from the redirections: %v", err)
+			continue
+		}
+
+		for _, epName := range rt.EntryPoints {
+			for _, redirection := range redirections[epName] {
+				redirection.Rule += " && !(" + rt.Rule + ")"
+			}
+		}
+	}
+
+	return cfg
+}
+
+// isRedirectionRouter returns true if the router is one of the routers generated for the redirections of the entry points.
+func isRedirectionRouter(cfg *dynamic.HTTPConfiguration, name string, rt *dynamic.Router) bool {
+	if !strings.HasSuffix(name, "@internal") {
+		return false
+	}
+
+	for _, middlewareName := range rt.Middlewares {
+		if !strings.Contains(middlewareName, "@") {
+			middlewareName = provider.MakeQualifiedName("internal", middlewareName)
+		}
+
+		if middleware, ok := cfg.Middlewares[middlewareName]; ok && middleware.RedirectScheme != nil && strings.HasSuffix(middlewareName, "@internal") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkRule(rule string) error {
+	router, err := rules.NewRouter()
+	if err != nil {
+		return err
+	}
+
+	return router.AddRoute(rule, 0, http.NotFoundHandler())
+}
+
 func containsACMETLS1(stores []string) bool {
 	for _, store := range stores {
 		if store == tlsalpn01.ACMETLS1Protocol {
@@ -157,6 +157,7 @@ func Test_mergeConfiguration_tlsCertificates(t *testing.T) {
 			expected: []*tls.CertAndStores{{
 				Certificate: tls.Certificate{CertFile: "foo", KeyFile: "bar"},
 				Stores:      []string{tlsalpn01.ACMETLS1Protocol},
+				Provider:    "tlsalpn.acme",
 			}},
 		},
 	}
@@ -479,6 +480,81 @@ func Test_mergeConfiguration_defaultTCPEntryPoint(t *testing.T) {
 	assert.Equal(t, expected, actual.TCP)
 }
 
+func Test_mergeConfiguration_conflicts(t *testing.T) {
+	given := dynamic.Configurations{
+		"provider-1": &dynamic.Configuration{
+			Conflicts: []dynamic.Conflict{
+				{Kind: "router", Name: "foo", Sources: []string{"container-1", "container-2"}},
+			},
+		},
+		"provider-2": &dynamic.Configuration{
+			Conflicts: []dynamic.Conflict{
+				{Kind: "service", Name: "foo", Sources: []string{"container-1", "container-2"}},
+				{Kind: "router", Name: "foo", Sources: []string{"container-3", "container-4"}},
+			},
+		},
+	}
+
+	expected := []dynamic.Conflict{
+		{Kind: "router", Name: "foo@provider-1", Sources: []string{"container-1", "container-2"}},
+		{Kind: "router", Name: "foo@provider-2", Sources: []string{"container-3", "container-4"}},
+		{Kind: "service", Name: "foo@provider-2", Sources: []string{"container-1", "container-2"}},
+	}
+
+	actual := mergeConfiguration(given, []string{"defaultEP"})
+	assert.Equal(t, expected, actual.Conflicts)
+}
+
+func Test_mergeConfiguration_labelErrors(t *testing.T) {
+	given := dynamic.Configurations{
+		"provider-1": &dynamic.Configuration{
+			LabelErrors: []dynamic.LabelError{
+				{Source: "container-2", Label: "traefik.htp.routers.foo.rule", Reason: "unknown label"},
+				{Source: "container-1", Label: "traefik.http.routers.foo.rulee", Reason: "field not found, node: rulee"},
+			},
+		},
+		"provider-2": &dynamic.Configuration{
+			LabelErrors: []dynamic.LabelError{
+				{Source: "container-1", Label: "traefik.enabled", Reason: "unknown label"},
+			},
+		},
+	}
+
+	expected := []dynamic.LabelError{
+		{Provider: "provider-1", Source: "container-1", Label: "traefik.http.routers.foo.rulee", Reason: "field not found, node: rulee"},
+		{Provider: "provider-1", Source: "container-2", Label: "traefik.htp.routers.foo.rule", Reason: "unknown label"},
+		{Provider: "provider-2", Source: "container-1", Label: "traefik.enabled", Reason: "unknown label"},
+	}
+
+	actual := mergeConfiguration(given, []string{"defaultEP"})
+	assert.Equal(t, expected, actual.LabelErrors)
+}
+
+func Test_mergeConfiguration_namespaces(t *testing.T) {
+	given := dynamic.Configurations{
+		"kubernetescrd": &dynamic.Configuration{
+			Namespaces: []dynamic.ElementNamespace{
+				{Kind: "service", Name: "team-a-foo", Namespace: "team-a"},
+				{Kind: "router", Name: "team-a-foo", Namespace: "team-a"},
+			},
+		},
+		"kubernetes": &dynamic.Configuration{
+			Namespaces: []dynamic.ElementNamespace{
+				{Kind: "router", Name: "team-a-foo", Namespace: "team"},
+			},
+		},
+	}
+
+	expected := []dynamic.ElementNamespace{
+		{Kind: "router", Name: "team-a-foo@kubernetes", Namespace: "team"},
+		{Kind: "router", Name: "team-a-foo@kubernetescrd", Namespace: "team-a"},
+		{Kind: "service", Name: "team-a-foo@kubernetescrd", Namespace: "team-a"},
+	}
+
+	actual := mergeConfiguration(given, []string{"defaultEP"})
+	assert.Equal(t, expected, actual.Namespaces)
+}
+
 func Test_applyModel(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -672,3 +748,96 @@ func Test_applyModel(t *testing.T) {
 		})
 	}
 }
+
+func Test_applySkipRedirections(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		routers      map[string]*dynamic.Router
+		expectedRule string
+	}{
+		{
+			desc: "no router skipping the redirections",
+			routers: map[string]*dynamic.Router{
+				"foo@file": {
+					EntryPoints: []string{"web"},
+					Rule:        "Host(`foo.localhost`)",
+				},
+			},
+			expectedRule: "HostRegexp(`{host:.+}`)",
+		},
+		{
+			desc: "routers skipping the redirections",
+			routers: map[string]*dynamic.Router{
+				"foo@file": {
+					EntryPoints:      []string{"web"},
+					Rule:             "Host(`foo.localhost`) && PathPrefix(`/api`)",
+					SkipRedirections: true,
+				},
+				"bar@file": {
+					EntryPoints:      []string{"web", "websecure"},
+					Rule:             "Host(`bar.localhost`) || Host(`baz.localhost`)",
+					SkipRedirections: true,
+				},
+			},
+			expectedRule: "HostRegexp(`{host:.+}`) && !(Host(`bar.localhost`) || Host(`baz.localhost`)) && !(Host(`foo.localhost`) && PathPrefix(`/api`))",
+		},
+		{
+			desc: "router skipping the redirections on another entry point",
+			routers: map[string]*dynamic.Router{
+				"foo@file": {
+					EntryPoints:      []string{"websecure"},
+					Rule:             "Host(`foo.localhost`)",
+					SkipRedirections: true,
+				},
+			},
+			expectedRule: "HostRegexp(`{host:.+}`)",
+		},
+		{
+			desc: "router skipping the redirections with an invalid rule",
+			routers: map[string]*dynamic.Router{
+				"foo@file": {
+					EntryPoints:      []string{"web"},
+					Rule:             "Host(`foo.localhost`",
+					SkipRedirections: true,
+				},
+			},
+			expectedRule: "HostRegexp(`{host:.+}`)",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			routers := map[string]*dynamic.Router{
+				"web-to-websecure@internal": {
+					EntryPoints: []string{"web"},
+					Middlewares: []string{"redirect-web-to-websecure"},
+					Service:     "noop@internal",
+					Rule:        "HostRegexp(`{host:.+}`)",
+				},
+			}
+			for name, rt := range test.routers {
+				routers[name] = rt
+			}
+
+			cfg := dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: routers,
+					Middlewares: map[string]*dynamic.Middleware{
+						"redirect-web-to-websecure@internal": {
+							RedirectScheme: &dynamic.RedirectScheme{Scheme: "https", Port: "443"},
+						},
+					},
+				},
+			}
+
+			actual := applySkipRedirections(cfg)
+
+			assert.Equal(t, test.expectedRule, actual.HTTP.Routers["web-to-websecure@internal"].Rule)
+
+			assert.NoError(t, checkRule(actual.HTTP.Routers["web-to-websecure@internal"].Rule))
+		})
+	}
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/eapache/channels"
 	"github.com/sirupsen/logrus"
+	"github.com/traefik/traefik/v2/pkg/api"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/provider"
 	"github.com/traefik/traefik/v2/pkg/safe"
@@ -28,8 +32,41 @@ type ConfigurationWatcher struct {
 	configurationValidatedChan chan dynamic.Message
 	providerConfigUpdateMap    map[string]chan dynamic.Message
 
+	// entryPointsUpdates receives the updates of the entry points, applied in the routine calling the listeners.
+	entryPointsUpdates chan entryPointsUpdate
+
 	requiredProvider       string
 	configurationListeners []func(dynamic.Configuration)
+	reloadListeners        []func(error)
+
+	// rejection is the error of the configuration being applied, if a listener rejected it.
+	rejection error
+	// lastAccepted is the last configuration accepted by the listeners, if any.
+	lastAccepted *dynamic.Configuration
+
+	lastRejectionMu sync.RWMutex
+	// lastRejection is the last rejected configuration, when it has not been superseded by an accepted one.
+	lastRejection *runtime.RejectedReload
+
+	// providersExpiry is the expiry configuration of the providers, by provider name.
+	providersExpiry     map[string]static.ProviderExpiry
+	expiryCheckInterval time.Duration
+
+	lastUpdatesMu sync.Mutex
+	// lastUpdates is the time of the last message received from each provider.
+	lastUpdates map[string]time.Time
+
+	staleProvidersMu sync.RWMutex
+	// staleProviders holds the time of the last update of the providers whose configuration is stale.
+	staleProviders map[string]time.Time
+	// removedProviders holds the providers whose configuration is removed because it expired.
+	removedProviders map[string]struct{}
+
+	historyMu   sync.RWMutex
+	historySize int
+	// history holds the last applied configurations, the oldest first.
+	history      []api.ConfigurationSnapshot
+	lastSnapshot int
 
 	routinesPool *safe.Pool
 }
@@ -41,16 +78,25 @@ func NewConfigurationWatcher(
 	providersThrottleDuration time.Duration,
 	defaultEntryPoints []string,
 	requiredProvider string,
+	providersExpiry map[string]static.ProviderExpiry,
+	historySize int,
 ) *ConfigurationWatcher {
 	watcher := &ConfigurationWatcher{
 		provider:                   pvd,
 		configurationChan:          make(chan dynamic.Message, 100),
 		configurationValidatedChan: make(chan dynamic.Message, 100),
 		providerConfigUpdateMap:    make(map[string]chan dynamic.Message),
+		entryPointsUpdates:         make(chan entryPointsUpdate, 10),
 		providersThrottleDuration:  providersThrottleDuration,
 		routinesPool:               routinesPool,
 		defaultEntryPoints:         defaultEntryPoints,
 		requiredProvider:           requiredProvider,
+		providersExpiry:            providersExpiry,
+		expiryCheckInterval:        time.Second,
+		lastUpdates:                make(map[string]time.Time),
+		staleProviders:             make(map[string]time.Time),
+		removedProviders:           make(map[string]struct{}),
+		historySize:                historySize,
 	}
 
 	currentConfigurations := make(dynamic.Configurations)
@@ -63,7 +109,7 @@ func NewConfigurationWatcher(
 func (c *ConfigurationWatcher) Start() {
 	c.routinesPool.GoCtx(c.listenProviders)
 	c.routinesPool.GoCtx(c.listenConfigurations)
-	c.startProvider()
+	c.startProvider(c.provider)
 }
 
 // Stop the configuration watcher.
@@ -80,16 +126,53 @@ func (c *ConfigurationWatcher) AddListener(listener func(dynamic.Configuration))
 	c.configurationListeners = append(c.configurationListeners, listener)
 }
 
-func (c *ConfigurationWatcher) startProvider() {
+// AddReloadListener adds a new listener function called once a new configuration is applied,
+// with the error of its rejection, if it is rejected.
+func (c *ConfigurationWatcher) AddReloadListener(listener func(error)) {
+	c.reloadListeners = append(c.reloadListeners, listener)
+}
+
+// Reject rejects the configuration being applied, and is meant to be called by the listeners.
+// The remaining listeners are skipped, and all the listeners are called again with the last accepted configuration, if any.
+func (c *ConfigurationWatcher) Reject(err error) {
+	c.rejection = err
+}
+
+// LastRejection returns the last rejected configuration, if no configuration has been accepted since.
+func (c *ConfigurationWatcher) LastRejection() *runtime.RejectedReload {
+	c.lastRejectionMu.RLock()
+	defer c.lastRejectionMu.RUnlock()
+
+	return c.lastRejection
+}
+
+// AddProvider starts a provider once the watcher is started,
+// its configurations being merged with the ones of the other providers.
+func (c *ConfigurationWatcher) AddProvider(pvd provider.Provider) {
+	c.startProvider(pvd)
+}
+
+// UpdateEntryPoints calls the update function, sets the default entry points, and applies the current configurations again,
+// in the routine calling the listeners, so that the update does not happen while a configuration is being applied.
+func (c *ConfigurationWatcher) UpdateEntryPoints(defaultEntryPoints []string, update func()) {
+	c.entryPointsUpdates <- entryPointsUpdate{defaultEntryPoints: defaultEntryPoints, update: update}
+}
+
+type entryPointsUpdate struct {
+	defaultEntryPoints []string
+	update             func()
+}
+
+func (c *ConfigurationWatcher) startProvider(pvd provider.Provider) {
 	logger := log.WithoutContext()
 
-	jsonConf, err := json.Marshal(c.provider)
+	jsonConf, err := json.Marshal(pvd)
 	if err != nil {
-		logger.Debugf("Unable to marshal provider configuration %T: %v", c.provider, err)
+		logger.Debugf("Unable to marshal provider configuration %T: %v", pvd, err)
 	}
 
-	logger.Infof("Starting provider %T %s", c.provider, jsonConf)
-	currentProvider := c.provider
+	logger.Infof("Starting provider %T %s", pvd, jsonConf)
+	currentProvider := pvd
 
 	safe.Go(func() {
 		err := currentProvider.Provide(c.configurationChan, c.routinesPool)
@@ -112,10 +195,18 @@ func (c *ConfigurationWatcher) listenProviders(ctx context.Context) {
 				return
 			}
 
+			c.lastUpdatesMu.Lock()
+			c.lastUpdates[configMsg.ProviderName] = time.Now()
+			c.lastUpdatesMu.Unlock()
+
+			provider.ReportRefresh(configMsg.ProviderName)
+
+			// A message without configuration is a keep-alive,
+			// sent by the providers whose configuration did not change.
 			if configMsg.Configuration == nil {
 				log.WithoutContext().WithField(log.ProviderName, configMsg.ProviderName).
 					Debug("Received nil configuration from provider, skipping.")
-				return
+				continue
 			}
 
 			c.preLoadConfiguration(configMsg)
@@ -124,6 +215,15 @@ func (c *ConfigurationWatcher) listenProviders(ctx context.Context) {
 }
 
 func (c *ConfigurationWatcher) listenConfigurations(ctx context.Context) {
+	// The expiry is checked in the same routine as the configurations are loaded,
+	// so that the listeners are never called concurrently.
+	var expiryCheck <-chan time.Time
+	if len(c.providersExpiry) > 0 {
+		ticker := time.NewTicker(c.expiryCheckInterval)
+		defer ticker.Stop()
+		expiryCheck = ticker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -133,6 +233,14 @@ func (c *ConfigurationWatcher) listenConfigurations(ctx context.Context) {
 				return
 			}
 			c.loadMessage(configMsg)
+		case now := <-expiryCheck:
+			if c.updateExpiry(now) {
+				c.applyConfigurations(c.currentConfigurations.Get().(dynamic.Configurations))
+			}
+		case update := <-c.entryPointsUpdates:
+			update.update()
+			c.defaultEntryPoints = update.defaultEntryPoints
+			c.applyConfigurations(c.currentConfigurations.Get().(dynamic.Configurations))
 		}
 	}
 }
@@ -146,15 +254,190 @@ func (c *ConfigurationWatcher) loadMessage(configMsg dynamic.Message) {
 
 	c.currentConfigurations.Set(newConfigurations)
 
-	conf := mergeConfiguration(newConfigurations, c.defaultEntryPoints)
+	if len(c.providersExpiry) > 0 {
+		c.updateExpiry(time.Now())
+	}
+
+	c.applyConfigurations(newConfigurations)
+}
+
+// applyConfigurations merges the configurations of the providers, except the removed ones, and calls the listeners.
+func (c *ConfigurationWatcher) applyConfigurations(configurations dynamic.Configurations) {
+	c.staleProvidersMu.RLock()
+	if len(c.removedProviders) > 0 {
+		activeConfigurations := make(dynamic.Configurations, len(configurations))
+		for name, configuration := range configurations {
+			if _, ok := c.removedProviders[name]; !ok {
+				activeConfigurations[name] = configuration
+			}
+		}
+		configurations = activeConfigurations
+	}
+	c.staleProvidersMu.RUnlock()
+
+	// The configurations are copied as the merge sets the default entry points on the routers,
+	// which can change when entry points are added.
+	conf := mergeConfiguration(configurations.DeepCopy(), c.defaultEntryPoints)
 	conf = applyModel(conf)
+	conf = applySkipRedirections(conf)
 
 	// We wait for first configuration of the require provider before applying configurations.
-	if _, ok := newConfigurations[c.requiredProvider]; c.requiredProvider == "" || ok {
-		for _, listener := range c.configurationListeners {
-			listener(conf)
+	if _, ok := configurations[c.requiredProvider]; c.requiredProvider == "" || ok {
+		c.applyConfiguration(conf)
+	}
+}
+
+// applyConfiguration calls the listeners with the configuration,
+// and calls them again with the last accepted configuration if one of them rejects it.
+func (c *ConfigurationWatcher) applyConfiguration(conf dynamic.Configuration) {
+	c.setLastRejection(nil)
+	c.rejection = nil
+
+	for _, listener := range c.configurationListeners {
+		listener(conf)
+
+		if c.rejection != nil {
+			break
 		}
 	}
+
+	err := c.rejection
+	c.rejection = nil
+
+	for _, listener := range c.reloadListeners {
+		listener(err)
+	}
+
+	if err == nil {
+		c.lastAccepted = &conf
+		c.addToHistory(conf)
+		return
+	}
+
+	log.WithoutContext().Errorf("Configuration rejected: %v", err)
+
+	c.setLastRejection(&runtime.RejectedReload{Date: time.Now().UTC(), Error: err.Error()})
+
+	if c.lastAccepted == nil {
+		return
+	}
+
+	log.WithoutContext().Info("Rolling back to the last accepted configuration")
+
+	for _, listener := range c.configurationListeners {
+		listener(*c.lastAccepted)
+	}
+
+	c.rejection = nil
+}
+
+func (c *ConfigurationWatcher) setLastRejection(rejection *runtime.RejectedReload) {
+	c.lastRejectionMu.Lock()
+	defer c.lastRejectionMu.Unlock()
+
+	c.lastRejection = rejection
+}
+
+// StaleProviders returns the providers whose configuration is stale, with the time of their last update.
+func (c *ConfigurationWatcher) StaleProviders() map[string]time.Time {
+	c.staleProvidersMu.RLock()
+	defer c.staleProvidersMu.RUnlock()
+
+	staleProviders := make(map[string]time.Time, len(c.staleProviders))
+	for name, lastUpdate := range c.staleProviders {
+		staleProviders[name] = lastUpdate
+	}
+
+	return staleProviders
+}
+
+// History returns the last applied configurations, the oldest first.
+func (c *ConfigurationWatcher) History() []api.ConfigurationSnapshot {
+	c.historyMu.RLock()
+	defer c.historyMu.RUnlock()
+
+	history := make([]api.ConfigurationSnapshot, len(c.history))
+	copy(history, c.history)
+
+	return history
+}
+
+// addToHistory records a copy of the applied configuration, and drops the oldest ones beyond the history size.
+func (c *ConfigurationWatcher) addToHistory(conf dynamic.Configuration) {
+	if c.historySize <= 0 {
+		return
+	}
+
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	c.lastSnapshot++
+	c.history = append(c.history, api.ConfigurationSnapshot{
+		ID:            c.lastSnapshot,
+		Date:          time.Now().UTC(),
+		Configuration: conf.DeepCopy(),
+	})
+
+	if len(c.history) > c.historySize {
+		c.history = c.history[len(c.history)-c.historySize:]
+	}
+}
+
+// updateExpiry computes the stale and removed providers at the given time,
+// and reports whether they changed.
+func (c *ConfigurationWatcher) updateExpiry(now time.Time) bool {
+	c.lastUpdatesMu.Lock()
+	lastUpdates := make(map[string]time.Time, len(c.lastUpdates))
+	for name, lastUpdate := range c.lastUpdates {
+		lastUpdates[name] = lastUpdate
+	}
+	c.lastUpdatesMu.Unlock()
+
+	staleProviders := make(map[string]time.Time)
+	removedProviders := make(map[string]struct{})
+
+	for name, expiry := range c.providersExpiry {
+		lastUpdate, ok := lastUpdates[name]
+		if !ok {
+			continue
+		}
+
+		elapsed := now.Sub(lastUpdate)
+
+		if expiry.RemoveAfter > 0 && elapsed >= time.Duration(expiry.RemoveAfter) {
+			removedProviders[name] = struct{}{}
+			continue
+		}
+
+		if expiry.StaleAfter > 0 && elapsed >= time.Duration(expiry.StaleAfter) {
+			staleProviders[name] = lastUpdate
+		}
+	}
+
+	c.staleProvidersMu.Lock()
+	defer c.staleProvidersMu.Unlock()
+
+	if reflect.DeepEqual(staleProviders, c.staleProviders) && reflect.DeepEqual(removedProviders, c.removedProviders) {
+		return false
+	}
+
+	for name := range staleProviders {
+		if _, ok := c.staleProviders[name]; !ok {
+			log.WithoutContext().WithField(log.ProviderName, name).
+				Warnf("No update received from provider %s since %s, marking its configuration as stale", name, staleProviders[name].Format(time.RFC3339))
+		}
+	}
+	for name := range removedProviders {
+		if _, ok := c.removedProviders[name]; !ok {
+			log.WithoutContext().WithField(log.ProviderName, name).
+				Warnf("No update received from provider %s since %s, removing its configuration", name, lastUpdates[name].Format(time.RFC3339))
+		}
+	}
+
+	c.staleProviders = staleProviders
+	c.removedProviders = removedProviders
+
+	return true
 }
 
 func (c *ConfigurationWatcher) preLoadConfiguration(configMsg dynamic.Message) {
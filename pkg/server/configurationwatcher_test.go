@@ -2,13 +2,18 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	th "github.com/traefik/traefik/v2/pkg/testhelpers"
 	"github.com/traefik/traefik/v2/pkg/tls"
@@ -55,7 +60,7 @@ func TestNewConfigurationWatcher(t *testing.T) {
 		}},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{}, "", nil, 0)
 
 	run := make(chan struct{})
 
@@ -113,7 +118,7 @@ func TestListenProvidersThrottleProviderConfigReload(t *testing.T) {
 		})
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "", nil, 0)
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(_ dynamic.Configuration) {
@@ -137,7 +142,7 @@ func TestListenProvidersSkipsEmptyConfigs(t *testing.T) {
 		messages: []dynamic.Message{{ProviderName: "mock"}},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, time.Second, []string{}, "", nil, 0)
 	watcher.AddListener(func(_ dynamic.Configuration) {
 		t.Error("An empty configuration was published but it should not")
 	})
@@ -163,7 +168,7 @@ func TestListenProvidersSkipsSameConfigurationForProvider(t *testing.T) {
 		messages: []dynamic.Message{message, message},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{}, "", nil, 0)
 
 	alreadyCalled := false
 	watcher.AddListener(func(_ dynamic.Configuration) {
@@ -206,7 +211,7 @@ func TestListenProvidersDoesNotSkipFlappingConfiguration(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 15*time.Millisecond, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 15*time.Millisecond, []string{"defaultEP"}, "", nil, 0)
 
 	var lastConfig dynamic.Configuration
 	watcher.AddListener(func(conf dynamic.Configuration) {
@@ -262,7 +267,7 @@ func TestListenProvidersPublishesConfigForEachProvider(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{"defaultEP"}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{"defaultEP"}, "", nil, 0)
 
 	var publishedProviderConfig dynamic.Configuration
 
@@ -330,7 +335,7 @@ func TestPublishConfigUpdatedByProvider(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "", nil, 0)
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(configuration dynamic.Configuration) {
@@ -378,7 +383,7 @@ func TestPublishConfigUpdatedByConfigWatcherListener(t *testing.T) {
 		},
 	}
 
-	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "")
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 30*time.Millisecond, []string{}, "", nil, 0)
 
 	publishedConfigCount := 0
 	watcher.AddListener(func(configuration dynamic.Configuration) {
@@ -397,3 +402,257 @@ func TestPublishConfigUpdatedByConfigWatcherListener(t *testing.T) {
 
 	assert.Equal(t, 1, publishedConfigCount)
 }
+
+func TestProvidersExpiry(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	pvd := &mockProvider{
+		messages: []dynamic.Message{{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(
+						th.WithRouter("test",
+							th.WithEntryPoints("e"),
+							th.WithServiceName("scv"))),
+				),
+			},
+		}},
+	}
+
+	expiry := map[string]static.ProviderExpiry{
+		"mock": {
+			StaleAfter:  ptypes.Duration(100 * time.Millisecond),
+			RemoveAfter: ptypes.Duration(300 * time.Millisecond),
+		},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{}, "", expiry, 0)
+	watcher.expiryCheckInterval = 10 * time.Millisecond
+
+	var mu sync.Mutex
+	var routers []string
+	var staleProviders []string
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		routers = nil
+		for name := range conf.HTTP.Routers {
+			routers = append(routers, name)
+		}
+
+		staleProviders = nil
+		for name := range watcher.StaleProviders() {
+			staleProviders = append(staleProviders, name)
+		}
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, []string{"test@mock"}, routers)
+	assert.Empty(t, staleProviders)
+	mu.Unlock()
+
+	time.Sleep(150 * time.Millisecond)
+
+	mu.Lock()
+	assert.Equal(t, []string{"test@mock"}, routers)
+	assert.Equal(t, []string{"mock"}, staleProviders)
+	mu.Unlock()
+
+	time.Sleep(200 * time.Millisecond)
+
+	mu.Lock()
+	assert.Empty(t, routers)
+	assert.Empty(t, staleProviders)
+	mu.Unlock()
+
+	// A keep-alive from the provider restores its configuration.
+	watcher.configurationChan <- dynamic.Message{ProviderName: "mock"}
+
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"test@mock"}, routers)
+	assert.Empty(t, staleProviders)
+}
+
+func TestConfigurationHistory(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	var messages []dynamic.Message
+	for i := 0; i < 3; i++ {
+		messages = append(messages, dynamic.Message{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(
+						th.WithRouter("test"+strconv.Itoa(i),
+							th.WithEntryPoints("e"),
+							th.WithServiceName("scv"))),
+				),
+			},
+		})
+	}
+
+	pvd := &mockProvider{messages: messages}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{}, "", nil, 2)
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	// Wait for the configurations to be applied.
+	time.Sleep(100 * time.Millisecond)
+
+	history := watcher.History()
+	require.Len(t, history, 2)
+
+	assert.Equal(t, 2, history[0].ID)
+	assert.Contains(t, history[0].Configuration.HTTP.Routers, "test1@mock")
+
+	assert.Equal(t, 3, history[1].ID)
+	assert.Contains(t, history[1].Configuration.HTTP.Routers, "test2@mock")
+	assert.False(t, history[1].Date.Before(history[0].Date))
+}
+
+func TestConfigurationRollback(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	var messages []dynamic.Message
+	for i := 0; i < 3; i++ {
+		messages = append(messages, dynamic.Message{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(
+						th.WithRouter("test"+strconv.Itoa(i),
+							th.WithEntryPoints("e"),
+							th.WithServiceName("scv"))),
+				),
+			},
+		})
+	}
+
+	pvd := &mockProvider{messages: messages}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{}, "", nil, 3)
+
+	var mu sync.Mutex
+	var applied, skipped []string
+	var rejections []bool
+	var reloadErrors []error
+
+	routerName := func(conf dynamic.Configuration) string {
+		for name := range conf.HTTP.Routers {
+			return name
+		}
+		return ""
+	}
+
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		name := routerName(conf)
+		applied = append(applied, name)
+		rejections = append(rejections, watcher.LastRejection() != nil)
+
+		if name == "test1@mock" {
+			watcher.Reject(errors.New("router test1@mock failing"))
+		}
+	})
+
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		skipped = append(skipped, routerName(conf))
+	})
+
+	watcher.AddReloadListener(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		reloadErrors = append(reloadErrors, err)
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	// Wait for the configurations to be applied.
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, []string{"test0@mock", "test1@mock", "test0@mock", "test2@mock"}, applied)
+	assert.Equal(t, []bool{false, false, true, false}, rejections)
+	assert.Equal(t, []string{"test0@mock", "test0@mock", "test2@mock"}, skipped)
+
+	require.Len(t, reloadErrors, 3)
+	assert.NoError(t, reloadErrors[0])
+	assert.EqualError(t, reloadErrors[1], "router test1@mock failing")
+	assert.NoError(t, reloadErrors[2])
+
+	assert.Nil(t, watcher.LastRejection())
+
+	// The rejected configuration is not part of the history.
+	history := watcher.History()
+	require.Len(t, history, 2)
+	assert.Contains(t, history[0].Configuration.HTTP.Routers, "test0@mock")
+	assert.Contains(t, history[1].Configuration.HTTP.Routers, "test2@mock")
+}
+
+func TestUpdateEntryPoints(t *testing.T) {
+	routinesPool := safe.NewPool(context.Background())
+
+	pvd := &mockProvider{
+		messages: []dynamic.Message{{
+			ProviderName: "mock",
+			Configuration: &dynamic.Configuration{
+				HTTP: th.BuildConfiguration(
+					th.WithRouters(th.WithRouter("test", th.WithServiceName("scv"))),
+				),
+			},
+		}},
+	}
+
+	watcher := NewConfigurationWatcher(routinesPool, pvd, 0, []string{"web"}, "", nil, 0)
+
+	entryPoints := make(chan []string, 2)
+	watcher.AddListener(func(conf dynamic.Configuration) {
+		entryPoints <- conf.HTTP.Routers["test@mock"].EntryPoints
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	select {
+	case eps := <-entryPoints:
+		assert.Equal(t, []string{"web"}, eps)
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for the configuration")
+	}
+
+	var updated bool
+	watcher.UpdateEntryPoints([]string{"web", "websecure"}, func() {
+		updated = true
+	})
+
+	select {
+	case eps := <-entryPoints:
+		assert.Equal(t, []string{"web", "websecure"}, eps)
+	case <-time.After(time.Second):
+		t.Fatal("timeout while waiting for the configuration")
+	}
+
+	// The update is called before the configuration is applied again.
+	assert.True(t, updated)
+}
@@ -2,15 +2,20 @@ package middleware
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
 	metricsmiddleware "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/operation"
 	"github.com/traefik/traefik/v2/pkg/middlewares/requestdecorator"
 	mTracing "github.com/traefik/traefik/v2/pkg/middlewares/tracing"
+	"github.com/traefik/traefik/v2/pkg/middlewares/watchdog"
 	"github.com/traefik/traefik/v2/pkg/tracing"
 	"github.com/traefik/traefik/v2/pkg/tracing/jaeger"
 )
@@ -21,23 +26,72 @@ type ChainBuilder struct {
 	accessLoggerMiddleware *accesslog.Handler
 	tracer                 *tracing.Tracing
 	requestDecorator       *requestdecorator.RequestDecorator
+	// classifier classifies the requests into operations for the metrics and the tracing, if any.
+	classifier *operation.Classifier
+	// observability is the observability configuration, by entry point.
+	observability map[string]*static.ObservabilityConfig
+	// watchdog tracks the requests of the entry points detecting the slow requests, if any.
+	watchdog *watchdog.Watchdog
 }
 
 // NewChainBuilder Creates a new ChainBuilder.
-func NewChainBuilder(staticConfiguration static.Configuration, metricsRegistry metrics.Registry, accessLoggerMiddleware *accesslog.Handler) *ChainBuilder {
-	return &ChainBuilder{
+func NewChainBuilder(staticConfiguration static.Configuration, metricsRegistry metrics.Registry, accessLoggerMiddleware *accesslog.Handler) (*ChainBuilder, error) {
+	var classifier *operation.Classifier
+	if staticConfiguration.Metrics != nil && len(staticConfiguration.Metrics.Operations) > 0 {
+		var err error
+		classifier, err = operation.New(staticConfiguration.Metrics.Operations)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the operations classifier: %w", err)
+		}
+	}
+
+	observability := make(map[string]*static.ObservabilityConfig)
+	slowRequests := false
+	for name, entryPoint := range staticConfiguration.EntryPoints {
+		if entryPoint != nil && entryPoint.Observability != nil {
+			observability[name] = entryPoint.Observability
+			slowRequests = slowRequests || entryPoint.Observability.SlowRequests != nil
+		}
+	}
+
+	builder := &ChainBuilder{
 		metricsRegistry:        metricsRegistry,
 		accessLoggerMiddleware: accessLoggerMiddleware,
 		tracer:                 setupTracing(staticConfiguration.Tracing),
 		requestDecorator:       requestdecorator.New(staticConfiguration.HostResolver),
+		classifier:             classifier,
+		observability:          observability,
+	}
+
+	if slowRequests {
+		var slowReqsCounter gokitmetrics.Counter
+		if metricsRegistry != nil {
+			slowReqsCounter = metricsRegistry.EntryPointSlowReqsCounter()
+		}
+
+		builder.watchdog = watchdog.New(slowReqsCounter)
+		builder.watchdog.Start()
 	}
+
+	return builder, nil
 }
 
 // Build a middleware chain by entry point.
 func (c *ChainBuilder) Build(ctx context.Context, entryPointName string) alice.Chain {
 	chain := alice.New()
 
-	if c.accessLoggerMiddleware != nil {
+	if c.classifier != nil {
+		chain = chain.Append(operation.WrapHandler(c.classifier))
+	}
+
+	// The entry points without observability configuration have the access logs and the metrics enabled.
+	observability := c.observability[entryPointName]
+
+	if c.watchdog != nil && observability != nil && observability.SlowRequests != nil {
+		chain = chain.Append(c.watchdog.WrapEntryPointHandler(entryPointName, time.Duration(observability.SlowRequests.Threshold)))
+	}
+
+	if c.accessLoggerMiddleware != nil && (observability == nil || observability.AccessLogs) {
 		chain = chain.Append(accesslog.WrapHandler(c.accessLoggerMiddleware))
 	}
 
@@ -45,15 +99,19 @@ func (c *ChainBuilder) Build(ctx context.Context, entryPointName string) alice.C
 		chain = chain.Append(mTracing.WrapEntryPointHandler(ctx, c.tracer, entryPointName))
 	}
 
-	if c.metricsRegistry != nil && c.metricsRegistry.IsEpEnabled() {
+	if c.metricsRegistry != nil && c.metricsRegistry.IsEpEnabled() && (observability == nil || observability.Metrics) {
 		chain = chain.Append(metricsmiddleware.WrapEntryPointHandler(ctx, c.metricsRegistry, entryPointName))
 	}
 
 	return chain.Append(requestdecorator.WrapHandler(c.requestDecorator))
 }
 
-// Close accessLogger and tracer.
+// Close accessLogger, tracer, and watchdog.
 func (c *ChainBuilder) Close() {
+	if c.watchdog != nil {
+		c.watchdog.Stop()
+	}
+
 	if c.accessLoggerMiddleware != nil {
 		if err := c.accessLoggerMiddleware.Close(); err != nil {
 			log.WithoutContext().Errorf("Could not close the access log file: %s", err)
@@ -123,7 +181,7 @@ func setupTracing(conf *static.Tracing) *tracing.Tracing {
 		backend = defaultBackend
 	}
 
-	tracer, err := tracing.NewTracing(conf.ServiceName, conf.SpanNameLimit, backend)
+	tracer, err := tracing.NewTracing(conf.ServiceName, conf.SpanNameLimit, backend, conf.SpanAttributes)
 	if err != nil {
 		log.WithoutContext().Warnf("Unable to create tracer: %v", err)
 		return nil
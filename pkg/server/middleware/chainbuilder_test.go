@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v2/pkg/types"
+)
+
+func TestChainBuilder_Build_accessLogs(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		observability *static.ObservabilityConfig
+		expectedLog   bool
+	}{
+		{
+			desc:        "without observability configuration",
+			expectedLog: true,
+		},
+		{
+			desc:          "access logs enabled",
+			observability: &static.ObservabilityConfig{AccessLogs: true},
+			expectedLog:   true,
+		},
+		{
+			desc:          "access logs disabled",
+			observability: &static.ObservabilityConfig{AccessLogs: false, Metrics: true},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := filepath.Join(t.TempDir(), "access.log")
+
+			accessLogger, err := accesslog.NewHandler(&types.AccessLog{FilePath: filePath, Format: accesslog.CommonFormat})
+			require.NoError(t, err)
+
+			staticConfig := static.Configuration{
+				EntryPoints: static.EntryPoints{
+					"web": {Address: ":80", Observability: test.observability},
+				},
+			}
+
+			builder, err := NewChainBuilder(staticConfig, nil, accessLogger)
+			require.NoError(t, err)
+
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+			handler, err := builder.Build(context.Background(), "web").Then(next)
+			require.NoError(t, err)
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://foo.localhost/", nil))
+
+			builder.Close()
+
+			logs, err := os.ReadFile(filePath)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedLog, len(logs) > 0)
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/containous/alice"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/acceptlanguage"
 	"github.com/traefik/traefik/v2/pkg/middlewares/addprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/auth"
 	"github.com/traefik/traefik/v2/pkg/middlewares/buffering"
@@ -16,7 +18,10 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/circuitbreaker"
 	"github.com/traefik/traefik/v2/pkg/middlewares/compress"
 	"github.com/traefik/traefik/v2/pkg/middlewares/customerrors"
+	"github.com/traefik/traefik/v2/pkg/middlewares/fallback"
+	"github.com/traefik/traefik/v2/pkg/middlewares/geoip"
 	"github.com/traefik/traefik/v2/pkg/middlewares/headers"
+	"github.com/traefik/traefik/v2/pkg/middlewares/headerslimit"
 	"github.com/traefik/traefik/v2/pkg/middlewares/inflightreq"
 	"github.com/traefik/traefik/v2/pkg/middlewares/ipwhitelist"
 	"github.com/traefik/traefik/v2/pkg/middlewares/passtlsclientcert"
@@ -24,11 +29,16 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/redirect"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepath"
 	"github.com/traefik/traefik/v2/pkg/middlewares/replacepathregex"
+	"github.com/traefik/traefik/v2/pkg/middlewares/requestid"
 	"github.com/traefik/traefik/v2/pkg/middlewares/retry"
+	"github.com/traefik/traefik/v2/pkg/middlewares/rewritebody"
+	"github.com/traefik/traefik/v2/pkg/middlewares/signedurl"
+	"github.com/traefik/traefik/v2/pkg/middlewares/staticresponse"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefix"
 	"github.com/traefik/traefik/v2/pkg/middlewares/stripprefixregex"
 	"github.com/traefik/traefik/v2/pkg/middlewares/tracing"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/types"
 )
 
 type middlewareStackType int
@@ -39,9 +49,12 @@ const (
 
 // Builder the middleware builder.
 type Builder struct {
-	configs        map[string]*runtime.MiddlewareInfo
-	pluginBuilder  PluginsBuilder
-	serviceBuilder serviceBuilder
+	configs            map[string]*runtime.MiddlewareInfo
+	pluginBuilder      PluginsBuilder
+	serviceBuilder     serviceBuilder
+	forwardAuthProxy   *types.Proxy
+	sourceRangeSources *types.SourceRangeSources
+	metricsRegistry    metrics.Registry
 }
 
 type serviceBuilder interface {
@@ -49,8 +62,13 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder}
+// A nil metrics registry disables the metrics of the middlewares.
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder, forwardAuthProxy *types.Proxy, sourceRangeSources *types.SourceRangeSources, metricsRegistry metrics.Registry) *Builder {
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder, forwardAuthProxy: forwardAuthProxy, sourceRangeSources: sourceRangeSources, metricsRegistry: metricsRegistry}
 }
 
 // BuildChain creates a middleware chain.
@@ -110,8 +128,18 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 	var middleware alice.Constructor
 	badConf := errors.New("cannot create middleware: multi-types middleware not supported, consider declaring two different pieces of middleware instead")
 
+	// AcceptLanguage
+	if config.AcceptLanguage != nil {
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return acceptlanguage.New(ctx, next, *config.AcceptLanguage, middlewareName)
+		}
+	}
+
 	// AddPrefix
 	if config.AddPrefix != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
 		middleware = func(next http.Handler) (http.Handler, error) {
 			return addprefix.New(ctx, next, *config.AddPrefix, middlewareName)
 		}
@@ -208,13 +236,23 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Fallback
+	if config.Fallback != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return fallback.New(ctx, next, *config.Fallback, b.serviceBuilder, middlewareName)
+		}
+	}
+
 	// ForwardAuth
 	if config.ForwardAuth != nil {
 		if middleware != nil {
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return auth.NewForward(ctx, next, *config.ForwardAuth, middlewareName)
+			return auth.NewForward(ctx, next, *config.ForwardAuth, middlewareName, b.forwardAuthProxy)
 		}
 	}
 
@@ -228,13 +266,23 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// HeadersLimit
+	if config.HeadersLimit != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return headerslimit.New(ctx, next, *config.HeadersLimit, b.metricsRegistry.MiddlewareRejectedReqsCounter(), middlewareName)
+		}
+	}
+
 	// IPWhiteList
 	if config.IPWhiteList != nil {
 		if middleware != nil {
 			return nil, badConf
 		}
 		middleware = func(next http.Handler) (http.Handler, error) {
-			return ipwhitelist.New(ctx, next, *config.IPWhiteList, middlewareName)
+			return ipwhitelist.New(ctx, next, *config.IPWhiteList, middlewareName, b.sourceRangeSources)
 		}
 	}
 
@@ -248,6 +296,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// JWT
+	if config.JWT != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return auth.NewJWT(ctx, next, *config.JWT, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -308,6 +366,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// RequestID
+	if config.RequestID != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return requestid.New(ctx, next, *config.RequestID, middlewareName)
+		}
+	}
+
+	// RewriteBody
+	if config.RewriteBody != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return rewritebody.New(ctx, next, *config.RewriteBody, middlewareName)
+		}
+	}
+
+	// GeoIP
+	if config.GeoIP != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return geoip.New(ctx, next, *config.GeoIP, b.metricsRegistry.MiddlewareRejectedReqsCounter(), middlewareName)
+		}
+	}
+
 	// Retry
 	if config.Retry != nil {
 		if middleware != nil {
@@ -319,6 +407,26 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// SignedURL
+	if config.SignedURL != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return signedurl.New(ctx, next, *config.SignedURL, middlewareName)
+		}
+	}
+
+	// StaticResponse
+	if config.StaticResponse != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return staticresponse.New(ctx, next, *config.StaticResponse, middlewareName)
+		}
+	}
+
 	// StripPrefix
 	if config.StripPrefix != nil {
 		if middleware != nil {
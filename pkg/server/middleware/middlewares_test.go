@@ -18,7 +18,7 @@ func TestBuilder_BuildChainNilConfig(t *testing.T) {
 	testConfig := map[string]*runtime.MiddlewareInfo{
 		"empty": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil, nil, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -29,7 +29,7 @@ func TestBuilder_BuildChainNonExistentChain(t *testing.T) {
 	testConfig := map[string]*runtime.MiddlewareInfo{
 		"foobar": {},
 	}
-	middlewaresBuilder := NewBuilder(testConfig, nil, nil)
+	middlewaresBuilder := NewBuilder(testConfig, nil, nil, nil, nil, nil)
 
 	chain := middlewaresBuilder.BuildChain(context.Background(), []string{"empty"})
 	_, err := chain.Then(nil)
@@ -270,7 +270,7 @@ func TestBuilder_BuildChainWithContext(t *testing.T) {
 					Middlewares: test.configuration,
 				},
 			})
-			builder := NewBuilder(rtConf.Middlewares, nil, nil)
+			builder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil, nil)
 
 			result := builder.BuildChain(ctx, test.buildChain)
 
@@ -329,7 +329,7 @@ func TestBuilder_buildConstructor(t *testing.T) {
 			Middlewares: testConfig,
 		},
 	})
-	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil)
+	middlewaresBuilder := NewBuilder(rtConf.Middlewares, nil, nil, nil, nil, nil)
 
 	testCases := []struct {
 		desc          string
@@ -2,11 +2,15 @@ package tcpmiddleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	inflightconn "github.com/traefik/traefik/v2/pkg/middlewares/tcp/inflightconn"
 	ipwhitelist "github.com/traefik/traefik/v2/pkg/middlewares/tcp/ipwhitelist"
+	ratelimit "github.com/traefik/traefik/v2/pkg/middlewares/tcp/ratelimit"
+	snilimit "github.com/traefik/traefik/v2/pkg/middlewares/tcp/snilimit"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 )
@@ -85,6 +89,7 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 	}
 
 	var middleware tcp.Constructor
+	badConf := errors.New("cannot create middleware: multi-types middleware not supported, consider declaring two different pieces of middleware instead")
 
 	// IPWhiteList
 	if config.IPWhiteList != nil {
@@ -93,6 +98,36 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// SNILimit
+	if config.SNILimit != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return snilimit.New(ctx, next, *config.SNILimit, middlewareName)
+		}
+	}
+
+	// InFlightConn
+	if config.InFlightConn != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return inflightconn.New(ctx, next, *config.InFlightConn, middlewareName)
+		}
+	}
+
+	// RateLimit
+	if config.RateLimit != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return ratelimit.New(ctx, next, *config.RateLimit, middlewareName)
+		}
+	}
+
 	if middleware == nil {
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
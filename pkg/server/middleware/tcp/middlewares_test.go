@@ -0,0 +1,71 @@
+package tcpmiddleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+func TestBuilder_buildConstructor(t *testing.T) {
+	testConfig := map[string]*dynamic.TCPMiddleware{
+		"inflight": {
+			InFlightConn: &dynamic.TCPInFlightConn{Amount: 10},
+		},
+		"multi-types": {
+			InFlightConn: &dynamic.TCPInFlightConn{Amount: 10},
+			SNILimit:     &dynamic.TCPSNILimit{MaxConnections: 10},
+		},
+		"empty": {},
+	}
+
+	rtConf := runtime.NewConfig(dynamic.Configuration{
+		TCP: &dynamic.TCPConfiguration{
+			Middlewares: testConfig,
+		},
+	})
+	middlewaresBuilder := NewBuilder(rtConf.TCPMiddlewares)
+
+	testCases := []struct {
+		desc          string
+		middlewareID  string
+		expectedError bool
+	}{
+		{
+			desc:         "Should create an InFlightConn middleware",
+			middlewareID: "inflight",
+		},
+		{
+			desc:          "Should not create a middleware with several types",
+			middlewareID:  "multi-types",
+			expectedError: true,
+		},
+		{
+			desc:          "Should not create a middleware without type",
+			middlewareID:  "empty",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			constructor, err := middlewaresBuilder.buildConstructor(context.Background(), test.middlewareID)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			middleware, err := constructor(tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			require.NoError(t, err)
+			assert.NotNil(t, middleware)
+		})
+	}
+}
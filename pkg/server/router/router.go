@@ -6,13 +6,17 @@ import (
 	"net/http"
 
 	"github.com/containous/alice"
+	"github.com/traefik/traefik/v2/pkg/accounting"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/accesslog"
+	accountingMiddle "github.com/traefik/traefik/v2/pkg/middlewares/accounting"
 	metricsMiddle "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/recovery"
+	"github.com/traefik/traefik/v2/pkg/middlewares/tlsclientauth"
 	"github.com/traefik/traefik/v2/pkg/middlewares/tracing"
+	"github.com/traefik/traefik/v2/pkg/middlewares/watchdog"
 	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
@@ -32,20 +36,25 @@ type Manager struct {
 	routerHandlers     map[string]http.Handler
 	serviceManager     serviceManager
 	metricsRegistry    metrics.Registry
+	accountant         *accounting.Accountant
 	middlewaresBuilder middlewareBuilder
 	chainBuilder       *middleware.ChainBuilder
 	conf               *runtime.Configuration
+	// priorityStrategies are the strategies computing the priority of the routers without an explicit one, by entry point.
+	priorityStrategies map[string]string
 }
 
 // NewManager Creates a new Manager.
-func NewManager(conf *runtime.Configuration, serviceManager serviceManager, middlewaresBuilder middlewareBuilder, chainBuilder *middleware.ChainBuilder, metricsRegistry metrics.Registry) *Manager {
+func NewManager(conf *runtime.Configuration, serviceManager serviceManager, middlewaresBuilder middlewareBuilder, chainBuilder *middleware.ChainBuilder, metricsRegistry metrics.Registry, accountant *accounting.Accountant, priorityStrategies map[string]string) *Manager {
 	return &Manager{
 		routerHandlers:     make(map[string]http.Handler),
 		serviceManager:     serviceManager,
 		metricsRegistry:    metricsRegistry,
+		accountant:         accountant,
 		middlewaresBuilder: middlewaresBuilder,
 		chainBuilder:       chainBuilder,
 		conf:               conf,
+		priorityStrategies: priorityStrategies,
 	}
 }
 
@@ -65,7 +74,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 		entryPointName := entryPointName
 		ctx := log.With(rootCtx, log.Str(log.EntryPointName, entryPointName))
 
-		handler, err := m.buildEntryPointHandler(ctx, routers)
+		handler, err := m.buildEntryPointHandler(ctx, routers, m.priorityStrategies[entryPointName])
 		if err != nil {
 			log.FromContext(ctx).Error(err)
 			continue
@@ -101,13 +110,32 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.RouterInfo) (http.Handler, error) {
+func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.RouterInfo, priorityStrategy string) (http.Handler, error) {
 	router, err := rules.NewRouter()
 	if err != nil {
 		return nil, err
 	}
 
+	var routes []rules.Route
 	for routerName, routerConfig := range configs {
+		priority, err := rules.GetPriority(routerConfig.Rule, routerConfig.Priority, priorityStrategy)
+		if err != nil {
+			ctxRouter := log.With(provider.AddInContext(ctx, routerName), log.Str(log.RouterName, routerName))
+			routerConfig.AddError(err, true)
+			log.FromContext(ctxRouter).Error(err)
+			continue
+		}
+
+		routes = append(routes, rules.Route{Name: routerName, Rule: routerConfig.Rule, Priority: priority})
+	}
+
+	// The routes are added in evaluation order, instead of being sorted afterwards,
+	// so that the order of the routes with the same priority is deterministic.
+	rules.SortRoutes(routes)
+
+	for _, route := range routes {
+		routerName, routerConfig := route.Name, configs[route.Name]
+
 		ctxRouter := log.With(provider.AddInContext(ctx, routerName), log.Str(log.RouterName, routerName))
 		logger := log.FromContext(ctxRouter)
 
@@ -118,15 +146,20 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		err = router.AddRoute(routerConfig.Rule, routerConfig.Priority, handler)
+		strategy, err := routerConfig.IPStrategy.Get()
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
 			continue
 		}
-	}
 
-	router.SortRoutes()
+		err = router.AddRouteWithIPStrategy(route.Rule, route.Priority, strategy, handler)
+		if err != nil {
+			routerConfig.AddError(err, true)
+			logger.Error(err)
+			continue
+		}
+	}
 
 	chain := alice.New()
 	chain = chain.Append(func(next http.Handler) (http.Handler, error) {
@@ -148,7 +181,7 @@ func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, rou
 
 	handlerWithAccessLog, err := alice.New(func(next http.Handler) (http.Handler, error) {
 		return accesslog.NewFieldHandler(next, accesslog.RouterName, routerName, nil), nil
-	}).Then(handler)
+	}, watchdog.WrapRouterHandler(routerName)).Then(handler)
 	if err != nil {
 		log.FromContext(ctx).Error(err)
 		m.routerHandlers[routerName] = handler
@@ -187,6 +220,17 @@ func (m *Manager) buildHTTPHandler(ctx context.Context, router *runtime.RouterIn
 		chain = chain.Append(metricsMiddle.WrapRouterHandler(ctx, m.metricsRegistry, routerName, router.Service))
 	}
 
+	if m.accountant != nil {
+		chain = chain.Append(accountingMiddle.WrapRouterHandler(ctx, m.accountant, routerName, router.Service))
+	}
+
+	if router.TLS != nil && router.TLS.ClientAuth != nil {
+		clientAuth := *router.TLS.ClientAuth
+		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
+			return tlsclientauth.New(ctx, next, clientAuth, routerName)
+		})
+	}
+
 	return chain.Extend(*mHandler).Append(tHandler).Then(sHandler)
 }
 
@@ -314,10 +314,11 @@ func TestRouterManager_Get(t *testing.T) {
 			roundTripperManager := service.NewRoundTripperManager()
 			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
-			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil, nil)
+			chainBuilder, err := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+			require.NoError(t, err)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry())
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), nil, nil)
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -420,10 +421,11 @@ func TestAccessLog(t *testing.T) {
 			roundTripperManager := service.NewRoundTripperManager()
 			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
-			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil, nil)
+			chainBuilder, err := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+			require.NoError(t, err)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry())
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), nil, nil)
 
 			handlers := routerManager.BuildHandlers(context.Background(), test.entryPoints, false)
 
@@ -709,10 +711,11 @@ func TestRuntimeConfiguration(t *testing.T) {
 			roundTripperManager := service.NewRoundTripperManager()
 			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
-			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
-			chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil, nil)
+			chainBuilder, err := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+			require.NoError(t, err)
 
-			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry())
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), nil, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -792,10 +795,11 @@ func TestProviderOnMiddlewares(t *testing.T) {
 	roundTripperManager := service.NewRoundTripperManager()
 	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
 	serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
-	chainBuilder := middleware.NewChainBuilder(staticCfg, nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil, nil)
+	chainBuilder, err := middleware.NewChainBuilder(staticCfg, nil, nil)
+	require.NoError(t, err)
 
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry())
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), nil, nil)
 
 	_ = routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -805,6 +809,90 @@ func TestProviderOnMiddlewares(t *testing.T) {
 	assert.Equal(t, []string{"m1@docker", "m2@docker", "m1@file"}, rtConf.Middlewares["chain@docker"].Chain.Middlewares)
 }
 
+func TestPriorityStrategy(t *testing.T) {
+	hostServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("host"))
+	}))
+	t.Cleanup(hostServer.Close)
+
+	prefixServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("prefix"))
+	}))
+	t.Cleanup(prefixServer.Close)
+
+	testCases := []struct {
+		desc         string
+		strategy     string
+		expectedBody string
+	}{
+		{
+			desc:         "length strategy",
+			expectedBody: "prefix",
+		},
+		{
+			desc:         "specificity strategy",
+			strategy:     "specificity",
+			expectedBody: "host",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			rtConf := runtime.NewConfig(dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Services: map[string]*dynamic.Service{
+						"host@file": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{{URL: hostServer.URL}},
+							},
+						},
+						"prefix@file": {
+							LoadBalancer: &dynamic.ServersLoadBalancer{
+								Servers: []dynamic.Server{{URL: prefixServer.URL}},
+							},
+						},
+					},
+					Routers: map[string]*dynamic.Router{
+						"host@file": {
+							EntryPoints: []string{"web"},
+							Rule:        "Host(`foo.bar`)",
+							Service:     "host@file",
+						},
+						"prefix@file": {
+							EntryPoints: []string{"web"},
+							Rule:        "PathPrefix(`/some/long/path/prefix`)",
+							Service:     "prefix@file",
+						},
+					},
+				},
+			})
+
+			roundTripperManager := service.NewRoundTripperManager()
+			roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+			serviceManager := service.NewManager(rtConf.Services, nil, nil, roundTripperManager)
+			middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil, nil)
+			chainBuilder, err := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+			require.NoError(t, err)
+
+			routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), nil, map[string]string{"web": test.strategy})
+
+			handlers := routerManager.BuildHandlers(context.Background(), []string{"web"}, false)
+
+			w := httptest.NewRecorder()
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://foo.bar/some/long/path/prefix", nil)
+
+			reqHost := requestdecorator.New(nil)
+			reqHost.ServeHTTP(w, req, handlers["web"].ServeHTTP)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
+
 type staticRoundTripperGetter struct {
 	res *http.Response
 }
@@ -813,6 +901,10 @@ func (s staticRoundTripperGetter) Get(name string) (http.RoundTripper, error) {
 	return &staticTransport{res: s.res}, nil
 }
 
+func (s staticRoundTripperGetter) GetHTTP2Pool(name string, size int) (http.RoundTripper, error) {
+	return &staticTransport{res: s.res}, nil
+}
+
 type staticTransport struct {
 	res *http.Response
 }
@@ -860,10 +952,11 @@ func BenchmarkRouterServe(b *testing.B) {
 	})
 
 	serviceManager := service.NewManager(rtConf.Services, nil, nil, staticRoundTripperGetter{res})
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil)
-	chainBuilder := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, nil, nil, nil, nil)
+	chainBuilder, err := middleware.NewChainBuilder(static.Configuration{}, nil, nil)
+	require.NoError(b, err)
 
-	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry())
+	routerManager := NewManager(rtConf, serviceManager, middlewaresBuilder, chainBuilder, metrics.NewVoidRegistry(), nil, nil)
 
 	handlers := routerManager.BuildHandlers(context.Background(), entryPoints, false)
 
@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/connections"
 	"github.com/traefik/traefik/v2/pkg/rules"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	tcpservice "github.com/traefik/traefik/v2/pkg/server/service/tcp"
@@ -29,6 +32,8 @@ func NewManager(conf *runtime.Configuration,
 	httpHandlers map[string]http.Handler,
 	httpsHandlers map[string]http.Handler,
 	tlsManager *traefiktls.Manager,
+	defaultServices map[string]string,
+	observer *connections.Observer,
 ) *Manager {
 	return &Manager{
 		serviceManager:     serviceManager,
@@ -37,6 +42,8 @@ func NewManager(conf *runtime.Configuration,
 		httpsHandlers:      httpsHandlers,
 		tlsManager:         tlsManager,
 		conf:               conf,
+		defaultServices:    defaultServices,
+		observer:           observer,
 	}
 }
 
@@ -48,6 +55,10 @@ type Manager struct {
 	httpsHandlers      map[string]http.Handler
 	tlsManager         *traefiktls.Manager
 	conf               *runtime.Configuration
+	// defaultServices are the services handling the connections without SNI, by entry point.
+	defaultServices map[string]string
+	// observer logs and measures the connections of the routers, if any.
+	observer *connections.Observer
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -84,6 +95,16 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 			log.FromContext(ctx).Error(err)
 			continue
 		}
+
+		if serviceName := m.defaultServices[entryPointName]; serviceName != "" {
+			defaultHandler, err := m.serviceManager.BuildTCP(ctx, serviceName)
+			if err != nil {
+				log.FromContext(ctx).Errorf("Error while building the default service %s of the connections without SNI: %v", serviceName, err)
+			} else {
+				handler.AddCatchAllNoSNI(defaultHandler)
+			}
+		}
+
 		entryPointHandlers[entryPointName] = handler
 	}
 	return entryPointHandlers
@@ -103,13 +124,15 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		log.FromContext(ctx).Errorf("Error during the build of the default TLS configuration: %v", err)
 	}
 
-	if len(configsHTTP) > 0 {
-		router.AddRouteHTTPTLS("*", defaultTLSConf)
-	}
-
 	// Keyed by domain, then by options reference.
 	tlsOptionsForHostSNI := map[string]map[string]nameAndConfig{}
 	tlsOptionsForHost := map[string]string{}
+	domainsForRouter := map[string][]string{}
+
+	// The domains of the routers verifying the client certificates themselves,
+	// for which the TLS handshake requests the client certificates.
+	clientCertsForHost := map[string]struct{}{}
+	clientCertsForAll := false
 	for routerHTTPName, routerHTTPConfig := range configsHTTP {
 		if routerHTTPConfig.TLS == nil {
 			continue
@@ -135,6 +158,16 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			logger.Warnf("No domain found in rule %v, the TLS options applied for this router will depend on the hostSNI of each request", routerHTTPConfig.Rule)
 		}
 
+		if routerHTTPConfig.TLS.ClientAuth != nil {
+			if len(domains) == 0 {
+				clientCertsForAll = true
+			}
+
+			for _, domain := range domains {
+				clientCertsForHost[domain] = struct{}{}
+			}
+		}
+
 		for _, domain := range domains {
 			tlsConf, err := m.tlsManager.Get(traefiktls.DefaultTLSStoreName, tlsOptionsName)
 			if err != nil {
@@ -158,9 +191,22 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			} else {
 				tlsOptionsForHost[domain] = tlsOptionsName
 			}
+
+			domainsForRouter[routerHTTPName] = append(domainsForRouter[routerHTTPName], domain)
 		}
 	}
 
+	// The served certificates depend on the TLS options finally applied to the domains.
+	for routerHTTPName, domains := range domainsForRouter {
+		certificates := make(map[string]*traefiktls.CertificateInfo, len(domains))
+		for _, domain := range domains {
+			tlsOptionsName := findTLSOptionName(tlsOptionsForHost, domain)
+			certificates[domain] = m.tlsManager.GetServedCertificate(traefiktls.DefaultTLSStoreName, tlsOptionsName, domain)
+		}
+
+		configsHTTP[routerHTTPName].Certificates = certificates
+	}
+
 	sniCheck := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		if req.TLS == nil {
 			handlerHTTPS.ServeHTTP(rw, req)
@@ -194,7 +240,16 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		handlerHTTPS.ServeHTTP(rw, req)
 	})
 
-	router.HTTPSHandler(sniCheck, defaultTLSConf)
+	defaultHTTPTLSConf := defaultTLSConf
+	if clientCertsForAll {
+		defaultHTTPTLSConf = requestClientCert(defaultTLSConf)
+	}
+
+	if len(configsHTTP) > 0 {
+		router.AddRouteHTTPTLS("*", defaultHTTPTLSConf)
+	}
+
+	router.HTTPSHandler(sniCheck, defaultHTTPTLSConf)
 
 	logger := log.FromContext(ctx)
 	for hostSNI, tlsConfigs := range tlsOptionsForHostSNI {
@@ -209,6 +264,10 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 			logger.Debugf("Adding route for %s with TLS options %s", hostSNI, optionsName)
 
+			if _, ok := clientCertsForHost[hostSNI]; ok || clientCertsForAll {
+				config = requestClientCert(config)
+			}
+
 			router.AddRouteHTTPTLS(hostSNI, config)
 		} else {
 			routers := make([]string, 0, len(tlsConfigs))
@@ -219,11 +278,29 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 			logger.Warnf("Found different TLS options for routers on the same host %v, so using the default TLS options instead for these routers: %#v", hostSNI, routers)
 
-			router.AddRouteHTTPTLS(hostSNI, defaultTLSConf)
+			config := defaultHTTPTLSConf
+			if _, ok := clientCertsForHost[hostSNI]; ok {
+				config = requestClientCert(defaultTLSConf)
+			}
+
+			router.AddRouteHTTPTLS(hostSNI, config)
 		}
 	}
 
-	for routerName, routerConfig := range configs {
+	// The routes of the same domain are keyed by domain, and matched in the order of the names of their routers,
+	// the routes restricted to client IPs being matched first.
+	routesForDomain := map[string][]tcp.ClientIPRoute{}
+	var catchAllNoTLSRoutes []tcp.ClientIPRoute
+
+	routerNames := make([]string, 0, len(configs))
+	for routerName := range configs {
+		routerNames = append(routerNames, routerName)
+	}
+	sort.Strings(routerNames)
+
+	for _, routerName := range routerNames {
+		routerConfig := configs[routerName]
+
 		ctxRouter := log.With(provider.AddInContext(ctx, routerName), log.Str(log.RouterName, routerName))
 		logger := log.FromContext(ctxRouter)
 
@@ -241,67 +318,148 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		handler, err := m.buildTCPHandler(ctxRouter, routerConfig)
+		handler, err := m.buildTCPHandler(ctxRouter, routerName, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error(err)
 			continue
 		}
 
-		domains, err := rules.ParseHostSNI(routerConfig.Rule)
+		routes, err := branchRoutes(routerConfig.Rule)
 		if err != nil {
-			routerErr := fmt.Errorf("unknown rule %s", routerConfig.Rule)
-			routerConfig.AddError(routerErr, true)
-			logger.Error(routerErr)
+			routerConfig.AddError(err, true)
+			logger.Error(err)
 			continue
 		}
 
-		for _, domain := range domains {
-			logger.Debugf("Adding route %s on TCP", domain)
-			switch {
-			case routerConfig.TLS != nil:
-				if !rules.IsASCII(domain) {
-					asciiError := fmt.Errorf("invalid domain name value %q, non-ASCII characters are not allowed", domain)
-					routerConfig.AddError(asciiError, true)
-					logger.Debug(asciiError)
-					continue
-				}
+		if routerConfig.TLS != nil && hasSSHRoute(routes) {
+			err := errors.New("the SSH rule cannot be used with TLS")
+			routerConfig.AddError(err, true)
+			logger.Error(err)
+			continue
+		}
 
-				if routerConfig.TLS.Passthrough {
-					router.AddRoute(domain, handler)
-					continue
+		for _, route := range routes {
+			if route.SSH {
+				logger.Debugf("Adding SSH route on TCP for versions %v", route.SSHVersions)
+				router.AddRouteSSH(route.SSHVersions, clientIPHandler([]tcp.ClientIPRoute{{Checkers: route.checkers, Handler: handler}}))
+			}
+
+			for _, domain := range route.Domains {
+				logger.Debugf("Adding route %s on TCP", domain)
+				switch {
+				case routerConfig.TLS != nil:
+					if !rules.IsASCII(domain) {
+						asciiError := fmt.Errorf("invalid domain name value %q, non-ASCII characters are not allowed", domain)
+						routerConfig.AddError(asciiError, true)
+						logger.Debug(asciiError)
+						continue
+					}
+
+					if routerConfig.TLS.Passthrough {
+						routesForDomain[domain] = append(routesForDomain[domain], tcp.ClientIPRoute{Checkers: route.checkers, Handler: handler})
+						continue
+					}
+
+					tlsOptionsName := routerConfig.TLS.Options
+
+					if len(tlsOptionsName) == 0 {
+						tlsOptionsName = traefiktls.DefaultTLSConfigName
+					}
+
+					if tlsOptionsName != traefiktls.DefaultTLSConfigName {
+						tlsOptionsName = provider.GetQualifiedName(ctxRouter, tlsOptionsName)
+					}
+
+					tlsConf, err := m.tlsManager.Get(traefiktls.DefaultTLSStoreName, tlsOptionsName)
+					if err != nil {
+						routerConfig.AddError(err, true)
+						logger.Debug(err)
+						continue
+					}
+
+					routesForDomain[domain] = append(routesForDomain[domain], tcp.ClientIPRoute{
+						Checkers: route.checkers,
+						Handler:  &tcp.TLSHandler{Next: handler, Config: tlsConf},
+					})
+				case domain == "*":
+					catchAllNoTLSRoutes = append(catchAllNoTLSRoutes, tcp.ClientIPRoute{Checkers: route.checkers, Handler: handler})
+				default:
+					logger.Warn("TCP Router ignored, cannot specify a Host rule without TLS")
 				}
+			}
+		}
+	}
 
-				tlsOptionsName := routerConfig.TLS.Options
+	for domain, routes := range routesForDomain {
+		router.AddRoute(domain, clientIPHandler(routes))
+	}
 
-				if len(tlsOptionsName) == 0 {
-					tlsOptionsName = traefiktls.DefaultTLSConfigName
-				}
+	if len(catchAllNoTLSRoutes) > 0 {
+		router.AddCatchAllNoTLS(clientIPHandler(catchAllNoTLSRoutes))
+	}
 
-				if tlsOptionsName != traefiktls.DefaultTLSConfigName {
-					tlsOptionsName = provider.GetQualifiedName(ctxRouter, tlsOptionsName)
-				}
+	return router, nil
+}
 
-				tlsConf, err := m.tlsManager.Get(traefiktls.DefaultTLSStoreName, tlsOptionsName)
-				if err != nil {
-					routerConfig.AddError(err, true)
-					logger.Debug(err)
-					continue
-				}
+// branchRoute is a branch of the rule of a router, with the checkers of its ClientIP matchers.
+type branchRoute struct {
+	rules.TCPRuleBranch
+
+	checkers []*ip.Checker
+}
 
-				router.AddRouteTLS(domain, handler, tlsConf)
-			case domain == "*":
-				router.AddCatchAllNoTLS(handler)
-			default:
-				logger.Warn("TCP Router ignored, cannot specify a Host rule without TLS")
+// branchRoutes returns the routes of the branches of the rule of a router,
+// so that the ClientIP matchers of a branch only restrict the other matchers of the branch.
+func branchRoutes(rule string) ([]branchRoute, error) {
+	branches, err := rules.ParseTCPRuleBranches(rule)
+	if err != nil {
+		return nil, fmt.Errorf("unknown rule %s: %w", rule, err)
+	}
+
+	routes := make([]branchRoute, 0, len(branches))
+	for _, branch := range branches {
+		var checkers []*ip.Checker
+		for _, clientIPs := range branch.ClientIPs {
+			checker, err := ip.NewChecker(clientIPs)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ClientIP matcher in rule %s: %w", rule, err)
 			}
+
+			checkers = append(checkers, checker)
 		}
+
+		routes = append(routes, branchRoute{TCPRuleBranch: branch, checkers: checkers})
 	}
 
-	return router, nil
+	return routes, nil
+}
+
+func hasSSHRoute(routes []branchRoute) bool {
+	for _, route := range routes {
+		if route.SSH {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIPHandler returns the handler forwarding the connections to the first of the routes matching their client IP,
+// the routes restricted to client IPs being matched before the others.
+func clientIPHandler(routes []tcp.ClientIPRoute) tcp.Handler {
+	if len(routes) == 1 && len(routes[0].Checkers) == 0 {
+		return routes[0].Handler
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].Checkers) > 0 && len(routes[j].Checkers) == 0
+	})
+
+	return tcp.ClientIPRouter(routes)
 }
 
-func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
+func (m *Manager) buildTCPHandler(ctx context.Context, routerName string, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
 	var qualifiedNames []string
 	for _, name := range router.Middlewares {
 		qualifiedNames = append(qualifiedNames, provider.GetQualifiedName(ctx, name))
@@ -317,11 +475,28 @@ func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouter
 		return nil, err
 	}
 
+	// The connections are observed behind the middlewares, which may wrap them,
+	// so that the service records the address of the server it forwards them to.
+	sHandler = m.observer.WrapTCP(routerName, provider.GetQualifiedName(ctx, router.Service), sHandler)
+
 	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
 
 	return tcp.NewChain().Extend(*mHandler).Then(sHandler)
 }
 
+// requestClientCert returns a copy of the TLS configuration requesting the client certificates,
+// if it does not already request them.
+func requestClientCert(conf *tls.Config) *tls.Config {
+	if conf == nil || conf.ClientAuth != tls.NoClientCert {
+		return conf
+	}
+
+	conf = conf.Clone()
+	conf.ClientAuth = tls.RequestClientCert
+
+	return conf
+}
+
 func findTLSOptionName(tlsOptionsForHost map[string]string, host string) string {
 	tlsOptions, ok := tlsOptionsForHost[host]
 	if ok {
@@ -3,14 +3,17 @@ package tcp
 import (
 	"context"
 	"crypto/tls"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/safe"
 	tcpmiddleware "github.com/traefik/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/service/tcp"
 	traefiktls "github.com/traefik/traefik/v2/pkg/tls"
@@ -72,6 +75,42 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 0,
 		},
+		{
+			desc: "Invalid ClientIP error",
+			tcpServiceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Port:    "8085",
+									Address: "127.0.0.1:8085",
+								},
+							},
+						},
+					},
+				},
+			},
+			tcpRouterConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`bar.foo`) && ClientIP(`10.0.0.0/8`)",
+						TLS:         &dynamic.RouterTCPTLSConfig{},
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "HostSNI(`bar.foo`) && ClientIP(`invalid`)",
+						TLS:         &dynamic.RouterTCPTLSConfig{},
+					},
+				},
+			},
+			expectedError: 1,
+		},
 		{
 			desc: "Non-ASCII domain error",
 			tcpServiceConfig: map[string]*runtime.TCPServiceInfo{
@@ -215,6 +254,40 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 2,
 		},
+		{
+			desc: "SSH router with TLS",
+			tcpServiceConfig: map[string]*runtime.TCPServiceInfo{
+				"foo-service": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "127.0.0.1:22",
+								},
+							},
+						},
+					},
+				},
+			},
+			tcpRouterConfig: map[string]*runtime.TCPRouterInfo{
+				"foo": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "SSH()",
+					},
+				},
+				"bar": {
+					TCPRouter: &dynamic.TCPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "SSH(`OpenSSH*`)",
+						TLS:         &dynamic.RouterTCPTLSConfig{},
+					},
+				},
+			},
+			expectedError: 1,
+		},
 		{
 			desc: "Router with unknown service",
 			tcpServiceConfig: map[string]*runtime.TCPServiceInfo{
@@ -285,7 +358,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				TCPServices: test.tcpServiceConfig,
 				TCPRouters:  test.tcpRouterConfig,
 			}
-			serviceManager := tcp.NewManager(conf)
+			serviceManager := tcp.NewManager(conf, safe.NewPool(context.Background()))
 			tlsManager := traefiktls.NewManager()
 			tlsManager.UpdateConfigs(
 				context.Background(),
@@ -306,7 +379,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 			middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
 
 			routerManager := NewManager(conf, serviceManager, middlewaresBuilder,
-				nil, nil, tlsManager)
+				nil, nil, tlsManager, nil, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
 
@@ -526,7 +599,7 @@ func TestDomainFronting(t *testing.T) {
 				Routers: test.routers,
 			}
 
-			serviceManager := tcp.NewManager(conf)
+			serviceManager := tcp.NewManager(conf, safe.NewPool(context.Background()))
 
 			tlsManager := traefiktls.NewManager()
 			tlsManager.UpdateConfigs(context.Background(), map[string]traefiktls.Store{}, tlsOptions, []*traefiktls.CertAndStores{})
@@ -537,7 +610,7 @@ func TestDomainFronting(t *testing.T) {
 
 			middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
 
-			routerManager := NewManager(conf, serviceManager, middlewaresBuilder, nil, httpsHandler, tlsManager)
+			routerManager := NewManager(conf, serviceManager, middlewaresBuilder, nil, httpsHandler, tlsManager, nil, nil)
 
 			routers := routerManager.BuildHandlers(context.Background(), entryPoints)
 
@@ -558,3 +631,180 @@ func TestDomainFronting(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestClientCert(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		conf     *tls.Config
+		expected tls.ClientAuthType
+	}{
+		{
+			desc:     "no client certificate",
+			conf:     &tls.Config{},
+			expected: tls.RequestClientCert,
+		},
+		{
+			desc:     "client certificates already verified",
+			conf:     &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert},
+			expected: tls.RequireAndVerifyClientCert,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conf := requestClientCert(test.conf)
+
+			assert.Equal(t, test.expected, conf.ClientAuth)
+			// The original configuration, shared with the other hosts, is left untouched.
+			assert.NotEqual(t, tls.RequestClientCert, test.conf.ClientAuth)
+		})
+	}
+}
+
+// remoteAddrConn is a connection with a given client address.
+type remoteAddrConn struct {
+	net.Conn
+	remoteAddr net.Addr
+}
+
+func (c remoteAddrConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c remoteAddrConn) CloseWrite() error {
+	return nil
+}
+
+func TestClientIPBranches(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		rule       string
+		serverName string
+		clientIP   string
+		expected   bool
+	}{
+		{
+			desc:       "domain of a branch with the client IP of the branch",
+			rule:       "(HostSNI(`a.com`) && ClientIP(`1.1.1.1`)) || (HostSNI(`b.com`) && ClientIP(`2.2.2.2`))",
+			serverName: "a.com",
+			clientIP:   "1.1.1.1",
+			expected:   true,
+		},
+		{
+			desc:       "domain of a branch with the client IP of another branch",
+			rule:       "(HostSNI(`a.com`) && ClientIP(`1.1.1.1`)) || (HostSNI(`b.com`) && ClientIP(`2.2.2.2`))",
+			serverName: "a.com",
+			clientIP:   "2.2.2.2",
+		},
+		{
+			desc:       "other domain with the client IP of its branch",
+			rule:       "(HostSNI(`a.com`) && ClientIP(`1.1.1.1`)) || (HostSNI(`b.com`) && ClientIP(`2.2.2.2`))",
+			serverName: "b.com",
+			clientIP:   "2.2.2.2",
+			expected:   true,
+		},
+		{
+			desc:       "domain of a branch without ClientIP matcher",
+			rule:       "HostSNI(`a.com`) || (HostSNI(`b.com`) && ClientIP(`2.2.2.2`))",
+			serverName: "a.com",
+			clientIP:   "1.1.1.1",
+			expected:   true,
+		},
+		{
+			desc:       "domain of a branch with a ClientIP matcher",
+			rule:       "HostSNI(`a.com`) || (HostSNI(`b.com`) && ClientIP(`2.2.2.2`))",
+			serverName: "b.com",
+			clientIP:   "1.1.1.1",
+		},
+		{
+			desc:       "client IP allowed by all the ClientIP matchers of a branch",
+			rule:       "HostSNI(`a.com`) && ClientIP(`1.1.1.0/24`) && ClientIP(`1.1.1.1`)",
+			serverName: "a.com",
+			clientIP:   "1.1.1.1",
+			expected:   true,
+		},
+		{
+			desc:       "client IP not allowed by all the ClientIP matchers of a branch",
+			rule:       "HostSNI(`a.com`) && ClientIP(`1.1.1.0/24`) && ClientIP(`1.1.1.1`)",
+			serverName: "a.com",
+			clientIP:   "1.1.1.2",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backend, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = backend.Close() })
+
+			accepted := make(chan struct{}, 1)
+			go func() {
+				conn, err := backend.Accept()
+				if err != nil {
+					return
+				}
+				accepted <- struct{}{}
+				_ = conn.Close()
+			}()
+
+			conf := runtime.NewConfig(dynamic.Configuration{
+				TCP: &dynamic.TCPConfiguration{
+					Services: map[string]*dynamic.TCPService{
+						"service": {
+							LoadBalancer: &dynamic.TCPServersLoadBalancer{
+								Servers: []dynamic.TCPServer{{Address: backend.Addr().String()}},
+							},
+						},
+					},
+					Routers: map[string]*dynamic.TCPRouter{
+						"router": {
+							EntryPoints: []string{"web"},
+							Service:     "service",
+							Rule:        test.rule,
+							TLS:         &dynamic.RouterTCPTLSConfig{Passthrough: true},
+						},
+					},
+				},
+			})
+
+			serviceManager := tcp.NewManager(conf, safe.NewPool(context.Background()))
+			tlsManager := traefiktls.NewManager()
+			middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
+
+			routerManager := NewManager(conf, serviceManager, middlewaresBuilder, nil, nil, tlsManager, nil, nil)
+
+			routers := routerManager.BuildHandlers(context.Background(), []string{"web"})
+			router, ok := routers["web"]
+			require.True(t, ok)
+			require.Empty(t, conf.TCPRouters["router"].Err)
+
+			serverConn, clientConn := net.Pipe()
+			t.Cleanup(func() { _ = clientConn.Close() })
+
+			handshake := make(chan error, 1)
+			go func() {
+				handshake <- tls.Client(clientConn, &tls.Config{ServerName: test.serverName, InsecureSkipVerify: true}).Handshake()
+			}()
+
+			go router.ServeTCP(remoteAddrConn{
+				Conn:       serverConn,
+				remoteAddr: &net.TCPAddr{IP: net.ParseIP(test.clientIP), Port: 1234},
+			})
+
+			select {
+			case <-accepted:
+				assert.True(t, test.expected, "the connection should have been refused")
+			case <-handshake:
+				assert.False(t, test.expected, "the connection should have been forwarded")
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for the connection to be forwarded or refused")
+			}
+		})
+	}
+}
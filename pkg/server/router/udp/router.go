@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"time"
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/middlewares/connections"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	udpservice "github.com/traefik/traefik/v2/pkg/server/service/udp"
 	"github.com/traefik/traefik/v2/pkg/udp"
@@ -15,10 +17,12 @@ import (
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *udpservice.Manager,
+	observer *connections.Observer,
 ) *Manager {
 	return &Manager{
 		serviceManager: serviceManager,
 		conf:           conf,
+		observer:       observer,
 	}
 }
 
@@ -26,6 +30,8 @@ func NewManager(conf *runtime.Configuration,
 type Manager struct {
 	serviceManager *udpservice.Manager
 	conf           *runtime.Configuration
+	// observer logs and measures the sessions of the routers, if any.
+	observer *connections.Observer
 }
 
 func (m *Manager) getUDPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.UDPRouterInfo {
@@ -91,6 +97,13 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
+		if routerConfig.Timeout < 0 {
+			err := errors.New("the timeout of the udp router must be positive")
+			routerConfig.AddError(err, true)
+			logger.Error(err)
+			continue
+		}
+
 		handler, err := m.serviceManager.BuildUDP(ctxRouter, routerConfig.Service)
 		if err != nil {
 			routerConfig.AddError(err, true)
@@ -98,8 +111,22 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 			continue
 		}
 
+		handler = m.observer.WrapUDP(routerName, provider.GetQualifiedName(ctxRouter, routerConfig.Service), handler)
+
+		if timeout := time.Duration(routerConfig.Timeout); timeout > 0 {
+			handler = withTimeout(handler, timeout)
+		}
+
 		handlers = append(handlers, handler)
 	}
 
 	return handlers, nil
 }
+
+// withTimeout sets the session timeout of the router on the sessions, before handling them.
+func withTimeout(next udp.Handler, timeout time.Duration) udp.Handler {
+	return udp.HandlerFunc(func(conn *udp.Conn) {
+		conn.SetTimeout(timeout)
+		next.ServeUDP(conn)
+	})
+}
@@ -3,8 +3,10 @@ package udp
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/server/service/udp"
@@ -105,6 +107,32 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 2,
 		},
+		{
+			desc: "Router with negative timeout",
+			serviceConfig: map[string]*runtime.UDPServiceInfo{
+				"foo-service": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.UDPRouterInfo{
+				"foo": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Timeout:     ptypes.Duration(-time.Second),
+					},
+				},
+			},
+			expectedError: 1,
+		},
 	}
 
 	for _, test := range testCases {
@@ -120,7 +148,7 @@ func TestRuntimeConfiguration(t *testing.T) {
 				UDPRouters:  test.routerConfig,
 			}
 			serviceManager := udp.NewManager(conf)
-			routerManager := NewManager(conf, serviceManager)
+			routerManager := NewManager(conf, serviceManager, nil)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
 
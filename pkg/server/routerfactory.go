@@ -3,20 +3,22 @@ package server
 import (
 	"context"
 
+	"github.com/traefik/traefik/v2/pkg/accounting"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
 	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/middlewares/connections"
 	"github.com/traefik/traefik/v2/pkg/server/middleware"
 	middlewaretcp "github.com/traefik/traefik/v2/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/router"
 	routertcp "github.com/traefik/traefik/v2/pkg/server/router/tcp"
 	routerudp "github.com/traefik/traefik/v2/pkg/server/router/udp"
 	"github.com/traefik/traefik/v2/pkg/server/service"
-	"github.com/traefik/traefik/v2/pkg/server/service/tcp"
 	"github.com/traefik/traefik/v2/pkg/server/service/udp"
 	tcpCore "github.com/traefik/traefik/v2/pkg/tcp"
 	"github.com/traefik/traefik/v2/pkg/tls"
+	"github.com/traefik/traefik/v2/pkg/types"
 	udpCore "github.com/traefik/traefik/v2/pkg/udp"
 )
 
@@ -27,39 +29,66 @@ type RouterFactory struct {
 
 	managerFactory  *service.ManagerFactory
 	metricsRegistry metrics.Registry
+	accountant      *accounting.Accountant
+	connObserver    *connections.Observer
 
 	pluginBuilder middleware.PluginsBuilder
 
 	chainBuilder *middleware.ChainBuilder
 	tlsManager   *tls.Manager
+
+	priorityStrategies map[string]string
+	defaultTCPServices map[string]string
+
+	forwardAuthProxy   *types.Proxy
+	sourceRangeSources *types.SourceRangeSources
 }
 
 // NewRouterFactory creates a new RouterFactory.
 func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager,
-	chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder, metricsRegistry metrics.Registry) *RouterFactory {
-	var entryPointsTCP, entryPointsUDP []string
-	for name, cfg := range staticConfiguration.EntryPoints {
-		protocol, err := cfg.GetProtocol()
-		if err != nil {
-			// Should never happen because Traefik should not start if protocol is invalid.
-			log.WithoutContext().Errorf("Invalid protocol: %v", err)
-		}
-
-		if protocol == "udp" {
-			entryPointsUDP = append(entryPointsUDP, name)
-		} else {
-			entryPointsTCP = append(entryPointsTCP, name)
-		}
-	}
-
-	return &RouterFactory{
-		entryPointsTCP:  entryPointsTCP,
-		entryPointsUDP:  entryPointsUDP,
+	chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder, metricsRegistry metrics.Registry, accountant *accounting.Accountant,
+	connObserver *connections.Observer) *RouterFactory {
+	factory := &RouterFactory{
 		managerFactory:  managerFactory,
 		metricsRegistry: metricsRegistry,
+		accountant:      accountant,
+		connObserver:    connObserver,
 		tlsManager:      tlsManager,
 		chainBuilder:    chainBuilder,
 		pluginBuilder:   pluginBuilder,
+
+		priorityStrategies: make(map[string]string),
+		defaultTCPServices: make(map[string]string),
+
+		forwardAuthProxy:   staticConfiguration.OutboundProxy.ForwardAuthProxy(),
+		sourceRangeSources: staticConfiguration.SourceRangeSources,
+	}
+
+	for name, cfg := range staticConfiguration.EntryPoints {
+		factory.AddEntryPoint(name, cfg)
+	}
+
+	return factory
+}
+
+// AddEntryPoint adds an entry point to the ones the routers are created for.
+func (f *RouterFactory) AddEntryPoint(name string, cfg *static.EntryPoint) {
+	protocol, err := cfg.GetProtocol()
+	if err != nil {
+		// Should never happen because Traefik should not start if protocol is invalid.
+		log.WithoutContext().Errorf("Invalid protocol: %v", err)
+	}
+
+	if protocol == "udp" {
+		f.entryPointsUDP = append(f.entryPointsUDP, name)
+		return
+	}
+
+	f.entryPointsTCP = append(f.entryPointsTCP, name)
+	f.priorityStrategies[name] = cfg.HTTP.PriorityStrategy
+
+	if cfg.SNIInspection != nil && cfg.SNIInspection.DefaultService != "" {
+		f.defaultTCPServices[name] = cfg.SNIInspection.DefaultService
 	}
 }
 
@@ -70,26 +99,29 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	// HTTP
 	serviceManager := f.managerFactory.Build(rtConf)
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder, f.forwardAuthProxy, f.sourceRangeSources, f.metricsRegistry)
 
-	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.chainBuilder, f.metricsRegistry)
+	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.chainBuilder, f.metricsRegistry, f.accountant, f.priorityStrategies)
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
 
 	serviceManager.LaunchHealthCheck()
+	serviceManager.LaunchRamps()
+	serviceManager.LaunchWarmUps()
+	serviceManager.LaunchSlowStarts()
 
 	// TCP
-	svcTCPManager := tcp.NewManager(rtConf)
+	svcTCPManager := f.managerFactory.BuildTCP(rtConf)
 
 	middlewaresTCPBuilder := middlewaretcp.NewBuilder(rtConf.TCPMiddlewares)
 
-	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
+	rtTCPManager := routertcp.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager, f.defaultTCPServices, f.connObserver)
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
 	// UDP
 	svcUDPManager := udp.NewManager(rtConf)
-	rtUDPManager := routerudp.NewManager(rtConf, svcUDPManager)
+	rtUDPManager := routerudp.NewManager(rtConf, svcUDPManager, f.connObserver)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
 	rtConf.PopulateUsedBy()
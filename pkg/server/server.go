@@ -23,6 +23,8 @@ type Server struct {
 
 	accessLoggerMiddleware *accesslog.Handler
 
+	staticReloader *StaticReloader
+
 	signals  chan os.Signal
 	stopChan chan bool
 
@@ -31,12 +33,13 @@ type Server struct {
 
 // NewServer returns an initialized Server.
 func NewServer(routinesPool *safe.Pool, entryPoints TCPEntryPoints, entryPointsUDP UDPEntryPoints, watcher *ConfigurationWatcher,
-	chainBuilder *middleware.ChainBuilder, accessLoggerMiddleware *accesslog.Handler) *Server {
+	chainBuilder *middleware.ChainBuilder, accessLoggerMiddleware *accesslog.Handler, staticReloader *StaticReloader) *Server {
 	srv := &Server{
 		watcher:                watcher,
 		tcpEntryPoints:         entryPoints,
 		chainBuilder:           chainBuilder,
 		accessLoggerMiddleware: accessLoggerMiddleware,
+		staticReloader:         staticReloader,
 		signals:                make(chan os.Signal, 1),
 		stopChan:               make(chan bool, 1),
 		routinesPool:           routinesPool,
@@ -74,6 +77,10 @@ func (s *Server) Wait() {
 func (s *Server) Stop() {
 	defer log.WithoutContext().Info("Server stopped")
 
+	if s.staticReloader != nil {
+		s.staticReloader.Close()
+	}
+
 	s.tcpEntryPoints.Stop()
 	s.udpEntryPoints.Stop()
 
@@ -111,4 +118,5 @@ func stopMetricsClients() {
 	metrics.StopDatadog()
 	metrics.StopStatsd()
 	metrics.StopInfluxDB()
+	metrics.StopInfluxDB2()
 }
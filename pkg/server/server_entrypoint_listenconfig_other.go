@@ -0,0 +1,20 @@
+// +build !linux,!darwin,!freebsd,!openbsd,!netbsd,!dragonfly
+
+package server
+
+import (
+	"net"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// newListenConfig creates a new net.ListenConfig for the given configuration of
+// an entry point.
+func newListenConfig(configuration *static.EntryPoint) net.ListenConfig {
+	if configuration != nil && configuration.ReusePort {
+		log.WithoutContext().Warn("The reusePort option of the entry points is not supported on this platform, and is ignored")
+	}
+
+	return net.ListenConfig{}
+}
@@ -0,0 +1,40 @@
+// +build linux darwin freebsd openbsd netbsd dragonfly
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"golang.org/x/sys/unix"
+)
+
+// newListenConfig creates a new net.ListenConfig for the given configuration of
+// an entry point.
+func newListenConfig(configuration *static.EntryPoint) net.ListenConfig {
+	if configuration == nil || !configuration.ReusePort {
+		return net.ListenConfig{}
+	}
+
+	return net.ListenConfig{Control: reusePortControl}
+}
+
+// reusePortControl sets the SO_REUSEPORT option, which allows several sockets,
+// of the same or different processes, to listen on the same address.
+func reusePortControl(_, _ string, c syscall.RawConn) error {
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	})
+	if err != nil {
+		return err
+	}
+
+	if sockErr != nil {
+		return fmt.Errorf("unable to set the SO_REUSEPORT socket option: %w", sockErr)
+	}
+
+	return nil
+}
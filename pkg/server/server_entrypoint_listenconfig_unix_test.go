@@ -0,0 +1,49 @@
+// +build linux darwin freebsd openbsd netbsd dragonfly
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+func TestNewListenConfig(t *testing.T) {
+	ep := static.EntryPoint{Address: "127.0.0.1:0"}
+	listenConfig := newListenConfig(&ep)
+	require.Nil(t, listenConfig.Control)
+
+	l1, err := listenConfig.Listen(context.Background(), "tcp", ep.Address)
+	require.NoError(t, err)
+	defer l1.Close()
+
+	_, err = listenConfig.Listen(context.Background(), "tcp", l1.Addr().String())
+	require.Error(t, err)
+
+	ep = static.EntryPoint{Address: "127.0.0.1:0", ReusePort: true}
+	listenConfig = newListenConfig(&ep)
+	require.NotNil(t, listenConfig.Control)
+
+	l2, err := listenConfig.Listen(context.Background(), "tcp", ep.Address)
+	require.NoError(t, err)
+	defer l2.Close()
+
+	l3, err := listenConfig.Listen(context.Background(), "tcp", l2.Addr().String())
+	require.NoError(t, err)
+	defer l3.Close()
+
+	assert.Equal(t, l2.Addr().String(), l3.Addr().String())
+
+	c1, err := listenConfig.ListenPacket(context.Background(), "udp", ep.Address)
+	require.NoError(t, err)
+	defer c1.Close()
+
+	c2, err := listenConfig.ListenPacket(context.Background(), "udp", c1.LocalAddr().String())
+	require.NoError(t, err)
+	defer c2.Close()
+
+	assert.Equal(t, c1.LocalAddr().String(), c2.LocalAddr().String())
+}
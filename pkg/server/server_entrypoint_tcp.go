@@ -8,14 +8,17 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/pires/go-proxyproto"
 	"github.com/sirupsen/logrus"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/ip"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares"
 	"github.com/traefik/traefik/v2/pkg/middlewares/forwardedheaders"
 	"github.com/traefik/traefik/v2/pkg/safe"
@@ -60,7 +63,7 @@ func (h *httpForwarder) Accept() (net.Conn, error) {
 type TCPEntryPoints map[string]*TCPEntryPoint
 
 // NewTCPEntryPoints creates a new TCPEntryPoints.
-func NewTCPEntryPoints(entryPointsConfig static.EntryPoints) (TCPEntryPoints, error) {
+func NewTCPEntryPoints(entryPointsConfig static.EntryPoints, metricsRegistry metrics.Registry) (TCPEntryPoints, error) {
 	serverEntryPointsTCP := make(TCPEntryPoints)
 	for entryPointName, config := range entryPointsConfig {
 		protocol, err := config.GetProtocol()
@@ -74,7 +77,7 @@ func NewTCPEntryPoints(entryPointsConfig static.EntryPoints) (TCPEntryPoints, er
 
 		ctx := log.With(context.Background(), log.Str(log.EntryPointName, entryPointName))
 
-		serverEntryPointsTCP[entryPointName], err = NewTCPEntryPoint(ctx, config)
+		serverEntryPointsTCP[entryPointName], err = NewTCPEntryPoint(ctx, entryPointName, config, metricsRegistry)
 		if err != nil {
 			return nil, fmt.Errorf("error while building entryPoint %s: %w", entryPointName, err)
 		}
@@ -125,14 +128,26 @@ type TCPEntryPoint struct {
 	tracker                *connectionTracker
 	httpServer             *httpServer
 	httpsServer            *httpServer
+	rateLimiter            *connRateLimiter
+	connLimiter            *connLimiter
+	sniInspection          *static.SNIInspection
+	name                   string
+	rejectedConns          gokitmetrics.Counter
 
 	http3Server *http3server
 }
 
 // NewTCPEntryPoint creates a new TCPEntryPoint.
-func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*TCPEntryPoint, error) {
+func NewTCPEntryPoint(ctx context.Context, name string, configuration *static.EntryPoint, metricsRegistry metrics.Registry) (*TCPEntryPoint, error) {
 	tracker := newConnectionTracker()
 
+	rateLimiter, err := newConnRateLimiter(name, configuration.ConnectionRateLimit, metricsRegistry.EntryPointRejectedConnsCounter())
+	if err != nil {
+		return nil, fmt.Errorf("error preparing connection rate limiter: %w", err)
+	}
+
+	connLimiter := newConnLimiter(name, configuration.ConnectionLimit, metricsRegistry.EntryPointRejectedConnsCounter())
+
 	listener, err := buildListener(ctx, configuration)
 	if err != nil {
 		return nil, fmt.Errorf("error preparing server: %w", err)
@@ -159,6 +174,8 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*T
 
 	rt.HTTPSForwarder(httpsServer.Forwarder)
 
+	setClientHelloInspection(rt, configuration.SNIInspection)
+
 	tcpSwitcher := &tcp.HandlerSwitcher{}
 	tcpSwitcher.Switch(rt)
 
@@ -169,6 +186,11 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint) (*T
 		tracker:                tracker,
 		httpServer:             httpServer,
 		httpsServer:            httpsServer,
+		rateLimiter:            rateLimiter,
+		connLimiter:            connLimiter,
+		sniInspection:          configuration.SNIInspection,
+		name:                   name,
+		rejectedConns:          metricsRegistry.EntryPointRejectedConnsCounter(),
 		http3Server:            h3server,
 	}, nil
 }
@@ -207,6 +229,8 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 			// Enforce read/write deadlines at the connection level,
 			// because when we're peeking the first byte to determine whether we are doing TLS,
 			// the deadlines at the server level are not taken into account.
+			// They are set first, because with the PROXY protocol,
+			// getting the source of the connection reads the header from the connection.
 			if e.transportConfiguration.RespondingTimeouts.ReadTimeout > 0 {
 				err := writeCloser.SetReadDeadline(time.Now().Add(time.Duration(e.transportConfiguration.RespondingTimeouts.ReadTimeout)))
 				if err != nil {
@@ -221,6 +245,44 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 				}
 			}
 
+			// The header is read within the read deadline.
+			if missingProxyProtocolHeader(conn) {
+				logger.Debugf("Rejecting connection from %s without the required PROXY protocol header", conn.RemoteAddr())
+				e.rejectedConns.With("entrypoint", e.name, "reason", rejectReasonProxyProtocol).Add(1)
+				_ = writeCloser.Close()
+				return
+			}
+
+			// Rate limiting happens before any TLS or HTTP data is read from the connection,
+			// so that connections over the limit cost no TLS handshake.
+			if e.rateLimiter != nil {
+				allowed, err := e.rateLimiter.Wait(ctx, writeCloser)
+				if err != nil {
+					logger.Errorf("Error while rate limiting connection: %v", err)
+				}
+
+				if !allowed {
+					_ = writeCloser.Close()
+					return
+				}
+			}
+
+			// The connection limit is enforced before any TLS or HTTP data is read from the connection,
+			// and the connection releases its slot once closed.
+			if e.connLimiter != nil {
+				limitedConn, allowed, err := e.connLimiter.Acquire(writeCloser)
+				if err != nil {
+					logger.Errorf("Error while limiting connection: %v", err)
+				}
+
+				if !allowed {
+					_ = writeCloser.Close()
+					return
+				}
+
+				writeCloser = limitedConn
+			}
+
 			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
 		})
 	}
@@ -295,6 +357,15 @@ func (e *TCPEntryPoint) Shutdown(ctx context.Context) {
 	cancel()
 }
 
+// setClientHelloInspection applies the limits of the SNI inspection of the entry point to the router.
+func setClientHelloInspection(rt *tcp.Router, inspection *static.SNIInspection) {
+	if inspection == nil {
+		return
+	}
+
+	rt.SetClientHelloInspection(time.Duration(inspection.ClientHelloTimeout), inspection.MaxBytes)
+}
+
 // SwitchRouter switches the TCP router handler.
 func (e *TCPEntryPoint) SwitchRouter(rt *tcp.Router) {
 	rt.HTTPForwarder(e.httpServer.Forwarder)
@@ -315,6 +386,8 @@ func (e *TCPEntryPoint) SwitchRouter(rt *tcp.Router) {
 
 	e.httpsServer.Switcher.UpdateHandler(httpsHandler)
 
+	setClientHelloInspection(rt, e.sniInspection)
+
 	e.switcher.Switch(rt)
 
 	if e.http3Server != nil {
@@ -377,11 +450,22 @@ func (ln tcpKeepAliveListener) Accept() (net.Conn, error) {
 	return tc, nil
 }
 
+const rejectReasonProxyProtocol = "proxy_protocol"
+
 func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoint, listener net.Listener) (net.Listener, error) {
 	proxyListener := &proxyproto.Listener{Listener: listener}
 
+	// The trusted IPs sending no PROXY protocol header are rejected if the header is required.
+	trustedPolicy := proxyproto.USE
+	if entryPoint.ProxyProtocol.Required {
+		trustedPolicy = proxyproto.REQUIRE
+	}
+
 	if entryPoint.ProxyProtocol.Insecure {
 		log.FromContext(ctx).Infof("Enabling ProxyProtocol without trusted IPs: Insecure")
+		proxyListener.Policy = func(upstream net.Addr) (proxyproto.Policy, error) {
+			return trustedPolicy, nil
+		}
 		return proxyListener, nil
 	}
 
@@ -400,7 +484,7 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 			log.FromContext(ctx).Debugf("IP %s is not in trusted IPs list, ignoring ProxyProtocol Headers and bypass connection", ipAddr.IP)
 			return proxyproto.IGNORE, nil
 		}
-		return proxyproto.USE, nil
+		return trustedPolicy, nil
 	}
 
 	log.FromContext(ctx).Infof("Enabling ProxyProtocol for trusted IPs %v", entryPoint.ProxyProtocol.TrustedIPs)
@@ -408,8 +492,20 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 	return proxyListener, nil
 }
 
+// missingProxyProtocolHeader reads the PROXY protocol header of the connection if it is required,
+// and returns whether it is missing or invalid.
+func missingProxyProtocolHeader(conn net.Conn) bool {
+	proxyConn, ok := conn.(*proxyproto.Conn)
+	if !ok || proxyConn.ProxyHeaderPolicy != proxyproto.REQUIRE {
+		return false
+	}
+
+	return proxyConn.ProxyHeader() == nil
+}
+
 func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.Listener, error) {
-	listener, err := net.Listen("tcp", entryPoint.GetAddress())
+	listenConfig := newListenConfig(entryPoint)
+	listener, err := listenConfig.Listen(ctx, "tcp", entryPoint.GetAddress())
 	if err != nil {
 		return nil, fmt.Errorf("error opening listener: %w", err)
 	}
@@ -514,16 +610,28 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		return nil, err
 	}
 
+	maxKeepAliveRequests := configuration.Transport.MaxKeepAliveRequests
+	if maxKeepAliveRequests > 0 {
+		handler = newKeepAliveLimiter(handler, maxKeepAliveRequests)
+	}
+
 	if withH2c {
 		handler = h2c.NewHandler(handler, &http2.Server{})
 	}
 
 	serverHTTP := &http.Server{
-		Handler:      handler,
-		ErrorLog:     httpServerLogger,
-		ReadTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
-		WriteTimeout: time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
-		IdleTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
+		Handler:           handler,
+		ErrorLog:          httpServerLogger,
+		ReadTimeout:       time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(configuration.Transport.RespondingTimeouts.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
+		IdleTimeout:       time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
+	}
+
+	if maxKeepAliveRequests > 0 {
+		serverHTTP.ConnContext = func(ctx context.Context, _ net.Conn) context.Context {
+			return context.WithValue(ctx, connRequestsKey, new(int64))
+		}
 	}
 
 	listener := newHTTPForwarder(ln)
@@ -540,6 +648,27 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 	}, nil
 }
 
+type connRequestsKeyType struct{}
+
+var connRequestsKey connRequestsKeyType
+
+// newKeepAliveLimiter returns a handler asking the clients to close their connection
+// once it has served the maximum number of requests.
+func newKeepAliveLimiter(next http.Handler, maxRequests int) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// The counter is missing for the requests received over HTTP/3.
+		if count, ok := req.Context().Value(connRequestsKey).(*int64); ok {
+			// The HTTP/2 streams of a connection are served concurrently.
+			if atomic.AddInt64(count, 1) >= int64(maxRequests) {
+				// The HTTP/2 server translates this header into a graceful shutdown of the connection.
+				rw.Header().Set("Connection", "close")
+			}
+		}
+
+		next.ServeHTTP(rw, req)
+	})
+}
+
 func newTrackedConnection(conn tcp.WriteCloser, tracker *connectionTracker) *trackedConnection {
 	tracker.AddConnection(conn)
 	return &trackedConnection{
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/tcp"
+)
+
+const rejectReasonConnectionLimit = "connection_limit"
+
+// connLimiter limits the number of connections handled at the same time by the entry point handlers,
+// globally and per source IP.
+type connLimiter struct {
+	entryPointName string
+
+	maxConns       int64
+	sourceMaxConns int64
+
+	mu      sync.Mutex
+	conns   int64
+	sources map[string]int64
+
+	rejectedConns gokitmetrics.Counter
+}
+
+func newConnLimiter(entryPointName string, config *static.ConnectionLimit, rejectedConns gokitmetrics.Counter) *connLimiter {
+	if config == nil || config.MaxConnections <= 0 && config.SourceMaxConnections <= 0 {
+		return nil
+	}
+
+	return &connLimiter{
+		entryPointName: entryPointName,
+		maxConns:       config.MaxConnections,
+		sourceMaxConns: config.SourceMaxConnections,
+		sources:        make(map[string]int64),
+		rejectedConns:  rejectedConns,
+	}
+}
+
+// Acquire reports whether the connection fits in the limits.
+// When it does, the returned connection releases its slot when closed.
+func (l *connLimiter) Acquire(conn tcp.WriteCloser) (tcp.WriteCloser, bool, error) {
+	source, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return nil, false, fmt.Errorf("could not extract source of connection: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxConns > 0 && l.conns >= l.maxConns || l.sourceMaxConns > 0 && l.sources[source] >= l.sourceMaxConns {
+		if l.rejectedConns != nil {
+			l.rejectedConns.With("entrypoint", l.entryPointName, "reason", rejectReasonConnectionLimit).Add(1)
+		}
+		return nil, false, nil
+	}
+
+	l.conns++
+	l.sources[source]++
+
+	return &limitedConnection{WriteCloser: conn, limiter: l, source: source}, true, nil
+}
+
+func (l *connLimiter) release(source string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.conns--
+
+	// Forgets the idle sources, so that the map only holds the sources with open connections.
+	l.sources[source]--
+	if l.sources[source] <= 0 {
+		delete(l.sources, source)
+	}
+}
+
+// limitedConnection is a connection holding a slot of a connLimiter, until it is closed.
+type limitedConnection struct {
+	tcp.WriteCloser
+	limiter *connLimiter
+	source  string
+	once    sync.Once
+}
+
+func (c *limitedConnection) Close() error {
+	c.once.Do(func() { c.limiter.release(c.source) })
+	return c.WriteCloser.Close()
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+type fakeWriteCloser struct {
+	net.Conn
+	closed int
+}
+
+func (c *fakeWriteCloser) Close() error {
+	c.closed++
+	return nil
+}
+
+func (c *fakeWriteCloser) CloseWrite() error {
+	return nil
+}
+
+func writeCloserFrom(t *testing.T, addr string) *fakeWriteCloser {
+	t.Helper()
+
+	return &fakeWriteCloser{Conn: connFrom(t, addr)}
+}
+
+func TestNewConnLimiter(t *testing.T) {
+	assert.Nil(t, newConnLimiter("web", nil, nil))
+	assert.Nil(t, newConnLimiter("web", &static.ConnectionLimit{}, nil))
+	assert.NotNil(t, newConnLimiter("web", &static.ConnectionLimit{MaxConnections: 1}, nil))
+	assert.NotNil(t, newConnLimiter("web", &static.ConnectionLimit{SourceMaxConnections: 1}, nil))
+}
+
+func TestConnLimiter_Acquire(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   static.ConnectionLimit
+		sources  []string
+		expected []bool
+	}{
+		{
+			desc:     "global limit",
+			config:   static.ConnectionLimit{MaxConnections: 2},
+			sources:  []string{"10.0.0.1:1000", "10.0.0.2:1000", "10.0.0.3:1000"},
+			expected: []bool{true, true, false},
+		},
+		{
+			desc:     "source limit",
+			config:   static.ConnectionLimit{SourceMaxConnections: 1},
+			sources:  []string{"10.0.0.1:1000", "10.0.0.2:1000", "10.0.0.1:1001"},
+			expected: []bool{true, true, false},
+		},
+		{
+			desc:     "rejected by source does not hold a global slot",
+			config:   static.ConnectionLimit{MaxConnections: 2, SourceMaxConnections: 1},
+			sources:  []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.0.2:1000", "10.0.0.3:1000"},
+			expected: []bool{true, false, true, false},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &collectingCounter{}
+			limiter := newConnLimiter("web", &test.config, counter)
+			require.NotNil(t, limiter)
+
+			var rejected float64
+			for i, source := range test.sources {
+				_, allowed, err := limiter.Acquire(writeCloserFrom(t, source))
+				require.NoError(t, err)
+				assert.Equal(t, test.expected[i], allowed, "connection %d from %s", i, source)
+
+				if !allowed {
+					rejected++
+				}
+			}
+
+			assert.Equal(t, rejected, counter.counterValue)
+			assert.Equal(t, []string{"entrypoint", "web", "reason", "connection_limit"}, counter.lastLabelValues)
+		})
+	}
+}
+
+func TestConnLimiter_release(t *testing.T) {
+	limiter := newConnLimiter("web", &static.ConnectionLimit{MaxConnections: 2, SourceMaxConnections: 1}, nil)
+
+	conn := writeCloserFrom(t, "10.0.0.1:1000")
+
+	limitedConn, allowed, err := limiter.Acquire(conn)
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	_, allowed, err = limiter.Acquire(writeCloserFrom(t, "10.0.0.1:1001"))
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	// Closing the connection twice releases its slot only once.
+	require.NoError(t, limitedConn.Close())
+	require.NoError(t, limitedConn.Close())
+	assert.Equal(t, 2, conn.closed)
+	assert.Equal(t, int64(0), limiter.conns)
+	assert.Empty(t, limiter.sources)
+
+	_, allowed, err = limiter.Acquire(writeCloserFrom(t, "10.0.0.1:1001"))
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
@@ -30,7 +30,8 @@ func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, https
 		return nil, nil
 	}
 
-	conn, err := net.ListenPacket("udp", configuration.GetAddress())
+	listenConfig := newListenConfig(configuration)
+	conn, err := listenConfig.ListenPacket(ctx, "udp", configuration.GetAddress())
 	if err != nil {
 		return nil, fmt.Errorf("error while starting http3 listener: %w", err)
 	}
@@ -44,13 +45,14 @@ func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, https
 
 	h3.Server = &http3.Server{
 		Server: &http.Server{
-			Addr:         configuration.GetAddress(),
-			Handler:      httpsServer.Server.(*http.Server).Handler,
-			ErrorLog:     httpServerLogger,
-			ReadTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
-			WriteTimeout: time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
-			IdleTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
-			TLSConfig:    &tls.Config{GetConfigForClient: h3.getGetConfigForClient},
+			Addr:              configuration.GetAddress(),
+			Handler:           httpsServer.Server.(*http.Server).Handler,
+			ErrorLog:          httpServerLogger,
+			ReadTimeout:       time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
+			ReadHeaderTimeout: time.Duration(configuration.Transport.RespondingTimeouts.ReadHeaderTimeout),
+			WriteTimeout:      time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
+			IdleTimeout:       time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
+			TLSConfig:         &tls.Config{GetConfigForClient: h3.getGetConfigForClient},
 		},
 	}
 
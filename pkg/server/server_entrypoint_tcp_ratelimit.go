@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"golang.org/x/time/rate"
+)
+
+const (
+	maxConnRateLimitSources = 65536
+	rejectReasonRateLimit   = "rate_limit"
+)
+
+// connRateLimiter limits the rate at which new connections are handed to the entry point handlers,
+// with a global token bucket and one token bucket per source IP.
+type connRateLimiter struct {
+	entryPointName string
+
+	global *rate.Limiter
+
+	sourceRate  rate.Limit
+	sourceBurst int
+	// sourceTTL is how long (in seconds) an idle source bucket is kept.
+	sourceTTL int
+	sources   *ttlmap.TtlMap
+
+	queue    bool
+	maxDelay time.Duration
+
+	rejectedConns gokitmetrics.Counter
+}
+
+func newConnRateLimiter(entryPointName string, config *static.ConnectionRateLimit, rejectedConns gokitmetrics.Counter) (*connRateLimiter, error) {
+	if config == nil || config.Average <= 0 && config.SourceAverage <= 0 {
+		return nil, nil
+	}
+
+	limiter := &connRateLimiter{
+		entryPointName: entryPointName,
+		maxDelay:       time.Duration(config.MaxDelay),
+		rejectedConns:  rejectedConns,
+	}
+
+	switch config.Policy {
+	case "", static.ConnectionRateLimitPolicyReject:
+	case static.ConnectionRateLimitPolicyQueue:
+		limiter.queue = true
+	default:
+		return nil, fmt.Errorf("unknown connection rate limit policy: %q", config.Policy)
+	}
+
+	if config.Average > 0 {
+		limiter.global = rate.NewLimiter(rate.Limit(config.Average), burstOrOne(config.Burst))
+	}
+
+	if config.SourceAverage > 0 {
+		sources, err := ttlmap.NewConcurrent(maxConnRateLimitSources)
+		if err != nil {
+			return nil, err
+		}
+
+		limiter.sources = sources
+		limiter.sourceRate = rate.Limit(config.SourceAverage)
+		limiter.sourceBurst = burstOrOne(config.SourceBurst)
+
+		// A source bucket is full again after burst/rate seconds,
+		// at which point forgetting it is equivalent to keeping it.
+		ttl := time.Duration(limiter.sourceBurst)*time.Second/time.Duration(config.SourceAverage) + limiter.maxDelay
+		limiter.sourceTTL = int(ttl/time.Second) + 1
+	}
+
+	return limiter, nil
+}
+
+// Wait reports whether the connection can be handled.
+// With the queue policy, it blocks until the connection fits in the limits, for at most the configured max delay.
+func (l *connRateLimiter) Wait(ctx context.Context, conn net.Conn) (bool, error) {
+	now := time.Now()
+
+	var reservations []*rate.Reservation
+
+	if l.sources != nil {
+		bucket, err := l.sourceBucket(conn)
+		if err != nil {
+			return false, err
+		}
+
+		reservations = append(reservations, bucket.ReserveN(now, 1))
+	}
+
+	if l.global != nil {
+		reservations = append(reservations, l.global.ReserveN(now, 1))
+	}
+
+	var delay time.Duration
+	for _, res := range reservations {
+		if !res.OK() {
+			l.reject(now, reservations)
+			return false, nil
+		}
+
+		if d := res.DelayFrom(now); d > delay {
+			delay = d
+		}
+	}
+
+	if delay == 0 {
+		return true, nil
+	}
+
+	if !l.queue || delay > l.maxDelay {
+		l.reject(now, reservations)
+		return false, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		l.reject(time.Now(), reservations)
+		return false, nil
+	}
+}
+
+func (l *connRateLimiter) sourceBucket(conn net.Conn) (*rate.Limiter, error) {
+	source, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return nil, fmt.Errorf("could not extract source of connection: %w", err)
+	}
+
+	var bucket *rate.Limiter
+	if rlSource, exists := l.sources.Get(source); exists {
+		bucket = rlSource.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(l.sourceRate, l.sourceBurst)
+	}
+
+	// We Set even in the case where the source already exists,
+	// because we want to update the expiryTime every time we get the source,
+	// as the expiryTime is supposed to reflect the activity (or lack thereof) on that source.
+	if err := l.sources.Set(source, bucket, l.sourceTTL); err != nil {
+		return nil, fmt.Errorf("could not insert bucket: %w", err)
+	}
+
+	return bucket, nil
+}
+
+func (l *connRateLimiter) reject(now time.Time, reservations []*rate.Reservation) {
+	for _, res := range reservations {
+		res.CancelAt(now)
+	}
+
+	if l.rejectedConns != nil {
+		l.rejectedConns.With("entrypoint", l.entryPointName, "reason", rejectReasonRateLimit).Add(1)
+	}
+}
+
+func burstOrOne(burst int64) int {
+	if burst < 1 {
+		return 1
+	}
+	return int(burst)
+}
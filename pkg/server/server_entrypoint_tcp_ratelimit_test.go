@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+)
+
+type fakeAddrConn struct {
+	net.Conn
+	remoteAddr net.Addr
+}
+
+func (c fakeAddrConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// collectingCounter is a metrics.Counter implementation that enables access to the counter value and last label values.
+type collectingCounter struct {
+	counterValue    float64
+	lastLabelValues []string
+}
+
+func (c *collectingCounter) With(labelValues ...string) metrics.Counter {
+	c.lastLabelValues = labelValues
+	return c
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.counterValue += delta
+}
+
+func connFrom(t *testing.T, addr string) net.Conn {
+	t.Helper()
+
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+	require.NoError(t, err)
+
+	return fakeAddrConn{remoteAddr: tcpAddr}
+}
+
+func TestNewConnRateLimiter(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		config    *static.ConnectionRateLimit
+		expectNil bool
+		expectErr bool
+	}{
+		{
+			desc:      "no configuration",
+			expectNil: true,
+		},
+		{
+			desc:      "no limit",
+			config:    &static.ConnectionRateLimit{Burst: 10},
+			expectNil: true,
+		},
+		{
+			desc:   "global limit",
+			config: &static.ConnectionRateLimit{Average: 10},
+		},
+		{
+			desc:   "per-source limit",
+			config: &static.ConnectionRateLimit{SourceAverage: 10},
+		},
+		{
+			desc:      "unknown policy",
+			config:    &static.ConnectionRateLimit{Average: 10, Policy: "drop"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			limiter, err := newConnRateLimiter("web", test.config, nil)
+			if test.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if test.expectNil {
+				assert.Nil(t, limiter)
+			} else {
+				assert.NotNil(t, limiter)
+			}
+		})
+	}
+}
+
+func TestConnRateLimiter_reject(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   static.ConnectionRateLimit
+		sources  []string
+		expected []bool
+	}{
+		{
+			desc:     "global burst",
+			config:   static.ConnectionRateLimit{Average: 1, Burst: 2},
+			sources:  []string{"10.0.0.1:1000", "10.0.0.2:1000", "10.0.0.3:1000"},
+			expected: []bool{true, true, false},
+		},
+		{
+			desc:     "source burst",
+			config:   static.ConnectionRateLimit{SourceAverage: 1, SourceBurst: 1},
+			sources:  []string{"10.0.0.1:1000", "10.0.0.2:1000", "10.0.0.1:1001"},
+			expected: []bool{true, true, false},
+		},
+		{
+			desc:     "rejected by source does not consume global tokens",
+			config:   static.ConnectionRateLimit{Average: 1, Burst: 2, SourceAverage: 1, SourceBurst: 1},
+			sources:  []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.0.2:1000", "10.0.0.3:1000"},
+			expected: []bool{true, false, true, false},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &collectingCounter{}
+			limiter, err := newConnRateLimiter("web", &test.config, counter)
+			require.NoError(t, err)
+
+			var rejected float64
+			for i, source := range test.sources {
+				allowed, err := limiter.Wait(context.Background(), connFrom(t, source))
+				require.NoError(t, err)
+				assert.Equal(t, test.expected[i], allowed, "connection %d from %s", i, source)
+
+				if !allowed {
+					rejected++
+				}
+			}
+
+			assert.Equal(t, rejected, counter.counterValue)
+			assert.Equal(t, []string{"entrypoint", "web", "reason", "rate_limit"}, counter.lastLabelValues)
+		})
+	}
+}
+
+func TestConnRateLimiter_queue(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		maxDelay time.Duration
+		expected bool
+	}{
+		{
+			desc:     "delay within max delay",
+			maxDelay: 200 * time.Millisecond,
+			expected: true,
+		},
+		{
+			desc:     "delay over max delay",
+			maxDelay: 50 * time.Millisecond,
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := &static.ConnectionRateLimit{
+				Average:  10,
+				Burst:    1,
+				Policy:   static.ConnectionRateLimitPolicyQueue,
+				MaxDelay: ptypes.Duration(test.maxDelay),
+			}
+
+			limiter, err := newConnRateLimiter("web", config, nil)
+			require.NoError(t, err)
+
+			conn := connFrom(t, "10.0.0.1:1000")
+
+			allowed, err := limiter.Wait(context.Background(), conn)
+			require.NoError(t, err)
+			require.True(t, allowed)
+
+			// The next token is available in 100ms.
+			start := time.Now()
+			allowed, err = limiter.Wait(context.Background(), conn)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, allowed)
+
+			if test.expected {
+				assert.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 )
 
@@ -73,13 +74,13 @@ func testShutdown(t *testing.T, router *tcp.Router) {
 	epConfig.RespondingTimeouts.ReadTimeout = ptypes.Duration(5 * time.Second)
 	epConfig.RespondingTimeouts.WriteTimeout = ptypes.Duration(5 * time.Second)
 
-	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+	entryPoint, err := NewTCPEntryPoint(context.Background(), "", &static.EntryPoint{
 		// We explicitly use an IPV4 address because on Alpine, with an IPV6 address
 		// there seems to be shenanigans related to properly cleaning up file descriptors
 		Address:          "127.0.0.1:0",
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
-	})
+	}, metrics.NewVoidRegistry())
 	require.NoError(t, err)
 
 	conn, err := startEntrypoint(entryPoint, router)
@@ -158,11 +159,11 @@ func TestReadTimeoutWithoutFirstByte(t *testing.T) {
 	epConfig.SetDefaults()
 	epConfig.RespondingTimeouts.ReadTimeout = ptypes.Duration(2 * time.Second)
 
-	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+	entryPoint, err := NewTCPEntryPoint(context.Background(), "", &static.EntryPoint{
 		Address:          ":0",
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
-	})
+	}, metrics.NewVoidRegistry())
 	require.NoError(t, err)
 
 	router := &tcp.Router{}
@@ -194,11 +195,11 @@ func TestReadTimeoutWithFirstByte(t *testing.T) {
 	epConfig.SetDefaults()
 	epConfig.RespondingTimeouts.ReadTimeout = ptypes.Duration(2 * time.Second)
 
-	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+	entryPoint, err := NewTCPEntryPoint(context.Background(), "", &static.EntryPoint{
 		Address:          ":0",
 		Transport:        epConfig,
 		ForwardedHeaders: &static.ForwardedHeaders{},
-	})
+	}, metrics.NewVoidRegistry())
 	require.NoError(t, err)
 
 	router := &tcp.Router{}
@@ -227,3 +228,125 @@ func TestReadTimeoutWithFirstByte(t *testing.T) {
 		t.Error("Timeout while read")
 	}
 }
+
+func TestMaxKeepAliveRequests(t *testing.T) {
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+	epConfig.MaxKeepAliveRequests = 2
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), "", &static.EntryPoint{
+		Address:          ":0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+	}, metrics.NewVoidRegistry())
+	require.NoError(t, err)
+
+	router := &tcp.Router{}
+	router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	conn, err := startEntrypoint(entryPoint, router)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	request, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:8082", nil)
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+
+	for i := 0; i < 2; i++ {
+		err = request.Write(conn)
+		require.NoError(t, err)
+
+		resp, err := http.ReadResponse(reader, request)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, i == 1, resp.Close)
+	}
+
+	// The connection is closed once the maximum number of requests is reached.
+	_, err = reader.ReadByte()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestProxyProtocolRequired(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		trustedIPs   []string
+		sendHeader   bool
+		expectReject bool
+	}{
+		{
+			desc:       "trusted IP with header",
+			trustedIPs: []string{"127.0.0.1", "::1"},
+			sendHeader: true,
+		},
+		{
+			desc:         "trusted IP without header",
+			trustedIPs:   []string{"127.0.0.1", "::1"},
+			expectReject: true,
+		},
+		{
+			desc:       "untrusted IP without header",
+			trustedIPs: []string{"10.0.0.1"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			epConfig := &static.EntryPointsTransport{}
+			epConfig.SetDefaults()
+
+			entryPoint, err := NewTCPEntryPoint(context.Background(), "", &static.EntryPoint{
+				Address:          ":0",
+				Transport:        epConfig,
+				ForwardedHeaders: &static.ForwardedHeaders{},
+				ProxyProtocol: &static.ProxyProtocol{
+					TrustedIPs: test.trustedIPs,
+					Required:   true,
+				},
+			}, metrics.NewVoidRegistry())
+			require.NoError(t, err)
+
+			router := &tcp.Router{}
+			router.HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = rw.Write([]byte(req.RemoteAddr))
+			}))
+
+			conn, err := startEntrypoint(entryPoint, router)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			if test.sendHeader {
+				_, err = conn.Write([]byte("PROXY TCP4 1.2.3.4 127.0.0.1 1234 80\r\n"))
+				require.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:8082", nil)
+			require.NoError(t, err)
+
+			err = request.Write(conn)
+			require.NoError(t, err)
+
+			resp, err := http.ReadResponse(bufio.NewReader(conn), request)
+			if test.expectReject {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			if test.sendHeader {
+				assert.Equal(t, "1.2.3.4:1234", string(body))
+			}
+		})
+	}
+}
@@ -3,12 +3,13 @@ package server
 import (
 	"context"
 	"fmt"
-	"net"
 	"sync"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/udp"
 )
 
@@ -16,7 +17,7 @@ import (
 type UDPEntryPoints map[string]*UDPEntryPoint
 
 // NewUDPEntryPoints returns all the UDP entry points, keyed by name.
-func NewUDPEntryPoints(cfg static.EntryPoints) (UDPEntryPoints, error) {
+func NewUDPEntryPoints(cfg static.EntryPoints, metricsRegistry metrics.Registry) (UDPEntryPoints, error) {
 	entryPoints := make(UDPEntryPoints)
 	for entryPointName, entryPoint := range cfg {
 		protocol, err := entryPoint.GetProtocol()
@@ -28,7 +29,7 @@ func NewUDPEntryPoints(cfg static.EntryPoints) (UDPEntryPoints, error) {
 			continue
 		}
 
-		ep, err := NewUDPEntryPoint(entryPoint)
+		ep, err := NewUDPEntryPoint(entryPoint, entryPointName, metricsRegistry.EntryPointUDPSessionsGauge())
 		if err != nil {
 			return nil, fmt.Errorf("error while building entryPoint %s: %w", entryPointName, err)
 		}
@@ -81,21 +82,27 @@ type UDPEntryPoint struct {
 	listener               *udp.Listener
 	switcher               *udp.HandlerSwitcher
 	transportConfiguration *static.EntryPointsTransport
+	sessionsGauge          gokitmetrics.Gauge
 }
 
 // NewUDPEntryPoint returns a UDP entry point.
-func NewUDPEntryPoint(cfg *static.EntryPoint) (*UDPEntryPoint, error) {
-	addr, err := net.ResolveUDPAddr("udp", cfg.GetAddress())
+// The sessions gauge, if any, counts the active sessions of the entry point.
+func NewUDPEntryPoint(cfg *static.EntryPoint, name string, sessionsGauge gokitmetrics.Gauge) (*UDPEntryPoint, error) {
+	listener, err := udp.Listen(newListenConfig(cfg), "udp", cfg.GetAddress(), time.Duration(cfg.UDP.Timeout), cfg.UDP.SourceIPAffinity)
 	if err != nil {
 		return nil, err
 	}
 
-	listener, err := udp.Listen("udp", addr, time.Duration(cfg.UDP.Timeout))
-	if err != nil {
-		return nil, err
+	if sessionsGauge != nil {
+		sessionsGauge = sessionsGauge.With("entrypoint", name)
 	}
 
-	return &UDPEntryPoint{listener: listener, switcher: &udp.HandlerSwitcher{}, transportConfiguration: cfg.Transport}, nil
+	return &UDPEntryPoint{
+		listener:               listener,
+		switcher:               &udp.HandlerSwitcher{},
+		transportConfiguration: cfg.Transport,
+		sessionsGauge:          sessionsGauge,
+	}, nil
 }
 
 // Start commences the listening for ep.
@@ -108,8 +115,18 @@ func (ep *UDPEntryPoint) Start(ctx context.Context) {
 			return
 		}
 
-		go ep.switcher.ServeUDP(conn)
+		go ep.serveUDP(conn)
+	}
+}
+
+// serveUDP serves the session, which lasts as long as its handler.
+func (ep *UDPEntryPoint) serveUDP(conn *udp.Conn) {
+	if ep.sessionsGauge != nil {
+		ep.sessionsGauge.Add(1)
+		defer ep.sessionsGauge.Add(-1)
 	}
+
+	ep.switcher.ServeUDP(conn)
 }
 
 // Shutdown closes ep's listener. It eventually closes all "sessions" and
@@ -24,7 +24,7 @@ func TestShutdownUDPConn(t *testing.T) {
 	}
 	ep.SetDefaults()
 
-	entryPoint, err := NewUDPEntryPoint(&ep)
+	entryPoint, err := NewUDPEntryPoint(&ep, "", nil)
 	require.NoError(t, err)
 
 	go entryPoint.Start(context.Background())
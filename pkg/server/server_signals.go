@@ -12,6 +12,10 @@ import (
 
 func (s *Server) configureSignals() {
 	signal.Notify(s.signals, syscall.SIGUSR1)
+
+	if s.staticReloader != nil {
+		signal.Notify(s.signals, syscall.SIGHUP)
+	}
 }
 
 func (s *Server) listenSignals(ctx context.Context) {
@@ -20,6 +24,14 @@ func (s *Server) listenSignals(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case sig := <-s.signals:
+			if sig == syscall.SIGHUP {
+				log.WithoutContext().Infof("Reloading the static configuration: %+v", sig)
+
+				if _, err := s.staticReloader.ReloadStaticConfiguration(); err != nil {
+					log.WithoutContext().Errorf("Error reloading the static configuration: %v", err)
+				}
+			}
+
 			if sig == syscall.SIGUSR1 {
 				log.WithoutContext().Infof("Closing and re-opening log files for rotation: %+v", sig)
 
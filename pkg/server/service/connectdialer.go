@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+// connectDialer dials the backend servers through an upstream proxy,
+// by tunneling the connections with CONNECT requests.
+// With the https scheme, the connection to the proxy itself is secured with TLS.
+type connectDialer struct {
+	proxyAddr     string
+	tlsConfig     *tls.Config
+	authorization string
+	dialContext   func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func newConnectDialer(cfg *dynamic.ServersTransportProxy, dialContext func(ctx context.Context, network, addr string) (net.Conn, error)) (*connectDialer, error) {
+	proxyURL, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", cfg.URL, err)
+	}
+
+	if proxyURL.Hostname() == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", cfg.URL)
+	}
+
+	d := &connectDialer{
+		proxyAddr:   proxyURL.Host,
+		dialContext: dialContext,
+	}
+
+	switch proxyURL.Scheme {
+	case "http":
+		if proxyURL.Port() == "" {
+			d.proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+		}
+	case "https":
+		if proxyURL.Port() == "" {
+			d.proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "443")
+		}
+
+		d.tlsConfig = &tls.Config{
+			ServerName:         proxyURL.Hostname(),
+			InsecureSkipVerify: cfg.InsecureSkipVerify,
+			RootCAs:            createRootCACertPool(cfg.RootCAs),
+		}
+	default:
+		return nil, fmt.Errorf("invalid proxy URL %q: unsupported scheme %q", cfg.URL, proxyURL.Scheme)
+	}
+
+	if cfg.Username != "" || cfg.Password != "" {
+		d.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.Username+":"+cfg.Password))
+	}
+
+	return d, nil
+}
+
+// DialContext connects to the proxy, and asks it to open a tunnel to the given address.
+func (d *connectDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := d.dialContext(ctx, network, d.proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("dialing proxy %s: %w", d.proxyAddr, err)
+	}
+
+	// The TLS handshake and the CONNECT exchange are bounded by the deadline of the dial.
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+
+	if d.tlsConfig != nil {
+		tlsConn := tls.Client(conn, d.tlsConfig)
+		if err = tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("TLS handshake with proxy %s: %w", d.proxyAddr, err)
+		}
+
+		conn = tlsConn
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if d.authorization != "" {
+		req.Header.Set("Proxy-Authorization", d.authorization)
+	}
+
+	if err = req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("sending CONNECT request to proxy %s: %w", d.proxyAddr, err)
+	}
+
+	br := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("reading CONNECT response from proxy %s: %w", d.proxyAddr, err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", d.proxyAddr, addr, resp.Status)
+	}
+
+	// Data sent by the backend right after the tunnel is established may already be buffered.
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: br}, nil
+	}
+
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn whose first bytes are read from a buffered reader.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
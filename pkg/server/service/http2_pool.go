@@ -0,0 +1,35 @@
+package service
+
+import (
+	"net/http"
+	"sync"
+)
+
+// http2Pool spreads the requests sent to each server over a pool of round trippers,
+// each of them holding its own HTTP/2 connection to the server.
+// As HTTP/2 multiplexes all the requests on a single connection,
+// a server reached through an L4 load-balancer would otherwise pin all the calls (e.g. gRPC) to a single backend.
+type http2Pool struct {
+	roundTrippers []http.RoundTripper
+
+	mu   sync.Mutex
+	next map[string]int
+}
+
+func newHTTP2Pool(roundTrippers []http.RoundTripper) *http2Pool {
+	return &http2Pool{
+		roundTrippers: roundTrippers,
+		next:          make(map[string]int),
+	}
+}
+
+func (p *http2Pool) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The round trippers are picked in turn for each server,
+	// so that the balancing between the servers does not pin a server to a connection.
+	p.mu.Lock()
+	index := p.next[req.URL.Host]
+	p.next[req.URL.Host] = (index + 1) % len(p.roundTrippers)
+	p.mu.Unlock()
+
+	return p.roundTrippers[index].RoundTrip(req)
+}
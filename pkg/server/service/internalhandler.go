@@ -11,11 +11,15 @@ import (
 type serviceManager interface {
 	BuildHTTP(rootCtx context.Context, serviceName string) (http.Handler, error)
 	LaunchHealthCheck()
+	LaunchRamps()
+	LaunchWarmUps()
+	LaunchSlowStarts()
 }
 
 // InternalHandlers is the internal HTTP handlers builder.
 type InternalHandlers struct {
 	api        http.Handler
+	scopedAPIs map[string]http.Handler
 	dashboard  http.Handler
 	rest       http.Handler
 	prometheus http.Handler
@@ -25,9 +29,10 @@ type InternalHandlers struct {
 }
 
 // NewInternalHandlers creates a new InternalHandlers.
-func NewInternalHandlers(next serviceManager, apiHandler, rest, metricsHandler, pingHandler, dashboard, acmeHTTP http.Handler) *InternalHandlers {
+func NewInternalHandlers(next serviceManager, apiHandler http.Handler, scopedAPIs map[string]http.Handler, rest, metricsHandler, pingHandler, dashboard, acmeHTTP http.Handler) *InternalHandlers {
 	return &InternalHandlers{
 		api:            apiHandler,
+		scopedAPIs:     scopedAPIs,
 		dashboard:      dashboard,
 		rest:           rest,
 		prometheus:     metricsHandler,
@@ -95,6 +100,13 @@ func (m *InternalHandlers) get(serviceName string) (http.Handler, error) {
 		return m.prometheus, nil
 
 	default:
+		if strings.HasPrefix(serviceName, "api-") {
+			scopedAPI, ok := m.scopedAPIs[strings.TrimSuffix(strings.TrimPrefix(serviceName, "api-"), "@internal")]
+			if ok {
+				return scopedAPI, nil
+			}
+		}
+
 		return nil, fmt.Errorf("unknown internal service %s", serviceName)
 	}
 }
@@ -0,0 +1,170 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// Failover is an http.Handler forwarding the requests to its main handler while it is up,
+// and to its fallback handler when the main handler is down.
+type Failover struct {
+	wantsHealthCheck       bool
+	requireHealthyFallback bool
+	handler                http.Handler
+	fallbackHandler        http.Handler
+	// updaters is the list of hooks that are run (to update the Failover
+	// parent(s)), whenever the Failover status changes.
+	updaters []func(bool)
+	// failoverGauge reports whether the traffic is switched to the fallback handler.
+	failoverGauge gokitmetrics.Gauge
+
+	mutex          sync.RWMutex
+	handlerStatus  bool
+	fallbackStatus bool
+}
+
+// New creates a new Failover handler.
+// The failoverGauge, if not nil, is set to 1 while the traffic is switched to the fallback handler, and to 0 otherwise.
+func New(config *dynamic.Failover, failoverGauge gokitmetrics.Gauge) *Failover {
+	f := &Failover{
+		wantsHealthCheck:       config.HealthCheck != nil,
+		requireHealthyFallback: config.RequireHealthyFallback,
+		failoverGauge:          failoverGauge,
+		handlerStatus:          true,
+		fallbackStatus:         true,
+	}
+
+	if f.failoverGauge != nil {
+		f.failoverGauge.Set(0)
+	}
+
+	return f
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Failover changes.
+// Not thread safe.
+func (f *Failover) RegisterStatusUpdater(fn func(up bool)) error {
+	if !f.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this failover service")
+	}
+
+	f.updaters = append(f.updaters, fn)
+
+	return nil
+}
+
+// WantsFallbackStatus reports whether the status of the fallback handler is needed,
+// either to serve the requests, or to report the status of the Failover to its parent(s).
+func (f *Failover) WantsFallbackStatus() bool {
+	return f.requireHealthyFallback || f.wantsHealthCheck
+}
+
+func (f *Failover) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mutex.RLock()
+	handlerStatus, fallbackStatus := f.handlerStatus, f.fallbackStatus
+	f.mutex.RUnlock()
+
+	if handlerStatus {
+		f.handler.ServeHTTP(w, req)
+		return
+	}
+
+	if fallbackStatus {
+		f.fallbackHandler.ServeHTTP(w, req)
+		return
+	}
+
+	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
+
+// SetHandler sets the main http.Handler.
+func (f *Failover) SetHandler(handler http.Handler) {
+	f.handler = handler
+}
+
+// SetFallbackHandler sets the fallback http.Handler.
+func (f *Failover) SetFallbackHandler(handler http.Handler) {
+	f.fallbackHandler = handler
+}
+
+// SetHandlerStatus sets the status of the main handler,
+// switching the traffic to the fallback handler when it is down.
+func (f *Failover) SetHandlerStatus(ctx context.Context, up bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.handlerStatus == up {
+		return
+	}
+
+	upBefore := f.up()
+	f.handlerStatus = up
+
+	logger := log.FromContext(ctx)
+	if up {
+		logger.Info("Service is up, switching the traffic back from the fallback service")
+	} else {
+		logger.Warn("Service is down, switching the traffic to the fallback service")
+	}
+
+	if f.failoverGauge != nil {
+		if up {
+			f.failoverGauge.Set(0)
+		} else {
+			f.failoverGauge.Set(1)
+		}
+	}
+
+	f.propagate(ctx, upBefore)
+}
+
+// SetFallbackStatus sets the status of the fallback handler.
+func (f *Failover) SetFallbackStatus(ctx context.Context, up bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.fallbackStatus == up {
+		return
+	}
+
+	upBefore := f.up()
+	f.fallbackStatus = up
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+	log.FromContext(ctx).Debugf("Setting status of the fallback service to %s", status)
+
+	f.propagate(ctx, upBefore)
+}
+
+// up reports whether the Failover can serve the requests.
+func (f *Failover) up() bool {
+	return f.handlerStatus || f.fallbackStatus
+}
+
+// propagate runs the updaters if the status of the Failover changed.
+func (f *Failover) propagate(ctx context.Context, upBefore bool) {
+	upAfter := f.up()
+	if upBefore == upAfter {
+		return
+	}
+
+	status := "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+	log.FromContext(ctx).Debugf("Propagating new %s status", status)
+
+	for _, fn := range f.updaters {
+		fn(upAfter)
+	}
+}
@@ -0,0 +1,103 @@
+package failover
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+)
+
+func newHandler(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", name)
+		rw.WriteHeader(http.StatusOK)
+	})
+}
+
+func serve(f *Failover) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	f.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return recorder
+}
+
+func TestFailover(t *testing.T) {
+	gauge := generic.NewGauge("failover")
+
+	f := New(&dynamic.Failover{}, gauge)
+	f.SetHandler(newHandler("handler"))
+	f.SetFallbackHandler(newHandler("fallback"))
+
+	assert.Equal(t, "handler", serve(f).Header().Get("server"))
+	assert.Equal(t, float64(0), gauge.Value())
+
+	f.SetHandlerStatus(context.Background(), false)
+
+	assert.Equal(t, "fallback", serve(f).Header().Get("server"))
+	assert.Equal(t, float64(1), gauge.Value())
+
+	f.SetHandlerStatus(context.Background(), true)
+
+	assert.Equal(t, "handler", serve(f).Header().Get("server"))
+	assert.Equal(t, float64(0), gauge.Value())
+}
+
+func TestFailoverFallbackDown(t *testing.T) {
+	f := New(&dynamic.Failover{RequireHealthyFallback: true}, nil)
+	f.SetHandler(newHandler("handler"))
+	f.SetFallbackHandler(newHandler("fallback"))
+
+	assert.True(t, f.WantsFallbackStatus())
+
+	f.SetFallbackStatus(context.Background(), false)
+
+	assert.Equal(t, "handler", serve(f).Header().Get("server"))
+
+	f.SetHandlerStatus(context.Background(), false)
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve(f).Code)
+
+	f.SetFallbackStatus(context.Background(), true)
+
+	assert.Equal(t, "fallback", serve(f).Header().Get("server"))
+}
+
+func TestFailoverPropagate(t *testing.T) {
+	f := New(&dynamic.Failover{HealthCheck: &dynamic.HealthCheck{}}, nil)
+	f.SetHandler(newHandler("handler"))
+	f.SetFallbackHandler(newHandler("fallback"))
+
+	assert.True(t, f.WantsFallbackStatus())
+
+	var statuses []bool
+	err := f.RegisterStatusUpdater(func(up bool) {
+		statuses = append(statuses, up)
+	})
+	require.NoError(t, err)
+
+	f.SetHandlerStatus(context.Background(), false)
+	assert.Empty(t, statuses)
+
+	f.SetFallbackStatus(context.Background(), false)
+	assert.Equal(t, []bool{false}, statuses)
+
+	f.SetFallbackStatus(context.Background(), true)
+	assert.Equal(t, []bool{false, true}, statuses)
+
+	f.SetHandlerStatus(context.Background(), true)
+	assert.Equal(t, []bool{false, true}, statuses)
+}
+
+func TestFailoverRegisterStatusUpdaterWithoutHealthCheck(t *testing.T) {
+	f := New(&dynamic.Failover{}, nil)
+
+	assert.False(t, f.WantsFallbackStatus())
+
+	err := f.RegisterStatusUpdater(func(up bool) {})
+	assert.Error(t, err)
+}
@@ -53,18 +53,20 @@ func (m *Mirroring) inc() uint64 {
 type mirrorHandler struct {
 	http.Handler
 	percent int
+	match   *dynamic.RequestMatch
 
 	lock  sync.RWMutex
 	count uint64
 }
 
-func (m *Mirroring) getActiveMirrors() []http.Handler {
+func (m *Mirroring) getActiveMirrors(req *http.Request) []http.Handler {
 	total := m.inc()
 
 	var mirrors []http.Handler
 	for _, handler := range m.mirrorHandlers {
 		handler.lock.Lock()
-		if handler.count*100 < total*uint64(handler.percent) {
+		// The matching requests are always mirrored, and counted in the percentage of mirrored requests.
+		if handler.match != nil && handler.match.Match(req) || handler.count*100 < total*uint64(handler.percent) {
 			handler.count++
 			handler.lock.Unlock()
 			mirrors = append(mirrors, handler)
@@ -76,7 +78,7 @@ func (m *Mirroring) getActiveMirrors() []http.Handler {
 }
 
 func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	mirrors := m.getActiveMirrors()
+	mirrors := m.getActiveMirrors(req)
 	if len(mirrors) == 0 {
 		m.handler.ServeHTTP(rw, req)
 		return
@@ -131,10 +133,15 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
 // AddMirror adds an httpHandler to mirror to.
 func (m *Mirroring) AddMirror(handler http.Handler, percent int) error {
+	return m.AddMatchingMirror(handler, percent, nil)
+}
+
+// AddMatchingMirror adds an httpHandler to mirror to, the requests selected by the given match being always mirrored.
+func (m *Mirroring) AddMatchingMirror(handler http.Handler, percent int, match *dynamic.RequestMatch) error {
 	if percent < 0 || percent > 100 {
 		return errors.New("percent must be between 0 and 100")
 	}
-	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent})
+	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent, match: match})
 	return nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/safe"
 )
 
@@ -44,6 +45,35 @@ func TestMirroringOn100(t *testing.T) {
 	assert.Equal(t, 50, int(val2))
 }
 
+func TestMirroringPrimaryResponse(t *testing.T) {
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Served-By", "primary")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte("primary"))
+	})
+
+	var countMirror int32
+	pool := safe.NewPool(context.Background())
+	mirror := New(handler, pool, defaultMaxBodySize, nil)
+	err := mirror.AddMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&countMirror, 1)
+		rw.Header().Set("X-Served-By", "mirror")
+		rw.WriteHeader(http.StatusInternalServerError)
+		_, _ = rw.Write([]byte("mirror"))
+	}), 100)
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	mirror.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	pool.Stop()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&countMirror))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "primary", recorder.Body.String())
+	assert.Equal(t, []string{"primary"}, recorder.Header()["X-Served-By"])
+}
+
 func TestMirroringOn10(t *testing.T) {
 	var countMirror1, countMirror2 int32
 	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
@@ -73,6 +103,32 @@ func TestMirroringOn10(t *testing.T) {
 	assert.Equal(t, 5, int(val2))
 }
 
+func TestMirroringMatch(t *testing.T) {
+	var countMirror int32
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	pool := safe.NewPool(context.Background())
+	mirror := New(handler, pool, defaultMaxBodySize, nil)
+	err := mirror.AddMatchingMirror(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&countMirror, 1)
+	}), 10, &dynamic.RequestMatch{Header: "X-Mirror"})
+	assert.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if i < 5 {
+			req.Header.Set("X-Mirror", "true")
+		}
+		mirror.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	pool.Stop()
+
+	// The matching requests are counted in the percentage, so that no other request is mirrored.
+	assert.Equal(t, 5, int(atomic.LoadInt32(&countMirror)))
+}
+
 func TestInvalidPercent(t *testing.T) {
 	mirror := New(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}), safe.NewPool(context.Background()), defaultMaxBodySize, nil)
 	err := mirror.AddMirror(nil, -1)
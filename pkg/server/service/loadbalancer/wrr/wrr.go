@@ -19,6 +19,12 @@ type namedHandler struct {
 	deadline float64
 }
 
+// serviceMatch selects the requests always forwarded to a child service.
+type serviceMatch struct {
+	name  string
+	match *dynamic.RequestMatch
+}
+
 type stickyCookie struct {
 	name     string
 	secure   bool
@@ -37,6 +43,9 @@ type Balancer struct {
 	mutex       sync.RWMutex
 	handlers    []*namedHandler
 	curDeadline float64
+	// idleHandlers are the handlers with a non-positive weight, keyed by name.
+	// They are not load-balanced, until their weight is made positive through SetWeight.
+	idleHandlers map[string]*namedHandler
 	// status is a record of which child services of the Balancer are healthy, keyed
 	// by name of child service. A service is initially added to the map when it is
 	// created via AddService, and it is later removed or added to the map as needed,
@@ -45,12 +54,15 @@ type Balancer struct {
 	// updaters is the list of hooks that are run (to update the Balancer
 	// parent(s)), whenever the Balancer status changes.
 	updaters []func(bool)
+	// matches select the requests always forwarded to a child service, in the order of the child services.
+	matches []serviceMatch
 }
 
 // New creates a new load balancer.
 func New(sticky *dynamic.Sticky, hc *dynamic.HealthCheck) *Balancer {
 	balancer := &Balancer{
 		status:           make(map[string]struct{}),
+		idleHandlers:     make(map[string]*namedHandler),
 		wantsHealthCheck: hc != nil,
 	}
 	if sticky != nil && sticky.Cookie != nil {
@@ -176,7 +188,51 @@ func (b *Balancer) nextServer() (*namedHandler, error) {
 	return handler, nil
 }
 
+// matchedHandler returns the first healthy child service selected by its match for the request, if any.
+func (b *Balancer) matchedHandler(req *http.Request) http.Handler {
+	for _, m := range b.matches {
+		if !m.match.Match(req) {
+			continue
+		}
+
+		b.mutex.RLock()
+		handler := b.handler(m.name)
+		b.mutex.RUnlock()
+
+		if handler != nil {
+			return handler
+		}
+	}
+
+	return nil
+}
+
+// handler returns the given child service, unless it is down.
+// The child services which are not load-balanced, because of their non-positive weight, are returned too.
+func (b *Balancer) handler(name string) http.Handler {
+	if h, ok := b.idleHandlers[name]; ok {
+		return h
+	}
+
+	if _, ok := b.status[name]; !ok {
+		return nil
+	}
+
+	for _, h := range b.handlers {
+		if h.name == name {
+			return h
+		}
+	}
+
+	return nil
+}
+
 func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if handler := b.matchedHandler(req); handler != nil {
+		handler.ServeHTTP(w, req)
+		return
+	}
+
 	if b.stickyCookie != nil {
 		cookie, err := req.Cookie(b.stickyCookie.name)
 
@@ -224,21 +280,70 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 }
 
 // AddService adds a handler.
-// A handler with a non-positive weight is ignored.
+// A handler with a non-positive weight is not load-balanced, until its weight is made positive through SetWeight.
 func (b *Balancer) AddService(name string, handler http.Handler, weight *int) {
 	w := 1
 	if weight != nil {
 		w = *weight
 	}
+
+	h := &namedHandler{Handler: handler, name: name, weight: float64(w)}
+
 	if w <= 0 { // non-positive weight is meaningless
+		b.mutex.Lock()
+		b.idleHandlers[name] = h
+		b.mutex.Unlock()
 		return
 	}
 
-	h := &namedHandler{Handler: handler, name: name, weight: float64(w)}
-
 	b.mutex.Lock()
 	h.deadline = b.curDeadline + 1/h.weight
 	heap.Push(b, h)
 	b.status[name] = struct{}{}
 	b.mutex.Unlock()
 }
+
+// AddMatch makes the requests selected by the given match always forwarded to the given child service, whatever its weight.
+// The matches are evaluated in the order they are added.
+func (b *Balancer) AddMatch(name string, match *dynamic.RequestMatch) {
+	b.matches = append(b.matches, serviceMatch{name: name, match: match})
+}
+
+// SetWeight sets the weight of the given child service.
+// A child service with a non-positive weight is not load-balanced anymore.
+func (b *Balancer) SetWeight(name string, weight float64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if h, ok := b.idleHandlers[name]; ok {
+		h.weight = weight
+		if weight <= 0 {
+			return
+		}
+
+		delete(b.idleHandlers, name)
+		h.deadline = b.curDeadline + 1/h.weight
+		heap.Push(b, h)
+		b.status[name] = struct{}{}
+		return
+	}
+
+	for i, h := range b.handlers {
+		if h.name != name {
+			continue
+		}
+
+		h.weight = weight
+		if weight <= 0 {
+			heap.Remove(b, i)
+			delete(b.status, name)
+			b.idleHandlers[name] = h
+			return
+		}
+
+		// The new deadline gives the handler a fair competition with the other ones, as when it is added.
+		h.deadline = b.curDeadline + 1/h.weight
+		heap.Fix(b, i)
+		return
+	}
+}
@@ -8,6 +8,10 @@ import (
 	"github.com/traefik/traefik/v2/pkg/config/static"
 	"github.com/traefik/traefik/v2/pkg/metrics"
 	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/server/service/ramp"
+	"github.com/traefik/traefik/v2/pkg/server/service/slowstart"
+	"github.com/traefik/traefik/v2/pkg/server/service/tcp"
+	"github.com/traefik/traefik/v2/pkg/server/service/warmup"
 )
 
 // ManagerFactory a factory of service manager.
@@ -15,8 +19,12 @@ type ManagerFactory struct {
 	metricsRegistry metrics.Registry
 
 	roundTripperManager *RoundTripperManager
+	rampController      *ramp.Controller
+	warmUpController    *warmup.Controller
+	slowStartController *slowstart.Controller
 
 	api              func(configuration *runtime.Configuration) http.Handler
+	scopedAPIs       map[string]func(configuration *runtime.Configuration) http.Handler
 	restHandler      http.Handler
 	dashboardHandler http.Handler
 	metricsHandler   http.Handler
@@ -27,16 +35,27 @@ type ManagerFactory struct {
 }
 
 // NewManagerFactory creates a new ManagerFactory.
-func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, roundTripperManager *RoundTripperManager, acmeHTTPHandler http.Handler) *ManagerFactory {
+func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *safe.Pool, metricsRegistry metrics.Registry, roundTripperManager *RoundTripperManager, acmeHTTPHandler http.Handler, accountKeyRotators map[string]api.AccountKeyRotator, configurationHistory api.ConfigurationHistory, staticReloader api.StaticConfigurationReloader, cluster api.ClusterMembership, trafficStatistics api.TrafficStatistics) *ManagerFactory {
 	factory := &ManagerFactory{
 		metricsRegistry:     metricsRegistry,
 		routinesPool:        routinesPool,
 		roundTripperManager: roundTripperManager,
+		rampController:      ramp.NewController(routinesPool),
+		warmUpController:    warmup.NewController(routinesPool),
+		slowStartController: slowstart.NewController(routinesPool),
 		acmeHTTPHandler:     acmeHTTPHandler,
 	}
 
 	if staticConfiguration.API != nil {
-		factory.api = api.NewBuilder(staticConfiguration)
+		factory.api = api.NewBuilder(staticConfiguration, accountKeyRotators, configurationHistory, staticReloader, cluster, trafficStatistics)
+
+		factory.scopedAPIs = make(map[string]func(configuration *runtime.Configuration) http.Handler)
+		for name, scope := range staticConfiguration.API.Scopes {
+			if scope == nil {
+				scope = &static.APIScope{}
+			}
+			factory.scopedAPIs[name] = api.NewScopedBuilder(staticConfiguration, *scope)
+		}
 
 		if staticConfiguration.API.Dashboard {
 			factory.dashboardHandler = api.DashboardHandler{Assets: staticConfiguration.API.DashboardAssets}
@@ -64,11 +83,24 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 // Build creates a service manager.
 func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHandlers {
 	svcManager := NewManager(configuration.Services, f.metricsRegistry, f.routinesPool, f.roundTripperManager)
+	svcManager.rampController = f.rampController
+	svcManager.warmUpController = f.warmUpController
+	svcManager.slowStartController = f.slowStartController
 
 	var apiHandler http.Handler
 	if f.api != nil {
 		apiHandler = f.api(configuration)
 	}
 
-	return NewInternalHandlers(svcManager, apiHandler, f.restHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, f.acmeHTTPHandler)
+	scopedAPIHandlers := make(map[string]http.Handler, len(f.scopedAPIs))
+	for name, builder := range f.scopedAPIs {
+		scopedAPIHandlers[name] = builder(configuration)
+	}
+
+	return NewInternalHandlers(svcManager, apiHandler, scopedAPIHandlers, f.restHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, f.acmeHTTPHandler)
+}
+
+// BuildTCP creates a TCP service manager.
+func (f *ManagerFactory) BuildTCP(configuration *runtime.Configuration) *tcp.Manager {
+	return tcp.NewManager(configuration, f.routinesPool)
 }
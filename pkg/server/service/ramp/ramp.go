@@ -0,0 +1,246 @@
+package ramp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+// Balancer is a load balancer whose child services weights can be changed.
+type Balancer interface {
+	SetWeight(name string, weight float64)
+}
+
+// Target is a weighted service whose weight ramp is handled by the Controller,
+// as built from one version of the configuration.
+type Target struct {
+	config      dynamic.WeightRamp
+	startWeight int
+	info        *runtime.ServiceInfo
+	balancers   []Balancer
+
+	requests int64
+	errors   int64
+}
+
+// NewTarget creates a Target, ramping the weight of a service configured with startWeight.
+// The info of the weighted service, if any, is updated with the progress of the ramp.
+func NewTarget(config dynamic.WeightRamp, startWeight int, info *runtime.ServiceInfo) *Target {
+	return &Target{
+		config:      config,
+		startWeight: startWeight,
+		info:        info,
+	}
+}
+
+// AddBalancer adds a balancer of the weighted service,
+// as there is one per reference to the service.
+func (t *Target) AddBalancer(balancer Balancer) {
+	t.balancers = append(t.balancers, balancer)
+}
+
+// WrapHandler wraps the handler of the ramped service, to measure its ratio of 5XX responses.
+func (t *Target) WrapHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		recorder := &codeRecorder{ResponseWriter: rw, code: http.StatusOK}
+		next.ServeHTTP(recorder, req)
+
+		atomic.AddInt64(&t.requests, 1)
+		if recorder.code >= http.StatusInternalServerError {
+			atomic.AddInt64(&t.errors, 1)
+		}
+	})
+}
+
+// resetCounts returns the number of requests and 5XX responses of the ramped service since the last call.
+func (t *Target) resetCounts() (int64, int64) {
+	return atomic.SwapInt64(&t.requests, 0), atomic.SwapInt64(&t.errors, 0)
+}
+
+// Controller changes the weights of the ramped services over time.
+// The progress of a ramp is kept across the configuration reloads, as long as its configuration does not change.
+type Controller struct {
+	routinesPool *safe.Pool
+
+	mu     sync.Mutex
+	ramps  map[string]*ramp
+	cancel context.CancelFunc
+}
+
+type ramp struct {
+	config      dynamic.WeightRamp
+	startWeight int
+	startedAt   time.Time
+	state       string
+	weight      float64
+	progress    float64
+	errorRatio  float64
+	target      *Target
+}
+
+// NewController creates a Controller.
+func NewController(routinesPool *safe.Pool) *Controller {
+	return &Controller{
+		routinesPool: routinesPool,
+		ramps:        make(map[string]*ramp),
+	}
+}
+
+// SetTargets replaces the ramped services, keyed by service name, and ramps their weights.
+func (c *Controller) SetTargets(targets map[string]*Target) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+
+	ramps := make(map[string]*ramp, len(targets))
+	for name, target := range targets {
+		r, ok := c.ramps[name]
+		if !ok || r.config != target.config || r.startWeight != target.startWeight {
+			r = &ramp{
+				config:      target.config,
+				startWeight: target.startWeight,
+				startedAt:   time.Now(),
+				state:       runtime.RampRunning,
+				weight:      float64(target.startWeight),
+			}
+
+			log.WithoutContext().WithField(log.ServiceName, name).
+				Infof("Starting the weight ramp of %s from %d to %d", r.config.Service, r.startWeight, r.config.TargetWeight)
+		}
+
+		r.target = target
+		r.apply()
+		ramps[name] = r
+
+		if r.state == runtime.RampRunning {
+			c.launch(ctx, name, r)
+		}
+	}
+
+	c.ramps = ramps
+}
+
+func (c *Controller) launch(ctx context.Context, name string, r *ramp) {
+	c.routinesPool.GoCtx(func(routineCtx context.Context) {
+		ticker := time.NewTicker(time.Duration(r.config.Interval))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-routineCtx.Done():
+				return
+			case now := <-ticker.C:
+				if !c.step(ctx, name, r, now) {
+					return
+				}
+			}
+		}
+	})
+}
+
+// step updates the weight of the ramped service at the given time,
+// and returns whether the ramp is still running.
+func (c *Controller) step(ctx context.Context, name string, r *ramp, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The targets have been replaced in the meantime.
+	if ctx.Err() != nil {
+		return false
+	}
+
+	logger := log.WithoutContext().WithField(log.ServiceName, name)
+
+	requests, errors := r.target.resetCounts()
+
+	r.errorRatio = 0
+	if requests > 0 {
+		r.errorRatio = float64(errors) / float64(requests)
+	}
+
+	if r.config.MaxErrorRatio > 0 && r.errorRatio > r.config.MaxErrorRatio {
+		r.state = runtime.RampAborted
+		r.weight = float64(r.startWeight)
+		r.apply()
+
+		logger.Warnf("Weight ramp of %s aborted: error ratio %.2f above %.2f, restoring weight %d",
+			r.config.Service, r.errorRatio, r.config.MaxErrorRatio, r.startWeight)
+		return false
+	}
+
+	r.progress = 1
+	if r.config.Duration > 0 && now.Sub(r.startedAt) < time.Duration(r.config.Duration) {
+		r.progress = float64(now.Sub(r.startedAt)) / float64(r.config.Duration)
+	}
+
+	r.weight = float64(r.startWeight) + float64(r.config.TargetWeight-r.startWeight)*r.progress
+	if r.progress >= 1 {
+		r.state = runtime.RampCompleted
+		logger.Infof("Weight ramp of %s completed", r.config.Service)
+	}
+
+	r.apply()
+
+	return r.state == runtime.RampRunning
+}
+
+// apply sets the current weight on the balancers of the target, and reports the progress of the ramp.
+func (r *ramp) apply() {
+	for _, balancer := range r.target.balancers {
+		balancer.SetWeight(r.config.Service, r.weight)
+	}
+
+	if r.target.info == nil {
+		return
+	}
+
+	r.target.info.UpdateRampStatus(runtime.RampStatus{
+		Service:    r.config.Service,
+		State:      r.state,
+		Weight:     r.weight,
+		Progress:   r.progress,
+		ErrorRatio: r.errorRatio,
+		StartedAt:  r.startedAt,
+	})
+}
+
+// codeRecorder captures the status code of the response.
+type codeRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+// WriteHeader captures the status code for later retrieval.
+func (r *codeRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Hijack hijacks the connection.
+func (r *codeRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+// Flush sends any buffered data to the client.
+func (r *codeRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
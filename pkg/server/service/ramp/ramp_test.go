@@ -0,0 +1,172 @@
+package ramp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/config/runtime"
+	"github.com/traefik/traefik/v2/pkg/safe"
+)
+
+type balancerMock map[string]float64
+
+func (b balancerMock) SetWeight(name string, weight float64) {
+	b[name] = weight
+}
+
+func TestController(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		maxErrorRatio    float64
+		errors           int
+		elapsed          time.Duration
+		expectedState    string
+		expectedWeight   float64
+		expectedProgress float64
+	}{
+		{
+			desc:             "halfway",
+			elapsed:          5 * time.Minute,
+			expectedState:    runtime.RampRunning,
+			expectedWeight:   25.5,
+			expectedProgress: 0.5,
+		},
+		{
+			desc:             "completed",
+			elapsed:          15 * time.Minute,
+			expectedState:    runtime.RampCompleted,
+			expectedWeight:   50,
+			expectedProgress: 1,
+		},
+		{
+			desc:             "error ratio below the threshold",
+			maxErrorRatio:    0.5,
+			errors:           1,
+			elapsed:          5 * time.Minute,
+			expectedState:    runtime.RampRunning,
+			expectedWeight:   25.5,
+			expectedProgress: 0.5,
+		},
+		{
+			desc:           "aborted",
+			maxErrorRatio:  0.2,
+			errors:         3,
+			elapsed:        5 * time.Minute,
+			expectedState:  runtime.RampAborted,
+			expectedWeight: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := dynamic.WeightRamp{
+				Service:       "canary",
+				TargetWeight:  50,
+				Duration:      ptypes.Duration(10 * time.Minute),
+				Interval:      ptypes.Duration(time.Hour),
+				MaxErrorRatio: test.maxErrorRatio,
+			}
+
+			info := &runtime.ServiceInfo{}
+			balancer := balancerMock{}
+
+			target := NewTarget(config, 1, info)
+			target.AddBalancer(balancer)
+
+			handler := target.WrapHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("X-Fail") != "" {
+					rw.WriteHeader(http.StatusBadGateway)
+				}
+			}))
+
+			for i := 0; i < 10; i++ {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if i < test.errors {
+					req.Header.Set("X-Fail", "true")
+				}
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+
+			controller := NewController(safe.NewPool(ctx))
+			controller.SetTargets(map[string]*Target{"foo@file": target})
+			t.Cleanup(func() { controller.SetTargets(nil) })
+
+			assert.Equal(t, 1.0, balancer["canary"])
+
+			r := controller.ramps["foo@file"]
+			require.NotNil(t, r)
+
+			running := controller.step(context.Background(), "foo@file", r, r.startedAt.Add(test.elapsed))
+			assert.Equal(t, test.expectedState == runtime.RampRunning, running)
+
+			assert.Equal(t, test.expectedWeight, balancer["canary"])
+
+			status := info.GetRampStatus()
+			require.NotNil(t, status)
+			assert.Equal(t, "canary", status.Service)
+			assert.Equal(t, test.expectedState, status.State)
+			assert.Equal(t, test.expectedWeight, status.Weight)
+			assert.Equal(t, test.expectedProgress, status.Progress)
+			assert.Equal(t, float64(test.errors)/10, status.ErrorRatio)
+		})
+	}
+}
+
+func TestController_SetTargets(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	controller := NewController(safe.NewPool(ctx))
+	t.Cleanup(func() { controller.SetTargets(nil) })
+
+	config := dynamic.WeightRamp{
+		Service:      "canary",
+		TargetWeight: 11,
+		Duration:     ptypes.Duration(10 * time.Minute),
+		Interval:     ptypes.Duration(time.Hour),
+	}
+
+	controller.SetTargets(map[string]*Target{"foo@file": NewTarget(config, 1, nil)})
+
+	r := controller.ramps["foo@file"]
+	require.NotNil(t, r)
+
+	controller.step(context.Background(), "foo@file", r, r.startedAt.Add(5*time.Minute))
+
+	// The progress is kept when the configuration is reloaded without changes.
+	balancer := balancerMock{}
+	target := NewTarget(config, 1, nil)
+	target.AddBalancer(balancer)
+
+	controller.SetTargets(map[string]*Target{"foo@file": target})
+
+	assert.Same(t, r, controller.ramps["foo@file"])
+	assert.Equal(t, 6.0, balancer["canary"])
+
+	// The ramp starts over when its configuration changes.
+	config.TargetWeight = 21
+	balancer = balancerMock{}
+	target = NewTarget(config, 1, nil)
+	target.AddBalancer(balancer)
+
+	controller.SetTargets(map[string]*Target{"foo@file": target})
+
+	assert.NotSame(t, r, controller.ramps["foo@file"])
+	assert.Equal(t, 1.0, balancer["canary"])
+
+	controller.SetTargets(nil)
+	assert.Empty(t, controller.ramps)
+}
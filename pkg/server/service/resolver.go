@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"golang.org/x/sync/singleflight"
+)
+
+// dnsNegativeCacheTTL is how long a failed resolution of a backend hostname is remembered.
+const dnsNegativeCacheTTL = 2 * time.Second
+
+type resolutionFailure struct {
+	err     error
+	expires time.Time
+}
+
+// resolver resolves the backend hostnames for the dialers of the round trippers.
+// Concurrent resolutions of the same hostname are shared,
+// and failed resolutions are remembered for a short time,
+// so that an upstream DNS incident does not pile up lookups and timeouts for each request.
+type resolver struct {
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+	negativeTTL  time.Duration
+
+	group singleflight.Group
+
+	failuresMu sync.RWMutex
+	failures   map[string]resolutionFailure
+}
+
+func newResolver(negativeTTL time.Duration) *resolver {
+	return &resolver{
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+		negativeTTL:  negativeTTL,
+		failures:     make(map[string]resolutionFailure),
+	}
+}
+
+// DialContext returns a dial function which resolves the hostnames with the resolver, and dials with the given dialer.
+// The timeout of the dialer bounds the resolution and the dials of all the resolved addresses,
+// and each address gets a share of the remaining time, as done by the dialer for the hostnames it resolves.
+func (r *resolver) DialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		if dialer.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, dialer.Timeout)
+			defer cancel()
+		}
+
+		ips, err := r.lookup(ctx, host, dialer.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		var addrs []string
+		for _, ip := range ips {
+			if network == "tcp4" && ip.IP.To4() == nil || network == "tcp6" && ip.IP.To4() != nil {
+				continue
+			}
+			addrs = append(addrs, net.JoinHostPort(ip.String(), port))
+		}
+
+		if len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no suitable address found", Name: host, IsNotFound: true}
+		}
+
+		var firstErr error
+		for i, address := range addrs {
+			dialCtx, cancel := partialDeadline(ctx, len(addrs)-i)
+			conn, err := dialer.DialContext(dialCtx, network, address)
+			cancel()
+			if err == nil {
+				return conn, nil
+			}
+
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		return nil, firstErr
+	}
+}
+
+// minDialTimeout is the minimum time given to the dial of an address, unless less time remains.
+const minDialTimeout = 2 * time.Second
+
+// partialDeadline returns a context whose deadline is a share of the time remaining before the deadline of ctx,
+// for the dial of one of the given number of remaining addresses.
+func partialDeadline(ctx context.Context, addrsRemaining int) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+
+	timeRemaining := time.Until(deadline)
+	timeout := timeRemaining / time.Duration(addrsRemaining)
+	if timeout < minDialTimeout {
+		timeout = minDialTimeout
+		if timeRemaining < timeout {
+			timeout = timeRemaining
+		}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (r *resolver) lookup(ctx context.Context, host string, timeout time.Duration) ([]net.IPAddr, error) {
+	r.failuresMu.RLock()
+	failure, ok := r.failures[host]
+	r.failuresMu.RUnlock()
+
+	if ok && time.Now().Before(failure.expires) {
+		return nil, failure.err
+	}
+
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	ch := r.group.DoChan(host, func() (interface{}, error) {
+		// The lookup is shared by all the callers, so it must not be canceled with the context of one of them.
+		lookupCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		ips, err := r.lookupIPAddr(lookupCtx, host)
+
+		now := time.Now()
+
+		r.failuresMu.Lock()
+		// The expired failures are removed, so that the hostnames which are no longer used are not remembered forever.
+		for failedHost, failure := range r.failures {
+			if !now.Before(failure.expires) {
+				delete(r.failures, failedHost)
+			}
+		}
+		if err != nil {
+			r.failures[host] = resolutionFailure{err: err, expires: now.Add(r.negativeTTL)}
+		} else {
+			delete(r.failures, host)
+		}
+		r.failuresMu.Unlock()
+
+		return ips, err
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]net.IPAddr), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// resolutionFailuresRoundTripper counts the requests which failed because the backend hostname could not be resolved.
+type resolutionFailuresRoundTripper struct {
+	next    http.RoundTripper
+	counter gokitmetrics.Counter
+}
+
+func newResolutionFailuresRoundTripper(next http.RoundTripper, counter gokitmetrics.Counter) http.RoundTripper {
+	return &resolutionFailuresRoundTripper{next: next, counter: counter}
+}
+
+func (r *resolutionFailuresRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+
+	var dnsErr *net.DNSError
+	if err != nil && errors.As(err, &dnsErr) {
+		r.counter.Add(1)
+	}
+
+	return resp, err
+}
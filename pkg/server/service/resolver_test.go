@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_DialContext(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	var lookups int32
+	r := newResolver(time.Minute)
+	r.lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		atomic.AddInt32(&lookups, 1)
+		if host != "backend.local" {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+
+	dial := r.DialContext(&net.Dialer{Timeout: time.Second})
+
+	conn, err := dial(context.Background(), "tcp", net.JoinHostPort("backend.local", port))
+	require.NoError(t, err)
+	_ = conn.Close()
+
+	// Successful resolutions are not cached.
+	conn, err = dial(context.Background(), "tcp", net.JoinHostPort("backend.local", port))
+	require.NoError(t, err)
+	_ = conn.Close()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&lookups))
+
+	// IP addresses are not resolved.
+	conn, err = dial(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	_ = conn.Close()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&lookups))
+
+	// Failed resolutions are cached.
+	for i := 0; i < 3; i++ {
+		_, err = dial(context.Background(), "tcp", net.JoinHostPort("unknown.local", port))
+		var dnsErr *net.DNSError
+		require.True(t, errors.As(err, &dnsErr))
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&lookups))
+}
+
+func TestResolver_negativeCacheExpiration(t *testing.T) {
+	var lookups int32
+	r := newResolver(50 * time.Millisecond)
+	r.lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		atomic.AddInt32(&lookups, 1)
+		return nil, &net.DNSError{Err: "server misbehaving", Name: host, IsTemporary: true}
+	}
+
+	_, err := r.lookup(context.Background(), "backend.local", time.Second)
+	require.Error(t, err)
+	_, err = r.lookup(context.Background(), "backend.local", time.Second)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&lookups))
+
+	time.Sleep(100 * time.Millisecond)
+
+	// The expired failures are removed when another failure is remembered.
+	_, err = r.lookup(context.Background(), "other.local", time.Second)
+	require.Error(t, err)
+	r.failuresMu.RLock()
+	assert.Len(t, r.failures, 1)
+	assert.Contains(t, r.failures, "other.local")
+	r.failuresMu.RUnlock()
+
+	_, err = r.lookup(context.Background(), "backend.local", time.Second)
+	require.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&lookups))
+}
+
+func Test_partialDeadline(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		timeout        time.Duration
+		addrsRemaining int
+		expected       time.Duration
+	}{
+		{
+			desc:           "no deadline",
+			addrsRemaining: 2,
+		},
+		{
+			desc:           "share of the remaining time",
+			timeout:        10 * time.Second,
+			addrsRemaining: 2,
+			expected:       5 * time.Second,
+		},
+		{
+			desc:           "minimum dial timeout",
+			timeout:        5 * time.Second,
+			addrsRemaining: 5,
+			expected:       minDialTimeout,
+		},
+		{
+			desc:           "less time remaining than the minimum",
+			timeout:        time.Second,
+			addrsRemaining: 3,
+			expected:       time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if test.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.timeout)
+				defer cancel()
+			}
+
+			dialCtx, cancel := partialDeadline(ctx, test.addrsRemaining)
+			defer cancel()
+
+			deadline, ok := dialCtx.Deadline()
+			if test.expected == 0 {
+				assert.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			assert.InDelta(t, test.expected, time.Until(deadline), float64(100*time.Millisecond))
+		})
+	}
+}
+
+func TestResolver_singleflight(t *testing.T) {
+	var lookups int32
+	release := make(chan struct{})
+
+	r := newResolver(time.Minute)
+	r.lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		atomic.AddInt32(&lookups, 1)
+		<-release
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ips, err := r.lookup(context.Background(), "backend.local", time.Second)
+			assert.NoError(t, err)
+			assert.Len(t, ips, 1)
+		}()
+	}
+
+	// Let the goroutines join the pending lookup.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&lookups))
+}
+
+func TestResolver_canceledCaller(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	r := newResolver(time.Minute)
+	r.lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		<-release
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := r.lookup(ctx, "backend.local", time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// collectingCounter is a metrics.Counter implementation that enables access to the counter value.
+type collectingCounter struct {
+	counterValue float64
+}
+
+func (c *collectingCounter) With(_ ...string) metrics.Counter {
+	return c
+}
+
+func (c *collectingCounter) Add(delta float64) {
+	c.counterValue += delta
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestResolutionFailuresRoundTripper(t *testing.T) {
+	counter := &collectingCounter{}
+
+	var err error
+	rt := newResolutionFailuresRoundTripper(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, err
+	}), counter)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://backend.local", nil)
+
+	err = &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "backend.local"}}
+	_, _ = rt.RoundTrip(req)
+
+	err = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	_, _ = rt.RoundTrip(req)
+
+	err = nil
+	_, _ = rt.RoundTrip(req)
+
+	assert.Equal(t, float64(1), counter.counterValue)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -30,15 +31,24 @@ func (t *h2cTransportWrapper) RoundTrip(req *http.Request) (*http.Response, erro
 func NewRoundTripperManager() *RoundTripperManager {
 	return &RoundTripperManager{
 		roundTrippers: make(map[string]http.RoundTripper),
+		http2Pools:    make(map[http2PoolKey]http.RoundTripper),
 		configs:       make(map[string]*dynamic.ServersTransport),
+		resolver:      newResolver(dnsNegativeCacheTTL),
 	}
 }
 
+type http2PoolKey struct {
+	name string
+	size int
+}
+
 // RoundTripperManager handles roundtripper for the reverse proxy.
 type RoundTripperManager struct {
 	rtLock        sync.RWMutex
 	roundTrippers map[string]http.RoundTripper
+	http2Pools    map[http2PoolKey]http.RoundTripper
 	configs       map[string]*dynamic.ServersTransport
+	resolver      *resolver
 }
 
 // Update updates the roundtrippers configurations.
@@ -51,6 +61,7 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 		if !ok {
 			delete(r.configs, configName)
 			delete(r.roundTrippers, configName)
+			r.deleteHTTP2Pools(configName)
 			continue
 		}
 
@@ -58,8 +69,10 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 			continue
 		}
 
+		r.deleteHTTP2Pools(configName)
+
 		var err error
-		r.roundTrippers[configName], err = createRoundTripper(newConfig)
+		r.roundTrippers[configName], err = createRoundTripper(newConfig, r.resolver)
 		if err != nil {
 			log.WithoutContext().Errorf("Could not configure HTTP Transport %s, fallback on default transport: %v", configName, err)
 			r.roundTrippers[configName] = http.DefaultTransport
@@ -72,7 +85,7 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 		}
 
 		var err error
-		r.roundTrippers[newConfigName], err = createRoundTripper(newConfig)
+		r.roundTrippers[newConfigName], err = createRoundTripper(newConfig, r.resolver)
 		if err != nil {
 			log.WithoutContext().Errorf("Could not configure HTTP Transport %s, fallback on default transport: %v", newConfigName, err)
 			r.roundTrippers[newConfigName] = http.DefaultTransport
@@ -98,11 +111,65 @@ func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
 	return nil, fmt.Errorf("servers transport not found %s", name)
 }
 
+// GetHTTP2Pool gets a roundtripper spreading the requests sent to each server
+// over a pool of size connections, created with the named servers transport configuration.
+func (r *RoundTripperManager) GetHTTP2Pool(name string, size int) (http.RoundTripper, error) {
+	if len(name) == 0 {
+		name = "default@internal"
+	}
+
+	if size < 1 {
+		return nil, fmt.Errorf("invalid HTTP/2 connection pool size %d", size)
+	}
+
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	key := http2PoolKey{name: name, size: size}
+	if rt, ok := r.http2Pools[key]; ok {
+		return rt, nil
+	}
+
+	config, ok := r.configs[name]
+	if !ok {
+		return nil, fmt.Errorf("servers transport not found %s", name)
+	}
+
+	// Without HTTP/2, the requests are not multiplexed and are already spread over the connections.
+	if config.DisableHTTP2 {
+		return r.roundTrippers[name], nil
+	}
+
+	var roundTrippers []http.RoundTripper
+	for i := 0; i < size; i++ {
+		rt, err := createRoundTripper(config, r.resolver)
+		if err != nil {
+			return nil, err
+		}
+
+		roundTrippers = append(roundTrippers, rt)
+	}
+
+	pool := newHTTP2Pool(roundTrippers)
+	r.http2Pools[key] = pool
+
+	return pool, nil
+}
+
+func (r *RoundTripperManager) deleteHTTP2Pools(name string) {
+	for key := range r.http2Pools {
+		if key.name == name {
+			delete(r.http2Pools, key)
+		}
+	}
+}
+
 // createRoundTripper creates an http.RoundTripper configured with the Transport configuration settings.
 // For the settings that can't be configured in Traefik it uses the default http.Transport settings.
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost in Traefik at this point in time.
 // Setting this value to the default of 100 could lead to confusing behavior and backwards compatibility issues.
-func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error) {
+// The backend hostnames are resolved with the given resolver, if any.
+func createRoundTripper(cfg *dynamic.ServersTransport, resolver *resolver) (http.RoundTripper, error) {
 	if cfg == nil {
 		return nil, errors.New("no transport configuration given")
 	}
@@ -116,9 +183,34 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 		dialer.Timeout = time.Duration(cfg.ForwardingTimeouts.DialTimeout)
 	}
 
+	dialContext := dialer.DialContext
+	if resolver != nil {
+		dialContext = resolver.DialContext(dialer)
+	}
+
+	proxy := http.ProxyFromEnvironment
+
+	// With an upstream proxy, the connections to the backend servers are tunneled through the proxy,
+	// whatever the protocol, instead of using the proxy of the environment.
+	h2cDial := func(netw, addr string, _ *tls.Config) (net.Conn, error) {
+		return net.Dial(netw, addr)
+	}
+	if cfg.Proxy != nil {
+		connectDialer, err := newConnectDialer(cfg.Proxy, dialContext)
+		if err != nil {
+			return nil, err
+		}
+
+		proxy = nil
+		dialContext = connectDialer.DialContext
+		h2cDial = func(netw, addr string, _ *tls.Config) (net.Conn, error) {
+			return connectDialer.DialContext(context.Background(), netw, addr)
+		}
+	}
+
 	transport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           dialer.DialContext,
+		Proxy:                 proxy,
+		DialContext:           dialContext,
 		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
@@ -130,6 +222,7 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 	if cfg.ForwardingTimeouts != nil {
 		transport.ResponseHeaderTimeout = time.Duration(cfg.ForwardingTimeouts.ResponseHeaderTimeout)
 		transport.IdleConnTimeout = time.Duration(cfg.ForwardingTimeouts.IdleConnTimeout)
+		transport.TLSHandshakeTimeout = time.Duration(cfg.ForwardingTimeouts.TLSHandshakeTimeout)
 	}
 
 	if cfg.InsecureSkipVerify || len(cfg.RootCAs) > 0 || len(cfg.ServerName) > 0 || len(cfg.Certificates) > 0 {
@@ -148,9 +241,7 @@ func createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error
 
 	transport.RegisterProtocol("h2c", &h2cTransportWrapper{
 		Transport: &http2.Transport{
-			DialTLS: func(netw, addr string, cfg *tls.Config) (net.Conn, error) {
-				return net.Dial(netw, addr)
-			},
+			DialTLS:   h2cDial,
 			AllowHTTP: true,
 		},
 	})
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/containous/alice"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/healthcheck"
@@ -20,11 +21,16 @@ import (
 	"github.com/traefik/traefik/v2/pkg/middlewares/emptybackendhandler"
 	metricsMiddle "github.com/traefik/traefik/v2/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v2/pkg/middlewares/pipelining"
+	"github.com/traefik/traefik/v2/pkg/middlewares/watchdog"
 	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/cookie"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
+	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/failover"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/mirror"
 	"github.com/traefik/traefik/v2/pkg/server/service/loadbalancer/wrr"
+	"github.com/traefik/traefik/v2/pkg/server/service/ramp"
+	"github.com/traefik/traefik/v2/pkg/server/service/slowstart"
+	"github.com/traefik/traefik/v2/pkg/server/service/warmup"
 	"github.com/vulcand/oxy/roundrobin"
 	"github.com/vulcand/oxy/roundrobin/stickycookie"
 )
@@ -39,6 +45,7 @@ const defaultMaxBodySize int64 = -1
 // RoundTripperGetter is a roundtripper getter interface.
 type RoundTripperGetter interface {
 	Get(name string) (http.RoundTripper, error)
+	GetHTTP2Pool(name string, size int) (http.RoundTripper, error)
 }
 
 // NewManager creates a new Manager.
@@ -50,6 +57,7 @@ func NewManager(configs map[string]*runtime.ServiceInfo, metricsRegistry metrics
 		roundTripperManager: roundTripperManager,
 		balancers:           make(map[string]healthcheck.Balancers),
 		configs:             configs,
+		rampTargets:         make(map[string]*ramp.Target),
 	}
 }
 
@@ -65,6 +73,15 @@ type Manager struct {
 	// which is why there is not just one Balancer per service name.
 	balancers map[string]healthcheck.Balancers
 	configs   map[string]*runtime.ServiceInfo
+	// rampController ramps the weights of the weighted services described by rampTargets, keyed by service name.
+	rampController *ramp.Controller
+	rampTargets    map[string]*ramp.Target
+	// warmUpController warms up the servers of the warmUpBalancers before they enter the rotation.
+	warmUpController *warmup.Controller
+	warmUpBalancers  []*warmup.Balancer
+	// slowStartController ramps up the weights of the new servers of the slowStartBalancers.
+	slowStartController *slowstart.Controller
+	slowStartBalancers  []*slowstart.Balancer
 }
 
 // BuildHTTP Creates a http.Handler for a service configuration.
@@ -116,6 +133,13 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 			conf.AddError(err, true)
 			return nil, err
 		}
+	case conf.Failover != nil:
+		var err error
+		lb, err = m.getFailoverServiceHandler(ctx, serviceName, conf.Failover)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 	default:
 		sErr := fmt.Errorf("the service %q does not have any type defined", serviceName)
 		conf.AddError(sErr, true)
@@ -142,7 +166,7 @@ func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.M
 			return nil, err
 		}
 
-		err = handler.AddMirror(mirrorHandler, mirrorConfig.Percent)
+		err = handler.AddMatchingMirror(mirrorHandler, mirrorConfig.Percent, mirrorConfig.Match)
 		if err != nil {
 			return nil, err
 		}
@@ -150,6 +174,60 @@ func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.M
 	return handler, nil
 }
 
+func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName string, config *dynamic.Failover) (http.Handler, error) {
+	var failoverGauge gokitmetrics.Gauge
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() && m.metricsRegistry.ServiceFailoverGauge() != nil {
+		failoverGauge = m.metricsRegistry.ServiceFailoverGauge().With("service", serviceName)
+	}
+
+	f := failover.New(config, failoverGauge)
+
+	serviceHandler, err := m.BuildHTTP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	f.SetHandler(serviceHandler)
+
+	updater, ok := serviceHandler.(healthcheck.StatusUpdater)
+	if !ok {
+		return nil, fmt.Errorf("child service %v of %v not a healthcheck.StatusUpdater (%T)", config.Service, serviceName, serviceHandler)
+	}
+
+	if err := updater.RegisterStatusUpdater(func(up bool) {
+		f.SetHandlerStatus(ctx, up)
+	}); err != nil {
+		return nil, fmt.Errorf("cannot register %v as updater for %v: %w", config.Service, serviceName, err)
+	}
+
+	fallbackHandler, err := m.BuildHTTP(ctx, config.Fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	f.SetFallbackHandler(fallbackHandler)
+
+	// The fallback service is always deemed up when its status is not needed.
+	if !f.WantsFallbackStatus() {
+		return f, nil
+	}
+
+	fallbackUpdater, ok := fallbackHandler.(healthcheck.StatusUpdater)
+	if !ok {
+		return nil, fmt.Errorf("child service %v of %v not a healthcheck.StatusUpdater (%T)", config.Fallback, serviceName, fallbackHandler)
+	}
+
+	if err := fallbackUpdater.RegisterStatusUpdater(func(up bool) {
+		f.SetFallbackStatus(ctx, up)
+	}); err != nil {
+		return nil, fmt.Errorf("cannot register %v as updater for %v: %w", config.Fallback, serviceName, err)
+	}
+
+	log.FromContext(ctx).Debugf("Child services %v and %v will update parent %v on status change", config.Service, config.Fallback, serviceName)
+
+	return f, nil
+}
+
 func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string, config *dynamic.WeightedRoundRobin) (http.Handler, error) {
 	// TODO Handle accesslog and metrics with multiple service name
 	if config.Sticky != nil && config.Sticky.Cookie != nil {
@@ -157,13 +235,34 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 	}
 
 	balancer := wrr.New(config.Sticky, config.HealthCheck)
+
+	var rampTarget *ramp.Target
+	if config.Ramp != nil {
+		var err error
+		rampTarget, err = m.getRampTarget(serviceName, config)
+		if err != nil {
+			return nil, err
+		}
+
+		rampTarget.AddBalancer(balancer)
+	}
+
 	for _, service := range config.Services {
 		serviceHandler, err := m.BuildHTTP(ctx, service.Name)
 		if err != nil {
 			return nil, err
 		}
 
-		balancer.AddService(service.Name, serviceHandler, service.Weight)
+		handler := serviceHandler
+		if rampTarget != nil && service.Name == config.Ramp.Service {
+			handler = rampTarget.WrapHandler(serviceHandler)
+		}
+
+		balancer.AddService(service.Name, handler, service.Weight)
+		if service.Match != nil {
+			balancer.AddMatch(service.Name, service.Match)
+		}
+
 		if config.HealthCheck == nil {
 			continue
 		}
@@ -186,6 +285,38 @@ func (m *Manager) getWRRServiceHandler(ctx context.Context, serviceName string,
 	return balancer, nil
 }
 
+func (m *Manager) getRampTarget(serviceName string, config *dynamic.WeightedRoundRobin) (*ramp.Target, error) {
+	if target, ok := m.rampTargets[serviceName]; ok {
+		return target, nil
+	}
+
+	if config.Ramp.TargetWeight < 0 {
+		return nil, fmt.Errorf("invalid ramp target weight: %d", config.Ramp.TargetWeight)
+	}
+
+	if config.Ramp.Interval <= 0 {
+		return nil, fmt.Errorf("invalid ramp interval: %s", config.Ramp.Interval)
+	}
+
+	for _, service := range config.Services {
+		if service.Name != config.Ramp.Service {
+			continue
+		}
+
+		startWeight := 1
+		if service.Weight != nil {
+			startWeight = *service.Weight
+		}
+
+		target := ramp.NewTarget(*config.Ramp, startWeight, m.configs[serviceName])
+		m.rampTargets[serviceName] = target
+
+		return target, nil
+	}
+
+	return nil, fmt.Errorf("ramped service %s is not one of the weighted services", config.Ramp.Service)
+}
+
 func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer) (http.Handler, error) {
 	if service.PassHostHeader == nil {
 		defaultPassHostHeader := true
@@ -196,11 +327,15 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		service.ServersTransport = provider.GetQualifiedName(ctx, service.ServersTransport)
 	}
 
-	roundTripper, err := m.roundTripperManager.Get(service.ServersTransport)
+	roundTripper, err := m.getRoundTripper(service)
 	if err != nil {
 		return nil, err
 	}
 
+	if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+		roundTripper = newResolutionFailuresRoundTripper(roundTripper, m.metricsRegistry.ServiceDNSFailuresCounter().With("service", serviceName))
+	}
+
 	fwd, err := buildProxy(service.PassHostHeader, service.ResponseForwarding, roundTripper, m.bufferPool)
 	if err != nil {
 		return nil, err
@@ -214,12 +349,12 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 		chain = chain.Append(metricsMiddle.WrapServiceHandler(ctx, m.metricsRegistry, serviceName))
 	}
 
-	handler, err := chain.Append(alHandler).Then(pipelining.New(ctx, fwd, "pipelining"))
+	handler, err := chain.Append(alHandler, watchdog.WrapServerHandler(serviceName)).Then(pipelining.New(ctx, fwd, "pipelining"))
 	if err != nil {
 		return nil, err
 	}
 
-	balancer, err := m.getLoadBalancer(ctx, serviceName, service, handler)
+	balancer, err := m.getLoadBalancer(ctx, serviceName, service, handler, roundTripper)
 	if err != nil {
 		return nil, err
 	}
@@ -231,6 +366,41 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 	return emptybackendhandler.New(balancer), nil
 }
 
+func (m *Manager) getRoundTripper(service *dynamic.ServersLoadBalancer) (http.RoundTripper, error) {
+	if service.HTTP2ConnectionPool != nil {
+		return m.roundTripperManager.GetHTTP2Pool(service.ServersTransport, service.HTTP2ConnectionPool.Size)
+	}
+
+	return m.roundTripperManager.Get(service.ServersTransport)
+}
+
+// LaunchRamps starts ramping the weights of the weighted services.
+func (m *Manager) LaunchRamps() {
+	if m.rampController == nil {
+		return
+	}
+
+	m.rampController.SetTargets(m.rampTargets)
+}
+
+// LaunchWarmUps stops the warm-ups of the servers which are not part of the configuration anymore.
+func (m *Manager) LaunchWarmUps() {
+	if m.warmUpController == nil {
+		return
+	}
+
+	m.warmUpController.SetBalancers(m.warmUpBalancers)
+}
+
+// LaunchSlowStarts forgets the slow starts of the servers which are not part of the configuration anymore.
+func (m *Manager) LaunchSlowStarts() {
+	if m.slowStartController == nil {
+		return
+	}
+
+	m.slowStartController.SetBalancers(m.slowStartBalancers)
+}
+
 // LaunchHealthCheck launches the health checks.
 func (m *Manager) LaunchHealthCheck() {
 	backendConfigs := make(map[string]*healthcheck.BackendConfig)
@@ -246,6 +416,7 @@ func (m *Manager) LaunchHealthCheck() {
 			continue
 		}
 		hcOpts.Transport, _ = m.roundTripperManager.Get(service.ServersTransport)
+		hcOpts.ServiceInfo = m.configs[serviceName]
 		log.FromContext(ctx).Debugf("Setting up healthcheck for service %s with %s", serviceName, *hcOpts)
 
 		backendConfigs[serviceName] = healthcheck.NewBackendConfig(*hcOpts, serviceName)
@@ -309,7 +480,7 @@ func buildHealthCheckOptions(ctx context.Context, lb healthcheck.Balancer, backe
 	}
 }
 
-func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer, fwd http.Handler) (healthcheck.BalancerStatusHandler, error) {
+func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, service *dynamic.ServersLoadBalancer, fwd http.Handler, roundTripper http.RoundTripper) (healthcheck.BalancerStatusHandler, error) {
 	logger := log.FromContext(ctx)
 	logger.Debug("Creating load-balancer")
 
@@ -326,11 +497,23 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 		}
 
 		// Sticky Cookie Value
+		var cv stickycookie.CookieValue
 		cv, err := stickycookie.NewFallbackValue(&stickycookie.RawValue{}, &stickycookie.HashValue{})
 		if err != nil {
 			return nil, err
 		}
 
+		if store := service.Sticky.Cookie.Store; store != nil && store.Redis != nil {
+			client, err := getRedisStickyStore(store.Redis)
+			if err != nil {
+				return nil, fmt.Errorf("error configuring sticky sessions store: %w", err)
+			}
+
+			cv = newStoreValue(client, cookieName, time.Duration(store.Redis.TTL), cv)
+
+			logger.Debugf("Sticky sessions stored in Redis at %s", store.Redis.Endpoints[0])
+		}
+
 		options = append(options, roundrobin.EnableStickySession(roundrobin.NewStickySessionWithOptions(cookieName, opts).SetCookieValue(cv)))
 
 		logger.Debugf("Sticky session cookie name: %v", cookieName)
@@ -341,12 +524,34 @@ func (m *Manager) getLoadBalancer(ctx context.Context, serviceName string, servi
 		return nil, err
 	}
 
-	lbsu := healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName], service.HealthCheck)
-	if err := m.upsertServers(ctx, lbsu, service.Servers); err != nil {
+	var balancer healthcheck.BalancerStatusHandler = healthcheck.NewLBStatusUpdater(lb, m.configs[serviceName], service.HealthCheck)
+
+	// The slow start of the servers begins once they are warmed up.
+	if service.SlowStart != nil && m.slowStartController != nil {
+		slowStartBalancer, err := m.slowStartController.NewBalancer(serviceName, *service.SlowStart, balancer)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring slow start for service %s: %w", serviceName, err)
+		}
+
+		m.slowStartBalancers = append(m.slowStartBalancers, slowStartBalancer)
+		balancer = slowStartBalancer
+	}
+
+	if service.WarmUp != nil && m.warmUpController != nil {
+		warmUpBalancer, err := m.warmUpController.NewBalancer(serviceName, *service.WarmUp, roundTripper, balancer)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring warm-up for service %s: %w", serviceName, err)
+		}
+
+		m.warmUpBalancers = append(m.warmUpBalancers, warmUpBalancer)
+		balancer = warmUpBalancer
+	}
+
+	if err := m.upsertServers(ctx, balancer, service.Servers); err != nil {
 		return nil, fmt.Errorf("error configuring load balancer for service %s: %w", serviceName, err)
 	}
 
-	return lbsu, nil
+	return balancer, nil
 }
 
 func (m *Manager) upsertServers(ctx context.Context, lb healthcheck.BalancerHandler, servers []dynamic.Server) error {
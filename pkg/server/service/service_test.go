@@ -6,9 +6,11 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
@@ -67,7 +69,7 @@ func TestGetLoadBalancer(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			handler, err := sm.getLoadBalancer(context.Background(), test.serviceName, test.service, test.fwd)
+			handler, err := sm.getLoadBalancer(context.Background(), test.serviceName, test.service, test.fwd, http.DefaultTransport)
 			if test.expectError {
 				require.Error(t, err)
 				assert.Nil(t, handler)
@@ -406,3 +408,144 @@ func TestMultipleTypeOnBuildHTTP(t *testing.T) {
 	_, err := manager.BuildHTTP(context.Background(), "test@file")
 	assert.Error(t, err, "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 }
+
+func TestManager_BuildHTTP_weightRamp(t *testing.T) {
+	stableWeight, canaryWeight := 99, 1
+
+	testCases := []struct {
+		desc          string
+		ramp          *dynamic.WeightRamp
+		expectedError bool
+	}{
+		{
+			desc: "valid ramp",
+			ramp: &dynamic.WeightRamp{Service: "canary", TargetWeight: 50, Interval: ptypes.Duration(time.Second)},
+		},
+		{
+			desc:          "unknown ramped service",
+			ramp:          &dynamic.WeightRamp{Service: "foo", TargetWeight: 50, Interval: ptypes.Duration(time.Second)},
+			expectedError: true,
+		},
+		{
+			desc:          "negative target weight",
+			ramp:          &dynamic.WeightRamp{Service: "canary", TargetWeight: -1, Interval: ptypes.Duration(time.Second)},
+			expectedError: true,
+		},
+		{
+			desc:          "missing interval",
+			ramp:          &dynamic.WeightRamp{Service: "canary", TargetWeight: 50},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			services := map[string]*runtime.ServiceInfo{
+				"weighted@file": {
+					Service: &dynamic.Service{
+						Weighted: &dynamic.WeightedRoundRobin{
+							Services: []dynamic.WRRService{
+								{Name: "stable", Weight: &stableWeight},
+								{Name: "canary", Weight: &canaryWeight},
+							},
+							Ramp: test.ramp,
+						},
+					},
+				},
+				"stable@file": {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{}}},
+				"canary@file": {Service: &dynamic.Service{LoadBalancer: &dynamic.ServersLoadBalancer{}}},
+			}
+
+			manager := NewManager(services, nil, nil, &RoundTripperManager{
+				roundTrippers: map[string]http.RoundTripper{
+					"default@internal": http.DefaultTransport,
+				},
+			})
+
+			_, err := manager.BuildHTTP(context.Background(), "weighted@file")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Len(t, manager.rampTargets, 1)
+			assert.Contains(t, manager.rampTargets, "weighted@file")
+		})
+	}
+}
+
+func TestManager_BuildHTTP_failover(t *testing.T) {
+	withHealthCheck := &dynamic.ServersLoadBalancer{HealthCheck: &dynamic.ServerHealthCheck{Path: "/health"}}
+
+	testCases := []struct {
+		desc          string
+		failover      *dynamic.Failover
+		main          *dynamic.ServersLoadBalancer
+		fallback      *dynamic.ServersLoadBalancer
+		expectedError bool
+	}{
+		{
+			desc:     "main service with health check",
+			failover: &dynamic.Failover{Service: "main", Fallback: "fallback"},
+			main:     withHealthCheck,
+			fallback: &dynamic.ServersLoadBalancer{},
+		},
+		{
+			desc:          "main service without health check",
+			failover:      &dynamic.Failover{Service: "main", Fallback: "fallback"},
+			main:          &dynamic.ServersLoadBalancer{},
+			fallback:      &dynamic.ServersLoadBalancer{},
+			expectedError: true,
+		},
+		{
+			desc:     "healthy fallback required",
+			failover: &dynamic.Failover{Service: "main", Fallback: "fallback", RequireHealthyFallback: true},
+			main:     withHealthCheck,
+			fallback: withHealthCheck,
+		},
+		{
+			desc:          "healthy fallback required without health check",
+			failover:      &dynamic.Failover{Service: "main", Fallback: "fallback", RequireHealthyFallback: true},
+			main:          withHealthCheck,
+			fallback:      &dynamic.ServersLoadBalancer{},
+			expectedError: true,
+		},
+		{
+			desc:          "unknown fallback service",
+			failover:      &dynamic.Failover{Service: "main", Fallback: "foo"},
+			main:          withHealthCheck,
+			fallback:      &dynamic.ServersLoadBalancer{},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			services := map[string]*runtime.ServiceInfo{
+				"failover@file": {Service: &dynamic.Service{Failover: test.failover}},
+				"main@file":     {Service: &dynamic.Service{LoadBalancer: test.main}},
+				"fallback@file": {Service: &dynamic.Service{LoadBalancer: test.fallback}},
+			}
+
+			manager := NewManager(services, nil, nil, &RoundTripperManager{
+				roundTrippers: map[string]http.RoundTripper{
+					"default@internal": http.DefaultTransport,
+				},
+			})
+
+			_, err := manager.BuildHTTP(context.Background(), "failover@file")
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package slowstart
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+// fullWeight is the weight of the servers out of their slow start.
+// The weight of a server in slow start increases from 1 to fullWeight.
+const fullWeight = 100
+
+const defaultInterval = time.Second
+
+// Balancer is a load-balancer whose servers receive a share of the traffic increasing linearly over the slow start,
+// after they are added to the load-balancer, at its creation or when back from a failed health check.
+type Balancer struct {
+	healthcheck.BalancerStatusHandler
+
+	controller  *Controller
+	serviceName string
+	duration    time.Duration
+
+	mu      sync.Mutex
+	servers map[string]*server
+}
+
+// server is a server of a Balancer, with its current weight.
+type server struct {
+	url     *url.URL
+	options []roundrobin.ServerOption
+	weight  int
+}
+
+// UpsertServer adds the given server to the underlying load-balancer, with the weight of its progress in the slow start.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	startedAt := b.controller.start(b.serviceName, u)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	srv := &server{url: u, options: options}
+	b.servers[u.String()] = srv
+
+	if err := b.upsert(srv, time.Since(startedAt)); err != nil {
+		return err
+	}
+
+	if srv.weight < fullWeight {
+		b.controller.launch()
+	}
+
+	return nil
+}
+
+// RemoveServer removes the given server from the underlying load-balancer,
+// and forgets its slow start, as a server removed by a failed health check is likely to have been restarted.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	delete(b.servers, u.String())
+	err := b.BalancerStatusHandler.RemoveServer(u)
+	b.mu.Unlock()
+
+	b.controller.forget(b.serviceName, u)
+
+	return err
+}
+
+// update updates the weights of the servers in slow start.
+func (b *Balancer) update(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, srv := range b.servers {
+		if srv.weight >= fullWeight {
+			continue
+		}
+
+		startedAt, ok := b.controller.startedAt(b.serviceName, srv.url)
+		if !ok {
+			// The slow start has been forgotten, e.g. by a new configuration, the server gets its full weight.
+			startedAt = time.Time{}
+		}
+
+		if err := b.upsert(srv, now.Sub(startedAt)); err != nil {
+			log.WithoutContext().WithField(log.ServiceName, b.serviceName).
+				Errorf("Error updating the weight of server %s: %v", srv.url, err)
+		}
+	}
+}
+
+// upsert upserts the server in the underlying load-balancer, with the weight reached after the given elapsed time.
+// The caller must hold the lock of the balancer.
+func (b *Balancer) upsert(srv *server, elapsed time.Duration) error {
+	weight := b.weight(elapsed)
+	if weight == srv.weight {
+		return nil
+	}
+
+	options := make([]roundrobin.ServerOption, 0, len(srv.options)+1)
+	options = append(options, srv.options...)
+	options = append(options, roundrobin.Weight(weight))
+
+	if err := b.BalancerStatusHandler.UpsertServer(srv.url, options...); err != nil {
+		return err
+	}
+
+	srv.weight = weight
+
+	return nil
+}
+
+// weight returns the weight of a server after the given time in slow start.
+func (b *Balancer) weight(elapsed time.Duration) int {
+	if elapsed >= b.duration {
+		return fullWeight
+	}
+
+	weight := int(fullWeight * float64(elapsed) / float64(b.duration))
+	if weight < 1 {
+		return 1
+	}
+
+	return weight
+}
+
+type serverKey struct {
+	serviceName string
+	server      string
+}
+
+// Controller updates the weights of the servers in slow start of the load-balancers.
+// The start times of the slow starts are kept across the configuration reloads,
+// so that the servers of the new load-balancers carry on with their slow start.
+type Controller struct {
+	routinesPool *safe.Pool
+	interval     time.Duration
+
+	mu        sync.Mutex
+	starts    map[serverKey]time.Time
+	balancers []*Balancer
+	running   bool
+}
+
+// NewController creates a Controller.
+func NewController(routinesPool *safe.Pool) *Controller {
+	return &Controller{
+		routinesPool: routinesPool,
+		interval:     defaultInterval,
+		starts:       make(map[serverKey]time.Time),
+	}
+}
+
+// NewBalancer wraps the given load-balancer of a service, so that its new servers go through a slow start.
+func (c *Controller) NewBalancer(serviceName string, config dynamic.SlowStart, lb healthcheck.BalancerStatusHandler) (*Balancer, error) {
+	if config.Duration <= 0 {
+		return nil, fmt.Errorf("invalid slow start duration: %s", time.Duration(config.Duration))
+	}
+
+	b := &Balancer{
+		BalancerStatusHandler: lb,
+		controller:            c,
+		serviceName:           serviceName,
+		duration:              time.Duration(config.Duration),
+		servers:               make(map[string]*server),
+	}
+
+	c.mu.Lock()
+	c.balancers = append(c.balancers, b)
+	c.mu.Unlock()
+
+	return b, nil
+}
+
+// SetBalancers replaces the load-balancers whose servers go through a slow start,
+// and forgets the slow starts of the servers which are not part of them anymore.
+func (c *Controller) SetBalancers(balancers []*Balancer) {
+	keys := make(map[serverKey]struct{})
+	for _, balancer := range balancers {
+		balancer.mu.Lock()
+		for srv := range balancer.servers {
+			keys[serverKey{serviceName: balancer.serviceName, server: srv}] = struct{}{}
+		}
+		balancer.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.starts {
+		if _, ok := keys[key]; !ok {
+			delete(c.starts, key)
+		}
+	}
+
+	c.balancers = append([]*Balancer(nil), balancers...)
+}
+
+// start returns the start time of the slow start of the server, which starts now if it is not known yet.
+func (c *Controller) start(serviceName string, u *url.URL) time.Time {
+	key := serverKey{serviceName: serviceName, server: u.String()}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	startedAt, ok := c.starts[key]
+	if !ok {
+		startedAt = time.Now()
+		c.starts[key] = startedAt
+
+		log.WithoutContext().WithField(log.ServiceName, serviceName).Debugf("Starting the slow start of server %s", key.server)
+	}
+
+	return startedAt
+}
+
+func (c *Controller) startedAt(serviceName string, u *url.URL) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	startedAt, ok := c.starts[serverKey{serviceName: serviceName, server: u.String()}]
+	return startedAt, ok
+}
+
+func (c *Controller) forget(serviceName string, u *url.URL) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.starts, serverKey{serviceName: serviceName, server: u.String()})
+}
+
+// launch starts updating the weights of the servers in slow start, if it is not already running.
+func (c *Controller) launch() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.running {
+		return
+	}
+	c.running = true
+
+	c.routinesPool.GoCtx(func(routineCtx context.Context) {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-routineCtx.Done():
+				c.mu.Lock()
+				c.running = false
+				c.mu.Unlock()
+				return
+			case now := <-ticker.C:
+				c.update(now)
+			}
+		}
+	})
+}
+
+// update updates the weights of the servers in slow start of all the load-balancers.
+func (c *Controller) update(now time.Time) {
+	c.mu.Lock()
+	balancers := append([]*Balancer(nil), c.balancers...)
+	c.mu.Unlock()
+
+	for _, balancer := range balancers {
+		balancer.update(now)
+	}
+}
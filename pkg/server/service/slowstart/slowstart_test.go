@@ -0,0 +1,213 @@
+package slowstart
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+func newRoundRobin(t *testing.T) *roundrobin.RoundRobin {
+	t.Helper()
+
+	lb, err := roundrobin.New(http.NotFoundHandler())
+	require.NoError(t, err)
+
+	return lb
+}
+
+func serverWeight(t *testing.T, lb *roundrobin.RoundRobin, u *url.URL) int {
+	t.Helper()
+
+	weight, ok := lb.ServerWeight(u)
+	require.True(t, ok)
+
+	return weight
+}
+
+func TestController_NewBalancer(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.SlowStart
+		expectedError bool
+	}{
+		{
+			desc:   "valid",
+			config: dynamic.SlowStart{Duration: ptypes.Duration(time.Minute)},
+		},
+		{
+			desc:          "no duration",
+			config:        dynamic.SlowStart{},
+			expectedError: true,
+		},
+		{
+			desc:          "negative duration",
+			config:        dynamic.SlowStart{Duration: ptypes.Duration(-time.Minute)},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			controller := NewController(safe.NewPool(context.Background()))
+
+			_, err := controller.NewBalancer("foo", test.config, healthcheck.NewLBStatusUpdater(newRoundRobin(t), nil, nil))
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBalancer_weight(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		elapsed  time.Duration
+		expected int
+	}{
+		{
+			desc:     "start",
+			elapsed:  0,
+			expected: 1,
+		},
+		{
+			desc:     "quarter",
+			elapsed:  15 * time.Second,
+			expected: 25,
+		},
+		{
+			desc:     "half",
+			elapsed:  30 * time.Second,
+			expected: 50,
+		},
+		{
+			desc:     "end",
+			elapsed:  time.Minute,
+			expected: fullWeight,
+		},
+		{
+			desc:     "after the end",
+			elapsed:  time.Hour,
+			expected: fullWeight,
+		},
+	}
+
+	balancer := &Balancer{duration: time.Minute}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, balancer.weight(test.elapsed))
+		})
+	}
+}
+
+func TestBalancer_slowStart(t *testing.T) {
+	pool := safe.NewPool(context.Background())
+	t.Cleanup(pool.Stop)
+
+	controller := NewController(pool)
+	config := dynamic.SlowStart{Duration: ptypes.Duration(time.Minute)}
+
+	serverURL, err := url.Parse("http://127.0.0.1:8080")
+	require.NoError(t, err)
+
+	lb := newRoundRobin(t)
+	balancer, err := controller.NewBalancer("foo", config, healthcheck.NewLBStatusUpdater(lb, nil, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL, roundrobin.Weight(1)))
+	controller.SetBalancers([]*Balancer{balancer})
+
+	assert.Equal(t, 1, serverWeight(t, lb, serverURL))
+
+	now := time.Now()
+
+	balancer.update(now.Add(30 * time.Second))
+	assert.InDelta(t, 50, serverWeight(t, lb, serverURL), 1)
+
+	// The load-balancer of a new configuration carries on with the slow start of the server.
+	newLB := newRoundRobin(t)
+	newBalancer, err := controller.NewBalancer("foo", config, healthcheck.NewLBStatusUpdater(newLB, nil, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, newBalancer.UpsertServer(serverURL, roundrobin.Weight(1)))
+	controller.SetBalancers([]*Balancer{newBalancer})
+
+	newBalancer.update(now.Add(45 * time.Second))
+	assert.InDelta(t, 75, serverWeight(t, newLB, serverURL), 1)
+
+	newBalancer.update(now.Add(2 * time.Minute))
+	assert.Equal(t, fullWeight, serverWeight(t, newLB, serverURL))
+
+	// A server removed by the health check goes through a new slow start when it comes back.
+	require.NoError(t, newBalancer.RemoveServer(serverURL))
+	assert.Empty(t, newLB.Servers())
+
+	require.NoError(t, newBalancer.UpsertServer(serverURL, roundrobin.Weight(1)))
+	assert.Equal(t, 1, serverWeight(t, newLB, serverURL))
+}
+
+func TestController_launch(t *testing.T) {
+	pool := safe.NewPool(context.Background())
+	t.Cleanup(pool.Stop)
+
+	controller := NewController(pool)
+	controller.interval = 10 * time.Millisecond
+
+	serverURL, err := url.Parse("http://127.0.0.1:8080")
+	require.NoError(t, err)
+
+	lb := newRoundRobin(t)
+	balancer, err := controller.NewBalancer("foo", dynamic.SlowStart{Duration: ptypes.Duration(200 * time.Millisecond)}, healthcheck.NewLBStatusUpdater(lb, nil, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL))
+	controller.SetBalancers([]*Balancer{balancer})
+
+	assert.Eventually(t, func() bool {
+		weight, _ := lb.ServerWeight(serverURL)
+		return weight == fullWeight
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestController_SetBalancers(t *testing.T) {
+	controller := NewController(safe.NewPool(context.Background()))
+
+	serverURL, err := url.Parse("http://127.0.0.1:8080")
+	require.NoError(t, err)
+
+	balancer, err := controller.NewBalancer("foo", dynamic.SlowStart{Duration: ptypes.Duration(time.Hour)}, healthcheck.NewLBStatusUpdater(newRoundRobin(t), nil, nil))
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL))
+	controller.SetBalancers([]*Balancer{balancer})
+
+	controller.mu.Lock()
+	assert.Len(t, controller.starts, 1)
+	controller.mu.Unlock()
+
+	// The server is not part of the configuration anymore.
+	controller.SetBalancers(nil)
+
+	controller.mu.Lock()
+	assert.Empty(t, controller.starts)
+	assert.Empty(t, controller.balancers)
+	controller.mu.Unlock()
+}
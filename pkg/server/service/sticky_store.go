@@ -0,0 +1,137 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/abronan/valkeyrie/store/redis"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/vulcand/oxy/roundrobin/stickycookie"
+)
+
+const stickyStoreRootKey = "traefik/sticky"
+
+// stickyStores holds the clients of the sticky sessions stores, keyed by endpoint and password,
+// so that they are shared by the services, and kept across the configuration reloads.
+var stickyStores = struct {
+	sync.Mutex
+	clients map[string]store.Store
+}{clients: make(map[string]store.Store)}
+
+// getRedisStickyStore returns the client of the Redis sticky sessions store described by the configuration.
+func getRedisStickyStore(config *dynamic.RedisCookieStore) (store.Store, error) {
+	if len(config.Endpoints) != 1 {
+		return nil, fmt.Errorf("exactly one Redis endpoint is required, got %d", len(config.Endpoints))
+	}
+
+	key := config.Endpoints[0] + "|" + config.Password
+
+	stickyStores.Lock()
+	defer stickyStores.Unlock()
+
+	if client, ok := stickyStores.clients[key]; ok {
+		return client, nil
+	}
+
+	client, err := redis.New(config.Endpoints, &store.Config{Password: config.Password})
+	if err != nil {
+		return nil, err
+	}
+
+	stickyStores.clients[key] = client
+
+	return client, nil
+}
+
+// storeValue is a sticky cookie value mapped to its server in an external store.
+// The cookie holds a random session ID, and the store maps it to the server URL,
+// so that all the Traefik instances sharing the store route a returning client to the same server.
+// When the store is unreachable, or does not know the session, the fallback cookie value is used instead.
+type storeValue struct {
+	store    store.Store
+	prefix   string
+	ttl      time.Duration
+	fallback stickycookie.CookieValue
+}
+
+func newStoreValue(kvStore store.Store, cookieName string, ttl time.Duration, fallback stickycookie.CookieValue) *storeValue {
+	return &storeValue{
+		store:    kvStore,
+		prefix:   stickyStoreRootKey + "/" + cookieName + "/",
+		ttl:      ttl,
+		fallback: fallback,
+	}
+}
+
+// Get creates a new session ID, and maps it to the server in the store.
+func (v *storeValue) Get(u *url.URL) string {
+	id, err := newSessionID()
+	if err != nil {
+		log.WithoutContext().Errorf("Unable to create sticky session ID: %v", err)
+		return v.fallback.Get(u)
+	}
+
+	server := normalizedURL(u)
+	if err := v.store.Put(v.prefix+id, []byte(server), &store.WriteOptions{TTL: v.ttl}); err != nil {
+		log.WithoutContext().Errorf("Unable to store sticky session for server %s: %v", server, err)
+		return v.fallback.Get(u)
+	}
+
+	return id
+}
+
+// FindURL returns the server mapped to the session ID in the store, if it is still one of the given servers.
+func (v *storeValue) FindURL(raw string, urls []*url.URL) (*url.URL, error) {
+	// The session IDs are hexadecimal, so that a cookie value cannot target another key of the store.
+	if !isSessionID(raw) {
+		return v.fallback.FindURL(raw, urls)
+	}
+
+	pair, err := v.store.Get(v.prefix+raw, nil)
+	if err != nil {
+		if !errors.Is(err, store.ErrKeyNotFound) {
+			log.WithoutContext().Errorf("Unable to get sticky session: %v", err)
+		}
+		return v.fallback.FindURL(raw, urls)
+	}
+
+	for _, u := range urls {
+		if normalizedURL(u) == string(pair.Value) {
+			return u, nil
+		}
+	}
+
+	return nil, nil
+}
+
+const sessionIDLength = 16
+
+func newSessionID() (string, error) {
+	id := make([]byte, sessionIDLength)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
+
+func isSessionID(value string) bool {
+	if len(value) != 2*sessionIDLength {
+		return false
+	}
+
+	return strings.Trim(value, "0123456789abcdef") == ""
+}
+
+func normalizedURL(u *url.URL) string {
+	normalized := url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}
+	return normalized.String()
+}
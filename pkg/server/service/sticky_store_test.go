@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abronan/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/vulcand/oxy/roundrobin/stickycookie"
+)
+
+// memoryStore is an in-memory store, only implementing the calls used by the sticky sessions.
+type memoryStore struct {
+	store.Store
+
+	mu    sync.Mutex
+	pairs map[string][]byte
+	ttls  map[string]time.Duration
+	err   error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		pairs: make(map[string][]byte),
+		ttls:  make(map[string]time.Duration),
+	}
+}
+
+func (s *memoryStore) Put(key string, value []byte, options *store.WriteOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.pairs[key] = value
+	s.ttls[key] = options.TTL
+
+	return nil
+}
+
+func (s *memoryStore) Get(key string, _ *store.ReadOptions) (*store.KVPair, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	value, ok := s.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return &store.KVPair{Key: key, Value: value}, nil
+}
+
+func TestStoreValue(t *testing.T) {
+	servers := []*url.URL{
+		{Scheme: "http", Host: "10.0.0.1:80"},
+		{Scheme: "http", Host: "10.0.0.2:80"},
+	}
+
+	kvStore := newMemoryStore()
+
+	// Two instances sharing the same store.
+	instanceA := newStoreValue(kvStore, "cookie", time.Hour, &stickycookie.HashValue{})
+	instanceB := newStoreValue(kvStore, "cookie", time.Hour, &stickycookie.HashValue{})
+
+	value := instanceA.Get(servers[1])
+	assert.True(t, isSessionID(value))
+	assert.Equal(t, time.Hour, kvStore.ttls["traefik/sticky/cookie/"+value])
+
+	server, err := instanceB.FindURL(value, servers)
+	require.NoError(t, err)
+	assert.Equal(t, servers[1], server)
+
+	// Each session gets its own ID.
+	assert.NotEqual(t, value, instanceA.Get(servers[1]))
+
+	// The server is not part of the load-balancer anymore.
+	server, err = instanceB.FindURL(value, servers[:1])
+	require.NoError(t, err)
+	assert.Nil(t, server)
+
+	// Unknown session.
+	server, err = instanceB.FindURL("0123456789abcdef0123456789abcdef", servers)
+	require.NoError(t, err)
+	assert.Nil(t, server)
+}
+
+func TestStoreValue_fallback(t *testing.T) {
+	servers := []*url.URL{
+		{Scheme: "http", Host: "10.0.0.1:80"},
+		{Scheme: "http", Host: "10.0.0.2:80"},
+	}
+
+	fallback := &stickycookie.HashValue{}
+
+	kvStore := newMemoryStore()
+	value := newStoreValue(kvStore, "cookie", time.Hour, fallback)
+
+	// Cookies set before the store was configured are still honored.
+	server, err := value.FindURL(fallback.Get(servers[1]), servers)
+	require.NoError(t, err)
+	assert.Equal(t, servers[1], server)
+
+	kvStore.err = errors.New("unreachable")
+
+	// The store is unreachable.
+	cookieValue := value.Get(servers[0])
+	assert.Equal(t, fallback.Get(servers[0]), cookieValue)
+
+	server, err = value.FindURL(cookieValue, servers)
+	require.NoError(t, err)
+	assert.Equal(t, servers[0], server)
+}
+
+func TestGetRedisStickyStore_invalidEndpoints(t *testing.T) {
+	_, err := getRedisStickyStore(&dynamic.RedisCookieStore{})
+	assert.Error(t, err)
+
+	_, err = getRedisStickyStore(&dynamic.RedisCookieStore{Endpoints: []string{"127.0.0.1:6379", "127.0.0.2:6379"}})
+	assert.Error(t, err)
+}
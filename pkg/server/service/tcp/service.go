@@ -9,19 +9,22 @@ import (
 
 	"github.com/traefik/traefik/v2/pkg/config/runtime"
 	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
 	"github.com/traefik/traefik/v2/pkg/server/provider"
 	"github.com/traefik/traefik/v2/pkg/tcp"
 )
 
 // Manager is the TCPHandlers factory.
 type Manager struct {
-	configs map[string]*runtime.TCPServiceInfo
+	configs     map[string]*runtime.TCPServiceInfo
+	routinePool *safe.Pool
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration) *Manager {
+func NewManager(conf *runtime.Configuration, routinePool *safe.Pool) *Manager {
 	return &Manager{
-		configs: conf.TCPServices,
+		configs:     conf.TCPServices,
+		routinePool: routinePool,
 	}
 }
 
@@ -53,6 +56,10 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		}
 		duration := time.Duration(*conf.LoadBalancer.TerminationDelay) * time.Millisecond
 
+		if conf.LoadBalancer.Retry != nil {
+			loadBalancer.SetRetry(m.routinePool.Ctx(), conf.LoadBalancer.Retry.Attempts, time.Duration(conf.LoadBalancer.Retry.InitialInterval))
+		}
+
 		for name, server := range conf.LoadBalancer.Servers {
 			if _, _, err := net.SplitHostPort(server.Address); err != nil {
 				logger.Errorf("In service %q: %v", serviceQualifiedName, err)
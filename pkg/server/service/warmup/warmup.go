@@ -0,0 +1,347 @@
+package warmup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/healthcheck"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/traefik/traefik/v2/pkg/types"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+const defaultRetryInterval = 10 * time.Second
+
+// Balancer is a load-balancer whose servers are warmed up before they enter the rotation.
+// The servers added to the load-balancer, at its creation or when back from a failed health check,
+// are only added to the underlying load-balancer once their warm-up succeeded.
+type Balancer struct {
+	healthcheck.BalancerStatusHandler
+
+	controller  *Controller
+	serviceName string
+	config      dynamic.WarmUp
+	statuses    types.HTTPCodeRanges
+	transport   http.RoundTripper
+
+	mu      sync.Mutex
+	servers map[string]struct{}
+}
+
+// UpsertServer warms up the given server, if needed, before adding it to the underlying load-balancer.
+func (b *Balancer) UpsertServer(u *url.URL, options ...roundrobin.ServerOption) error {
+	b.mu.Lock()
+	b.servers[u.String()] = struct{}{}
+	b.mu.Unlock()
+
+	return b.controller.warmUp(b, u, options)
+}
+
+// RemoveServer removes the given server from the underlying load-balancer,
+// and forgets its warm-up, as a server removed by a failed health check is likely to have been restarted.
+func (b *Balancer) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	delete(b.servers, u.String())
+	b.mu.Unlock()
+
+	b.controller.forget(b.serviceName, u)
+
+	return b.BalancerStatusHandler.RemoveServer(u)
+}
+
+type serverKey struct {
+	serviceName string
+	server      string
+}
+
+type server struct {
+	warmed  bool
+	cancel  context.CancelFunc
+	waiters []waiter
+}
+
+// waiter is a load-balancer waiting for the end of the warm-up of a server.
+type waiter struct {
+	balancer *Balancer
+	options  []roundrobin.ServerOption
+}
+
+// Controller warms up the servers of the load-balancers.
+// The warmed up servers are kept across the configuration reloads,
+// so that they enter the rotation of the new load-balancers right away.
+type Controller struct {
+	routinesPool  *safe.Pool
+	retryInterval time.Duration
+
+	mu      sync.Mutex
+	servers map[serverKey]*server
+}
+
+// NewController creates a Controller.
+func NewController(routinesPool *safe.Pool) *Controller {
+	return &Controller{
+		routinesPool:  routinesPool,
+		retryInterval: defaultRetryInterval,
+		servers:       make(map[serverKey]*server),
+	}
+}
+
+// NewBalancer wraps the given load-balancer of a service,
+// so that its servers are warmed up, through the given transport, before they enter the rotation.
+func (c *Controller) NewBalancer(serviceName string, config dynamic.WarmUp, transport http.RoundTripper, lb healthcheck.BalancerStatusHandler) (*Balancer, error) {
+	if config.Requests < 1 {
+		return nil, fmt.Errorf("invalid warm-up requests: %d", config.Requests)
+	}
+
+	if config.Concurrency < 1 {
+		return nil, fmt.Errorf("invalid warm-up concurrency: %d", config.Concurrency)
+	}
+
+	if config.MinSuccessRatio < 0 || config.MinSuccessRatio > 1 {
+		return nil, fmt.Errorf("invalid warm-up success ratio: %v", config.MinSuccessRatio)
+	}
+
+	status := config.Status
+	if len(status) == 0 {
+		status = []string{"200-399"}
+	}
+
+	statuses, err := types.NewHTTPCodeRanges(status)
+	if err != nil {
+		return nil, fmt.Errorf("invalid warm-up status: %w", err)
+	}
+
+	return &Balancer{
+		BalancerStatusHandler: lb,
+		controller:            c,
+		serviceName:           serviceName,
+		config:                config,
+		statuses:              statuses,
+		transport:             transport,
+		servers:               make(map[string]struct{}),
+	}, nil
+}
+
+// SetBalancers replaces the load-balancers whose servers are warmed up,
+// and stops the warm-ups of the servers which are not part of them anymore.
+func (c *Controller) SetBalancers(balancers []*Balancer) {
+	current := make(map[*Balancer]struct{}, len(balancers))
+	keys := make(map[serverKey]struct{})
+	for _, balancer := range balancers {
+		current[balancer] = struct{}{}
+
+		balancer.mu.Lock()
+		for srv := range balancer.servers {
+			keys[serverKey{serviceName: balancer.serviceName, server: srv}] = struct{}{}
+		}
+		balancer.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, srv := range c.servers {
+		if _, ok := keys[key]; !ok {
+			if srv.cancel != nil {
+				srv.cancel()
+			}
+
+			delete(c.servers, key)
+			continue
+		}
+
+		// The load-balancers of the previous configuration do not need the server anymore.
+		var waiters []waiter
+		for _, w := range srv.waiters {
+			if _, ok := current[w.balancer]; ok {
+				waiters = append(waiters, w)
+			}
+		}
+		srv.waiters = waiters
+	}
+}
+
+func (c *Controller) warmUp(b *Balancer, u *url.URL, options []roundrobin.ServerOption) error {
+	key := serverKey{serviceName: b.serviceName, server: u.String()}
+
+	c.mu.Lock()
+
+	srv, ok := c.servers[key]
+	if !ok {
+		srv = &server{}
+		c.servers[key] = srv
+	}
+
+	if srv.warmed {
+		c.mu.Unlock()
+		return b.BalancerStatusHandler.UpsertServer(u, options...)
+	}
+
+	srv.waiters = append(srv.waiters, waiter{balancer: b, options: options})
+
+	if srv.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		srv.cancel = cancel
+
+		c.routinesPool.GoCtx(func(routineCtx context.Context) {
+			c.run(ctx, routineCtx, key, b, u)
+		})
+	}
+
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Controller) forget(serviceName string, u *url.URL) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if srv, ok := c.servers[serverKey{serviceName: serviceName, server: u.String()}]; ok {
+		srv.warmed = false
+	}
+}
+
+// run warms up the server until it succeeds, then adds it to the waiting load-balancers.
+func (c *Controller) run(ctx, routineCtx context.Context, key serverKey, b *Balancer, u *url.URL) {
+	logger := log.WithoutContext().WithField(log.ServiceName, key.serviceName)
+
+	for {
+		logger.Debugf("Warming up server %s", key.server)
+
+		ratio := b.send(ctx, u)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if ratio >= b.config.MinSuccessRatio {
+			logger.Infof("Server %s warmed up (success ratio %.2f), adding it to the load-balancer", key.server, ratio)
+			c.done(ctx, key, u)
+			return
+		}
+
+		logger.Warnf("Warm-up of server %s failed: success ratio %.2f below %.2f, retrying in %s",
+			key.server, ratio, b.config.MinSuccessRatio, c.retryInterval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-routineCtx.Done():
+			return
+		case <-time.After(c.retryInterval):
+		}
+	}
+}
+
+// done marks the server as warmed up, and adds it to the waiting load-balancers.
+func (c *Controller) done(ctx context.Context, key serverKey, u *url.URL) {
+	c.mu.Lock()
+
+	srv, ok := c.servers[key]
+	// The warm-up has been stopped in the meantime.
+	if !ok || ctx.Err() != nil {
+		c.mu.Unlock()
+		return
+	}
+
+	srv.warmed = true
+	srv.cancel = nil
+	waiters := srv.waiters
+	srv.waiters = nil
+
+	c.mu.Unlock()
+
+	for _, w := range waiters {
+		if err := w.balancer.BalancerStatusHandler.UpsertServer(u, w.options...); err != nil {
+			log.WithoutContext().WithField(log.ServiceName, key.serviceName).
+				Errorf("Error adding warmed up server %s to the load-balancer: %v", key.server, err)
+		}
+	}
+}
+
+// send sends the warm-up requests to the server, and returns the ratio of successful ones.
+func (b *Balancer) send(ctx context.Context, u *url.URL) float64 {
+	paths := b.config.Paths
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+
+	requests := make(chan string)
+	go func() {
+		defer close(requests)
+
+		for _, path := range paths {
+			for i := 0; i < b.config.Requests; i++ {
+				select {
+				case requests <- path:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	var (
+		mu        sync.Mutex
+		successes int
+		wg        sync.WaitGroup
+	)
+
+	for i := 0; i < b.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for path := range requests {
+				if b.request(ctx, u, path) {
+					mu.Lock()
+					successes++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return float64(successes) / float64(len(paths)*b.config.Requests)
+}
+
+// request sends a warm-up request to the given path of the server, and tells whether it succeeded.
+func (b *Balancer) request(ctx context.Context, u *url.URL, path string) bool {
+	if b.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(b.config.Timeout))
+		defer cancel()
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(u.String(), "/")+path, nil)
+	if err != nil {
+		log.WithoutContext().WithField(log.ServiceName, b.serviceName).Debugf("Invalid warm-up request: %v", err)
+		return false
+	}
+
+	resp, err := b.transport.RoundTrip(req)
+	if err != nil {
+		log.WithoutContext().WithField(log.ServiceName, b.serviceName).Debugf("Warm-up request to %s failed: %v", req.URL, err)
+		return false
+	}
+
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return b.statuses.Contains(resp.StatusCode)
+}
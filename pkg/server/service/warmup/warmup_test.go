@@ -0,0 +1,225 @@
+package warmup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
+	"github.com/traefik/traefik/v2/pkg/safe"
+	"github.com/vulcand/oxy/roundrobin"
+)
+
+type balancerMock struct {
+	mu      sync.Mutex
+	servers []*url.URL
+}
+
+func (b *balancerMock) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+func (b *balancerMock) RegisterStatusUpdater(func(up bool)) error {
+	return nil
+}
+
+func (b *balancerMock) Servers() []*url.URL {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]*url.URL(nil), b.servers...)
+}
+
+func (b *balancerMock) RemoveServer(u *url.URL) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, srv := range b.servers {
+		if srv.String() == u.String() {
+			b.servers = append(b.servers[:i], b.servers[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (b *balancerMock) UpsertServer(u *url.URL, _ ...roundrobin.ServerOption) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.servers = append(b.servers, u)
+
+	return nil
+}
+
+func TestController_NewBalancer(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.WarmUp
+		expectedError bool
+	}{
+		{
+			desc:   "valid",
+			config: dynamic.WarmUp{Requests: 10, Concurrency: 2, MinSuccessRatio: 0.5, Status: []string{"200"}},
+		},
+		{
+			desc:          "no request",
+			config:        dynamic.WarmUp{Concurrency: 1, MinSuccessRatio: 1},
+			expectedError: true,
+		},
+		{
+			desc:          "no concurrency",
+			config:        dynamic.WarmUp{Requests: 10, MinSuccessRatio: 1},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid success ratio",
+			config:        dynamic.WarmUp{Requests: 10, Concurrency: 1, MinSuccessRatio: 2},
+			expectedError: true,
+		},
+		{
+			desc:          "invalid status",
+			config:        dynamic.WarmUp{Requests: 10, Concurrency: 1, MinSuccessRatio: 1, Status: []string{"foo"}},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			controller := NewController(safe.NewPool(context.Background()))
+
+			_, err := controller.NewBalancer("foo", test.config, http.DefaultTransport, &balancerMock{})
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBalancer_warmUp(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if req.URL.Path == "/fail" {
+			rw.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	config := dynamic.WarmUp{
+		Paths:           []string{"/foo", "bar"},
+		Requests:        5,
+		Concurrency:     2,
+		MinSuccessRatio: 1,
+	}
+
+	controller := NewController(safe.NewPool(context.Background()))
+
+	lb := &balancerMock{}
+	balancer, err := controller.NewBalancer("foo", config, http.DefaultTransport, lb)
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL))
+	controller.SetBalancers([]*Balancer{balancer})
+
+	assert.Eventually(t, func() bool { return len(lb.Servers()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(10), atomic.LoadInt32(&requests))
+
+	// The load-balancer of a new configuration gets the warmed up server right away.
+	newLB := &balancerMock{}
+	newBalancer, err := controller.NewBalancer("foo", config, http.DefaultTransport, newLB)
+	require.NoError(t, err)
+
+	require.NoError(t, newBalancer.UpsertServer(serverURL))
+	controller.SetBalancers([]*Balancer{newBalancer})
+
+	assert.Len(t, newLB.Servers(), 1)
+	assert.Equal(t, int32(10), atomic.LoadInt32(&requests))
+
+	// A server removed by the health check is warmed up again when it comes back.
+	require.NoError(t, newBalancer.RemoveServer(serverURL))
+	assert.Empty(t, newLB.Servers())
+
+	require.NoError(t, newBalancer.UpsertServer(serverURL))
+
+	assert.Eventually(t, func() bool { return len(newLB.Servers()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(20), atomic.LoadInt32(&requests))
+}
+
+func TestBalancer_warmUpFailure(t *testing.T) {
+	var (
+		requests int32
+		healthy  int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// One request out of two fails until the server is healthy.
+		if atomic.AddInt32(&requests, 1)%2 == 0 && atomic.LoadInt32(&healthy) == 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	controller := NewController(safe.NewPool(context.Background()))
+	controller.retryInterval = 100 * time.Millisecond
+
+	lb := &balancerMock{}
+	balancer, err := controller.NewBalancer("foo", dynamic.WarmUp{Requests: 4, Concurrency: 1, MinSuccessRatio: 0.75}, http.DefaultTransport, lb)
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL))
+	controller.SetBalancers([]*Balancer{balancer})
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&requests) >= 8 }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, lb.Servers())
+
+	atomic.StoreInt32(&healthy, 1)
+
+	assert.Eventually(t, func() bool { return len(lb.Servers()) == 1 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestController_SetBalancers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	controller := NewController(safe.NewPool(context.Background()))
+	controller.retryInterval = 10 * time.Millisecond
+
+	balancer, err := controller.NewBalancer("foo", dynamic.WarmUp{Requests: 1, Concurrency: 1, MinSuccessRatio: 1}, http.DefaultTransport, &balancerMock{})
+	require.NoError(t, err)
+
+	require.NoError(t, balancer.UpsertServer(serverURL))
+	controller.SetBalancers([]*Balancer{balancer})
+
+	controller.mu.Lock()
+	assert.Len(t, controller.servers, 1)
+	controller.mu.Unlock()
+
+	// The server is not part of the configuration anymore.
+	controller.SetBalancers(nil)
+
+	controller.mu.Lock()
+	assert.Empty(t, controller.servers)
+	controller.mu.Unlock()
+}
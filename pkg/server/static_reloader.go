@@ -0,0 +1,190 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/traefik/traefik/v2/pkg/api"
+	"github.com/traefik/traefik/v2/pkg/config/static"
+	"github.com/traefik/traefik/v2/pkg/log"
+	"github.com/traefik/traefik/v2/pkg/metrics"
+	"github.com/traefik/traefik/v2/pkg/provider/aggregator"
+)
+
+// StaticReloader reloads the static configuration, and applies its additive changes without a restart:
+// the new entry points start listening and the new providers are started,
+// while the existing entry points, and their connections, are kept untouched.
+// The other changes are only reported, as they require a restart.
+type StaticReloader struct {
+	mu sync.Mutex
+	// configuration is the static configuration in use, with the changes applied so far.
+	configuration static.Configuration
+
+	load func() (*static.Configuration, error)
+
+	watcher         *ConfigurationWatcher
+	metricsRegistry metrics.Registry
+
+	// entryPointsMu guards the entry points of the server, which the added entry points are written to
+	// from the routine applying the dynamic configurations, while the server may be stopping them.
+	entryPointsMu  sync.Mutex
+	tcpEntryPoints TCPEntryPoints
+	udpEntryPoints UDPEntryPoints
+	closed         bool
+
+	entryPointListeners []func(string, *static.EntryPoint)
+}
+
+// NewStaticReloader creates a new StaticReloader, loading the static configuration with the given function.
+func NewStaticReloader(staticConfiguration static.Configuration, load func() (*static.Configuration, error), watcher *ConfigurationWatcher,
+	tcpEntryPoints TCPEntryPoints, udpEntryPoints UDPEntryPoints, metricsRegistry metrics.Registry) *StaticReloader {
+	return &StaticReloader{
+		configuration:   staticConfiguration,
+		load:            load,
+		watcher:         watcher,
+		tcpEntryPoints:  tcpEntryPoints,
+		udpEntryPoints:  udpEntryPoints,
+		metricsRegistry: metricsRegistry,
+	}
+}
+
+// AddEntryPointListener adds a listener function called with each added entry point,
+// in the routine applying the dynamic configurations.
+func (r *StaticReloader) AddEntryPointListener(listener func(string, *static.EntryPoint)) {
+	r.entryPointListeners = append(r.entryPointListeners, listener)
+}
+
+// Close stops adding the entry points of the reloads to the entry points of the server,
+// which can then be safely stopped.
+// The entry points of the reloads not applied yet are stopped instead of being added.
+func (r *StaticReloader) Close() {
+	r.entryPointsMu.Lock()
+	defer r.entryPointsMu.Unlock()
+
+	r.closed = true
+}
+
+// StaticConfiguration returns the static configuration in use.
+func (r *StaticReloader) StaticConfiguration() static.Configuration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.configuration
+}
+
+// ReloadStaticConfiguration loads the static configuration, and applies the new entry points and the new providers.
+func (r *StaticReloader) ReloadStaticConfiguration() (*api.StaticReload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated, err := r.load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the static configuration: %w", err)
+	}
+
+	changes := static.Diff(&r.configuration, updated)
+
+	logger := log.WithoutContext()
+
+	for _, option := range changes.RestartRequired {
+		logger.Warnf("The static configuration option %s has changed, the change requires a restart", option)
+	}
+
+	reload := &api.StaticReload{
+		Providers:       changes.AddedProviders(),
+		RestartRequired: changes.RestartRequired,
+	}
+
+	if !changes.HasAdditions() {
+		if len(changes.RestartRequired) == 0 {
+			logger.Info("The static configuration has not changed")
+		}
+
+		return reload, nil
+	}
+
+	// The listeners are opened first, so that nothing is applied if one of them cannot be opened.
+	tcpEntryPoints, err := NewTCPEntryPoints(changes.EntryPoints, r.metricsRegistry)
+	if err != nil {
+		return nil, err
+	}
+
+	udpEntryPoints, err := NewUDPEntryPoints(changes.EntryPoints, r.metricsRegistry)
+	if err != nil {
+		tcpEntryPoints.Stop()
+		return nil, err
+	}
+
+	r.configuration = changes.Apply(r.configuration)
+
+	for name := range changes.EntryPoints {
+		reload.EntryPoints = append(reload.EntryPoints, name)
+	}
+	sort.Strings(reload.EntryPoints)
+
+	if len(reload.EntryPoints) > 0 {
+		r.watcher.UpdateEntryPoints(DefaultEntryPoints(r.configuration), func() {
+			for _, name := range reload.EntryPoints {
+				for _, listener := range r.entryPointListeners {
+					listener(name, changes.EntryPoints[name])
+				}
+			}
+
+			r.addEntryPoints(tcpEntryPoints, udpEntryPoints)
+		})
+
+		logger.Infof("Entry points added: %v", reload.EntryPoints)
+	}
+
+	if len(reload.Providers) > 0 {
+		r.watcher.AddProvider(aggregator.NewProviderAggregator(changes.Providers))
+
+		logger.Infof("Providers added: %v", reload.Providers)
+	}
+
+	return reload, nil
+}
+
+// addEntryPoints adds the given entry points to the entry points of the server and starts them,
+// or stops them if the server is stopping.
+func (r *StaticReloader) addEntryPoints(tcpEntryPoints TCPEntryPoints, udpEntryPoints UDPEntryPoints) {
+	r.entryPointsMu.Lock()
+	defer r.entryPointsMu.Unlock()
+
+	if r.closed {
+		tcpEntryPoints.Stop()
+		udpEntryPoints.Stop()
+		return
+	}
+
+	for name, entryPoint := range tcpEntryPoints {
+		r.tcpEntryPoints[name] = entryPoint
+	}
+	for name, entryPoint := range udpEntryPoints {
+		r.udpEntryPoints[name] = entryPoint
+	}
+
+	tcpEntryPoints.Start()
+	udpEntryPoints.Start()
+}
+
+// DefaultEntryPoints returns the entry points the routers without entry points are attached to.
+func DefaultEntryPoints(staticConfiguration static.Configuration) []string {
+	var names []string
+	for name, cfg := range staticConfiguration.EntryPoints {
+		protocol, err := cfg.GetProtocol()
+		if err != nil {
+			// Should never happen because Traefik should not start if protocol is invalid.
+			log.WithoutContext().Errorf("Invalid protocol: %v", err)
+		}
+
+		if protocol != "udp" && name != static.DefaultInternalEntryPointName {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
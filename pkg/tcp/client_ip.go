@@ -0,0 +1,52 @@
+package tcp
+
+import (
+	"net"
+
+	"github.com/traefik/traefik/v2/pkg/ip"
+	"github.com/traefik/traefik/v2/pkg/log"
+)
+
+// ClientIPRoute is a handler for the connections whose client IP is allowed by all the checkers.
+// A route without checkers matches all the connections.
+type ClientIPRoute struct {
+	Checkers []*ip.Checker
+	Handler  Handler
+}
+
+// ClientIPRouter forwards the connections to the handler of the first route matching their client IP,
+// and closes them when no route matches.
+type ClientIPRouter []ClientIPRoute
+
+// ServeTCP forwards the connection to the handler of the first matching route.
+func (r ClientIPRouter) ServeTCP(conn WriteCloser) {
+	clientIP, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		clientIP = conn.RemoteAddr().String()
+	}
+
+	for _, route := range r {
+		if route.matches(clientIP) {
+			route.Handler.ServeTCP(conn)
+			return
+		}
+	}
+
+	conn.Close()
+}
+
+func (r ClientIPRoute) matches(clientIP string) bool {
+	for _, checker := range r.Checkers {
+		ok, err := checker.Contains(clientIP)
+		if err != nil {
+			log.WithoutContext().Debugf("Unable to match the client IP %s: %v", clientIP, err)
+			return false
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
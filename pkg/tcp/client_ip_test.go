@@ -0,0 +1,101 @@
+package tcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/ip"
+)
+
+type remoteAddrConn struct {
+	WriteCloser
+	remoteAddr net.Addr
+	closed     bool
+}
+
+func (c *remoteAddrConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c *remoteAddrConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestClientIPRouter_ServeTCP(t *testing.T) {
+	internal, err := ip.NewChecker([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	local, err := ip.NewChecker([]string{"::1"})
+	require.NoError(t, err)
+
+	gateway, err := ip.NewChecker([]string{"10.0.0.254"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		remoteAddr     string
+		withFallback   bool
+		expectedTarget string
+	}{
+		{
+			desc:           "matching first route",
+			remoteAddr:     "10.0.0.1:1234",
+			expectedTarget: "internal",
+		},
+		{
+			desc:           "matching all the checkers of a route",
+			remoteAddr:     "10.0.0.254:1234",
+			expectedTarget: "gateway",
+		},
+		{
+			desc:           "matching IPv6 route",
+			remoteAddr:     "[::1]:1234",
+			expectedTarget: "local",
+		},
+		{
+			desc:           "fallback route",
+			remoteAddr:     "192.168.0.1:1234",
+			withFallback:   true,
+			expectedTarget: "fallback",
+		},
+		{
+			desc:       "no matching route",
+			remoteAddr: "192.168.0.1:1234",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var target string
+			handler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					target = name
+				})
+			}
+
+			router := ClientIPRouter{
+				{Checkers: []*ip.Checker{internal, gateway}, Handler: handler("gateway")},
+				{Checkers: []*ip.Checker{internal}, Handler: handler("internal")},
+				{Checkers: []*ip.Checker{local}, Handler: handler("local")},
+			}
+			if test.withFallback {
+				router = append(router, ClientIPRoute{Handler: handler("fallback")})
+			}
+
+			addr, err := net.ResolveTCPAddr("tcp", test.remoteAddr)
+			require.NoError(t, err)
+
+			conn := &remoteAddrConn{remoteAddr: addr}
+			router.ServeTCP(conn)
+
+			assert.Equal(t, test.expectedTarget, target)
+			assert.Equal(t, test.expectedTarget == "", conn.closed)
+		})
+	}
+}
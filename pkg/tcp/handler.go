@@ -26,3 +26,10 @@ type WriteCloser interface {
 	// It corresponds to sending a FIN packet.
 	CloseWrite() error
 }
+
+// Unwrapper is implemented by the connections wrapping another connection, such as the ones of the middlewares,
+// so that the handlers can find the connection they expect under the wrappers.
+type Unwrapper interface {
+	// Unwrap returns the wrapped connection.
+	Unwrap() WriteCloser
+}
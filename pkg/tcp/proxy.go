@@ -11,6 +11,12 @@ import (
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
+// serverAddrRecorder is implemented by the connections recording the address of the server they are forwarded to,
+// such as the ones observed by the routers for the access log.
+type serverAddrRecorder interface {
+	SetServerAddr(addr string)
+}
+
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
 	address          string
@@ -59,6 +65,29 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 		return
 	}
 
+	recordServerAddr(conn, connBackend)
+
+	p.serveBackend(conn, connBackend)
+}
+
+// recordServerAddr records the address of the backend on the connection, or on the connection it wraps, if it is a serverAddrRecorder.
+func recordServerAddr(conn WriteCloser, connBackend *net.TCPConn) {
+	for conn != nil {
+		if recorder, ok := conn.(serverAddrRecorder); ok {
+			recorder.SetServerAddr(connBackend.RemoteAddr().String())
+			return
+		}
+
+		unwrapper, ok := conn.(Unwrapper)
+		if !ok {
+			return
+		}
+		conn = unwrapper.Unwrap()
+	}
+}
+
+// serveBackend forwards the connection to the given backend connection.
+func (p *Proxy) serveBackend(conn WriteCloser, connBackend *net.TCPConn) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 	errChan := make(chan error)
@@ -74,7 +103,7 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	go p.connCopy(conn, connBackend, errChan)
 	go p.connCopy(connBackend, conn, errChan)
 
-	err = <-errChan
+	err := <-errChan
 	if err != nil {
 		log.WithoutContext().Errorf("Error during connection: %v", err)
 	}
@@ -210,3 +210,28 @@ func TestLookupAddress(t *testing.T) {
 		})
 	}
 }
+
+// unwrappingConn wraps a connection, as the TCP middlewares do.
+type unwrappingConn struct {
+	WriteCloser
+}
+
+func (c unwrappingConn) Unwrap() WriteCloser {
+	return c.WriteCloser
+}
+
+func Test_recordServerAddr(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer backendListener.Close()
+
+	connBackend, err := net.DialTCP("tcp", nil, backendListener.Addr().(*net.TCPAddr))
+	require.NoError(t, err)
+	defer connBackend.Close()
+
+	recorder := &serverAddrConn{}
+
+	recordServerAddr(unwrappingConn{WriteCloser: unwrappingConn{WriteCloser: recorder}}, connBackend)
+
+	assert.Equal(t, backendListener.Addr().String(), recorder.getServerAddr())
+}
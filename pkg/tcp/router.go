@@ -26,7 +26,20 @@ type Router struct {
 	httpsHandler      http.Handler
 	httpsTLSConfig    *tls.Config // default TLS config
 	catchAllNoTLS     Handler
+	catchAllNoSNI     Handler
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+	sshRoutes         []sshRoute
+
+	// clientHelloTimeout is the maximum duration to wait for the first bytes of a connection.
+	clientHelloTimeout time.Duration
+	// clientHelloMaxBytes is the maximum size of the ClientHello read to find the SNI.
+	clientHelloMaxBytes int
+}
+
+// sshRoute is a handler for the SSH connections whose client software version matches one of the versions.
+type sshRoute struct {
+	versions []string
+	handler  Handler
 }
 
 // GetTLSGetClientInfo is called after a ClientHello is received from a client.
@@ -43,18 +56,40 @@ func (r *Router) GetTLSGetClientInfo() func(info *tls.ClientHelloInfo) (*tls.Con
 func (r *Router) ServeTCP(conn WriteCloser) {
 	// FIXME -- Check if ProxyProtocol changes the first bytes of the request
 
-	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 {
+	if r.catchAllNoTLS != nil && len(r.routingTable) == 0 && len(r.sshRoutes) == 0 {
 		r.catchAllNoTLS.ServeTCP(conn)
 		return
 	}
 
+	if r.clientHelloTimeout > 0 {
+		err := conn.SetReadDeadline(time.Now().Add(r.clientHelloTimeout))
+		if err != nil {
+			log.WithoutContext().Errorf("Error while setting read deadline: %v", err)
+		}
+	}
+
 	br := bufio.NewReader(conn)
-	serverName, tls, peeked, err := clientHelloServerName(br)
+	serverName, tls, peeked, err := clientHelloServerName(br, r.clientHelloMaxBytes)
 	if err != nil {
-		conn.Close()
+		var netErr net.Error
+		if r.clientHelloTimeout <= 0 || !errors.As(err, &netErr) || !netErr.Timeout() {
+			conn.Close()
+			return
+		}
+
+		// The client did not send anything, e.g. for a protocol where the server speaks first.
+		r.serveNoClientHello(conn)
 		return
 	}
 
+	// The SSH identification string is peeked before removing the read deadline.
+	var sshVersion string
+	var isSSH bool
+	if !tls && len(r.sshRoutes) > 0 {
+		sshVersion, isSSH = sshSoftwareVersion(br)
+		peeked = getPeeked(br)
+	}
+
 	// Remove read/write deadline and delegate this to underlying tcp server (for now only handled by HTTP Server)
 	err = conn.SetReadDeadline(time.Time{})
 	if err != nil {
@@ -67,9 +102,18 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 	}
 
 	if !tls {
+		if isSSH {
+			if target := r.sshTarget(sshVersion); target != nil {
+				target.ServeTCP(r.GetConn(conn, peeked))
+				return
+			}
+		}
+
 		switch {
 		case r.catchAllNoTLS != nil:
 			r.catchAllNoTLS.ServeTCP(r.GetConn(conn, peeked))
+		case r.catchAllNoSNI != nil:
+			r.catchAllNoSNI.ServeTCP(r.GetConn(conn, peeked))
 		case r.httpForwarder != nil:
 			r.httpForwarder.ServeTCP(r.GetConn(conn, peeked))
 		default:
@@ -82,24 +126,51 @@ func (r *Router) ServeTCP(conn WriteCloser) {
 	serverName = types.CanonicalDomain(serverName)
 	if r.routingTable != nil && serverName != "" {
 		if target, ok := r.routingTable[serverName]; ok {
-			target.ServeTCP(r.GetConn(conn, peeked))
+			target.ServeTCP(r.getTLSConn(conn, peeked, serverName))
 			return
 		}
 	}
 
 	// FIXME Needs tests
 	if target, ok := r.routingTable["*"]; ok {
-		target.ServeTCP(r.GetConn(conn, peeked))
+		target.ServeTCP(r.getTLSConn(conn, peeked, serverName))
+		return
+	}
+
+	if serverName == "" && r.catchAllNoSNI != nil {
+		r.catchAllNoSNI.ServeTCP(r.getTLSConn(conn, peeked, serverName))
 		return
 	}
 
 	if r.httpsForwarder != nil {
-		r.httpsForwarder.ServeTCP(r.GetConn(conn, peeked))
+		r.httpsForwarder.ServeTCP(r.getTLSConn(conn, peeked, serverName))
 	} else {
 		conn.Close()
 	}
 }
 
+// serveNoClientHello handles a connection on which nothing was received before the ClientHello timeout.
+func (r *Router) serveNoClientHello(conn WriteCloser) {
+	err := conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		log.WithoutContext().Errorf("Error while setting read deadline: %v", err)
+	}
+
+	err = conn.SetWriteDeadline(time.Time{})
+	if err != nil {
+		log.WithoutContext().Errorf("Error while setting write deadline: %v", err)
+	}
+
+	switch {
+	case r.catchAllNoTLS != nil:
+		r.catchAllNoTLS.ServeTCP(conn)
+	case r.catchAllNoSNI != nil:
+		r.catchAllNoSNI.ServeTCP(conn)
+	default:
+		conn.Close()
+	}
+}
+
 // AddRoute defines a handler for a given sniHost (* is the only valid option).
 func (r *Router) AddRoute(sniHost string, target Handler) {
 	if r.routingTable == nil {
@@ -108,6 +179,43 @@ func (r *Router) AddRoute(sniHost string, target Handler) {
 	r.routingTable[strings.ToLower(sniHost)] = target
 }
 
+// AddRouteSSH defines a handler for the SSH connections whose client software version matches one of the given versions.
+// A version ending with * matches the versions starting with it, and all the SSH connections are matched when no version is given.
+func (r *Router) AddRouteSSH(versions []string, target Handler) {
+	r.sshRoutes = append(r.sshRoutes, sshRoute{versions: versions, handler: target})
+}
+
+// sshTarget returns the handler of the SSH route matching the client software version with the longest version,
+// or the one matching all the SSH connections.
+func (r *Router) sshTarget(version string) Handler {
+	var target Handler
+	matchedLen := -1
+
+	for _, route := range r.sshRoutes {
+		if len(route.versions) == 0 {
+			if matchedLen < 0 {
+				target, matchedLen = route.handler, 0
+			}
+			continue
+		}
+
+		for _, pattern := range route.versions {
+			if len(pattern) > matchedLen && matchSSHVersion(pattern, version) {
+				target, matchedLen = route.handler, len(pattern)
+			}
+		}
+	}
+
+	return target
+}
+
+func matchSSHVersion(pattern, version string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(version, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == version
+}
+
 // AddRouteTLS defines a handler for a given sniHost and sets the matching tlsConfig.
 func (r *Router) AddRouteTLS(sniHost string, target Handler, config *tls.Config) {
 	r.AddRoute(sniHost, &TLSHandler{
@@ -129,6 +237,20 @@ func (r *Router) AddCatchAllNoTLS(handler Handler) {
 	r.catchAllNoTLS = handler
 }
 
+// AddCatchAllNoSNI defines the fallback tcp handler of the connections without SNI:
+// the TLS connections without server name, and the non-TLS connections when there is no catch-all non-TLS handler.
+func (r *Router) AddCatchAllNoSNI(handler Handler) {
+	r.catchAllNoSNI = handler
+}
+
+// SetClientHelloInspection sets the maximum duration to wait for the first bytes of the connections,
+// and the maximum size of the ClientHello read to find the SNI.
+// A zero value means no limit.
+func (r *Router) SetClientHelloInspection(timeout time.Duration, maxBytes int) {
+	r.clientHelloTimeout = timeout
+	r.clientHelloMaxBytes = maxBytes
+}
+
 // GetConn creates a connection proxy with a peeked string.
 func (r *Router) GetConn(conn WriteCloser, peeked string) WriteCloser {
 	// FIXME should it really be on Router ?
@@ -139,6 +261,15 @@ func (r *Router) GetConn(conn WriteCloser, peeked string) WriteCloser {
 	return conn
 }
 
+// getTLSConn creates a connection proxy with a peeked string, and the server name of the TLS ClientHello.
+func (r *Router) getTLSConn(conn WriteCloser, peeked, serverName string) WriteCloser {
+	return &Conn{
+		Peeked:      []byte(peeked),
+		WriteCloser: conn,
+		ServerName:  serverName,
+	}
+}
+
 // GetHTTPHandler gets the attached http handler.
 func (r *Router) GetHTTPHandler() http.Handler {
 	return r.httpHandler
@@ -189,6 +320,9 @@ type Conn struct {
 	// as needed. It should not be read from directly unless
 	// Peeked is nil.
 	WriteCloser
+
+	// ServerName is the server name (SNI) of the TLS ClientHello, if any.
+	ServerName string
 }
 
 // Read reads bytes from the connection (using the buffer prior to actually reading).
@@ -206,8 +340,9 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 
 // clientHelloServerName returns the SNI server name inside the TLS ClientHello,
 // without consuming any bytes from br.
+// The ClientHello is not read if it is bigger than maxBytes, when maxBytes is positive.
 // On any error, the empty string is returned.
-func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
+func clientHelloServerName(br *bufio.Reader, maxBytes int) (string, bool, string, error) {
 	hdr, err := br.Peek(1)
 	if err != nil {
 		var opErr *net.OpError
@@ -241,6 +376,11 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 
 	recLen := int(hdr[3])<<8 | int(hdr[4]) // ignoring version in hdr[1:3]
 
+	if maxBytes > 0 && recordHeaderLen+recLen > maxBytes {
+		log.WithoutContext().Debugf("ClientHello of %d bytes bigger than %d bytes, ignoring its server name", recordHeaderLen+recLen, maxBytes)
+		return "", true, getPeeked(br), nil
+	}
+
 	if recordHeaderLen+recLen > defaultBufSize {
 		br = bufio.NewReaderSize(br, recordHeaderLen+recLen)
 	}
@@ -263,6 +403,56 @@ func clientHelloServerName(br *bufio.Reader) (string, bool, string, error) {
 	return sni, true, getPeeked(br), nil
 }
 
+// sshSoftwareVersion returns the software version of the SSH identification string sent by the client
+// (SSH-protoversion-softwareversion SP comments CR LF), without consuming any bytes from br.
+func sshSoftwareVersion(br *bufio.Reader) (string, bool) {
+	// The maximum length of the identification string, CR LF included (RFC 4253).
+	const maxIdentificationLen = 255
+
+	hdr, err := br.Peek(1)
+	if err != nil || hdr[0] != 'S' {
+		return "", false
+	}
+
+	hdr, err = br.Peek(4)
+	if err != nil || string(hdr) != "SSH-" {
+		return "", false
+	}
+
+	for {
+		buffered, err := br.Peek(br.Buffered())
+		if err != nil {
+			return "", false
+		}
+
+		if i := bytes.IndexByte(buffered, '\n'); i >= 0 {
+			return parseSSHIdentification(string(buffered[:i]))
+		}
+
+		if len(buffered) >= maxIdentificationLen {
+			return "", false
+		}
+
+		if _, err := br.Peek(len(buffered) + 1); err != nil {
+			return "", false
+		}
+	}
+}
+
+func parseSSHIdentification(line string) (string, bool) {
+	parts := strings.SplitN(strings.TrimSuffix(line, "\r"), "-", 3)
+	if len(parts) != 3 || parts[0] != "SSH" || (parts[1] != "2.0" && parts[1] != "1.99") {
+		return "", false
+	}
+
+	version := parts[2]
+	if i := strings.IndexByte(version, ' '); i >= 0 {
+		version = version[:i]
+	}
+
+	return version, version != ""
+}
+
 func getPeeked(br *bufio.Reader) string {
 	peeked, err := br.Peek(br.Buffered())
 	if err != nil {
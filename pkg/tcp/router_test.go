@@ -0,0 +1,175 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pipeConn struct {
+	net.Conn
+}
+
+func (p pipeConn) CloseWrite() error {
+	return nil
+}
+
+func TestRouter_ServeTCP_ssh(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		data            string
+		expectedHandler string
+	}{
+		{
+			desc:            "OpenSSH client",
+			data:            "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3\r\n",
+			expectedHandler: "openssh",
+		},
+		{
+			desc:            "other SSH client",
+			data:            "SSH-2.0-PuTTY_Release_0.76\r\n",
+			expectedHandler: "ssh",
+		},
+		{
+			desc:            "SSH client with protocol version 1.99",
+			data:            "SSH-1.99-OpenSSH_3.9\r\n",
+			expectedHandler: "openssh",
+		},
+		{
+			desc:            "unsupported SSH protocol version",
+			data:            "SSH-1.5-OpenSSH_3.9\r\n",
+			expectedHandler: "http",
+		},
+		{
+			desc:            "HTTP request",
+			data:            "GET / HTTP/1.1\r\nHost: foo.bar\r\n\r\n",
+			expectedHandler: "http",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handled := make(chan string, 1)
+			newHandler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					// The peeked bytes are read again by the handler.
+					data := make([]byte, len(test.data))
+					_, err := io.ReadFull(conn, data)
+					assert.NoError(t, err)
+					assert.Equal(t, test.data, string(data))
+
+					handled <- name
+				})
+			}
+
+			router := &Router{}
+			router.AddRouteSSH(nil, newHandler("ssh"))
+			router.AddRouteSSH([]string{"OpenSSH*"}, newHandler("openssh"))
+			router.HTTPForwarder(newHandler("http"))
+
+			server, client := net.Pipe()
+			t.Cleanup(func() {
+				_ = server.Close()
+				_ = client.Close()
+			})
+
+			go func() {
+				_, _ = client.Write([]byte(test.data))
+			}()
+
+			router.ServeTCP(pipeConn{Conn: server})
+
+			require.Len(t, handled, 1)
+			assert.Equal(t, test.expectedHandler, <-handled)
+		})
+	}
+}
+
+func TestRouter_ServeTCP_noSNI(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		serverName      string
+		data            string
+		timeout         time.Duration
+		maxBytes        int
+		expectedHandler string
+	}{
+		{
+			desc:            "TLS connection with SNI",
+			serverName:      "foo.bar",
+			expectedHandler: "foo",
+		},
+		{
+			desc:            "TLS connection without SNI",
+			expectedHandler: "default",
+		},
+		{
+			desc:            "ClientHello bigger than the maximum size",
+			serverName:      "foo.bar",
+			maxBytes:        10,
+			expectedHandler: "default",
+		},
+		{
+			desc:            "non-TLS connection",
+			data:            "GET / HTTP/1.1\r\nHost: foo.bar\r\n\r\n",
+			expectedHandler: "default",
+		},
+		{
+			desc:            "nothing sent before the ClientHello timeout",
+			timeout:         10 * time.Millisecond,
+			expectedHandler: "default",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handled := make(chan string, 1)
+			newHandler := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					handled <- name
+				})
+			}
+
+			router := &Router{}
+			router.AddRoute("foo.bar", newHandler("foo"))
+			router.AddCatchAllNoSNI(newHandler("default"))
+			router.HTTPForwarder(newHandler("http"))
+			router.HTTPSForwarder(newHandler("https"))
+			router.SetClientHelloInspection(test.timeout, test.maxBytes)
+
+			server, client := net.Pipe()
+			t.Cleanup(func() {
+				_ = server.Close()
+				_ = client.Close()
+			})
+
+			switch {
+			case test.data != "":
+				go func() {
+					_, _ = client.Write([]byte(test.data))
+				}()
+			case test.timeout == 0:
+				go func() {
+					// The handshake fails once the connection is closed, the ClientHello being sent anyway.
+					_ = tls.Client(client, &tls.Config{ServerName: test.serverName, InsecureSkipVerify: true}).Handshake()
+				}()
+			}
+
+			router.ServeTCP(pipeConn{Conn: server})
+
+			require.Len(t, handled, 1)
+			assert.Equal(t, test.expectedHandler, <-handled)
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package tcp
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"sync"
+	"time"
 
+	"github.com/traefik/traefik/v2/pkg/job"
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
@@ -12,12 +16,22 @@ type server struct {
 	weight int
 }
 
+// backendDialer is a Handler connecting to a backend before forwarding the connection to it.
+type backendDialer interface {
+	dialBackend() (*net.TCPConn, error)
+	serveBackend(conn WriteCloser, connBackend *net.TCPConn)
+}
+
 // WRRLoadBalancer is a naive RoundRobin load balancer for TCP services.
 type WRRLoadBalancer struct {
 	servers       []server
 	lock          sync.RWMutex
 	currentWeight int
 	index         int
+
+	retryCtx        context.Context
+	attempts        int
+	initialInterval time.Duration
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
@@ -34,6 +48,11 @@ func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 		return
 	}
 
+	if b.attempts > 1 {
+		b.serveWithRetry(conn)
+		return
+	}
+
 	next, err := b.next()
 	if err != nil {
 		log.WithoutContext().Errorf("Error during load balancing: %v", err)
@@ -42,6 +61,64 @@ func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 	next.ServeTCP(conn)
 }
 
+// SetRetry makes the load balancer try up to attempts times to connect to a server, before giving up on the connection.
+// Each attempt connects to the next server, and the attempts are spaced by an exponential backoff starting at initialInterval.
+// The wait between the attempts stops, and the connection is closed, when ctx is canceled.
+func (b *WRRLoadBalancer) SetRetry(ctx context.Context, attempts int, initialInterval time.Duration) {
+	b.retryCtx = ctx
+	b.attempts = attempts
+	b.initialInterval = initialInterval
+}
+
+func (b *WRRLoadBalancer) serveWithRetry(conn WriteCloser) {
+	backOff := job.NewAttemptsBackOff(b.attempts, b.initialInterval)
+
+	for attempt := 1; ; attempt++ {
+		next, err := b.next()
+		if err != nil {
+			log.WithoutContext().Errorf("Error during load balancing: %v", err)
+			conn.Close()
+			return
+		}
+
+		dialer, ok := next.Handler.(backendDialer)
+		if !ok {
+			next.ServeTCP(conn)
+			return
+		}
+
+		connBackend, err := dialer.dialBackend()
+		if err == nil {
+			log.WithoutContext().Debugf("Handling connection from %s", conn.RemoteAddr())
+
+			// needed because of e.g. server.trackedConnection
+			defer conn.Close()
+
+			recordServerAddr(conn, connBackend)
+
+			dialer.serveBackend(conn, connBackend)
+			return
+		}
+
+		if attempt >= b.attempts {
+			log.WithoutContext().Errorf("Error while connecting to backend after %d attempts: %v", attempt, err)
+			conn.Close()
+			return
+		}
+
+		log.WithoutContext().Debugf("Error while connecting to backend, new attempt %d: %v", attempt+1, err)
+
+		timer := time.NewTimer(backOff.NextBackOff())
+		select {
+		case <-timer.C:
+		case <-b.retryCtx.Done():
+			timer.Stop()
+			conn.Close()
+			return
+		}
+	}
+}
+
 // AddServer appends a server to the existing list.
 func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
 	w := 1
@@ -86,12 +163,12 @@ func gcd(a, b int) int {
 	return a
 }
 
-func (b *WRRLoadBalancer) next() (Handler, error) {
+func (b *WRRLoadBalancer) next() (server, error) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
 	if len(b.servers) == 0 {
-		return nil, fmt.Errorf("no servers in the pool")
+		return server{}, fmt.Errorf("no servers in the pool")
 	}
 
 	// The algo below may look messy, but is actually very simple
@@ -110,7 +187,7 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 			if b.currentWeight <= 0 {
 				b.currentWeight = max
 				if b.currentWeight == 0 {
-					return nil, fmt.Errorf("all servers have 0 weight")
+					return server{}, fmt.Errorf("all servers have 0 weight")
 				}
 			}
 		}
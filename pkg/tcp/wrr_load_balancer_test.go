@@ -1,7 +1,12 @@
 package tcp
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"net"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -129,3 +134,115 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBalancing_Retry(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		attempts int
+		canceled bool
+		expected string
+	}{
+		{
+			desc:     "without retry",
+			attempts: 0,
+			expected: "",
+		},
+		{
+			desc:     "retry on the next server",
+			attempts: 2,
+			expected: "PONG",
+		},
+		{
+			desc:     "no retry once canceled",
+			attempts: 2,
+			canceled: true,
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			backendListener, err := net.Listen("tcp", ":0")
+			require.NoError(t, err)
+
+			go fakeRedis(t, backendListener)
+
+			// The listener is closed right away, so that nothing listens on its port.
+			downListener, err := net.Listen("tcp", ":0")
+			require.NoError(t, err)
+			require.NoError(t, downListener.Close())
+
+			downProxy, err := NewProxy(downListener.Addr().String(), 10*time.Millisecond, nil)
+			require.NoError(t, err)
+
+			upProxy, err := NewProxy(backendListener.Addr().String(), 10*time.Millisecond, nil)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if test.canceled {
+				cancel()
+			}
+
+			balancer := NewWRRLoadBalancer()
+			balancer.AddServer(downProxy)
+			balancer.AddServer(upProxy)
+			balancer.SetRetry(ctx, test.attempts, 10*time.Millisecond)
+
+			proxyListener, err := net.Listen("tcp", ":0")
+			require.NoError(t, err)
+			defer proxyListener.Close()
+
+			recorder := &serverAddrConn{}
+			go func() {
+				for {
+					conn, err := proxyListener.Accept()
+					if err != nil {
+						return
+					}
+					recorder.TCPConn = conn.(*net.TCPConn)
+					balancer.ServeTCP(recorder)
+				}
+			}()
+
+			conn, err := net.Dial("tcp", proxyListener.Addr().String())
+			require.NoError(t, err)
+
+			// The errors are ignored, as the connection is closed by the load balancer when all the attempts failed.
+			_, _ = conn.Write([]byte("ping\n"))
+			_ = conn.(*net.TCPConn).CloseWrite()
+
+			buffer := &bytes.Buffer{}
+			_, _ = io.Copy(buffer, conn)
+
+			assert.Equal(t, test.expected, buffer.String())
+			if test.expected != "" {
+				_, port, err := net.SplitHostPort(recorder.getServerAddr())
+				require.NoError(t, err)
+				assert.Equal(t, strconv.Itoa(backendListener.Addr().(*net.TCPAddr).Port), port)
+			}
+		})
+	}
+}
+
+// serverAddrConn records the address of the server the connection is forwarded to.
+type serverAddrConn struct {
+	*net.TCPConn
+
+	mu         sync.Mutex
+	serverAddr string
+}
+
+func (c *serverAddrConn) SetServerAddr(addr string) {
+	c.mu.Lock()
+	c.serverAddr = addr
+	c.mu.Unlock()
+}
+
+func (c *serverAddrConn) getServerAddr() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.serverAddr
+}
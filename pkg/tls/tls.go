@@ -38,4 +38,6 @@ type Store struct {
 type CertAndStores struct {
 	Certificate `yaml:",inline" export:"true"`
 	Stores      []string `json:"stores,omitempty" toml:"stores,omitempty" yaml:"stores,omitempty" export:"true"`
+	// Provider is the name of the provider of the certificate, set when the configurations are merged.
+	Provider string `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
 }
@@ -2,11 +2,15 @@ package tls
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/sirupsen/logrus"
@@ -23,9 +27,32 @@ const (
 	DefaultTLSStoreName = "default"
 )
 
+// Sources of the served certificates.
+const (
+	CertificateSourceDefault  = "default"
+	CertificateSourceStatic   = "static"
+	CertificateSourceResolver = "resolver"
+)
+
+// acmeProviderSuffix is the suffix of the names of the providers of the certificate resolvers.
+const acmeProviderSuffix = ".acme"
+
 // DefaultTLSOptions the default TLS options.
 var DefaultTLSOptions = Options{}
 
+// CertificateInfo describes the certificate served for a domain.
+type CertificateInfo struct {
+	// Source is where the certificate comes from: default, static, or resolver.
+	Source string `json:"source"`
+	// Provider is the provider of a static certificate.
+	Provider string `json:"provider,omitempty"`
+	// Resolver is the certificate resolver of the certificate.
+	Resolver     string    `json:"resolver,omitempty"`
+	SerialNumber string    `json:"serialNumber,omitempty"`
+	SANs         []string  `json:"sans,omitempty"`
+	NotAfter     time.Time `json:"notAfter"`
+}
+
 // Manager is the TLS option/store/configuration factory.
 type Manager struct {
 	lock         sync.RWMutex
@@ -33,6 +60,8 @@ type Manager struct {
 	stores       map[string]*CertificateStore
 	configs      map[string]Options
 	certs        []*CertAndStores
+	// certProviders are the providers of the certificates, keyed by fingerprint.
+	certProviders map[[sha256.Size]byte]string
 }
 
 // NewManager creates a new Manager.
@@ -78,8 +107,16 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 		m.stores[storeName] = store
 	}
 
+	m.certProviders = make(map[[sha256.Size]byte]string)
+
 	storesCertificates := make(map[string]map[string]*tls.Certificate)
 	for _, conf := range certs {
+		if conf.Provider != "" {
+			if fingerprint, ok := certificateFingerprint(conf.Certificate); ok {
+				m.certProviders[fingerprint] = conf.Provider
+			}
+		}
+
 		if len(conf.Stores) == 0 {
 			if log.GetLevel() >= logrus.DebugLevel {
 				log.FromContext(ctx).Debugf("No store is defined to add the certificate %s, it will be added to the default store.",
@@ -162,6 +199,92 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 	return tlsConfig, err
 }
 
+// GetServedCertificate returns the certificate served by a store to the clients requesting the domain,
+// with the given TLS options, or nil if the TLS handshake would fail.
+func (m *Manager) GetServedCertificate(storeName, configName, domain string) *CertificateInfo {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	domain = types.CanonicalDomain(domain)
+
+	store := m.getStore(storeName)
+	if store == nil || domain == "" {
+		return nil
+	}
+
+	if certificate := store.GetBestCertificate(&tls.ClientHelloInfo{ServerName: domain}); certificate != nil {
+		info := newCertificateInfo(certificate)
+		if info == nil {
+			return nil
+		}
+
+		info.Source = CertificateSourceStatic
+		info.Provider = m.certProviders[sha256.Sum256(certificate.Certificate[0])]
+
+		if strings.HasSuffix(info.Provider, acmeProviderSuffix) {
+			info.Source = CertificateSourceResolver
+			info.Resolver = strings.TrimSuffix(info.Provider, acmeProviderSuffix)
+			info.Provider = ""
+		}
+
+		return info
+	}
+
+	if m.configs[configName].SniStrict || store.DefaultCertificate == nil {
+		return nil
+	}
+
+	info := newCertificateInfo(store.DefaultCertificate)
+	if info == nil {
+		return nil
+	}
+
+	info.Source = CertificateSourceDefault
+
+	return info
+}
+
+func newCertificateInfo(certificate *tls.Certificate) *CertificateInfo {
+	if len(certificate.Certificate) == 0 {
+		return nil
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return nil
+	}
+
+	sans := append([]string{}, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	if len(sans) == 0 && leaf.Subject.CommonName != "" {
+		sans = append(sans, leaf.Subject.CommonName)
+	}
+
+	return &CertificateInfo{
+		SerialNumber: leaf.SerialNumber.Text(16),
+		SANs:         sans,
+		NotAfter:     leaf.NotAfter,
+	}
+}
+
+// certificateFingerprint returns the SHA-256 fingerprint of the leaf certificate, without parsing the private key.
+func certificateFingerprint(certificate Certificate) ([sha256.Size]byte, bool) {
+	content, err := certificate.CertFile.Read()
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return [sha256.Size]byte{}, false
+	}
+
+	return sha256.Sum256(block.Bytes), true
+}
+
 // GetCertificates returns all stored certificates.
 func (m *Manager) GetCertificates() []*x509.Certificate {
 	var certificates []*x509.Certificate
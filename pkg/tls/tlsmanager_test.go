@@ -152,6 +152,88 @@ func TestManager_Get(t *testing.T) {
 	}
 }
 
+func TestManager_GetServedCertificate(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		provider         string
+		domain           string
+		sniStrict        bool
+		expectedSource   string
+		expectedProvider string
+		expectedResolver string
+		expectedSANs     []string
+	}{
+		{
+			desc:             "static certificate",
+			provider:         "file",
+			domain:           "example.com",
+			expectedSource:   CertificateSourceStatic,
+			expectedProvider: "file",
+			expectedSANs:     []string{"example.com", "127.0.0.1", "::1"},
+		},
+		{
+			desc:             "certificate of a resolver",
+			provider:         "myresolver.acme",
+			domain:           "Example.com",
+			expectedSource:   CertificateSourceResolver,
+			expectedResolver: "myresolver",
+			expectedSANs:     []string{"example.com", "127.0.0.1", "::1"},
+		},
+		{
+			desc:           "default certificate",
+			provider:       "file",
+			domain:         "foo.bar",
+			expectedSource: CertificateSourceDefault,
+		},
+		{
+			desc:      "no certificate with strict SNI",
+			provider:  "file",
+			domain:    "foo.bar",
+			sniStrict: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dynamicConfigs := []*CertAndStores{{
+				Certificate: Certificate{
+					CertFile: localhostCert,
+					KeyFile:  localhostKey,
+				},
+				Provider: test.provider,
+			}}
+
+			tlsConfigs := map[string]Options{
+				"default": {SniStrict: test.sniStrict},
+			}
+
+			tlsManager := NewManager()
+			tlsManager.UpdateConfigs(context.Background(), nil, tlsConfigs, dynamicConfigs)
+
+			info := tlsManager.GetServedCertificate("default", "default", test.domain)
+			if test.expectedSource == "" {
+				assert.Nil(t, info)
+				return
+			}
+
+			require.NotNil(t, info)
+			assert.Equal(t, test.expectedSource, info.Source)
+			assert.Equal(t, test.expectedProvider, info.Provider)
+			assert.Equal(t, test.expectedResolver, info.Resolver)
+			assert.NotEmpty(t, info.SerialNumber)
+			assert.False(t, info.NotAfter.IsZero())
+
+			// The SANs of the generated default certificate are random.
+			if test.expectedSANs != nil {
+				assert.Equal(t, test.expectedSANs, info.SANs)
+			}
+		})
+	}
+}
+
 func TestClientAuth(t *testing.T) {
 	tlsConfigs := map[string]Options{
 		"eca": {
@@ -22,7 +22,7 @@ type Config struct {
 	SamplingParam              float64    `description:"Set the sampling parameter." json:"samplingParam,omitempty" toml:"samplingParam,omitempty" yaml:"samplingParam,omitempty" export:"true"`
 	LocalAgentHostPort         string     `description:"Set jaeger-agent's host:port that the reporter will used." json:"localAgentHostPort,omitempty" toml:"localAgentHostPort,omitempty" yaml:"localAgentHostPort,omitempty"`
 	Gen128Bit                  bool       `description:"Generate 128 bit span IDs." json:"gen128Bit,omitempty" toml:"gen128Bit,omitempty" yaml:"gen128Bit,omitempty" export:"true"`
-	Propagation                string     `description:"Which propagation format to use (jaeger/b3)." json:"propagation,omitempty" toml:"propagation,omitempty" yaml:"propagation,omitempty" export:"true"`
+	Propagation                string     `description:"Which propagation format to use (jaeger/b3/w3c)." json:"propagation,omitempty" toml:"propagation,omitempty" yaml:"propagation,omitempty" export:"true"`
 	TraceContextHeaderName     string     `description:"Set the header to use for the trace-id." json:"traceContextHeaderName,omitempty" toml:"traceContextHeaderName,omitempty" yaml:"traceContextHeaderName,omitempty" export:"true"`
 	Collector                  *Collector `description:"Define the collector information" json:"collector,omitempty" toml:"collector,omitempty" yaml:"collector,omitempty" export:"true"`
 	DisableAttemptReconnecting bool       `description:"Disable the periodic re-resolution of the agent's hostname and reconnection if there was a change." json:"disableAttemptReconnecting,omitempty" toml:"disableAttemptReconnecting,omitempty" yaml:"disableAttemptReconnecting,omitempty" export:"true"`
@@ -101,6 +101,12 @@ func (c *Config) Setup(componentName string) (opentracing.Tracer, io.Closer, err
 			jaegercfg.Injector(opentracing.HTTPHeaders, p),
 			jaegercfg.Extractor(opentracing.HTTPHeaders, p),
 		)
+	case "w3c":
+		p := w3cPropagator{}
+		opts = append(opts,
+			jaegercfg.Injector(opentracing.HTTPHeaders, p),
+			jaegercfg.Extractor(opentracing.HTTPHeaders, p),
+		)
 	case "jaeger", "":
 	default:
 		return nil, nil, fmt.Errorf("unknown propagation format: %s", c.Propagation)
@@ -0,0 +1,117 @@
+package jaeger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	jaegercli "github.com/uber/jaeger-client-go"
+)
+
+// traceParentHeader is the header holding the span context in the W3C Trace Context format.
+const traceParentHeader = "traceparent"
+
+// w3cPropagator injects and extracts the span contexts with the W3C Trace Context traceparent header
+// (https://www.w3.org/TR/trace-context/).
+type w3cPropagator struct{}
+
+// Inject implements jaegercli.Injector.
+func (w3cPropagator) Inject(sc jaegercli.SpanContext, abstractCarrier interface{}) error {
+	carrier, ok := abstractCarrier.(opentracing.TextMapWriter)
+	if !ok {
+		return opentracing.ErrInvalidCarrier
+	}
+
+	flags := "00"
+	if sc.IsSampled() {
+		flags = "01"
+	}
+
+	traceID := sc.TraceID()
+	carrier.Set(traceParentHeader, fmt.Sprintf("00-%016x%016x-%016x-%s", traceID.High, traceID.Low, uint64(sc.SpanID()), flags))
+
+	return nil
+}
+
+// Extract implements jaegercli.Extractor.
+func (w3cPropagator) Extract(abstractCarrier interface{}) (jaegercli.SpanContext, error) {
+	carrier, ok := abstractCarrier.(opentracing.TextMapReader)
+	if !ok {
+		return jaegercli.SpanContext{}, opentracing.ErrInvalidCarrier
+	}
+
+	var traceParent string
+	err := carrier.ForeachKey(func(key, value string) error {
+		if strings.EqualFold(key, traceParentHeader) {
+			traceParent = value
+		}
+		return nil
+	})
+	if err != nil {
+		return jaegercli.SpanContext{}, err
+	}
+
+	if traceParent == "" {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextNotFound
+	}
+
+	return parseTraceParent(traceParent)
+}
+
+// parseTraceParent parses a traceparent header value: version-traceID-parentID-flags.
+// The fields added by the versions after 00 are ignored.
+func parseTraceParent(value string) (jaegercli.SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || !isLowerHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	if !isLowerHex(parts[1], 32) || !isLowerHex(parts[2], 16) || !isLowerHex(parts[3], 2) {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	var traceID jaegercli.TraceID
+	var err error
+
+	traceID.High, err = strconv.ParseUint(parts[1][:16], 16, 64)
+	if err != nil {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	traceID.Low, err = strconv.ParseUint(parts[1][16:], 16, 64)
+	if err != nil {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	spanID, err := strconv.ParseUint(parts[2], 16, 64)
+	if err != nil {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	if !traceID.IsValid() || spanID == 0 {
+		return jaegercli.SpanContext{}, opentracing.ErrSpanContextCorrupted
+	}
+
+	return jaegercli.NewSpanContext(traceID, jaegercli.SpanID(spanID), 0, flags&1 == 1, nil), nil
+}
+
+// isLowerHex reports whether the value is made of the given number of lowercase hexadecimal digits.
+func isLowerHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+
+	for _, c := range value {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
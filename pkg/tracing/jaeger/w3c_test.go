@@ -0,0 +1,105 @@
+package jaeger
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jaegercli "github.com/uber/jaeger-client-go"
+)
+
+func TestW3CPropagator_Extract(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		traceParent string
+		expected    jaegercli.SpanContext
+		expectedErr error
+	}{
+		{
+			desc:        "sampled",
+			traceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			expected:    jaegercli.NewSpanContext(jaegercli.TraceID{High: 0x0af7651916cd43dd, Low: 0x8448eb211c80319c}, 0xb7ad6b7169203331, 0, true, nil),
+		},
+		{
+			desc:        "not sampled",
+			traceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00",
+			expected:    jaegercli.NewSpanContext(jaegercli.TraceID{High: 0x0af7651916cd43dd, Low: 0x8448eb211c80319c}, 0xb7ad6b7169203331, 0, false, nil),
+		},
+		{
+			desc:        "future version with additional fields",
+			traceParent: "cc-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-what-the-future-will-be-like",
+			expected:    jaegercli.NewSpanContext(jaegercli.TraceID{High: 0x0af7651916cd43dd, Low: 0x8448eb211c80319c}, 0xb7ad6b7169203331, 0, true, nil),
+		},
+		{
+			desc:        "no traceparent",
+			expectedErr: opentracing.ErrSpanContextNotFound,
+		},
+		{
+			desc:        "version 00 with additional fields",
+			traceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-foo",
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "invalid version",
+			traceParent: "ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "uppercase trace ID",
+			traceParent: "00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01",
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "zero trace ID",
+			traceParent: "00-00000000000000000000000000000000-b7ad6b7169203331-01",
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "zero parent ID",
+			traceParent: "00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+		{
+			desc:        "short parent ID",
+			traceParent: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b716920333-01",
+			expectedErr: opentracing.ErrSpanContextCorrupted,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			if test.traceParent != "" {
+				header.Set("Traceparent", test.traceParent)
+			}
+
+			sc, err := w3cPropagator{}.Extract(opentracing.HTTPHeadersCarrier(header))
+			if test.expectedErr != nil {
+				assert.Equal(t, test.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, sc)
+		})
+	}
+}
+
+func TestW3CPropagator_Inject(t *testing.T) {
+	sc := jaegercli.NewSpanContext(jaegercli.TraceID{Low: 0x8448eb211c80319c}, 0xb7ad6b7169203331, 0, true, nil)
+
+	header := http.Header{}
+	err := w3cPropagator{}.Inject(sc, opentracing.HTTPHeadersCarrier(header))
+	require.NoError(t, err)
+
+	assert.Equal(t, "00-00000000000000008448eb211c80319c-b7ad6b7169203331-01", header.Get("traceparent"))
+
+	extracted, err := w3cPropagator{}.Extract(opentracing.HTTPHeadersCarrier(header))
+	require.NoError(t, err)
+	assert.Equal(t, sc, extracted)
+}
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// redactedValue replaces the value of the redacted headers in the spans.
+const redactedValue = "REDACTED"
+
+// SpanAttributes holds the customization of the span attributes.
+type SpanAttributes struct {
+	Static          map[string]string `description:"Attributes added to all the spans." json:"static,omitempty" toml:"static,omitempty" yaml:"static,omitempty" export:"true"`
+	CaptureHeaders  bool              `description:"Add the request headers to the entry point spans." json:"captureHeaders,omitempty" toml:"captureHeaders,omitempty" yaml:"captureHeaders,omitempty" export:"true"`
+	RedactedHeaders []string          `description:"Headers whose value is redacted from the spans." json:"redactedHeaders,omitempty" toml:"redactedHeaders,omitempty" yaml:"redactedHeaders,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *SpanAttributes) SetDefaults() {
+	s.RedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+}
+
+// setStaticAttributes adds the static attributes to the span.
+func (t *Tracing) setStaticAttributes(span opentracing.Span) {
+	if t == nil || t.spanAttributes == nil {
+		return
+	}
+
+	for key, value := range t.spanAttributes.Static {
+		span.SetTag(key, value)
+	}
+}
+
+// LogRequestHeaders adds the request headers to the span, as http.request.header.<name> attributes,
+// when the capture of the headers is enabled.
+func (t *Tracing) LogRequestHeaders(span opentracing.Span, r *http.Request) {
+	if span == nil || r == nil || t == nil || t.spanAttributes == nil || !t.spanAttributes.CaptureHeaders {
+		return
+	}
+
+	for name, values := range r.Header {
+		value := strings.Join(values, ",")
+		if _, ok := t.redactedHeaders[http.CanonicalHeaderKey(name)]; ok {
+			value = redactedValue
+		}
+
+		span.SetTag("http.request.header."+strings.ToLower(name), value)
+	}
+}
@@ -20,6 +20,12 @@ const (
 	tracingKey       contextKey       = iota
 )
 
+// W3C Trace Context headers.
+const (
+	traceParentHeader = "traceparent"
+	traceStateHeader  = "tracestate"
+)
+
 // WithTracing Adds Tracing into the context.
 func WithTracing(ctx context.Context, tracing *Tracing) context.Context {
 	return context.WithValue(ctx, tracingKey, tracing)
@@ -48,15 +54,25 @@ type Tracing struct {
 	ServiceName   string `description:"Set the name for this service" export:"true"`
 	SpanNameLimit int    `description:"Set the maximum character limit for Span names (default 0 = no limit)" export:"true"`
 
-	tracer opentracing.Tracer
-	closer io.Closer
+	tracer          opentracing.Tracer
+	closer          io.Closer
+	spanAttributes  *SpanAttributes
+	redactedHeaders map[string]struct{}
 }
 
 // NewTracing Creates a Tracing.
-func NewTracing(serviceName string, spanNameLimit int, tracingBackend Backend) (*Tracing, error) {
+func NewTracing(serviceName string, spanNameLimit int, tracingBackend Backend, spanAttributes *SpanAttributes) (*Tracing, error) {
 	tracing := &Tracing{
-		ServiceName:   serviceName,
-		SpanNameLimit: spanNameLimit,
+		ServiceName:     serviceName,
+		SpanNameLimit:   spanNameLimit,
+		spanAttributes:  spanAttributes,
+		redactedHeaders: make(map[string]struct{}),
+	}
+
+	if spanAttributes != nil {
+		for _, header := range spanAttributes.RedactedHeaders {
+			tracing.redactedHeaders[http.CanonicalHeaderKey(header)] = struct{}{}
+		}
 	}
 
 	var err error
@@ -76,7 +92,10 @@ func (t *Tracing) StartSpan(operationName string, opts ...opentracing.StartSpanO
 func (t *Tracing) StartSpanf(r *http.Request, spanKind ext.SpanKindEnum, opPrefix string, opParts []string, separator string, opts ...opentracing.StartSpanOption) (opentracing.Span, *http.Request, func()) {
 	operationName := generateOperationName(opPrefix, opParts, separator, t.SpanNameLimit)
 
-	return StartSpan(r, operationName, spanKind, opts...)
+	span, r, finish := StartSpan(r, operationName, spanKind, opts...)
+	t.setStaticAttributes(span)
+
+	return span, r, finish
 }
 
 // Inject delegates to opentracing.Tracer.
@@ -130,14 +149,24 @@ func GetSpan(r *http.Request) opentracing.Span {
 
 // InjectRequestHeaders used to inject OpenTracing headers into the request.
 func InjectRequestHeaders(r *http.Request) {
+	InjectHeaders(r, r.Header)
+}
+
+// InjectHeaders injects the OpenTracing headers of the span in the request context into the given headers.
+// When the W3C Trace Context traceparent header is injected, the tracestate header of the request is kept along.
+func InjectHeaders(r *http.Request, header http.Header) {
 	if span := GetSpan(r); span != nil {
 		err := opentracing.GlobalTracer().Inject(
 			span.Context(),
 			opentracing.HTTPHeaders,
-			opentracing.HTTPHeadersCarrier(r.Header))
+			opentracing.HTTPHeadersCarrier(header))
 		if err != nil {
 			log.FromContext(r.Context()).Error(err)
 		}
+
+		if header.Get(traceParentHeader) != "" && header.Get(traceStateHeader) == "" && r.Header.Get(traceStateHeader) != "" {
+			header.Set(traceStateHeader, r.Header.Get(traceStateHeader))
+		}
 	}
 }
 
@@ -152,6 +181,10 @@ func LogEventf(r *http.Request, format string, args ...interface{}) {
 func StartSpan(r *http.Request, operationName string, spanKind ext.SpanKindEnum, opts ...opentracing.StartSpanOption) (opentracing.Span, *http.Request, func()) {
 	span, ctx := opentracing.StartSpanFromContext(r.Context(), operationName, opts...)
 
+	if t, err := FromContext(r.Context()); err == nil {
+		t.setStaticAttributes(span)
+	}
+
 	switch spanKind {
 	case ext.SpanKindRPCClientEnum:
 		ext.SpanKindRPCClient.Set(span)
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"time"
+
+	"github.com/traefik/paerser/types"
+)
+
+// Accounting holds the configuration of the request/response size accounting.
+type Accounting struct {
+	FilePath      string         `description:"Accounting file path. Records are appended to this file as JSON lines." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
+	FlushInterval types.Duration `description:"Interval between two flushes of the accounting records." json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (a *Accounting) SetDefaults() {
+	a.FilePath = "accounting.log"
+	a.FlushInterval = types.Duration(time.Minute)
+}
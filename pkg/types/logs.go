@@ -21,9 +21,11 @@ const (
 
 // TraefikLog holds the configuration settings for the traefik logger.
 type TraefikLog struct {
-	Level    string `description:"Log level set to traefik logs." json:"level,omitempty" toml:"level,omitempty" yaml:"level,omitempty" export:"true"`
-	FilePath string `description:"Traefik log file path. Stdout is used when omitted or empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
-	Format   string `description:"Traefik log format: json | common" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Level    string   `description:"Log level set to traefik logs." json:"level,omitempty" toml:"level,omitempty" yaml:"level,omitempty" export:"true"`
+	FilePath string   `description:"Traefik log file path. Stdout is used when omitted or empty." json:"filePath,omitempty" toml:"filePath,omitempty" yaml:"filePath,omitempty"`
+	Format   string   `description:"Traefik log format: json | common" json:"format,omitempty" toml:"format,omitempty" yaml:"format,omitempty" export:"true"`
+	Syslog   *Syslog  `description:"Sends the Traefik logs to a syslog server." json:"syslog,omitempty" toml:"syslog,omitempty" yaml:"syslog,omitempty" export:"true"`
+	Fluentd  *Fluentd `description:"Sends the Traefik logs to Fluentd, with the forward protocol." json:"fluentd,omitempty" toml:"fluentd,omitempty" yaml:"fluentd,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -39,6 +41,9 @@ type AccessLog struct {
 	Filters       *AccessLogFilters `description:"Access log filters, used to keep only specific access logs." json:"filters,omitempty" toml:"filters,omitempty" yaml:"filters,omitempty" export:"true"`
 	Fields        *AccessLogFields  `description:"AccessLogFields." json:"fields,omitempty" toml:"fields,omitempty" yaml:"fields,omitempty" export:"true"`
 	BufferingSize int64             `description:"Number of access log lines to process in a buffered way." json:"bufferingSize,omitempty" toml:"bufferingSize,omitempty" yaml:"bufferingSize,omitempty" export:"true"`
+	Syslog        *Syslog           `description:"Sends the access logs to a syslog server." json:"syslog,omitempty" toml:"syslog,omitempty" yaml:"syslog,omitempty" export:"true"`
+	Fluentd       *Fluentd          `description:"Sends the access logs to Fluentd, with the forward protocol." json:"fluentd,omitempty" toml:"fluentd,omitempty" yaml:"fluentd,omitempty" export:"true"`
+	Connections   bool              `description:"Logs the TCP connections and the UDP sessions of the routers, when they are closed." json:"connections,omitempty" toml:"connections,omitempty" yaml:"connections,omitempty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -50,6 +55,34 @@ func (l *AccessLog) SetDefaults() {
 	l.Fields.SetDefaults()
 }
 
+// Syslog holds the configuration of the syslog (RFC 5424) output of the logs.
+type Syslog struct {
+	Address    string `description:"Syslog server address: udp://host:port, tcp://host:port, or unix:///path/to/socket." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Tag        string `description:"Application name of the messages." json:"tag,omitempty" toml:"tag,omitempty" yaml:"tag,omitempty" export:"true"`
+	Facility   string `description:"Facility of the messages: kern | user | mail | daemon | auth | syslog | lpr | news | uucp | cron | authpriv | ftp | local0 to local7" json:"facility,omitempty" toml:"facility,omitempty" yaml:"facility,omitempty" export:"true"`
+	BufferSize int    `description:"Number of log entries waiting to be sent, beyond which the new ones are dropped." json:"bufferSize,omitempty" toml:"bufferSize,omitempty" yaml:"bufferSize,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *Syslog) SetDefaults() {
+	s.Tag = "traefik"
+	s.Facility = "daemon"
+	s.BufferSize = 1024
+}
+
+// Fluentd holds the configuration of the Fluentd forward protocol output of the logs.
+type Fluentd struct {
+	Address    string `description:"Fluentd forward input address: tcp://host:port, or unix:///path/to/socket." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Tag        string `description:"Tag of the events." json:"tag,omitempty" toml:"tag,omitempty" yaml:"tag,omitempty" export:"true"`
+	BufferSize int    `description:"Number of log entries waiting to be sent, beyond which the new ones are dropped." json:"bufferSize,omitempty" toml:"bufferSize,omitempty" yaml:"bufferSize,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (f *Fluentd) SetDefaults() {
+	f.Tag = "traefik"
+	f.BufferSize = 1024
+}
+
 // AccessLogFilters holds filters configuration.
 type AccessLogFilters struct {
 	StatusCodes   []string       `description:"Keep access logs with status codes in the specified range." json:"statusCodes,omitempty" toml:"statusCodes,omitempty" yaml:"statusCodes,omitempty" export:"true"`
@@ -10,10 +10,38 @@ import (
 
 // Metrics provides options to expose and send Traefik metrics to different third party monitoring systems.
 type Metrics struct {
-	Prometheus *Prometheus `description:"Prometheus metrics exporter type." json:"prometheus,omitempty" toml:"prometheus,omitempty" yaml:"prometheus,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	Datadog    *Datadog    `description:"Datadog metrics exporter type." json:"datadog,omitempty" toml:"datadog,omitempty" yaml:"datadog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	StatsD     *Statsd     `description:"StatsD metrics exporter type." json:"statsD,omitempty" toml:"statsD,omitempty" yaml:"statsD,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
-	InfluxDB   *InfluxDB   `description:"InfluxDB metrics exporter type." json:"influxDB,omitempty" toml:"influxDB,omitempty" yaml:"influxDB,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Prometheus    *Prometheus        `description:"Prometheus metrics exporter type." json:"prometheus,omitempty" toml:"prometheus,omitempty" yaml:"prometheus,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Datadog       *Datadog           `description:"Datadog metrics exporter type." json:"datadog,omitempty" toml:"datadog,omitempty" yaml:"datadog,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	StatsD        *Statsd            `description:"StatsD metrics exporter type." json:"statsD,omitempty" toml:"statsD,omitempty" yaml:"statsD,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	InfluxDB      *InfluxDB          `description:"InfluxDB metrics exporter type." json:"influxDB,omitempty" toml:"influxDB,omitempty" yaml:"influxDB,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	InfluxDB2     *InfluxDB2         `description:"InfluxDB v2 metrics exporter type." json:"influxDB2,omitempty" toml:"influxDB2,omitempty" yaml:"influxDB2,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	OpenTelemetry *OpenTelemetry     `description:"OpenTelemetry metrics exporter type." json:"openTelemetry,omitempty" toml:"openTelemetry,omitempty" yaml:"openTelemetry,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Labels        *MetricsLabels     `description:"Rewrites the label values of the metrics, to control their cardinality." json:"labels,omitempty" toml:"labels,omitempty" yaml:"labels,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Operations    []MetricsOperation `description:"Classifies the requests into operations, set as the operation label of the router and service metrics, and as a tag of the tracing spans." json:"operations,omitempty" toml:"operations,omitempty" yaml:"operations,omitempty" export:"true"`
+}
+
+// MetricsOperation classifies the requests matching its methods and path into a named operation.
+// The first operation matching a request is the one used.
+type MetricsOperation struct {
+	Name      string   `description:"Name of the operation, where {method} is replaced by the lowercased request method." json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	Methods   []string `description:"Methods of the requests, all the methods if empty." json:"methods,omitempty" toml:"methods,omitempty" yaml:"methods,omitempty" export:"true"`
+	Path      string   `description:"Path template of the requests, where each {placeholder} matches a path segment, e.g. /users/{id}/orders/{id}." json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty" export:"true"`
+	PathRegex string   `description:"Regular expression matching the path of the requests, used if the path template is empty." json:"pathRegex,omitempty" toml:"pathRegex,omitempty" yaml:"pathRegex,omitempty" export:"true"`
+}
+
+// MetricsLabels rewrites the label values of the metrics before they are exported.
+type MetricsLabels struct {
+	Drop           []string `description:"Names of the labels whose values are dropped." json:"drop,omitempty" toml:"drop,omitempty" yaml:"drop,omitempty" export:"true"`
+	AggregateCodes bool     `description:"Replaces the status codes by their class (2xx, 3xx, 4xx, 5xx)." json:"aggregateCodes,omitempty" toml:"aggregateCodes,omitempty" yaml:"aggregateCodes,omitempty" export:"true"`
+	Hash           []string `description:"Names of the labels whose values are hashed, once they have more distinct values than the hash threshold." json:"hash,omitempty" toml:"hash,omitempty" yaml:"hash,omitempty" export:"true"`
+	HashThreshold  int      `description:"Number of distinct values kept as is, for each hashed label." json:"hashThreshold,omitempty" toml:"hashThreshold,omitempty" yaml:"hashThreshold,omitempty" export:"true"`
+	HashBuckets    int      `description:"Number of values the values over the hash threshold are spread over." json:"hashBuckets,omitempty" toml:"hashBuckets,omitempty" yaml:"hashBuckets,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (l *MetricsLabels) SetDefaults() {
+	l.HashThreshold = 100
+	l.HashBuckets = 16
 }
 
 // Prometheus can contain specific configuration used by the Prometheus Metrics exporter.
@@ -102,6 +130,48 @@ func (i *InfluxDB) SetDefaults() {
 	i.AddServicesLabels = true
 }
 
+// InfluxDB2 contains address, token and metrics pushing interval configuration.
+type InfluxDB2 struct {
+	Address              string         `description:"InfluxDB v2 address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Token                string         `description:"InfluxDB v2 access token." json:"token,omitempty" toml:"token,omitempty" yaml:"token,omitempty"`
+	PushInterval         types.Duration `description:"InfluxDB v2 push interval." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
+	Org                  string         `description:"InfluxDB v2 organization name." json:"org,omitempty" toml:"org,omitempty" yaml:"org,omitempty" export:"true"`
+	Bucket               string         `description:"InfluxDB v2 bucket name." json:"bucket,omitempty" toml:"bucket,omitempty" yaml:"bucket,omitempty" export:"true"`
+	AddEntryPointsLabels bool           `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
+	AddRoutersLabels     bool           `description:"Enable metrics on routers." json:"addRoutersLabels,omitempty" toml:"addRoutersLabels,omitempty" yaml:"addRoutersLabels,omitempty" export:"true"`
+	AddServicesLabels    bool           `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (i *InfluxDB2) SetDefaults() {
+	i.Address = "http://localhost:8086"
+	i.PushInterval = types.Duration(10 * time.Second)
+	i.AddEntryPointsLabels = true
+	i.AddServicesLabels = true
+}
+
+// OpenTelemetry contains the OTLP collector address, headers and metrics pushing interval configuration.
+type OpenTelemetry struct {
+	Address              string            `description:"OTLP/HTTP metrics endpoint of the OpenTelemetry collector." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Headers              map[string]string `description:"Headers sent with the metrics to the collector." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	ServiceName          string            `description:"Service name set in the resource attributes." json:"serviceName,omitempty" toml:"serviceName,omitempty" yaml:"serviceName,omitempty" export:"true"`
+	PushInterval         types.Duration    `description:"Period between two pushes of the metrics to the collector." json:"pushInterval,omitempty" toml:"pushInterval,omitempty" yaml:"pushInterval,omitempty" export:"true"`
+	ExplicitBoundaries   []float64         `description:"Boundaries for latency metrics." json:"explicitBoundaries,omitempty" toml:"explicitBoundaries,omitempty" yaml:"explicitBoundaries,omitempty" export:"true"`
+	AddEntryPointsLabels bool              `description:"Enable metrics on entry points." json:"addEntryPointsLabels,omitempty" toml:"addEntryPointsLabels,omitempty" yaml:"addEntryPointsLabels,omitempty" export:"true"`
+	AddRoutersLabels     bool              `description:"Enable metrics on routers." json:"addRoutersLabels,omitempty" toml:"addRoutersLabels,omitempty" yaml:"addRoutersLabels,omitempty" export:"true"`
+	AddServicesLabels    bool              `description:"Enable metrics on services." json:"addServicesLabels,omitempty" toml:"addServicesLabels,omitempty" yaml:"addServicesLabels,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *OpenTelemetry) SetDefaults() {
+	o.Address = "http://localhost:4318/v1/metrics"
+	o.ServiceName = "traefik"
+	o.PushInterval = types.Duration(10 * time.Second)
+	o.ExplicitBoundaries = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	o.AddEntryPointsLabels = true
+	o.AddServicesLabels = true
+}
+
 // Statistics provides options for monitoring request and response stats.
 type Statistics struct {
 	RecentErrors int `description:"Number of recent errors logged." json:"recentErrors,omitempty" toml:"recentErrors,omitempty" yaml:"recentErrors,omitempty" export:"true"`
@@ -0,0 +1,184 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// OutboundProxy holds the upstream proxies used for the outbound traffic of Traefik itself.
+// When no proxy is configured for a component, the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables are used.
+type OutboundProxy struct {
+	Default     *Proxy `description:"Upstream proxy used by default." json:"default,omitempty" toml:"default,omitempty" yaml:"default,omitempty" export:"true"`
+	ACME        *Proxy `description:"Upstream proxy used to reach the ACME servers." json:"acme,omitempty" toml:"acme,omitempty" yaml:"acme,omitempty" export:"true"`
+	Providers   *Proxy `description:"Upstream proxy used by the providers." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
+	Plugins     *Proxy `description:"Upstream proxy used to download the plugins." json:"plugins,omitempty" toml:"plugins,omitempty" yaml:"plugins,omitempty" export:"true"`
+	ForwardAuth *Proxy `description:"Upstream proxy used by the ForwardAuth middlewares." json:"forwardAuth,omitempty" toml:"forwardAuth,omitempty" yaml:"forwardAuth,omitempty" export:"true"`
+}
+
+// ACMEProxy returns the proxy used to reach the ACME servers, if any.
+func (o *OutboundProxy) ACMEProxy() *Proxy {
+	if o == nil {
+		return nil
+	}
+	return o.pick(o.ACME)
+}
+
+// ProvidersProxy returns the proxy used by the providers, if any.
+func (o *OutboundProxy) ProvidersProxy() *Proxy {
+	if o == nil {
+		return nil
+	}
+	return o.pick(o.Providers)
+}
+
+// PluginsProxy returns the proxy used to download the plugins, if any.
+func (o *OutboundProxy) PluginsProxy() *Proxy {
+	if o == nil {
+		return nil
+	}
+	return o.pick(o.Plugins)
+}
+
+// ForwardAuthProxy returns the proxy used by the ForwardAuth middlewares, if any.
+func (o *OutboundProxy) ForwardAuthProxy() *Proxy {
+	if o == nil {
+		return nil
+	}
+	return o.pick(o.ForwardAuth)
+}
+
+func (o *OutboundProxy) pick(override *Proxy) *Proxy {
+	if override != nil {
+		return override
+	}
+	return o.Default
+}
+
+// Validate checks the proxies URLs.
+func (o *OutboundProxy) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	proxies := map[string]*Proxy{
+		"default":     o.Default,
+		"acme":        o.ACME,
+		"providers":   o.Providers,
+		"plugins":     o.Plugins,
+		"forwardAuth": o.ForwardAuth,
+	}
+
+	for name, proxy := range proxies {
+		if proxy == nil {
+			continue
+		}
+
+		for _, rawURL := range []string{proxy.HTTPProxy, proxy.HTTPSProxy} {
+			if rawURL == "" {
+				continue
+			}
+
+			if _, err := parseProxyURL(rawURL); err != nil {
+				return fmt.Errorf("invalid %s outbound proxy: %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Proxy holds the configuration of an upstream proxy.
+type Proxy struct {
+	HTTPProxy  string            `description:"URL of the proxy used for the HTTP requests." json:"httpProxy,omitempty" toml:"httpProxy,omitempty" yaml:"httpProxy,omitempty"`
+	HTTPSProxy string            `description:"URL of the proxy used for the HTTPS requests." json:"httpsProxy,omitempty" toml:"httpsProxy,omitempty" yaml:"httpsProxy,omitempty"`
+	NoProxy    string            `description:"Comma-separated list of the hosts, domains, IP addresses and CIDRs reached without proxy." json:"noProxy,omitempty" toml:"noProxy,omitempty" yaml:"noProxy,omitempty" export:"true"`
+	Headers    map[string]string `description:"Headers sent to the proxy, such as Proxy-Authorization." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// ProxyFunc returns the function selecting the proxy of a request, following the NO_PROXY conventions.
+func (p *Proxy) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  p.HTTPProxy,
+		HTTPSProxy: p.HTTPSProxy,
+		NoProxy:    p.NoProxy,
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+// WrapTransport configures the transport to use the proxy,
+// and returns a round tripper sending the proxy headers along with the proxied requests.
+// A nil Proxy leaves the transport unchanged.
+func (p *Proxy) WrapTransport(tr *http.Transport) http.RoundTripper {
+	if p == nil {
+		return tr
+	}
+
+	tr.Proxy = p.ProxyFunc()
+
+	if len(p.Headers) == 0 {
+		return tr
+	}
+
+	headers := make(http.Header)
+	for name, value := range p.Headers {
+		headers.Set(name, value)
+	}
+
+	// The headers are sent with the CONNECT requests tunneling the HTTPS requests,
+	// and added to the plain HTTP requests which are forwarded by the proxy.
+	tr.ProxyConnectHeader = headers
+
+	return &proxyHeadersRoundTripper{
+		next:    tr,
+		proxy:   tr.Proxy,
+		headers: headers,
+	}
+}
+
+type proxyHeadersRoundTripper struct {
+	next    http.RoundTripper
+	proxy   func(*http.Request) (*url.URL, error)
+	headers http.Header
+}
+
+func (r *proxyHeadersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "http" {
+		return r.next.RoundTrip(req)
+	}
+
+	proxyURL, err := r.proxy(req)
+	if err != nil || proxyURL == nil {
+		return r.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	for name, values := range r.headers {
+		req.Header[name] = values
+	}
+
+	return r.next.RoundTrip(req)
+}
+
+// parseProxyURL parses a proxy URL, whose scheme is optional like in the HTTP_PROXY environment variable.
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy address %q: %w", rawURL, err)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy address %q: missing host", rawURL)
+	}
+
+	return proxyURL, nil
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// SourceRangeSources holds the files and the URLs the IPWhiteList middlewares are allowed to read their source ranges from.
+// As the dynamic configuration can come from untrusted sources (e.g. container labels), none of them is allowed by default.
+type SourceRangeSources struct {
+	Directories []string `description:"Directories of the files the IPWhiteList middlewares can read their source ranges from." json:"directories,omitempty" toml:"directories,omitempty" yaml:"directories,omitempty" export:"true"`
+	URLs        []string `description:"URL prefixes the IPWhiteList middlewares can fetch their source ranges from." json:"urls,omitempty" toml:"urls,omitempty" yaml:"urls,omitempty" export:"true"`
+}
+
+// AllowsFile reports whether the file, given by its absolute path, is in one of the allowed directories.
+func (s *SourceRangeSources) AllowsFile(path string) bool {
+	if s == nil {
+		return false
+	}
+
+	path = filepath.Clean(path)
+
+	for _, dir := range s.Directories {
+		if dir == "" {
+			continue
+		}
+
+		rel, err := filepath.Rel(filepath.Clean(dir), path)
+		if err != nil {
+			continue
+		}
+
+		if rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsURL reports whether the URL has the scheme and the host of one of the allowed URLs, and starts with its path.
+func (s *SourceRangeSources) AllowsURL(rawURL string) bool {
+	if s == nil {
+		return false
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User != nil {
+		return false
+	}
+
+	for _, allowed := range s.URLs {
+		prefix, err := url.Parse(allowed)
+		if err != nil || prefix.Host == "" {
+			continue
+		}
+
+		if strings.EqualFold(u.Scheme, prefix.Scheme) && strings.EqualFold(u.Host, prefix.Host) && strings.HasPrefix(u.EscapedPath(), prefix.EscapedPath()) {
+			return true
+		}
+	}
+
+	return false
+}
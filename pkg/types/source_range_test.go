@@ -0,0 +1,36 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceRangeSources_AllowsFile(t *testing.T) {
+	sources := &SourceRangeSources{Directories: []string{"/etc/traefik/whitelists/"}}
+
+	assert.True(t, sources.AllowsFile("/etc/traefik/whitelists/offices.txt"))
+	assert.True(t, sources.AllowsFile("/etc/traefik/whitelists/partners/cdn.txt"))
+	assert.False(t, sources.AllowsFile("/etc/traefik/whitelists"))
+	assert.False(t, sources.AllowsFile("/etc/traefik/whitelists/../traefik.toml"))
+	assert.False(t, sources.AllowsFile("/etc/traefik/whitelists-other/offices.txt"))
+	assert.False(t, sources.AllowsFile("/etc/passwd"))
+
+	var noSources *SourceRangeSources
+	assert.False(t, noSources.AllowsFile("/etc/traefik/whitelists/offices.txt"))
+}
+
+func TestSourceRangeSources_AllowsURL(t *testing.T) {
+	sources := &SourceRangeSources{URLs: []string{"https://example.com/whitelists/"}}
+
+	assert.True(t, sources.AllowsURL("https://example.com/whitelists/offices.txt"))
+	assert.True(t, sources.AllowsURL("https://EXAMPLE.com/whitelists/offices.txt"))
+	assert.False(t, sources.AllowsURL("http://example.com/whitelists/offices.txt"))
+	assert.False(t, sources.AllowsURL("https://example.com/admin"))
+	assert.False(t, sources.AllowsURL("https://example.com.evil.com/whitelists/offices.txt"))
+	assert.False(t, sources.AllowsURL("https://user@example.com/whitelists/offices.txt"))
+	assert.False(t, sources.AllowsURL("http://169.254.169.254/latest/meta-data/"))
+
+	var noSources *SourceRangeSources
+	assert.False(t, noSources.AllowsURL("https://example.com/whitelists/offices.txt"))
+}
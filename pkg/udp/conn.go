@@ -1,10 +1,13 @@
 package udp
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -29,25 +32,37 @@ type Listener struct {
 	// timeout defines how long to wait on an idle session,
 	// before releasing its related resources.
 	timeout time.Duration
+
+	// sourceIPAffinity identifies the sessions by the client IP only, ignoring the client port,
+	// for the protocols changing the client port during a session (e.g. QUIC, DTLS).
+	sourceIPAffinity bool
 }
 
 // Listen creates a new listener.
-func Listen(network string, laddr *net.UDPAddr, timeout time.Duration) (*Listener, error) {
+func Listen(listenConfig net.ListenConfig, network, address string, timeout time.Duration, sourceIPAffinity bool) (*Listener, error) {
 	if timeout <= 0 {
 		return nil, errors.New("timeout should be greater than zero")
 	}
 
-	conn, err := net.ListenUDP(network, laddr)
+	packetConn, err := listenConfig.ListenPacket(context.Background(), network, address)
 	if err != nil {
 		return nil, err
 	}
 
+	conn, ok := packetConn.(*net.UDPConn)
+	if !ok {
+		_ = packetConn.Close()
+		return nil, fmt.Errorf("unexpected connection type %T for the network %s", packetConn, network)
+	}
+
 	l := &Listener{
 		pConn:     conn,
 		acceptCh:  make(chan *Conn),
 		conns:     make(map[string]*Conn),
 		accepting: true,
 		timeout:   timeout,
+
+		sourceIPAffinity: sourceIPAffinity,
 	}
 
 	go l.readLoop()
@@ -158,38 +173,63 @@ func (l *Listener) getConn(raddr net.Addr) (*Conn, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	conn, ok := l.conns[raddr.String()]
+	key := l.sessionKey(raddr)
+
+	conn, ok := l.conns[key]
 	if ok {
+		// With the source IP affinity, the replies are sent to the latest client port.
+		conn.setRemoteAddr(raddr)
 		return conn, nil
 	}
 
 	if !l.accepting {
 		return nil, errClosedListener
 	}
-	conn = l.newConn(raddr)
-	l.conns[raddr.String()] = conn
+	conn = l.newConn(key, raddr)
+	l.conns[key] = conn
 	l.acceptCh <- conn
 	go conn.readLoop()
 
 	return conn, nil
 }
 
-func (l *Listener) newConn(rAddr net.Addr) *Conn {
+// sessionKey returns the key identifying the session of the remote address.
+func (l *Listener) sessionKey(raddr net.Addr) string {
+	if l.sourceIPAffinity {
+		if udpAddr, ok := raddr.(*net.UDPAddr); ok {
+			return udpAddr.IP.String()
+		}
+	}
+
+	return raddr.String()
+}
+
+func (l *Listener) newConn(key string, rAddr net.Addr) *Conn {
 	return &Conn{
 		listener:  l,
+		key:       key,
 		rAddr:     rAddr,
 		receiveCh: make(chan []byte),
 		readCh:    make(chan []byte),
 		sizeCh:    make(chan int),
 		doneCh:    make(chan struct{}),
+		timeoutCh: make(chan time.Duration, 1),
 		timeout:   l.timeout,
 	}
 }
 
 // Conn represents an on-going session with a client, over UDP packets.
 type Conn struct {
+	// bytesIn and bytesOut are the number of bytes received from and sent to the client, accessed atomically.
+	bytesIn  int64
+	bytesOut int64
+
 	listener *Listener
-	rAddr    net.Addr
+	key      string // the key of the session in the listener
+
+	muAddr     sync.RWMutex
+	rAddr      net.Addr
+	serverAddr string
 
 	receiveCh chan []byte // to receive the data from the listener's readLoop
 	readCh    chan []byte // to receive the buffer into which we should Read
@@ -199,9 +239,10 @@ type Conn struct {
 	muActivity   sync.RWMutex
 	lastActivity time.Time // the last time the session saw either read or write activity
 
-	timeout  time.Duration // for timeouts
-	doneOnce sync.Once
-	doneCh   chan struct{}
+	timeout   time.Duration      // for timeouts, guarded by muActivity
+	timeoutCh chan time.Duration // to notify the readLoop of the timeout changes
+	doneOnce  sync.Once
+	doneCh    chan struct{}
 }
 
 // readLoop waits for data to come from the listener's readLoop.
@@ -209,19 +250,26 @@ type Conn struct {
 // that is to say it waits on readCh to receive the slice of bytes that the Read operation wants to read onto.
 // The Read operation receives the signal that the data has been written to the slice of bytes through the sizeCh.
 func (c *Conn) readLoop() {
-	ticker := time.NewTicker(c.timeout / 10)
+	ticker := time.NewTicker(c.getTimeout() / 10)
 	defer ticker.Stop()
 
+	expired := func() bool {
+		c.muActivity.RLock()
+		deadline := c.lastActivity.Add(c.timeout)
+		c.muActivity.RUnlock()
+		return time.Now().After(deadline)
+	}
+
 	for {
 		if len(c.msgs) == 0 {
 			select {
 			case msg := <-c.receiveCh:
 				c.msgs = append(c.msgs, msg)
+			case timeout := <-c.timeoutCh:
+				ticker.Reset(timeout / 10)
+				continue
 			case <-ticker.C:
-				c.muActivity.RLock()
-				deadline := c.lastActivity.Add(c.timeout)
-				c.muActivity.RUnlock()
-				if time.Now().After(deadline) {
+				if expired() {
 					c.Close()
 					return
 				}
@@ -237,11 +285,10 @@ func (c *Conn) readLoop() {
 			c.sizeCh <- n
 		case msg := <-c.receiveCh:
 			c.msgs = append(c.msgs, msg)
+		case timeout := <-c.timeoutCh:
+			ticker.Reset(timeout / 10)
 		case <-ticker.C:
-			c.muActivity.RLock()
-			deadline := c.lastActivity.Add(c.timeout)
-			c.muActivity.RUnlock()
-			if time.Now().After(deadline) {
+			if expired() {
 				c.Close()
 				return
 			}
@@ -249,11 +296,81 @@ func (c *Conn) readLoop() {
 	}
 }
 
+// SetTimeout sets how long to wait on the idle session before releasing its related resources.
+// It overrides the timeout of the listener.
+func (c *Conn) SetTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	c.muActivity.Lock()
+	c.timeout = timeout
+	c.muActivity.Unlock()
+
+	// Replaces the pending change, if any, so that the readLoop follows the latest timeout.
+	select {
+	case <-c.timeoutCh:
+	default:
+	}
+
+	select {
+	case c.timeoutCh <- timeout:
+	default:
+	}
+}
+
+func (c *Conn) getTimeout() time.Duration {
+	c.muActivity.RLock()
+	defer c.muActivity.RUnlock()
+
+	return c.timeout
+}
+
+// RemoteAddr returns the address of the client,
+// which is the latest address seen for the session with the source IP affinity.
+func (c *Conn) RemoteAddr() net.Addr {
+	c.muAddr.RLock()
+	defer c.muAddr.RUnlock()
+
+	return c.rAddr
+}
+
+func (c *Conn) setRemoteAddr(rAddr net.Addr) {
+	c.muAddr.Lock()
+	c.rAddr = rAddr
+	c.muAddr.Unlock()
+}
+
+// ServerAddr returns the address of the server the session is forwarded to, if any.
+func (c *Conn) ServerAddr() string {
+	c.muAddr.RLock()
+	defer c.muAddr.RUnlock()
+
+	return c.serverAddr
+}
+
+func (c *Conn) setServerAddr(addr string) {
+	c.muAddr.Lock()
+	c.serverAddr = addr
+	c.muAddr.Unlock()
+}
+
+// BytesIn returns the number of bytes received from the client during the session.
+func (c *Conn) BytesIn() int64 {
+	return atomic.LoadInt64(&c.bytesIn)
+}
+
+// BytesOut returns the number of bytes sent to the client during the session.
+func (c *Conn) BytesOut() int64 {
+	return atomic.LoadInt64(&c.bytesOut)
+}
+
 // Read implements io.Reader for a Conn.
 func (c *Conn) Read(p []byte) (int, error) {
 	select {
 	case c.readCh <- p:
 		n := <-c.sizeCh
+		atomic.AddInt64(&c.bytesIn, int64(n))
 		c.muActivity.Lock()
 		c.lastActivity = time.Now()
 		c.muActivity.Unlock()
@@ -273,7 +390,11 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	c.muActivity.Lock()
 	c.lastActivity = time.Now()
 	c.muActivity.Unlock()
-	return l.pConn.WriteTo(p, c.rAddr)
+
+	n, err = l.pConn.WriteTo(p, c.RemoteAddr())
+	atomic.AddInt64(&c.bytesOut, int64(n))
+
+	return n, err
 }
 
 func (c *Conn) close() {
@@ -288,6 +409,8 @@ func (c *Conn) Close() error {
 
 	c.listener.mu.Lock()
 	defer c.listener.mu.Unlock()
-	delete(c.listener.conns, c.rAddr.String())
+	if c.listener.conns[c.key] == c {
+		delete(c.listener.conns, c.key)
+	}
 	return nil
 }
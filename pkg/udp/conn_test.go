@@ -15,7 +15,7 @@ func TestConsecutiveWrites(t *testing.T) {
 	addr, err := net.ResolveUDPAddr("udp", ":0")
 	require.NoError(t, err)
 
-	ln, err := Listen("udp", addr, 3*time.Second)
+	ln, err := Listen(net.ListenConfig{}, "udp", addr.String(), 3*time.Second, false)
 	require.NoError(t, err)
 	defer func() {
 		err := ln.Close()
@@ -77,7 +77,7 @@ func TestListenNotBlocking(t *testing.T) {
 
 	require.NoError(t, err)
 
-	ln, err := Listen("udp", addr, 3*time.Second)
+	ln, err := Listen(net.ListenConfig{}, "udp", addr.String(), 3*time.Second, false)
 	require.NoError(t, err)
 	defer func() {
 		err := ln.Close()
@@ -166,7 +166,7 @@ func TestListenWithZeroTimeout(t *testing.T) {
 	addr, err := net.ResolveUDPAddr("udp", ":0")
 	require.NoError(t, err)
 
-	_, err = Listen("udp", addr, 0)
+	_, err = Listen(net.ListenConfig{}, "udp", addr.String(), 0, false)
 	assert.Error(t, err)
 }
 
@@ -184,7 +184,7 @@ func testTimeout(t *testing.T, withRead bool) {
 	addr, err := net.ResolveUDPAddr("udp", ":0")
 	require.NoError(t, err)
 
-	ln, err := Listen("udp", addr, 3*time.Second)
+	ln, err := Listen(net.ListenConfig{}, "udp", addr.String(), 3*time.Second, false)
 	require.NoError(t, err)
 	defer func() {
 		err := ln.Close()
@@ -228,7 +228,7 @@ func TestShutdown(t *testing.T) {
 	addr, err := net.ResolveUDPAddr("udp", ":0")
 	require.NoError(t, err)
 
-	l, err := Listen("udp", addr, 3*time.Second)
+	l, err := Listen(net.ListenConfig{}, "udp", addr.String(), 3*time.Second, false)
 	require.NoError(t, err)
 
 	go func() {
@@ -317,6 +317,138 @@ func TestShutdown(t *testing.T) {
 	}
 }
 
+func TestSourceIPAffinity(t *testing.T) {
+	addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ln, err := Listen(net.ListenConfig{}, "udp", addr.String(), 3*time.Second, true)
+	require.NoError(t, err)
+	defer func() {
+		err := ln.Close()
+		require.NoError(t, err)
+	}()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				b := make([]byte, 1024)
+				for {
+					n, err := conn.Read(b)
+					if err != nil {
+						return
+					}
+
+					_, err = conn.Write(b[:n])
+					require.NoError(t, err)
+				}
+			}()
+		}
+	}()
+
+	// The clients use different ports from the same IP.
+	for i := 0; i < 3; i++ {
+		client, err := net.Dial("udp", ln.Addr().String())
+		require.NoError(t, err)
+
+		requireEcho(t, "TEST", client, time.Second)
+
+		err = client.Close()
+		require.NoError(t, err)
+	}
+
+	ln.mu.RLock()
+	assert.Len(t, ln.conns, 1)
+	ln.mu.RUnlock()
+}
+
+func TestConn_SetTimeout(t *testing.T) {
+	addr, err := net.ResolveUDPAddr("udp", ":0")
+	require.NoError(t, err)
+
+	ln, err := Listen(net.ListenConfig{}, "udp", addr.String(), time.Minute, false)
+	require.NoError(t, err)
+	defer func() {
+		err := ln.Close()
+		require.NoError(t, err)
+	}()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			conn.SetTimeout(500 * time.Millisecond)
+		}
+	}()
+
+	client, err := net.Dial("udp", ln.Addr().String())
+	require.NoError(t, err)
+
+	_, err = client.Write([]byte("TEST"))
+	require.NoError(t, err)
+
+	time.Sleep(10 * time.Millisecond)
+
+	ln.mu.RLock()
+	assert.Len(t, ln.conns, 1)
+	ln.mu.RUnlock()
+
+	time.Sleep(time.Second)
+
+	ln.mu.RLock()
+	assert.Len(t, ln.conns, 0)
+	ln.mu.RUnlock()
+}
+
+func TestConn_bytes(t *testing.T) {
+	addr, err := net.ResolveUDPAddr("udp", ":0")
+	require.NoError(t, err)
+
+	ln, err := Listen(net.ListenConfig{}, "udp", addr.String(), time.Minute, false)
+	require.NoError(t, err)
+	defer func() {
+		err := ln.Close()
+		require.NoError(t, err)
+	}()
+
+	connCh := make(chan *Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		b := make([]byte, 1024)
+		n, err := conn.Read(b)
+		require.NoError(t, err)
+		_, err = conn.Write(append(b[:n], b[:n]...))
+		require.NoError(t, err)
+
+		connCh <- conn
+	}()
+
+	client, err := net.Dial("udp", ln.Addr().String())
+	require.NoError(t, err)
+
+	_, err = client.Write([]byte("TEST"))
+	require.NoError(t, err)
+
+	select {
+	case conn := <-connCh:
+		assert.Equal(t, int64(4), conn.BytesIn())
+		assert.Equal(t, int64(8), conn.BytesOut())
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the session")
+	}
+}
+
 // requireEcho tests that the conn session is live and functional,
 // by writing data through it, and expecting the same data as a response when reading on it.
 // It fatals if the read blocks longer than timeout,
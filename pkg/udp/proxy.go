@@ -1,31 +1,50 @@
 package udp
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"net"
 
+	"github.com/pires/go-proxyproto"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 	"github.com/traefik/traefik/v2/pkg/log"
 )
 
 // Proxy is a reverse-proxy implementation of the Handler interface.
 type Proxy struct {
 	// TODO: maybe optimize by pre-resolving it at proxy creation time
-	target string
+	target        string
+	proxyProtocol *dynamic.ProxyProtocol
+	transparent   bool
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string) (*Proxy, error) {
-	return &Proxy{target: address}, nil
+func NewProxy(address string, proxyProtocol *dynamic.ProxyProtocol, transparent bool) (*Proxy, error) {
+	// The version 1 of the PROXY protocol only describes TCP connections.
+	if proxyProtocol != nil && proxyProtocol.Version != 2 {
+		return nil, fmt.Errorf("unsupported proxyProtocol version for UDP: %d", proxyProtocol.Version)
+	}
+
+	if transparent && !transparentSupported {
+		return nil, errors.New("transparent mode is not supported on this platform")
+	}
+
+	return &Proxy{
+		target:        address,
+		proxyProtocol: proxyProtocol,
+		transparent:   transparent,
+	}, nil
 }
 
 // ServeUDP implements the Handler interface.
 func (p *Proxy) ServeUDP(conn *Conn) {
-	log.Debugf("Handling connection from %s", conn.rAddr)
+	log.Debugf("Handling connection from %s", conn.RemoteAddr())
 
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
-	connBackend, err := net.Dial("udp", p.target)
+	connBackend, err := p.dialBackend(conn)
 	if err != nil {
 		log.Errorf("Error while connecting to backend: %v", err)
 		return
@@ -34,9 +53,22 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
+	conn.setServerAddr(connBackend.RemoteAddr().String())
+
+	var dst io.WriteCloser = connBackend
+	if p.proxyProtocol != nil {
+		header, err := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.listener.Addr()).Format()
+		if err != nil {
+			log.Errorf("Error while building proxy protocol header: %v", err)
+			return
+		}
+
+		dst = &headerWriter{WriteCloser: connBackend, header: header}
+	}
+
 	errChan := make(chan error)
 	go p.connCopy(conn, connBackend, errChan)
-	go p.connCopy(connBackend, conn, errChan)
+	go p.connCopy(dst, conn, errChan)
 
 	err = <-errChan
 	if err != nil {
@@ -46,6 +78,20 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	<-errChan
 }
 
+// dialBackend connects to the target, from the client address in transparent mode.
+func (p *Proxy) dialBackend(conn *Conn) (net.Conn, error) {
+	if !p.transparent {
+		return net.Dial("udp", p.target)
+	}
+
+	dialer := net.Dialer{
+		LocalAddr: conn.RemoteAddr(),
+		Control:   transparentControl,
+	}
+
+	return dialer.Dial("udp", p.target)
+}
+
 func (p Proxy) connCopy(dst io.WriteCloser, src io.Reader, errCh chan error) {
 	_, err := io.Copy(dst, src)
 	errCh <- err
@@ -54,3 +100,25 @@ func (p Proxy) connCopy(dst io.WriteCloser, src io.Reader, errCh chan error) {
 		log.WithoutContext().Debugf("Error while terminating connection: %v", err)
 	}
 }
+
+// headerWriter prepends the PROXY protocol header to each datagram,
+// as the datagrams may be lost or reordered.
+type headerWriter struct {
+	io.WriteCloser
+	header []byte
+}
+
+func (h *headerWriter) Write(p []byte) (int, error) {
+	datagram := make([]byte, 0, len(h.header)+len(p))
+	datagram = append(datagram, h.header...)
+	datagram = append(datagram, p...)
+
+	n, err := h.WriteCloser.Write(datagram)
+
+	n -= len(h.header)
+	if n < 0 {
+		n = 0
+	}
+
+	return n, err
+}
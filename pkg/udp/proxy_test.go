@@ -1,12 +1,17 @@
 package udp
 
 import (
+	"bufio"
+	"bytes"
+	"io"
 	"net"
 	"testing"
 	"time"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v2/pkg/config/dynamic"
 )
 
 func TestUDPProxy(t *testing.T) {
@@ -21,7 +26,7 @@ func TestUDPProxy(t *testing.T) {
 		}
 	}))
 
-	proxy, err := NewProxy(backendAddr)
+	proxy, err := NewProxy(backendAddr, nil, false)
 	require.NoError(t, err)
 
 	proxyAddr := ":8080"
@@ -40,13 +45,60 @@ func TestUDPProxy(t *testing.T) {
 	assert.Equal(t, "DATAWRITE", string(b[:n]))
 }
 
+func TestUDPProxy_proxyProtocol(t *testing.T) {
+	backendAddr := "127.0.0.1:8083"
+	go newServer(t, backendAddr, HandlerFunc(func(conn *Conn) {
+		for {
+			b := make([]byte, 1024*1024)
+			n, err := conn.Read(b)
+			require.NoError(t, err)
+
+			// Each datagram starts with the PROXY protocol header.
+			reader := bufio.NewReader(bytes.NewReader(b[:n]))
+			header, err := proxyproto.Read(reader)
+			require.NoError(t, err)
+
+			payload, err := io.ReadAll(reader)
+			require.NoError(t, err)
+
+			_, err = conn.Write(append([]byte(header.SourceAddr.String()+" "), payload...))
+			require.NoError(t, err)
+		}
+	}))
+
+	proxy, err := NewProxy(backendAddr, &dynamic.ProxyProtocol{Version: 2}, false)
+	require.NoError(t, err)
+
+	proxyAddr := "127.0.0.1:8082"
+	go newServer(t, proxyAddr, proxy)
+
+	time.Sleep(time.Second)
+	udpConn, err := net.Dial("udp", proxyAddr)
+	require.NoError(t, err)
+
+	for _, data := range []string{"DATAWRITE", "DATAWRITE2"} {
+		_, err = udpConn.Write([]byte(data))
+		require.NoError(t, err)
+
+		b := make([]byte, 1024*1024)
+		n, err := udpConn.Read(b)
+		require.NoError(t, err)
+		assert.Equal(t, udpConn.LocalAddr().String()+" "+data, string(b[:n]))
+	}
+}
+
+func TestNewProxy_proxyProtocolVersion1(t *testing.T) {
+	_, err := NewProxy("127.0.0.1:8084", &dynamic.ProxyProtocol{Version: 1}, false)
+	require.Error(t, err)
+}
+
 func newServer(t *testing.T, addr string, handler Handler) {
 	t.Helper()
 
 	addrL, err := net.ResolveUDPAddr("udp", addr)
 	require.NoError(t, err)
 
-	listener, err := Listen("udp", addrL, 3*time.Second)
+	listener, err := Listen(net.ListenConfig{}, "udp", addrL.String(), 3*time.Second, false)
 	require.NoError(t, err)
 
 	for {
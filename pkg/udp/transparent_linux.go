@@ -0,0 +1,35 @@
+package udp
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// ipv6Transparent is the IPV6_TRANSPARENT socket option, which is not defined by the syscall package.
+const ipv6Transparent = 0x4b
+
+const transparentSupported = true
+
+// transparentControl allows the socket to be bound to the non-local address of the client.
+// It requires the CAP_NET_ADMIN capability,
+// and a routing of the responses of the backends through the host running Traefik.
+func transparentControl(network, _ string, c syscall.RawConn) error {
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		if network == "udp6" {
+			sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_IPV6, ipv6Transparent, 1)
+			return
+		}
+
+		sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_IP, syscall.IP_TRANSPARENT, 1)
+	})
+	if err != nil {
+		return err
+	}
+
+	if sockErr != nil {
+		return fmt.Errorf("unable to set the transparent socket option: %w", sockErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+// +build !linux
+
+package udp
+
+import (
+	"errors"
+	"syscall"
+)
+
+const transparentSupported = false
+
+func transparentControl(_, _ string, _ syscall.RawConn) error {
+	return errors.New("transparent mode is not supported on this platform")
+}